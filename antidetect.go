@@ -33,6 +33,8 @@
 package antidetect
 
 import (
+	"context"
+
 	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
 )
 
@@ -57,6 +59,59 @@ type BitBrowserClient = bitbrowser.Client
 // BitBrowserOption is a function that configures a BitBrowser client.
 type BitBrowserOption = bitbrowser.ClientOption
 
+// Config holds client settings loaded by LoadConfig and ReadConfig.
+type Config = bitbrowser.Config
+
+// FleetClient spreads work across several BitBrowser instances.
+type FleetClient = bitbrowser.FleetClient
+
+// FleetOption configures a FleetClient.
+type FleetOption = bitbrowser.FleetOption
+
+// FleetStore records which host owns each profile.
+type FleetStore = bitbrowser.FleetStore
+
+// MemoryFleetStore is the default in-process FleetStore.
+type MemoryFleetStore = bitbrowser.MemoryFleetStore
+
+// HealthProbes serves /healthz and /readyz for a service built on a Client.
+type HealthProbes = bitbrowser.HealthProbes
+
+// FleetHostOption configures a single fleet host.
+type FleetHostOption = bitbrowser.FleetHostOption
+
+// HostLoad is the load of a fleet host as seen by the scheduler.
+type HostLoad = bitbrowser.HostLoad
+
+// HostMetrics is the CPU and memory usage of a host, in percent.
+type HostMetrics = bitbrowser.HostMetrics
+
+// MetricsAgent reports the resource usage of a fleet host.
+type MetricsAgent = bitbrowser.MetricsAgent
+
+// MetricsAgentFunc adapts a function to the MetricsAgent interface.
+type MetricsAgentFunc = bitbrowser.MetricsAgentFunc
+
+// AgentClient talks to an antidetect-agent on a BitBrowser host. It is a
+// ProcessKiller, a MetricsAgent, a PortForwarder, an AppController and a
+// ClipboardWriter.
+type AgentClient = bitbrowser.AgentClient
+
+// AgentOption configures an AgentClient.
+type AgentOption = bitbrowser.AgentOption
+
+// DiskUsage is the disk usage of a file system on an agent host, in bytes.
+type DiskUsage = bitbrowser.DiskUsage
+
+// FleetOpenResult is an OpenResult together with the host that runs the browser.
+type FleetOpenResult = bitbrowser.FleetOpenResult
+
+// FleetProfile is a profile together with the host that stores it.
+type FleetProfile = bitbrowser.FleetProfile
+
+// FleetPort is a debugging port together with the host it belongs to.
+type FleetPort = bitbrowser.FleetPort
+
 // WithHTTPClient sets a custom HTTP client for the BitBrowser client.
 // Use this to configure custom transport settings.
 //
@@ -72,6 +127,42 @@ var WithHTTPClient = bitbrowser.WithHTTPClient
 //	client, err := antidetect.NewBitBrowser(apiURL, antidetect.WithAPIKey("56d2b7c905"))
 var WithAPIKey = bitbrowser.WithAPIKey
 
+// WithHeader adds a header to every API request.
+// Use this for reverse proxies that require bearer tokens or custom headers.
+//
+// Example:
+//
+//	client, err := antidetect.NewBitBrowser(apiURL, antidetect.WithHeader("Authorization", "Bearer "+token))
+var WithHeader = bitbrowser.WithHeader
+
+// WithUserAgent sets the User-Agent header sent with every API request.
+var WithUserAgent = bitbrowser.WithUserAgent
+
+// WithRequestEditor registers a function that can modify every API request
+// right before it is sent.
+var WithRequestEditor = bitbrowser.WithRequestEditor
+
+// WithDialContext sets a custom dialer for API requests, e.g. to reach the
+// BitBrowser API through a sidecar or tunnel.
+var WithDialContext = bitbrowser.WithDialContext
+
+// WithUnixSocket routes all API requests through a Unix socket.
+//
+// Example:
+//
+//	client, err := antidetect.NewBitBrowser("http://localhost", antidetect.WithUnixSocket("/run/bitbrowser.sock"))
+var WithUnixSocket = bitbrowser.WithUnixSocket
+
+// WithTLSConfig sets the TLS configuration for API requests and browser
+// debug endpoints, e.g. when BitBrowser is fronted by a TLS proxy.
+var WithTLSConfig = bitbrowser.WithTLSConfig
+
+// WithClientCertificate loads a PEM client certificate and key for mTLS.
+var WithClientCertificate = bitbrowser.WithClientCertificate
+
+// WithCAFile adds the PEM certificates in a file to the trusted roots.
+var WithCAFile = bitbrowser.WithCAFile
+
 // WithLogger sets the logger for the client.
 // If nil, logging is disabled.
 var WithLogger = bitbrowser.WithLogger
@@ -85,6 +176,196 @@ var WithRetry = bitbrowser.WithRetry
 // If nil, no retries will be performed (MaxAttempts=1).
 var WithRetryConfig = bitbrowser.WithRetryConfig
 
+// WithRetryBudget caps the total number of retries across all calls (and
+// all clients) sharing the budget.
+//
+// Example:
+//
+//	budget := antidetect.NewRetryBudget(60, time.Minute)
+//	client, err := antidetect.NewBitBrowser(apiURL, antidetect.WithRetry(3), antidetect.WithRetryBudget(budget))
+var WithRetryBudget = bitbrowser.WithRetryBudget
+
+// WithEndpointRetryPolicy sets the retry configuration per endpoint path.
+//
+// Example:
+//
+//	retry := &antidetect.RetryConfig{MaxAttempts: 3, BaseDelay: time.Second}
+//	client, err := antidetect.NewBitBrowser(apiURL, antidetect.WithEndpointRetryPolicy(antidetect.RetryReads(retry)))
+var WithEndpointRetryPolicy = bitbrowser.WithEndpointRetryPolicy
+
+// RetryReads returns a policy that retries reads and sends mutations once.
+var RetryReads = bitbrowser.RetryReads
+
+// WithEndpointTimeouts sets timeouts, keyed by endpoint path, for API calls
+// made without a context deadline.
+var WithEndpointTimeouts = bitbrowser.WithEndpointTimeouts
+
+// DefaultEndpointTimeouts returns the default timeouts of calls made without
+// a context deadline: open 120s, close 30s, ports 5s.
+var DefaultEndpointTimeouts = bitbrowser.DefaultEndpointTimeouts
+
+// WithTransport replaces the transport of the client's HTTP client.
+var WithTransport = bitbrowser.WithTransport
+
+// DefaultTransport returns the client's default transport, tuned for many
+// concurrent calls to one BitBrowser host.
+var DefaultTransport = bitbrowser.DefaultTransport
+
+// WithCompression asks the BitBrowser API for gzip-compressed responses.
+var WithCompression = bitbrowser.WithCompression
+
+// NewRetryBudget creates a budget allowing maxRetries retries per window.
+var NewRetryBudget = bitbrowser.NewRetryBudget
+
+// WithOpenBusyPolicy sets how Open reacts when BitBrowser reports that the
+// profile is busy ("正在打开"). By default Open fails fast with ErrBusy.
+var WithOpenBusyPolicy = bitbrowser.WithOpenBusyPolicy
+
+// WithOpenCacheTTL sets how long GetOrOpen reuses the result of an open.
+var WithOpenCacheTTL = bitbrowser.WithOpenCacheTTL
+
+// WithMaxOpenBrowsers caps how many browsers may run on the BitBrowser host at once.
+var WithMaxOpenBrowsers = bitbrowser.WithMaxOpenBrowsers
+
+// WithHedging enables hedged requests for idempotent read endpoints such as
+// GetPorts and GetProfileDetail.
+var WithHedging = bitbrowser.WithHedging
+
+// WithClock sets the time source used for retry backoff, busy waiting and
+// readiness polling.
+var WithClock = bitbrowser.WithClock
+
+// NewRetryBudgetWithClock is like NewRetryBudget but refills according to the given clock.
+var NewRetryBudgetWithClock = bitbrowser.NewRetryBudgetWithClock
+
+// NewFakeClock creates a Clock that only moves when advanced, for tests and simulations.
+var NewFakeClock = bitbrowser.NewFakeClock
+
+// SystemClock is the default Clock backed by the time package.
+var SystemClock = bitbrowser.SystemClock
+
+// NewBudget returns a Budget that divides a context's deadline across the given steps.
+var NewBudget = bitbrowser.NewBudget
+
+// WithRequestID returns a context carrying a correlation ID that is sent in the
+// X-Request-ID header and included in every log line.
+//
+// Example:
+//
+//	ctx := antidetect.WithRequestID(ctx, "job-42")
+//	id, err := client.CreateProfile(ctx, config)
+//	result, err := client.Open(ctx, id, nil)
+var WithRequestID = bitbrowser.WithRequestID
+
+// RequestIDFromContext returns the request ID set with WithRequestID.
+var RequestIDFromContext = bitbrowser.RequestIDFromContext
+
+// WithAuditLogger records every mutating operation to the given AuditLogger.
+var WithAuditLogger = bitbrowser.WithAuditLogger
+
+// WithAuditActor returns a context that records actor as the "who" of audit events.
+var WithAuditActor = bitbrowser.WithAuditActor
+
+// NewJSONAuditLogger creates an AuditLogger that writes JSON lines to w.
+var NewJSONAuditLogger = bitbrowser.NewJSONAuditLogger
+
+// OpenAuditLog creates an AuditLogger that appends JSON lines to a file.
+var OpenAuditLog = bitbrowser.OpenAuditLog
+
+// WithDryRun makes mutating calls log the would-be request and return a
+// synthesized success without contacting the API.
+//
+// Example:
+//
+//	client, err := antidetect.NewBitBrowser(apiURL, antidetect.WithDryRun(*dryRun))
+var WithDryRun = bitbrowser.WithDryRun
+
+// WithResponseBodyCapture attaches the redacted, truncated response body of
+// failed calls to APIError.Body and to the failure's log line.
+var WithResponseBodyCapture = bitbrowser.WithResponseBodyCapture
+
+// WithProcessKiller sets how KillBrowserProcess and CloseAndWait kill
+// browser processes on the BitBrowser host.
+var WithProcessKiller = bitbrowser.WithProcessKiller
+
+// WithProcessMonitor sets how ResourceStats reads the usage of browser
+// processes on the BitBrowser host.
+var WithProcessMonitor = bitbrowser.WithProcessMonitor
+
+// WithCrashLogs launches browsers with Chrome logging and crash dumps in a
+// per-profile directory on the BitBrowser host, collected by CrashLogs.
+var WithCrashLogs = bitbrowser.WithCrashLogs
+
+// WithClipboard sets how SetClipboard and TypeViaClipboard write the
+// clipboard of the BitBrowser host.
+var WithClipboard = bitbrowser.WithClipboard
+
+// WithProxyPool sets the proxies AssignProxyMatching picks from.
+var WithProxyPool = bitbrowser.WithProxyPool
+
+// WithPortForwarder sets how ForwardPort reaches loopback-only debugging ports.
+var WithPortForwarder = bitbrowser.WithPortForwarder
+
+// WithAppController sets how StartBitBrowserApp and StopBitBrowserApp control
+// the BitBrowser application.
+var WithAppController = bitbrowser.WithAppController
+
+// WithProfileLimit sets the account's profile limit used by GetQuota and the
+// CreateProfiles preflight check.
+var WithProfileLimit = bitbrowser.WithProfileLimit
+
+// LoadConfig builds a BitBrowser client from a config file (.json, .yaml or
+// .toml) and ANTIDETECT_* environment variables. Options passed explicitly
+// take precedence over both.
+//
+// Example:
+//
+//	client, err := antidetect.LoadConfig("/etc/antidetect.yaml")
+var LoadConfig = bitbrowser.LoadConfig
+
+// NewFleetClient creates a client that spreads work across several BitBrowser
+// instances.
+//
+// Example:
+//
+//	fleet, err := antidetect.NewFleetClient(
+//	    antidetect.WithFleetHost("node-a", clientA),
+//	    antidetect.WithFleetHost("node-b", clientB),
+//	)
+var NewFleetClient = bitbrowser.NewFleetClient
+
+// WithFleetHost adds a BitBrowser instance to a fleet under a unique name.
+var WithFleetHost = bitbrowser.WithFleetHost
+
+// WithFleetStore sets where a fleet records profile owners, e.g. a store
+// shared between processes.
+var WithFleetStore = bitbrowser.WithFleetStore
+
+// NewHealthProbes creates liveness and readiness probes for a client.
+var NewHealthProbes = bitbrowser.NewHealthProbes
+
+// WithMaxBrowsers caps how many browsers may run on a fleet host at once.
+var WithMaxBrowsers = bitbrowser.WithMaxBrowsers
+
+// WithMetricsAgent sets an agent that reports a fleet host's CPU and memory usage.
+var WithMetricsAgent = bitbrowser.WithMetricsAgent
+
+// HTTPMetricsAgent returns a MetricsAgent that reads {"cpu": ..., "memory": ...} from a URL.
+var HTTPMetricsAgent = bitbrowser.HTTPMetricsAgent
+
+// NewAgentClient creates a client for an antidetect-agent.
+var NewAgentClient = bitbrowser.NewAgentClient
+
+// WithAgentHTTPClient sets the HTTP client an AgentClient uses.
+var WithAgentHTTPClient = bitbrowser.WithAgentHTTPClient
+
+// NewMemoryFleetStore creates an empty in-process FleetStore.
+var NewMemoryFleetStore = bitbrowser.NewMemoryFleetStore
+
+// ReadConfig reads client settings from a config file and the environment
+// without building a client.
+var ReadConfig = bitbrowser.ReadConfig
+
 // WithPortRange sets the port range for Managed Mode.
 // When configured, the SDK will:
 //   - Randomly select ports from the range [minPort, maxPort]
@@ -102,6 +383,97 @@ var WithRetryConfig = bitbrowser.WithRetryConfig
 // unreachable from remote hosts. Recommended range: MinPort=50000, MaxPort=51000.
 var WithPortRange = bitbrowser.WithPortRange
 
+// WithPortProbe sets how Managed Mode checks candidate ports before opening
+// a browser on them. A timeout of 0 means DefaultProbeTimeout.
+var WithPortProbe = bitbrowser.WithPortProbe
+
+// WithPortPartition splits the Managed Mode range between workerCount
+// workers and makes the client pick ports only from slice workerIndex.
+var WithPortPartition = bitbrowser.WithPortPartition
+
+// WithPublicHost sets the host that replaces 0.0.0.0 in the endpoints Open
+// returns. By default the API URL's host is used.
+var WithPublicHost = bitbrowser.WithPublicHost
+
+// ValidateProfileID checks that an ID can be a profile ID before it is
+// sent; the client's methods do so themselves.
+var ValidateProfileID = bitbrowser.ValidateProfileID
+
+// ValidateChromeArgs checks Chrome arguments against the flag catalog for
+// missing values, duplicates and conflicting flags.
+var ValidateChromeArgs = bitbrowser.ValidateChromeArgs
+
+// ValidateTimeZone checks a fingerprint's time zone against the IANA tz
+// database and its offset against the zone at a given time.
+var ValidateTimeZone = bitbrowser.ValidateTimeZone
+
+// TimeZoneOffset returns the Fingerprint.TimeZoneOffset of an IANA time zone
+// at a given time.
+var TimeZoneOffset = bitbrowser.TimeZoneOffset
+
+// ValidateUserAgent checks a fingerprint's manual user agent against its
+// browser, version and OS.
+var ValidateUserAgent = bitbrowser.ValidateUserAgent
+
+// GenerateUserAgent returns a realistic user agent for a fingerprint's
+// browser, version and OS.
+var GenerateUserAgent = bitbrowser.GenerateUserAgent
+
+// CheckClientHints compares the user agent and client hints of a running
+// browser with its fingerprint.
+var CheckClientHints = bitbrowser.CheckClientHints
+
+// ProbeRuntimeFingerprint reads the fingerprint an open browser shows to
+// pages, for comparing it with the stored one.
+var ProbeRuntimeFingerprint = bitbrowser.ProbeRuntimeFingerprint
+
+// CheckLeaks checks an open browser for WebRTC and DNS leaks around its proxy.
+var CheckLeaks = bitbrowser.CheckLeaks
+
+// HTTPDNSLeakService returns a DNSLeakService that reads the resolvers of a canary from a URL.
+var HTTPDNSLeakService = bitbrowser.HTTPDNSLeakService
+
+// WithReadyHook registers hooks that run on a DevTools session after every
+// Open.
+var WithReadyHook = bitbrowser.WithReadyHook
+
+// BlockResourcesHook returns a ReadyHook that blocks requests matching the
+// rules for as long as the browser runs.
+var BlockResourcesHook = bitbrowser.BlockResourcesHook
+
+// GeoOverrideHook returns a ReadyHook that emulates a time zone, locale and
+// geolocation for as long as the browser runs.
+var GeoOverrideHook = bitbrowser.GeoOverrideHook
+
+// PermissionsHook returns a ReadyHook that grants or denies browser
+// permissions without prompts.
+var PermissionsHook = bitbrowser.PermissionsHook
+
+// DeviceMetricsHook returns a ReadyHook that emulates a device's screen, pixel
+// ratio and touch input.
+var DeviceMetricsHook = bitbrowser.DeviceMetricsHook
+
+// LookupDevice returns the device of Devices with the given name.
+var LookupDevice = bitbrowser.LookupDevice
+
+// DeviceFromFingerprint returns the device a mobile fingerprint describes.
+var DeviceFromFingerprint = bitbrowser.DeviceFromFingerprint
+
+// Devices is the catalog of phones LookupDevice finds.
+var Devices = bitbrowser.Devices
+
+// CommonViewports are common desktop screen sizes, the default sizes of ViewportRange.
+var CommonViewports = bitbrowser.CommonViewports
+
+// ProxyGeoOverride returns the time zone, locale and position of a checked
+// proxy for emulating them in a running browser.
+var ProxyGeoOverride = bitbrowser.ProxyGeoOverride
+
+// WithLaunchArgs adds Chrome arguments to every Open. They and
+// OpenOptions.ExtraArgs may use variables such as {{port}} and
+// {{profileSeq}}, resolved when the browser is opened.
+var WithLaunchArgs = bitbrowser.WithLaunchArgs
+
 // NewBitBrowser creates a new BitBrowser client.
 // apiURL should be the BitBrowser API endpoint, e.g., "http://127.0.0.1:54345".
 //
@@ -143,6 +515,9 @@ type Fingerprint = bitbrowser.Fingerprint
 // This is the recommended way to open browsers with common settings.
 type OpenOptions = bitbrowser.OpenOptions
 
+// ProxyOverride describes a proxy applied to a profile just before it is opened.
+type ProxyOverride = bitbrowser.ProxyOverride
+
 // OpenConfig represents the raw API request for opening a browser.
 // For most use cases, prefer using OpenOptions with the Open method.
 type OpenConfig = bitbrowser.OpenConfig
@@ -150,6 +525,28 @@ type OpenConfig = bitbrowser.OpenConfig
 // OpenResult contains the browser connection information after opening.
 type OpenResult = bitbrowser.OpenResult
 
+// OpenWarning reports something Open did differently from what OpenOptions
+// asked for, without failing.
+type OpenWarning = bitbrowser.OpenWarning
+
+// OpenWarningCode identifies an OpenWarning.
+type OpenWarningCode = bitbrowser.OpenWarningCode
+
+// ReadyHook sets up a browser after Open, through a DevTools session
+// attached to its first page.
+type ReadyHook = bitbrowser.ReadyHook
+
+// RuntimeFingerprint is the fingerprint a running browser shows to pages.
+type RuntimeFingerprint = bitbrowser.RuntimeFingerprint
+
+// FingerprintMismatch is a fingerprint setting a running browser does not
+// show.
+type FingerprintMismatch = bitbrowser.FingerprintMismatch
+
+// ResolvedEndpoints are the parsed host, port and URLs of an OpenResult,
+// returned by OpenResult.Endpoints.
+type ResolvedEndpoints = bitbrowser.ResolvedEndpoints
+
 // BrowserVersion contains browser version information from CDP.
 type BrowserVersion = bitbrowser.BrowserVersion
 
@@ -162,6 +559,37 @@ type ListRequest = bitbrowser.ListRequest
 // ListResult contains the paginated list response.
 type ListResult = bitbrowser.ListResult
 
+// DeleteOptions configures DeleteProfilesByFilter.
+type DeleteOptions = bitbrowser.DeleteOptions
+
+// DeleteProgress reports the progress of DeleteProfilesByFilter.
+type DeleteProgress = bitbrowser.DeleteProgress
+
+// Quota describes profile usage against the account's plan limit.
+type Quota = bitbrowser.Quota
+
+// SpreadsheetMapping maps spreadsheet columns to profile settings for
+// ImportProfilesFromSpreadsheet.
+type SpreadsheetMapping = bitbrowser.SpreadsheetMapping
+
+// ImportReport lists the profiles created and the rows rejected by an import.
+type ImportReport = bitbrowser.ImportReport
+
+// ImportedProfile is a profile created from a spreadsheet row.
+type ImportedProfile = bitbrowser.ImportedProfile
+
+// RejectedRow is a spreadsheet row that did not become a profile.
+type RejectedRow = bitbrowser.RejectedRow
+
+// ExcelRows is a spreadsheet read with Client.ReadExcelRows.
+type ExcelRows = bitbrowser.ExcelRows
+
+// Report is a table written as CSV or as an Excel workbook.
+type Report = bitbrowser.Report
+
+// ProxyCheck is the outcome of checking a profile's proxy.
+type ProxyCheck = bitbrowser.ProxyCheck
+
 // PartialUpdateRequest represents a batch partial update request.
 type PartialUpdateRequest = bitbrowser.PartialUpdateRequest
 
@@ -180,6 +608,141 @@ type WindowBoundsRequest = bitbrowser.WindowBoundsRequest
 // Cookie represents a browser cookie.
 type Cookie = bitbrowser.Cookie
 
+// CookieExpiry describes a cookie that has expired or expires soon.
+type CookieExpiry = bitbrowser.CookieExpiry
+
+// KeyCookie identifies a cookie a session depends on.
+type KeyCookie = bitbrowser.KeyCookie
+
+// CookieAlert reports a key cookie that is missing, expired or about to expire.
+type CookieAlert = bitbrowser.CookieAlert
+
+// CookieWatchConfig configures a CookieWatcher.
+type CookieWatchConfig = bitbrowser.CookieWatchConfig
+
+// CookieWatcher alerts when key cookies of running profiles are missing or expired.
+type CookieWatcher = bitbrowser.CookieWatcher
+
+// ResourceLimits are memory and CPU thresholds for a browser.
+type ResourceLimits = bitbrowser.ResourceLimits
+
+// ResourceAlert reports a browser that stayed over its resource limits.
+type ResourceAlert = bitbrowser.ResourceAlert
+
+// ResourceWatchConfig configures a ResourceWatchdog.
+type ResourceWatchConfig = bitbrowser.ResourceWatchConfig
+
+// ResourceWatchdog restarts browsers that use too much memory or CPU.
+type ResourceWatchdog = bitbrowser.ResourceWatchdog
+
+// ProxyRotationConfig configures a ProxyRotator.
+type ProxyRotationConfig = bitbrowser.ProxyRotationConfig
+
+// ProxyRotation reports the rotation of a profile's proxy session.
+type ProxyRotation = bitbrowser.ProxyRotation
+
+// ProxyRotator keeps profiles on fresh sessions of session-based proxies.
+type ProxyRotator = bitbrowser.ProxyRotator
+
+// Campaign runs a task across a group of profiles, once or on an interval.
+type Campaign = bitbrowser.Campaign
+
+// CampaignConfig configures a Campaign.
+type CampaignConfig = bitbrowser.CampaignConfig
+
+// CampaignTask does a campaign's work in one open profile.
+type CampaignTask = bitbrowser.CampaignTask
+
+// CampaignResult is the outcome of a campaign task in one profile.
+type CampaignResult = bitbrowser.CampaignResult
+
+// CampaignStatus is a snapshot of a Campaign.
+type CampaignStatus = bitbrowser.CampaignStatus
+
+// CampaignState is the state of a Campaign.
+type CampaignState = bitbrowser.CampaignState
+
+// ProfileSelector returns the profiles of a campaign run.
+type ProfileSelector = bitbrowser.ProfileSelector
+
+// CookieProvider reads and writes the cookies of a running profile.
+type CookieProvider = bitbrowser.CookieProvider
+
+// Account is the platform account a profile is bound to.
+type Account = bitbrowser.Account
+
+// AccountStatus is the state of a platform account.
+type AccountStatus = bitbrowser.AccountStatus
+
+// AccountBinding is an account together with its profile ID.
+type AccountBinding = bitbrowser.AccountBinding
+
+// AccountQuery selects accounts in AccountRegistry.Find.
+type AccountQuery = bitbrowser.AccountQuery
+
+// AccountStore persists account metadata by profile ID.
+type AccountStore = bitbrowser.AccountStore
+
+// AccountRegistry binds platform accounts to profiles with uniqueness checks.
+type AccountRegistry = bitbrowser.AccountRegistry
+
+// ProxyAssignment records the proxy and exit IP of a profile.
+type ProxyAssignment = bitbrowser.ProxyAssignment
+
+// ProxyLedgerStore persists proxy assignments by profile ID.
+type ProxyLedgerStore = bitbrowser.ProxyLedgerStore
+
+// ProxyCollision is an exit IP shared by profiles of the same group or platform.
+type ProxyCollision = bitbrowser.ProxyCollision
+
+// ProxyLedger records proxy assignments and keeps exit IPs unique per group and platform.
+type ProxyLedger = bitbrowser.ProxyLedger
+
+// ProxyPool is the set of proxies AssignProxyMatching picks from.
+type ProxyPool = bitbrowser.ProxyPool
+
+// ProxyCriteria selects proxies by the country, city or ASN of their exit IP.
+type ProxyCriteria = bitbrowser.ProxyCriteria
+
+// ProxyMatch is the proxy AssignProxyMatching applied.
+type ProxyMatch = bitbrowser.ProxyMatch
+
+// LeakCheckConfig configures CheckLeaks.
+type LeakCheckConfig = bitbrowser.LeakCheckConfig
+
+// LeakReport is the result of a leak check.
+type LeakReport = bitbrowser.LeakReport
+
+// DNSResolver is a DNS resolver that looked up a canary host name.
+type DNSResolver = bitbrowser.DNSResolver
+
+// DNSLeakService reports which resolvers looked up the canary host of a leak check.
+type DNSLeakService = bitbrowser.DNSLeakService
+
+// DNSLeakServiceFunc adapts a function to the DNSLeakService interface.
+type DNSLeakServiceFunc = bitbrowser.DNSLeakServiceFunc
+
+// HealthSignal names an input of a profile's health score.
+type HealthSignal = bitbrowser.HealthSignal
+
+// HealthScore is the health of a profile, from 0 (retire) to 100.
+type HealthScore = bitbrowser.HealthScore
+
+// HealthScoreConfig configures a HealthScorer.
+type HealthScoreConfig = bitbrowser.HealthScoreConfig
+
+// HealthScorer combines profile signals into health scores.
+type HealthScorer = bitbrowser.HealthScorer
+
+// HealthCollector refreshes signals of a profile for a HealthScorer.
+type HealthCollector = bitbrowser.HealthCollector
+
+// Capabilities lists the features an antidetect browser client offers.
+type Capabilities = bitbrowser.Capabilities
+
+// CapabilityProvider reports the features of an antidetect browser client.
+type CapabilityProvider = bitbrowser.CapabilityProvider
+
 // Display represents a monitor display.
 type Display = bitbrowser.Display
 
@@ -189,10 +752,160 @@ type Rect = bitbrowser.Rect
 // RetryConfig configures the retry behavior.
 type RetryConfig = bitbrowser.RetryConfig
 
+// RetryBudget limits the total number of retries across calls that share it.
+type RetryBudget = bitbrowser.RetryBudget
+
+// EndpointRetryPolicy chooses the retry configuration of each API call by path.
+type EndpointRetryPolicy = bitbrowser.EndpointRetryPolicy
+
+// OpenBusyPolicy controls what Open does when the profile is busy.
+type OpenBusyPolicy = bitbrowser.OpenBusyPolicy
+
+// OpenRetryPolicy retries Open when the profile is busy or the browser kernel
+// is still downloading, separately from transport retries.
+type OpenRetryPolicy = bitbrowser.OpenRetryPolicy
+
+// ChromeFlag is a Chrome command-line flag from the catalog Open validates
+// arguments against.
+type ChromeFlag = bitbrowser.ChromeFlag
+
+// ProcessKiller terminates a browser process on the BitBrowser host.
+type ProcessKiller = bitbrowser.ProcessKiller
+
+// ProcessKillerFunc adapts a function to ProcessKiller.
+type ProcessKillerFunc = bitbrowser.ProcessKillerFunc
+
+// LocalProcessKiller kills processes on this machine.
+var LocalProcessKiller = bitbrowser.LocalProcessKiller
+
+// SSHProcessKiller kills processes on a remote BitBrowser host over ssh.
+type SSHProcessKiller = bitbrowser.SSHProcessKiller
+
+// ProcessStats is the memory and CPU usage of a browser's process tree.
+type ProcessStats = bitbrowser.ProcessStats
+
+// ProcessMonitor reports the usage of browser processes on the BitBrowser host.
+type ProcessMonitor = bitbrowser.ProcessMonitor
+
+// ProcessMonitorFunc adapts a function to ProcessMonitor.
+type ProcessMonitorFunc = bitbrowser.ProcessMonitorFunc
+
+// LocalProcessMonitor reads process usage on this machine.
+var LocalProcessMonitor = bitbrowser.LocalProcessMonitor
+
+// CrashLogs are the Chrome log and crash dumps of a browser.
+type CrashLogs = bitbrowser.CrashLogs
+
+// CrashDump is a minidump written by Chrome's crash handler.
+type CrashDump = bitbrowser.CrashDump
+
+// CrashCollector reads Chrome logs and crash dumps on the BitBrowser host.
+type CrashCollector = bitbrowser.CrashCollector
+
+// CrashCollectorFunc adapts a function to CrashCollector.
+type CrashCollectorFunc = bitbrowser.CrashCollectorFunc
+
+// LocalCrashCollector reads crash logs on this machine.
+var LocalCrashCollector = bitbrowser.LocalCrashCollector
+
+// ClipboardWriter sets the clipboard of the BitBrowser host.
+type ClipboardWriter = bitbrowser.ClipboardWriter
+
+// ClipboardWriterFunc adapts a function to ClipboardWriter.
+type ClipboardWriterFunc = bitbrowser.ClipboardWriterFunc
+
+// LocalClipboard sets this machine's clipboard.
+var LocalClipboard = bitbrowser.LocalClipboard
+
+// PortForwarder reaches loopback-only ports on a remote BitBrowser host.
+type PortForwarder = bitbrowser.PortForwarder
+
+// PortForward is a local proxy to a remote debugging port, from ForwardPort.
+type PortForward = bitbrowser.PortForward
+
+// SSHPortForwarder reaches remote loopback ports with "ssh -W".
+type SSHPortForwarder = bitbrowser.SSHPortForwarder
+
+// AppController starts and stops the BitBrowser application.
+type AppController = bitbrowser.AppController
+
+// LocalAppController starts and stops BitBrowser with local commands.
+type LocalAppController = bitbrowser.LocalAppController
+
+// HedgingConfig configures hedged requests for idempotent read endpoints.
+type HedgingConfig = bitbrowser.HedgingConfig
+
+// Clock abstracts time for retry backoff and polling.
+type Clock = bitbrowser.Clock
+
+// FakeClock is a manually advanced Clock.
+type FakeClock = bitbrowser.FakeClock
+
+// Budget divides a context's deadline across the steps of a multi-step operation.
+type Budget = bitbrowser.Budget
+
+// BudgetStep is a named, weighted step of a Budget.
+type BudgetStep = bitbrowser.BudgetStep
+
+// AuditLogger receives an event for every mutating operation.
+type AuditLogger = bitbrowser.AuditLogger
+
+// AuditLoggerFunc adapts a function to the AuditLogger interface.
+type AuditLoggerFunc = bitbrowser.AuditLoggerFunc
+
+// AuditEvent records a single mutating operation.
+type AuditEvent = bitbrowser.AuditEvent
+
+// JSONAuditLogger writes audit events as JSON lines.
+type JSONAuditLogger = bitbrowser.JSONAuditLogger
+
 // PortConfig configures the port management behavior.
 // See the package documentation for detailed usage of Managed Mode vs Native Mode.
 type PortConfig = bitbrowser.PortConfig
 
+// ProbeMode is how Managed Mode checks that a port is free.
+type ProbeMode = bitbrowser.ProbeMode
+
+// ProxyType is the protocol of a profile's proxy.
+type ProxyType = bitbrowser.ProxyType
+
+// OSType is the device class a profile emulates.
+type OSType = bitbrowser.OSType
+
+// Device is a phone a mobile profile emulates.
+type Device = bitbrowser.Device
+
+// Viewport is a window or viewport size in CSS pixels.
+type Viewport = bitbrowser.Viewport
+
+// ViewportRange selects the viewport of OpenOptions.RandomizeViewport.
+type ViewportRange = bitbrowser.ViewportRange
+
+// Workbench is what a profile shows on its workbench page when it opens.
+type Workbench = bitbrowser.Workbench
+
+// DynamicIpChannel is the provider of an extracted-IP proxy.
+type DynamicIpChannel = bitbrowser.DynamicIpChannel
+
+// IpCheckService is the service BitBrowser asks for a proxy's exit IP.
+type IpCheckService = bitbrowser.IpCheckService
+
+// SSHProxy is an SSH server used as a profile's proxy. BitBrowser only
+// supports password authentication for it.
+type SSHProxy = bitbrowser.SSHProxy
+
+// Bool returns a pointer to v, for the *bool fields of ProfileConfig and
+// Fingerprint that default to true, such as SyncTabs.
+//
+// Example:
+//
+//	config := antidetect.ProfileConfig{Name: "no-sync", SyncTabs: antidetect.Bool(false)}
+var Bool = bitbrowser.Bool
+
+// OpenAPISpec returns an OpenAPI 3.0 document for the BitBrowser endpoints,
+// generated from the SDK's request and response types.
+var OpenAPISpec = bitbrowser.OpenAPISpec
+
 // DefaultRetryConfig returns a RetryConfig with sensible defaults.
 // By default, MaxAttempts is 1 (no retries) for backward compatibility.
 var DefaultRetryConfig = bitbrowser.DefaultRetryConfig
@@ -200,6 +913,167 @@ var DefaultRetryConfig = bitbrowser.DefaultRetryConfig
 // DefaultPortConfig returns a PortConfig with Native Mode (no port management).
 var DefaultPortConfig = bitbrowser.DefaultPortConfig
 
+// SyncCookies copies cookies, optionally limited to some domains, from one
+// running profile to another, possibly of a different provider.
+//
+// Example:
+//
+//	n, err := antidetect.SyncCookies(ctx, oldClient, oldID, newClient, newID, "example.com")
+var SyncCookies = bitbrowser.SyncCookies
+
+// FilterCookies returns the cookies of the given domains and their subdomains.
+var FilterCookies = bitbrowser.FilterCookies
+
+// NormalizeCookies converts cookies to the conventions BitBrowser expects.
+var NormalizeCookies = bitbrowser.NormalizeCookies
+
+// ParseCookies parses a JSON cookie export from another browser or tool.
+var ParseCookies = bitbrowser.ParseCookies
+
+// AnalyzeCookies returns, per domain, the cookies that have expired or expire within a window.
+var AnalyzeCookies = bitbrowser.AnalyzeCookies
+
+// AnalyzeCookiesAt is AnalyzeCookies at a given time.
+var AnalyzeCookiesAt = bitbrowser.AnalyzeCookiesAt
+
+// NewCookieWatcher creates a watcher for the key cookies of running profiles.
+//
+// Example:
+//
+//	watcher := antidetect.NewCookieWatcher(client, antidetect.CookieWatchConfig{
+//	    KeyCookies: []antidetect.KeyCookie{{Domain: "example.com", Name: "session_id"}},
+//	    OnAlert:    func(a antidetect.CookieAlert) { log.Printf("%s: %s %s", a.ProfileID, a.Name, a.Reason) },
+//	})
+//	go watcher.Watch(ctx, profileID)
+var NewCookieWatcher = bitbrowser.NewCookieWatcher
+
+// NewResourceWatchdog creates a watchdog that restarts runaway browsers.
+//
+// Example:
+//
+//	watchdog := antidetect.NewResourceWatchdog(client, antidetect.ResourceWatchConfig{
+//	    Limits:  antidetect.ResourceLimits{MaxRSS: 4 << 30, MaxCPU: 150},
+//	    OnAlert: func(a antidetect.ResourceAlert) { log.Printf("%s: %s", a.ProfileID, a.Reason) },
+//	})
+//	go watchdog.Watch(ctx)
+var NewResourceWatchdog = bitbrowser.NewResourceWatchdog
+
+// NewProxyRotator creates a rotator that sets new proxy session IDs as
+// sessions expire or on demand.
+//
+// Example:
+//
+//	rotator, err := antidetect.NewProxyRotator(client, antidetect.ProxyRotationConfig{
+//	    Proxy: antidetect.ProxyUpdateRequest{
+//	        ProxyType: antidetect.ProxyTypeHTTP, Host: "gate.example-proxy.com", Port: 7000,
+//	        ProxyUserName: "customer-acme-session-{{session}}", ProxyPassword: "secret",
+//	    },
+//	    TTL: 25 * time.Minute,
+//	})
+//	go rotator.Run(ctx, ids...)
+var NewProxyRotator = bitbrowser.NewProxyRotator
+
+// NewCampaign creates a campaign that runs a task across selected profiles.
+//
+// Example:
+//
+//	campaign, err := antidetect.NewCampaign(client, antidetect.CampaignConfig{
+//	    Profiles:    antidetect.SelectProfilesWhere(antidetect.ListRequest{GroupID: groupID}, nil),
+//	    Concurrency: 3,
+//	    Task:        task,
+//	})
+//	campaign.Start(ctx)
+var NewCampaign = bitbrowser.NewCampaign
+
+// SelectProfiles returns a ProfileSelector of fixed profiles.
+var SelectProfiles = bitbrowser.SelectProfiles
+
+// SelectProfilesWhere returns a ProfileSelector of the listed profiles that match a filter.
+var SelectProfilesWhere = bitbrowser.SelectProfilesWhere
+
+// ReadExcelTyped reads an Excel file on the BitBrowser host and maps each
+// data row to a struct, matching fields to columns by `excel:"Header"` tags.
+func ReadExcelTyped[T any](ctx context.Context, c *BitBrowserClient, path string) ([]T, error) {
+	return bitbrowser.ReadExcelTyped[T](ctx, c, path)
+}
+
+// ProfilesReport lists profiles for export with Report.Save.
+//
+// Example:
+//
+//	profiles, _ := client.ListProfiles(ctx, antidetect.ListRequest{PageSize: 100})
+//	err := antidetect.ProfilesReport(profiles.List).Save("profiles.xlsx")
+var ProfilesReport = bitbrowser.ProfilesReport
+
+// ProxyCheckReport lists the results of Client.CheckProfileProxies.
+var ProxyCheckReport = bitbrowser.ProxyCheckReport
+
+// UsageReport lists the browser count and resource usage of fleet hosts.
+var UsageReport = bitbrowser.UsageReport
+
+// TrafficReport lists the traffic of profiles from Client.ListTrafficStats.
+var TrafficReport = bitbrowser.TrafficReport
+
+// TrafficStats is the network traffic of a profile's browser pages.
+type TrafficStats = bitbrowser.TrafficStats
+
+// WithTrafficAccounting counts the bytes every opened browser sends and
+// receives, per profile.
+var WithTrafficAccounting = bitbrowser.WithTrafficAccounting
+
+// DefaultSpreadsheetMapping reads the columns "name", "remark", "group",
+// "proxy", "cookies", "ua", "platform", "username" and "password".
+var DefaultSpreadsheetMapping = bitbrowser.DefaultSpreadsheetMapping
+
+// NewAccountRegistry creates a registry of account bindings backed by a store.
+//
+// Example:
+//
+//	accounts := antidetect.NewAccountRegistry(antidetect.NewRemarkAccountStore(client))
+//	err := accounts.Bind(ctx, id, antidetect.Account{Platform: "https://x.com", Handle: "jdoe"})
+var NewAccountRegistry = bitbrowser.NewAccountRegistry
+
+// NewRemarkAccountStore stores account metadata in profile remarks.
+var NewRemarkAccountStore = bitbrowser.NewRemarkAccountStore
+
+// NewFileAccountStore stores account metadata in a sidecar JSON file.
+var NewFileAccountStore = bitbrowser.NewFileAccountStore
+
+// NewProxyLedger creates a ledger of proxy assignments backed by a store.
+//
+// Example:
+//
+//	ledger := antidetect.NewProxyLedger(client, antidetect.NewFileProxyLedgerStore("proxies.json"))
+//	err := ledger.Assign(ctx, id, antidetect.ProxyUpdateRequest{ProxyType: antidetect.ProxyTypeSOCKS5, Host: "10.0.0.9", Port: 1080})
+var NewProxyLedger = bitbrowser.NewProxyLedger
+
+// NewFileProxyLedgerStore stores proxy assignments in a JSON file.
+var NewFileProxyLedgerStore = bitbrowser.NewFileProxyLedgerStore
+
+// EncodeAccountRemark stores an account in the last line of a remark.
+var EncodeAccountRemark = bitbrowser.EncodeAccountRemark
+
+// DecodeAccountRemark extracts an account stored by EncodeAccountRemark.
+var DecodeAccountRemark = bitbrowser.DecodeAccountRemark
+
+// NewHealthScorer creates a scorer that combines cookie freshness, logins,
+// proxy status, fingerprint self-tests and failures into profile health.
+//
+// Example:
+//
+//	scorer := antidetect.NewHealthScorer(antidetect.HealthScoreConfig{
+//	    Collectors: []antidetect.HealthCollector{antidetect.ProxyHealthCollector(client)},
+//	})
+//	go scorer.Run(ctx, ids...)
+//	retire := scorer.Below(40)
+var NewHealthScorer = bitbrowser.NewHealthScorer
+
+// CookieHealthCollector feeds key cookie checks into a HealthScorer.
+var CookieHealthCollector = bitbrowser.CookieHealthCollector
+
+// ProxyHealthCollector feeds proxy checks into a HealthScorer.
+var ProxyHealthCollector = bitbrowser.ProxyHealthCollector
+
 // ============================================================================
 // Error Types
 // ============================================================================
@@ -220,6 +1094,55 @@ var (
 
 	// ErrRetryExhausted indicates all retry attempts have been exhausted.
 	ErrRetryExhausted = bitbrowser.ErrRetryExhausted
+
+	// ErrRetryBudgetExhausted indicates retries were stopped by a shared RetryBudget.
+	ErrRetryBudgetExhausted = bitbrowser.ErrRetryBudgetExhausted
+
+	// ErrBusy indicates BitBrowser is busy with the profile (e.g. "正在打开").
+	ErrBusy = bitbrowser.ErrBusy
+
+	// ErrQuotaExceeded indicates the account's profile quota has been reached.
+	ErrQuotaExceeded = bitbrowser.ErrQuotaExceeded
+
+	// ErrProfileNotFound indicates no fleet host has the profile, or no
+	// profile has a sequence number.
+	ErrProfileNotFound = bitbrowser.ErrProfileNotFound
+
+	// ErrProfileLocked indicates the profile is in use by another team member or device.
+	ErrProfileLocked = bitbrowser.ErrProfileLocked
+
+	// ErrKernelDownloading indicates BitBrowser is still downloading the browser kernel.
+	ErrKernelDownloading = bitbrowser.ErrKernelDownloading
+
+	// ErrInsufficientBalance indicates the account balance cannot cover the operation.
+	ErrInsufficientBalance = bitbrowser.ErrInsufficientBalance
+
+	// ErrDraining indicates the service is shutting down and takes no new work.
+	ErrDraining = bitbrowser.ErrDraining
+
+	// ErrNotSupportedByVersion indicates the installed BitBrowser version lacks an endpoint.
+	ErrNotSupportedByVersion = bitbrowser.ErrNotSupportedByVersion
+
+	// ErrBrowserNotRunning indicates the profile has no live browser process.
+	ErrBrowserNotRunning = bitbrowser.ErrBrowserNotRunning
+
+	// ErrNoCapacity indicates no fleet host can take another browser.
+	ErrNoCapacity = bitbrowser.ErrNoCapacity
+
+	// ErrProfileNotOnHost indicates a fleet routed a profile to a host that does not have it.
+	ErrProfileNotOnHost = bitbrowser.ErrProfileNotOnHost
+
+	// ErrAccountBound indicates a platform account is already bound to another profile.
+	ErrAccountBound = bitbrowser.ErrAccountBound
+
+	// ErrProxyCheckFailed indicates BitBrowser could not connect through a proxy.
+	ErrProxyCheckFailed = bitbrowser.ErrProxyCheckFailed
+
+	// ErrProxyInUse indicates another profile of the same group or platform uses the exit IP.
+	ErrProxyInUse = bitbrowser.ErrProxyInUse
+
+	// ErrNoMatchingProxy indicates no healthy proxy of the pool matches the criteria.
+	ErrNoMatchingProxy = bitbrowser.ErrNoMatchingProxy
 )
 
 // NetworkError represents a network-level error.
@@ -237,6 +1160,21 @@ type TimeoutError = bitbrowser.TimeoutError
 // RetryError represents an error after all retry attempts have been exhausted.
 type RetryError = bitbrowser.RetryError
 
+// FleetError is a failure on one host of a FleetClient.
+type FleetError = bitbrowser.FleetError
+
+// ProfileNotOnHostError reports a routing miss and the host that has the profile.
+type ProfileNotOnHostError = bitbrowser.ProfileNotOnHostError
+
+// ProfileError is a failure for one profile of a call that handles many.
+type ProfileError = bitbrowser.ProfileError
+
+// ProfileErrors returns the *ProfileError failures joined in an error.
+var ProfileErrors = bitbrowser.ProfileErrors
+
+// QuotaError is returned when a batch would exceed the remaining profile quota.
+type QuotaError = bitbrowser.QuotaError
+
 // IsRetryable determines if an error is retryable.
 // Network errors and certain HTTP status codes are considered retryable.
 // API business logic errors (e.g., "profile not found") are not retryable.
@@ -253,4 +1191,162 @@ const (
 	ProxyMethodCustom = bitbrowser.ProxyMethodCustom
 	// ProxyMethodExtract indicates using extracted IP (value: 3).
 	ProxyMethodExtract = bitbrowser.ProxyMethodExtract
+
+	// OSTypePC is a desktop profile.
+	OSTypePC = bitbrowser.OSTypePC
+	// OSTypeAndroid is an Android phone profile.
+	OSTypeAndroid = bitbrowser.OSTypeAndroid
+	// OSTypeIOS is an iPhone profile.
+	OSTypeIOS = bitbrowser.OSTypeIOS
+
+	// ProxyTypeNone connects directly, without a proxy.
+	ProxyTypeNone = bitbrowser.ProxyTypeNone
+	// ProxyTypeHTTP is an HTTP proxy.
+	ProxyTypeHTTP = bitbrowser.ProxyTypeHTTP
+	// ProxyTypeHTTPS is an HTTPS proxy.
+	ProxyTypeHTTPS = bitbrowser.ProxyTypeHTTPS
+	// ProxyTypeSOCKS5 is a SOCKS5 proxy.
+	ProxyTypeSOCKS5 = bitbrowser.ProxyTypeSOCKS5
+	// ProxyTypeSSH is an SSH tunnel.
+	ProxyTypeSSH = bitbrowser.ProxyTypeSSH
+	// DefaultSSHPort is the port SSHProxy uses when Port is 0.
+	DefaultSSHPort = bitbrowser.DefaultSSHPort
+
+	// RotateExpired is the reason of a rotation whose session reached its TTL.
+	RotateExpired = bitbrowser.RotateExpired
+	// RotateNew is the reason of a profile's first rotation.
+	RotateNew = bitbrowser.RotateNew
+
+	// DefaultLeakCheckSTUNServer is the STUN server CheckLeaks asks by default.
+	DefaultLeakCheckSTUNServer = bitbrowser.DefaultLeakCheckSTUNServer
+	// DefaultLeakCheckWait bounds WebRTC candidate gathering by default.
+	DefaultLeakCheckWait = bitbrowser.DefaultLeakCheckWait
+
+	// DefaultViewportJitter is the default of ViewportRange.Jitter.
+	DefaultViewportJitter = bitbrowser.DefaultViewportJitter
+
+	// CampaignIdle is a campaign that has not been started.
+	CampaignIdle = bitbrowser.CampaignIdle
+	// CampaignRunning is a campaign launching profiles.
+	CampaignRunning = bitbrowser.CampaignRunning
+	// CampaignPaused is a campaign launching nothing new.
+	CampaignPaused = bitbrowser.CampaignPaused
+	// CampaignWaiting is a campaign between runs.
+	CampaignWaiting = bitbrowser.CampaignWaiting
+	// CampaignFinished is a campaign whose single run ended.
+	CampaignFinished = bitbrowser.CampaignFinished
+	// CampaignStopped is a campaign whose context ended.
+	CampaignStopped = bitbrowser.CampaignStopped
+
+	// WorkbenchLocalServer shows the local workbench page.
+	WorkbenchLocalServer = bitbrowser.WorkbenchLocalServer
+	// WorkbenchDisable opens without the workbench page.
+	WorkbenchDisable = bitbrowser.WorkbenchDisable
+
+	// DynamicIpChannelRola extracts IPs from Rola.
+	DynamicIpChannelRola = bitbrowser.DynamicIpChannelRola
+	// DynamicIpChannelIpidea extracts IPs from IPIDEA.
+	DynamicIpChannelIpidea = bitbrowser.DynamicIpChannelIpidea
+	// DynamicIpChannelDoveip extracts IPs from DoveIP.
+	DynamicIpChannelDoveip = bitbrowser.DynamicIpChannelDoveip
+	// DynamicIpChannelCloudam extracts IPs from Cloudam.
+	DynamicIpChannelCloudam = bitbrowser.DynamicIpChannelCloudam
+	// DynamicIpChannelCommon extracts IPs from any provider's extraction URL.
+	DynamicIpChannelCommon = bitbrowser.DynamicIpChannelCommon
+
+	// IpCheckServiceIP123in checks exit IPs with ip123.in.
+	IpCheckServiceIP123in = bitbrowser.IpCheckServiceIP123in
+	// IpCheckServiceIPAPI checks exit IPs with ip-api.com.
+	IpCheckServiceIPAPI = bitbrowser.IpCheckServiceIPAPI
+	// IpCheckServiceLuminati checks exit IPs with Luminati.
+	IpCheckServiceLuminati = bitbrowser.IpCheckServiceLuminati
+
+	// ProbeNone picks Managed Mode ports without any check.
+	ProbeNone = bitbrowser.ProbeNone
+	// ProbeAPIOnly skips ports BitBrowser reports in use (the default).
+	ProbeAPIOnly = bitbrowser.ProbeAPIOnly
+	// ProbeTCP skips ports that accept TCP connections on the BitBrowser host.
+	ProbeTCP = bitbrowser.ProbeTCP
+	// ProbeBoth combines ProbeAPIOnly and ProbeTCP.
+	ProbeBoth = bitbrowser.ProbeBoth
+	// DefaultProbeTimeout is the default timeout of each TCP port probe.
+	DefaultProbeTimeout = bitbrowser.DefaultProbeTimeout
+
+	// AccountActive marks an account in use.
+	AccountActive = bitbrowser.AccountActive
+	// AccountPending marks an account not yet verified or warmed up.
+	AccountPending = bitbrowser.AccountPending
+	// AccountSuspended marks a temporarily suspended account.
+	AccountSuspended = bitbrowser.AccountSuspended
+	// AccountBanned marks a banned account.
+	AccountBanned = bitbrowser.AccountBanned
+
+	// SignalCookies scores whether key cookies are present and fresh.
+	SignalCookies = bitbrowser.SignalCookies
+	// SignalLogin scores the time since the last successful login.
+	SignalLogin = bitbrowser.SignalLogin
+	// SignalProxy scores the last proxy check.
+	SignalProxy = bitbrowser.SignalProxy
+	// SignalFingerprint scores the last fingerprint self-test.
+	SignalFingerprint = bitbrowser.SignalFingerprint
+	// SignalFailures scores consecutive failed runs.
+	SignalFailures = bitbrowser.SignalFailures
+
+	// FlagHeadless runs without a window.
+	FlagHeadless = bitbrowser.FlagHeadless
+	// FlagStartMaximized opens the window maximized.
+	FlagStartMaximized = bitbrowser.FlagStartMaximized
+	// FlagStartFullscreen opens the window in full screen.
+	FlagStartFullscreen = bitbrowser.FlagStartFullscreen
+	// FlagKiosk opens full screen without browser UI.
+	FlagKiosk = bitbrowser.FlagKiosk
+	// FlagWindowSize sets the window size.
+	FlagWindowSize = bitbrowser.FlagWindowSize
+	// FlagWindowPosition sets the window position.
+	FlagWindowPosition = bitbrowser.FlagWindowPosition
+	// FlagLang sets the UI language.
+	FlagLang = bitbrowser.FlagLang
+	// FlagAcceptLang sets the Accept-Language list.
+	FlagAcceptLang = bitbrowser.FlagAcceptLang
+	// FlagUserAgent sets the User-Agent header.
+	FlagUserAgent = bitbrowser.FlagUserAgent
+	// FlagProxyServer replaces the profile's proxy.
+	FlagProxyServer = bitbrowser.FlagProxyServer
+	// FlagProxyBypassList lists hosts that skip the proxy.
+	FlagProxyBypassList = bitbrowser.FlagProxyBypassList
+	// FlagDisableBlinkFeatures disables Blink features.
+	FlagDisableBlinkFeatures = bitbrowser.FlagDisableBlinkFeatures
+	// FlagDisableFeatures disables Chrome features.
+	FlagDisableFeatures = bitbrowser.FlagDisableFeatures
+	// FlagEnableFeatures enables Chrome features.
+	FlagEnableFeatures = bitbrowser.FlagEnableFeatures
+	// FlagIncognito opens in incognito mode.
+	FlagIncognito = bitbrowser.FlagIncognito
+	// FlagDisableGPU disables GPU acceleration.
+	FlagDisableGPU = bitbrowser.FlagDisableGPU
+	// FlagNoSandbox disables the sandbox.
+	FlagNoSandbox = bitbrowser.FlagNoSandbox
+	// FlagMuteAudio mutes all audio.
+	FlagMuteAudio = bitbrowser.FlagMuteAudio
+	// FlagDisableExtensions disables all extensions.
+	FlagDisableExtensions = bitbrowser.FlagDisableExtensions
+	// FlagLoadExtension loads unpacked extensions.
+	FlagLoadExtension = bitbrowser.FlagLoadExtension
+	// FlagRemoteDebuggingPort sets the DevTools port.
+	FlagRemoteDebuggingPort = bitbrowser.FlagRemoteDebuggingPort
+	// FlagRemoteDebuggingAddress sets the DevTools listen address.
+	FlagRemoteDebuggingAddress = bitbrowser.FlagRemoteDebuggingAddress
+	// FlagEnableLogging writes Chrome's log.
+	FlagEnableLogging = bitbrowser.FlagEnableLogging
+	// FlagLogFile sets the log file.
+	FlagLogFile = bitbrowser.FlagLogFile
+	// FlagCrashDumpsDir sets the crash dump directory.
+	FlagCrashDumpsDir = bitbrowser.FlagCrashDumpsDir
+
+	// WarnStartURLNavigated reports that a headless StartURL was loaded
+	// after launch.
+	WarnStartURLNavigated = bitbrowser.WarnStartURLNavigated
+	// WarnStartURLDropped reports that a headless StartURL could not be
+	// loaded after launch.
+	WarnStartURLDropped = bitbrowser.WarnStartURLDropped
 )
@@ -0,0 +1,108 @@
+// Command antidetect-agent runs on a BitBrowser machine and exposes process
+// kill, BitBrowser start/stop, disk usage, host metrics, desktop screenshots,
+// clipboard writes and port checks over an authenticated HTTP API. See package agent for the
+// API.
+//
+// Usage:
+//
+//	ANTIDETECT_AGENT_TOKEN=secret antidetect-agent -addr :54350
+//	antidetect-agent -token-file /etc/antidetect-agent/token -tls-cert cert.pem -tls-key key.pem
+//	antidetect-agent -app-start "systemctl start bitbrowser" -app-stop "systemctl stop bitbrowser"
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/agent"
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+func main() {
+	addr := flag.String("addr", ":54350", "listen address")
+	tokenFile := flag.String("token-file", "", "file containing the API token (default: $ANTIDETECT_AGENT_TOKEN)")
+	certFile := flag.String("tls-cert", "", "TLS certificate file; serves HTTPS with -tls-key")
+	keyFile := flag.String("tls-key", "", "TLS private key file")
+	diskPath := flag.String("disk-path", "", "path reported by /disk by default (default: working directory)")
+	appStart := flag.String("app-start", "", "command that launches BitBrowser; enables /app/start")
+	appStop := flag.String("app-stop", "", "command that stops BitBrowser; enables /app/stop")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	token := os.Getenv("ANTIDETECT_AGENT_TOKEN")
+	if *tokenFile != "" {
+		data, err := os.ReadFile(*tokenFile)
+		if err != nil {
+			logger.Error("read token file", "error", err)
+			os.Exit(1)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+
+	config := agent.Config{Token: token, DiskPath: *diskPath, Logger: logger}
+	if *appStart != "" || *appStop != "" {
+		config.App = &bitbrowser.LocalAppController{Start: splitCommand(*appStart), Stop: splitCommand(*appStop)}
+	}
+	handler, err := agent.NewHandler(config)
+	if err != nil {
+		logger.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	server := &http.Server{Addr: *addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("antidetect-agent listening", "addr", *addr, "tls", *certFile != "")
+	if *certFile != "" {
+		err = server.ListenAndServeTLS(*certFile, *keyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("serve", "error", err)
+		os.Exit(1)
+	}
+}
+
+// splitCommand splits a command line at spaces, keeping double-quoted parts
+// together so that paths such as "C:\Program Files\..." survive.
+func splitCommand(s string) []string {
+	var args []string
+	var arg strings.Builder
+	quoted, inArg := false, false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted, inArg = !quoted, true
+		case r == ' ' && !quoted:
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args
+}
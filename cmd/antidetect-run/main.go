@@ -0,0 +1,115 @@
+// Command antidetect-run runs a macro file against a BitBrowser profile and
+// prints what its extract and exportCookies steps stored as JSON. The file
+// holds the steps read by cdp.ParseMacro; "-" reads them from stdin. The
+// profile is opened if it is not running.
+//
+// Usage:
+//
+//	antidetect-run -profile 2c9f5a1e... task.json
+//	antidetect-run -api http://10.0.0.5:54345 -seq 42 -close -timeout 2m task.json
+//
+// A task file:
+//
+//	{"steps": [
+//	    {"action": "navigate", "url": "https://example.com/"},
+//	    {"action": "waitFor", "selector": "h1", "timeout": "10s"},
+//	    {"action": "click", "selector": "a.more"},
+//	    {"action": "type", "selector": "#q", "text": "shoes"},
+//	    {"action": "extract", "selector": "h1", "name": "title"},
+//	    {"action": "extract", "selector": "a", "attribute": "href", "all": true, "name": "links"},
+//	    {"action": "exportCookies"}
+//	]}
+//
+// or the same steps in YAML:
+//
+//	steps:
+//	  - action: navigate
+//	    url: https://example.com/
+//	  - action: waitFor
+//	    selector: h1
+//	    timeout: 10s
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+func main() {
+	apiURL := flag.String("api", "http://127.0.0.1:54345", "BitBrowser API URL")
+	profile := flag.String("profile", "", "profile ID")
+	seq := flag.Int("seq", 0, "profile sequence number, instead of -profile")
+	closeAfter := flag.Bool("close", false, "close the browser after the macro")
+	timeout := flag.Duration("timeout", 5*time.Minute, "time limit for opening the profile and running the macro")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: antidetect-run [flags] (-profile ID | -seq N) task.json\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || (*profile == "") == (*seq == 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*apiURL, *profile, *seq, flag.Arg(0), *closeAfter, *timeout); err != nil {
+		fmt.Fprintln(os.Stderr, "antidetect-run:", err)
+		os.Exit(1)
+	}
+}
+
+func run(apiURL, id string, seq int, path string, closeAfter bool, timeout time.Duration) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	steps, err := cdp.ParseMacro(data)
+	if err != nil {
+		return err
+	}
+
+	client, err := bitbrowser.New(apiURL)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if seq != 0 {
+		if id, err = client.ResolveSeq(ctx, seq); err != nil {
+			return err
+		}
+	}
+	output, runErr := client.RunMacro(ctx, id, steps)
+	if closeAfter {
+		if err := client.Close(context.WithoutCancel(ctx), id); err != nil && runErr == nil {
+			runErr = err
+		}
+	}
+
+	// Print what was stored, also before a failing step
+	if len(output) > 0 || runErr == nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return err
+		}
+	}
+	return runErr
+}
@@ -39,13 +39,13 @@ func main() {
 		Remark: "Created by go-antidetect SDK",
 		// Configure proxy (optional)
 		ProxyMethod: antidetect.ProxyMethodCustom,
-		ProxyType:   "socks5",
+		ProxyType:   antidetect.ProxyTypeSOCKS5,
 		Host:        "127.0.0.1",
 		Port:        1080,
 		// Fingerprint configuration (optional, will use defaults if not set)
 		BrowserFingerPrint: &antidetect.Fingerprint{
 			CoreVersion: "130",
-			OSType:      "PC",
+			OSType:      antidetect.OSTypePC,
 			OS:          "Win32",
 			OSVersion:   "10",
 		},
@@ -0,0 +1,321 @@
+package agent
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+// portDialTimeout bounds a /port check.
+const portDialTimeout = 2 * time.Second
+
+// maxClipboardBytes bounds a /clipboard request body.
+const maxClipboardBytes = 8 << 20
+
+// Config configures the agent's HTTP handler.
+type Config struct {
+	// Token authenticates requests. Required.
+	Token string
+
+	// Killer kills processes for /kill. Default: bitbrowser.LocalProcessKiller.
+	Killer bitbrowser.ProcessKiller
+
+	// Monitor reads process usage for /processes.
+	// Default: bitbrowser.LocalProcessMonitor.
+	Monitor bitbrowser.ProcessMonitor
+
+	// Crashes reads Chrome logs and crash dumps for /crashlogs.
+	// Default: bitbrowser.LocalCrashCollector.
+	Crashes bitbrowser.CrashCollector
+
+	// App starts and stops BitBrowser for /app/start and /app/stop, typically
+	// a *bitbrowser.LocalAppController. Nil disables those endpoints.
+	App bitbrowser.AppController
+
+	// Screenshot captures the desktop as PNG for /screenshot.
+	// Default: CaptureDesktop.
+	Screenshot func(ctx context.Context) ([]byte, error)
+
+	// Clipboard sets the desktop clipboard for /clipboard.
+	// Default: bitbrowser.LocalClipboard.
+	Clipboard bitbrowser.ClipboardWriter
+
+	// DiskPath is the path reported by /disk when none is given.
+	// Default: the working directory.
+	DiskPath string
+
+	// Logger receives a line per failed request. Default: no logging.
+	Logger *slog.Logger
+}
+
+// handler serves the agent API.
+type handler struct {
+	config Config
+	mux    *http.ServeMux
+}
+
+// NewHandler returns the agent's HTTP handler.
+func NewHandler(config Config) (http.Handler, error) {
+	if config.Token == "" {
+		return nil, bitbrowser.NewValidationError("Token", "agent token is required")
+	}
+	if config.Killer == nil {
+		config.Killer = bitbrowser.LocalProcessKiller
+	}
+	if config.Monitor == nil {
+		config.Monitor = bitbrowser.LocalProcessMonitor
+	}
+	if config.Crashes == nil {
+		config.Crashes = bitbrowser.LocalCrashCollector
+	}
+	if config.Screenshot == nil {
+		config.Screenshot = CaptureDesktop
+	}
+	if config.Clipboard == nil {
+		config.Clipboard = bitbrowser.LocalClipboard
+	}
+	if config.DiskPath == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		config.DiskPath = wd
+	}
+
+	h := &handler{config: config, mux: http.NewServeMux()}
+	h.mux.HandleFunc("POST /kill", h.kill)
+	h.mux.HandleFunc("POST /app/start", h.app)
+	h.mux.HandleFunc("POST /app/stop", h.app)
+	h.mux.HandleFunc("POST /clipboard", h.clipboard)
+	h.mux.HandleFunc("GET /disk", h.disk)
+	h.mux.HandleFunc("GET /metrics", h.metrics)
+	h.mux.HandleFunc("GET /port", h.port)
+	h.mux.HandleFunc("GET /processes", h.processes)
+	h.mux.HandleFunc("GET /crashlogs", h.crashLogs)
+	h.mux.HandleFunc("GET /screenshot", h.screenshot)
+	h.mux.HandleFunc("GET /tunnel", h.tunnel)
+	h.mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"status": "ok"})
+	})
+	return h, nil
+}
+
+// ServeHTTP authenticates the request and dispatches it. Responses are
+// gzip-compressed for clients that accept it.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.config.Token)) != 1 {
+		h.fail(w, r, http.StatusUnauthorized, errors.New("unauthorized"))
+		return
+	}
+	if acceptsGzip(r) {
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.close()
+		w = gw
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *handler) kill(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		PID int `json:"pid"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PID <= 0 {
+		h.fail(w, r, http.StatusBadRequest, errors.New("a positive pid is required"))
+		return
+	}
+	if err := h.config.Killer.Kill(r.Context(), req.PID); err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) app(w http.ResponseWriter, r *http.Request) {
+	if h.config.App == nil {
+		h.fail(w, r, http.StatusNotImplemented, errors.New("app control is not configured"))
+		return
+	}
+	control := h.config.App.StartApp
+	if r.URL.Path == "/app/stop" {
+		control = h.config.App.StopApp
+	}
+	if err := control(r.Context()); err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) clipboard(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Text *string `json:"text"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxClipboardBytes)).Decode(&req); err != nil || req.Text == nil {
+		h.fail(w, r, http.StatusBadRequest, errors.New("text is required"))
+		return
+	}
+	if err := h.config.Clipboard.SetClipboard(r.Context(), *req.Text); err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) disk(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		path = h.config.DiskPath
+	}
+	usage, err := diskUsage(path)
+	if err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, usage)
+}
+
+func (h *handler) metrics(w http.ResponseWriter, r *http.Request) {
+	m, err := hostMetrics(r.Context())
+	if errors.Is(err, errors.ErrUnsupported) {
+		h.fail(w, r, http.StatusNotImplemented, err)
+		return
+	}
+	if err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, m)
+}
+
+func (h *handler) port(w http.ResponseWriter, r *http.Request) {
+	port, err := strconv.Atoi(r.URL.Query().Get("port"))
+	if err != nil || port <= 0 || port > 65535 {
+		h.fail(w, r, http.StatusBadRequest, errors.New("port must be between 1 and 65535"))
+		return
+	}
+	var d net.Dialer
+	ctx, cancel := context.WithTimeout(r.Context(), portDialTimeout)
+	defer cancel()
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err == nil {
+		conn.Close()
+	}
+	writeJSON(w, map[string]any{"port": port, "open": err == nil})
+}
+
+func (h *handler) processes(w http.ResponseWriter, r *http.Request) {
+	var pids []int
+	for _, field := range strings.Split(r.URL.Query().Get("pids"), ",") {
+		pid, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || pid <= 0 {
+			h.fail(w, r, http.StatusBadRequest, errors.New("pids must be a comma-separated list of positive pids"))
+			return
+		}
+		pids = append(pids, pid)
+	}
+	stats, err := h.config.Monitor.ProcessStats(r.Context(), pids)
+	if errors.Is(err, errors.ErrUnsupported) {
+		h.fail(w, r, http.StatusNotImplemented, err)
+		return
+	}
+	if err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, stats)
+}
+
+func (h *handler) crashLogs(w http.ResponseWriter, r *http.Request) {
+	dir := r.URL.Query().Get("dir")
+	if dir == "" {
+		h.fail(w, r, http.StatusBadRequest, errors.New("dir is required"))
+		return
+	}
+	logs, err := h.config.Crashes.CollectCrashLogs(r.Context(), dir)
+	if err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, logs)
+}
+
+func (h *handler) screenshot(w http.ResponseWriter, r *http.Request) {
+	png, err := h.config.Screenshot(r.Context())
+	if errors.Is(err, errors.ErrUnsupported) {
+		h.fail(w, r, http.StatusNotImplemented, err)
+		return
+	}
+	if err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(png)
+}
+
+// tunnel upgrades the request to a raw TCP relay to a loopback port, so
+// clients can reach browsers that only listen on 127.0.0.1.
+func (h *handler) tunnel(w http.ResponseWriter, r *http.Request) {
+	port, err := strconv.Atoi(r.URL.Query().Get("port"))
+	if err != nil || port <= 0 || port > 65535 {
+		h.fail(w, r, http.StatusBadRequest, errors.New("port must be between 1 and 65535"))
+		return
+	}
+	var d net.Dialer
+	ctx, cancel := context.WithTimeout(r.Context(), portDialTimeout)
+	defer cancel()
+	upstream, err := d.DialContext(ctx, "tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		h.fail(w, r, http.StatusBadGateway, err)
+		return
+	}
+	defer upstream.Close()
+
+	conn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		h.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	defer conn.Close()
+	brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+	if err := brw.Flush(); err != nil {
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	go func() { io.Copy(upstream, brw.Reader); done <- struct{}{} }()
+	go func() { io.Copy(conn, upstream); done <- struct{}{} }()
+	<-done
+}
+
+// fail writes an error response and logs it.
+func (h *handler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.config.Logger != nil {
+		h.config.Logger.WarnContext(r.Context(), "antidetect-agent: request failed",
+			"method", r.Method, "path", r.URL.Path, "status", status, "error", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, fmt.Sprintf("encode: %v", err), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,375 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+const testToken = "secret"
+
+// newTestAgent starts an agent with the given config and returns an
+// AgentClient for it.
+func newTestAgent(t *testing.T, config Config) *bitbrowser.AgentClient {
+	t.Helper()
+	config.Token = testToken
+	config.DiskPath = t.TempDir()
+	handler, err := NewHandler(config)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return bitbrowser.NewAgentClient(server.URL, testToken)
+}
+
+func TestNewHandler(t *testing.T) {
+	if _, err := NewHandler(Config{}); !errors.Is(err, bitbrowser.ErrValidation) {
+		t.Errorf("err = %v, want ErrValidation", err)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	handler, err := NewHandler(Config{Token: testToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, header := range []string{"", "Bearer wrong", testToken} {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status = %d, want 401", header, rec.Code)
+		}
+	}
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	client := bitbrowser.NewAgentClient(server.URL, "wrong")
+	_, err = client.PortOpen(context.Background(), 80)
+	var apiErr *bitbrowser.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "unauthorized" {
+		t.Errorf("err = %v, want 401 APIError", err)
+	}
+}
+
+func TestKill(t *testing.T) {
+	var killed int
+	client := newTestAgent(t, Config{Killer: bitbrowser.ProcessKillerFunc(func(ctx context.Context, pid int) error {
+		if pid == 13 {
+			return errors.New("access denied")
+		}
+		killed = pid
+		return nil
+	})})
+
+	if err := client.Kill(context.Background(), 4242); err != nil {
+		t.Fatalf("Kill() error = %v", err)
+	}
+	if killed != 4242 {
+		t.Errorf("killed = %d, want 4242", killed)
+	}
+
+	err := client.Kill(context.Background(), 13)
+	var apiErr *bitbrowser.APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "access denied" {
+		t.Errorf("err = %v, want APIError with the killer's message", err)
+	}
+
+	if err := client.Kill(context.Background(), 0); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("err = %v, want 400 APIError", err)
+	}
+}
+
+func TestDiskUsage(t *testing.T) {
+	client := newTestAgent(t, Config{})
+	usage, err := client.DiskUsage(context.Background(), "")
+	if err != nil {
+		t.Fatalf("DiskUsage() error = %v", err)
+	}
+	if usage.Total == 0 || usage.Free > usage.Total || usage.Used > usage.Total {
+		t.Errorf("usage = %+v, want plausible numbers", usage)
+	}
+}
+
+func TestPortOpen(t *testing.T) {
+	client := newTestAgent(t, Config{})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	open, err := client.PortOpen(context.Background(), port)
+	if err != nil || !open {
+		t.Errorf("PortOpen(listening) = %v, %v; want true", open, err)
+	}
+	ln.Close()
+	open, err = client.PortOpen(context.Background(), port)
+	if err != nil || open {
+		t.Errorf("PortOpen(closed) = %v, %v; want false", open, err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	client := newTestAgent(t, Config{})
+	m, err := client.Metrics(context.Background())
+	if runtime.GOOS != "linux" {
+		var apiErr *bitbrowser.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented {
+			t.Errorf("err = %v, want 501 APIError", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Metrics() error = %v", err)
+	}
+	if m.CPU < 0 || m.CPU > 100 || m.Memory <= 0 || m.Memory > 100 {
+		t.Errorf("metrics = %+v, want percentages", m)
+	}
+}
+
+func TestScreenshot(t *testing.T) {
+	png := []byte("\x89PNG fake")
+	client := newTestAgent(t, Config{Screenshot: func(context.Context) ([]byte, error) {
+		return png, nil
+	}})
+	got, err := client.Screenshot(context.Background())
+	if err != nil || string(got) != string(png) {
+		t.Errorf("Screenshot() = %q, %v", got, err)
+	}
+}
+
+func TestClipboard(t *testing.T) {
+	var got []string
+	client := newTestAgent(t, Config{Clipboard: bitbrowser.ClipboardWriterFunc(func(ctx context.Context, text string) error {
+		if text == "fail" {
+			return errors.New("no display")
+		}
+		got = append(got, text)
+		return nil
+	})})
+
+	for _, text := range []string{"héllo\nworld", ""} {
+		if err := client.SetClipboard(context.Background(), text); err != nil {
+			t.Fatalf("SetClipboard(%q) error = %v", text, err)
+		}
+	}
+	if len(got) != 2 || got[0] != "héllo\nworld" || got[1] != "" {
+		t.Errorf("clipboard writes = %q", got)
+	}
+
+	err := client.SetClipboard(context.Background(), "fail")
+	var apiErr *bitbrowser.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError || !strings.Contains(err.Error(), "no display") {
+		t.Errorf("err = %v, want a 500 APIError carrying the agent's message", err)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	handler, _ := NewHandler(Config{Token: testToken})
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var body map[string]string
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusOK || body["status"] != "ok" {
+		t.Errorf("status = %d, body = %v", rec.Code, body)
+	}
+	if !strings.Contains(rec.Header().Get("Content-Type"), "json") {
+		t.Errorf("Content-Type = %q", rec.Header().Get("Content-Type"))
+	}
+}
+
+// recordingApp is an AppController that records calls.
+type recordingApp struct{ calls []string }
+
+func (a *recordingApp) StartApp(context.Context) error {
+	a.calls = append(a.calls, "start")
+	return nil
+}
+
+func (a *recordingApp) StopApp(context.Context) error {
+	a.calls = append(a.calls, "stop")
+	return nil
+}
+
+func TestApp(t *testing.T) {
+	app := &recordingApp{}
+	client := newTestAgent(t, Config{App: app})
+	if err := client.StopApp(context.Background()); err != nil {
+		t.Fatalf("StopApp() error = %v", err)
+	}
+	if err := client.StartApp(context.Background()); err != nil {
+		t.Fatalf("StartApp() error = %v", err)
+	}
+	if strings.Join(app.calls, ",") != "stop,start" {
+		t.Errorf("calls = %v, want [stop start]", app.calls)
+	}
+
+	err := newTestAgent(t, Config{}).StartApp(context.Background())
+	var apiErr *bitbrowser.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented {
+		t.Errorf("err = %v, want 501 APIError without Config.App", err)
+	}
+}
+
+func TestTunnel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	client := newTestAgent(t, Config{})
+	conn, err := client.DialRemote(context.Background(), "ignored", ln.Addr().(*net.TCPAddr).Port)
+	if err != nil {
+		t.Fatalf("DialRemote() error = %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "hello" {
+		t.Errorf("Read() = %q, %v; want hello", buf, err)
+	}
+
+	_, err = client.DialRemote(context.Background(), "ignored", 1)
+	var apiErr *bitbrowser.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("closed port: err = %v, want 502 APIError", err)
+	}
+}
+
+func TestProcessStats(t *testing.T) {
+	client := newTestAgent(t, Config{Monitor: bitbrowser.ProcessMonitorFunc(
+		func(_ context.Context, pids []int) (map[int]bitbrowser.ProcessStats, error) {
+			stats := make(map[int]bitbrowser.ProcessStats)
+			for _, pid := range pids {
+				stats[pid] = bitbrowser.ProcessStats{PID: pid, Processes: 3, RSS: 1 << 20}
+			}
+			return stats, nil
+		})})
+
+	stats, err := client.ProcessStats(context.Background(), []int{101, 102})
+	if err != nil {
+		t.Fatalf("ProcessStats() error = %v", err)
+	}
+	if len(stats) != 2 || stats[102].PID != 102 || stats[102].RSS != 1<<20 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	if _, err := client.ProcessStats(context.Background(), nil); err == nil {
+		t.Error("ProcessStats(no pids) succeeded, want 400")
+	}
+}
+
+func TestProcessStatsUnsupported(t *testing.T) {
+	client := newTestAgent(t, Config{Monitor: bitbrowser.ProcessMonitorFunc(
+		func(context.Context, []int) (map[int]bitbrowser.ProcessStats, error) {
+			return nil, errors.ErrUnsupported
+		})})
+	_, err := client.ProcessStats(context.Background(), []int{101})
+	var apiErr *bitbrowser.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented {
+		t.Errorf("err = %v, want 501 APIError", err)
+	}
+}
+
+func TestCollectCrashLogs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "chrome_debug.log"), []byte("renderer crashed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestAgent(t, Config{})
+
+	logs, err := client.CollectCrashLogs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("CollectCrashLogs() error = %v", err)
+	}
+	if string(logs.Log) != "renderer crashed" {
+		t.Errorf("log = %q", logs.Log)
+	}
+}
+
+func TestGzip(t *testing.T) {
+	large := strings.Repeat("renderer crashed\n", 4096)
+	handler, err := NewHandler(Config{
+		Token: testToken,
+		Crashes: bitbrowser.CrashCollectorFunc(func(ctx context.Context, dir string) (*bitbrowser.CrashLogs, error) {
+			return &bitbrowser.CrashLogs{Log: []byte(large)}, nil
+		}),
+		Screenshot: func(ctx context.Context) ([]byte, error) { return make([]byte, 4096), nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, accept string
+		wantGzip     bool
+		wantStatus   int
+	}{
+		{"/crashlogs?dir=x", "gzip", true, http.StatusOK},
+		{"/crashlogs?dir=x", "deflate, gzip;q=0.8", true, http.StatusOK},
+		{"/crashlogs?dir=x", "gzip;q=0", false, http.StatusOK},
+		{"/crashlogs?dir=x", "", false, http.StatusOK},
+		{"/health", "gzip", false, http.StatusOK},              // Too small
+		{"/screenshot", "gzip", false, http.StatusOK},          // Already compressed
+		{"/port?port=x", "gzip", false, http.StatusBadRequest}, // Status kept
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Authorization", "Bearer "+testToken)
+		if tt.accept != "" {
+			req.Header.Set("Accept-Encoding", tt.accept)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		gzipped := rec.Header().Get("Content-Encoding") == "gzip"
+		if gzipped != tt.wantGzip || rec.Code != tt.wantStatus {
+			t.Errorf("%s (Accept-Encoding %q): gzip = %v, status = %d, want %v, %d",
+				tt.path, tt.accept, gzipped, rec.Code, tt.wantGzip, tt.wantStatus)
+		}
+		if gzipped && rec.Body.Len() >= len(large)/10 {
+			t.Errorf("%s: compressed body is %d bytes", tt.path, rec.Body.Len())
+		}
+	}
+
+	// AgentClient decompresses transparently
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	logs, err := bitbrowser.NewAgentClient(server.URL, testToken).CollectCrashLogs(context.Background(), "x")
+	if err != nil || string(logs.Log) != large {
+		t.Errorf("CollectCrashLogs() = %d bytes, %v", len(logs.Log), err)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package agent
+
+import (
+	"syscall"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+// diskUsage reports the usage of the file system containing path.
+func diskUsage(path string) (*bitbrowser.DiskUsage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return nil, err
+	}
+	bsize := uint64(st.Bsize)
+	total := uint64(st.Blocks) * bsize
+	return &bitbrowser.DiskUsage{
+		Path:  path,
+		Total: total,
+		Free:  uint64(st.Bavail) * bsize,
+		Used:  total - uint64(st.Bfree)*bsize,
+	}, nil
+}
@@ -0,0 +1,29 @@
+package agent
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+var getDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// diskUsage reports the usage of the volume containing path.
+func diskUsage(path string) (*bitbrowser.DiskUsage, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	var free, total, totalFree uint64
+	ok, _, err := getDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(&free)),
+		uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&totalFree)),
+	)
+	if ok == 0 {
+		return nil, err
+	}
+	return &bitbrowser.DiskUsage{Path: path, Total: total, Free: free, Used: total - totalFree}, nil
+}
@@ -0,0 +1,39 @@
+// Package agent implements antidetect-agent, a small HTTP service that runs
+// on a BitBrowser machine and performs operations the BitBrowser API cannot:
+// killing processes, starting and stopping BitBrowser, reporting disk usage,
+// CPU/memory load and browser process usage, collecting Chrome crash logs,
+// capturing the desktop, setting the clipboard, checking ports and tunneling
+// to loopback-only ports.
+//
+// The SDK side is bitbrowser.AgentClient, which fleets use as a MetricsAgent
+// and clients as a ProcessKiller, ProcessMonitor, CrashCollector,
+// AppController, PortForwarder and ClipboardWriter. The binary is cmd/antidetect-agent.
+//
+// # API
+//
+// Every request must carry "Authorization: Bearer <token>". Errors are
+// returned as {"error": "..."} with a 4xx or 5xx status. JSON responses of
+// 1 KiB or more are gzip-compressed for clients sending
+// "Accept-Encoding: gzip", as Go's HTTP client does.
+//
+//	POST /kill        {"pid": 1234}         -> 204
+//	POST /app/start   launch BitBrowser     -> 204 (501 without Config.App)
+//	POST /app/stop    stop BitBrowser       -> 204 (501 without Config.App)
+//	POST /clipboard   {"text": "..."}       -> 204
+//	GET  /crashlogs?dir= Chrome log and dumps -> {"log": "<base64>", "dumps": [{"name": ..., "data": ...}]}
+//	GET  /disk?path=  DiskUsage             -> {"path": ..., "total": ..., "free": ..., "used": ...}
+//	GET  /metrics     HostMetrics           -> {"cpu": 12.5, "memory": 61.0}
+//	GET  /port?port=  loopback TCP check    -> {"port": 9222, "open": true}
+//	GET  /processes?pids=1,2 process trees  -> {"1": {"pid": 1, "processes": 9, "rss": ..., "cpu": ...}}
+//	GET  /screenshot  desktop capture       -> image/png
+//	GET  /tunnel?port= loopback TCP relay   -> 101, then raw bytes ("Upgrade: tcp")
+//	GET  /health                            -> {"status": "ok"}
+//
+// # Usage
+//
+//	handler, err := agent.NewHandler(agent.Config{Token: os.Getenv("ANTIDETECT_AGENT_TOKEN")})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	log.Fatal(http.ListenAndServe(":54350", handler))
+package agent
@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// gzipMinSize is the smallest response body worth compressing.
+const gzipMinSize = 1 << 10
+
+// gzipWriters holds *gzip.Writer for compressing responses.
+var gzipWriters = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+
+// acceptsGzip reports whether the client of r accepts gzip-encoded
+// responses. Upgrade requests are never compressed.
+func acceptsGzip(r *http.Request) bool {
+	if r.Header.Get("Upgrade") != "" {
+		return false
+	}
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(enc), ";")
+		if strings.EqualFold(strings.TrimSpace(name), "gzip") && strings.ReplaceAll(params, " ", "") != "q=0" {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter compresses JSON and text responses of at least
+// gzipMinSize bytes. Whether to compress is decided on the first write,
+// which holds the whole body of a JSON response, so the status code is
+// held back until then.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	started bool
+	zw      *gzip.Writer
+}
+
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if g.status == 0 {
+		g.status = status
+	}
+}
+
+func (g *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !g.started {
+		g.start(len(p))
+	}
+	if g.zw != nil {
+		return g.zw.Write(p)
+	}
+	return g.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// start writes the header, compressed if the first write is n bytes.
+func (g *gzipResponseWriter) start(n int) {
+	g.started = true
+	h := g.Header()
+	h.Add("Vary", "Accept-Encoding")
+	if n >= gzipMinSize && h.Get("Content-Encoding") == "" && compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		g.zw = gzipWriters.Get().(*gzip.Writer)
+		g.zw.Reset(g.ResponseWriter)
+	}
+	if g.status != 0 {
+		g.ResponseWriter.WriteHeader(g.status)
+	}
+}
+
+// close finishes the response.
+func (g *gzipResponseWriter) close() {
+	if !g.started {
+		g.start(0)
+		return
+	}
+	if g.zw != nil {
+		g.zw.Close()
+		gzipWriters.Put(g.zw)
+		g.zw = nil
+	}
+}
+
+// compressible reports whether a response of the content type is worth
+// compressing. Images such as screenshots already are.
+func compressible(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, "text/")
+}
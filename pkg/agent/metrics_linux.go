@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+// cpuSampleInterval is how long CPU usage is sampled for.
+const cpuSampleInterval = 200 * time.Millisecond
+
+// hostMetrics reports CPU usage, sampled from /proc/stat, and memory usage
+// from /proc/meminfo, both in percent.
+func hostMetrics(ctx context.Context) (bitbrowser.HostMetrics, error) {
+	idle1, total1, err := cpuTimes()
+	if err != nil {
+		return bitbrowser.HostMetrics{}, err
+	}
+	select {
+	case <-ctx.Done():
+		return bitbrowser.HostMetrics{}, ctx.Err()
+	case <-time.After(cpuSampleInterval):
+	}
+	idle2, total2, err := cpuTimes()
+	if err != nil {
+		return bitbrowser.HostMetrics{}, err
+	}
+
+	var m bitbrowser.HostMetrics
+	if total2 > total1 {
+		m.CPU = 100 * (1 - float64(idle2-idle1)/float64(total2-total1))
+	}
+	m.Memory, err = memoryUsage()
+	return m, err
+}
+
+// cpuTimes returns the idle and total jiffies from the "cpu" line of /proc/stat.
+func cpuTimes() (idle, total uint64, err error) {
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return 0, 0, err
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return 0, 0, fmt.Errorf("unexpected /proc/stat line %q", line)
+	}
+	for i, f := range fields[1:] {
+		v, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		total += v
+		if i == 3 || i == 4 { // idle, iowait
+			idle += v
+		}
+	}
+	return idle, total, nil
+}
+
+// memoryUsage returns the share of memory not available, in percent.
+func memoryUsage() (float64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	values := make(map[string]float64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		if v, err := strconv.ParseFloat(fields[0], 64); err == nil {
+			values[key] = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	total, available := values["MemTotal"], values["MemAvailable"]
+	if total == 0 {
+		return 0, fmt.Errorf("MemTotal missing from /proc/meminfo")
+	}
+	return 100 * (1 - available/total), nil
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+// hostMetrics is only implemented on Linux.
+func hostMetrics(context.Context) (bitbrowser.HostMetrics, error) {
+	return bitbrowser.HostMetrics{}, fmt.Errorf("host metrics on %s: %w", runtime.GOOS, errors.ErrUnsupported)
+}
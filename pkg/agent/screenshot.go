@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// windowsScreenshot is the PowerShell script that saves the virtual screen to
+// the file named by $args[0].
+const windowsScreenshot = `Add-Type -AssemblyName System.Windows.Forms,System.Drawing
+$b = [System.Windows.Forms.SystemInformation]::VirtualScreen
+$bmp = New-Object System.Drawing.Bitmap $b.Width, $b.Height
+$g = [System.Drawing.Graphics]::FromImage($bmp)
+$g.CopyFromScreen($b.Left, $b.Top, 0, 0, $bmp.Size)
+$bmp.Save($args[0], [System.Drawing.Imaging.ImageFormat]::Png)`
+
+// CaptureDesktop captures the whole desktop as PNG with the platform's tool:
+// PowerShell on Windows, screencapture on macOS and ImageMagick's import on
+// Linux (which needs DISPLAY). The agent must run in the desktop session.
+func CaptureDesktop(ctx context.Context) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "antidetect-agent-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "desktop.png")
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", windowsScreenshot, file)
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "screencapture", "-x", "-t", "png", file)
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.CommandContext(ctx, "import", "-window", "root", file)
+	default:
+		return nil, fmt.Errorf("desktop capture on %s: %w", runtime.GOOS, errors.ErrUnsupported)
+	}
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", cmd.Args[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	return os.ReadFile(file)
+}
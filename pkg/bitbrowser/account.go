@@ -0,0 +1,368 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// AccountStatus is the state of a platform account bound to a profile.
+type AccountStatus string
+
+// Account statuses.
+const (
+	AccountActive    AccountStatus = "active"
+	AccountPending   AccountStatus = "pending" // Registered, not yet verified or warmed up
+	AccountSuspended AccountStatus = "suspended"
+	AccountBanned    AccountStatus = "banned"
+)
+
+// Account is the platform account a profile is bound to, kept as typed
+// metadata instead of being encoded in profile names.
+type Account struct {
+	Platform string        `json:"platform"` // Platform URL as BitBrowser uses it, e.g. "https://www.facebook.com"
+	Handle   string        `json:"handle"`   // Username or account handle on the platform
+	Email    string        `json:"email,omitempty"`
+	Phone    string        `json:"phone,omitempty"`
+	Status   AccountStatus `json:"status,omitempty"`
+}
+
+// ApplyTo sets the platform account fields of a profile configuration and
+// enables IsValidUsername, so BitBrowser also rejects creating a second
+// profile for the same platform account.
+func (a Account) ApplyTo(config *ProfileConfig) {
+	config.Platform = a.Platform
+	config.UserName = a.Handle
+	config.IsValidUsername = true
+}
+
+// AccountBinding is an account together with the profile it is bound to.
+type AccountBinding struct {
+	ProfileID string  `json:"profileId"`
+	Account   Account `json:"account"`
+}
+
+// AccountStore persists account metadata by profile ID.
+// RemarkAccountStore keeps it in the profile remarks; FileAccountStore keeps
+// it in a sidecar JSON file.
+type AccountStore interface {
+	// LoadAccounts returns all stored accounts keyed by profile ID.
+	LoadAccounts(ctx context.Context) (map[string]Account, error)
+
+	// SaveAccount stores the account of a profile, or removes it if
+	// account is nil.
+	SaveAccount(ctx context.Context, id string, account *Account) error
+}
+
+// accountRemarkPrefix starts the remark line that holds account metadata.
+const accountRemarkPrefix = "#account:"
+
+// EncodeAccountRemark returns remark with account stored in its last line,
+// replacing any account stored before. A nil account removes it.
+func EncodeAccountRemark(remark string, account *Account) string {
+	_, rest, _ := DecodeAccountRemark(remark)
+	if account == nil {
+		return rest
+	}
+	data, _ := json.Marshal(account)
+	if rest == "" {
+		return accountRemarkPrefix + string(data)
+	}
+	return rest + "\n" + accountRemarkPrefix + string(data)
+}
+
+// DecodeAccountRemark extracts the account stored by EncodeAccountRemark
+// and returns it with the rest of the remark. It reports false if the remark
+// holds no valid account.
+func DecodeAccountRemark(remark string) (Account, string, bool) {
+	rest, line := "", remark
+	if i := strings.LastIndexByte(remark, '\n'); i >= 0 {
+		rest, line = remark[:i], remark[i+1:]
+	}
+	data, ok := strings.CutPrefix(line, accountRemarkPrefix)
+	if !ok {
+		return Account{}, remark, false
+	}
+	var account Account
+	if err := json.Unmarshal([]byte(data), &account); err != nil {
+		return Account{}, remark, false
+	}
+	return account, rest, true
+}
+
+// RemarkAccountStore stores accounts in the remarks of the profiles, after
+// any text already there, so the metadata lives in BitBrowser itself.
+type RemarkAccountStore struct {
+	client *Client
+}
+
+// NewRemarkAccountStore creates an AccountStore backed by profile remarks.
+func NewRemarkAccountStore(client *Client) *RemarkAccountStore {
+	return &RemarkAccountStore{client: client}
+}
+
+// LoadAccounts lists all profiles and decodes their remarks.
+func (s *RemarkAccountStore) LoadAccounts(ctx context.Context) (map[string]Account, error) {
+	accounts := make(map[string]Account)
+	seen := 0
+	for page := 0; ; page++ {
+		result, err := s.client.ListProfiles(ctx, ListRequest{Page: page, PageSize: maxBatchSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range result.List {
+			if account, _, ok := DecodeAccountRemark(p.Remark); ok {
+				accounts[p.ID] = account
+			}
+		}
+		seen += len(result.List)
+		if len(result.List) == 0 || seen >= result.Total {
+			return accounts, nil
+		}
+	}
+}
+
+// SaveAccount rewrites the account line of the profile's remark.
+func (s *RemarkAccountStore) SaveAccount(ctx context.Context, id string, account *Account) error {
+	detail, err := s.client.GetProfileDetail(ctx, id)
+	if err != nil {
+		return err
+	}
+	remark := EncodeAccountRemark(detail.Remark, account)
+	if remark == detail.Remark {
+		return nil
+	}
+	return s.client.UpdateRemark(ctx, remark, []string{id})
+}
+
+// FileAccountStore stores accounts in a JSON file next to the application,
+// leaving profile remarks untouched. It is safe for concurrent use within
+// one process.
+type FileAccountStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileAccountStore creates an AccountStore backed by the JSON file at
+// path. The file is created on the first save.
+func NewFileAccountStore(path string) *FileAccountStore {
+	return &FileAccountStore{path: path}
+}
+
+// LoadAccounts reads the file. A missing file holds no accounts.
+func (s *FileAccountStore) LoadAccounts(ctx context.Context) (map[string]Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// SaveAccount updates the file, replacing it atomically.
+func (s *FileAccountStore) SaveAccount(ctx context.Context, id string, account *Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	accounts, err := s.load()
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		delete(accounts, id)
+	} else {
+		accounts[id] = *account
+	}
+
+	data, err := json.MarshalIndent(accounts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("bitbrowser: save accounts: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces the file at path with data, so that readers
+// never see a partly written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// load reads the file; the caller holds mu.
+func (s *FileAccountStore) load() (map[string]Account, error) {
+	accounts := make(map[string]Account)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return accounts, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: load accounts: %w", err)
+	}
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		return nil, fmt.Errorf("bitbrowser: load accounts from %s: %w", s.path, err)
+	}
+	return accounts, nil
+}
+
+// AccountQuery selects accounts in AccountRegistry.Find. Empty fields match
+// anything; the others must match exactly, ignoring case.
+type AccountQuery struct {
+	Platform string
+	Handle   string
+	Email    string
+	Phone    string
+	Status   AccountStatus
+}
+
+// matches reports whether a satisfies q.
+func (q AccountQuery) matches(a Account) bool {
+	field := func(want, got string) bool { return want == "" || strings.EqualFold(want, got) }
+	return field(q.Platform, a.Platform) && field(q.Handle, a.Handle) &&
+		field(q.Email, a.Email) && field(q.Phone, a.Phone) && field(string(q.Status), string(a.Status))
+}
+
+// AccountRegistry binds platform accounts to profiles on top of an
+// AccountStore. It makes sure a platform account, email or phone number is
+// bound to at most one profile per platform.
+//
+// Example:
+//
+//	accounts := bitbrowser.NewAccountRegistry(bitbrowser.NewRemarkAccountStore(client))
+//	err := accounts.Bind(ctx, id, bitbrowser.Account{
+//	    Platform: "https://www.facebook.com", Handle: "jane.doe", Email: "jane@example.com",
+//	    Status: bitbrowser.AccountActive,
+//	})
+//	banned, err := accounts.Find(ctx, bitbrowser.AccountQuery{Status: bitbrowser.AccountBanned})
+type AccountRegistry struct {
+	store AccountStore
+	mu    sync.Mutex // Serializes Bind's check and save
+}
+
+// NewAccountRegistry creates an AccountRegistry backed by store.
+func NewAccountRegistry(store AccountStore) *AccountRegistry {
+	return &AccountRegistry{store: store}
+}
+
+// Bind binds account to a profile, replacing the profile's previous
+// account. Platform and Handle are required. It fails with ErrAccountBound
+// if another profile already has the same handle, email or phone on the
+// platform. Uniqueness is checked within this process; profiles created
+// with Account.ApplyTo are also checked by BitBrowser.
+func (r *AccountRegistry) Bind(ctx context.Context, id string, account Account) error {
+	if err := ValidateProfileID(id); err != nil {
+		return err
+	}
+	if account.Platform == "" {
+		return NewValidationError("platform", "platform is required")
+	}
+	if account.Handle == "" {
+		return NewValidationError("handle", "account handle is required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	accounts, err := r.store.LoadAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range slices.Sorted(maps.Keys(accounts)) {
+		if other == id {
+			continue
+		}
+		if field := conflict(account, accounts[other]); field != "" {
+			return fmt.Errorf("%w: %s %s is bound to profile %s", ErrAccountBound, field, accountField(account, field), other)
+		}
+	}
+	return r.store.SaveAccount(ctx, id, &account)
+}
+
+// conflict returns the field in which a and b identify the same account on
+// the same platform, or "" if they do not.
+func conflict(a, b Account) string {
+	if !strings.EqualFold(a.Platform, b.Platform) {
+		return ""
+	}
+	for _, field := range []string{"handle", "email", "phone"} {
+		if v := accountField(a, field); v != "" && strings.EqualFold(v, accountField(b, field)) {
+			return field
+		}
+	}
+	return ""
+}
+
+// accountField returns the value of an identifying field of a.
+func accountField(a Account, field string) string {
+	switch field {
+	case "handle":
+		return a.Handle
+	case "email":
+		return a.Email
+	default:
+		return a.Phone
+	}
+}
+
+// Unbind removes the account of a profile.
+func (r *AccountRegistry) Unbind(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.store.SaveAccount(ctx, id, nil)
+}
+
+// SetStatus changes the status of the account bound to a profile.
+func (r *AccountRegistry) SetStatus(ctx context.Context, id string, status AccountStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	accounts, err := r.store.LoadAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	account, ok := accounts[id]
+	if !ok {
+		return &ValidationError{Field: "id", Message: "profile has no bound account", Value: id}
+	}
+	account.Status = status
+	return r.store.SaveAccount(ctx, id, &account)
+}
+
+// Account returns the account bound to a profile, or false if none is.
+func (r *AccountRegistry) Account(ctx context.Context, id string) (Account, bool, error) {
+	accounts, err := r.store.LoadAccounts(ctx)
+	if err != nil {
+		return Account{}, false, err
+	}
+	account, ok := accounts[id]
+	return account, ok, nil
+}
+
+// Find returns the bindings matching q, ordered by profile ID.
+func (r *AccountRegistry) Find(ctx context.Context, q AccountQuery) ([]AccountBinding, error) {
+	accounts, err := r.store.LoadAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var bindings []AccountBinding
+	for _, id := range slices.Sorted(maps.Keys(accounts)) {
+		if q.matches(accounts[id]) {
+			bindings = append(bindings, AccountBinding{ProfileID: id, Account: accounts[id]})
+		}
+	}
+	return bindings, nil
+}
@@ -0,0 +1,151 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestAccountRemark(t *testing.T) {
+	account := &Account{Platform: "https://www.facebook.com", Handle: "jane.doe", Status: AccountActive}
+
+	remark := EncodeAccountRemark("warmed up in May", account)
+	got, rest, ok := DecodeAccountRemark(remark)
+	if !ok || got != *account || rest != "warmed up in May" {
+		t.Fatalf("DecodeAccountRemark(%q) = %+v, %q, %v", remark, got, rest, ok)
+	}
+
+	account.Status = AccountBanned
+	remark = EncodeAccountRemark(remark, account)
+	if got, _, _ := DecodeAccountRemark(remark); got.Status != AccountBanned {
+		t.Errorf("re-encoded status = %q, want banned", got.Status)
+	}
+	if remark = EncodeAccountRemark(remark, nil); remark != "warmed up in May" {
+		t.Errorf("remark after removal = %q", remark)
+	}
+
+	if _, rest, ok := DecodeAccountRemark("plain\nnotes"); ok || rest != "plain\nnotes" {
+		t.Errorf("plain remark decoded as account: %q, %v", rest, ok)
+	}
+	if got := EncodeAccountRemark("", account); got[0] != '#' {
+		t.Errorf("EncodeAccountRemark on empty remark = %q", got)
+	}
+}
+
+func TestAccountApplyTo(t *testing.T) {
+	var config ProfileConfig
+	Account{Platform: "https://x.com", Handle: "jdoe"}.ApplyTo(&config)
+	if config.Platform != "https://x.com" || config.UserName != "jdoe" || !config.IsValidUsername {
+		t.Errorf("ApplyTo() config = %+v", config)
+	}
+}
+
+func TestAccountRegistry(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "accounts.json")
+	accounts := NewAccountRegistry(NewFileAccountStore(path))
+
+	fb := "https://www.facebook.com"
+	if err := accounts.Bind(ctx, "p1", Account{Platform: fb, Handle: "jane", Email: "jane@example.com", Status: AccountActive}); err != nil {
+		t.Fatalf("Bind(p1) failed: %v", err)
+	}
+	if err := accounts.Bind(ctx, "p2", Account{Platform: "https://x.com", Handle: "jane", Status: AccountPending}); err != nil {
+		t.Fatalf("Bind(p2) on another platform failed: %v", err)
+	}
+	// Rebinding the same profile is not a conflict
+	if err := accounts.Bind(ctx, "p1", Account{Platform: fb, Handle: "jane", Email: "jane@example.com", Phone: "+15550100"}); err != nil {
+		t.Fatalf("rebinding p1 failed: %v", err)
+	}
+
+	for name, account := range map[string]Account{
+		"handle": {Platform: fb, Handle: "JANE"},
+		"email":  {Platform: fb, Handle: "jane2", Email: "Jane@Example.com"},
+		"phone":  {Platform: fb, Handle: "jane3", Phone: "+15550100"},
+	} {
+		err := accounts.Bind(ctx, "p3", account)
+		if !errors.Is(err, ErrAccountBound) {
+			t.Errorf("Bind with duplicate %s = %v, want ErrAccountBound", name, err)
+		}
+	}
+	if err := accounts.Bind(ctx, "p3", Account{Platform: fb}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Bind without handle = %v, want ErrValidation", err)
+	}
+
+	if err := accounts.SetStatus(ctx, "p2", AccountBanned); err != nil {
+		t.Fatalf("SetStatus failed: %v", err)
+	}
+	if err := accounts.SetStatus(ctx, "p9", AccountBanned); !errors.Is(err, ErrValidation) {
+		t.Errorf("SetStatus on unbound profile = %v, want ErrValidation", err)
+	}
+
+	// A new registry on the same file sees the bindings
+	accounts = NewAccountRegistry(NewFileAccountStore(path))
+	banned, err := accounts.Find(ctx, AccountQuery{Status: AccountBanned})
+	if err != nil || len(banned) != 1 || banned[0].ProfileID != "p2" {
+		t.Errorf("Find(banned) = %+v, %v", banned, err)
+	}
+	janes, _ := accounts.Find(ctx, AccountQuery{Handle: "Jane"})
+	if len(janes) != 2 || janes[0].ProfileID != "p1" || janes[1].ProfileID != "p2" {
+		t.Errorf("Find(handle) = %+v", janes)
+	}
+
+	if err := accounts.Unbind(ctx, "p1"); err != nil {
+		t.Fatalf("Unbind failed: %v", err)
+	}
+	if _, ok, err := accounts.Account(ctx, "p1"); ok || err != nil {
+		t.Errorf("Account(p1) after Unbind = %v, %v", ok, err)
+	}
+	if a, ok, _ := accounts.Account(ctx, "p2"); !ok || a.Handle != "jane" {
+		t.Errorf("Account(p2) = %+v, %v", a, ok)
+	}
+}
+
+func TestRemarkAccountStore(t *testing.T) {
+	remarks := map[string]string{
+		"p1": "notes",
+		"p2": EncodeAccountRemark("", &Account{Platform: "https://x.com", Handle: "jdoe"}),
+	}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		switch r.URL.Path {
+		case "/browser/list":
+			var list []map[string]any
+			if req["page"] == 0.0 {
+				list = []map[string]any{{"id": "p1", "remark": remarks["p1"]}, {"id": "p2", "remark": remarks["p2"]}}
+			}
+			w.Write(successResponse(map[string]any{"list": list, "totalNum": 2}))
+		case "/browser/detail":
+			id := req["id"].(string)
+			w.Write(successResponse(map[string]any{"id": id, "remark": remarks[id]}))
+		case "/browser/remark/update":
+			for _, id := range req["browserIds"].([]any) {
+				remarks[id.(string)] = req["remark"].(string)
+			}
+			w.Write(successResponse(nil))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	accounts := NewAccountRegistry(NewRemarkAccountStore(mustNew(t, server.URL)))
+	if err := accounts.Bind(ctx, "p1", Account{Platform: "https://x.com", Handle: "jdoe"}); !errors.Is(err, ErrAccountBound) {
+		t.Errorf("Bind duplicate = %v, want ErrAccountBound", err)
+	}
+	if err := accounts.Bind(ctx, "p1", Account{Platform: "https://x.com", Handle: "other"}); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+	account, rest, ok := DecodeAccountRemark(remarks["p1"])
+	if !ok || account.Handle != "other" || rest != "notes" {
+		t.Errorf("p1 remark = %q", remarks["p1"])
+	}
+	found, err := accounts.Find(ctx, AccountQuery{Platform: "https://x.com"})
+	if err != nil || len(found) != 2 {
+		t.Errorf("Find() = %+v, %v", found, err)
+	}
+}
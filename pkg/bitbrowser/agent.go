@@ -0,0 +1,309 @@
+package bitbrowser
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiskUsage is the disk usage of a file system on an agent host, in bytes.
+type DiskUsage struct {
+	Path  string `json:"path"`
+	Total uint64 `json:"total"`
+	Free  uint64 `json:"free"` // Available to unprivileged users
+	Used  uint64 `json:"used"`
+}
+
+// AgentClient talks to an antidetect-agent (cmd/antidetect-agent) running on
+// a BitBrowser host. It kills processes, reports disk usage, host metrics
+// and browser process usage, collects crash logs, captures the desktop,
+// sets the clipboard and checks ports on that machine.
+//
+// An AgentClient is a ProcessKiller, a ProcessMonitor, a CrashCollector, a
+// MetricsAgent, a PortForwarder, a ClipboardWriter and, if the agent has app
+// commands configured, an AppController:
+//
+//	agent := bitbrowser.NewAgentClient("http://10.0.0.5:54350", token)
+//	client, err := bitbrowser.New("http://10.0.0.5:54345",
+//	    bitbrowser.WithProcessKiller(agent), bitbrowser.WithAppController(agent))
+//	fleet, err := bitbrowser.NewFleetClient(
+//	    bitbrowser.WithFleetHost("node-a", client, bitbrowser.WithMetricsAgent(agent)),
+//	)
+type AgentClient struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+// AgentOption configures an AgentClient.
+type AgentOption func(*AgentClient)
+
+// WithAgentHTTPClient sets the HTTP client used to reach the agent, e.g. one
+// with TLS settings for an agent serving HTTPS.
+func WithAgentHTTPClient(httpClient *http.Client) AgentOption {
+	return func(a *AgentClient) {
+		if httpClient != nil {
+			a.httpClient = httpClient
+		}
+	}
+}
+
+// NewAgentClient creates a client for the agent at baseURL, authenticating
+// with token.
+func NewAgentClient(baseURL, token string, opts ...AgentOption) *AgentClient {
+	a := &AgentClient{
+		url:        strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Kill force-kills a process and its children on the agent host.
+// It implements ProcessKiller.
+func (a *AgentClient) Kill(ctx context.Context, pid int) error {
+	body, _ := json.Marshal(map[string]int{"pid": pid})
+	resp, err := a.do(ctx, http.MethodPost, "/kill", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// StartApp launches BitBrowser on the agent host. It implements AppController.
+func (a *AgentClient) StartApp(ctx context.Context) error {
+	return a.post(ctx, "/app/start")
+}
+
+// StopApp stops BitBrowser on the agent host. It implements AppController.
+func (a *AgentClient) StopApp(ctx context.Context) error {
+	return a.post(ctx, "/app/stop")
+}
+
+// SetClipboard sets the clipboard of the agent host's desktop session. It
+// implements ClipboardWriter.
+func (a *AgentClient) SetClipboard(ctx context.Context, text string) error {
+	body, _ := json.Marshal(map[string]string{"text": text})
+	resp, err := a.do(ctx, http.MethodPost, "/clipboard", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// post sends a POST without a body to path.
+func (a *AgentClient) post(ctx context.Context, path string) error {
+	resp, err := a.do(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// DiskUsage reports the usage of the file system containing path on the
+// agent host. An empty path means the agent's default (its working
+// directory's volume).
+func (a *AgentClient) DiskUsage(ctx context.Context, path string) (*DiskUsage, error) {
+	var usage DiskUsage
+	if err := a.getJSON(ctx, "/disk?path="+url.QueryEscape(path), &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// Metrics reports the agent host's CPU and memory usage. It implements
+// MetricsAgent.
+func (a *AgentClient) Metrics(ctx context.Context) (HostMetrics, error) {
+	var m HostMetrics
+	err := a.getJSON(ctx, "/metrics", &m)
+	return m, err
+}
+
+// ProcessStats reports the usage of process trees on the agent host. It
+// implements ProcessMonitor.
+func (a *AgentClient) ProcessStats(ctx context.Context, pids []int) (map[int]ProcessStats, error) {
+	list := make([]string, len(pids))
+	for i, pid := range pids {
+		list[i] = strconv.Itoa(pid)
+	}
+	var stats map[int]ProcessStats
+	if err := a.getJSON(ctx, "/processes?pids="+strings.Join(list, ","), &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// CollectCrashLogs reads the Chrome log and crash dumps in dir on the agent
+// host. It implements CrashCollector.
+func (a *AgentClient) CollectCrashLogs(ctx context.Context, dir string) (*CrashLogs, error) {
+	var logs CrashLogs
+	if err := a.getJSON(ctx, "/crashlogs?dir="+url.QueryEscape(dir), &logs); err != nil {
+		return nil, err
+	}
+	return &logs, nil
+}
+
+// PortOpen reports whether something accepts TCP connections on port on the
+// agent host's loopback interface.
+func (a *AgentClient) PortOpen(ctx context.Context, port int) (bool, error) {
+	var result struct {
+		Open bool `json:"open"`
+	}
+	if err := a.getJSON(ctx, "/port?port="+strconv.Itoa(port), &result); err != nil {
+		return false, err
+	}
+	return result.Open, nil
+}
+
+// Screenshot captures the agent host's desktop as a PNG image.
+func (a *AgentClient) Screenshot(ctx context.Context) ([]byte, error) {
+	resp, err := a.do(ctx, http.MethodGet, "/screenshot", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// DialRemote opens a tunnel through the agent to 127.0.0.1:port on the
+// agent's machine. host is ignored: an agent only reaches its own host. It
+// implements PortForwarder.
+func (a *AgentClient) DialRemote(ctx context.Context, _ string, port int) (net.Conn, error) {
+	u, err := url.Parse(a.url)
+	if err != nil {
+		return nil, NewValidationError("baseURL", err.Error())
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, NewNetworkError("agent", a.url, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if u.Scheme == "https" {
+		config := &tls.Config{}
+		if t, ok := a.httpClient.Transport.(*http.Transport); ok && t.TLSClientConfig != nil {
+			config = t.TLSClientConfig.Clone()
+		}
+		if config.ServerName == "" {
+			config.ServerName = u.Hostname()
+		}
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, NewNetworkError("agent", a.url, err)
+		}
+		conn = tlsConn
+	}
+
+	endpoint := a.url + "/tunnel?port=" + strconv.Itoa(port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "tcp")
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, NewNetworkError("agent", endpoint, err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, NewNetworkError("agent", endpoint, err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer conn.Close()
+		return nil, agentError(endpoint, resp)
+	}
+	conn.SetDeadline(time.Time{})
+	return &bufferedConn{Conn: conn, r: br}, nil
+}
+
+// bufferedConn is a net.Conn whose reads first drain a bufio.Reader that
+// may hold bytes read past a handshake.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) { return c.r.Read(b) }
+
+// getJSON GETs path and decodes the JSON response into v.
+func (a *AgentClient) getJSON(ctx context.Context, path string, v any) error {
+	resp, err := a.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("bitbrowser: failed to parse agent response: %w", err)
+	}
+	return nil
+}
+
+// do sends an authenticated request to the agent. Responses other than 2xx
+// are returned as *APIError carrying the agent's error message.
+func (a *AgentClient) do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	endpoint := a.url + path
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, NewNetworkError("agent", endpoint, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, agentError(endpoint, resp)
+	}
+	return resp, nil
+}
+
+// agentError converts a failed agent response into an *APIError carrying
+// the agent's error message.
+func agentError(endpoint string, resp *http.Response) error {
+	var e struct {
+		Error string `json:"error"`
+	}
+	msg := resp.Status
+	if json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&e) == nil && e.Error != "" {
+		msg = e.Error
+	}
+	return NewAPIError(endpoint, resp.StatusCode, msg)
+}
@@ -0,0 +1,67 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestAgentClient(t *testing.T) {
+	t.Run("sends the token and decodes responses", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+				t.Errorf("Authorization = %q", got)
+			}
+			switch r.URL.Path {
+			case "/kill":
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != `{"pid":42}` {
+					t.Errorf("kill body = %s", body)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			case "/disk":
+				if r.URL.Query().Get("path") != `C:\` {
+					t.Errorf("path = %q", r.URL.Query().Get("path"))
+				}
+				w.Write([]byte(`{"path":"C:\\","total":100,"free":40,"used":60}`))
+			case "/metrics":
+				w.Write([]byte(`{"cpu":12.5,"memory":50}`))
+			}
+		})
+		defer server.Close()
+
+		agent := NewAgentClient(server.URL+"/", "secret")
+		if err := agent.Kill(context.Background(), 42); err != nil {
+			t.Errorf("Kill() error = %v", err)
+		}
+		usage, err := agent.DiskUsage(context.Background(), `C:\`)
+		if err != nil || usage.Total != 100 || usage.Free != 40 {
+			t.Errorf("DiskUsage() = %+v, %v", usage, err)
+		}
+		m, err := agent.Metrics(context.Background())
+		if err != nil || m.CPU != 12.5 {
+			t.Errorf("Metrics() = %+v, %v", m, err)
+		}
+	})
+
+	t.Run("errors carry the agent message", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"access denied"}`))
+		})
+		defer server.Close()
+
+		err := NewAgentClient(server.URL, "secret").Kill(context.Background(), 42)
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Message != "access denied" || apiErr.StatusCode != 500 {
+			t.Errorf("err = %v, want APIError", err)
+		}
+	})
+}
+
+var (
+	_ ProcessKiller = (*AgentClient)(nil)
+	_ MetricsAgent  = (*AgentClient)(nil)
+)
@@ -0,0 +1,130 @@
+package bitbrowser
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// appPollInterval is the delay between Health checks while the BitBrowser
+// application starts or stops.
+const appPollInterval = time.Second
+
+// AppController starts and stops the BitBrowser client application itself,
+// e.g. to recover from a hung instance. LocalAppController runs commands on
+// this machine; an AgentClient does it on a remote host.
+type AppController interface {
+	// StartApp launches BitBrowser. It need not wait for the API.
+	StartApp(ctx context.Context) error
+	// StopApp stops BitBrowser, forcibly if needed.
+	StopApp(ctx context.Context) error
+}
+
+// LocalAppController starts and stops BitBrowser with local commands. The
+// start command is launched detached and not waited for, so it may be the
+// application itself or a service manager:
+//
+//	// Windows, desktop session
+//	&bitbrowser.LocalAppController{
+//	    Start: []string{`C:\Program Files\BitBrowser\BitBrowser.exe`},
+//	    Stop:  []string{"taskkill", "/F", "/T", "/IM", "BitBrowser.exe"},
+//	}
+//
+//	// systemd unit
+//	&bitbrowser.LocalAppController{
+//	    Start: []string{"systemctl", "start", "bitbrowser"},
+//	    Stop:  []string{"systemctl", "stop", "bitbrowser"},
+//	}
+type LocalAppController struct {
+	Start []string // Command and arguments that launch BitBrowser
+	Stop  []string // Command and arguments that stop BitBrowser
+}
+
+// StartApp launches the Start command without waiting for it to exit.
+func (l *LocalAppController) StartApp(_ context.Context) error {
+	if len(l.Start) == 0 {
+		return NewValidationError("Start", "start command is required")
+	}
+	// Not bound to ctx: the application must outlive the request.
+	cmd := exec.Command(l.Start[0], l.Start[1:]...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// StopApp runs the Stop command and waits for it.
+func (l *LocalAppController) StopApp(ctx context.Context) error {
+	if len(l.Stop) == 0 {
+		return NewValidationError("Stop", "stop command is required")
+	}
+	return runKill(exec.CommandContext(ctx, l.Stop[0], l.Stop[1:]...))
+}
+
+// WithAppController sets how StartBitBrowserApp and StopBitBrowserApp
+// control the BitBrowser application.
+func WithAppController(controller AppController) ClientOption {
+	return func(c *Client) {
+		c.appController = controller
+	}
+}
+
+// StartBitBrowserApp launches the BitBrowser application with the
+// controller set by WithAppController and waits up to timeout for Health to
+// pass. Zero or a negative timeout means 60 seconds.
+//
+// Together with StopBitBrowserApp it allows unattended recovery:
+//
+//	if err := client.Health(ctx); err != nil {
+//	    client.StopBitBrowserApp(ctx, 30*time.Second)
+//	    err = client.StartBitBrowserApp(ctx, 2*time.Minute)
+//	}
+func (c *Client) StartBitBrowserApp(ctx context.Context, timeout time.Duration) error {
+	if c.appController == nil {
+		return NewValidationError("AppController", "no app controller configured; use WithAppController")
+	}
+	if err := c.appController.StartApp(ctx); err != nil {
+		return err
+	}
+	return c.waitForApp(ctx, "start_bitbrowser_app", timeout, true)
+}
+
+// StopBitBrowserApp stops the BitBrowser application with the controller set
+// by WithAppController and waits up to timeout for its API to go away. Zero
+// or a negative timeout means 60 seconds.
+func (c *Client) StopBitBrowserApp(ctx context.Context, timeout time.Duration) error {
+	if c.appController == nil {
+		return NewValidationError("AppController", "no app controller configured; use WithAppController")
+	}
+	if err := c.appController.StopApp(ctx); err != nil {
+		return err
+	}
+	return c.waitForApp(ctx, "stop_bitbrowser_app", timeout, false)
+}
+
+// waitForApp polls Health until it passes (up) or fails (!up).
+func (c *Client) waitForApp(ctx context.Context, op string, timeout time.Duration, up bool) error {
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+	deadline := c.clock.Now().Add(timeout)
+	var lastErr error
+	for {
+		lastErr = c.Health(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if (lastErr == nil) == up {
+			return nil
+		}
+		if !c.clock.Now().Before(deadline) {
+			return NewTimeoutError(op, timeout.String(), lastErr)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.clock.After(min(appPollInterval, timeout)):
+		}
+	}
+}
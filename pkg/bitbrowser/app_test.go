@@ -0,0 +1,124 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeApp is an AppController whose state is served by the health handler.
+type fakeApp struct {
+	up       atomic.Bool
+	startErr error
+}
+
+func (a *fakeApp) StartApp(context.Context) error {
+	if a.startErr != nil {
+		return a.startErr
+	}
+	a.up.Store(true)
+	return nil
+}
+
+func (a *fakeApp) StopApp(context.Context) error {
+	a.up.Store(false)
+	return nil
+}
+
+func (a *fakeApp) handler(w http.ResponseWriter, r *http.Request) {
+	if !a.up.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Write(successResponse(nil))
+}
+
+func TestBitBrowserApp(t *testing.T) {
+	t.Run("start waits for health", func(t *testing.T) {
+		app := &fakeApp{}
+		server := mockServer(app.handler)
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithAppController(app))
+		if err := client.StartBitBrowserApp(context.Background(), time.Second); err != nil {
+			t.Fatalf("StartBitBrowserApp() error = %v", err)
+		}
+		if err := client.Health(context.Background()); err != nil {
+			t.Errorf("Health() after start = %v", err)
+		}
+	})
+
+	t.Run("stop waits for the API to go away", func(t *testing.T) {
+		app := &fakeApp{}
+		app.up.Store(true)
+		server := mockServer(app.handler)
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithAppController(app))
+		if err := client.StopBitBrowserApp(context.Background(), time.Second); err != nil {
+			t.Fatalf("StopBitBrowserApp() error = %v", err)
+		}
+		if app.up.Load() {
+			t.Error("app still up")
+		}
+	})
+
+	t.Run("start times out if health never passes", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithAppController(nopApp{}))
+		err := client.StartBitBrowserApp(context.Background(), 20*time.Millisecond)
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) {
+			t.Errorf("err = %v, want *TimeoutError", err)
+		}
+	})
+
+	t.Run("controller errors are returned", func(t *testing.T) {
+		startErr := errors.New("not installed")
+		client := mustNew(t, "http://127.0.0.1:1", WithAppController(&fakeApp{startErr: startErr}))
+		if err := client.StartBitBrowserApp(context.Background(), time.Second); !errors.Is(err, startErr) {
+			t.Errorf("err = %v, want %v", err, startErr)
+		}
+	})
+
+	t.Run("requires a controller", func(t *testing.T) {
+		client := mustNew(t, "http://127.0.0.1:1")
+		if err := client.StartBitBrowserApp(context.Background(), time.Second); !errors.Is(err, ErrValidation) {
+			t.Errorf("err = %v, want ErrValidation", err)
+		}
+	})
+}
+
+// nopApp is an AppController that does nothing.
+type nopApp struct{}
+
+func (nopApp) StartApp(context.Context) error { return nil }
+func (nopApp) StopApp(context.Context) error  { return nil }
+
+func TestLocalAppController(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX commands")
+	}
+	ctx := context.Background()
+
+	if err := (&LocalAppController{Start: []string{"true"}}).StartApp(ctx); err != nil {
+		t.Errorf("StartApp() error = %v", err)
+	}
+	if err := (&LocalAppController{Start: []string{"/nonexistent/bitbrowser"}}).StartApp(ctx); err == nil {
+		t.Error("StartApp(missing binary) error = nil")
+	}
+	if err := (&LocalAppController{Stop: []string{"false"}}).StopApp(ctx); err == nil {
+		t.Error("StopApp(false) error = nil")
+	}
+	if err := (&LocalAppController{}).StopApp(ctx); !errors.Is(err, ErrValidation) {
+		t.Errorf("StopApp(no command) error = %v, want ErrValidation", err)
+	}
+}
@@ -0,0 +1,197 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redactedKeys lists request fields (lowercased) whose values are replaced
+// with "[REDACTED]" in audit events.
+var redactedKeys = map[string]bool{
+	"password":      true,
+	"proxypassword": true,
+	"cookie":        true,
+	"value":         true, // Cookie values
+	"fasecretkey":   true,
+	"token":         true,
+}
+
+// Redacted replaces sensitive values in audit event parameters.
+const Redacted = "[REDACTED]"
+
+// AuditEvent records a single mutating operation.
+type AuditEvent struct {
+	Time       time.Time       `json:"time"`
+	Actor      string          `json:"actor,omitempty"`      // Set with WithAuditActor
+	RequestID  string          `json:"request_id,omitempty"` // Set with WithRequestID
+	Operation  string          `json:"operation"`            // e.g. "CreateProfile"
+	Endpoint   string          `json:"endpoint"`             // e.g. "/browser/update"
+	ProfileIDs []string        `json:"profile_ids,omitempty"`
+	Params     json.RawMessage `json:"params,omitempty"` // Request body with secrets redacted
+	Success    bool            `json:"success"`
+	Error      string          `json:"error,omitempty"`
+	Duration   time.Duration   `json:"duration"`
+}
+
+// AuditLogger receives an event for every mutating operation, such as
+// CreateProfile, DeleteProfile, UpdateProxy and SetCookies.
+// Implementations must be safe for concurrent use.
+type AuditLogger interface {
+	Audit(ctx context.Context, event AuditEvent)
+}
+
+// AuditLoggerFunc adapts a function to the AuditLogger interface.
+type AuditLoggerFunc func(ctx context.Context, event AuditEvent)
+
+// Audit calls f(ctx, event).
+func (f AuditLoggerFunc) Audit(ctx context.Context, event AuditEvent) {
+	f(ctx, event)
+}
+
+// JSONAuditLogger writes audit events as JSON lines.
+type JSONAuditLogger struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	err    error
+}
+
+// NewJSONAuditLogger creates an AuditLogger that writes one JSON object per
+// line to w.
+func NewJSONAuditLogger(w io.Writer) *JSONAuditLogger {
+	return &JSONAuditLogger{w: w}
+}
+
+// OpenAuditLog creates an AuditLogger that appends JSON lines to the file at
+// path, creating it with mode 0600 if needed. Close the logger when done.
+func OpenAuditLog(path string) (*JSONAuditLogger, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, NewValidationError("path", "failed to open audit log: "+err.Error())
+	}
+	return &JSONAuditLogger{w: f, closer: f}, nil
+}
+
+// Audit writes the event. Write errors are kept and reported by Err.
+func (l *JSONAuditLogger) Audit(ctx context.Context, event AuditEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(data); err != nil && l.err == nil {
+		l.err = err
+	}
+}
+
+// Err returns the first error encountered while writing events.
+func (l *JSONAuditLogger) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Close closes the underlying file if the logger was created by OpenAuditLog.
+func (l *JSONAuditLogger) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// audit records a mutating request if an audit logger is configured.
+func (c *Client) audit(ctx context.Context, path string, jsonData []byte, respBody any, err error, start time.Time) {
+	ep := endpoints[path]
+	if c.auditLogger == nil || !ep.audited {
+		return
+	}
+
+	var params map[string]any
+	json.Unmarshal(jsonData, &params)
+
+	event := AuditEvent{
+		Time:       start,
+		Operation:  ep.name,
+		Endpoint:   path,
+		ProfileIDs: profileIDs(params),
+		Success:    err == nil,
+		Duration:   time.Since(start),
+	}
+	event.Actor, _ = AuditActorFromContext(ctx)
+	event.RequestID, _ = RequestIDFromContext(ctx)
+
+	if resp, ok := respBody.(*Response); ok && err == nil {
+		event.Success = resp.Success
+		if !resp.Success {
+			event.Error = resp.Msg
+		}
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	if path == "/browser/update" && len(event.ProfileIDs) == 0 {
+		event.Operation = "CreateProfile"
+		if resp, ok := respBody.(*Response); ok && event.Success {
+			var data struct {
+				ID string `json:"id"`
+			}
+			if json.Unmarshal(resp.Data, &data) == nil && data.ID != "" {
+				event.ProfileIDs = []string{data.ID}
+			}
+		}
+	}
+
+	if params != nil {
+		event.Params, _ = json.Marshal(redact(params))
+	}
+
+	c.auditLogger.Audit(ctx, event)
+}
+
+// profileIDs extracts the target profile IDs from a request body.
+func profileIDs(params map[string]any) []string {
+	var ids []string
+	for _, key := range []string{"id", "browserId", "ids", "browserIds"} {
+		switch v := params[key].(type) {
+		case string:
+			if v != "" {
+				ids = append(ids, v)
+			}
+		case []any:
+			for _, id := range v {
+				if s, ok := id.(string); ok {
+					ids = append(ids, s)
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// redact replaces sensitive values in a decoded JSON value.
+func redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, val := range v {
+			if redactedKeys[strings.ToLower(key)] && val != nil && val != "" {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redact(val)
+		}
+	case []any:
+		for i, val := range v {
+			v[i] = redact(val)
+		}
+	}
+	return v
+}
@@ -0,0 +1,205 @@
+package bitbrowser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordAudit returns an AuditLogger that collects events.
+func recordAudit() (AuditLogger, func() []AuditEvent) {
+	var mu sync.Mutex
+	var events []AuditEvent
+	logger := AuditLoggerFunc(func(ctx context.Context, event AuditEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+	return logger, func() []AuditEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]AuditEvent(nil), events...)
+	}
+}
+
+func TestAuditLogger(t *testing.T) {
+	t.Run("records create with new profile ID", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(map[string]string{"id": "new-profile"}))
+		})
+		defer server.Close()
+
+		logger, events := recordAudit()
+		client := mustNew(t, server.URL, WithAuditLogger(logger))
+		ctx := WithAuditActor(WithRequestID(context.Background(), "job-1"), "alice")
+
+		_, err := client.CreateProfile(ctx, ProfileConfig{Name: "test", Password: "hunter2"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got := events()
+		if len(got) != 1 {
+			t.Fatalf("events = %d, want 1", len(got))
+		}
+		e := got[0]
+		if e.Operation != "CreateProfile" || e.Endpoint != "/browser/update" {
+			t.Errorf("unexpected operation: %s %s", e.Operation, e.Endpoint)
+		}
+		if e.Actor != "alice" || e.RequestID != "job-1" {
+			t.Errorf("Actor = %q, RequestID = %q", e.Actor, e.RequestID)
+		}
+		if len(e.ProfileIDs) != 1 || e.ProfileIDs[0] != "new-profile" {
+			t.Errorf("ProfileIDs = %v, want [new-profile]", e.ProfileIDs)
+		}
+		if !e.Success || e.Time.IsZero() {
+			t.Errorf("unexpected event: %+v", e)
+		}
+		if strings.Contains(string(e.Params), "hunter2") || !strings.Contains(string(e.Params), Redacted) {
+			t.Errorf("password not redacted: %s", e.Params)
+		}
+	})
+
+	t.Run("redacts cookie values", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(nil))
+		})
+		defer server.Close()
+
+		logger, events := recordAudit()
+		client := mustNew(t, server.URL, WithAuditLogger(logger))
+		cookies := []Cookie{{Name: "session", Value: "secret-token", Domain: ".example.com"}}
+		if err := client.SetCookies(context.Background(), "profile-1", cookies); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		e := events()[0]
+		if e.Operation != "SetCookies" || len(e.ProfileIDs) != 1 || e.ProfileIDs[0] != "profile-1" {
+			t.Errorf("unexpected event: %+v", e)
+		}
+		params := string(e.Params)
+		if strings.Contains(params, "secret-token") {
+			t.Errorf("cookie value not redacted: %s", params)
+		}
+		if !strings.Contains(params, "session") || !strings.Contains(params, ".example.com") {
+			t.Errorf("cookie metadata should be kept: %s", params)
+		}
+	})
+
+	t.Run("records failures", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(errorResponse("profile not found"))
+		})
+		defer server.Close()
+
+		logger, events := recordAudit()
+		client := mustNew(t, server.URL, WithAuditLogger(logger))
+		client.DeleteProfile(context.Background(), "missing")
+
+		e := events()[0]
+		if e.Operation != "DeleteProfile" || e.Success || e.Error != "profile not found" {
+			t.Errorf("unexpected event: %+v", e)
+		}
+		if len(e.ProfileIDs) != 1 || e.ProfileIDs[0] != "missing" {
+			t.Errorf("ProfileIDs = %v, want [missing]", e.ProfileIDs)
+		}
+	})
+
+	t.Run("skips read-only operations", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(map[string]string{}))
+		})
+		defer server.Close()
+
+		logger, events := recordAudit()
+		client := mustNew(t, server.URL, WithAuditLogger(logger))
+		client.GetPorts(context.Background())
+		client.GetProfileDetail(context.Background(), "profile-1")
+
+		if n := len(events()); n != 0 {
+			t.Errorf("events = %d, want 0", n)
+		}
+	})
+
+	t.Run("records proxy updates", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(nil))
+		})
+		defer server.Close()
+
+		logger, events := recordAudit()
+		client := mustNew(t, server.URL, WithAuditLogger(logger))
+		client.UpdateProxy(context.Background(), ProxyUpdateRequest{
+			IDs:           []string{"a", "b"},
+			ProxyMethod:   ProxyMethodCustom,
+			ProxyPassword: "p4ss",
+		})
+
+		e := events()[0]
+		if e.Operation != "UpdateProxy" || len(e.ProfileIDs) != 2 {
+			t.Errorf("unexpected event: %+v", e)
+		}
+		if strings.Contains(string(e.Params), "p4ss") {
+			t.Errorf("proxy password not redacted: %s", e.Params)
+		}
+	})
+}
+
+func TestJSONAuditLogger(t *testing.T) {
+	t.Run("writes JSON lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := NewJSONAuditLogger(&buf)
+		logger.Audit(context.Background(), AuditEvent{Operation: "DeleteProfile", ProfileIDs: []string{"a"}})
+		logger.Audit(context.Background(), AuditEvent{Operation: "UpdateProxy"})
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("lines = %d, want 2", len(lines))
+		}
+		var e AuditEvent
+		if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if e.Operation != "DeleteProfile" || e.ProfileIDs[0] != "a" {
+			t.Errorf("unexpected event: %+v", e)
+		}
+		if logger.Err() != nil {
+			t.Errorf("unexpected error: %v", logger.Err())
+		}
+	})
+
+	t.Run("appends to file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		for range 2 {
+			logger, err := OpenAuditLog(path)
+			if err != nil {
+				t.Fatalf("OpenAuditLog failed: %v", err)
+			}
+			logger.Audit(context.Background(), AuditEvent{Operation: "ClearCookies"})
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := strings.Count(string(data), "\n"); n != 2 {
+			t.Errorf("lines = %d, want 2", n)
+		}
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		_, err := OpenAuditLog(filepath.Join(t.TempDir(), "missing", "audit.jsonl"))
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package bitbrowser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// benchClient returns a client whose transport answers every request with
+// body without touching the network, so that benchmarks measure the SDK.
+func benchClient(b *testing.B, body []byte) *Client {
+	b.Helper()
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		io.Copy(io.Discard, r.Body)
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       r,
+		}, nil
+	})
+	client, err := New("http://127.0.0.1:54345", WithTransport(transport))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return client
+}
+
+// benchProfiles is a /browser/list response with n profiles.
+func benchProfiles(n int) []byte {
+	result := ListResult{Total: n}
+	for i := range n {
+		result.List = append(result.List, ProfileDetail{
+			ID:          fmt.Sprintf("profile-%d", i),
+			Seq:         i,
+			Name:        fmt.Sprintf("Profile %d", i),
+			Platform:    "https://www.example.com",
+			ProxyMethod: 2,
+			ProxyType:   "socks5",
+			Host:        "10.0.0.1",
+			Port:        1080,
+		})
+	}
+	return successResponse(result)
+}
+
+func BenchmarkDoRequest(b *testing.B) {
+	client := benchClient(b, successResponse(map[string]string{"id": "profile-1"}))
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		var resp Response
+		if err := client.doRequest(ctx, "/browser/detail", idBody{ID: "profile-1"}, &resp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetPorts(b *testing.B) {
+	ports := make(map[string]string)
+	for i := range 50 {
+		ports[fmt.Sprintf("profile-%d", i)] = fmt.Sprint(9222 + i)
+	}
+	client := benchClient(b, successResponse(ports))
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := client.GetPorts(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkListProfiles(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			body := benchProfiles(n)
+			client := benchClient(b, body)
+			ctx := context.Background()
+			b.SetBytes(int64(len(body)))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := client.ListProfiles(ctx, ListRequest{PageSize: n}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkListProfilesStream(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			body := benchProfiles(n)
+			client := benchClient(b, body)
+			ctx := context.Background()
+			b.SetBytes(int64(len(body)))
+			b.ReportAllocs()
+			for b.Loop() {
+				err := client.ListProfilesStream(ctx, ListRequest{PageSize: n}, func(ProfileDetail) error { return nil })
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkEncodeProfileConfig(b *testing.B) {
+	config := ProfileConfig{
+		Name:               "bench",
+		ProxyMethod:        2,
+		ProxyType:          "socks5",
+		Host:               "10.0.0.1",
+		Port:               1080,
+		BrowserFingerPrint: &Fingerprint{CoreVersion: DefaultCoreVersion},
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := json.Marshal(config); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodePartialUpdate(b *testing.B) {
+	req := PartialUpdateRequest{
+		IDs: []string{"profile-1", "profile-2"},
+		ProfileConfig: ProfileConfig{
+			Name:  "bench",
+			Host:  "10.0.0.1",
+			Port:  1080,
+			Extra: map[string]any{"newSetting": true},
+		},
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := json.Marshal(req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeProfileDetail(b *testing.B) {
+	data := []byte(`{"id":"profile-1","seq":1,"name":"Profile 1","platform":"https://www.example.com",` +
+		`"proxyMethod":2,"proxyType":"socks5","host":"10.0.0.1","port":1080,"lastIp":"203.0.113.7",` +
+		`"browserFingerPrint":{"coreVersion":"130"},"isGlobalProxyInfo":false,"syncTabs":true,"workbench":"localserver"}`)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		var detail ProfileDetail
+		if err := json.Unmarshal(data, &detail); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package bitbrowsertest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+// renderJS draws a fixed canvas 2D and WebGL scene and returns both as data
+// URLs. WebGL is "" where it is unavailable or disabled.
+const renderJS = `(() => {
+	const canvas = document.createElement("canvas");
+	canvas.width = 240;
+	canvas.height = 60;
+	const ctx = canvas.getContext("2d");
+	const gradient = ctx.createLinearGradient(0, 0, 240, 0);
+	gradient.addColorStop(0, "#f60");
+	gradient.addColorStop(1, "#069");
+	ctx.fillStyle = gradient;
+	ctx.fillRect(0, 0, 240, 60);
+	ctx.font = "18px Arial";
+	ctx.fillStyle = "rgba(102, 204, 0, 0.7)";
+	ctx.fillText("go-antidetect \u{1F50D} 0123", 4, 36);
+	ctx.beginPath();
+	ctx.arc(200, 30, 20, 0, Math.PI * 2);
+	ctx.stroke();
+
+	let webgl = "";
+	const glCanvas = document.createElement("canvas");
+	glCanvas.width = 64;
+	glCanvas.height = 64;
+	const gl = glCanvas.getContext("webgl", {preserveDrawingBuffer: true});
+	if (gl) {
+		const shader = (type, source) => {
+			const s = gl.createShader(type);
+			gl.shaderSource(s, source);
+			gl.compileShader(s);
+			return s;
+		};
+		const program = gl.createProgram();
+		gl.attachShader(program, shader(gl.VERTEX_SHADER,
+			"attribute vec2 p; varying vec2 v; void main() { v = p; gl_Position = vec4(p, 0.0, 1.0); }"));
+		gl.attachShader(program, shader(gl.FRAGMENT_SHADER,
+			"precision mediump float; varying vec2 v; void main() { gl_FragColor = vec4(v * 0.5 + 0.5, 0.3, 1.0); }"));
+		gl.linkProgram(program);
+		gl.useProgram(program);
+		gl.bindBuffer(gl.ARRAY_BUFFER, gl.createBuffer());
+		gl.bufferData(gl.ARRAY_BUFFER, new Float32Array([-0.9, -0.9, 0.9, -0.7, 0.1, 0.9]), gl.STATIC_DRAW);
+		gl.enableVertexAttribArray(0);
+		gl.vertexAttribPointer(0, 2, gl.FLOAT, false, 0, 0);
+		gl.clearColor(0.1, 0.1, 0.1, 1.0);
+		gl.clear(gl.COLOR_BUFFER_BIT);
+		gl.drawArrays(gl.TRIANGLES, 0, 3);
+		webgl = glCanvas.toDataURL();
+	}
+	return {canvas: canvas.toDataURL(), webgl};
+})()`
+
+// RenderHashes are hashes of a fixed canvas 2D and WebGL scene as rendered
+// by one browser.
+type RenderHashes struct {
+	Canvas string
+	WebGL  string // Empty if WebGL is unavailable
+}
+
+// Render draws the scene in the first page of the browser at ws (typically
+// OpenResult.Ws) and returns the hashes of the results. A browser without
+// canvas noise returns the same hashes as every other browser on the same
+// machine; with noise, the hashes differ between profiles.
+func Render(ctx context.Context, ws string) (RenderHashes, error) {
+	conn, err := cdp.Dial(ctx, ws)
+	if err != nil {
+		return RenderHashes{}, err
+	}
+	defer conn.Close()
+	session, err := conn.AttachToPage(ctx)
+	if err != nil {
+		return RenderHashes{}, err
+	}
+	var images struct {
+		Canvas string `json:"canvas"`
+		WebGL  string `json:"webgl"`
+	}
+	if err := session.Evaluate(ctx, renderJS, &images); err != nil {
+		return RenderHashes{}, err
+	}
+	return RenderHashes{Canvas: hashImage(images.Canvas), WebGL: hashImage(images.WebGL)}, nil
+}
+
+func hashImage(dataURL string) string {
+	if dataURL == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(dataURL))
+	return hex.EncodeToString(sum[:8])
+}
+
+// CheckNoise verifies that canvas and WebGL noise work for two browsers
+// opened from different profiles: each renders the scene twice, and the
+// hashes must be stable within a browser and differ between the two. WebGL
+// is only checked where both browsers have it. It returns an error naming
+// every failed check.
+func CheckNoise(ctx context.Context, wsA, wsB string) error {
+	var renders [4]RenderHashes
+	for i, ws := range []string{wsA, wsA, wsB, wsB} {
+		hashes, err := Render(ctx, ws)
+		if err != nil {
+			return fmt.Errorf("bitbrowsertest: render %d: %w", i+1, err)
+		}
+		renders[i] = hashes
+	}
+	return compareNoise(renders)
+}
+
+// compareNoise checks two renders of browser A followed by two of B.
+func compareNoise(r [4]RenderHashes) error {
+	var errs []error
+	check := func(kind string, a1, a2, b1, b2 string) {
+		if a1 != a2 {
+			errs = append(errs, fmt.Errorf("%s hash is unstable in the first profile: %s, %s", kind, a1, a2))
+		}
+		if b1 != b2 {
+			errs = append(errs, fmt.Errorf("%s hash is unstable in the second profile: %s, %s", kind, b1, b2))
+		}
+		if a1 == b1 {
+			errs = append(errs, fmt.Errorf("%s hash %s is the same in both profiles: noise is not applied", kind, a1))
+		}
+	}
+	check("canvas", r[0].Canvas, r[1].Canvas, r[2].Canvas, r[3].Canvas)
+	if r[0].WebGL != "" && r[2].WebGL != "" {
+		check("WebGL", r[0].WebGL, r[1].WebGL, r[2].WebGL, r[3].WebGL)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("bitbrowsertest: noise check failed: %w", err)
+	}
+	return nil
+}
+
+// AssertNoise runs CheckNoise and fails the test if noise is broken.
+//
+// Example:
+//
+//	a, _ := client.Open(ctx, profileA, nil)
+//	b, _ := client.Open(ctx, profileB, nil)
+//	bitbrowsertest.AssertNoise(t, ctx, a.Ws, b.Ws)
+func AssertNoise(t testing.TB, ctx context.Context, wsA, wsB string) {
+	t.Helper()
+	if err := CheckNoise(ctx, wsA, wsB); err != nil {
+		t.Fatal(err)
+	}
+}
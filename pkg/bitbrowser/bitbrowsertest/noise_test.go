@@ -0,0 +1,61 @@
+package bitbrowsertest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+func TestCompareNoise(t *testing.T) {
+	a := RenderHashes{Canvas: "a1", WebGL: "a2"}
+	b := RenderHashes{Canvas: "b1", WebGL: "b2"}
+	if err := compareNoise([4]RenderHashes{a, a, b, b}); err != nil {
+		t.Errorf("noised renders: %v", err)
+	}
+	if err := compareNoise([4]RenderHashes{a, a, {Canvas: "b1"}, {Canvas: "b1"}}); err != nil {
+		t.Errorf("WebGL unavailable in one profile: %v", err)
+	}
+
+	err := compareNoise([4]RenderHashes{a, {Canvas: "a9", WebGL: "a2"}, {Canvas: "a1", WebGL: "a2"}, {Canvas: "a1", WebGL: "a2"}})
+	for _, want := range []string{"canvas hash is unstable in the first profile", "canvas hash a1 is the same in both profiles", "WebGL hash a2 is the same"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("compareNoise() = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+// TestCheckNoise_Chrome checks that plain Chrome, which adds no noise, is
+// reported as such. It is skipped when no Chrome is installed.
+func TestCheckNoise_Chrome(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+	if _, ok := FindChrome(); !ok {
+		t.Skip("no Chrome found; set " + ChromeEnv + " to run")
+	}
+
+	server := NewServer(WithChrome(""))
+	defer server.Close()
+	client, err := bitbrowser.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	a, err := client.Open(ctx, server.AddProfile("a"), nil)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	b, err := client.Open(ctx, server.AddProfile("b"), nil)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	err = CheckNoise(ctx, a.Ws, b.Ws)
+	if err == nil || !strings.Contains(err.Error(), "canvas hash") || !strings.Contains(err.Error(), "same in both profiles") {
+		t.Errorf("CheckNoise() = %v, want the canvas reported as not noised", err)
+	}
+}
@@ -0,0 +1,407 @@
+// Package bitbrowsertest provides an in-process BitBrowser emulator for tests.
+//
+// The emulator implements the local API endpoints used by the bitbrowser
+// client (profiles, open/close, ports and PIDs) on an httptest.Server. By
+// default opened browsers are simulated. With WithChrome, each open launches
+// a real headless Chrome, so end-to-end tests can drive actual CDP flows
+// (open, navigate, cookies) in CI without a BitBrowser installation or
+// license.
+//
+// Example:
+//
+//	server := bitbrowsertest.NewServer(bitbrowsertest.WithChrome(""))
+//	defer server.Close()
+//
+//	client, _ := bitbrowser.New(server.URL)
+//	id := server.AddProfile("e2e")
+//	result, err := client.Open(ctx, id, nil)
+//	conn, err := cdp.Dial(ctx, result.Ws)
+//
+// Against a real BitBrowser, AssertNoise checks that canvas and WebGL noise
+// tell two profiles apart.
+package bitbrowsertest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+// ChromeEnv names the environment variable FindChrome checks first.
+const ChromeEnv = "CHROME_PATH"
+
+// chromeStartTimeout bounds how long a launched Chrome may take to print its
+// DevTools endpoint.
+const chromeStartTimeout = 20 * time.Second
+
+// chromeNames are the executables FindChrome looks for on PATH.
+var chromeNames = []string{
+	"google-chrome",
+	"google-chrome-stable",
+	"chromium",
+	"chromium-browser",
+	"chrome",
+	"headless-shell",
+}
+
+// FindChrome returns the path of a Chrome or Chromium executable, taken from
+// $CHROME_PATH or else searched on PATH.
+func FindChrome() (string, bool) {
+	if path := os.Getenv(ChromeEnv); path != "" {
+		return path, true
+	}
+	for _, name := range chromeNames {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// Server is a BitBrowser emulator. Close it to stop the server and any
+// browsers it launched.
+type Server struct {
+	*httptest.Server
+
+	chrome string // Chrome executable, "" to simulate browsers
+
+	mu       sync.Mutex
+	profiles map[string]*bitbrowser.ProfileDetail
+	browsers map[string]*browser
+	seq      int
+}
+
+// browser is an open browser, either simulated or a Chrome process.
+type browser struct {
+	result  bitbrowser.OpenResult
+	port    string
+	cmd     *exec.Cmd
+	dataDir string
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithChrome makes open launch a headless Chrome at path. An empty path uses
+// FindChrome; if no Chrome is found, open fails.
+func WithChrome(path string) Option {
+	return func(s *Server) {
+		if path == "" {
+			path, _ = FindChrome()
+			if path == "" {
+				path = "chrome-not-found"
+			}
+		}
+		s.chrome = path
+	}
+}
+
+// NewServer starts a BitBrowser emulator.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		profiles: make(map[string]*bitbrowser.ProfileDetail),
+		browsers: make(map[string]*browser),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Close stops all browsers and shuts down the server.
+func (s *Server) Close() {
+	s.mu.Lock()
+	for id, b := range s.browsers {
+		b.stop()
+		delete(s.browsers, id)
+	}
+	s.mu.Unlock()
+	s.Server.Close()
+}
+
+// AddProfile creates a profile directly and returns its ID.
+func (s *Server) AddProfile(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addProfile(bitbrowser.ProfileConfig{Name: name})
+}
+
+// Running returns the IDs of open browsers, sorted.
+func (s *Server) Running() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.browsers))
+	for id := range s.browsers {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// serve dispatches a local API request.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		bitbrowser.ProfileConfig
+		Args     []string `json:"args"`
+		Page     int      `json:"page"`
+		PageSize int      `json:"pageSize"`
+		IDs      []string `json:"ids"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/health":
+		reply(w, nil, nil)
+	case "/browser/update":
+		if req.ID != "" {
+			if _, ok := s.profiles[req.ID]; !ok {
+				reply(w, nil, errors.New("browser not found"))
+				return
+			}
+			s.profiles[req.ID].Name = req.Name
+			reply(w, map[string]string{"id": req.ID}, nil)
+			return
+		}
+		reply(w, map[string]string{"id": s.addProfile(req.ProfileConfig)}, nil)
+	case "/browser/detail":
+		p, ok := s.profiles[req.ID]
+		if !ok {
+			reply(w, nil, errors.New("browser not found"))
+			return
+		}
+		reply(w, p, nil)
+	case "/browser/list":
+		reply(w, s.list(req.Page, req.PageSize), nil)
+	case "/browser/delete":
+		if _, ok := s.profiles[req.ID]; !ok {
+			reply(w, nil, errors.New("browser not found"))
+			return
+		}
+		s.closeBrowser(req.ID)
+		delete(s.profiles, req.ID)
+		reply(w, nil, nil)
+	case "/browser/delete/ids":
+		for _, id := range req.IDs {
+			s.closeBrowser(id)
+			delete(s.profiles, id)
+		}
+		reply(w, nil, nil)
+	case "/browser/open":
+		result, err := s.open(req.ID, req.Args)
+		reply(w, result, err)
+	case "/browser/close":
+		if _, ok := s.profiles[req.ID]; !ok {
+			reply(w, nil, errors.New("browser not found"))
+			return
+		}
+		s.closeBrowser(req.ID)
+		reply(w, nil, nil)
+	case "/browser/close/all":
+		for id := range s.browsers {
+			s.closeBrowser(id)
+		}
+		reply(w, nil, nil)
+	case "/browser/ports":
+		ports := make(map[string]string, len(s.browsers))
+		for id, b := range s.browsers {
+			ports[id] = b.port
+		}
+		reply(w, ports, nil)
+	case "/browser/pids/all", "/browser/pids/alive":
+		pids := make(map[string]int, len(s.browsers))
+		for id, b := range s.browsers {
+			pids[id] = b.result.PID
+		}
+		reply(w, pids, nil)
+	default:
+		reply(w, nil, fmt.Errorf("bitbrowsertest: %s is not emulated", r.URL.Path))
+	}
+}
+
+// addProfile stores a new profile. Callers must hold s.mu.
+func (s *Server) addProfile(config bitbrowser.ProfileConfig) string {
+	s.seq++
+	id := fmt.Sprintf("profile-%d", s.seq)
+	s.profiles[id] = &bitbrowser.ProfileDetail{
+		ID:      id,
+		Seq:     s.seq,
+		Name:    config.Name,
+		Remark:  config.Remark,
+		GroupID: config.GroupID,
+	}
+	return id
+}
+
+// list returns one page of profiles in creation order. Callers must hold s.mu.
+func (s *Server) list(page, pageSize int) bitbrowser.ListResult {
+	all := make([]bitbrowser.ProfileDetail, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		all = append(all, *p)
+	}
+	slices.SortFunc(all, func(a, b bitbrowser.ProfileDetail) int { return a.Seq - b.Seq })
+
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	start := min(page*pageSize, len(all))
+	end := min(start+pageSize, len(all))
+	return bitbrowser.ListResult{List: all[start:end], Page: page, Total: len(all)}
+}
+
+// open opens a profile's browser, reusing it if it is already open.
+// Callers must hold s.mu.
+func (s *Server) open(id string, args []string) (*bitbrowser.OpenResult, error) {
+	p, ok := s.profiles[id]
+	if !ok {
+		return nil, errors.New("browser not found")
+	}
+	if b, ok := s.browsers[id]; ok {
+		return &b.result, nil
+	}
+
+	port := debuggingPort(args)
+	var b *browser
+	if s.chrome == "" {
+		if port == "0" {
+			port = strconv.Itoa(40000 + p.Seq)
+		}
+		b = &browser{port: port, result: bitbrowser.OpenResult{
+			Ws:   "ws://127.0.0.1:" + port + "/devtools/browser/" + id,
+			Http: "127.0.0.1:" + port,
+			PID:  10000 + p.Seq,
+		}}
+	} else {
+		var err error
+		if b, err = launchChrome(s.chrome, port); err != nil {
+			return nil, err
+		}
+	}
+
+	b.result.Seq = p.Seq
+	b.result.Name = p.Name
+	b.result.Remark = p.Remark
+	b.result.GroupID = p.GroupID
+	s.browsers[id] = b
+	return &b.result, nil
+}
+
+// closeBrowser stops a profile's browser if it is open. Callers must hold s.mu.
+func (s *Server) closeBrowser(id string) {
+	if b, ok := s.browsers[id]; ok {
+		b.stop()
+		delete(s.browsers, id)
+	}
+}
+
+// launchChrome starts a headless Chrome with its own profile directory and
+// waits for its DevTools endpoint.
+func launchChrome(path, port string) (*browser, error) {
+	dataDir, err := os.MkdirTemp("", "bitbrowsertest-")
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(path,
+		"--headless=new",
+		"--remote-debugging-port="+port,
+		"--user-data-dir="+dataDir,
+		"--no-first-run",
+		"--no-default-browser-check",
+		"--no-sandbox",
+		"--disable-gpu",
+		"about:blank",
+	)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		os.RemoveAll(dataDir)
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(dataDir)
+		return nil, fmt.Errorf("bitbrowsertest: start chrome: %w", err)
+	}
+	b := &browser{cmd: cmd, dataDir: dataDir}
+
+	// Chrome prints "DevTools listening on ws://127.0.0.1:PORT/devtools/browser/ID"
+	found := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			if _, ws, ok := strings.Cut(scanner.Text(), "DevTools listening on "); ok {
+				found <- strings.TrimSpace(ws)
+				break
+			}
+		}
+		close(found)
+		for scanner.Scan() {
+		}
+	}()
+
+	select {
+	case ws, ok := <-found:
+		if !ok {
+			b.stop()
+			return nil, errors.New("bitbrowsertest: chrome exited before listening")
+		}
+		u, err := url.Parse(ws)
+		if err != nil {
+			b.stop()
+			return nil, fmt.Errorf("bitbrowsertest: bad DevTools URL %q: %w", ws, err)
+		}
+		b.port = u.Port()
+		b.result = bitbrowser.OpenResult{Ws: ws, Http: u.Host, PID: cmd.Process.Pid}
+		return b, nil
+	case <-time.After(chromeStartTimeout):
+		b.stop()
+		return nil, errors.New("bitbrowsertest: timed out waiting for chrome")
+	}
+}
+
+// stop kills a launched Chrome and removes its profile directory.
+func (b *browser) stop() {
+	if b.cmd == nil {
+		return
+	}
+	b.cmd.Process.Kill()
+	b.cmd.Wait()
+	os.RemoveAll(b.dataDir)
+}
+
+// debuggingPort returns the port requested with --remote-debugging-port,
+// or "0" to let the browser choose.
+func debuggingPort(args []string) string {
+	for _, arg := range args {
+		if port, ok := strings.CutPrefix(arg, "--remote-debugging-port="); ok {
+			return port
+		}
+	}
+	return "0"
+}
+
+// reply writes a BitBrowser API response.
+func reply(w http.ResponseWriter, data any, err error) {
+	resp := bitbrowser.Response{Success: err == nil}
+	if err != nil {
+		resp.Msg = err.Error()
+	} else if data != nil {
+		resp.Data, _ = json.Marshal(data)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
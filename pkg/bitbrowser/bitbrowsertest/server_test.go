@@ -0,0 +1,151 @@
+package bitbrowsertest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client, err := bitbrowser.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := client.Health(ctx); err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+
+	id, err := client.CreateProfile(ctx, bitbrowser.ProfileConfig{Name: "first"})
+	if err != nil {
+		t.Fatalf("CreateProfile failed: %v", err)
+	}
+	second := server.AddProfile("second")
+
+	list, err := client.ListProfiles(ctx, bitbrowser.ListRequest{PageSize: 1})
+	if err != nil {
+		t.Fatalf("ListProfiles failed: %v", err)
+	}
+	if list.Total != 2 || len(list.List) != 1 || list.List[0].ID != id {
+		t.Errorf("unexpected list: %+v", list)
+	}
+
+	result, err := client.Open(ctx, id, nil)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if result.Ws == "" || result.Name != "first" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	ports, err := client.GetPorts(ctx)
+	if err != nil || ports[id] == 0 {
+		t.Errorf("GetPorts = %v, %v", ports, err)
+	}
+
+	if err := client.Close(ctx, id); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if running := server.Running(); len(running) != 0 {
+		t.Errorf("running = %v, want none", running)
+	}
+
+	if err := client.DeleteProfile(ctx, second); err != nil {
+		t.Fatalf("DeleteProfile failed: %v", err)
+	}
+	if _, err := client.GetProfileDetail(ctx, second); err == nil {
+		t.Error("expected error for deleted profile")
+	}
+}
+
+func TestServer_ManagedPort(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client, err := bitbrowser.New(server.URL, bitbrowser.WithPortRange(50000, 50100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := server.AddProfile("managed")
+	if _, err := client.Open(context.Background(), id, nil); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	ports, _ := client.GetPorts(context.Background())
+	if port := ports[id]; port < 50000 || port > 50100 {
+		t.Errorf("port = %d, want one from the managed range", port)
+	}
+}
+
+// TestServer_Chrome runs a real CDP flow against a headless Chrome.
+// It is skipped when no Chrome is installed (see FindChrome).
+func TestServer_Chrome(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+	if _, ok := FindChrome(); !ok {
+		t.Skip("no Chrome found; set " + ChromeEnv + " to run")
+	}
+
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "e2e"})
+		w.Write([]byte("<title>e2e</title>"))
+	}))
+	defer page.Close()
+
+	server := NewServer(WithChrome(""))
+	defer server.Close()
+	client, err := bitbrowser.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := client.Open(ctx, server.AddProfile("chrome"), nil)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	conn, err := cdp.Dial(ctx, result.Ws)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	session, err := conn.AttachToPage(ctx)
+	if err != nil {
+		t.Fatalf("AttachToPage failed: %v", err)
+	}
+	if err := session.Call(ctx, "Page.navigate", map[string]string{"url": page.URL}, nil); err != nil {
+		t.Fatalf("navigate failed: %v", err)
+	}
+
+	var cookies struct {
+		Cookies []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"cookies"`
+	}
+	for range 50 {
+		var raw json.RawMessage
+		if err := session.Call(ctx, "Network.getCookies", map[string][]string{"urls": {page.URL}}, &raw); err != nil {
+			t.Fatalf("getCookies failed: %v", err)
+		}
+		json.Unmarshal(raw, &cookies)
+		if len(cookies.Cookies) > 0 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if len(cookies.Cookies) != 1 || cookies.Cookies[0].Value != "e2e" {
+		t.Errorf("cookies = %+v, want session=e2e", cookies.Cookies)
+	}
+}
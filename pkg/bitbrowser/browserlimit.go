@@ -0,0 +1,134 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// browserLimitPoll is how often Open rechecks the running browsers while
+// waiting for capacity, in case browsers were closed by others.
+const browserLimitPoll = 2 * time.Second
+
+// browserLimit is the cap set with WithMaxOpenBrowsers.
+type browserLimit struct {
+	max int // 0 means unlimited
+
+	mu      sync.Mutex
+	opened  map[string]struct{} // Profiles this client opened and has not closed
+	pending int                 // Opens holding a slot that have not finished
+	changed chan struct{}       // Closed and replaced whenever a slot frees
+}
+
+// WithMaxOpenBrowsers caps how many browsers may run on the BitBrowser
+// host at once, so that a busy queue cannot open more browsers than the
+// machine has memory for. Before opening a profile that is not running,
+// Open counts the running browsers with GetAllPIDs (or, if that fails, the
+// browsers this client opened), plus the opens still in flight. At the
+// cap, Open fails with an error matching ErrNoCapacity, or waits for a
+// browser to close if OpenOptions.WaitForCapacity is set.
+//
+// The cap counts browsers opened by anyone, but only this client's opens
+// in flight; clients sharing a host should share a cap with a FleetClient
+// instead. n <= 0 means unlimited, the default.
+//
+// Example:
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithMaxOpenBrowsers(20))
+func WithMaxOpenBrowsers(n int) ClientOption {
+	return func(c *Client) {
+		c.limit.max = max(n, 0)
+	}
+}
+
+// acquireBrowserSlot reserves a slot for opening the profile id, waiting
+// for one if wait is set. The returned function must be called with the
+// outcome of the open. Profiles that are already running need no slot.
+func (c *Client) acquireBrowserSlot(ctx context.Context, id string, wait bool) (func(opened bool), error) {
+	l := &c.limit
+	if l.max == 0 {
+		return func(bool) {}, nil
+	}
+	for {
+		pids, pidsErr := c.GetAllPIDs(ctx)
+
+		l.mu.Lock()
+		if l.changed == nil {
+			l.changed = make(chan struct{})
+		}
+		changed := l.changed
+		running := len(l.opened)
+		_, isRunning := l.opened[id]
+		if pidsErr == nil {
+			// Forget browsers that were closed without Close
+			for opened := range l.opened {
+				if _, ok := pids[opened]; !ok {
+					delete(l.opened, opened)
+				}
+			}
+			running = len(pids)
+			_, isRunning = pids[id]
+		}
+		if isRunning || running+l.pending < l.max {
+			l.pending++
+			l.mu.Unlock()
+			return func(opened bool) { c.releaseBrowserSlot(id, opened) }, nil
+		}
+		pending := l.pending
+		l.mu.Unlock()
+
+		err := fmt.Errorf("%w: %d of %d browsers running, %d opening", ErrNoCapacity, running, l.max, pending)
+		if !wait {
+			return nil, fmt.Errorf("bitbrowser: open refused: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("bitbrowser: open refused: %w", errors.Join(err, ctx.Err()))
+		case <-changed:
+		case <-c.clock.After(browserLimitPoll):
+		}
+	}
+}
+
+// releaseBrowserSlot ends an open that held a slot.
+func (c *Client) releaseBrowserSlot(id string, opened bool) {
+	l := &c.limit
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending--
+	if opened {
+		if l.opened == nil {
+			l.opened = make(map[string]struct{})
+		}
+		l.opened[id] = struct{}{}
+	}
+	l.notify()
+}
+
+// forgetOpened records that browsers were closed, freeing their slots. No
+// ids means all browsers.
+func (c *Client) forgetOpened(ids ...string) {
+	l := &c.limit
+	if l.max == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(ids) == 0 {
+		clear(l.opened)
+	}
+	for _, id := range ids {
+		delete(l.opened, id)
+	}
+	l.notify()
+}
+
+// notify wakes opens waiting for capacity. The caller must hold l.mu.
+func (l *browserLimit) notify() {
+	if l.changed != nil {
+		close(l.changed)
+	}
+	l.changed = make(chan struct{})
+}
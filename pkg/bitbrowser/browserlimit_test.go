@@ -0,0 +1,123 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMaxOpenBrowsers(t *testing.T) {
+	ctx := context.Background()
+
+	// newClient returns a client of a fake API that opens and closes any
+	// profile. pids, if not nil, answers /browser/pids/all; otherwise it fails.
+	newClient := func(t *testing.T, pids map[string]int, opts ...ClientOption) *Client {
+		t.Helper()
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/browser/pids/all":
+				if pids == nil {
+					w.Write(errorResponse("not supported"))
+					return
+				}
+				w.Write(successResponse(pids))
+			case "/browser/open":
+				w.Write(successResponse(OpenResult{Http: "http://127.0.0.1:9222"}))
+			default:
+				w.Write(successResponse(nil))
+			}
+		})
+		t.Cleanup(server.Close)
+		return mustNew(t, server.URL, opts...)
+	}
+
+	t.Run("refuses new browsers at the cap", func(t *testing.T) {
+		client := newClient(t, map[string]int{"p1": 101, "p2": 102}, WithMaxOpenBrowsers(2))
+
+		_, err := client.Open(ctx, "p3", nil)
+		if !errors.Is(err, ErrNoCapacity) {
+			t.Fatalf("err = %v, want ErrNoCapacity", err)
+		}
+		if _, err := client.Open(ctx, "p1", nil); err != nil {
+			t.Errorf("reopening a running profile failed: %v", err)
+		}
+	})
+
+	t.Run("counts its own opens without GetAllPIDs", func(t *testing.T) {
+		client := newClient(t, nil, WithMaxOpenBrowsers(1))
+
+		if _, err := client.Open(ctx, "p1", nil); err != nil {
+			t.Fatalf("Open(p1) failed: %v", err)
+		}
+		if _, err := client.Open(ctx, "p2", nil); !errors.Is(err, ErrNoCapacity) {
+			t.Fatalf("err = %v, want ErrNoCapacity", err)
+		}
+		if err := client.Close(ctx, "p1"); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if _, err := client.Open(ctx, "p2", nil); err != nil {
+			t.Errorf("Open(p2) after Close failed: %v", err)
+		}
+	})
+
+	t.Run("waits for a browser to close", func(t *testing.T) {
+		clock := NewFakeClock(time.Unix(0, 0))
+		client := newClient(t, nil, WithMaxOpenBrowsers(1), WithClock(clock))
+		if _, err := client.Open(ctx, "p1", nil); err != nil {
+			t.Fatalf("Open(p1) failed: %v", err)
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := client.Open(ctx, "p2", &OpenOptions{WaitForCapacity: true})
+			done <- err
+		}()
+		clock.BlockUntil(1) // Waiting for capacity
+		select {
+		case err := <-done:
+			t.Fatalf("Open(p2) returned %v before p1 closed", err)
+		default:
+		}
+		if err := client.CloseAll(ctx); err != nil {
+			t.Fatalf("CloseAll failed: %v", err)
+		}
+		if err := <-done; err != nil {
+			t.Errorf("Open(p2) failed: %v", err)
+		}
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		client := newClient(t, map[string]int{"p1": 101}, WithMaxOpenBrowsers(1))
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.Open(ctx, "p2", &OpenOptions{WaitForCapacity: true})
+		if !errors.Is(err, ErrNoCapacity) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("err = %v, want ErrNoCapacity and context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("opens in flight hold a slot", func(t *testing.T) {
+		client := newClient(t, map[string]int{}, WithMaxOpenBrowsers(1))
+		release, err := client.acquireBrowserSlot(ctx, "p1", false)
+		if err != nil {
+			t.Fatalf("acquireBrowserSlot failed: %v", err)
+		}
+		if _, err := client.Open(ctx, "p2", nil); !errors.Is(err, ErrNoCapacity) {
+			t.Errorf("err = %v, want ErrNoCapacity", err)
+		}
+		release(false)
+		if _, err := client.Open(ctx, "p2", nil); err != nil {
+			t.Errorf("Open(p2) after the slot was released failed: %v", err)
+		}
+	})
+
+	t.Run("unlimited by default", func(t *testing.T) {
+		client := newClient(t, map[string]int{"p1": 101})
+		if _, err := client.Open(ctx, "p2", nil); err != nil {
+			t.Errorf("Open failed: %v", err)
+		}
+	})
+}
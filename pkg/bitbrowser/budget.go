@@ -0,0 +1,106 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BudgetStep is a step of a Budget.
+type BudgetStep struct {
+	Name   string
+	Weight int // Share of the time relative to the other steps; 0 means 1
+}
+
+// Budget divides the deadline of a context across the steps of a
+// multi-step operation, so that one slow step cannot use up the time of
+// the steps after it, and a timeout names the step that ran out of time.
+//
+// Each step gets the time left, divided in proportion to its weight and
+// the weights of the steps after it; time a step does not use rolls over
+// to later steps. Steps run in the order they are declared and may be
+// skipped. If the context has no deadline, steps run without one.
+//
+// A Budget is not safe for concurrent use.
+//
+// Example:
+//
+//	budget := bitbrowser.NewBudget("provision",
+//	    bitbrowser.BudgetStep{Name: "create"},
+//	    bitbrowser.BudgetStep{Name: "open", Weight: 3},
+//	)
+//	err := budget.Run(ctx, "create", func(ctx context.Context) error {
+//	    id, err = client.CreateProfile(ctx, config)
+//	    return err
+//	})
+//	// On timeout, err is a *TimeoutError with Step "create".
+type Budget struct {
+	op    string
+	steps []BudgetStep
+	next  int
+}
+
+// NewBudget returns a Budget for the operation op with the given steps.
+func NewBudget(op string, steps ...BudgetStep) *Budget {
+	return &Budget{op: op, steps: steps}
+}
+
+// Run runs the step name with its share of ctx's remaining time, skipping
+// any steps declared before it that have not run. If the step fails after
+// its time ran out, the error is a *TimeoutError with the operation, step,
+// share and elapsed time, wrapping the step's error. Other errors,
+// including cancellation of ctx, are returned unchanged.
+func (b *Budget) Run(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	i := b.index(name)
+	if i < 0 {
+		return fmt.Errorf("bitbrowser: %s has no step %q left", b.op, name)
+	}
+	b.next = i + 1
+
+	stepCtx := ctx
+	var share time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		share = b.share(i, time.Until(deadline))
+		var cancel context.CancelFunc
+		stepCtx, cancel = context.WithTimeout(ctx, share)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := fn(stepCtx)
+	if err == nil || !errors.Is(stepCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	timeoutErr := &TimeoutError{Op: b.op, Step: name, Elapsed: time.Since(start), Err: err}
+	if share > 0 {
+		timeoutErr.Duration = share.Round(time.Millisecond).String()
+	}
+	return timeoutErr
+}
+
+// index returns the index of the step name among the steps left, or -1.
+func (b *Budget) index(name string) int {
+	for i := b.next; i < len(b.steps); i++ {
+		if b.steps[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// share returns step i's share of the time left.
+func (b *Budget) share(i int, left time.Duration) time.Duration {
+	if left <= 0 {
+		return 0
+	}
+	var total int
+	for _, step := range b.steps[i:] {
+		total += step.weight()
+	}
+	return time.Duration(int64(left) * int64(b.steps[i].weight()) / int64(total))
+}
+
+func (s BudgetStep) weight() int {
+	return max(s.Weight, 1)
+}
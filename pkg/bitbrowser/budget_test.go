@@ -0,0 +1,133 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBudget(t *testing.T) {
+	t.Run("divides the deadline by weight", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		budget := NewBudget("op", BudgetStep{Name: "a", Weight: 3}, BudgetStep{Name: "b"})
+
+		var left time.Duration
+		budget.Run(ctx, "a", func(ctx context.Context) error {
+			deadline, _ := ctx.Deadline()
+			left = time.Until(deadline)
+			return nil
+		})
+		if left > 3*time.Second || left < 2900*time.Millisecond {
+			t.Errorf("step a has %v, want 3s", left)
+		}
+	})
+
+	t.Run("unused time rolls over", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		budget := NewBudget("op", BudgetStep{Name: "a"}, BudgetStep{Name: "b"}, BudgetStep{Name: "c"})
+
+		budget.Run(ctx, "a", func(ctx context.Context) error { return nil })
+		var left time.Duration
+		budget.Run(ctx, "b", func(ctx context.Context) error {
+			deadline, _ := ctx.Deadline()
+			left = time.Until(deadline)
+			return nil
+		})
+		if left > 2*time.Second || left < 1900*time.Millisecond {
+			t.Errorf("step b has %v, want 2s", left)
+		}
+	})
+
+	t.Run("names the step that ran out of time", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		budget := NewBudget("provision", BudgetStep{Name: "create"}, BudgetStep{Name: "open"})
+
+		err := budget.Run(ctx, "create", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) {
+			t.Fatalf("err = %v, want *TimeoutError", err)
+		}
+		if timeoutErr.Op != "provision" || timeoutErr.Step != "create" {
+			t.Errorf("op, step = %q, %q", timeoutErr.Op, timeoutErr.Step)
+		}
+		if timeoutErr.Elapsed < 40*time.Millisecond {
+			t.Errorf("Elapsed = %v, want about 50ms", timeoutErr.Elapsed)
+		}
+		if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("err = %v, want ErrTimeout wrapping context.DeadlineExceeded", err)
+		}
+		if !strings.Contains(err.Error(), "step create ran") {
+			t.Errorf("Error() = %q", err.Error())
+		}
+	})
+
+	t.Run("returns other errors unchanged", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		budget := NewBudget("op", BudgetStep{Name: "a"})
+
+		err := budget.Run(ctx, "a", func(ctx context.Context) error {
+			cancel()
+			return ctx.Err()
+		})
+		if err != context.Canceled {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("runs without a deadline", func(t *testing.T) {
+		budget := NewBudget("op", BudgetStep{Name: "a"})
+		budget.Run(context.Background(), "a", func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); ok {
+				t.Error("step has a deadline")
+			}
+			return nil
+		})
+	})
+
+	t.Run("rejects steps that were skipped or are unknown", func(t *testing.T) {
+		budget := NewBudget("op", BudgetStep{Name: "a"}, BudgetStep{Name: "b"})
+		noop := func(ctx context.Context) error { return nil }
+		if err := budget.Run(context.Background(), "b", noop); err != nil {
+			t.Fatalf("Run(b) = %v", err)
+		}
+		if err := budget.Run(context.Background(), "a", noop); err == nil {
+			t.Error("Run(a) after b succeeded")
+		}
+		if err := budget.Run(context.Background(), "c", noop); err == nil {
+			t.Error("Run(c) succeeded")
+		}
+	})
+}
+
+func TestOpenBudget(t *testing.T) {
+	release := make(chan struct{})
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+	defer server.Close()
+	defer close(release)
+	client := mustNew(t, server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Open(ctx, "profile-1", nil)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Op != "open" || timeoutErr.Step != "open" {
+		t.Fatalf("err = %v, want a timeout in step open", err)
+	}
+	if ctx.Err() != nil {
+		t.Error("the open step used the whole deadline")
+	}
+}
@@ -0,0 +1,398 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CampaignState is the state of a Campaign.
+type CampaignState string
+
+// Campaign states.
+const (
+	CampaignIdle     CampaignState = "idle"     // Not started
+	CampaignRunning  CampaignState = "running"  // Launching profiles
+	CampaignPaused   CampaignState = "paused"   // Launching nothing new; running tasks finish
+	CampaignWaiting  CampaignState = "waiting"  // Between runs
+	CampaignFinished CampaignState = "finished" // The single run ended
+	CampaignStopped  CampaignState = "stopped"  // The context of Start ended
+)
+
+// ProfileSelector returns the profiles of a campaign run. It is called at
+// the start of every run, so that profiles added since the last run join.
+type ProfileSelector func(ctx context.Context, client *Client) ([]string, error)
+
+// SelectProfiles returns a ProfileSelector of fixed profiles.
+func SelectProfiles(ids ...string) ProfileSelector {
+	ids = slices.Clone(ids)
+	return func(context.Context, *Client) ([]string, error) {
+		return ids, nil
+	}
+}
+
+// SelectProfilesWhere returns a ProfileSelector of the profiles req lists,
+// across all pages, for which match reports true; a nil match selects all.
+//
+// Example:
+//
+//	selector := bitbrowser.SelectProfilesWhere(bitbrowser.ListRequest{GroupID: groupID},
+//	    func(p bitbrowser.ProfileDetail) bool { return strings.Contains(p.Remark, "warmup") })
+func SelectProfilesWhere(req ListRequest, match func(ProfileDetail) bool) ProfileSelector {
+	return func(ctx context.Context, client *Client) ([]string, error) {
+		var ids []string
+		err := client.ListProfilesStream(ctx, req, func(p ProfileDetail) error {
+			if match == nil || match(p) {
+				ids = append(ids, p.ID)
+			}
+			return nil
+		})
+		return ids, err
+	}
+}
+
+// CampaignTask does the campaign's work in one open profile and returns its
+// output.
+type CampaignTask func(ctx context.Context, id string, browser *OpenResult) (any, error)
+
+// CampaignConfig configures a Campaign.
+type CampaignConfig struct {
+	// Profiles selects the profiles of each run. Required.
+	Profiles ProfileSelector
+
+	// Task runs in every selected profile. Required.
+	Task CampaignTask
+
+	// Proxies, if set, assigns each profile the first working proxy of the
+	// pool that matches ProxyCriteria before it is opened, as
+	// AssignProxyMatching does with the client's pool.
+	Proxies       *ProxyPool
+	ProxyCriteria ProxyCriteria
+
+	// Concurrency is how many profiles are open at once (default: 1).
+	Concurrency int
+
+	// LaunchSpacing is the least time between two launches, so that a
+	// campaign does not open a batch of browsers at the same moment.
+	LaunchSpacing time.Duration
+
+	// Interval repeats the campaign this long after a run started; a run
+	// that takes longer is followed by the next at once. 0 runs it once.
+	Interval time.Duration
+
+	// OpenOptions are used to open the profiles.
+	OpenOptions *OpenOptions
+
+	// KeepOpen leaves browsers open after their task. By default they are
+	// closed, also after a failed task.
+	KeepOpen bool
+
+	// OnResult is called after every profile's task, including failed
+	// ones.
+	OnResult func(CampaignResult)
+
+	// OnError is called when selecting the profiles of a run fails.
+	OnError func(err error)
+
+	// Clock is the time source (default: SystemClock).
+	Clock Clock
+}
+
+// CampaignResult is the outcome of a campaign task in one profile.
+type CampaignResult struct {
+	ProfileID string    `json:"profileId"`
+	Run       int       `json:"run"` // From 1
+	Output    any       `json:"output,omitempty"`
+	Err       error     `json:"-"` // Why assigning a proxy, opening or the task failed
+	Started   time.Time `json:"started"`
+	Finished  time.Time `json:"finished"`
+}
+
+// CampaignStatus is a snapshot of a Campaign.
+type CampaignStatus struct {
+	State     CampaignState `json:"state"`
+	Run       int           `json:"run"`       // Current or last run, from 1
+	Total     int           `json:"total"`     // Profiles selected for the run
+	Succeeded int           `json:"succeeded"` // Tasks of the run that succeeded
+	Failed    int           `json:"failed"`    // Tasks of the run that failed
+	Running   int           `json:"running"`   // Tasks in progress
+	NextRun   time.Time     `json:"nextRun"`   // While waiting between runs
+	Err       error         `json:"-"`         // Why selecting the profiles of the run failed
+}
+
+// Pending returns how many profiles of the run have not been launched yet.
+func (s CampaignStatus) Pending() int {
+	return s.Total - s.Succeeded - s.Failed - s.Running
+}
+
+// Campaign runs a task across a group of profiles: it selects the profiles,
+// optionally assigns them proxies from a pool, opens them a few at a time
+// with spacing between launches, runs the task, closes them and collects
+// the results, once or on an interval.
+//
+// Example:
+//
+//	campaign, err := bitbrowser.NewCampaign(client, bitbrowser.CampaignConfig{
+//	    Profiles:      bitbrowser.SelectProfilesWhere(bitbrowser.ListRequest{GroupID: groupID}, nil),
+//	    Concurrency:   3,
+//	    LaunchSpacing: 20 * time.Second,
+//	    Interval:      6 * time.Hour,
+//	    Task: func(ctx context.Context, id string, browser *bitbrowser.OpenResult) (any, error) {
+//	        return checkInbox(ctx, browser.Ws)
+//	    },
+//	})
+//	campaign.Start(ctx)
+type Campaign struct {
+	client *Client
+	config CampaignConfig
+
+	mu       sync.Mutex
+	status   CampaignStatus
+	results  []CampaignResult
+	resumed  chan struct{} // Closed when a pause ends
+	done     chan struct{} // Closed when the current Start ends
+	launched time.Time
+}
+
+// NewCampaign creates a campaign of the client's profiles.
+func NewCampaign(client *Client, config CampaignConfig) (*Campaign, error) {
+	if config.Profiles == nil {
+		return nil, NewValidationError("Profiles", "profile selector is required")
+	}
+	if config.Task == nil {
+		return nil, NewValidationError("Task", "task is required")
+	}
+	if config.Proxies != nil && len(config.Proxies.Proxies) == 0 {
+		return nil, NewValidationError("Proxies", "proxy pool has no proxies")
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+	done := make(chan struct{})
+	close(done)
+	return &Campaign{client: client, config: config, status: CampaignStatus{State: CampaignIdle}, done: done}, nil
+}
+
+// Start runs the campaign in the background until its single run ends or
+// ctx is done; Done reports when. Starting a paused campaign resumes it,
+// and starting a running one does nothing.
+func (c *Campaign) Start(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch c.status.State {
+	case CampaignPaused:
+		c.status.State = CampaignRunning
+		if !c.status.NextRun.IsZero() {
+			c.status.State = CampaignWaiting
+		}
+		close(c.resumed)
+		return
+	case CampaignRunning, CampaignWaiting:
+		return
+	}
+	c.status = CampaignStatus{State: CampaignRunning}
+	c.done = make(chan struct{})
+	go c.run(ctx, c.done)
+}
+
+// Pause stops launching profiles until Start is called again. Tasks in
+// progress finish. A campaign waiting between runs pauses when the next
+// run starts.
+func (c *Campaign) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.status.State == CampaignRunning || c.status.State == CampaignWaiting {
+		c.status.State = CampaignPaused
+		c.resumed = make(chan struct{})
+	}
+}
+
+// Status returns the state and progress of the campaign.
+func (c *Campaign) Status() CampaignStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// Results returns the results of the current or last run, in the order the
+// tasks finished.
+func (c *Campaign) Results() []CampaignResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.results)
+}
+
+// Done returns a channel that is closed when the campaign stops: after its
+// single run, or when the context of Start is done.
+func (c *Campaign) Done() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done
+}
+
+// run runs the campaign until it finishes or ctx is done.
+func (c *Campaign) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	for n := 1; ; n++ {
+		start := c.config.Clock.Now()
+		c.runOnce(ctx, n)
+		if ctx.Err() != nil {
+			c.setState(CampaignStopped)
+			return
+		}
+		if c.config.Interval <= 0 {
+			c.setState(CampaignFinished)
+			return
+		}
+
+		next := start.Add(c.config.Interval)
+		c.mu.Lock()
+		if c.status.State == CampaignRunning {
+			c.status.State = CampaignWaiting
+		}
+		c.status.NextRun = next
+		c.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			c.setState(CampaignStopped)
+			return
+		case <-c.config.Clock.After(max(next.Sub(c.config.Clock.Now()), 0)):
+		}
+		c.mu.Lock()
+		if c.status.State == CampaignWaiting {
+			c.status.State = CampaignRunning
+		}
+		c.status.NextRun = time.Time{}
+		c.mu.Unlock()
+	}
+}
+
+// runOnce runs the task in every selected profile.
+func (c *Campaign) runOnce(ctx context.Context, n int) {
+	ids, err := c.config.Profiles(ctx, c.client)
+	c.mu.Lock()
+	state := c.status.State
+	c.status = CampaignStatus{State: state, Run: n, Total: len(ids), Err: err}
+	c.results = nil
+	c.mu.Unlock()
+	if err != nil {
+		if c.config.OnError != nil && ctx.Err() == nil {
+			c.config.OnError(fmt.Errorf("bitbrowser: campaign run %d: %w", n, err))
+		}
+		return
+	}
+
+	slots := make(chan struct{}, c.config.Concurrency)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		if err := c.waitLaunch(ctx, slots); err != nil {
+			break
+		}
+		c.mu.Lock()
+		c.status.Running++
+		c.mu.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			c.finish(c.runTask(ctx, n, id))
+		}()
+	}
+	wg.Wait()
+}
+
+// waitLaunch waits until LaunchSpacing has passed since the last launch, a
+// slot is free and the campaign is not paused, and takes the slot.
+func (c *Campaign) waitLaunch(ctx context.Context, slots chan struct{}) error {
+	c.mu.Lock()
+	wait := c.launched.Add(c.config.LaunchSpacing).Sub(c.config.Clock.Now())
+	c.mu.Unlock()
+	if !c.launched.IsZero() && wait > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.config.Clock.After(wait):
+		}
+	}
+	for {
+		c.mu.Lock()
+		paused, resumed := c.status.State == CampaignPaused, c.resumed
+		c.mu.Unlock()
+		if paused {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-resumed:
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case slots <- struct{}{}:
+		}
+
+		// Pause may have been called while waiting for the slot
+		c.mu.Lock()
+		if c.status.State != CampaignPaused {
+			c.launched = c.config.Clock.Now()
+			c.mu.Unlock()
+			return nil
+		}
+		c.mu.Unlock()
+		<-slots
+	}
+}
+
+// runTask assigns a proxy to a profile, opens it, runs the task and closes
+// it.
+func (c *Campaign) runTask(ctx context.Context, n int, id string) (result CampaignResult) {
+	result = CampaignResult{ProfileID: id, Run: n, Started: c.config.Clock.Now()}
+	defer func() { result.Finished = c.config.Clock.Now() }()
+
+	if c.config.Proxies != nil {
+		if _, err := c.client.assignProxyFrom(ctx, c.config.Proxies, id, c.config.ProxyCriteria); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+	browser, err := c.client.Open(ctx, id, c.config.OpenOptions)
+	// Open returns the browser with the error when a step after launch fails
+	if browser != nil && (err != nil || !c.config.KeepOpen) {
+		defer c.client.Close(context.WithoutCancel(ctx), id)
+	}
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Output, result.Err = c.config.Task(ctx, id, browser)
+	return result
+}
+
+// finish records a task's result.
+func (c *Campaign) finish(result CampaignResult) {
+	c.mu.Lock()
+	c.status.Running--
+	if result.Err != nil {
+		c.status.Failed++
+	} else {
+		c.status.Succeeded++
+	}
+	c.results = append(c.results, result)
+	c.mu.Unlock()
+	if c.config.OnResult != nil {
+		c.config.OnResult(result)
+	}
+}
+
+// setState sets the campaign's state.
+func (c *Campaign) setState(state CampaignState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status.State = state
+}
@@ -0,0 +1,216 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+// campaignServer opens and closes any profile, lists three profiles and
+// answers proxy checks from the US. It counts requests by path.
+type campaignServer struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func newCampaignClient(t *testing.T) (*campaignServer, *Client) {
+	t.Helper()
+	s := &campaignServer{calls: make(map[string]int)}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.calls[r.URL.Path]++
+		s.mu.Unlock()
+		switch r.URL.Path {
+		case "/browser/list":
+			w.Write(successResponse(ListResult{Total: 3, List: []ProfileDetail{
+				{ID: "a", Remark: "warmup"}, {ID: "b"}, {ID: "c", Remark: "warmup"},
+			}}))
+		case "/browser/open":
+			w.Write(successResponse(OpenResult{Ws: "ws://127.0.0.1:1/devtools/browser/x"}))
+		case "/checkagent":
+			w.Write(successResponse(map[string]any{"success": true, "data": map[string]any{"ip": "198.51.100.1", "countryCode": "US"}}))
+		default:
+			w.Write(successResponse(nil))
+		}
+	})
+	t.Cleanup(server.Close)
+	return s, mustNew(t, server.URL)
+}
+
+func (s *campaignServer) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[path]
+}
+
+// waitFor polls cond until it holds or a second has passed.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCampaignRun(t *testing.T) {
+	s, client := newCampaignClient(t)
+	var mu sync.Mutex
+	var reported []string
+	campaign, err := NewCampaign(client, CampaignConfig{
+		Profiles: SelectProfilesWhere(ListRequest{}, func(p ProfileDetail) bool { return p.Remark == "warmup" }),
+		Proxies: &ProxyPool{Proxies: []ProxyUpdateRequest{
+			{ProxyType: ProxyTypeSOCKS5, Host: "10.0.0.1", Port: 1080},
+		}},
+		ProxyCriteria: ProxyCriteria{Country: "US"},
+		Concurrency:   2,
+		Task: func(ctx context.Context, id string, browser *OpenResult) (any, error) {
+			if id == "c" {
+				return nil, errors.New("captcha")
+			}
+			return id + " done", nil
+		},
+		OnResult: func(r CampaignResult) {
+			mu.Lock()
+			reported = append(reported, r.ProfileID)
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	campaign.Start(context.Background())
+	<-campaign.Done()
+
+	status := campaign.Status()
+	if status.State != CampaignFinished || status.Run != 1 || status.Total != 2 || status.Succeeded != 1 || status.Failed != 1 || status.Pending() != 0 {
+		t.Errorf("status = %+v", status)
+	}
+	results := campaign.Results()
+	slices.SortFunc(results, func(a, b CampaignResult) int { return strings.Compare(a.ProfileID, b.ProfileID) })
+	if len(results) != 2 || results[0].Output != "a done" || results[1].Err == nil || len(reported) != 2 {
+		t.Errorf("results = %+v, reported %v", results, reported)
+	}
+	for _, r := range results {
+		if r.Started.IsZero() || r.Finished.Before(r.Started) {
+			t.Errorf("%s ran from %v to %v", r.ProfileID, r.Started, r.Finished)
+		}
+	}
+	if s.count("/browser/proxy/update") != 2 || s.count("/browser/open") != 2 || s.count("/browser/close") != 2 {
+		t.Errorf("calls = %v", s.calls)
+	}
+}
+
+func TestCampaignSpacingAndInterval(t *testing.T) {
+	s, client := newCampaignClient(t)
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	campaign, _ := NewCampaign(client, CampaignConfig{
+		Profiles:      SelectProfiles("a", "b"),
+		Concurrency:   2,
+		LaunchSpacing: time.Minute,
+		Interval:      time.Hour,
+		Clock:         clock,
+		Task:          func(ctx context.Context, id string, browser *OpenResult) (any, error) { return nil, nil },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	campaign.Start(ctx)
+
+	clock.BlockUntil(1) // b waits for the spacing
+	waitFor(t, "the first open", func() bool { return s.count("/browser/open") == 1 })
+	time.Sleep(20 * time.Millisecond)
+	if n := s.count("/browser/open"); n != 1 {
+		t.Errorf("%d opens before the spacing passed, want 1", n)
+	}
+	clock.Advance(time.Minute)
+	waitFor(t, "the wait for the next run", func() bool { return campaign.Status().State == CampaignWaiting })
+	if status := campaign.Status(); status.Succeeded != 2 || !status.NextRun.Equal(clock.Now().Add(59*time.Minute)) {
+		t.Errorf("status = %+v", status)
+	}
+
+	clock.BlockUntil(1)
+	clock.Advance(59 * time.Minute)
+	waitFor(t, "the second run", func() bool { s := campaign.Status(); return s.Run == 2 && s.Succeeded == 1 })
+	cancel()
+	<-campaign.Done()
+	if state := campaign.Status().State; state != CampaignStopped {
+		t.Errorf("state = %s, want stopped", state)
+	}
+}
+
+func TestCampaignPause(t *testing.T) {
+	s, client := newCampaignClient(t)
+	started, release := make(chan string, 2), make(chan struct{})
+	campaign, _ := NewCampaign(client, CampaignConfig{
+		Profiles: SelectProfiles("a", "b"),
+		Task: func(ctx context.Context, id string, browser *OpenResult) (any, error) {
+			started <- id
+			<-release
+			return nil, nil
+		},
+	})
+	campaign.Start(context.Background())
+	<-started
+	campaign.Pause()
+	release <- struct{}{}
+
+	waitFor(t, "the first task", func() bool { return campaign.Status().Succeeded == 1 })
+	time.Sleep(20 * time.Millisecond)
+	if status := campaign.Status(); status.State != CampaignPaused || status.Pending() != 1 || s.count("/browser/open") != 1 {
+		t.Fatalf("paused status = %+v, %d opens", status, s.count("/browser/open"))
+	}
+
+	campaign.Start(context.Background())
+	if id := <-started; id != "b" {
+		t.Errorf("resumed with %s, want b", id)
+	}
+	close(release)
+	<-campaign.Done()
+	if status := campaign.Status(); status.State != CampaignFinished || status.Succeeded != 2 {
+		t.Errorf("status = %+v", status)
+	}
+}
+
+func TestCampaignClosesAfterFailedOpen(t *testing.T) {
+	s, client := newCampaignClient(t)
+	failing := func(ctx context.Context, session *cdp.Session) error { return errors.New("not reached") }
+	campaign, _ := NewCampaign(client, CampaignConfig{
+		Profiles:    SelectProfiles("a"),
+		KeepOpen:    true,
+		OpenOptions: &OpenOptions{OnReady: []ReadyHook{failing}},
+		Task: func(ctx context.Context, id string, browser *OpenResult) (any, error) {
+			t.Error("task ran although open failed")
+			return nil, nil
+		},
+	})
+	campaign.Start(context.Background())
+	<-campaign.Done()
+
+	if status := campaign.Status(); status.Failed != 1 {
+		t.Errorf("status = %+v", status)
+	}
+	if n := s.count("/browser/close"); n != 1 {
+		t.Errorf("%d closes, want the launched browser closed", n)
+	}
+}
+
+func TestNewCampaignValidation(t *testing.T) {
+	client := mustNew(t, "http://127.0.0.1:1")
+	task := func(ctx context.Context, id string, browser *OpenResult) (any, error) { return nil, nil }
+	for name, config := range map[string]CampaignConfig{
+		"no selector": {Task: task},
+		"no task":     {Profiles: SelectProfiles("a")},
+		"empty pool":  {Profiles: SelectProfiles("a"), Task: task, Proxies: &ProxyPool{}},
+	} {
+		if _, err := NewCampaign(client, config); !errors.Is(err, ErrValidation) {
+			t.Errorf("%s: err = %v, want ErrValidation", name, err)
+		}
+	}
+}
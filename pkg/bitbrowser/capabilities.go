@@ -0,0 +1,69 @@
+package bitbrowser
+
+// Capabilities lists the features an antidetect browser client offers, so
+// vendor-agnostic code can branch on them instead of type-asserting
+// concrete clients.
+type Capabilities struct {
+	Vendor string // e.g. "bitbrowser"
+
+	SupportsCookies       bool // GetCookies, SetCookies and ClearCookies
+	SupportsHeadless      bool // OpenOptions.Headless
+	SupportsWindowArrange bool // ArrangeWindows and ArrangeWindowsFlexible
+	SupportsProxyCheck    bool // CheckProxy
+	SupportsFingerprint   bool // RandomizeFingerprint
+	SupportsGroups        bool // UpdateGroup
+	SupportsPartialUpdate bool // UpdateProfilePartial
+	SupportsRPA           bool // StopRPA
+
+	SupportsManagedPorts bool // Managed Mode is enabled (WithPortRange)
+	SupportsProcessKill  bool // KillBrowserProcess (WithProcessKiller)
+	SupportsAppControl   bool // StartBitBrowserApp and StopBitBrowserApp (WithAppController)
+}
+
+// CapabilityProvider reports the features of an antidetect browser client.
+// *Client implements it; adapters for other vendors report their own.
+type CapabilityProvider interface {
+	Capabilities() Capabilities
+}
+
+// capabilityEndpoints are the endpoints each API capability relies on.
+var capabilityEndpoints = map[string][]string{
+	"cookies":       {"/browser/cookies/get", "/browser/cookies/set", "/browser/cookies/clear"},
+	"headless":      {"/browser/open"},
+	"windows":       {"/windowbounds", "/windowbounds/flexable"},
+	"proxyCheck":    {"/checkagent"},
+	"fingerprint":   {"/browser/fingerprint/random"},
+	"groups":        {"/browser/group/update"},
+	"partialUpdate": {"/browser/update/partial"},
+	"rpa":           {"/rpa/stop"},
+}
+
+// Capabilities reports what the client can do. API features are assumed
+// available unless one of their endpoints answered 404 earlier (see
+// Supports); use Supports to probe before relying on a read endpoint.
+// Features that need client configuration reflect the options given to New.
+// It makes no requests.
+func (c *Client) Capabilities() Capabilities {
+	has := func(capability string) bool {
+		for _, path := range capabilityEndpoints[capability] {
+			if c.checkSupported(path) != nil {
+				return false
+			}
+		}
+		return true
+	}
+	return Capabilities{
+		Vendor:                "bitbrowser",
+		SupportsCookies:       has("cookies"),
+		SupportsHeadless:      has("headless"),
+		SupportsWindowArrange: has("windows"),
+		SupportsProxyCheck:    has("proxyCheck"),
+		SupportsFingerprint:   has("fingerprint"),
+		SupportsGroups:        has("groups"),
+		SupportsPartialUpdate: has("partialUpdate"),
+		SupportsRPA:           has("rpa"),
+		SupportsManagedPorts:  c.portManager.IsActive(),
+		SupportsProcessKill:   c.processKiller != nil,
+		SupportsAppControl:    c.appController != nil,
+	}
+}
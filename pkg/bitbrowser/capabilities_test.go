@@ -0,0 +1,58 @@
+package bitbrowser
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		caps := mustNew(t, "http://127.0.0.1:54345").Capabilities()
+		want := Capabilities{
+			Vendor:                "bitbrowser",
+			SupportsCookies:       true,
+			SupportsHeadless:      true,
+			SupportsWindowArrange: true,
+			SupportsProxyCheck:    true,
+			SupportsFingerprint:   true,
+			SupportsGroups:        true,
+			SupportsPartialUpdate: true,
+			SupportsRPA:           true,
+		}
+		if caps != want {
+			t.Errorf("Capabilities() = %+v, want %+v", caps, want)
+		}
+	})
+
+	t.Run("reflects options", func(t *testing.T) {
+		client := mustNew(t, "http://127.0.0.1:54345",
+			WithPortRange(50000, 50100),
+			WithProcessKiller(LocalProcessKiller),
+			WithAppController(&LocalAppController{}),
+		)
+		caps := client.Capabilities()
+		if !caps.SupportsManagedPorts || !caps.SupportsProcessKill || !caps.SupportsAppControl {
+			t.Errorf("Capabilities() = %+v, want configured features", caps)
+		}
+	})
+
+	t.Run("drops features the version lacks", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		client.GetCookies(context.Background(), "profile-1")
+		caps := client.Capabilities()
+		if caps.SupportsCookies {
+			t.Error("SupportsCookies = true after a 404")
+		}
+		if !caps.SupportsWindowArrange {
+			t.Error("SupportsWindowArrange = false, want other features unaffected")
+		}
+	})
+
+	var _ CapabilityProvider = (*Client)(nil)
+}
@@ -0,0 +1,64 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// WithResponseBodyCapture attaches the raw response body of failed API
+// calls to the returned *APIError as Body, and to the warning logged for
+// the failure. Bodies are redacted like audit events and truncated to
+// maxBytes. Zero or a negative value disables capturing (the default).
+//
+// This helps debugging BitBrowser quirks without a packet sniffer:
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithResponseBodyCapture(4096))
+//	...
+//	var apiErr *bitbrowser.APIError
+//	if errors.As(err, &apiErr) {
+//	    log.Printf("BitBrowser replied: %s", apiErr.Body)
+//	}
+func WithResponseBodyCapture(maxBytes int) ClientOption {
+	return func(c *Client) {
+		c.captureBytes = max(maxBytes, 0)
+	}
+}
+
+// captureBody sets the Body of an *APIError in err's chain from its raw
+// response body, if capturing is enabled.
+func (c *Client) captureBody(err error) error {
+	var apiErr *APIError
+	if c.captureBytes == 0 || !errors.As(err, &apiErr) || apiErr.raw == nil || apiErr.Body != "" {
+		return err
+	}
+	apiErr.Body = redactBody(apiErr.raw, c.captureBytes)
+	return err
+}
+
+// failed reports an unsuccessful response: the body is captured and, when
+// capturing is enabled, the failure is logged with it.
+func (c *Client) failed(ctx context.Context, path string, err error) error {
+	if c.captureBytes == 0 {
+		return err
+	}
+	err = c.captureBody(err)
+	c.logError(ctx, path, err, 0)
+	return err
+}
+
+// redactBody redacts secrets in a JSON body and truncates it to maxBytes.
+// Bodies that are not JSON are only truncated.
+func redactBody(body []byte, maxBytes int) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err == nil {
+		if redacted, err := json.Marshal(redact(v)); err == nil {
+			body = redacted
+		}
+	}
+	if len(body) <= maxBytes {
+		return string(body)
+	}
+	return fmt.Sprintf("%s... (%d bytes truncated)", body[:maxBytes], len(body)-maxBytes)
+}
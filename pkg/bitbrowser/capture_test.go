@@ -0,0 +1,72 @@
+package bitbrowser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResponseBodyCapture(t *testing.T) {
+	t.Run("unsuccessful response", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"success":false,"msg":"weird","data":{"password":"hunter2","code":7}}`))
+		})
+		defer server.Close()
+
+		var buf bytes.Buffer
+		client := mustNew(t, server.URL, WithResponseBodyCapture(1024), WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+		err := client.CloseAll(context.Background())
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("err = %v, want *APIError", err)
+		}
+		if err.Error() != "bitbrowser: close all failed: weird" {
+			t.Errorf("Error() = %q", err.Error())
+		}
+		if !strings.Contains(apiErr.Body, `"code":7`) || strings.Contains(apiErr.Body, "hunter2") {
+			t.Errorf("Body = %q, want redacted body", apiErr.Body)
+		}
+		if !strings.Contains(buf.String(), "body=") {
+			t.Errorf("log does not include the body: %s", buf.String())
+		}
+	})
+
+	t.Run("HTTP error is truncated", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(strings.Repeat("x", 100)))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithResponseBodyCapture(10))
+		_, err := client.GetPorts(context.Background())
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("err = %v, want *APIError", err)
+		}
+		if apiErr.Body != "xxxxxxxxxx... (90 bytes truncated)" {
+			t.Errorf("Body = %q", apiErr.Body)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(errorResponse("weird"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		err := client.CloseAll(context.Background())
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Body != "" {
+			t.Errorf("err = %v, want APIError without body", err)
+		}
+	})
+}
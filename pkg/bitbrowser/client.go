@@ -3,14 +3,21 @@ package bitbrowser
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log/slog"
+	"math/rand/v2"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -38,7 +45,7 @@ const (
 //
 // In this mode:
 //   - SDK randomly selects ports from the range
-//   - Forces binding to 0.0.0.0 for remote access
+//   - Forces binding to 0.0.0.0 (or :: for an IPv6 API host) for remote access
 //   - Automatically retries on port conflicts
 //
 // # Native Mode (default, for local development)
@@ -55,8 +62,50 @@ type Client struct {
 	apiKey      string // API token for authentication (x-api-key header)
 	logger      *slog.Logger
 	retryConfig *RetryConfig
+	retryPolicy EndpointRetryPolicy
+	retryBudget *RetryBudget // Shared retry budget (nil means unlimited)
+	busyPolicy  OpenBusyPolicy
+	timeouts    map[string]time.Duration
+	hedging     *hedger      // Hedged reads (nil means disabled)
+	clock       Clock        // Time source for backoff and polling
+	auditLogger AuditLogger  // Receives mutating operations (nil means disabled)
+	dryRun      bool         // Log mutating requests instead of sending them
+	compression bool         // Ask for gzip-compressed responses
 	portConfig  *PortConfig  // Port management configuration
 	portManager *PortManager // Port manager (nil in Native Mode)
+	publicHost  string       // Host replacing 0.0.0.0 in open results (empty means the API host)
+	launchArgs  []string     // Templated Chrome arguments added to every Open
+	readyHooks  []ReadyHook  // Run after every Open
+	traffic     *trafficBook // Traffic meters of opened profiles (nil means disabled)
+	ready       readyPoller  // Shared GetPorts loop of readiness waits
+	opens       openCache    // Results of GetOrOpen
+	seqs        seqCache     // Profile IDs by window sequence number
+	limit       browserLimit // Cap on running browsers (see WithMaxOpenBrowsers)
+
+	profileLimit int // Plan profile limit for quota checks (0 means unknown)
+	captureBytes int // Response body bytes attached to API errors (0 means disabled)
+
+	processKiller  ProcessKiller   // Kills stuck browser processes (nil means disabled)
+	processMonitor ProcessMonitor  // Reads browser process usage (nil means disabled)
+	appController  AppController   // Starts and stops the BitBrowser app (nil means disabled)
+	portForwarder  PortForwarder   // Reaches loopback-only debug ports (nil means disabled)
+	clipboard      ClipboardWriter // Sets the BitBrowser host's clipboard (nil means disabled)
+	proxyPool      *ProxyPool      // Proxies for AssignProxyMatching (nil means disabled)
+	crashCollector CrashCollector  // Reads crash logs from crashLogDir (nil means disabled)
+	crashLogDir    string          // Host directory for Chrome logs and crash dumps (see WithCrashLogs)
+	unsupported    sync.Map        // Endpoint paths that answered 404, to fail fast
+
+	headers        http.Header           // Extra headers sent with every API request
+	requestEditors []func(*http.Request) // Hooks applied to every API request
+
+	dialContext func(ctx context.Context, network, addr string) (net.Conn, error) // Custom API dialer
+	apiClient   *http.Client                                                      // HTTP client for API requests
+	transport   http.RoundTripper                                                 // Replaces the HTTP client's transport (see WithTransport)
+
+	tlsConfig   *tls.Config // TLS settings for API and debug endpoints
+	tlsCertFile string      // Client certificate for mTLS
+	tlsKeyFile  string      // Client private key for mTLS
+	tlsCAFile   string      // Extra CA bundle to trust
 }
 
 // ClientOption is a function that configures a Client.
@@ -93,6 +142,10 @@ func WithAPIKey(apiKey string) ClientOption {
 //	defer cancel()
 //	client.Open(ctx, id, opts)
 //
+// Calls made without a deadline are bounded by WithEndpointTimeouts. The
+// HTTP client uses DefaultTransport, which is tuned for many concurrent
+// calls to one host; WithTransport replaces it.
+//
 // To customize the HTTP client (e.g., for custom transport or timeouts):
 //
 //	client, err := bitbrowser.New(apiURL, bitbrowser.WithHTTPClient(&http.Client{
@@ -108,15 +161,21 @@ func WithAPIKey(apiKey string) ClientOption {
 func New(apiURL string, opts ...ClientOption) (*Client, error) {
 	c := &Client{
 		apiURL:      strings.TrimRight(apiURL, "/"),
-		httpClient:  &http.Client{}, // No timeout - controlled by context
+		httpClient:  &http.Client{Transport: DefaultTransport()}, // No timeout - controlled by context
 		retryConfig: DefaultRetryConfig(),
+		timeouts:    DefaultEndpointTimeouts(),
 		portConfig:  DefaultPortConfig(),
+		clock:       SystemClock,
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if err := c.buildHTTPClients(); err != nil {
+		return nil, err
+	}
+
 	// Initialize port manager if Managed Mode is enabled
 	if c.portConfig.IsManaged() {
 		// Extract host from API URL for remote port probing
@@ -154,14 +213,7 @@ func New(apiURL string, opts ...ClientOption) (*Client, error) {
 // Health checks if the BitBrowser local server is running.
 // POST /health
 func (c *Client) Health(ctx context.Context) error {
-	var resp Response
-	if err := c.doRequest(ctx, "/health", struct{}{}, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: health check failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: health check returned false")
-	}
-	return nil
+	return c.exec(ctx, "/health", struct{}{})
 }
 
 // ============================================================================
@@ -177,143 +229,293 @@ func (c *Client) CreateProfile(ctx context.Context, config ProfileConfig) (strin
 			CoreVersion: DefaultCoreVersion,
 		}
 	}
+	if err := validateFingerprint(config.BrowserFingerPrint, c.clock.Now()); err != nil {
+		return "", err
+	}
+	if err := validateConfig(&config); err != nil {
+		return "", err
+	}
 
 	var resp Response
 	if err := c.doRequest(ctx, "/browser/update", config, &resp); err != nil {
 		return "", fmt.Errorf("bitbrowser: create profile failed: %w", err)
 	}
 	if !resp.Success {
-		return "", fmt.Errorf("bitbrowser: create profile failed: %s", resp.Msg)
+		return "", c.failed(ctx, "/browser/update", &APIError{Endpoint: "/browser/update", Op: "create profile", Message: resp.Msg, raw: resp.raw})
 	}
 
-	var data struct {
-		ID string `json:"id"`
-	}
+	var data idBody
 	if err := json.Unmarshal(resp.Data, &data); err != nil {
 		return "", fmt.Errorf("bitbrowser: failed to parse response: %w", err)
 	}
 	return data.ID, nil
 }
 
+// CreateProfiles creates several profiles and returns their IDs in order.
+//
+// If a profile limit is configured with WithProfileLimit, the batch is checked
+// against the remaining quota first and rejected with a *QuotaError (matching
+// ErrQuotaExceeded) before any profile is created. On failure, the IDs of the
+// profiles created so far are returned along with the error.
+func (c *Client) CreateProfiles(ctx context.Context, configs []ProfileConfig) ([]string, error) {
+	if err := c.checkQuota(ctx, len(configs)); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(configs))
+	for _, config := range configs {
+		id, err := c.CreateProfile(ctx, config)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// checkQuota fails with a *QuotaError if creating n profiles would exceed
+// the limit set with WithProfileLimit.
+func (c *Client) checkQuota(ctx context.Context, n int) error {
+	if c.profileLimit <= 0 {
+		return nil
+	}
+	quota, err := c.GetQuota(ctx)
+	if err != nil {
+		return err
+	}
+	if n > quota.Remaining {
+		return &QuotaError{Requested: n, Remaining: quota.Remaining, Limit: quota.Limit}
+	}
+	return nil
+}
+
+// GetQuota reports how many profiles exist and, if a limit is configured with
+// WithProfileLimit, how many more can be created.
+//
+// The BitBrowser local API does not expose the account's plan limit, so
+// Limit is 0 and Remaining is -1 unless WithProfileLimit is used.
+func (c *Client) GetQuota(ctx context.Context) (*Quota, error) {
+	result, err := c.ListProfiles(ctx, ListRequest{Page: 0, PageSize: 1})
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: get quota failed: %w", err)
+	}
+
+	quota := &Quota{Used: result.Total, Limit: c.profileLimit, Remaining: -1}
+	if c.profileLimit > 0 {
+		quota.Remaining = max(c.profileLimit-result.Total, 0)
+	}
+	return quota, nil
+}
+
 // UpdateProfile updates an existing browser profile.
 // POST /browser/update
 func (c *Client) UpdateProfile(ctx context.Context, config ProfileConfig) error {
-	if config.ID == "" {
-		return NewValidationError("id", "profile ID is required for update")
+	if err := ValidateProfileID(config.ID); err != nil {
+		return err
 	}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/update", config, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: update profile failed: %w", err)
+	if err := validateFingerprint(config.BrowserFingerPrint, c.clock.Now()); err != nil {
+		return err
 	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: update profile failed: %s", resp.Msg)
+	if err := validateConfig(&config); err != nil {
+		return err
 	}
-	return nil
+	return c.exec(ctx, "/browser/update", config)
 }
 
 // UpdateProfilePartial updates specific fields of one or more profiles.
+// Set req.UpdateFields to send fields with zero values (false, 0, "").
 // POST /browser/update/partial
 func (c *Client) UpdateProfilePartial(ctx context.Context, req PartialUpdateRequest) error {
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/update/partial", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: partial update failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: partial update failed: %s", resp.Msg)
+	if err := req.validate(); err != nil {
+		return err
 	}
-	return nil
+	return c.exec(ctx, "/browser/update/partial", req)
 }
 
 // GetProfileDetail gets detailed information about a browser profile.
 // POST /browser/detail
 func (c *Client) GetProfileDetail(ctx context.Context, id string) (*ProfileDetail, error) {
-	req := struct {
-		ID string `json:"id"`
-	}{ID: id}
+	if err := ValidateProfileID(id); err != nil {
+		return nil, err
+	}
+	req := idBody{ID: id}
 
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/detail", req, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: get profile detail failed: %w", err)
+	detail, err := call[ProfileDetail](ctx, c, "/browser/detail", req)
+	if err != nil {
+		return nil, err
 	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: get profile detail failed: %s", resp.Msg)
+	c.seqs.remember(detail.Seq, detail.ID)
+	return &detail, nil
+}
+
+// defaultDetailConcurrency is the number of concurrent requests of
+// GetProfileDetails unless the caller chooses.
+const defaultDetailConcurrency = 8
+
+// GetProfileDetails gets the details of many profiles with up to
+// concurrency requests in flight (8 if concurrency <= 0), since the API
+// only accepts one ID per call. The result has one entry per ID, in the
+// same order; the entry of a profile that could not be fetched is nil and
+// its failure is reported as a *ProfileError, joined with the others.
+//
+// Example:
+//
+//	details, err := client.GetProfileDetails(ctx, ids, 16)
+//	for _, err := range bitbrowser.ProfileErrors(err) {
+//	    log.Printf("skipping %s: %v", err.ProfileID, err.Err)
+//	}
+func (c *Client) GetProfileDetails(ctx context.Context, ids []string, concurrency int) ([]*ProfileDetail, error) {
+	if concurrency <= 0 {
+		concurrency = defaultDetailConcurrency
+	}
+	details := make([]*ProfileDetail, len(ids))
+	errs := make([]error, len(ids))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = &ProfileError{ProfileID: id, Err: ctx.Err()}
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			detail, err := c.GetProfileDetail(ctx, id)
+			if err != nil {
+				errs[i] = &ProfileError{ProfileID: id, Err: err}
+				return
+			}
+			details[i] = detail
+		}()
 	}
+	wg.Wait()
+	return details, errors.Join(errs...)
+}
 
-	var detail ProfileDetail
-	if err := json.Unmarshal(resp.Data, &detail); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
+// ProfileErrors returns the *ProfileError failures in err, as returned by
+// GetProfileDetails.
+func ProfileErrors(err error) []*ProfileError {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []*ProfileError
+		for _, err := range joined.Unwrap() {
+			errs = append(errs, ProfileErrors(err)...)
+		}
+		return errs
 	}
-	return &detail, nil
+	var profileErr *ProfileError
+	if errors.As(err, &profileErr) {
+		return []*ProfileError{profileErr}
+	}
+	return nil
 }
 
 // ListProfiles gets a paginated list of browser profiles.
 // POST /browser/list
 func (c *Client) ListProfiles(ctx context.Context, req ListRequest) (*ListResult, error) {
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/list", req, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: list profiles failed: %w", err)
-	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: list profiles failed: %s", resp.Msg)
-	}
-
-	var result ListResult
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
+	result, err := call[ListResult](ctx, c, "/browser/list", req)
+	if err != nil {
+		return nil, err
 	}
+	c.seqs.rememberAll(result.List)
 	return &result, nil
 }
 
 // DeleteProfile deletes a single browser profile permanently.
 // POST /browser/delete
 func (c *Client) DeleteProfile(ctx context.Context, id string) error {
-	req := struct {
-		ID string `json:"id"`
-	}{ID: id}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/delete", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: delete profile failed: %w", err)
+	if err := ValidateProfileID(id); err != nil {
+		return err
 	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: delete profile failed: %s", resp.Msg)
+	req := idBody{ID: id}
+	if err := c.exec(ctx, "/browser/delete", req); err != nil {
+		return err
 	}
+	c.seqs.forget(id)
 	return nil
 }
 
 // DeleteProfiles deletes multiple browser profiles permanently (max 100).
 // POST /browser/delete/ids
 func (c *Client) DeleteProfiles(ctx context.Context, ids []string) error {
-	req := struct {
-		IDs []string `json:"ids"`
-	}{IDs: ids}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/delete/ids", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: batch delete failed: %w", err)
+	if err := validateProfileIDs(ids); err != nil {
+		return err
 	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: batch delete failed: %s", resp.Msg)
+	req := idsBody{IDs: ids}
+	if err := c.exec(ctx, "/browser/delete/ids", req); err != nil {
+		return err
 	}
+	c.seqs.forget(ids...)
 	return nil
 }
 
+// maxBatchSize is the largest number of IDs accepted by batch endpoints.
+const maxBatchSize = 100
+
+// DeleteProfilesByFilter deletes every profile matched by filter and returns
+// the number of profiles deleted.
+//
+// All matching IDs are collected before anything is deleted, so deleting does
+// not shift the pages being read. Deletes are sent in batches of at most 100.
+// filter.Page and filter.PageSize are ignored. opts.Confirm must be true.
+//
+// Example:
+//
+//	n, err := client.DeleteProfilesByFilter(ctx, bitbrowser.ListRequest{GroupID: groupID},
+//	    bitbrowser.DeleteOptions{
+//	        Confirm: true,
+//	        Progress: func(p bitbrowser.DeleteProgress) {
+//	            log.Printf("deleted %d/%d", p.Deleted, p.Total)
+//	        },
+//	    })
+func (c *Client) DeleteProfilesByFilter(ctx context.Context, filter ListRequest, opts DeleteOptions) (int, error) {
+	if !opts.Confirm {
+		return 0, NewValidationError("confirm", "DeleteOptions.Confirm must be true to delete profiles")
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 || batchSize > maxBatchSize {
+		batchSize = maxBatchSize
+	}
+
+	var ids []string
+	filter.PageSize = maxBatchSize
+	for page := 0; ; page++ {
+		filter.Page = page
+		result, err := c.ListProfiles(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		for _, p := range result.List {
+			ids = append(ids, p.ID)
+		}
+		if len(result.List) == 0 || len(ids) >= result.Total {
+			break
+		}
+	}
+
+	deleted := 0
+	for batch := range slices.Chunk(ids, batchSize) {
+		if err := c.DeleteProfiles(ctx, batch); err != nil {
+			return deleted, err
+		}
+		deleted += len(batch)
+		if opts.Progress != nil {
+			opts.Progress(DeleteProgress{Batch: batch, Deleted: deleted, Total: len(ids)})
+		}
+	}
+	return deleted, nil
+}
+
 // ResetClosingState resets a profile's closing state when it's stuck.
 // POST /browser/closing/reset
 func (c *Client) ResetClosingState(ctx context.Context, id string) error {
-	req := struct {
-		ID string `json:"id"`
-	}{ID: id}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/closing/reset", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: reset closing state failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: reset closing state failed: %s", resp.Msg)
+	if err := ValidateProfileID(id); err != nil {
+		return err
 	}
-	return nil
+	req := idBody{ID: id}
+	return c.exec(ctx, "/browser/closing/reset", req)
 }
 
 // ============================================================================
@@ -323,13 +525,17 @@ func (c *Client) ResetClosingState(ctx context.Context, id string) error {
 // Open opens a browser instance with the specified options.
 // This is the recommended method for opening browsers with convenient options.
 //
+// If a step after the launch fails, such as checking the endpoint or a
+// ready hook, Open returns the result together with the error: the browser
+// is running, and callers that give up on it should Close it.
+//
 // # Port Management Modes
 //
 // The behavior depends on whether Managed Mode is enabled:
 //
 // Managed Mode (WithPortRange configured):
 //   - SDK allocates a port from the configured range
-//   - Automatically binds to 0.0.0.0 for remote access
+//   - Automatically binds to 0.0.0.0 (or :: for an IPv6 API host) for remote access
 //   - Retries with different ports on conflict
 //   - opts.CustomPort and opts.AllowLAN are ignored
 //
@@ -338,6 +544,11 @@ func (c *Client) ResetClosingState(ctx context.Context, id string) error {
 //   - opts.CustomPort and opts.AllowLAN are respected
 //   - WARNING: May return 127.0.0.1 which is unreachable remotely
 //
+// A wildcard host (0.0.0.0 or ::) in the returned endpoints is replaced by
+// the host set with WithPublicHost, or else the API host, and the endpoint
+// is checked. If it is unreachable, the result is returned together with a
+// *NetworkError, as the browser is already open.
+//
 // Example:
 //
 //	result, err := client.Open(ctx, "profile-id", &bitbrowser.OpenOptions{
@@ -345,11 +556,192 @@ func (c *Client) ResetClosingState(ctx context.Context, id string) error {
 //	    IgnoreDefaultUrls: true,
 //	    WaitReady:         true,
 //	})
+//
+// # Proxy Override
+//
+// When opts.ProxyOverride is set, the profile's proxy is updated right before
+// opening. With ProxyOverride.Restore, the previous proxy is written back after
+// the open attempt. If the browser opened but restoring failed, both the
+// result and an error are returned so the caller can keep the browser while
+// knowing the profile still carries the override.
+//
+// # Ready Hooks
+//
+// Hooks registered with WithReadyHook and set in opts.OnReady run once the
+// browser is open, on a DevTools session attached to its first page. If a
+// hook fails, the result is returned with the error, as the browser is
+// already open.
+//
+// # Start URL
+//
+// opts.StartURL may be a text/template over the profile's ProfileDetail,
+// such as "https://example.com/ref/{{.Seq}}", rendered on every Open. The
+// details are only fetched when the URL has template actions.
+//
+// # Viewport
+//
+// With opts.RandomizeViewport, each Open picks a realistic window size and
+// launches the browser with it, or emulates it over DevTools; the size is
+// reported in OpenResult.Viewport.
+//
+// # Browser Limit
+//
+// With WithMaxOpenBrowsers, opening a profile that is not running fails with
+// an error matching ErrNoCapacity once the cap is reached, or waits for a
+// browser to close if opts.WaitForCapacity is set.
+//
+// # Deadlines
+//
+// If ctx has a deadline, it is divided across the steps of Open with a
+// Budget: opening (with retries), waiting for readiness, checking the
+// endpoint and running ready hooks. A step that runs out of time returns a
+// *TimeoutError naming it. Without a deadline, the "/browser/open" timeout
+// of WithEndpointTimeouts (120 seconds by default) applies once a browser
+// slot is acquired.
 func (c *Client) Open(ctx context.Context, id string, opts *OpenOptions) (*OpenResult, error) {
+	if err := ValidateProfileID(id); err != nil {
+		return nil, err
+	}
 	if opts == nil {
 		opts = &OpenOptions{}
 	}
 
+	// Dry run: skip port management, proxy overrides and readiness polling
+	if c.dryRun {
+		return c.OpenRaw(ctx, OpenConfig{ID: id})
+	}
+	opts, err := c.renderStartURL(ctx, id, opts)
+	if err != nil {
+		return nil, err
+	}
+	var viewport Viewport
+	if opts.RandomizeViewport != nil {
+		if opts, viewport, err = randomizeViewport(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	release, err := c.acquireBrowserSlot(ctx, id, opts.WaitForCapacity)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.endpointTimeout(ctx, "/browser/open")
+	defer cancel()
+	var result *OpenResult
+	if opts.ProxyOverride != nil {
+		result, err = c.openWithProxyOverride(ctx, id, opts)
+	} else {
+		result, err = c.open(ctx, id, opts)
+	}
+	release(result != nil)
+	if result != nil {
+		result.Viewport = viewport
+	}
+	return result, err
+}
+
+// openSteps are the steps of open, weighted by how long they may take.
+var openSteps = []BudgetStep{
+	{Name: "open", Weight: 6},
+	{Name: "wait_ready", Weight: 2},
+	{Name: "check_endpoint", Weight: 1},
+	{Name: "ready_hooks", Weight: 1},
+}
+
+// open opens the browser and makes wildcard hosts in the result dialable.
+// If ctx has a deadline, it is divided across the steps with a Budget.
+func (c *Client) open(ctx context.Context, id string, opts *OpenOptions) (*OpenResult, error) {
+	budget := NewBudget("open", openSteps...)
+	var result *OpenResult
+	err := budget.Run(ctx, "open", func(ctx context.Context) (err error) {
+		result, err = c.openRetrying(ctx, id, opts)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opts.WaitReady && result.Ws == "" {
+		err := budget.Run(ctx, "wait_ready", func(ctx context.Context) error {
+			ready, err := c.waitForBrowserReady(ctx, id, opts)
+			if err == nil {
+				result = ready
+			}
+			return err
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+	err = budget.Run(ctx, "check_endpoint", func(ctx context.Context) (err error) {
+		result, err = c.dialableResult(ctx, result)
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+	err = budget.Run(ctx, "ready_hooks", func(ctx context.Context) error {
+		return c.runReadyHooks(ctx, id, result, opts)
+	})
+	if err != nil {
+		return result, err
+	}
+	if headlessStartURL(opts) {
+		c.navigateStartURL(ctx, result, opts.StartURL)
+	}
+	return result, nil
+}
+
+// openRetrying opens the browser, retrying according to opts.RetryPolicy
+// or, if that is not set, waiting according to the busy policy while
+// BitBrowser reports that the profile is busy.
+func (c *Client) openRetrying(ctx context.Context, id string, opts *OpenOptions) (*OpenResult, error) {
+	if opts.RetryPolicy != nil {
+		r := newRetryer(opts.RetryPolicy.retryConfig())
+		r.clock = c.clock
+
+		var result *OpenResult
+		err := r.do(ctx, func() error {
+			var err error
+			result, err = c.openOnce(ctx, id, opts)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	policy := c.busyPolicy
+	if !policy.Wait {
+		return c.openOnce(ctx, id, opts)
+	}
+
+	deadline := c.clock.Now().Add(policy.maxWait())
+	for {
+		result, err := c.openOnce(ctx, id, opts)
+		if err == nil || !errors.Is(err, ErrBusy) {
+			return result, err
+		}
+
+		delay := policy.interval()
+		if suggested := retryAfter(err); suggested > 0 {
+			delay = suggested
+		}
+		if c.clock.Now().Add(delay).After(deadline) {
+			return nil, err
+		}
+
+		c.logRetry(ctx, "/browser/open", 0, delay, err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-c.clock.After(delay):
+		}
+	}
+}
+
+// openOnce dispatches to Managed Mode or Native Mode.
+func (c *Client) openOnce(ctx context.Context, id string, opts *OpenOptions) (*OpenResult, error) {
 	// Check if Managed Mode is active
 	if c.portManager != nil && c.portManager.IsActive() {
 		return c.openWithManagedPort(ctx, id, opts)
@@ -359,44 +751,109 @@ func (c *Client) Open(ctx context.Context, id string, opts *OpenOptions) (*OpenR
 	return c.openNative(ctx, id, opts)
 }
 
+// openWithProxyOverride applies opts.ProxyOverride, opens the browser and
+// optionally restores the previous proxy.
+func (c *Client) openWithProxyOverride(ctx context.Context, id string, opts *OpenOptions) (*OpenResult, error) {
+	override := opts.ProxyOverride
+
+	var previous *ProxyUpdateRequest
+	if override.Restore {
+		detail, err := c.GetProfileDetail(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("bitbrowser: proxy override failed to read current proxy: %w", err)
+		}
+		previous = &ProxyUpdateRequest{
+			IDs:                 []string{id},
+			IpCheckService:      detail.IpCheckService,
+			ProxyMethod:         detail.ProxyMethod,
+			ProxyType:           detail.ProxyType,
+			Host:                detail.Host,
+			Port:                detail.Port,
+			ProxyUserName:       detail.ProxyUserName,
+			ProxyPassword:       detail.ProxyPassword,
+			RefreshProxyUrl:     detail.RefreshProxyUrl,
+			DynamicIpUrl:        detail.DynamicIpUrl,
+			DynamicIpChannel:    detail.DynamicIpChannel,
+			IsDynamicIpChangeIp: detail.IsDynamicIpChangeIp,
+			IsIpv6:              detail.IsIpv6,
+		}
+	}
+
+	method := override.ProxyMethod
+	if method == 0 {
+		method = ProxyMethodCustom
+	}
+	if err := c.UpdateProxy(ctx, ProxyUpdateRequest{
+		IDs:              []string{id},
+		ProxyMethod:      method,
+		ProxyType:        override.ProxyType,
+		Host:             override.Host,
+		Port:             override.Port,
+		ProxyUserName:    override.ProxyUserName,
+		ProxyPassword:    override.ProxyPassword,
+		DynamicIpUrl:     override.DynamicIpUrl,
+		DynamicIpChannel: override.DynamicIpChannel,
+	}); err != nil {
+		return nil, fmt.Errorf("bitbrowser: proxy override failed: %w", err)
+	}
+
+	result, openErr := c.open(ctx, id, opts)
+
+	if previous != nil {
+		// Restore even if the caller's context was canceled during open,
+		// otherwise the override would stick to the profile.
+		if err := c.UpdateProxy(context.WithoutCancel(ctx), *previous); err != nil {
+			restoreErr := fmt.Errorf("bitbrowser: failed to restore proxy for profile %s: %w", id, err)
+			if openErr != nil {
+				return nil, errors.Join(openErr, restoreErr)
+			}
+			return result, restoreErr
+		}
+	}
+
+	return result, openErr
+}
+
 // openWithManagedPort opens a browser with SDK-managed port allocation.
 // It allocates a port from the configured range and opens the browser.
 // If the browser is already open, BitBrowser API will return the existing connection info.
 func (c *Client) openWithManagedPort(ctx context.Context, id string, opts *OpenOptions) (*OpenResult, error) {
 	// Get ports currently used by BitBrowser to avoid conflicts
 	usedPorts := make(map[int]bool)
-	if ports, err := c.GetPorts(ctx); err == nil {
-		for _, portStr := range ports {
-			if portStr == "" {
-				continue
-			}
-			var port int
-			if _, err := fmt.Sscanf(portStr, "%d", &port); err == nil && port > 0 {
+	if c.portConfig.ProbeMode.usesAPI() {
+		if ports, err := c.GetPorts(ctx); err == nil {
+			for _, port := range ports {
 				usedPorts[port] = true
 			}
 		}
 	}
 
 	// Pick an available port
-	port, err := c.portManager.PickPortExcluding(usedPorts)
+	port, err := c.portManager.PickPortContext(ctx, usedPorts)
 	if err != nil {
 		return nil, fmt.Errorf("bitbrowser: failed to allocate port: %w", err)
 	}
 
+	opts, err = c.resolveLaunchArgs(ctx, id, port, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build Chrome arguments with managed port
-	args := c.buildManagedArgs(port, opts)
+	args := c.buildManagedArgs(port, c.portManager.BindAddress(ctx), opts)
+	if !opts.SkipArgValidation {
+		if err := ValidateChromeArgs(args); err != nil {
+			return nil, err
+		}
+	}
 
 	// Build request
-	// Note: In headless mode, NewPageUrl must be empty (official doc requirement)
-	startURL := opts.StartURL
-	if opts.Headless {
-		startURL = ""
-	}
+	ignoreDefaultUrls, startURL := openURLs(opts)
 	config := OpenConfig{
 		ID:                id,
 		Args:              args,
 		Queue:             true,
-		IgnoreDefaultUrls: opts.IgnoreDefaultUrls || opts.Headless,
+		IgnoreDefaultUrls: ignoreDefaultUrls,
 		NewPageUrl:        startURL,
 	}
 
@@ -415,20 +872,26 @@ func (c *Client) openWithManagedPort(ctx context.Context, id string, opts *OpenO
 // openNative opens a browser using Native Mode (BitBrowser-managed ports).
 // If the browser is already open, BitBrowser API will return the existing connection info.
 func (c *Client) openNative(ctx context.Context, id string, opts *OpenOptions) (*OpenResult, error) {
+	opts, err := c.resolveLaunchArgs(ctx, id, opts.CustomPort, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build Chrome arguments from options
-	args := c.buildNativeArgs(opts)
+	args := c.buildNativeArgs(ctx, opts)
+	if !opts.SkipArgValidation {
+		if err := ValidateChromeArgs(args); err != nil {
+			return nil, err
+		}
+	}
 
 	// Build request
-	// Note: In headless mode, NewPageUrl must be empty (official doc requirement)
-	startURL := opts.StartURL
-	if opts.Headless {
-		startURL = ""
-	}
+	ignoreDefaultUrls, startURL := openURLs(opts)
 	config := OpenConfig{
 		ID:                id,
 		Args:              args,
 		Queue:             true,
-		IgnoreDefaultUrls: opts.IgnoreDefaultUrls || opts.Headless,
+		IgnoreDefaultUrls: ignoreDefaultUrls,
 		NewPageUrl:        startURL,
 	}
 
@@ -437,7 +900,7 @@ func (c *Client) openNative(ctx context.Context, id string, opts *OpenOptions) (
 		return nil, fmt.Errorf("bitbrowser: open browser failed: %w", err)
 	}
 	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: open browser failed: %s", resp.Msg)
+		return nil, fmt.Errorf("bitbrowser: open browser failed: %w", c.openFailed(ctx, &resp))
 	}
 
 	var result OpenResult
@@ -455,13 +918,14 @@ func (c *Client) openNative(ctx context.Context, id string, opts *OpenOptions) (
 
 
 // buildManagedArgs builds Chrome arguments for Managed Mode.
-// It always includes port binding to 0.0.0.0 for remote access.
-func (c *Client) buildManagedArgs(port int, opts *OpenOptions) []string {
+// It always binds the port to all interfaces (0.0.0.0, or :: for an IPv6
+// API host) for remote access.
+func (c *Client) buildManagedArgs(port int, bindAddress string, opts *OpenOptions) []string {
 	var args []string
 
-	// Managed port and address (always 0.0.0.0 for remote access)
+	// Managed port and address (always all interfaces for remote access)
 	args = append(args, fmt.Sprintf("--remote-debugging-port=%d", port))
-	args = append(args, "--remote-debugging-address=0.0.0.0")
+	args = append(args, "--remote-debugging-address="+bindAddress)
 
 	// Headless mode
 	if opts.Headless {
@@ -491,7 +955,7 @@ func (c *Client) buildManagedArgs(port int, opts *OpenOptions) []string {
 
 // buildNativeArgs builds Chrome arguments for Native Mode.
 // It respects user-specified CustomPort and AllowLAN options.
-func (c *Client) buildNativeArgs(opts *OpenOptions) []string {
+func (c *Client) buildNativeArgs(ctx context.Context, opts *OpenOptions) []string {
 	var args []string
 
 	// Custom port (user-specified)
@@ -501,7 +965,7 @@ func (c *Client) buildNativeArgs(opts *OpenOptions) []string {
 
 	// Allow LAN access (user-specified)
 	if opts.AllowLAN {
-		args = append(args, "--remote-debugging-address=0.0.0.0")
+		args = append(args, "--remote-debugging-address="+c.bindAddress(ctx))
 	}
 
 	// Headless mode
@@ -537,7 +1001,7 @@ func (c *Client) doOpenRequest(ctx context.Context, config OpenConfig) (*OpenRes
 		return nil, err
 	}
 	if !resp.Success {
-		return nil, fmt.Errorf("%s", resp.Msg)
+		return nil, c.openFailed(ctx, &resp)
 	}
 
 	var result OpenResult
@@ -549,16 +1013,50 @@ func (c *Client) doOpenRequest(ctx context.Context, config OpenConfig) (*OpenRes
 }
 
 
+// openFailure converts an unsuccessful /browser/open response into an error.
+// Busy responses become an *APIError matching ErrBusy so that callers and
+// the OpenBusyPolicy can recognize them; other known messages become an
+// *APIError matching their sentinel, such as ErrKernelDownloading.
+func openFailure(msg string) error {
+	if isBusyMessage(msg) {
+		return &APIError{Endpoint: "/browser/open", Message: msg, Busy: true}
+	}
+	if classifyMessage(msg) != nil {
+		return &APIError{Endpoint: "/browser/open", Message: msg}
+	}
+	return errors.New(msg)
+}
+
+// openFailed is openFailure with the response body kept for
+// WithResponseBodyCapture.
+func (c *Client) openFailed(ctx context.Context, resp *Response) error {
+	err := openFailure(resp.Msg)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.raw = resp.raw
+	}
+	return c.failed(ctx, "/browser/open", err)
+}
+
 // OpenRaw opens a browser using the raw API configuration.
 // Use this when you need full control over the request parameters.
 // For most cases, prefer using Open with OpenOptions instead.
+// config.NewPageUrl may be a template, as OpenOptions.StartURL.
 func (c *Client) OpenRaw(ctx context.Context, config OpenConfig) (*OpenResult, error) {
+	if err := ValidateProfileID(config.ID); err != nil {
+		return nil, err
+	}
+	url, err := c.renderURL(ctx, config.ID, "NewPageUrl", config.NewPageUrl)
+	if err != nil {
+		return nil, err
+	}
+	config.NewPageUrl = url
 	var resp Response
 	if err := c.doRequest(ctx, "/browser/open", config, &resp); err != nil {
 		return nil, fmt.Errorf("bitbrowser: open browser failed: %w", err)
 	}
 	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: open browser failed: %s", resp.Msg)
+		return nil, fmt.Errorf("bitbrowser: open browser failed: %w", c.openFailed(ctx, &resp))
 	}
 
 	var result OpenResult
@@ -574,60 +1072,76 @@ func (c *Client) OpenRaw(ctx context.Context, config OpenConfig) (*OpenResult, e
 	return &result, nil
 }
 
-// waitForBrowserReady polls until the browser is ready.
+// waitForBrowserReady waits until the browser is ready, polling GetPorts
+// in the client's shared loop.
 func (c *Client) waitForBrowserReady(ctx context.Context, id string, opts *OpenOptions) (*OpenResult, error) {
-	timeout := opts.WaitTimeout
-	if timeout <= 0 {
-		timeout = 30 // Default 30 seconds
+	timeout := opts.waitTimeout()
+	port, ok, err := c.waitForPort(ctx, id, opts.pollInterval(), timeout, opts.PollJitter)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, NewTimeoutError("wait_for_browser_ready", timeout.String(), nil)
 	}
 
-	pollIntervalSec := opts.PollInterval
-	if pollIntervalSec <= 0 {
-		pollIntervalSec = 2 // Default 2 seconds
+	// Browser is ready, construct result
+	httpEndpoint := "http://127.0.0.1:" + strconv.Itoa(port)
+	if opts.AllowLAN {
+		httpEndpoint = "http://" + net.JoinHostPort(c.bindAddress(ctx), strconv.Itoa(port))
 	}
-	pollInterval := time.Duration(pollIntervalSec) * time.Second
 
-	maxAttempts := max(timeout/pollIntervalSec, 1)
+	// Get WebSocket URL from browser
+	version, verr := c.GetBrowserVersion(ctx, httpEndpoint)
+	if verr == nil && version.WebSocketDebuggerURL != "" {
+		return &OpenResult{
+			Http: httpEndpoint,
+			Ws:   version.WebSocketDebuggerURL,
+		}, nil
+	}
 
-	for range maxAttempts {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-time.After(pollInterval):
-		}
+	return &OpenResult{
+		Http: httpEndpoint,
+	}, nil
+}
 
-		// Try to get browser ports to check if it's ready
-		ports, err := c.GetPorts(ctx)
-		if err == nil {
-			if port, ok := ports[id]; ok && port != "" {
-				// Browser is ready, construct result
-				httpEndpoint := "http://127.0.0.1:" + port
-				if opts.AllowLAN {
-					httpEndpoint = "http://0.0.0.0:" + port
-				}
-
-				// Get WebSocket URL from browser
-				version, verr := c.GetBrowserVersion(ctx, httpEndpoint)
-				if verr == nil && version.WebSocketDebuggerURL != "" {
-					return &OpenResult{
-						Http: httpEndpoint,
-						Ws:   version.WebSocketDebuggerURL,
-					}, nil
-				}
-
-				return &OpenResult{
-					Http: httpEndpoint,
-				}, nil
-			}
-		}
+// waitTimeout returns the WaitReady timeout or its default.
+func (o *OpenOptions) waitTimeout() time.Duration {
+	switch {
+	case o.WaitTimeoutDuration > 0:
+		return o.WaitTimeoutDuration
+	case o.WaitTimeout > 0:
+		return time.Duration(o.WaitTimeout) * time.Second
 	}
+	return 30 * time.Second
+}
 
-	return nil, NewTimeoutError("wait_for_browser_ready", (time.Duration(timeout) * time.Second).String(), nil)
+// pollInterval returns the WaitReady poll interval or its default.
+func (o *OpenOptions) pollInterval() time.Duration {
+	switch {
+	case o.PollIntervalDuration > 0:
+		return o.PollIntervalDuration
+	case o.PollInterval > 0:
+		return time.Duration(o.PollInterval) * time.Second
+	}
+	return 2 * time.Second
+}
+
+// jitter returns d moved randomly by up to fraction of d in either
+// direction.
+func jitter(d time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return d
+	}
+	fraction = min(fraction, 1)
+	return time.Duration(float64(d) * (1 - fraction + rand.Float64()*2*fraction))
 }
 
 // WaitForReady waits until the browser is fully ready and returns connection info.
 // This is useful when you need to ensure the browser is ready before connecting.
 func (c *Client) WaitForReady(ctx context.Context, id string, timeoutSeconds int) (*OpenResult, error) {
+	if err := ValidateProfileID(id); err != nil {
+		return nil, err
+	}
 	if timeoutSeconds <= 0 {
 		timeoutSeconds = 30
 	}
@@ -710,47 +1224,33 @@ func (c *Client) GetBrowserVersion(ctx context.Context, httpEndpoint string) (*B
 // POST /browser/close
 // Note: Wait at least 5 seconds before reopening or deleting the profile.
 func (c *Client) Close(ctx context.Context, id string) error {
-	req := struct {
-		ID string `json:"id"`
-	}{ID: id}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/close", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: close browser failed: %w", err)
+	if err := ValidateProfileID(id); err != nil {
+		return err
 	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: close browser failed: %s", resp.Msg)
+	c.opens.forget(id)
+	req := idBody{ID: id}
+	if err := c.exec(ctx, "/browser/close", req); err != nil {
+		return err
 	}
+	c.forgetOpened(id)
 	return nil
 }
 
 // CloseBySeqs closes browsers by their sequence numbers.
 // POST /browser/close/byseqs
 func (c *Client) CloseBySeqs(ctx context.Context, seqs []int) error {
-	req := struct {
-		Seqs []int `json:"seqs"`
-	}{Seqs: seqs}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/close/byseqs", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: close by seqs failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: close by seqs failed: %s", resp.Msg)
-	}
-	return nil
+	req := seqsBody{Seqs: seqs}
+	return c.exec(ctx, "/browser/close/byseqs", req)
 }
 
 // CloseAll closes all open browser windows.
 // POST /browser/close/all
 func (c *Client) CloseAll(ctx context.Context) error {
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/close/all", struct{}{}, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: close all failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: close all failed: %s", resp.Msg)
+	c.opens.forgetAll()
+	if err := c.exec(ctx, "/browser/close/all", struct{}{}); err != nil {
+		return err
 	}
+	c.forgetOpened()
 	return nil
 }
 
@@ -761,114 +1261,69 @@ func (c *Client) CloseAll(ctx context.Context) error {
 // GetPIDs gets the process IDs for the specified browser profiles.
 // POST /browser/pids
 func (c *Client) GetPIDs(ctx context.Context, ids []string) (map[string]int, error) {
-	req := struct {
-		IDs []string `json:"ids"`
-	}{IDs: ids}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/pids", req, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: get pids failed: %w", err)
-	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: get pids failed: %s", resp.Msg)
-	}
-
-	var result map[string]int
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
+	if err := validateProfileIDs(ids); err != nil {
+		return nil, err
 	}
-	return result, nil
+	req := idsBody{IDs: ids}
+	return call[map[string]int](ctx, c, "/browser/pids", req)
 }
 
 // GetAllPIDs gets all running browser process IDs.
 // POST /browser/pids/all
 func (c *Client) GetAllPIDs(ctx context.Context) (map[string]int, error) {
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/pids/all", struct{}{}, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: get all pids failed: %w", err)
-	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: get all pids failed: %s", resp.Msg)
-	}
-
-	var result map[string]int
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
-	}
-	return result, nil
+	return call[map[string]int](ctx, c, "/browser/pids/all", struct{}{})
 }
 
 // GetAlivePIDs gets alive process IDs for the specified profiles.
 // POST /browser/pids/alive
 func (c *Client) GetAlivePIDs(ctx context.Context, ids []string) (map[string]int, error) {
-	req := struct {
-		IDs []string `json:"ids"`
-	}{IDs: ids}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/pids/alive", req, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: get alive pids failed: %w", err)
-	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: get alive pids failed: %s", resp.Msg)
-	}
-
-	var result map[string]int
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
+	if err := validateProfileIDs(ids); err != nil {
+		return nil, err
 	}
-	return result, nil
+	req := idsBody{IDs: ids}
+	return call[map[string]int](ctx, c, "/browser/pids/alive", req)
 }
 
-// GetPorts gets the debugging ports for all open browsers.
+// GetPorts gets the debugging ports for all open browsers, keyed by
+// profile ID. Browsers that have not reported a port yet are left out.
 // POST /browser/ports
-func (c *Client) GetPorts(ctx context.Context) (map[string]string, error) {
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/ports", struct{}{}, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: get ports failed: %w", err)
-	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: get ports failed: %s", resp.Msg)
-	}
-
-	var result map[string]string
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
+func (c *Client) GetPorts(ctx context.Context) (map[string]int, error) {
+	raw, err := call[map[string]string](ctx, c, "/browser/ports", struct{}{})
+	if err != nil {
+		return nil, err
 	}
-	return result, nil
+	return parsePorts(raw)
 }
 
 // ============================================================================
 // Proxy Management
 // ============================================================================
 
-// UpdateProxy updates proxy settings for multiple profiles.
+// UpdateProxy updates proxy settings for multiple profiles. Inconsistent
+// settings, such as ProxyMethodExtract without a DynamicIpUrl, are rejected
+// with a ValidationError before the request is sent.
 // POST /browser/proxy/update
 func (c *Client) UpdateProxy(ctx context.Context, req ProxyUpdateRequest) error {
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/proxy/update", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: update proxy failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: update proxy failed: %s", resp.Msg)
+	err := validateProxy(proxySettings{
+		method:       req.ProxyMethod,
+		proxyType:    req.ProxyType,
+		host:         req.Host,
+		port:         req.Port,
+		password:     req.ProxyPassword,
+		dynamicIpUrl: req.DynamicIpUrl,
+	})
+	if err != nil {
+		return err
 	}
-	return nil
+	return c.exec(ctx, "/browser/proxy/update", req)
 }
 
 // CheckProxy checks if a proxy is working and gets its information.
 // POST /checkagent
 func (c *Client) CheckProxy(ctx context.Context, req ProxyCheckRequest) (*ProxyCheckResult, error) {
-	var resp Response
-	if err := c.doRequest(ctx, "/checkagent", req, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: check proxy failed: %w", err)
-	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: check proxy failed: %s", resp.Msg)
-	}
-
-	var result ProxyCheckResult
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
+	result, err := call[ProxyCheckResult](ctx, c, "/checkagent", req)
+	if err != nil {
+		return nil, err
 	}
 	return &result, nil
 }
@@ -880,43 +1335,21 @@ func (c *Client) CheckProxy(ctx context.Context, req ProxyCheckRequest) (*ProxyC
 // UpdateGroup moves profiles to a specified group.
 // POST /browser/group/update
 func (c *Client) UpdateGroup(ctx context.Context, groupID string, browserIDs []string) error {
-	req := struct {
-		GroupID    string   `json:"groupId"`
-		BrowserIDs []string `json:"browserIds"`
-	}{
+	req := groupBody{
 		GroupID:    groupID,
 		BrowserIDs: browserIDs,
 	}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/group/update", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: update group failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: update group failed: %s", resp.Msg)
-	}
-	return nil
+	return c.exec(ctx, "/browser/group/update", req)
 }
 
 // UpdateRemark updates the remark for multiple profiles.
 // POST /browser/remark/update
 func (c *Client) UpdateRemark(ctx context.Context, remark string, browserIDs []string) error {
-	req := struct {
-		Remark     string   `json:"remark"`
-		BrowserIDs []string `json:"browserIds"`
-	}{
+	req := remarkBody{
 		Remark:     remark,
 		BrowserIDs: browserIDs,
 	}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/remark/update", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: update remark failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: update remark failed: %s", resp.Msg)
-	}
-	return nil
+	return c.exec(ctx, "/browser/remark/update", req)
 }
 
 // ============================================================================
@@ -926,31 +1359,14 @@ func (c *Client) UpdateRemark(ctx context.Context, remark string, browserIDs []s
 // ArrangeWindows arranges browser windows according to the specified layout.
 // POST /windowbounds
 func (c *Client) ArrangeWindows(ctx context.Context, req WindowBoundsRequest) error {
-	var resp Response
-	if err := c.doRequest(ctx, "/windowbounds", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: arrange windows failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: arrange windows failed: %s", resp.Msg)
-	}
-	return nil
+	return c.exec(ctx, "/windowbounds", req)
 }
 
 // ArrangeWindowsFlexible auto-arranges windows flexibly.
 // POST /windowbounds/flexable
 func (c *Client) ArrangeWindowsFlexible(ctx context.Context, seqList []int) error {
-	req := struct {
-		SeqList []int `json:"seqlist"`
-	}{SeqList: seqList}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/windowbounds/flexable", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: flexible arrange failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: flexible arrange failed: %s", resp.Msg)
-	}
-	return nil
+	req := seqListBody{SeqList: seqList}
+	return c.exec(ctx, "/windowbounds/flexable", req)
 }
 
 // ============================================================================
@@ -960,35 +1376,21 @@ func (c *Client) ArrangeWindowsFlexible(ctx context.Context, seqList []int) erro
 // ClearCache clears all cache for the specified profiles.
 // POST /cache/clear
 func (c *Client) ClearCache(ctx context.Context, ids []string) error {
-	req := struct {
-		IDs []string `json:"ids"`
-	}{IDs: ids}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/cache/clear", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: clear cache failed: %w", err)
+	if err := validateProfileIDs(ids); err != nil {
+		return err
 	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: clear cache failed: %s", resp.Msg)
-	}
-	return nil
+	req := idsBody{IDs: ids}
+	return c.exec(ctx, "/cache/clear", req)
 }
 
 // ClearCacheExceptExtensions clears cache but keeps extension data.
 // POST /cache/clear/exceptExtensions
 func (c *Client) ClearCacheExceptExtensions(ctx context.Context, ids []string) error {
-	req := struct {
-		IDs []string `json:"ids"`
-	}{IDs: ids}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/cache/clear/exceptExtensions", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: clear cache except extensions failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: clear cache except extensions failed: %s", resp.Msg)
+	if err := validateProfileIDs(ids); err != nil {
+		return err
 	}
-	return nil
+	req := idsBody{IDs: ids}
+	return c.exec(ctx, "/cache/clear/exceptExtensions", req)
 }
 
 // ============================================================================
@@ -998,21 +1400,14 @@ func (c *Client) ClearCacheExceptExtensions(ctx context.Context, ids []string) e
 // RandomizeFingerprint randomizes the fingerprint for a profile.
 // POST /browser/fingerprint/random
 func (c *Client) RandomizeFingerprint(ctx context.Context, browserID string) (*Fingerprint, error) {
-	req := struct {
-		BrowserID string `json:"browserId"`
-	}{BrowserID: browserID}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/fingerprint/random", req, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: randomize fingerprint failed: %w", err)
-	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: randomize fingerprint failed: %s", resp.Msg)
+	if err := ValidateProfileID(browserID); err != nil {
+		return nil, err
 	}
+	req := browserIDBody{BrowserID: browserID}
 
-	var result Fingerprint
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
+	result, err := call[Fingerprint](ctx, c, "/browser/fingerprint/random", req)
+	if err != nil {
+		return nil, err
 	}
 	return &result, nil
 }
@@ -1024,59 +1419,37 @@ func (c *Client) RandomizeFingerprint(ctx context.Context, browserID string) (*F
 // SetCookies sets cookies for an open browser.
 // POST /browser/cookies/set
 func (c *Client) SetCookies(ctx context.Context, browserID string, cookies []Cookie) error {
+	if err := ValidateProfileID(browserID); err != nil {
+		return err
+	}
 	req := SetCookiesRequest{
 		BrowserID: browserID,
 		Cookies:   cookies,
 	}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/cookies/set", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: set cookies failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: set cookies failed: %s", resp.Msg)
-	}
-	return nil
+	return c.exec(ctx, "/browser/cookies/set", req)
 }
 
 // GetCookies gets real-time cookies from an open browser.
 // POST /browser/cookies/get
 func (c *Client) GetCookies(ctx context.Context, browserID string) ([]Cookie, error) {
-	req := struct {
-		BrowserID string `json:"browserId"`
-	}{BrowserID: browserID}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/cookies/get", req, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: get cookies failed: %w", err)
-	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: get cookies failed: %s", resp.Msg)
-	}
-
-	var result []Cookie
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
+	if err := ValidateProfileID(browserID); err != nil {
+		return nil, err
 	}
-	return result, nil
+	req := browserIDBody{BrowserID: browserID}
+	return call[[]Cookie](ctx, c, "/browser/cookies/get", req)
 }
 
 // ClearCookies clears cookies for a profile.
 // POST /browser/cookies/clear
 func (c *Client) ClearCookies(ctx context.Context, browserID string, saveSynced bool) error {
+	if err := ValidateProfileID(browserID); err != nil {
+		return err
+	}
 	req := ClearCookiesRequest{
 		BrowserID:  browserID,
 		SaveSynced: saveSynced,
 	}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/cookies/clear", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: clear cookies failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: clear cookies failed: %s", resp.Msg)
-	}
-	return nil
+	return c.exec(ctx, "/browser/cookies/clear", req)
 }
 
 // FormatCookies formats cookies to standard format.
@@ -1086,20 +1459,7 @@ func (c *Client) FormatCookies(ctx context.Context, cookie any, hostname string)
 		Cookie:   cookie,
 		Hostname: hostname,
 	}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/browser/cookies/format", req, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: format cookies failed: %w", err)
-	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: format cookies failed: %s", resp.Msg)
-	}
-
-	var result []Cookie
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
-	}
-	return result, nil
+	return call[[]Cookie](ctx, c, "/browser/cookies/format", req)
 }
 
 // ============================================================================
@@ -1109,19 +1469,7 @@ func (c *Client) FormatCookies(ctx context.Context, cookie any, hostname string)
 // GetAllDisplays gets information about all connected displays.
 // POST /alldisplays
 func (c *Client) GetAllDisplays(ctx context.Context) ([]Display, error) {
-	var resp Response
-	if err := c.doRequest(ctx, "/alldisplays", struct{}{}, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: get displays failed: %w", err)
-	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: get displays failed: %s", resp.Msg)
-	}
-
-	var result []Display
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
-	}
-	return result, nil
+	return call[[]Display](ctx, c, "/alldisplays", struct{}{})
 }
 
 // ============================================================================
@@ -1131,31 +1479,13 @@ func (c *Client) GetAllDisplays(ctx context.Context) ([]Display, error) {
 // RunRPA starts an RPA task.
 // POST /rpa/run
 func (c *Client) RunRPA(ctx context.Context, taskID string) error {
-	req := RPARequest{ID: taskID}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/rpa/run", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: run RPA failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: run RPA failed: %s", resp.Msg)
-	}
-	return nil
+	return c.exec(ctx, "/rpa/run", RPARequest{ID: taskID})
 }
 
 // StopRPA stops a running RPA task.
 // POST /rpa/stop
 func (c *Client) StopRPA(ctx context.Context, taskID string) error {
-	req := RPARequest{ID: taskID}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/rpa/stop", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: stop RPA failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: stop RPA failed: %s", resp.Msg)
-	}
-	return nil
+	return c.exec(ctx, "/rpa/stop", RPARequest{ID: taskID})
 }
 
 // ============================================================================
@@ -1169,54 +1499,28 @@ func (c *Client) AutoPaste(ctx context.Context, browserID, url string) error {
 		BrowserID: browserID,
 		URL:       url,
 	}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/autopaste", req, &resp); err != nil {
-		return fmt.Errorf("bitbrowser: auto paste failed: %w", err)
-	}
-	if !resp.Success {
-		return fmt.Errorf("bitbrowser: auto paste failed: %s", resp.Msg)
-	}
-	return nil
+	return c.exec(ctx, "/autopaste", req)
 }
 
 // ReadExcel reads an Excel file from the local filesystem.
+// The result is the decoded JSON; ReadExcelRows and ReadExcelTyped parse it.
 // POST /utils/readexcel
 func (c *Client) ReadExcel(ctx context.Context, filepath string) (any, error) {
-	req := FileRequest{FilePath: filepath}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/utils/readexcel", req, &resp); err != nil {
-		return nil, fmt.Errorf("bitbrowser: read excel failed: %w", err)
-	}
-	if !resp.Success {
-		return nil, fmt.Errorf("bitbrowser: read excel failed: %s", resp.Msg)
-	}
-
-	var result any
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
-	}
-	return result, nil
+	return call[any](ctx, c, "/utils/readexcel", FileRequest{FilePath: filepath})
 }
 
 // ReadFile reads a text file from the local filesystem.
 // POST /utils/readfile
 func (c *Client) ReadFile(ctx context.Context, filepath string) (string, error) {
-	req := FileRequest{FilePath: filepath}
-
-	var resp Response
-	if err := c.doRequest(ctx, "/utils/readfile", req, &resp); err != nil {
-		return "", fmt.Errorf("bitbrowser: read file failed: %w", err)
-	}
-	if !resp.Success {
-		return "", fmt.Errorf("bitbrowser: read file failed: %s", resp.Msg)
+	data, err := c.post(ctx, "/utils/readfile", FileRequest{FilePath: filepath})
+	if err != nil {
+		return "", err
 	}
 
 	var result string
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
+	if err := json.Unmarshal(data, &result); err != nil {
 		// If it's not a string, return the raw JSON
-		return string(resp.Data), nil
+		return string(data), nil
 	}
 	return result, nil
 }
@@ -1235,21 +1539,38 @@ func (c *Client) doRequest(ctx context.Context, path string, reqBody any, respBo
 		}
 	}
 
+	if c.dryRun && isMutating(path) {
+		return c.dryRunRequest(ctx, path, jsonData, respBody)
+	}
+
+	ctx, cancel := c.endpointTimeout(ctx, path)
+	defer cancel()
+
 	c.logRequest(ctx, http.MethodPost, path, reqBody)
 	start := time.Now()
 
-	r := newRetryer(c.retryConfig)
+	r := newRetryer(c.retryConfigFor(path))
+	r.budget = c.retryBudget
+	r.clock = c.clock
 	attempt := 0
 
 	err = r.do(ctx, func() error {
 		attempt++
-		execErr := c.executeRequest(ctx, path, jsonData, respBody)
+		var execErr error
+		if c.hedging != nil && hedgeable(path) {
+			execErr = c.executeHedged(ctx, path, jsonData, respBody)
+		} else {
+			execErr = c.executeRequest(ctx, path, jsonData, respBody)
+		}
 		if execErr != nil {
+			execErr = c.captureBody(execErr)
 			c.logError(ctx, path, execErr, attempt)
 		}
 		return execErr
 	})
 
+	c.audit(ctx, path, jsonData, respBody, err, start)
+
 	duration := time.Since(start)
 	success := err == nil
 
@@ -1261,48 +1582,215 @@ func (c *Client) doRequest(ctx context.Context, path string, reqBody any, respBo
 
 // executeRequest performs a single HTTP POST request without retry.
 func (c *Client) executeRequest(ctx context.Context, path string, jsonData []byte, respBody any) error {
+	body, err := c.executeRaw(ctx, path, jsonData)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(path, body, respBody)
+}
+
+// decodeResponse unmarshals a successful response body.
+func decodeResponse(path string, body []byte, respBody any) error {
+	if err := json.Unmarshal(body, respBody); err != nil {
+		apiErr := NewAPIError(path, http.StatusOK, "failed to unmarshal response: "+err.Error())
+		apiErr.raw = body
+		return apiErr
+	}
+	if resp, ok := respBody.(*Response); ok {
+		resp.raw = body
+	}
+	return nil
+}
+
+// executeRaw performs a single HTTP POST request and returns the body of a
+// successful response.
+func (c *Client) executeRaw(ctx context.Context, path string, jsonData []byte) ([]byte, error) {
+	resp, err := c.send(ctx, path, jsonData)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, NewNetworkError("read_response", c.apiURL+path, err)
+	}
+	return body, nil
+}
+
+// send performs a single HTTP POST request and returns a successful
+// response, whose body the caller must close.
+func (c *Client) send(ctx context.Context, path string, jsonData []byte) (*http.Response, error) {
 	url := c.apiURL + path
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonData))
 	if err != nil {
-		return NewNetworkError("create_request", url, err)
+		return nil, NewNetworkError("create_request", url, err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if c.compression {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	for key, values := range c.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
 
 	// Add API key authentication header if configured
 	if c.apiKey != "" {
 		req.Header.Set("x-api-key", c.apiKey)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	if id, ok := RequestIDFromContext(ctx); ok {
+		req.Header.Set(RequestIDHeader, id)
+	}
+
+	// Request editors run last so they can override anything above
+	for _, edit := range c.requestEditors {
+		edit(req)
+	}
+
+	resp, err := c.apiClient.Do(req)
 	if err != nil {
 		// Check if it's a context error
 		if errors.Is(err, context.DeadlineExceeded) {
-			return NewTimeoutError("http_request", "", err)
+			return nil, NewTimeoutError("http_request", "", err)
 		}
 		if errors.Is(err, context.Canceled) {
-			return err
+			return nil, err
 		}
-		return NewNetworkError("http_request", url, err)
+		return nil, NewNetworkError("http_request", url, err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return NewNetworkError("read_response", url, err)
+	if err := decompress(resp); err != nil {
+		resp.Body.Close()
+		return nil, NewNetworkError("read_response", url, err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return NewAPIError(path, resp.StatusCode, string(body))
+		defer resp.Body.Close()
+		body, err := readBody(resp)
+		if err != nil {
+			return nil, NewNetworkError("read_response", url, err)
+		}
+		apiErr := NewAPIError(path, resp.StatusCode, string(body))
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), c.clock.Now())
+		apiErr.raw = body
+		return nil, apiErr
 	}
 
-	if err := json.Unmarshal(body, respBody); err != nil {
-		return NewAPIError(path, resp.StatusCode, "failed to unmarshal response: "+err.Error())
+	return resp, nil
+}
+
+// buildHTTPClients derives the HTTP clients used by the SDK.
+// TLS settings apply to both API requests and browser debug endpoints; a
+// custom dialer only applies to the BitBrowser API. The configured HTTP
+// client is copied rather than modified.
+func (c *Client) buildHTTPClients() error {
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Transport: DefaultTransport()}
+	}
+
+	if c.transport != nil {
+		client := *c.httpClient
+		client.Transport = c.transport
+		c.httpClient = &client
+	}
+
+	tlsConfig, err := c.buildTLSConfig()
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		client, err := cloneWithTransport(c.httpClient, func(t *http.Transport) {
+			t.TLSClientConfig = tlsConfig
+		})
+		if err != nil {
+			return err
+		}
+		c.httpClient = client
 	}
 
+	c.apiClient = c.httpClient
+	if c.dialContext != nil {
+		client, err := cloneWithTransport(c.httpClient, func(t *http.Transport) {
+			t.DialContext = c.dialContext
+		})
+		if err != nil {
+			return err
+		}
+		c.apiClient = client
+	}
 	return nil
 }
 
+// buildTLSConfig combines WithTLSConfig with certificate and CA files.
+// Returns nil if no TLS option was configured.
+func (c *Client) buildTLSConfig() (*tls.Config, error) {
+	if c.tlsConfig == nil && c.tlsCertFile == "" && c.tlsKeyFile == "" && c.tlsCAFile == "" {
+		return nil, nil
+	}
+
+	config := c.tlsConfig.Clone()
+	if config == nil {
+		config = &tls.Config{}
+	}
+
+	if c.tlsCertFile != "" || c.tlsKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.tlsCertFile, c.tlsKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("bitbrowser: failed to load client certificate: %w", err)
+		}
+		config.Certificates = append(config.Certificates, cert)
+	}
+
+	if c.tlsCAFile != "" {
+		pem, err := os.ReadFile(c.tlsCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("bitbrowser: failed to read CA file: %w", err)
+		}
+		// Clone shares the caller's pool, so copy it before adding to it.
+		if config.RootCAs == nil {
+			config.RootCAs = x509.NewCertPool()
+		} else {
+			config.RootCAs = config.RootCAs.Clone()
+		}
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, NewValidationError("caFile", "no valid PEM certificates found in "+c.tlsCAFile)
+		}
+	}
+
+	return config, nil
+}
+
+// cloneWithTransport returns a copy of client whose transport is a clone
+// of the original with edit applied.
+func cloneWithTransport(client *http.Client, edit func(*http.Transport)) (*http.Client, error) {
+	var transport *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, NewValidationError("transport", fmt.Sprintf("custom dialer and TLS options require an *http.Transport, got %T", t))
+	}
+	edit(transport)
+
+	clone := *client
+	clone.Transport = transport
+	return &clone, nil
+}
+
+// bindAddress returns the address browsers bind for LAN access, matching
+// the address family of the API host.
+func (c *Client) bindAddress(ctx context.Context) string {
+	host, err := extractHost(c.apiURL)
+	if err != nil {
+		return "0.0.0.0"
+	}
+	return wildcardAddress(ctx, host)
+}
+
 // extractHost extracts the hostname from a URL string.
 // Returns an error if the URL is invalid or has no host.
 func extractHost(rawURL string) (string, error) {
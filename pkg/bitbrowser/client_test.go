@@ -5,9 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -227,6 +230,38 @@ func TestCreateProfile(t *testing.T) {
 		}
 	})
 
+	t.Run("sends false for default-true settings", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+
+			if v, ok := body["syncTabs"]; !ok || v != false {
+				t.Errorf("syncTabs = %v (present %v), want false", v, ok)
+			}
+			if _, ok := body["syncCookies"]; ok {
+				t.Error("syncCookies should be omitted when nil")
+			}
+			fp, _ := body["browserFingerPrint"].(map[string]any)
+			if v, ok := fp["isIpCreateTimeZone"]; !ok || v != false {
+				t.Errorf("isIpCreateTimeZone = %v (present %v), want false", v, ok)
+			}
+
+			w.Write(successResponse(map[string]string{"id": "profile-123"}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		_, err := client.CreateProfile(context.Background(), ProfileConfig{
+			Name:               "Test Profile",
+			SyncTabs:           Bool(false),
+			BrowserFingerPrint: &Fingerprint{IsIpCreateTimeZone: Bool(false)},
+		})
+
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
 	t.Run("API error", func(t *testing.T) {
 		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
 			w.Write(errorResponse("quota exceeded"))
@@ -242,6 +277,123 @@ func TestCreateProfile(t *testing.T) {
 			t.Error("expected error, got nil")
 		}
 	})
+
+	t.Run("classifies API errors", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(errorResponse("余额不足"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithResponseBodyCapture(256))
+		_, err := client.CreateProfile(context.Background(), ProfileConfig{Name: "Test Profile"})
+		var apiErr *APIError
+		if !errors.Is(err, ErrInsufficientBalance) || !errors.As(err, &apiErr) || apiErr.Body == "" {
+			t.Errorf("err = %v, want ErrInsufficientBalance with the captured body", err)
+		}
+	})
+}
+
+func TestCreateProfiles(t *testing.T) {
+	// quotaServer reports existing profiles and creates new ones.
+	quotaServer := func(existing int, creates *int) *httptest.Server {
+		return mockServer(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/browser/list":
+				w.Write(successResponse(ListResult{Total: existing}))
+			case "/browser/update":
+				*creates++
+				w.Write(successResponse(map[string]string{"id": fmt.Sprintf("new-%d", *creates)}))
+			}
+		})
+	}
+
+	t.Run("creates all profiles", func(t *testing.T) {
+		creates := 0
+		server := quotaServer(8, &creates)
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithProfileLimit(10))
+		ids, err := client.CreateProfiles(context.Background(), make([]ProfileConfig, 2))
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ids) != 2 || ids[0] != "new-1" || ids[1] != "new-2" {
+			t.Errorf("ids = %v", ids)
+		}
+	})
+
+	t.Run("preflight rejects batch over quota", func(t *testing.T) {
+		creates := 0
+		server := quotaServer(8, &creates)
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithProfileLimit(10))
+		_, err := client.CreateProfiles(context.Background(), make([]ProfileConfig, 3))
+
+		if !errors.Is(err, ErrQuotaExceeded) {
+			t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+		}
+		var quotaErr *QuotaError
+		if !errors.As(err, &quotaErr) || quotaErr.Remaining != 2 || quotaErr.Requested != 3 {
+			t.Errorf("unexpected error: %+v", quotaErr)
+		}
+		if creates != 0 {
+			t.Errorf("creates = %d, want 0", creates)
+		}
+	})
+
+	t.Run("API quota error stops the batch", func(t *testing.T) {
+		creates := 0
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			creates++
+			if creates > 1 {
+				w.Write(errorResponse("窗口数量已达上限"))
+				return
+			}
+			w.Write(successResponse(map[string]string{"id": "new-1"}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		ids, err := client.CreateProfiles(context.Background(), make([]ProfileConfig, 3))
+
+		if !errors.Is(err, ErrQuotaExceeded) {
+			t.Errorf("expected ErrQuotaExceeded, got %v", err)
+		}
+		if len(ids) != 1 || creates != 2 {
+			t.Errorf("ids = %v, creates = %d", ids, creates)
+		}
+	})
+}
+
+func TestGetQuota(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(successResponse(ListResult{Total: 12}))
+	})
+	defer server.Close()
+
+	t.Run("unknown limit", func(t *testing.T) {
+		client := mustNew(t, server.URL)
+		quota, err := client.GetQuota(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if quota.Used != 12 || quota.Limit != 0 || quota.Remaining != -1 {
+			t.Errorf("unexpected quota: %+v", quota)
+		}
+	})
+
+	t.Run("configured limit", func(t *testing.T) {
+		client := mustNew(t, server.URL, WithProfileLimit(10))
+		quota, err := client.GetQuota(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if quota.Used != 12 || quota.Limit != 10 || quota.Remaining != 0 {
+			t.Errorf("unexpected quota: %+v", quota)
+		}
+	})
 }
 
 func TestUpdateProfile(t *testing.T) {
@@ -385,6 +537,129 @@ func TestDeleteProfiles(t *testing.T) {
 	})
 }
 
+func TestDeleteProfilesByFilter(t *testing.T) {
+	// filterServer serves total profiles from /browser/list and records
+	// the batches sent to /browser/delete/ids.
+	filterServer := func(total int, batches *[][]string, listedAfterDelete *bool) *httptest.Server {
+		return mockServer(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/browser/list":
+				if len(*batches) > 0 {
+					*listedAfterDelete = true
+				}
+				var req ListRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				if req.GroupID != "group-1" {
+					t.Errorf("GroupID = %q, want group-1", req.GroupID)
+				}
+				var list []ProfileDetail
+				for i := req.Page * req.PageSize; i < min(total, (req.Page+1)*req.PageSize); i++ {
+					list = append(list, ProfileDetail{ID: fmt.Sprintf("p%d", i)})
+				}
+				w.Write(successResponse(ListResult{List: list, Page: req.Page, Total: total}))
+			case "/browser/delete/ids":
+				var req struct {
+					IDs []string `json:"ids"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+				*batches = append(*batches, req.IDs)
+				w.Write(successResponse(nil))
+			}
+		})
+	}
+
+	t.Run("collects all pages then deletes in batches", func(t *testing.T) {
+		var batches [][]string
+		var listedAfterDelete bool
+		server := filterServer(250, &batches, &listedAfterDelete)
+		defer server.Close()
+
+		var progress []DeleteProgress
+		client := mustNew(t, server.URL)
+		n, err := client.DeleteProfilesByFilter(context.Background(), ListRequest{GroupID: "group-1"}, DeleteOptions{
+			Confirm:   true,
+			BatchSize: 120, // Capped at 100
+			Progress:  func(p DeleteProgress) { progress = append(progress, p) },
+		})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 250 {
+			t.Errorf("deleted = %d, want 250", n)
+		}
+		if len(batches) != 3 || len(batches[0]) != 100 || len(batches[2]) != 50 {
+			t.Errorf("unexpected batches: %d", len(batches))
+		}
+		if listedAfterDelete {
+			t.Error("listing should finish before deleting")
+		}
+		if len(progress) != 3 || progress[2].Deleted != 250 || progress[2].Total != 250 {
+			t.Errorf("unexpected progress: %+v", progress)
+		}
+	})
+
+	t.Run("custom batch size", func(t *testing.T) {
+		var batches [][]string
+		var listedAfterDelete bool
+		server := filterServer(25, &batches, &listedAfterDelete)
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		n, err := client.DeleteProfilesByFilter(context.Background(), ListRequest{GroupID: "group-1"},
+			DeleteOptions{Confirm: true, BatchSize: 10})
+
+		if err != nil || n != 25 {
+			t.Fatalf("n = %d, err = %v", n, err)
+		}
+		if len(batches) != 3 {
+			t.Errorf("batches = %d, want 3", len(batches))
+		}
+	})
+
+	t.Run("requires confirmation", func(t *testing.T) {
+		client := mustNew(t, "http://127.0.0.1:1")
+		_, err := client.DeleteProfilesByFilter(context.Background(), ListRequest{}, DeleteOptions{})
+
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("expected ErrValidation, got %v", err)
+		}
+	})
+
+	t.Run("reports partial progress on failure", func(t *testing.T) {
+		deletes := 0
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/browser/list":
+				list := make([]ProfileDetail, 30)
+				for i := range list {
+					list[i].ID = fmt.Sprintf("p%d", i)
+				}
+				w.Write(successResponse(ListResult{List: list, Total: 30}))
+			case "/browser/delete/ids":
+				deletes++
+				if deletes > 1 {
+					w.Write(errorResponse("delete failed"))
+					return
+				}
+				w.Write(successResponse(nil))
+			}
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		n, err := client.DeleteProfilesByFilter(context.Background(), ListRequest{},
+			DeleteOptions{Confirm: true, BatchSize: 10})
+
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if n != 10 {
+			t.Errorf("deleted = %d, want 10", n)
+		}
+	})
+}
+
 func TestOpen(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
@@ -455,6 +730,178 @@ func TestOpen(t *testing.T) {
 	})
 }
 
+func TestOpenProxyOverride(t *testing.T) {
+	t.Run("applies override and restores previous proxy", func(t *testing.T) {
+		var updates []ProxyUpdateRequest
+		var order []string
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			order = append(order, r.URL.Path)
+			switch r.URL.Path {
+			case "/browser/detail":
+				w.Write(successResponse(ProfileDetail{
+					ID: "profile-123", ProxyMethod: 2, ProxyType: "http", Host: "old.proxy", Port: 8080,
+				}))
+			case "/browser/proxy/update":
+				var req ProxyUpdateRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				updates = append(updates, req)
+				w.Write(successResponse(nil))
+			case "/browser/open":
+				w.Write(successResponse(OpenResult{Ws: "ws://127.0.0.1:9222/devtools/browser/abc"}))
+			}
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		result, err := client.Open(context.Background(), "profile-123", &OpenOptions{
+			ProxyOverride: &ProxyOverride{
+				ProxyType:     "socks5",
+				Host:          "rotating.proxy",
+				Port:          1080,
+				ProxyUserName: "user-session-1",
+				ProxyPassword: "secret",
+				Restore:       true,
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Ws == "" {
+			t.Error("expected WebSocket URL")
+		}
+		wantOrder := []string{"/browser/detail", "/browser/proxy/update", "/browser/open", "/browser/proxy/update"}
+		if strings.Join(order, ",") != strings.Join(wantOrder, ",") {
+			t.Errorf("call order = %v, want %v", order, wantOrder)
+		}
+		if len(updates) != 2 {
+			t.Fatalf("proxy updates = %d, want 2", len(updates))
+		}
+		if updates[0].Host != "rotating.proxy" || updates[0].ProxyMethod != ProxyMethodCustom || updates[0].ProxyUserName != "user-session-1" {
+			t.Errorf("unexpected override: %+v", updates[0])
+		}
+		if updates[1].Host != "old.proxy" || updates[1].Port != 8080 || updates[1].ProxyType != "http" {
+			t.Errorf("unexpected restore: %+v", updates[1])
+		}
+	})
+
+	t.Run("restores extract IP settings", func(t *testing.T) {
+		var updates []ProxyUpdateRequest
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/browser/detail":
+				w.Write(successResponse(ProfileDetail{
+					ID: "profile-123", ProxyMethod: ProxyMethodExtract, ProxyType: ProxyTypeSOCKS5,
+					DynamicIpUrl: "https://extract.example.com/ip", DynamicIpChannel: "rola", IsIpv6: true,
+				}))
+			case "/browser/proxy/update":
+				var req ProxyUpdateRequest
+				json.NewDecoder(r.Body).Decode(&req)
+				updates = append(updates, req)
+				w.Write(successResponse(nil))
+			case "/browser/open":
+				w.Write(successResponse(OpenResult{Ws: "ws://127.0.0.1:9222/devtools/browser/abc"}))
+			}
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		_, err := client.Open(context.Background(), "profile-123", &OpenOptions{
+			ProxyOverride: &ProxyOverride{
+				ProxyMethod:  ProxyMethodExtract,
+				ProxyType:    ProxyTypeHTTP,
+				DynamicIpUrl: "https://extract.example.com/other",
+				Restore:      true,
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(updates) != 2 || updates[0].DynamicIpUrl != "https://extract.example.com/other" {
+			t.Fatalf("proxy updates = %+v", updates)
+		}
+		restore := updates[1]
+		if restore.ProxyMethod != ProxyMethodExtract || restore.DynamicIpUrl != "https://extract.example.com/ip" || restore.DynamicIpChannel != "rola" || !restore.IsIpv6 {
+			t.Errorf("unexpected restore: %+v", restore)
+		}
+	})
+
+	t.Run("keeps override without restore", func(t *testing.T) {
+		var paths []string
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			w.Write(successResponse(OpenResult{Ws: "ws://127.0.0.1:9222/devtools/browser/abc"}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		_, err := client.Open(context.Background(), "profile-123", &OpenOptions{
+			ProxyOverride: &ProxyOverride{ProxyType: "http", Host: "proxy", Port: 3128},
+		})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Join(paths, ",") != "/browser/proxy/update,/browser/open" {
+			t.Errorf("paths = %v", paths)
+		}
+	})
+
+	t.Run("restores proxy when open fails", func(t *testing.T) {
+		updates := 0
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/browser/detail":
+				w.Write(successResponse(ProfileDetail{ID: "profile-123", ProxyType: "noproxy"}))
+			case "/browser/proxy/update":
+				updates++
+				w.Write(successResponse(nil))
+			case "/browser/open":
+				w.Write(errorResponse("browser is opening"))
+			}
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		_, err := client.Open(context.Background(), "profile-123", &OpenOptions{
+			ProxyOverride: &ProxyOverride{ProxyType: "http", Host: "proxy", Port: 3128, Restore: true},
+		})
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if updates != 2 {
+			t.Errorf("proxy updates = %d, want 2 (override + restore)", updates)
+		}
+	})
+
+	t.Run("does not open when override fails", func(t *testing.T) {
+		opened := false
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/browser/proxy/update":
+				w.Write(errorResponse("invalid proxy"))
+			case "/browser/open":
+				opened = true
+				w.Write(successResponse(OpenResult{}))
+			}
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		_, err := client.Open(context.Background(), "profile-123", &OpenOptions{
+			ProxyOverride: &ProxyOverride{ProxyType: "http", Host: "proxy", Port: 3128},
+		})
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if opened {
+			t.Error("browser should not be opened when the proxy override fails")
+		}
+	})
+}
+
 func TestClose(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
@@ -490,8 +937,8 @@ func TestGetPorts(t *testing.T) {
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
-		if ports["profile-1"] != "9222" {
-			t.Errorf("ports[profile-1] = %q, want %q", ports["profile-1"], "9222")
+		if ports["profile-1"] != 9222 {
+			t.Errorf("ports[profile-1] = %d, want %d", ports["profile-1"], 9222)
 		}
 	})
 }
@@ -1614,3 +2061,351 @@ func TestAPIKeyAuthentication(t *testing.T) {
 		}
 	})
 }
+
+func TestCustomHeaders(t *testing.T) {
+	t.Run("sends headers and user agent", func(t *testing.T) {
+		var got http.Header
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Clone()
+			w.Write(successResponse(nil))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL,
+			WithHeader("Authorization", "Bearer token-123"),
+			WithHeader("X-Tenant", "a"),
+			WithHeader("X-Tenant", "b"),
+			WithUserAgent("farm-worker/1.0"),
+			WithAPIKey("key"),
+		)
+		if err := client.Health(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got.Get("Authorization") != "Bearer token-123" {
+			t.Errorf("Authorization = %q", got.Get("Authorization"))
+		}
+		if v := got.Values("X-Tenant"); len(v) != 2 {
+			t.Errorf("X-Tenant = %v, want two values", v)
+		}
+		if got.Get("User-Agent") != "farm-worker/1.0" {
+			t.Errorf("User-Agent = %q", got.Get("User-Agent"))
+		}
+		if got.Get("x-api-key") != "key" {
+			t.Errorf("x-api-key = %q", got.Get("x-api-key"))
+		}
+		if got.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", got.Get("Content-Type"))
+		}
+	})
+
+	t.Run("request editors run last", func(t *testing.T) {
+		var got http.Header
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Clone()
+			w.Write(successResponse(nil))
+		})
+		defer server.Close()
+
+		calls := 0
+		client := mustNew(t, server.URL,
+			WithHeader("X-Token", "static"),
+			WithRequestEditor(func(r *http.Request) {
+				calls++
+				r.Header.Set("X-Token", "dynamic")
+				r.Header.Set("X-Path", r.URL.Path)
+			}),
+			WithRequestEditor(nil),
+		)
+		if err := client.Health(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if calls != 1 {
+			t.Errorf("editor calls = %d, want 1", calls)
+		}
+		if got.Get("X-Token") != "dynamic" {
+			t.Errorf("X-Token = %q, want editor value", got.Get("X-Token"))
+		}
+		if got.Get("X-Path") != "/health" {
+			t.Errorf("X-Path = %q", got.Get("X-Path"))
+		}
+	})
+
+	t.Run("editor runs on every retry attempt", func(t *testing.T) {
+		attempts := 0
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write(successResponse(nil))
+		})
+		defer server.Close()
+
+		calls := 0
+		client := mustNew(t, server.URL,
+			WithRetryConfig(&RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+			WithRequestEditor(func(r *http.Request) { calls++ }),
+		)
+		if err := client.Health(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("editor calls = %d, want 2", calls)
+		}
+	})
+}
+
+func TestCustomDialer(t *testing.T) {
+	t.Run("routes API requests through unix socket", func(t *testing.T) {
+		socketPath := filepath.Join(t.TempDir(), "api.sock")
+		listener, err := net.Listen("unix", socketPath)
+		if err != nil {
+			t.Skipf("unix sockets unavailable: %v", err)
+		}
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(nil))
+		}))
+		server.Listener = listener
+		server.Start()
+		defer server.Close()
+
+		client := mustNew(t, "http://bitbrowser.local", WithUnixSocket(socketPath))
+		if err := client.Health(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("does not modify the caller's HTTP client", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(nil))
+		})
+		defer server.Close()
+
+		dialed := 0
+		custom := &http.Client{Transport: &http.Transport{}}
+		client := mustNew(t, "http://api.invalid",
+			WithHTTPClient(custom),
+			WithDialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dialed++
+				var d net.Dialer
+				return d.DialContext(ctx, network, server.Listener.Addr().String())
+			}),
+		)
+		if err := client.Health(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if dialed == 0 {
+			t.Error("custom dialer was not used")
+		}
+		if custom.Transport.(*http.Transport).DialContext != nil {
+			t.Error("caller's transport should not be modified")
+		}
+	})
+
+	t.Run("rejects non-standard transports", func(t *testing.T) {
+		_, err := New("http://localhost",
+			WithHTTPClient(&http.Client{Transport: roundTripFunc(nil)}),
+			WithUnixSocket("/tmp/x.sock"),
+		)
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("expected ErrValidation, got %v", err)
+		}
+	})
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestOpenBusyPolicy(t *testing.T) {
+	t.Run("fails fast by default", func(t *testing.T) {
+		attempts := 0
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.Write(errorResponse("浏览器正在打开中"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		_, err := client.Open(context.Background(), "profile-123", nil)
+
+		if !errors.Is(err, ErrBusy) {
+			t.Errorf("expected ErrBusy, got %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("attempts = %d, want 1", attempts)
+		}
+	})
+
+	t.Run("waits while busy", func(t *testing.T) {
+		attempts := 0
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 3 {
+				w.Write(errorResponse("浏览器正在打开中"))
+				return
+			}
+			w.Write(successResponse(OpenResult{Ws: "ws://127.0.0.1:9222/devtools/browser/abc"}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithOpenBusyPolicy(OpenBusyPolicy{
+			Wait:     true,
+			Interval: 5 * time.Millisecond,
+		}))
+		result, err := client.Open(context.Background(), "profile-123", nil)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Ws == "" || attempts != 3 {
+			t.Errorf("attempts = %d, result = %+v", attempts, result)
+		}
+	})
+
+	t.Run("gives up after max wait", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(errorResponse("busy"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithOpenBusyPolicy(OpenBusyPolicy{
+			Wait:     true,
+			Interval: 10 * time.Millisecond,
+			MaxWait:  35 * time.Millisecond,
+		}))
+		_, err := client.Open(context.Background(), "profile-123", nil)
+
+		if !errors.Is(err, ErrBusy) {
+			t.Errorf("expected ErrBusy, got %v", err)
+		}
+	})
+
+	t.Run("does not wait on other errors", func(t *testing.T) {
+		attempts := 0
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.Write(errorResponse("profile not found"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithOpenBusyPolicy(OpenBusyPolicy{Wait: true, Interval: time.Millisecond}))
+		_, err := client.Open(context.Background(), "profile-123", nil)
+
+		if err == nil || errors.Is(err, ErrBusy) {
+			t.Errorf("expected non-busy error, got %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("attempts = %d, want 1", attempts)
+		}
+	})
+
+	t.Run("captures Retry-After on HTTP errors", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		err := client.Health(context.Background())
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected *APIError, got %T", err)
+		}
+		if apiErr.RetryAfter != 7*time.Second {
+			t.Errorf("RetryAfter = %v, want 7s", apiErr.RetryAfter)
+		}
+	})
+
+	t.Run("measures Retry-After dates with the client clock", func(t *testing.T) {
+		clock := NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", clock.Now().Add(90*time.Second).Format(http.TimeFormat))
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer server.Close()
+
+		err := mustNew(t, server.URL, WithClock(clock)).Health(context.Background())
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.RetryAfter != 90*time.Second {
+			t.Errorf("err = %v, want RetryAfter 90s", err)
+		}
+	})
+}
+
+func TestOpenRetryPolicy(t *testing.T) {
+	t.Run("retries while the kernel downloads", func(t *testing.T) {
+		attempts := 0
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 3 {
+				w.Write(errorResponse("内核正在下载中，请稍后"))
+				return
+			}
+			w.Write(successResponse(OpenResult{Ws: "ws://127.0.0.1:9222/devtools/browser/abc"}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		result, err := client.Open(context.Background(), "profile-123", &OpenOptions{
+			RetryPolicy: &OpenRetryPolicy{BaseDelay: time.Millisecond},
+		})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Ws == "" || attempts != 3 {
+			t.Errorf("attempts = %d, result = %+v", attempts, result)
+		}
+	})
+
+	t.Run("stops after max attempts", func(t *testing.T) {
+		attempts := 0
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.Write(errorResponse("浏览器正在打开中"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		_, err := client.Open(context.Background(), "profile-123", &OpenOptions{
+			RetryPolicy: &OpenRetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+		})
+
+		if !errors.Is(err, ErrBusy) || !errors.Is(err, ErrRetryExhausted) {
+			t.Errorf("expected exhausted ErrBusy, got %v", err)
+		}
+		if attempts != 2 {
+			t.Errorf("attempts = %d, want 2", attempts)
+		}
+	})
+
+	t.Run("custom classification", func(t *testing.T) {
+		attempts := 0
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.Write(errorResponse("内核正在下载中"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		_, err := client.Open(context.Background(), "profile-123", &OpenOptions{
+			RetryPolicy: &OpenRetryPolicy{RetryIf: func(err error) bool { return errors.Is(err, ErrBusy) }},
+		})
+
+		if !errors.Is(err, ErrKernelDownloading) {
+			t.Errorf("expected ErrKernelDownloading, got %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("attempts = %d, want 1", attempts)
+		}
+	})
+}
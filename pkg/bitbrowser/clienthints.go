@@ -0,0 +1,61 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+	"github.com/lpg-it/go-antidetect/pkg/ua"
+)
+
+// userAgentDataJS reads the user agent and, where the browser has them,
+// the client hints a page sees.
+const userAgentDataJS = `(async () => {
+	const data = navigator.userAgentData;
+	const hints = data ? await data.getHighEntropyValues(["platformVersion", "model", "fullVersionList"]) : null;
+	return {userAgent: navigator.userAgent, hints};
+})()`
+
+// CheckClientHints compares what a running browser reports to pages in
+// navigator.userAgent and navigator.userAgentData, which also make up the
+// Sec-CH-UA headers, with the fingerprint it was opened with. BitBrowser
+// derives client hints from CoreProduct, CoreVersion and OS and has no
+// separate settings for them, so a mismatch means the user agent or the
+// core does not fit the fingerprint. All mismatches are reported.
+//
+// Example:
+//
+//	detail, err := client.GetProfileDetail(ctx, id)
+//	err = bitbrowser.CheckClientHints(ctx, session, detail.BrowserFingerPrint)
+func CheckClientHints(ctx context.Context, session *cdp.Session, fp *Fingerprint) error {
+	if fp == nil {
+		return NewValidationError("browserFingerPrint", "fingerprint is required")
+	}
+	var data struct {
+		UserAgent string          `json:"userAgent"`
+		Hints     *ua.ClientHints `json:"hints"`
+	}
+	if err := session.Evaluate(ctx, userAgentDataJS, &data); err != nil {
+		return fmt.Errorf("bitbrowser: read client hints: %w", err)
+	}
+
+	want := userAgentExpect(fp)
+	var errs []error
+	if fp.UserAgent != "" && data.UserAgent != fp.UserAgent {
+		errs = append(errs, fmt.Errorf("navigator.userAgent is %q, want the fingerprint's %q", data.UserAgent, fp.UserAgent))
+	} else if err := ua.Validate(data.UserAgent, want); err != nil {
+		errs = append(errs, err)
+	}
+	var hints ua.ClientHints
+	if data.Hints != nil {
+		hints = *data.Hints
+	}
+	if err := ua.CheckClientHints(hints, want); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("bitbrowser: client hints do not match the fingerprint: %w", err)
+	}
+	return nil
+}
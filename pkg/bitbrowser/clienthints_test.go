@@ -0,0 +1,63 @@
+package bitbrowser
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+	"github.com/lpg-it/go-antidetect/pkg/ua"
+)
+
+func TestCheckClientHints(t *testing.T) {
+	const chrome130Win = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.0.0 Safari/537.36"
+
+	// check evaluates against a page that reports userAgent and hints.
+	check := func(t *testing.T, userAgent string, hints any, fp *Fingerprint) error {
+		t.Helper()
+		devtools := newFakeDevTools(t)
+		devtools.results = map[string]any{"Runtime.evaluate": map[string]any{
+			"result": map[string]any{"type": "object", "value": map[string]any{"userAgent": userAgent, "hints": hints}},
+		}}
+		conn, err := cdp.Dial(context.Background(), devtools.wsURL())
+		if err != nil {
+			t.Fatalf("Dial failed: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		session, err := conn.AttachToPage(context.Background())
+		if err != nil {
+			t.Fatalf("AttachToPage failed: %v", err)
+		}
+		return CheckClientHints(context.Background(), session, fp)
+	}
+	hints, _ := ua.GenerateClientHints(ua.Options{Version: "130", OS: ua.Windows})
+
+	t.Run("consistent", func(t *testing.T) {
+		if err := check(t, chrome130Win, hints, &Fingerprint{CoreVersion: "130", OS: "Win32"}); err != nil {
+			t.Errorf("CheckClientHints() = %v, want nil", err)
+		}
+	})
+
+	t.Run("reports every mismatch", func(t *testing.T) {
+		err := check(t, chrome130Win, hints, &Fingerprint{CoreVersion: "131", OS: "MacIntel"})
+		for _, want := range []string{"version is 130.0.0.0, want 131", "brand Google Chrome is version 130, want 131", `platform is "Windows", want "macOS"`} {
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("CheckClientHints() = %v, want it to contain %q", err, want)
+			}
+		}
+	})
+
+	t.Run("user agent differs from the fingerprint", func(t *testing.T) {
+		err := check(t, chrome130Win, hints, &Fingerprint{CoreVersion: "130", UserAgent: strings.Replace(chrome130Win, "130", "129", 1)})
+		if err == nil || !strings.Contains(err.Error(), "navigator.userAgent is") {
+			t.Errorf("CheckClientHints() = %v, want a user agent mismatch", err)
+		}
+	})
+
+	t.Run("Firefox has no hints", func(t *testing.T) {
+		firefox := "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:128.0) Gecko/20100101 Firefox/128.0"
+		if err := check(t, firefox, nil, &Fingerprint{CoreProduct: "firefox", CoreVersion: "128", OS: "Win32"}); err != nil {
+			t.Errorf("CheckClientHints() = %v, want nil", err)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ClipboardWriter sets the clipboard that AutoPaste pastes from, on the
+// BitBrowser host. LocalClipboard writes this machine's clipboard; an
+// AgentClient writes a remote host's; a cdp.Session's SetClipboard can be
+// adapted with ClipboardWriterFunc.
+type ClipboardWriter interface {
+	SetClipboard(ctx context.Context, text string) error
+}
+
+// ClipboardWriterFunc adapts a function to the ClipboardWriter interface.
+type ClipboardWriterFunc func(ctx context.Context, text string) error
+
+// SetClipboard calls f(ctx, text).
+func (f ClipboardWriterFunc) SetClipboard(ctx context.Context, text string) error {
+	return f(ctx, text)
+}
+
+// windowsSetClipboard is the PowerShell script that copies stdin, read as
+// UTF-8, to the clipboard.
+const windowsSetClipboard = `[Console]::InputEncoding = [Text.Encoding]::UTF8
+Set-Clipboard -Value ([Console]::In.ReadToEnd())`
+
+// LocalClipboard sets the clipboard of this machine's desktop session with
+// the platform's tool: PowerShell on Windows, pbcopy on macOS and wl-copy
+// (Wayland) or xclip (X11, which needs DISPLAY) elsewhere. It is only
+// correct when BitBrowser runs on the same host as the client.
+var LocalClipboard ClipboardWriter = ClipboardWriterFunc(func(ctx context.Context, text string) error {
+	var cmd *exec.Cmd
+	switch {
+	case runtime.GOOS == "windows":
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", windowsSetClipboard)
+	case runtime.GOOS == "darwin":
+		cmd = exec.CommandContext(ctx, "pbcopy")
+	case os.Getenv("WAYLAND_DISPLAY") != "":
+		cmd = exec.CommandContext(ctx, "wl-copy")
+	default:
+		cmd = exec.CommandContext(ctx, "xclip", "-selection", "clipboard")
+	}
+	// No output capture: xclip and wl-copy leave a process behind that
+	// serves the selection, which would keep the pipes open.
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	return nil
+})
+
+// WithClipboard sets how SetClipboard and TypeViaClipboard write the
+// clipboard of the BitBrowser host.
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithClipboard(bitbrowser.LocalClipboard))
+func WithClipboard(writer ClipboardWriter) ClientOption {
+	return func(c *Client) {
+		c.clipboard = writer
+	}
+}
+
+// SetClipboard sets the clipboard content of the BitBrowser host with the
+// ClipboardWriter set by WithClipboard. The BitBrowser API has no endpoint
+// for it.
+func (c *Client) SetClipboard(ctx context.Context, text string) error {
+	if c.clipboard == nil {
+		return NewValidationError("Clipboard", "no clipboard writer configured; use WithClipboard")
+	}
+	if err := c.clipboard.SetClipboard(ctx, text); err != nil {
+		return fmt.Errorf("bitbrowser: set clipboard failed: %w", err)
+	}
+	return nil
+}
+
+// TypeViaClipboard puts text on the clipboard and pastes it with AutoPaste
+// into the focused input of the browser's page at url, for inputs that
+// reject synthetic key events.
+//
+// Example:
+//
+//	client, _ := bitbrowser.New(apiURL, bitbrowser.WithClipboard(agent))
+//	err := client.TypeViaClipboard(ctx, id, "https://example.com/login", "jane@example.com")
+func (c *Client) TypeViaClipboard(ctx context.Context, browserID, url, text string) error {
+	if err := c.SetClipboard(ctx, text); err != nil {
+		return err
+	}
+	return c.AutoPaste(ctx, browserID, url)
+}
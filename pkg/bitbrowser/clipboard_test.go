@@ -0,0 +1,62 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestSetClipboard(t *testing.T) {
+	client := mustNew(t, "http://127.0.0.1:54345")
+	if err := client.SetClipboard(context.Background(), "x"); !errors.Is(err, ErrValidation) {
+		t.Errorf("SetClipboard without a writer = %v, want ErrValidation", err)
+	}
+
+	errNoDisplay := errors.New("no display")
+	client = mustNew(t, "http://127.0.0.1:54345", WithClipboard(ClipboardWriterFunc(func(ctx context.Context, text string) error {
+		return errNoDisplay
+	})))
+	if err := client.SetClipboard(context.Background(), "x"); !errors.Is(err, errNoDisplay) {
+		t.Errorf("SetClipboard = %v, want the writer's error", err)
+	}
+}
+
+func TestTypeViaClipboard(t *testing.T) {
+	var calls []string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/autopaste" {
+			t.Errorf("path = %q, want /autopaste", r.URL.Path)
+		}
+		var req AutoPasteRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		calls = append(calls, "paste "+req.BrowserID+" "+req.URL)
+		w.Write(successResponse(nil))
+	})
+	defer server.Close()
+
+	fail := false
+	client := mustNew(t, server.URL, WithClipboard(ClipboardWriterFunc(func(ctx context.Context, text string) error {
+		if fail {
+			return errors.New("clipboard busy")
+		}
+		calls = append(calls, "copy "+text)
+		return nil
+	})))
+
+	if err := client.TypeViaClipboard(context.Background(), "b1", "https://example.com/login", "jane@example.com"); err != nil {
+		t.Fatalf("TypeViaClipboard failed: %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "copy jane@example.com" || calls[1] != "paste b1 https://example.com/login" {
+		t.Errorf("calls = %q", calls)
+	}
+
+	fail, calls = true, nil
+	if err := client.TypeViaClipboard(context.Background(), "b1", "https://example.com/login", "x"); err == nil {
+		t.Error("expected an error when the clipboard cannot be set")
+	}
+	if len(calls) != 0 {
+		t.Errorf("pasted despite the clipboard failure: %q", calls)
+	}
+}
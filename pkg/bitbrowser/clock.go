@@ -0,0 +1,124 @@
+package bitbrowser
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock abstracts time so that retry backoff, busy waiting and readiness
+// polling can be driven deterministically in tests and simulations.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After waits for the duration to elapse and then sends the current
+	// time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by the time package. It is the default.
+var SystemClock Clock = systemClock{}
+
+// systemClock implements Clock using the real time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// FakeClock is a Clock whose time only moves when Advance is called.
+// It is safe for concurrent use.
+//
+// Example:
+//
+//	clock := bitbrowser.NewFakeClock(time.Now())
+//	client, _ := bitbrowser.New(apiURL, bitbrowser.WithRetry(3), bitbrowser.WithClock(clock))
+//
+//	go client.Health(ctx)
+//	clock.BlockUntil(1)       // Wait for the retryer to start its backoff
+//	clock.Advance(time.Second) // Fire it without sleeping
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+	changed chan struct{} // Closed and replaced whenever waiters change
+}
+
+// fakeWaiter is a pending After call.
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewFakeClock creates a FakeClock set to the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, changed: make(chan struct{})}
+}
+
+// Now returns the fake current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the fake time once the clock has
+// been advanced by at least d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	c.notify()
+	return ch
+}
+
+// Advance moves the clock forward and fires every After whose deadline has
+// been reached.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+	c.notify()
+}
+
+// Waiters returns the number of pending After calls.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until at least n After calls are pending.
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		c.mu.Lock()
+		if len(c.waiters) >= n {
+			c.mu.Unlock()
+			return
+		}
+		changed := c.changed
+		c.mu.Unlock()
+		<-changed
+	}
+}
+
+// notify wakes BlockUntil callers. The caller must hold c.mu.
+func (c *FakeClock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
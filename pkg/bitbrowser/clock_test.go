@@ -0,0 +1,184 @@
+package bitbrowser
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	t.Run("fires after advancing", func(t *testing.T) {
+		start := time.Unix(1000, 0)
+		clock := NewFakeClock(start)
+
+		ch := clock.After(time.Second)
+		clock.Advance(999 * time.Millisecond)
+		select {
+		case <-ch:
+			t.Fatal("fired early")
+		default:
+		}
+
+		clock.Advance(time.Millisecond)
+		select {
+		case now := <-ch:
+			if !now.Equal(start.Add(time.Second)) {
+				t.Errorf("fired at %v, want %v", now, start.Add(time.Second))
+			}
+		default:
+			t.Fatal("did not fire")
+		}
+		if clock.Waiters() != 0 {
+			t.Errorf("Waiters = %d, want 0", clock.Waiters())
+		}
+	})
+
+	t.Run("non-positive duration fires immediately", func(t *testing.T) {
+		clock := NewFakeClock(time.Unix(0, 0))
+		select {
+		case <-clock.After(0):
+		default:
+			t.Fatal("After(0) should fire immediately")
+		}
+	})
+
+	t.Run("BlockUntil waits for pending calls", func(t *testing.T) {
+		clock := NewFakeClock(time.Unix(0, 0))
+		go clock.After(time.Minute)
+		clock.BlockUntil(1)
+		if clock.Waiters() != 1 {
+			t.Errorf("Waiters = %d, want 1", clock.Waiters())
+		}
+	})
+}
+
+func TestWithClock(t *testing.T) {
+	t.Run("drives readiness polling", func(t *testing.T) {
+		var polls atomic.Int32
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			if polls.Add(1) < 3 {
+				w.Write(successResponse(map[string]string{}))
+				return
+			}
+			w.Write(successResponse(map[string]string{"profile-123": "9222"}))
+		})
+		defer server.Close()
+
+		clock := NewFakeClock(time.Unix(0, 0))
+		client := mustNew(t, server.URL, WithClock(clock))
+
+		type result struct {
+			res *OpenResult
+			err error
+		}
+		done := make(chan result, 1)
+		go func() {
+			res, err := client.WaitForReady(context.Background(), "profile-123", 30)
+			done <- result{res, err}
+		}()
+
+		// Each poll waits for the default 2s interval
+		for range 3 {
+			clock.BlockUntil(1)
+			clock.Advance(2 * time.Second)
+		}
+
+		r := <-done
+		if r.err != nil {
+			t.Fatalf("unexpected error: %v", r.err)
+		}
+		if r.res.Http != "http://127.0.0.1:9222" {
+			t.Errorf("Http = %q, want http://127.0.0.1:9222", r.res.Http)
+		}
+	})
+
+	t.Run("nil clock is ignored", func(t *testing.T) {
+		client := mustNew(t, "http://127.0.0.1:54345", WithClock(nil))
+		if client.clock != SystemClock {
+			t.Error("expected SystemClock")
+		}
+	})
+}
+
+func TestWaitReadyDurations(t *testing.T) {
+	t.Run("durations replace seconds", func(t *testing.T) {
+		tests := []struct {
+			opts             OpenOptions
+			timeout, polling time.Duration
+		}{
+			{OpenOptions{}, 30 * time.Second, 2 * time.Second},
+			{OpenOptions{WaitTimeout: 10, PollInterval: 1}, 10 * time.Second, time.Second},
+			{OpenOptions{WaitTimeout: 10, WaitTimeoutDuration: 1500 * time.Millisecond,
+				PollInterval: 1, PollIntervalDuration: 100 * time.Millisecond}, 1500 * time.Millisecond, 100 * time.Millisecond},
+		}
+		for _, tt := range tests {
+			if got := tt.opts.waitTimeout(); got != tt.timeout {
+				t.Errorf("waitTimeout() = %v, want %v", got, tt.timeout)
+			}
+			if got := tt.opts.pollInterval(); got != tt.polling {
+				t.Errorf("pollInterval() = %v, want %v", got, tt.polling)
+			}
+		}
+	})
+
+	t.Run("jitter stays within the fraction", func(t *testing.T) {
+		if got := jitter(time.Second, 0); got != time.Second {
+			t.Errorf("jitter(1s, 0) = %v", got)
+		}
+		for range 100 {
+			if got := jitter(time.Second, 0.2); got < 800*time.Millisecond || got > 1200*time.Millisecond {
+				t.Fatalf("jitter(1s, 0.2) = %v", got)
+			}
+		}
+	})
+
+	t.Run("open polls at sub-second intervals", func(t *testing.T) {
+		var polls atomic.Int32
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/browser/ports" {
+				w.Write(successResponse(OpenResult{}))
+				return
+			}
+			if polls.Add(1) < 3 {
+				w.Write(successResponse(map[string]string{}))
+				return
+			}
+			w.Write(successResponse(map[string]string{"profile-123": "9222"}))
+		})
+		defer server.Close()
+
+		clock := NewFakeClock(time.Unix(0, 0))
+		client := mustNew(t, server.URL, WithClock(clock))
+
+		type result struct {
+			res *OpenResult
+			err error
+		}
+		done := make(chan result, 1)
+		go func() {
+			res, err := client.Open(context.Background(), "profile-123", &OpenOptions{
+				WaitReady:            true,
+				WaitTimeoutDuration:  time.Second,
+				PollIntervalDuration: 250 * time.Millisecond,
+				PollJitter:           0.1,
+			})
+			done <- result{res, err}
+		}()
+
+		// Advancing by the longest jittered interval fires each poll
+		for range 3 {
+			clock.BlockUntil(1)
+			clock.Advance(275 * time.Millisecond)
+		}
+
+		r := <-done
+		if r.err != nil {
+			t.Fatalf("unexpected error: %v", r.err)
+		}
+		if r.res.Http != "http://127.0.0.1:9222" {
+			t.Errorf("Http = %q, want http://127.0.0.1:9222", r.res.Http)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// closePollInterval is the longest delay between process checks in CloseAndWait.
+const closePollInterval = 500 * time.Millisecond
+
+// CloseAndWait closes a browser and waits until its process has exited.
+//
+// BitBrowser sometimes leaves a profile stuck in "closing". If the process is
+// still alive after half of timeout, CloseAndWait calls ResetClosingState and
+// sends the close again. If it is still alive when timeout expires and a
+// ProcessKiller is configured (see WithProcessKiller), the process is killed
+// and given one more poll interval to exit. Otherwise a *TimeoutError is
+// returned.
+func (c *Client) CloseAndWait(ctx context.Context, id string, timeout time.Duration) error {
+	if err := ValidateProfileID(id); err != nil {
+		return err
+	}
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	interval := min(closePollInterval, max(timeout/10, time.Millisecond))
+	start := c.clock.Now()
+
+	closeErr := c.Close(ctx, id)
+	reset := false
+	for {
+		pid, err := c.alivePID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if pid == 0 {
+			return nil
+		}
+
+		elapsed := c.clock.Now().Sub(start)
+		if !reset && elapsed >= timeout/2 {
+			reset = true
+			if c.logger != nil {
+				c.logger.WarnContext(ctx, "bitbrowser: browser stuck closing, resetting", "id", id, "pid", pid)
+			}
+			if err := c.ResetClosingState(ctx, id); err != nil {
+				return err
+			}
+			closeErr = c.Close(ctx, id)
+		}
+		if elapsed >= timeout {
+			cause := errors.Join(fmt.Errorf("process %d still alive", pid), closeErr)
+			return c.killStuck(ctx, id, pid, interval, NewTimeoutError("close_and_wait", timeout.String(), cause))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.clock.After(interval):
+		}
+	}
+}
+
+// alivePID returns the PID of a profile's browser process, or 0 if none is alive.
+func (c *Client) alivePID(ctx context.Context, id string) (int, error) {
+	pids, err := c.GetAlivePIDs(ctx, []string{id})
+	if err != nil {
+		return 0, err
+	}
+	return pids[id], nil
+}
+
+// killStuck kills a browser process that outlived CloseAndWait's timeout and
+// waits one poll interval for it to exit.
+func (c *Client) killStuck(ctx context.Context, id string, pid int, interval time.Duration, timeoutErr *TimeoutError) error {
+	if c.processKiller == nil {
+		return timeoutErr
+	}
+
+	if err := c.killProcess(ctx, id, pid); err != nil {
+		timeoutErr.Err = errors.Join(timeoutErr.Err, err)
+		return timeoutErr
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.clock.After(interval):
+	}
+	pid, err := c.alivePID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if pid != 0 {
+		return timeoutErr
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// closeServer simulates a browser that exits once alive() reports false.
+// It records the endpoints called.
+func closeServer(alive func(calls []string) bool) (*http.ServeMux, func() []string) {
+	var mu sync.Mutex
+	var calls []string
+	mux := http.NewServeMux()
+	record := func(path string) {
+		mu.Lock()
+		calls = append(calls, path)
+		mu.Unlock()
+	}
+	for _, path := range []string{"/browser/close", "/browser/closing/reset"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			record(r.URL.Path)
+			w.Write(successResponse(nil))
+		})
+	}
+	mux.HandleFunc("/browser/pids/alive", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		isAlive := alive(calls)
+		mu.Unlock()
+		if isAlive {
+			w.Write(successResponse(map[string]int{"profile-1": 4242}))
+			return
+		}
+		w.Write(successResponse(map[string]int{}))
+	})
+	return mux, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
+	}
+}
+
+func contains(calls []string, path string) bool {
+	for _, c := range calls {
+		if c == path {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCloseAndWait(t *testing.T) {
+	t.Run("returns once the process exits", func(t *testing.T) {
+		mux, calls := closeServer(func(calls []string) bool { return false })
+		server := mockServer(mux.ServeHTTP)
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		if err := client.CloseAndWait(context.Background(), "profile-1", time.Second); err != nil {
+			t.Fatalf("CloseAndWait() error = %v", err)
+		}
+		if contains(calls(), "/browser/closing/reset") {
+			t.Errorf("calls = %v, want no reset", calls())
+		}
+	})
+
+	t.Run("resets a stuck close", func(t *testing.T) {
+		mux, calls := closeServer(func(calls []string) bool {
+			return !contains(calls, "/browser/closing/reset")
+		})
+		server := mockServer(mux.ServeHTTP)
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		if err := client.CloseAndWait(context.Background(), "profile-1", 100*time.Millisecond); err != nil {
+			t.Fatalf("CloseAndWait() error = %v", err)
+		}
+		want := []string{"/browser/close", "/browser/closing/reset", "/browser/close"}
+		if got := calls(); len(got) != len(want) || got[1] != want[1] {
+			t.Errorf("calls = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("times out without a killer", func(t *testing.T) {
+		mux, _ := closeServer(func(calls []string) bool { return true })
+		server := mockServer(mux.ServeHTTP)
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		err := client.CloseAndWait(context.Background(), "profile-1", 50*time.Millisecond)
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) {
+			t.Fatalf("err = %v, want *TimeoutError", err)
+		}
+	})
+
+	t.Run("kills as a last resort", func(t *testing.T) {
+		var killed atomic.Int64
+		mux, _ := closeServer(func(calls []string) bool { return killed.Load() == 0 })
+		server := mockServer(mux.ServeHTTP)
+		defer server.Close()
+
+		killer := ProcessKillerFunc(func(ctx context.Context, pid int) error {
+			killed.Store(int64(pid))
+			return nil
+		})
+		client := mustNew(t, server.URL, WithProcessKiller(killer))
+		if err := client.CloseAndWait(context.Background(), "profile-1", 50*time.Millisecond); err != nil {
+			t.Fatalf("CloseAndWait() error = %v", err)
+		}
+		if got := killed.Load(); got != 4242 {
+			t.Errorf("killed = %d, want 4242", got)
+		}
+	})
+
+	t.Run("kill failure is reported", func(t *testing.T) {
+		mux, _ := closeServer(func(calls []string) bool { return true })
+		server := mockServer(mux.ServeHTTP)
+		defer server.Close()
+
+		killErr := errors.New("permission denied")
+		client := mustNew(t, server.URL, WithProcessKiller(ProcessKillerFunc(func(context.Context, int) error {
+			return killErr
+		})))
+		err := client.CloseAndWait(context.Background(), "profile-1", 50*time.Millisecond)
+		if !errors.Is(err, killErr) {
+			t.Errorf("err = %v, want %v", err, killErr)
+		}
+	})
+}
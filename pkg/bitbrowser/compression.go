@@ -0,0 +1,71 @@
+package bitbrowser
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// WithCompression asks the BitBrowser API for gzip-compressed responses and
+// decompresses them, whatever transport the client uses. It pays off for
+// large responses over slow links, such as profile lists and multi-megabyte
+// cookie exports from a remote host; on a LAN, compressing the usual small
+// responses costs more CPU than it saves, which is why DefaultTransport
+// disables compression.
+//
+// Servers that don't compress are unaffected: their responses are read as
+// before.
+func WithCompression(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.compression = enabled
+	}
+}
+
+// gzipReaders holds *gzip.Reader for decompressing response bodies.
+var gzipReaders sync.Pool
+
+// decompress replaces the body of a gzip-encoded response with its
+// decompressed content. Other responses are left alone.
+func decompress(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	zr, _ := gzipReaders.Get().(*gzip.Reader)
+	var err error
+	if zr == nil {
+		zr, err = gzip.NewReader(resp.Body)
+	} else {
+		err = zr.Reset(resp.Body)
+	}
+	if err != nil {
+		return err
+	}
+
+	resp.Body = &gzipBody{zr: zr, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipBody is a decompressed response body. Closing it returns the gzip
+// reader to gzipReaders.
+type gzipBody struct {
+	zr   *gzip.Reader
+	body io.ReadCloser
+	once sync.Once
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	return b.zr.Read(p)
+}
+
+func (b *gzipBody) Close() error {
+	b.once.Do(func() {
+		gzipReaders.Put(b.zr)
+	})
+	return b.body.Close()
+}
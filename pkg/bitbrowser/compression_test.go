@@ -0,0 +1,99 @@
+package bitbrowser
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// gzipServer answers with body, gzip-compressed if the request accepts it,
+// and records the Accept-Encoding header it received.
+func gzipServer(t *testing.T, status int, body []byte, accepted *string) string {
+	t.Helper()
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		*accepted = r.Header.Get("Accept-Encoding")
+		if !strings.Contains(*accepted, "gzip") {
+			w.WriteHeader(status)
+			w.Write(body)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(status)
+		zw := gzip.NewWriter(w)
+		zw.Write(body)
+		zw.Close()
+	})
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestWithCompression(t *testing.T) {
+	profiles := ListResult{Total: 2, List: []ProfileDetail{{ID: "p1", Name: strings.Repeat("x", 4096)}, {ID: "p2"}}}
+
+	t.Run("decompresses responses", func(t *testing.T) {
+		var accepted string
+		client := mustNew(t, gzipServer(t, http.StatusOK, successResponse(profiles), &accepted), WithCompression(true))
+		for range 3 { // Reuses pooled readers
+			result, err := client.ListProfiles(context.Background(), ListRequest{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.List) != 2 || result.List[0].Name != profiles.List[0].Name {
+				t.Fatalf("result = %+v", result)
+			}
+		}
+		if accepted != "gzip" {
+			t.Errorf("Accept-Encoding = %q, want gzip", accepted)
+		}
+	})
+
+	t.Run("streams compressed lists", func(t *testing.T) {
+		var accepted string
+		client := mustNew(t, gzipServer(t, http.StatusOK, successResponse(profiles), &accepted), WithCompression(true))
+		var ids []string
+		err := client.ListProfilesStream(context.Background(), ListRequest{}, func(p ProfileDetail) error {
+			ids = append(ids, p.ID)
+			return nil
+		})
+		if err != nil || len(ids) != 2 {
+			t.Errorf("ids = %v, err = %v", ids, err)
+		}
+	})
+
+	t.Run("compressed error bodies", func(t *testing.T) {
+		var accepted string
+		client := mustNew(t, gzipServer(t, http.StatusBadRequest, []byte("bad request"), &accepted), WithCompression(true))
+		_, err := client.ListProfiles(context.Background(), ListRequest{})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Message != "bad request" {
+			t.Errorf("err = %v, want API error with the decompressed body", err)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		var accepted string
+		client := mustNew(t, gzipServer(t, http.StatusOK, successResponse(profiles), &accepted))
+		if _, err := client.ListProfiles(context.Background(), ListRequest{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if accepted != "" {
+			t.Errorf("Accept-Encoding = %q, want none", accepted)
+		}
+	})
+
+	t.Run("corrupt body", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write([]byte("not gzip"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithCompression(true))
+		if _, err := client.ListProfiles(context.Background(), ListRequest{}); !errors.Is(err, ErrNetwork) {
+			t.Errorf("err = %v, want network error", err)
+		}
+	})
+}
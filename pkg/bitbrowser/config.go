@@ -1,5 +1,10 @@
 package bitbrowser
 
+import (
+	"fmt"
+	"time"
+)
+
 // PortConfig configures the port management behavior.
 //
 // The SDK supports two working modes:
@@ -35,8 +40,42 @@ type PortConfig struct {
 	// MaxPort is the maximum port number in the range (inclusive).
 	// Set to 0 to disable Managed Mode.
 	MaxPort int
+
+	// ProbeMode selects how candidate ports are checked before a browser is
+	// opened on them. Empty means ProbeAPIOnly.
+	ProbeMode ProbeMode
+
+	// ProbeTimeout bounds each TCP probe. Default: DefaultProbeTimeout.
+	ProbeTimeout time.Duration
+
+	// PartitionIndex and PartitionCount give this client one of
+	// PartitionCount disjoint slices of the range, so that workers sharing
+	// a BitBrowser host never pick the same port. A PartitionCount of 0 or
+	// 1 uses the whole range.
+	PartitionIndex int
+	PartitionCount int
 }
 
+// ProbeMode is how Managed Mode checks that a port is free.
+type ProbeMode string
+
+// Probe modes, from fastest open to fewest conflicts.
+const (
+	// ProbeNone picks a random port from the range without any check.
+	ProbeNone ProbeMode = "none"
+	// ProbeAPIOnly skips ports BitBrowser reports in use (GetPorts). This
+	// is the default.
+	ProbeAPIOnly ProbeMode = "api"
+	// ProbeTCP skips ports that accept TCP connections on the BitBrowser
+	// host, which also catches ports held by other programs.
+	ProbeTCP ProbeMode = "tcp"
+	// ProbeBoth combines ProbeAPIOnly and ProbeTCP.
+	ProbeBoth ProbeMode = "both"
+)
+
+// DefaultProbeTimeout is the default PortConfig.ProbeTimeout.
+const DefaultProbeTimeout = 500 * time.Millisecond
+
 // DefaultPortConfig returns a PortConfig with Native Mode (no port management).
 func DefaultPortConfig() *PortConfig {
 	return &PortConfig{
@@ -59,6 +98,41 @@ func (c *PortConfig) PortRangeSize() int {
 	return c.MaxPort - c.MinPort + 1
 }
 
+// Partition returns the bounds (inclusive) of the slice of the range this
+// client picks ports from. The range is split into PartitionCount
+// contiguous slices whose sizes differ by at most one.
+func (c *PortConfig) Partition() (minPort, maxPort int) {
+	if c.PartitionCount <= 1 {
+		return c.MinPort, c.MaxPort
+	}
+	size := c.PortRangeSize()
+	base, extra := size/c.PartitionCount, size%c.PartitionCount
+	minPort = c.MinPort + c.PartitionIndex*base + min(c.PartitionIndex, extra)
+	maxPort = minPort + base - 1
+	if c.PartitionIndex < extra {
+		maxPort++
+	}
+	return minPort, maxPort
+}
+
+// validatePartition checks PartitionIndex and PartitionCount against the
+// range.
+func (c *PortConfig) validatePartition() error {
+	if c.PartitionCount < 0 {
+		return &ValidationError{Field: "PartitionCount", Message: "must not be negative", Value: c.PartitionCount}
+	}
+	if c.PartitionCount <= 1 {
+		return nil
+	}
+	if c.PartitionIndex < 0 || c.PartitionIndex >= c.PartitionCount {
+		return &ValidationError{Field: "PartitionIndex", Message: fmt.Sprintf("must be between 0 and %d", c.PartitionCount-1), Value: c.PartitionIndex}
+	}
+	if c.PartitionCount > c.PortRangeSize() {
+		return &ValidationError{Field: "PartitionCount", Message: fmt.Sprintf("exceeds the %d ports in the range", c.PortRangeSize()), Value: c.PartitionCount}
+	}
+	return nil
+}
+
 // WithPortRange sets the port range for Managed Mode.
 // When configured, the SDK will:
 //   - Randomly select ports from the range [minPort, maxPort]
@@ -78,3 +152,60 @@ func WithPortRange(minPort, maxPort int) ClientOption {
 		c.portConfig.MaxPort = maxPort
 	}
 }
+
+// WithPortProbe sets how Managed Mode checks candidate ports, trading open
+// latency against the chance of a port conflict. A timeout of 0 means
+// DefaultProbeTimeout. It has no effect in Native Mode.
+//
+//	client, err := bitbrowser.New(apiURL,
+//	    bitbrowser.WithPortRange(50000, 51000),
+//	    bitbrowser.WithPortProbe(bitbrowser.ProbeBoth, 200*time.Millisecond),
+//	)
+func WithPortProbe(mode ProbeMode, timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		if c.portConfig == nil {
+			c.portConfig = DefaultPortConfig()
+		}
+		c.portConfig.ProbeMode = mode
+		c.portConfig.ProbeTimeout = timeout
+	}
+}
+
+// usesAPI reports whether the mode excludes ports reported by GetPorts.
+func (m ProbeMode) usesAPI() bool {
+	return m == "" || m == ProbeAPIOnly || m == ProbeBoth
+}
+
+// usesTCP reports whether the mode probes ports over TCP.
+func (m ProbeMode) usesTCP() bool {
+	return m == ProbeTCP || m == ProbeBoth
+}
+
+// validate rejects unknown modes, which would otherwise disable probing.
+func (m ProbeMode) validate() error {
+	switch m {
+	case "", ProbeNone, ProbeAPIOnly, ProbeTCP, ProbeBoth:
+		return nil
+	}
+	return &ValidationError{Field: "ProbeMode", Message: "must be none, api, tcp or both", Value: m}
+}
+
+// WithPortPartition splits the Managed Mode range between workerCount
+// workers and makes this client pick ports only from slice workerIndex
+// (0-based). Workers that share a BitBrowser host and the same range then
+// never race for a port, without a shared store. Health probes and Drain
+// only count browsers in the client's own slice.
+//
+//	client, err := bitbrowser.New(apiURL,
+//	    bitbrowser.WithPortRange(50000, 51000),
+//	    bitbrowser.WithPortPartition(workerIndex, 4),
+//	)
+func WithPortPartition(workerIndex, workerCount int) ClientOption {
+	return func(c *Client) {
+		if c.portConfig == nil {
+			c.portConfig = DefaultPortConfig()
+		}
+		c.portConfig.PartitionIndex = workerIndex
+		c.portConfig.PartitionCount = workerCount
+	}
+}
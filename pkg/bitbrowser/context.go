@@ -0,0 +1,58 @@
+package bitbrowser
+
+import (
+	"context"
+	"log/slog"
+)
+
+// RequestIDHeader is the HTTP header that carries the request ID set with
+// WithRequestID.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the context key for request IDs.
+type requestIDKey struct{}
+
+// auditActorKey is the context key for audit actors.
+type auditActorKey struct{}
+
+// WithRequestID returns a context carrying a correlation ID. Every API request
+// made with the context sends the ID in the X-Request-ID header, and every log
+// line includes it as "request_id", so multi-step operations such as
+// create → open → verify can be traced across concurrent workers.
+//
+// Request editors can read the ID with RequestIDFromContext(req.Context()).
+//
+// Example:
+//
+//	ctx := bitbrowser.WithRequestID(ctx, "job-42")
+//	id, err := client.CreateProfile(ctx, config)
+//	result, err := client.Open(ctx, id, nil)
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set with WithRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithAuditActor returns a context that records actor (e.g. a user, service or
+// worker name) as the "who" of audit events for operations made with it.
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFromContext returns the actor set with WithAuditActor.
+func AuditActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(auditActorKey{}).(string)
+	return actor, ok && actor != ""
+}
+
+// withRequestID appends the context's request ID to log attributes.
+func withRequestID(ctx context.Context, attrs []any) []any {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	return attrs
+}
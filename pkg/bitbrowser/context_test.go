@@ -0,0 +1,83 @@
+package bitbrowser
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Run("round trips through context", func(t *testing.T) {
+		ctx := WithRequestID(context.Background(), "job-42")
+		if id, ok := RequestIDFromContext(ctx); !ok || id != "job-42" {
+			t.Errorf("RequestIDFromContext = %q, %v", id, ok)
+		}
+		if _, ok := RequestIDFromContext(context.Background()); ok {
+			t.Error("expected no request ID")
+		}
+		if _, ok := RequestIDFromContext(WithRequestID(context.Background(), "")); ok {
+			t.Error("empty request ID should be ignored")
+		}
+	})
+
+	t.Run("sends X-Request-ID header", func(t *testing.T) {
+		var got []string
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			got = append(got, r.Header.Get("X-Request-ID"))
+			w.Write(successResponse(map[string]string{}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		ctx := WithRequestID(context.Background(), "job-42")
+		client.GetPorts(ctx)
+		client.GetPorts(context.Background())
+
+		if len(got) != 2 || got[0] != "job-42" || got[1] != "" {
+			t.Errorf("X-Request-ID headers = %q, want [job-42 \"\"]", got)
+		}
+	})
+
+	t.Run("visible to request editors", func(t *testing.T) {
+		var seen string
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(map[string]string{}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithRequestEditor(func(req *http.Request) {
+			seen, _ = RequestIDFromContext(req.Context())
+		}))
+		client.GetPorts(WithRequestID(context.Background(), "job-42"))
+
+		if seen != "job-42" {
+			t.Errorf("editor saw %q, want job-42", seen)
+		}
+	})
+
+	t.Run("included in logs", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		defer server.Close()
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		client := mustNew(t, server.URL, WithLogger(logger))
+		buf.Reset()
+		client.GetPorts(WithRequestID(context.Background(), "job-42"))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) < 3 {
+			t.Fatalf("expected request, error and response logs, got:\n%s", buf.String())
+		}
+		for _, line := range lines {
+			if !strings.Contains(line, "request_id=job-42") {
+				t.Errorf("log line missing request_id: %s", line)
+			}
+		}
+	})
+}
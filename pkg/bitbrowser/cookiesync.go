@@ -0,0 +1,152 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CookieProvider reads and writes the cookies of a running profile.
+// *Client implements it; adapters for other antidetect browsers let
+// SyncCookies move sessions between vendors.
+type CookieProvider interface {
+	GetCookies(ctx context.Context, id string) ([]Cookie, error)
+	SetCookies(ctx context.Context, id string, cookies []Cookie) error
+}
+
+// SyncCookies copies the cookies of the running profile srcID to the running
+// profile dstID, e.g. when migrating accounts between tools. Only cookies for
+// the given domains and their subdomains are copied; with no domains, all
+// cookies are. Cookies are passed through NormalizeCookies, so providers
+// that report them in different conventions interoperate.
+//
+// It returns the number of cookies written.
+//
+// Example:
+//
+//	n, err := bitbrowser.SyncCookies(ctx, oldClient, oldID, newClient, newID, "example.com")
+func SyncCookies(ctx context.Context, src CookieProvider, srcID string, dst CookieProvider, dstID string, domains ...string) (int, error) {
+	cookies, err := src.GetCookies(ctx, srcID)
+	if err != nil {
+		return 0, fmt.Errorf("bitbrowser: sync cookies failed: %w", err)
+	}
+	cookies = NormalizeCookies(FilterCookies(cookies, domains...))
+	if len(cookies) == 0 {
+		return 0, nil
+	}
+	if err := dst.SetCookies(ctx, dstID, cookies); err != nil {
+		return 0, fmt.Errorf("bitbrowser: sync cookies failed: %w", err)
+	}
+	return len(cookies), nil
+}
+
+// FilterCookies returns the cookies that belong to one of the domains or
+// their subdomains. With no domains, all cookies are returned.
+func FilterCookies(cookies []Cookie, domains ...string) []Cookie {
+	if len(domains) == 0 {
+		return cookies
+	}
+	var result []Cookie
+	for _, c := range cookies {
+		if cookieMatchesDomain(c.Domain, domains) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// cookieMatchesDomain reports whether a cookie domain is one of domains or
+// a subdomain of one.
+func cookieMatchesDomain(domain string, domains []string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimPrefix(d, "."))
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeCookies converts cookies to the conventions BitBrowser and
+// Chromium expect:
+//
+//   - SameSite is "Strict", "Lax", "None" or empty; browser-extension values
+//     such as "no_restriction", "lax" and "unspecified" are mapped
+//   - SameSite=None cookies are marked Secure, as Chromium requires
+//   - Expiry times in milliseconds are converted to seconds
+//   - Cookies without an expiry are session cookies, and vice versa
+//
+// The input slice is not modified.
+func NormalizeCookies(cookies []Cookie) []Cookie {
+	result := make([]Cookie, len(cookies))
+	for i, c := range cookies {
+		switch strings.ToLower(c.SameSite) {
+		case "strict":
+			c.SameSite = "Strict"
+		case "lax":
+			c.SameSite = "Lax"
+		case "none", "no_restriction":
+			c.SameSite = "None"
+			c.Secure = true
+		default:
+			c.SameSite = ""
+		}
+
+		if c.Expires > 1e11 {
+			c.Expires /= 1000 // Milliseconds, e.g. from JavaScript exports
+		}
+		if c.Expires <= 0 {
+			c.Expires = 0
+			c.Session = true
+		} else {
+			c.Session = false
+		}
+		if c.Path == "" {
+			c.Path = "/"
+		}
+		result[i] = c
+	}
+	return result
+}
+
+// exportedCookie covers the field names used by common cookie exports:
+// Chromium/CDP ("expires"), browser extensions such as EditThisCookie
+// ("expirationDate", "hostOnly") and Selenium ("expiry").
+type exportedCookie struct {
+	Cookie
+	ExpirationDate float64 `json:"expirationDate"`
+	Expiry         float64 `json:"expiry"`
+	HostOnly       bool    `json:"hostOnly"`
+}
+
+// ParseCookies parses a JSON cookie export from another browser or tool
+// and returns normalized cookies. It accepts an array of cookies, or an
+// object with a "cookies" array, using any of the field conventions of
+// Chromium, browser extensions and Selenium.
+func ParseCookies(data []byte) ([]Cookie, error) {
+	var exported []exportedCookie
+	if err := json.Unmarshal(data, &exported); err != nil {
+		var wrapped struct {
+			Cookies []exportedCookie `json:"cookies"`
+		}
+		if err2 := json.Unmarshal(data, &wrapped); err2 != nil || wrapped.Cookies == nil {
+			return nil, fmt.Errorf("bitbrowser: parse cookies failed: %w", err)
+		}
+		exported = wrapped.Cookies
+	}
+
+	cookies := make([]Cookie, len(exported))
+	for i, e := range exported {
+		c := e.Cookie
+		if c.Expires == 0 {
+			c.Expires = max(e.ExpirationDate, e.Expiry)
+		}
+		if e.HostOnly {
+			c.Domain = strings.TrimPrefix(c.Domain, ".")
+		}
+		cookies[i] = c
+	}
+	return NormalizeCookies(cookies), nil
+}
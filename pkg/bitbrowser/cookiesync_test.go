@@ -0,0 +1,132 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// memoryCookies is a CookieProvider holding cookies per profile.
+type memoryCookies map[string][]Cookie
+
+func (m memoryCookies) GetCookies(ctx context.Context, id string) ([]Cookie, error) {
+	cookies, ok := m[id]
+	if !ok {
+		return nil, errors.New("profile not running")
+	}
+	return cookies, nil
+}
+
+func (m memoryCookies) SetCookies(ctx context.Context, id string, cookies []Cookie) error {
+	m[id] = append(m[id], cookies...)
+	return nil
+}
+
+func TestSyncCookies(t *testing.T) {
+	src := memoryCookies{"old": {
+		{Name: "sid", Value: "1", Domain: ".example.com", Expires: 1900000000000, SameSite: "no_restriction"},
+		{Name: "pref", Value: "2", Domain: "www.example.com", Expires: -1},
+		{Name: "other", Value: "3", Domain: "notexample.com"},
+	}}
+
+	t.Run("between providers", func(t *testing.T) {
+		dst := memoryCookies{}
+		n, err := SyncCookies(context.Background(), src, "old", dst, "new", "example.com")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 2 || len(dst["new"]) != 2 {
+			t.Fatalf("synced %d: %+v", n, dst["new"])
+		}
+		sid := dst["new"][0]
+		if sid.Expires != 1900000000 || sid.SameSite != "None" || !sid.Secure || sid.Session {
+			t.Errorf("sid not normalized: %+v", sid)
+		}
+		if pref := dst["new"][1]; !pref.Session || pref.Expires != 0 {
+			t.Errorf("pref not a session cookie: %+v", pref)
+		}
+	})
+
+	t.Run("into BitBrowser", func(t *testing.T) {
+		var got SetCookiesRequest
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&got)
+			w.Write(successResponse(nil))
+		})
+		defer server.Close()
+
+		n, err := SyncCookies(context.Background(), src, "old", mustNew(t, server.URL), "new")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 3 || got.BrowserID != "new" || len(got.Cookies) != 3 {
+			t.Errorf("synced %d, request %+v", n, got)
+		}
+	})
+
+	t.Run("source failure", func(t *testing.T) {
+		if _, err := SyncCookies(context.Background(), src, "missing", memoryCookies{}, "new"); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestFilterCookies(t *testing.T) {
+	cookies := []Cookie{{Domain: ".example.com"}, {Domain: "a.example.com"}, {Domain: "badexample.com"}, {Domain: "EXAMPLE.org"}}
+
+	if got := FilterCookies(cookies); len(got) != 4 {
+		t.Errorf("no domains: got %d cookies, want 4", len(got))
+	}
+	if got := FilterCookies(cookies, "example.com"); len(got) != 2 {
+		t.Errorf("example.com: got %+v", got)
+	}
+	if got := FilterCookies(cookies, ".example.org"); len(got) != 1 {
+		t.Errorf("example.org: got %+v", got)
+	}
+}
+
+func TestParseCookies(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Cookie
+	}{
+		{
+			name: "browser extension",
+			data: `[{"name":"a","value":"1","domain":".x.com","hostOnly":false,"expirationDate":1900000000.5,"sameSite":"lax","path":"/"}]`,
+			want: Cookie{Name: "a", Value: "1", Domain: ".x.com", Path: "/", Expires: 1900000000.5, SameSite: "Lax"},
+		},
+		{
+			name: "host-only cookie",
+			data: `[{"name":"a","value":"1","domain":".x.com","hostOnly":true,"session":true,"sameSite":"unspecified"}]`,
+			want: Cookie{Name: "a", Value: "1", Domain: "x.com", Path: "/", Session: true},
+		},
+		{
+			name: "selenium",
+			data: `[{"name":"a","value":"1","domain":"x.com","expiry":1900000000,"httpOnly":true,"secure":true}]`,
+			want: Cookie{Name: "a", Value: "1", Domain: "x.com", Path: "/", Expires: 1900000000, HttpOnly: true, Secure: true},
+		},
+		{
+			name: "wrapped CDP",
+			data: `{"cookies":[{"name":"a","value":"1","domain":"x.com","path":"/p","expires":-1,"sameSite":"Strict"}]}`,
+			want: Cookie{Name: "a", Value: "1", Domain: "x.com", Path: "/p", Session: true, SameSite: "Strict"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookies, err := ParseCookies([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(cookies) != 1 || cookies[0] != tt.want {
+				t.Errorf("got %+v, want %+v", cookies, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseCookies([]byte(`"nope"`)); err == nil {
+		t.Error("expected error for invalid input")
+	}
+}
@@ -0,0 +1,252 @@
+package bitbrowser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Default cookie watch settings.
+const (
+	DefaultCookieExpiryWindow  = 24 * time.Hour
+	DefaultCookieWatchInterval = 10 * time.Minute
+)
+
+// CookieExpiry describes a cookie that has expired or expires soon.
+type CookieExpiry struct {
+	Cookie
+	ExpiresAt time.Time
+	Expired   bool
+}
+
+// AnalyzeCookies returns, keyed by domain (without a leading dot), the
+// cookies that have expired or expire within window, soonest first.
+// Browser-session cookies never expire by date and are left out. A window
+// of zero uses DefaultCookieExpiryWindow.
+func AnalyzeCookies(cookies []Cookie, window time.Duration) map[string][]CookieExpiry {
+	return AnalyzeCookiesAt(cookies, SystemClock.Now(), window)
+}
+
+// AnalyzeCookiesAt is AnalyzeCookies at a given time, e.g. the Now of a
+// Clock, so that the result does not depend on the wall clock.
+func AnalyzeCookiesAt(cookies []Cookie, now time.Time, window time.Duration) map[string][]CookieExpiry {
+	if window <= 0 {
+		window = DefaultCookieExpiryWindow
+	}
+	result := make(map[string][]CookieExpiry)
+	for _, c := range cookies {
+		expiresAt, ok := cookieExpiry(c)
+		if !ok || expiresAt.Sub(now) > window {
+			continue
+		}
+		domain := strings.TrimPrefix(c.Domain, ".")
+		result[domain] = append(result[domain], CookieExpiry{Cookie: c, ExpiresAt: expiresAt, Expired: !expiresAt.After(now)})
+	}
+	for _, expiring := range result {
+		slices.SortFunc(expiring, func(a, b CookieExpiry) int { return a.ExpiresAt.Compare(b.ExpiresAt) })
+	}
+	return result
+}
+
+// cookieExpiry returns when a cookie expires, or false for session cookies.
+func cookieExpiry(c Cookie) (time.Time, bool) {
+	if c.Session || c.Expires <= 0 {
+		return time.Time{}, false
+	}
+	expires := c.Expires
+	if expires > 1e11 {
+		expires /= 1000 // Milliseconds
+	}
+	sec, frac := math.Modf(expires)
+	return time.Unix(int64(sec), int64(frac*1e9)), true
+}
+
+// KeyCookie identifies a cookie a session depends on.
+type KeyCookie struct {
+	Domain string // Matches the domain and its subdomains
+	Name   string
+}
+
+// CookieAlertReason is why a CookieAlert was raised.
+type CookieAlertReason string
+
+// Cookie alert reasons.
+const (
+	CookieMissing  CookieAlertReason = "missing"
+	CookieExpired  CookieAlertReason = "expired"
+	CookieExpiring CookieAlertReason = "expiring"
+)
+
+// CookieAlert reports a key cookie of a profile that is missing, expired
+// or about to expire.
+type CookieAlert struct {
+	ProfileID string            `json:"profileId"`
+	Domain    string            `json:"domain"`
+	Name      string            `json:"name"`
+	Reason    CookieAlertReason `json:"reason"`
+	ExpiresAt time.Time         `json:"expiresAt,omitzero"` // Zero if missing
+	Time      time.Time         `json:"time"`
+}
+
+// CookieWatchConfig configures a CookieWatcher.
+type CookieWatchConfig struct {
+	// KeyCookies are the cookies whose absence or expiry means the session
+	// is dead or dying.
+	KeyCookies []KeyCookie
+
+	// Window is how far ahead expiring cookies are reported
+	// (default: DefaultCookieExpiryWindow).
+	Window time.Duration
+
+	// Interval is the time between checks in Watch
+	// (default: DefaultCookieWatchInterval).
+	Interval time.Duration
+
+	// OnAlert is called for every alert.
+	OnAlert func(CookieAlert)
+
+	// OnError is called by Watch when a check fails, e.g. because the
+	// profile is not running.
+	OnError func(id string, err error)
+
+	// WebhookURL, if set, receives each check's alerts as a JSON array
+	// via POST. Checks without alerts post nothing.
+	WebhookURL string
+
+	// HTTPClient sends webhook requests (default: http.DefaultClient).
+	HTTPClient *http.Client
+
+	// Clock is the time source (default: SystemClock).
+	Clock Clock
+}
+
+// CookieWatcher checks the key cookies of running profiles and raises
+// alerts when they are missing or expired, as an early warning for dead
+// sessions.
+//
+// Example:
+//
+//	watcher := bitbrowser.NewCookieWatcher(client, bitbrowser.CookieWatchConfig{
+//	    KeyCookies: []bitbrowser.KeyCookie{{Domain: "example.com", Name: "session_id"}},
+//	    WebhookURL: "https://hooks.example.com/cookies",
+//	})
+//	err := watcher.Watch(ctx, profileID)
+type CookieWatcher struct {
+	provider CookieProvider
+	config   CookieWatchConfig
+}
+
+// NewCookieWatcher creates a CookieWatcher reading cookies from provider.
+func NewCookieWatcher(provider CookieProvider, config CookieWatchConfig) *CookieWatcher {
+	if config.Window <= 0 {
+		config.Window = DefaultCookieExpiryWindow
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultCookieWatchInterval
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+	return &CookieWatcher{provider: provider, config: config}
+}
+
+// Check reads the cookies of a running profile once, delivers an alert for
+// each key cookie that is missing, expired or expiring, and returns the
+// alerts. Webhook failures are returned together with the alerts.
+func (w *CookieWatcher) Check(ctx context.Context, id string) ([]CookieAlert, error) {
+	cookies, err := w.provider.GetCookies(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: cookie check failed: %w", err)
+	}
+
+	now := w.config.Clock.Now()
+	var alerts []CookieAlert
+	for _, key := range w.config.KeyCookies {
+		alert := CookieAlert{ProfileID: id, Domain: key.Domain, Name: key.Name, Reason: CookieMissing, Time: now}
+		for _, c := range cookies {
+			if c.Name != key.Name || !cookieMatchesDomain(c.Domain, []string{key.Domain}) {
+				continue
+			}
+			expiresAt, ok := cookieExpiry(c)
+			if !ok || expiresAt.Sub(now) > w.config.Window {
+				alert.Reason = "" // Healthy
+				break
+			}
+			// Keep the copy that lives longest
+			if alert.Reason == CookieMissing || expiresAt.After(alert.ExpiresAt) {
+				alert.ExpiresAt = expiresAt
+				alert.Reason = CookieExpiring
+				if !expiresAt.After(now) {
+					alert.Reason = CookieExpired
+				}
+			}
+		}
+		if alert.Reason != "" {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+	if w.config.OnAlert != nil {
+		for _, alert := range alerts {
+			w.config.OnAlert(alert)
+		}
+	}
+	if w.config.WebhookURL != "" {
+		if err := w.post(ctx, alerts); err != nil {
+			return alerts, err
+		}
+	}
+	return alerts, nil
+}
+
+// Watch checks the profiles every Interval until ctx is done and then
+// returns ctx.Err(). Failed checks are reported to OnError.
+func (w *CookieWatcher) Watch(ctx context.Context, ids ...string) error {
+	for {
+		for _, id := range ids {
+			if _, err := w.Check(ctx, id); err != nil && w.config.OnError != nil && ctx.Err() == nil {
+				w.config.OnError(id, err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.config.Clock.After(w.config.Interval):
+		}
+	}
+}
+
+// post sends alerts to the webhook.
+func (w *CookieWatcher) post(ctx context.Context, alerts []CookieAlert) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("bitbrowser: cookie webhook failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.config.HTTPClient.Do(req)
+	if err != nil {
+		return NewNetworkError("cookie webhook", w.config.WebhookURL, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return NewAPIError(w.config.WebhookURL, resp.StatusCode, resp.Status)
+	}
+	return nil
+}
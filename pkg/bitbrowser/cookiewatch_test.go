@@ -0,0 +1,112 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeCookies(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	at := func(d time.Duration) float64 { return float64(now.Add(d).Unix()) }
+	cookies := []Cookie{
+		{Name: "later", Domain: ".example.com", Expires: at(2 * time.Hour)},
+		{Name: "soon", Domain: "example.com", Expires: at(time.Hour)},
+		{Name: "gone", Domain: "other.com", Expires: at(-time.Hour) * 1000}, // Milliseconds
+		{Name: "fine", Domain: "example.com", Expires: at(48 * time.Hour)},
+		{Name: "session", Domain: "example.com", Session: true},
+	}
+
+	result := AnalyzeCookiesAt(cookies, now, 0)
+	if len(result) != 2 {
+		t.Fatalf("domains = %v, want example.com and other.com", result)
+	}
+	if got := result["example.com"]; len(got) != 2 || got[0].Name != "soon" || got[1].Name != "later" || got[0].Expired {
+		t.Errorf("example.com = %+v", got)
+	}
+	if got := result["other.com"]; len(got) != 1 || !got[0].Expired || !got[0].ExpiresAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("other.com = %+v", got)
+	}
+
+	if got := AnalyzeCookiesAt(cookies, now, 90*time.Minute)["example.com"]; len(got) != 1 {
+		t.Errorf("90m window: %+v", got)
+	}
+}
+
+func TestCookieWatcher(t *testing.T) {
+	clock := NewFakeClock(time.Unix(1_800_000_000, 0))
+	at := func(d time.Duration) float64 { return float64(clock.Now().Add(d).Unix()) }
+	provider := memoryCookies{"p1": {
+		{Name: "sid", Domain: ".example.com", Expires: at(time.Hour)},
+		{Name: "sid", Domain: "www.example.com", Expires: at(30 * time.Minute)},
+		{Name: "auth", Domain: "shop.com", Expires: at(-time.Minute)},
+		{Name: "token", Domain: "api.com", Session: true},
+	}}
+
+	var posted []CookieAlert
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&posted)
+	}))
+	defer webhook.Close()
+
+	var hooked []CookieAlert
+	watcher := NewCookieWatcher(provider, CookieWatchConfig{
+		KeyCookies: []KeyCookie{
+			{Domain: "example.com", Name: "sid"},
+			{Domain: "shop.com", Name: "auth"},
+			{Domain: "api.com", Name: "token"},
+			{Domain: "example.com", Name: "csrf"},
+		},
+		OnAlert:    func(a CookieAlert) { hooked = append(hooked, a) },
+		WebhookURL: webhook.URL,
+		Clock:      clock,
+	})
+
+	alerts, err := watcher.Check(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]CookieAlertReason{"sid": CookieExpiring, "auth": CookieExpired, "csrf": CookieMissing}
+	if len(alerts) != len(want) {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+	for _, a := range alerts {
+		if a.Reason != want[a.Name] || a.ProfileID != "p1" {
+			t.Errorf("alert %+v, want reason %q", a, want[a.Name])
+		}
+		if a.Name == "sid" && !a.ExpiresAt.Equal(clock.Now().Add(time.Hour)) {
+			t.Errorf("sid ExpiresAt = %v, want the longest-lived copy", a.ExpiresAt)
+		}
+	}
+	if len(hooked) != 3 || len(posted) != 3 {
+		t.Errorf("hook got %d, webhook got %d alerts", len(hooked), len(posted))
+	}
+
+	t.Run("profile not running", func(t *testing.T) {
+		if _, err := watcher.Check(context.Background(), "p2"); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("watch repeats checks", func(t *testing.T) {
+		hooked = nil
+		var failed []string
+		watcher.config.OnError = func(id string, err error) { failed = append(failed, id) }
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- watcher.Watch(ctx, "p1", "p2") }()
+
+		clock.BlockUntil(1)
+		clock.Advance(DefaultCookieWatchInterval)
+		clock.BlockUntil(1)
+		cancel()
+		<-done
+		if len(hooked) != 6 || len(failed) != 2 {
+			t.Errorf("alerts = %d, failures = %v", len(hooked), failed)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Limits of what CrashLogs collects, so that a report stays small enough to
+// attach to an error.
+const (
+	crashLogMaxSize  = 1 << 20  // Tail of chrome_debug.log
+	crashDumpMaxSize = 32 << 20 // Larger dumps are listed without data
+	crashDumpMax     = 3        // Most recent dumps
+)
+
+// CrashLogs are the Chrome log and crash dumps of a browser, collected from
+// the BitBrowser host.
+type CrashLogs struct {
+	Log   []byte      `json:"log,omitempty"`   // Tail of chrome_debug.log
+	Dumps []CrashDump `json:"dumps,omitempty"` // Most recent minidumps first
+}
+
+// CrashDump is a minidump written by Chrome's crash handler.
+type CrashDump struct {
+	Name    string    `json:"name"`
+	ModTime time.Time `json:"modTime"`
+	Size    int64     `json:"size"`
+	Data    []byte    `json:"data,omitempty"` // Nil if the dump is too large
+}
+
+// Empty reports whether nothing was collected.
+func (l *CrashLogs) Empty() bool {
+	return l == nil || (len(l.Log) == 0 && len(l.Dumps) == 0)
+}
+
+// CrashCollector reads the Chrome log and crash dumps that a browser wrote
+// to a directory on the BitBrowser host. It is used by CrashLogs.
+type CrashCollector interface {
+	// CollectCrashLogs reads dir/chrome_debug.log and the minidumps below
+	// dir. A missing dir is not an error.
+	CollectCrashLogs(ctx context.Context, dir string) (*CrashLogs, error)
+}
+
+// CrashCollectorFunc adapts a function to the CrashCollector interface.
+type CrashCollectorFunc func(ctx context.Context, dir string) (*CrashLogs, error)
+
+// CollectCrashLogs calls f(ctx, dir).
+func (f CrashCollectorFunc) CollectCrashLogs(ctx context.Context, dir string) (*CrashLogs, error) {
+	return f(ctx, dir)
+}
+
+// LocalCrashCollector reads crash logs from this machine's file system. It
+// is only correct when BitBrowser runs on the same host as the client. Use
+// an AgentClient for remote hosts.
+var LocalCrashCollector CrashCollector = CrashCollectorFunc(collectLocalCrashLogs)
+
+// WithCrashLogs launches every browser with Chrome logging enabled and a
+// crash dump directory, both in a per-profile directory below dir on the
+// BitBrowser host:
+//
+//	--enable-logging --log-file=<dir>/<profile ID>/chrome_debug.log
+//	--crash-dumps-dir=<dir>/<profile ID>/crashes
+//
+// CrashLogs collects them with collector once a browser has died, e.g. so
+// that a worker can attach them to the failed job.
+//
+// Example:
+//
+//	agent := bitbrowser.NewAgentClient("http://10.0.0.5:54350", token)
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithCrashLogs(`D:\chrome-logs`, agent))
+func WithCrashLogs(dir string, collector CrashCollector) ClientOption {
+	return func(c *Client) {
+		c.crashLogDir = dir
+		c.crashCollector = collector
+	}
+}
+
+// crashLogArgs returns the launch arguments of WithCrashLogs, with the
+// profile ID left as a launch argument variable.
+func (c *Client) crashLogArgs() []string {
+	if c.crashLogDir == "" {
+		return nil
+	}
+	dir := hostJoin(c.crashLogDir, "{{profileId}}")
+	return []string{
+		"--enable-logging",
+		"--log-file=" + hostJoin(dir, "chrome_debug.log"),
+		"--crash-dumps-dir=" + hostJoin(dir, "crashes"),
+	}
+}
+
+// hostJoin joins path elements with the separator dir already uses, so
+// that paths for a Windows host can be built on any client.
+func hostJoin(dir string, elem ...string) string {
+	sep := "/"
+	if strings.Contains(dir, `\`) && !strings.Contains(dir, "/") {
+		sep = `\`
+	}
+	return strings.TrimRight(dir, sep) + sep + strings.Join(elem, sep)
+}
+
+// CrashLogs collects the Chrome log and crash dumps of a profile whose
+// browser has died, as set up by WithCrashLogs. It returns nil and no
+// error while the browser is still running, so it can be called after any
+// failure to find out whether the browser crashed.
+//
+// Example:
+//
+//	if err := runSession(ctx, browser); err != nil {
+//	    if logs, _ := client.CrashLogs(ctx, profileID); !logs.Empty() {
+//	        report.Attach("chrome_debug.log", logs.Log)
+//	    }
+//	}
+func (c *Client) CrashLogs(ctx context.Context, id string) (*CrashLogs, error) {
+	if c.crashLogDir == "" || c.crashCollector == nil {
+		return nil, NewValidationError("CrashCollector", "crash logs are not configured; use WithCrashLogs")
+	}
+	pid, err := c.alivePID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pid != 0 {
+		return nil, nil
+	}
+	logs, err := c.crashCollector.CollectCrashLogs(ctx, hostJoin(c.crashLogDir, id))
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: collect crash logs of %s: %w", id, err)
+	}
+	return logs, nil
+}
+
+// collectLocalCrashLogs reads dir on this machine.
+func collectLocalCrashLogs(ctx context.Context, dir string) (*CrashLogs, error) {
+	logs := &CrashLogs{}
+	log, err := readTail(filepath.Join(dir, "chrome_debug.log"), crashLogMaxSize)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	logs.Log = log
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".dmp") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // Removed meanwhile
+		}
+		logs.Dumps = append(logs.Dumps, CrashDump{Name: path, ModTime: info.ModTime(), Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(logs.Dumps, func(a, b CrashDump) int { return b.ModTime.Compare(a.ModTime) })
+	logs.Dumps = logs.Dumps[:min(len(logs.Dumps), crashDumpMax)]
+	for i := range logs.Dumps {
+		d := &logs.Dumps[i]
+		if d.Size <= crashDumpMaxSize {
+			if d.Data, err = os.ReadFile(d.Name); err != nil {
+				return nil, err
+			}
+		}
+		d.Name = filepath.Base(d.Name)
+	}
+	return logs, nil
+}
+
+// readTail reads at most the last n bytes of a file.
+func readTail(name string, n int64) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > n {
+		if _, err := f.Seek(-n, io.SeekEnd); err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(f)
+}
@@ -0,0 +1,127 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHostJoin(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"/var/log/chrome", "/var/log/chrome/p1/crashes"},
+		{"/var/log/chrome/", "/var/log/chrome/p1/crashes"},
+		{`D:\chrome-logs`, `D:\chrome-logs\p1\crashes`},
+		{"D:/chrome-logs", "D:/chrome-logs/p1/crashes"},
+	}
+	for _, tt := range tests {
+		if got := hostJoin(tt.dir, "p1", "crashes"); got != tt.want {
+			t.Errorf("hostJoin(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestWithCrashLogs(t *testing.T) {
+	var args []string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var config OpenConfig
+		json.NewDecoder(r.Body).Decode(&config)
+		args = config.Args
+		w.Write(successResponse(OpenResult{Ws: "ws://127.0.0.1:9222/devtools/browser/abc"}))
+	})
+	defer server.Close()
+
+	client := mustNew(t, server.URL, WithCrashLogs(`D:\logs`, LocalCrashCollector))
+	if _, err := client.Open(context.Background(), "profile-1", nil); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, want := range []string{
+		"--enable-logging",
+		`--log-file=D:\logs\profile-1\chrome_debug.log`,
+		`--crash-dumps-dir=D:\logs\profile-1\crashes`,
+	} {
+		if !slices.Contains(args, want) {
+			t.Errorf("args = %v, want %s", args, want)
+		}
+	}
+}
+
+func TestLocalCrashCollector(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	logs, err := LocalCrashCollector.CollectCrashLogs(ctx, filepath.Join(dir, "missing"))
+	if err != nil || !logs.Empty() {
+		t.Fatalf("missing dir: logs = %+v, err = %v; want empty", logs, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "chrome_debug.log"), []byte(strings.Repeat("x", crashLogMaxSize)+"tail"), 0o644)
+	reports := filepath.Join(dir, "crashes", "reports")
+	os.MkdirAll(reports, 0o755)
+	for i, name := range []string{"a.dmp", "b.dmp", "c.dmp", "d.dmp", "notes.txt"} {
+		path := filepath.Join(reports, name)
+		os.WriteFile(path, []byte(name), 0o644)
+		mtime := time.Unix(int64(1000+i), 0)
+		os.Chtimes(path, mtime, mtime)
+	}
+
+	logs, err = LocalCrashCollector.CollectCrashLogs(ctx, dir)
+	if err != nil {
+		t.Fatalf("CollectCrashLogs failed: %v", err)
+	}
+	if len(logs.Log) != crashLogMaxSize || !strings.HasSuffix(string(logs.Log), "tail") {
+		t.Errorf("log has %d bytes, want the last %d", len(logs.Log), crashLogMaxSize)
+	}
+	var names []string
+	for _, d := range logs.Dumps {
+		names = append(names, d.Name)
+		if string(d.Data) != d.Name {
+			t.Errorf("dump %s data = %q", d.Name, d.Data)
+		}
+	}
+	if want := []string{"d.dmp", "c.dmp", "b.dmp"}; !slices.Equal(names, want) {
+		t.Errorf("dumps = %v, want %v", names, want)
+	}
+}
+
+func TestCrashLogs(t *testing.T) {
+	ctx := context.Background()
+	alive := map[string]int{"running": 101}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(successResponse(alive))
+	})
+	defer server.Close()
+
+	var dirs []string
+	collector := CrashCollectorFunc(func(_ context.Context, dir string) (*CrashLogs, error) {
+		dirs = append(dirs, dir)
+		return &CrashLogs{Log: []byte("crashed")}, nil
+	})
+	client := mustNew(t, server.URL, WithCrashLogs("/logs", collector))
+
+	logs, err := client.CrashLogs(ctx, "running")
+	if err != nil || logs != nil {
+		t.Errorf("running browser: logs = %+v, err = %v; want nil", logs, err)
+	}
+	logs, err = client.CrashLogs(ctx, "dead")
+	if err != nil || string(logs.Log) != "crashed" {
+		t.Errorf("dead browser: logs = %+v, err = %v", logs, err)
+	}
+	if !slices.Equal(dirs, []string{"/logs/dead"}) {
+		t.Errorf("collected %v, want /logs/dead", dirs)
+	}
+
+	client = mustNew(t, server.URL)
+	if _, err := client.CrashLogs(ctx, "dead"); !errors.Is(err, ErrValidation) {
+		t.Errorf("err = %v without WithCrashLogs, want ErrValidation", err)
+	}
+}
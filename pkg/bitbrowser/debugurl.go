@@ -0,0 +1,43 @@
+package bitbrowser
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// DebugURL returns the current DevTools WebSocket URL of a running profile.
+// The debugging port comes from PortOf and the URL from the browser's
+// /json/version, with its host replaced by the API host so that it is
+// reachable wherever the API is. It returns ErrBrowserNotRunning if the
+// profile has no debugging port.
+//
+// DebugURL fits cdp.Resolver, for connections that must follow a browser
+// across restarts:
+//
+//	mc, err := cdp.DialManaged(ctx, func(ctx context.Context) (string, error) {
+//	    return client.DebugURL(ctx, id)
+//	}, cdp.ManagedConfig{})
+func (c *Client) DebugURL(ctx context.Context, id string) (string, error) {
+	port, err := c.PortOf(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	host, err := extractHost(c.apiURL)
+	if err != nil {
+		return "", NewValidationError("apiURL", err.Error())
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	version, err := c.GetBrowserVersion(ctx, "http://"+addr)
+	if err != nil {
+		return "", err
+	}
+	ws, err := url.Parse(version.WebSocketDebuggerURL)
+	if err != nil || ws.Host == "" {
+		return "", NewAPIError("/json/version", 0, "invalid webSocketDebuggerUrl: "+version.WebSocketDebuggerURL)
+	}
+	ws.Host = addr
+	return ws.String(), nil
+}
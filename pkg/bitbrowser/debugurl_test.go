@@ -0,0 +1,37 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestDebugURL(t *testing.T) {
+	var port string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/browser/ports":
+			w.Write(successResponse(map[string]string{"profile-1": port}))
+		case "/json/version":
+			w.Write([]byte(`{"webSocketDebuggerUrl":"ws://127.0.0.1:1/devtools/browser/abc"}`))
+		}
+	})
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port = u.Port()
+
+	client := mustNew(t, server.URL)
+	got, err := client.DebugURL(context.Background(), "profile-1")
+	if err != nil {
+		t.Fatalf("DebugURL() error = %v", err)
+	}
+	if want := "ws://127.0.0.1:" + port + "/devtools/browser/abc"; got != want {
+		t.Errorf("DebugURL() = %q, want %q", got, want)
+	}
+
+	if _, err := client.DebugURL(context.Background(), "profile-2"); !errors.Is(err, ErrBrowserNotRunning) {
+		t.Errorf("err = %v, want ErrBrowserNotRunning", err)
+	}
+}
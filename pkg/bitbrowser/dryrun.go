@@ -0,0 +1,44 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// dryRunIDs numbers the synthesized profile IDs returned in dry-run mode.
+var dryRunIDs atomic.Int64
+
+// isMutating reports whether requests to path change state in BitBrowser.
+func isMutating(path string) bool {
+	return endpoints[path].mutates
+}
+
+// dryRunRequest logs a mutating request instead of sending it and fills
+// respBody with a synthesized success response.
+func (c *Client) dryRunRequest(ctx context.Context, path string, jsonData []byte, respBody any) error {
+	var params map[string]any
+	json.Unmarshal(jsonData, &params)
+
+	logger := c.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	redacted, _ := json.Marshal(redact(params))
+	logger.InfoContext(ctx, "bitbrowser: dry run, request not sent", withRequestID(ctx, []any{
+		slog.String("path", path),
+		slog.String("params", string(redacted)),
+	})...)
+
+	data := json.RawMessage("{}")
+	if path == "/browser/update" && len(profileIDs(params)) == 0 {
+		data, _ = json.Marshal(map[string]string{"id": fmt.Sprintf("dry-run-%d", dryRunIDs.Add(1))})
+	}
+
+	if resp, ok := respBody.(*Response); ok {
+		*resp = Response{Success: true, Data: data}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package bitbrowser
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Run("mutating calls do not reach the API", func(t *testing.T) {
+		var requests atomic.Int32
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.Write(errorResponse("should not be called"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithDryRun(true), WithLogger(slog.New(slog.DiscardHandler)))
+		ctx := context.Background()
+
+		id, err := client.CreateProfile(ctx, ProfileConfig{Name: "test"})
+		if err != nil {
+			t.Fatalf("CreateProfile: unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(id, "dry-run-") {
+			t.Errorf("id = %q, want dry-run- prefix", id)
+		}
+		if err := client.UpdateProfile(ctx, ProfileConfig{ID: id, Name: "renamed"}); err != nil {
+			t.Errorf("UpdateProfile: unexpected error: %v", err)
+		}
+		if err := client.DeleteProfiles(ctx, []string{"a", "b"}); err != nil {
+			t.Errorf("DeleteProfiles: unexpected error: %v", err)
+		}
+		if _, err := client.Open(ctx, "profile-1", &OpenOptions{WaitReady: true}); err != nil {
+			t.Errorf("Open: unexpected error: %v", err)
+		}
+		if err := client.Close(ctx, "profile-1"); err != nil {
+			t.Errorf("Close: unexpected error: %v", err)
+		}
+		if _, err := client.RandomizeFingerprint(ctx, "profile-1"); err != nil {
+			t.Errorf("RandomizeFingerprint: unexpected error: %v", err)
+		}
+
+		if n := requests.Load(); n != 0 {
+			t.Errorf("requests = %d, want 0", n)
+		}
+	})
+
+	t.Run("read-only calls still reach the API", func(t *testing.T) {
+		var requests atomic.Int32
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.Write(successResponse(map[string]string{"profile-1": "9222"}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithDryRun(true))
+		ports, err := client.GetPorts(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ports["profile-1"] != 9222 || requests.Load() != 1 {
+			t.Errorf("ports = %v, requests = %d", ports, requests.Load())
+		}
+	})
+
+	t.Run("logs the would-be request with secrets redacted", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+		client := mustNew(t, "http://127.0.0.1:1", WithDryRun(true), WithLogger(logger))
+
+		err := client.UpdateProxy(context.Background(), ProxyUpdateRequest{
+			IDs:           []string{"profile-1"},
+			ProxyMethod:   ProxyMethodCustom,
+			ProxyPassword: "p4ss",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		out := buf.String()
+		if !strings.Contains(out, "dry run") || !strings.Contains(out, "/browser/proxy/update") {
+			t.Errorf("expected dry-run log, got: %s", out)
+		}
+		if strings.Contains(out, "p4ss") {
+			t.Errorf("password leaked into log: %s", out)
+		}
+	})
+}
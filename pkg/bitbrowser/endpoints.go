@@ -0,0 +1,179 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// endpoint describes a BitBrowser API endpoint.
+type endpoint struct {
+	name    string // Client method, also the operation recorded in audit events
+	op      string // Operation used in error messages, e.g. "get pids"
+	audited bool   // Recorded by the audit logger
+	mutates bool   // Changes state in BitBrowser; skipped in dry-run mode
+	hedge   bool   // Idempotent read that may be hedged
+
+	request  reflect.Type // Request body
+	response reflect.Type // Data of a successful response (nil if none)
+}
+
+// Request bodies shared by several endpoints.
+type (
+	idBody struct {
+		ID string `json:"id"`
+	}
+	idsBody struct {
+		IDs []string `json:"ids"`
+	}
+	browserIDBody struct {
+		BrowserID string `json:"browserId"`
+	}
+	seqsBody struct {
+		Seqs []int `json:"seqs"`
+	}
+	seqListBody struct {
+		SeqList []int `json:"seqlist"`
+	}
+	emptyBody struct{}
+)
+
+// groupBody is the request body of /browser/group/update.
+type groupBody struct {
+	GroupID    string   `json:"groupId"`
+	BrowserIDs []string `json:"browserIds"`
+}
+
+// remarkBody is the request body of /browser/remark/update.
+type remarkBody struct {
+	Remark     string   `json:"remark"`
+	BrowserIDs []string `json:"browserIds"`
+}
+
+// typeOf is shorthand for reflect.TypeFor in the endpoint registry.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// endpoints is the registry of API endpoints, keyed by path. Adding an
+// endpoint takes an entry here and a method built on call or exec.
+var endpoints = map[string]endpoint{
+	"/health": {name: "Health", op: "health check", hedge: true, request: typeOf[emptyBody]()},
+
+	// Profiles. /browser/update is also CreateProfile when no ID is given.
+	"/browser/update": {name: "UpdateProfile", op: "update profile", audited: true, mutates: true,
+		request: typeOf[ProfileConfig](), response: typeOf[idBody]()},
+	"/browser/update/partial": {name: "UpdateProfilePartial", op: "partial update", audited: true, mutates: true,
+		request: typeOf[PartialUpdateRequest]()},
+	"/browser/detail": {name: "GetProfileDetail", op: "get profile detail", hedge: true,
+		request: typeOf[idBody](), response: typeOf[ProfileDetail]()},
+	"/browser/list": {name: "ListProfiles", op: "list profiles", hedge: true,
+		request: typeOf[ListRequest](), response: typeOf[ListResult]()},
+	"/browser/delete": {name: "DeleteProfile", op: "delete profile", audited: true, mutates: true,
+		request: typeOf[idBody]()},
+	"/browser/delete/ids": {name: "DeleteProfiles", op: "batch delete", audited: true, mutates: true,
+		request: typeOf[idsBody]()},
+	"/browser/closing/reset": {name: "ResetClosingState", op: "reset closing state", mutates: true,
+		request: typeOf[idBody]()},
+
+	// Browsers and processes
+	"/browser/open": {name: "Open", op: "open browser", mutates: true,
+		request: typeOf[OpenConfig](), response: typeOf[OpenResult]()},
+	"/browser/close": {name: "Close", op: "close browser", mutates: true,
+		request: typeOf[idBody]()},
+	"/browser/close/byseqs": {name: "CloseBySeqs", op: "close by seqs", mutates: true,
+		request: typeOf[seqsBody]()},
+	"/browser/close/all": {name: "CloseAll", op: "close all", mutates: true,
+		request: typeOf[emptyBody]()},
+	"/browser/pids": {name: "GetPIDs", op: "get pids", hedge: true,
+		request: typeOf[idsBody](), response: typeOf[map[string]int]()},
+	"/browser/pids/all": {name: "GetAllPIDs", op: "get all pids", hedge: true,
+		request: typeOf[emptyBody](), response: typeOf[map[string]int]()},
+	"/browser/pids/alive": {name: "GetAlivePIDs", op: "get alive pids", hedge: true,
+		request: typeOf[idsBody](), response: typeOf[map[string]int]()},
+	"/browser/ports": {name: "GetPorts", op: "get ports", hedge: true,
+		request: typeOf[emptyBody](), response: typeOf[map[string]string]()},
+
+	// Proxies and groups
+	"/browser/proxy/update": {name: "UpdateProxy", op: "update proxy", audited: true, mutates: true,
+		request: typeOf[ProxyUpdateRequest]()},
+	"/checkagent": {name: "CheckProxy", op: "check proxy",
+		request: typeOf[ProxyCheckRequest](), response: typeOf[ProxyCheckResult]()},
+	"/browser/group/update": {name: "UpdateGroup", op: "update group", audited: true, mutates: true,
+		request: typeOf[groupBody]()},
+	"/browser/remark/update": {name: "UpdateRemark", op: "update remark", audited: true, mutates: true,
+		request: typeOf[remarkBody]()},
+
+	// Windows, cache and fingerprints
+	"/windowbounds": {name: "ArrangeWindows", op: "arrange windows", mutates: true,
+		request: typeOf[WindowBoundsRequest]()},
+	"/windowbounds/flexable": {name: "ArrangeWindowsFlexible", op: "flexible arrange", mutates: true,
+		request: typeOf[seqListBody]()},
+	"/alldisplays": {name: "GetAllDisplays", op: "get displays", hedge: true,
+		request: typeOf[emptyBody](), response: typeOf[[]Display]()},
+	"/cache/clear": {name: "ClearCache", op: "clear cache", audited: true, mutates: true,
+		request: typeOf[idsBody]()},
+	"/cache/clear/exceptExtensions": {name: "ClearCacheExceptExtensions", op: "clear cache except extensions", audited: true, mutates: true,
+		request: typeOf[idsBody]()},
+	"/browser/fingerprint/random": {name: "RandomizeFingerprint", op: "randomize fingerprint", audited: true, mutates: true,
+		request: typeOf[browserIDBody](), response: typeOf[Fingerprint]()},
+
+	// Cookies
+	"/browser/cookies/set": {name: "SetCookies", op: "set cookies", audited: true, mutates: true,
+		request: typeOf[SetCookiesRequest]()},
+	"/browser/cookies/get": {name: "GetCookies", op: "get cookies", hedge: true,
+		request: typeOf[browserIDBody](), response: typeOf[[]Cookie]()},
+	"/browser/cookies/clear": {name: "ClearCookies", op: "clear cookies", audited: true, mutates: true,
+		request: typeOf[ClearCookiesRequest]()},
+	"/browser/cookies/format": {name: "FormatCookies", op: "format cookies",
+		request: typeOf[FormatCookiesRequest](), response: typeOf[[]Cookie]()},
+
+	// RPA and utilities
+	"/rpa/run":  {name: "RunRPA", op: "run RPA", mutates: true, request: typeOf[RPARequest]()},
+	"/rpa/stop": {name: "StopRPA", op: "stop RPA", mutates: true, request: typeOf[RPARequest]()},
+	"/autopaste": {name: "AutoPaste", op: "auto paste", mutates: true,
+		request: typeOf[AutoPasteRequest]()},
+	"/utils/readexcel": {name: "ReadExcel", op: "read excel",
+		request: typeOf[FileRequest](), response: typeOf[any]()},
+	"/utils/readfile": {name: "ReadFile", op: "read file",
+		request: typeOf[FileRequest](), response: typeOf[string]()},
+}
+
+// call posts req to the endpoint at path and decodes the data of a
+// successful response into a T.
+func call[T any](ctx context.Context, c *Client, path string, req any) (T, error) {
+	var result T
+	data, err := c.post(ctx, path, req)
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("bitbrowser: failed to parse response: %w", err)
+	}
+	return result, nil
+}
+
+// exec posts req to the endpoint at path and ignores the response data.
+func (c *Client) exec(ctx context.Context, path string, req any) error {
+	_, err := c.post(ctx, path, req)
+	return err
+}
+
+// post posts req to the endpoint at path and returns the data of a
+// successful response. Failures are wrapped with the endpoint's operation.
+func (c *Client) post(ctx context.Context, path string, req any) (json.RawMessage, error) {
+	op := endpoints[path].op
+	if err := c.checkSupported(path); err != nil {
+		return nil, fmt.Errorf("bitbrowser: %s failed: %w", op, err)
+	}
+	var resp Response
+	if err := c.doRequest(ctx, path, req, &resp); err != nil {
+		c.recordUnsupported(path, err)
+		return nil, fmt.Errorf("bitbrowser: %s failed: %w", op, err)
+	}
+	if !resp.Success {
+		return nil, c.failed(ctx, path, &APIError{Endpoint: path, Op: op, Message: resp.Msg, raw: resp.raw})
+	}
+	return resp.Data, nil
+}
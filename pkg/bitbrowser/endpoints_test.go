@@ -0,0 +1,66 @@
+package bitbrowser
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestEndpointRegistry(t *testing.T) {
+	for path, ep := range endpoints {
+		if ep.name == "" || ep.op == "" || ep.request == nil {
+			t.Errorf("%s: incomplete entry %+v", path, ep)
+		}
+		if ep.audited && !ep.mutates {
+			t.Errorf("%s: audited endpoints must be mutating", path)
+		}
+		if ep.hedge && ep.mutates {
+			t.Errorf("%s: mutating endpoints must not be hedged", path)
+		}
+	}
+}
+
+func TestCall(t *testing.T) {
+	t.Run("decodes data", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(map[string]int{"profile-1": 42}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		pids, err := call[map[string]int](context.Background(), client, "/browser/pids/all", struct{}{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pids["profile-1"] != 42 {
+			t.Errorf("pids = %v", pids)
+		}
+	})
+
+	t.Run("wraps failures with the operation", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(errorResponse("boom"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		err := client.exec(context.Background(), "/browser/close/all", struct{}{})
+		if err == nil || err.Error() != "bitbrowser: close all failed: boom" {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	t.Run("reports undecodable data", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse("not a map"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		_, err := call[map[string]string](context.Background(), client, "/browser/ports", struct{}{})
+		if err == nil || !strings.Contains(err.Error(), "failed to parse response") {
+			t.Errorf("err = %v", err)
+		}
+	})
+}
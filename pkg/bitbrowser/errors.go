@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Sentinel errors for error type checking using errors.Is().
@@ -22,6 +25,57 @@ var (
 
 	// ErrRetryExhausted indicates all retry attempts have been exhausted.
 	ErrRetryExhausted = errors.New("retry exhausted")
+
+	// ErrRetryBudgetExhausted indicates retries were stopped by a shared RetryBudget.
+	ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+	// ErrBusy indicates BitBrowser is busy with the profile (e.g. "正在打开").
+	ErrBusy = errors.New("browser busy")
+
+	// ErrQuotaExceeded indicates the account's profile quota has been reached.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+
+	// ErrProfileNotFound indicates no fleet host has the profile, or no
+	// profile has a sequence number.
+	ErrProfileNotFound = errors.New("profile not found")
+
+	// ErrProfileLocked indicates the profile is in use by another team member or device.
+	ErrProfileLocked = errors.New("profile locked")
+
+	// ErrKernelDownloading indicates BitBrowser is still downloading the browser kernel.
+	ErrKernelDownloading = errors.New("kernel downloading")
+
+	// ErrInsufficientBalance indicates the account balance cannot cover the operation.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+
+	// ErrNotSupportedByVersion indicates the installed BitBrowser version does
+	// not have the endpoint (it answered 404).
+	ErrNotSupportedByVersion = errors.New("not supported by this BitBrowser version")
+
+	// ErrBrowserNotRunning indicates the profile has no live browser process.
+	ErrBrowserNotRunning = errors.New("browser not running")
+
+	// ErrNoCapacity indicates no fleet host can take another browser, or
+	// the cap set with WithMaxOpenBrowsers is reached.
+	ErrNoCapacity = errors.New("no capacity")
+
+	// ErrProfileNotOnHost indicates a fleet routed a profile to a host that does not have it.
+	ErrProfileNotOnHost = errors.New("profile not on host")
+
+	// ErrAccountBound indicates a platform account is already bound to another profile.
+	ErrAccountBound = errors.New("account already bound")
+
+	// ErrProxyCheckFailed indicates BitBrowser could not connect through a proxy.
+	ErrProxyCheckFailed = errors.New("proxy check failed")
+
+	// ErrProxyInUse indicates another profile of the same group or platform uses the exit IP.
+	ErrProxyInUse = errors.New("proxy in use")
+
+	// ErrNoMatchingProxy indicates no healthy proxy of the pool matches the criteria.
+	ErrNoMatchingProxy = errors.New("no matching proxy")
+
+	// ErrDraining indicates the service is shutting down and takes no new work.
+	ErrDraining = errors.New("service draining")
 )
 
 // NetworkError represents a network-level error.
@@ -48,13 +102,23 @@ func (e *NetworkError) Is(target error) bool {
 
 // APIError represents an API-level error from BitBrowser.
 type APIError struct {
-	StatusCode int    // HTTP status code (0 if not applicable)
-	Message    string // Error message from API
-	Endpoint   string // API endpoint that was called
-	Err        error  // Underlying error (if any)
+	StatusCode int           // HTTP status code (0 if not applicable)
+	Message    string        // Error message from API
+	Endpoint   string        // API endpoint that was called
+	Err        error         // Underlying error (if any)
+	RetryAfter time.Duration // Server-suggested delay from the Retry-After header (0 if absent)
+	Busy       bool          // BitBrowser reported the profile as busy
+	Quota      bool          // BitBrowser reported the profile quota as exhausted
+	Op         string        // Operation of an unsuccessful response, e.g. "close all"
+	Body       string        // Response body, truncated and redacted; set with WithResponseBodyCapture
+
+	raw []byte // Unprocessed response body
 }
 
 func (e *APIError) Error() string {
+	if e.Op != "" {
+		return fmt.Sprintf("bitbrowser: %s failed: %s", e.Op, e.Message)
+	}
 	if e.StatusCode != 0 {
 		return fmt.Sprintf("bitbrowser: API error on %s (status %d): %s", e.Endpoint, e.StatusCode, e.Message)
 	}
@@ -65,8 +129,18 @@ func (e *APIError) Unwrap() error {
 	return e.Err
 }
 
+// Is reports whether target is ErrAPI or the sentinel indicated by the
+// message, such as ErrBusy or ErrProfileLocked.
 func (e *APIError) Is(target error) bool {
-	return target == ErrAPI
+	switch {
+	case target == ErrAPI:
+		return true
+	case target == ErrBusy && e.Busy, target == ErrQuotaExceeded && e.Quota:
+		return true
+	case target == ErrNotSupportedByVersion:
+		return e.StatusCode == http.StatusNotFound
+	}
+	return target != nil && classifyMessage(e.Message) == target
 }
 
 // ValidationError represents an input validation error.
@@ -96,9 +170,21 @@ type TimeoutError struct {
 	Op       string // Operation that timed out
 	Duration string // Timeout duration (as string for display)
 	Err      error  // Underlying error
+
+	// Step and Elapsed are set for multi-step operations run with a
+	// Budget: the step that ran out of time and how long it ran.
+	Step    string
+	Elapsed time.Duration
 }
 
 func (e *TimeoutError) Error() string {
+	if e.Step != "" {
+		msg := fmt.Sprintf("bitbrowser: timeout during %s: step %s ran %s", e.Op, e.Step, e.Elapsed.Round(time.Millisecond))
+		if e.Duration != "" {
+			msg += " of its " + e.Duration
+		}
+		return msg
+	}
 	if e.Duration != "" {
 		return fmt.Sprintf("bitbrowser: timeout during %s after %s", e.Op, e.Duration)
 	}
@@ -113,13 +199,81 @@ func (e *TimeoutError) Is(target error) bool {
 	return target == ErrTimeout
 }
 
+// QuotaError is returned when a batch is rejected before any request is
+// sent because it would exceed the remaining profile quota.
+type QuotaError struct {
+	Requested int // Profiles the operation would create
+	Remaining int // Profiles that can still be created
+	Limit     int // Total profile limit
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("bitbrowser: quota exceeded: %d profiles requested, %d of %d remaining", e.Requested, e.Remaining, e.Limit)
+}
+
+func (e *QuotaError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// FleetError is a failure on one host of a FleetClient.
+type FleetError struct {
+	Host string // Name of the host that failed
+	Err  error  // Underlying error
+}
+
+func (e *FleetError) Error() string {
+	return fmt.Sprintf("bitbrowser: fleet host %q: %v", e.Host, e.Err)
+}
+
+func (e *FleetError) Unwrap() error {
+	return e.Err
+}
+
+// ProfileError is a failure for one profile of a call that handles many,
+// such as GetProfileDetails.
+type ProfileError struct {
+	ProfileID string // Profile that failed
+	Err       error  // Underlying error
+}
+
+func (e *ProfileError) Error() string {
+	return fmt.Sprintf("bitbrowser: profile %s: %v", e.ProfileID, e.Err)
+}
+
+func (e *ProfileError) Unwrap() error {
+	return e.Err
+}
+
+// ProfileNotOnHostError is returned when a fleet routes a profile to a host
+// that no longer has it, e.g. after the profile was moved to another machine.
+type ProfileNotOnHostError struct {
+	ProfileID string // Profile that was routed
+	Host      string // Host the profile was routed to
+	Hint      string // Host that has the profile, or "" if none was found
+}
+
+func (e *ProfileNotOnHostError) Error() string {
+	if e.Hint == "" {
+		return fmt.Sprintf("bitbrowser: profile %s is not on fleet host %q and no other host has it", e.ProfileID, e.Host)
+	}
+	return fmt.Sprintf("bitbrowser: profile %s is not on fleet host %q (found on %q)", e.ProfileID, e.Host, e.Hint)
+}
+
+func (e *ProfileNotOnHostError) Is(target error) bool {
+	return target == ErrProfileNotOnHost
+}
+
 // RetryError represents an error after all retry attempts have been exhausted.
 type RetryError struct {
-	Attempts int   // Number of attempts made
-	LastErr  error // The last error that occurred
+	Attempts        int   // Number of attempts made
+	LastErr         error // The last error that occurred
+	BudgetExhausted bool  // Retries were stopped by a shared RetryBudget
 }
 
 func (e *RetryError) Error() string {
+	if e.BudgetExhausted {
+		return fmt.Sprintf("bitbrowser: retry budget exhausted after %d attempts: %v", e.Attempts, e.LastErr)
+	}
 	return fmt.Sprintf("bitbrowser: retry exhausted after %d attempts: %v", e.Attempts, e.LastErr)
 }
 
@@ -128,6 +282,9 @@ func (e *RetryError) Unwrap() error {
 }
 
 func (e *RetryError) Is(target error) bool {
+	if target == ErrRetryBudgetExhausted {
+		return e.BudgetExhausted
+	}
 	return target == ErrRetryExhausted
 }
 
@@ -152,6 +309,10 @@ func IsRetryable(err error) bool {
 	// Check for specific API errors that might be retryable
 	var apiErr *APIError
 	if errors.As(err, &apiErr) {
+		// BitBrowser is busy with the profile; it will be free shortly
+		if apiErr.Busy {
+			return true
+		}
 		// Server errors (5xx) are retryable
 		if apiErr.StatusCode >= http.StatusInternalServerError {
 			return true
@@ -228,3 +389,96 @@ func NewRetryError(attempts int, lastErr error) *RetryError {
 		LastErr:  lastErr,
 	}
 }
+
+// messageErrors maps fragments of BitBrowser messages, in Chinese and
+// English, to the sentinel error they indicate. The first matching entry
+// wins. BitBrowser rewords its messages between releases; add new variants
+// here rather than matching strings elsewhere. Fragments must not match
+// unrelated messages, such as "unlocked" or limits other than profiles.
+var messageErrors = []struct {
+	err       error
+	fragments []string
+}{
+	// The profile is opening, starting or closing
+	{ErrBusy, []string{"正在打开", "正在启动", "正在关闭", "busy", "is opening", "is closing"}},
+	// The profile is used by another member or device
+	{ErrProfileLocked, []string{"已被锁定", "被锁定", "其他人打开", "其他设备打开", "is locked", "been locked", "opened by another"}},
+	// The browser kernel for the profile is still being downloaded
+	{ErrKernelDownloading, []string{"内核下载", "下载内核", "内核正在下载", "kernel is downloading", "downloading kernel", "downloading core"}},
+	// The account cannot pay for the operation
+	{ErrInsufficientBalance, []string{"余额不足", "insufficient balance"}},
+	// The account cannot hold more profiles
+	{ErrQuotaExceeded, []string{"窗口数量已达上限", "窗口数已达上限", "超出窗口上限", "窗口数量不足", "超出套餐", "quota"}},
+}
+
+// notFoundMessages are fragments of BitBrowser messages saying that a
+// profile does not exist. They are not in messageErrors: a fleet host that
+// answers so does not make ErrProfileNotFound true for the fleet, which
+// needs every host to answer.
+var notFoundMessages = []string{"浏览器不存在", "窗口不存在", "profile not found", "browser not found", "does not exist"}
+
+// isNotFoundMessage reports whether an API message says that a profile
+// does not exist.
+func isNotFoundMessage(msg string) bool {
+	lower := strings.ToLower(msg)
+	for _, fragment := range notFoundMessages {
+		if strings.Contains(lower, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyMessage returns the sentinel error an API message indicates,
+// or nil if the message is not recognized.
+func classifyMessage(msg string) error {
+	lower := strings.ToLower(msg)
+	for _, m := range messageErrors {
+		for _, fragment := range m.fragments {
+			if strings.Contains(lower, fragment) {
+				return m.err
+			}
+		}
+	}
+	return nil
+}
+
+// isBusyMessage reports whether an API message means the profile is busy.
+func isBusyMessage(msg string) bool {
+	return classifyMessage(msg) == ErrBusy
+}
+
+// isQuotaMessage reports whether an API message means the quota is exhausted.
+func isQuotaMessage(msg string) bool {
+	return classifyMessage(msg) == ErrQuotaExceeded
+}
+
+// parseRetryAfter parses a Retry-After header value given either as
+// delay-seconds or as an HTTP date. Returns 0 if absent or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// retryAfter returns the server-suggested delay carried by err, if any.
+func retryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
@@ -1,9 +1,12 @@
 package bitbrowser
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNetworkError(t *testing.T) {
@@ -470,3 +473,142 @@ func TestErrorConstructors(t *testing.T) {
 		}
 	})
 }
+
+func TestQuotaErrors(t *testing.T) {
+	t.Run("recognizes quota messages", func(t *testing.T) {
+		for _, msg := range []string{"窗口数量已达上限", "超出套餐窗口数量", "Profile quota reached"} {
+			if !isQuotaMessage(msg) {
+				t.Errorf("isQuotaMessage(%q) = false, want true", msg)
+			}
+		}
+		if isQuotaMessage("profile not found") {
+			t.Error("isQuotaMessage should be false for unrelated messages")
+		}
+	})
+
+	t.Run("quota errors match ErrQuotaExceeded", func(t *testing.T) {
+		apiErr := &APIError{Endpoint: "/browser/update", Message: "上限", Quota: true}
+		if !errors.Is(apiErr, ErrQuotaExceeded) || !errors.Is(apiErr, ErrAPI) {
+			t.Error("expected ErrQuotaExceeded and ErrAPI")
+		}
+		if errors.Is(&APIError{Message: "other"}, ErrQuotaExceeded) {
+			t.Error("plain APIError should not match ErrQuotaExceeded")
+		}
+
+		quotaErr := &QuotaError{Requested: 5, Remaining: 2, Limit: 10}
+		if !errors.Is(quotaErr, ErrQuotaExceeded) {
+			t.Error("expected ErrQuotaExceeded")
+		}
+		if !strings.Contains(quotaErr.Error(), "5 profiles requested, 2 of 10 remaining") {
+			t.Errorf("unexpected message: %s", quotaErr.Error())
+		}
+		if IsRetryable(quotaErr) || IsRetryable(apiErr) {
+			t.Error("quota errors should not be retryable")
+		}
+	})
+}
+
+func TestBusyErrors(t *testing.T) {
+	t.Run("recognizes busy messages", func(t *testing.T) {
+		for _, msg := range []string{"浏览器正在打开中", "窗口正在关闭，请稍后", "Browser is busy"} {
+			if !isBusyMessage(msg) {
+				t.Errorf("isBusyMessage(%q) = false, want true", msg)
+			}
+		}
+		if isBusyMessage("profile not found") {
+			t.Error("isBusyMessage should be false for unrelated messages")
+		}
+	})
+
+	t.Run("busy APIError matches ErrBusy and is retryable", func(t *testing.T) {
+		err := openFailure("正在打开")
+		if !errors.Is(err, ErrBusy) {
+			t.Error("expected ErrBusy")
+		}
+		if !errors.Is(err, ErrAPI) {
+			t.Error("expected ErrAPI")
+		}
+		if !IsRetryable(err) {
+			t.Error("busy errors should be retryable")
+		}
+	})
+
+	t.Run("non-busy failure does not match ErrBusy", func(t *testing.T) {
+		err := openFailure("profile not found")
+		if errors.Is(err, ErrBusy) {
+			t.Error("should not match ErrBusy")
+		}
+		if err.Error() != "profile not found" {
+			t.Errorf("Error() = %q", err.Error())
+		}
+	})
+}
+
+func TestMessageErrors(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want error
+	}{
+		{"浏览器正在打开中", ErrBusy},
+		{"Browser is opening, please wait", ErrBusy},
+		{"该窗口已被锁定", ErrProfileLocked},
+		{"Profile is opened by another member", ErrProfileLocked},
+		{"内核正在下载中，请稍后", ErrKernelDownloading},
+		{"Kernel is downloading", ErrKernelDownloading},
+		{"账户余额不足", ErrInsufficientBalance},
+		{"Insufficient balance", ErrInsufficientBalance},
+		{"窗口数量不足", ErrQuotaExceeded},
+		{"超出窗口上限", ErrQuotaExceeded},
+		{"something else", nil},
+		{"Profile unlocked", nil},
+		{"并发数已达上限", nil},
+		{"请求频率已达上限，请稍后再试", nil},
+	}
+	for _, tt := range tests {
+		if got := classifyMessage(tt.msg); got != tt.want {
+			t.Errorf("classifyMessage(%q) = %v, want %v", tt.msg, got, tt.want)
+		}
+	}
+
+	t.Run("unsuccessful responses match sentinels", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(errorResponse("账户余额不足"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		err := client.UpdateGroup(context.Background(), "g", []string{"profile-1"})
+		if !errors.Is(err, ErrInsufficientBalance) || !errors.Is(err, ErrAPI) {
+			t.Errorf("err = %v, want ErrInsufficientBalance", err)
+		}
+		if errors.Is(err, ErrBusy) {
+			t.Error("should not match ErrBusy")
+		}
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"empty", "", 0},
+		{"seconds", "5", 5 * time.Second},
+		{"zero seconds", "0", 0},
+		{"negative seconds", "-3", 0},
+		{"http date", now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"garbage", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ExcelRows is a spreadsheet read with ReadExcelRows: a header row and the
+// data rows below it.
+type ExcelRows struct {
+	Header []string
+	Data   [][]string
+}
+
+// Len returns the number of data rows.
+func (r *ExcelRows) Len() int {
+	return len(r.Data)
+}
+
+// Column returns the index of the column with the given header, matched
+// ignoring case and surrounding spaces, or -1 if there is none.
+func (r *ExcelRows) Column(header string) int {
+	header = strings.TrimSpace(header)
+	for i, h := range r.Header {
+		if strings.EqualFold(strings.TrimSpace(h), header) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the cell of data row i in the column with the given header,
+// or "" if the column does not exist or the row is shorter.
+func (r *ExcelRows) Get(i int, header string) string {
+	col := r.Column(header)
+	if col < 0 || i < 0 || i >= len(r.Data) || col >= len(r.Data[i]) {
+		return ""
+	}
+	return r.Data[i][col]
+}
+
+// ReadExcelRows reads an Excel file on the BitBrowser host with ReadExcel
+// and returns its cells as strings, taking the first row as the header.
+func (c *Client) ReadExcelRows(ctx context.Context, path string) (*ExcelRows, error) {
+	data, err := c.ReadExcel(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := spreadsheetRows(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return &ExcelRows{}, nil
+	}
+	return &ExcelRows{Header: rows[0], Data: rows[1:]}, nil
+}
+
+// ReadExcelTyped reads an Excel file on the BitBrowser host and maps each
+// data row to a T, which must be a struct. Fields are matched to columns by
+// their `excel:"Header"` tag, or by field name without one, ignoring case;
+// `excel:"-"` skips a field. Fields may be strings, bools, integers or
+// floats. Columns without a field and fields without a column are ignored,
+// and empty cells leave fields at their zero value.
+//
+// Example:
+//
+//	type Account struct {
+//	    Name  string  `excel:"Profile Name"`
+//	    Proxy string  `excel:"proxy"`
+//	    Limit int     `excel:"Daily Limit"`
+//	}
+//	accounts, err := bitbrowser.ReadExcelTyped[Account](ctx, client, `C:\data\accounts.xlsx`)
+func ReadExcelTyped[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		return nil, &ValidationError{Field: "T", Message: "ReadExcelTyped needs a struct type", Value: typ.String()}
+	}
+	rows, err := c.ReadExcelRows(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Column index of each mapped field
+	type fieldColumn struct {
+		field  int
+		column int
+		header string
+	}
+	var fields []fieldColumn
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		header := f.Name
+		if tag, ok := f.Tag.Lookup("excel"); ok {
+			if tag == "-" {
+				continue
+			}
+			header = tag
+		}
+		if col := rows.Column(header); col >= 0 {
+			fields = append(fields, fieldColumn{field: i, column: col, header: header})
+		}
+	}
+
+	result := make([]T, len(rows.Data))
+	for n, row := range rows.Data {
+		v := reflect.ValueOf(&result[n]).Elem()
+		for _, fc := range fields {
+			if fc.column >= len(row) {
+				continue
+			}
+			if err := setExcelField(v.Field(fc.field), strings.TrimSpace(row[fc.column])); err != nil {
+				return nil, fmt.Errorf("bitbrowser: row %d, column %q: %w", n+2, fc.header, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// setExcelField parses a cell into a struct field.
+func setExcelField(f reflect.Value, cell string) error {
+	if cell == "" {
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(cell, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(cell, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(cell, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetFloat(x)
+	default:
+		return fmt.Errorf("unsupported field type %s", f.Type())
+	}
+	return nil
+}
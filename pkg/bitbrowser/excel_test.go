@@ -0,0 +1,106 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// excelServer answers ReadExcel with data.
+func excelServer(t *testing.T, data any) *Client {
+	t.Helper()
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/utils/readexcel" {
+			t.Errorf("path = %q, want /utils/readexcel", r.URL.Path)
+		}
+		w.Write(successResponse(data))
+	})
+	t.Cleanup(server.Close)
+	return mustNew(t, server.URL)
+}
+
+func TestReadExcelRows(t *testing.T) {
+	client := excelServer(t, [][]any{
+		{"Name", " Daily Limit ", "Active"},
+		{"alpha", 25, true},
+		{"beta", 1.5},
+	})
+	rows, err := client.ReadExcelRows(context.Background(), "/data/accounts.xlsx")
+	if err != nil {
+		t.Fatalf("ReadExcelRows failed: %v", err)
+	}
+	if rows.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", rows.Len())
+	}
+	if rows.Column("daily limit") != 1 || rows.Column("missing") != -1 {
+		t.Errorf("Column() = %d, %d", rows.Column("daily limit"), rows.Column("missing"))
+	}
+	for _, tt := range []struct {
+		row    int
+		header string
+		want   string
+	}{
+		{0, "name", "alpha"},
+		{0, "Daily Limit", "25"},
+		{0, "active", "true"},
+		{1, "Daily Limit", "1.5"},
+		{1, "Active", ""}, // Short row
+		{2, "Name", ""},   // Out of range
+	} {
+		if got := rows.Get(tt.row, tt.header); got != tt.want {
+			t.Errorf("Get(%d, %q) = %q, want %q", tt.row, tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestReadExcelTyped(t *testing.T) {
+	type account struct {
+		Name     string
+		Limit    int     `excel:"Daily Limit"`
+		Rate     float64 `excel:"rate"`
+		Active   bool
+		Internal string `excel:"-"`
+		Missing  string `excel:"Not There"`
+		note     string
+	}
+
+	t.Run("maps rows by tag and name", func(t *testing.T) {
+		client := excelServer(t, []map[string]any{
+			{"name": "alpha", "Daily Limit": 25, "Rate": 0.5, "Active": "TRUE", "Internal": "x"},
+			{"name": "beta", "Daily Limit": nil},
+		})
+		accounts, err := ReadExcelTyped[account](context.Background(), client, "/data/accounts.xlsx")
+		if err != nil {
+			t.Fatalf("ReadExcelTyped failed: %v", err)
+		}
+		want := []account{
+			{Name: "alpha", Limit: 25, Rate: 0.5, Active: true},
+			{Name: "beta"},
+		}
+		if len(accounts) != len(want) {
+			t.Fatalf("accounts = %+v", accounts)
+		}
+		for i := range want {
+			if accounts[i] != want[i] {
+				t.Errorf("accounts[%d] = %+v, want %+v", i, accounts[i], want[i])
+			}
+		}
+	})
+
+	t.Run("invalid cell", func(t *testing.T) {
+		client := excelServer(t, [][]any{{"Name", "Daily Limit"}, {"alpha", "1"}, {"beta", "lots"}})
+		_, err := ReadExcelTyped[account](context.Background(), client, "/data/accounts.xlsx")
+		if err == nil || !strings.Contains(err.Error(), `row 3, column "Daily Limit"`) {
+			t.Errorf("err = %v, want an error naming row 3", err)
+		}
+	})
+
+	t.Run("non-struct type", func(t *testing.T) {
+		_, err := ReadExcelTyped[string](context.Background(), excelServer(t, [][]any{}), "/data/x.xlsx")
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("err = %v, want ErrValidation", err)
+		}
+	})
+}
@@ -0,0 +1,295 @@
+package bitbrowser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// MarshalJSON encodes the config and merges Extra into the result.
+func (c ProfileConfig) MarshalJSON() ([]byte, error) {
+	type plain ProfileConfig // Drops this method
+	return marshalWithExtra(plain(c), c.Extra)
+}
+
+// MarshalJSON encodes the fingerprint and merges Extra into the result.
+func (f Fingerprint) MarshalJSON() ([]byte, error) {
+	type plain Fingerprint
+	return marshalWithExtra(plain(f), f.Extra)
+}
+
+// MarshalJSON encodes the config and merges Extra into the result.
+func (c OpenConfig) MarshalJSON() ([]byte, error) {
+	type plain OpenConfig
+	return marshalWithExtra(plain(c), c.Extra)
+}
+
+// UnmarshalJSON decodes the profile and collects fields the SDK does not
+// know into Extras.
+func (d *ProfileDetail) UnmarshalJSON(data []byte) error {
+	type plain ProfileDetail
+	if err := json.Unmarshal(data, (*plain)(d)); err != nil {
+		return err
+	}
+	extras, err := unknownFields(data, reflect.TypeFor[ProfileDetail]())
+	if err != nil {
+		return err
+	}
+	d.Extras = extras
+	return nil
+}
+
+// marshalWithExtra encodes v, which must encode to a JSON object, and sets
+// the entries of extra on it. Entries of extra replace fields of the same name.
+// Members are sorted by name, as if the object had been encoded as a map; the
+// encoded members of v are copied rather than decoded and encoded again.
+func marshalWithExtra(v any, extra map[string]any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	type member struct {
+		name     string
+		key, raw []byte // Encoded
+	}
+	members := make([]member, 0, len(extra)+16)
+	err = objectMembers(data, func(key, value []byte) error {
+		name, err := unquoteKey(key)
+		if err != nil {
+			return err
+		}
+		if _, ok := extra[name]; !ok {
+			members = append(members, member{name, key, value})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member{name, key, raw})
+	}
+	slices.SortFunc(members, func(a, b member) int { return strings.Compare(a.name, b.name) })
+
+	size := 2
+	for _, m := range members {
+		size += len(m.key) + len(m.raw) + 2
+	}
+	out := make([]byte, 0, size)
+	out = append(out, '{')
+	for i, m := range members {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, m.key...)
+		out = append(out, ':')
+		out = append(out, m.raw...)
+	}
+	return append(out, '}'), nil
+}
+
+// knownFieldNames caches the lower-cased JSON names of struct types, for
+// unknownFields.
+var knownFieldNames sync.Map // reflect.Type -> map[string]bool
+
+// unknownFields returns the members of the JSON object data that do not
+// match a field of struct type t, or nil if there are none. Like
+// encoding/json, names are matched case-insensitively. Known members are
+// skipped without being decoded.
+func unknownFields(data []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	known := fieldNames(t)
+
+	var extras map[string]json.RawMessage
+	err := objectMembers(data, func(key, value []byte) error {
+		var buf [64]byte
+		if lower, ok := appendLowerASCII(buf[:0], key); ok && known[string(lower)] {
+			return nil
+		}
+		name, err := unquoteKey(key)
+		if err != nil {
+			return err
+		}
+		if known[strings.ToLower(name)] {
+			return nil
+		}
+		if extras == nil {
+			extras = make(map[string]json.RawMessage)
+		}
+		extras[name] = bytes.Clone(value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return extras, nil
+}
+
+// fieldNames returns the lower-cased JSON names of the fields of struct
+// type t.
+func fieldNames(t reflect.Type) map[string]bool {
+	if known, ok := knownFieldNames.Load(t); ok {
+		return known.(map[string]bool)
+	}
+	known := make(map[string]bool, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		known[strings.ToLower(name)] = true
+	}
+	knownFieldNames.Store(t, known)
+	return known
+}
+
+// appendLowerASCII appends the lower-cased contents of the encoded JSON
+// string key to dst. It reports false if key has escapes or non-ASCII
+// characters, which need unquoteKey.
+func appendLowerASCII(dst, key []byte) ([]byte, bool) {
+	for _, c := range key[1 : len(key)-1] {
+		if c == '\\' || c >= utf8.RuneSelf {
+			return nil, false
+		}
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		dst = append(dst, c)
+	}
+	return dst, true
+}
+
+// unquoteKey decodes the encoded JSON string key.
+func unquoteKey(key []byte) (string, error) {
+	if bytes.IndexByte(key, '\\') < 0 {
+		return string(key[1 : len(key)-1]), nil
+	}
+	var name string
+	err := json.Unmarshal(key, &name)
+	return name, err
+}
+
+// errBadObject reports JSON that objectMembers cannot walk.
+var errBadObject = errors.New("bitbrowser: malformed JSON object")
+
+// objectMembers calls fn with the encoded key and value of each member of
+// the JSON object data, in order, without decoding them. data must already
+// be valid JSON, e.g. because it was just encoded or decoded. Like an empty
+// object, null has no members.
+func objectMembers(data []byte, fn func(key, value []byte) error) error {
+	i := skipSpace(data, 0)
+	if bytes.HasPrefix(data[i:], []byte("null")) {
+		return nil
+	}
+	if i == len(data) || data[i] != '{' {
+		return errBadObject
+	}
+	i = skipSpace(data, i+1)
+	if i < len(data) && data[i] == '}' {
+		return nil
+	}
+	for i < len(data) {
+		if data[i] != '"' {
+			return errBadObject
+		}
+		end := skipString(data, i)
+		key := data[i:end]
+		i = skipSpace(data, end)
+		if i == len(data) || data[i] != ':' {
+			return errBadObject
+		}
+		start := skipSpace(data, i+1)
+		end = skipValue(data, start)
+		if end <= start {
+			return errBadObject
+		}
+		if err := fn(key, data[start:end]); err != nil {
+			return err
+		}
+		i = skipSpace(data, end)
+		if i == len(data) {
+			break
+		}
+		switch data[i] {
+		case ',':
+			i = skipSpace(data, i+1)
+		case '}':
+			return nil
+		default:
+			return errBadObject
+		}
+	}
+	return errBadObject
+}
+
+// skipSpace returns the index of the first non-space byte at or after i.
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipString returns the index after the JSON string starting at i.
+func skipString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// skipValue returns the index after the JSON value starting at i.
+func skipValue(data []byte, i int) int {
+	if i == len(data) {
+		return i
+	}
+	switch data[i] {
+	case '"':
+		return skipString(data, i)
+	case '{', '[':
+		depth := 0
+		for i < len(data) {
+			switch data[i] {
+			case '"':
+				i = skipString(data, i)
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+			i++
+		}
+		return i
+	default:
+		for i < len(data) && !strings.ContainsRune(",}] \t\r\n", rune(data[i])) {
+			i++
+		}
+		return i
+	}
+}
@@ -0,0 +1,155 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestExtraFields(t *testing.T) {
+	t.Run("merged into requests", func(t *testing.T) {
+		var body map[string]any
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Write(successResponse(map[string]string{"id": "profile-1"}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		_, err := client.CreateProfile(context.Background(), ProfileConfig{
+			Name:  "test",
+			Extra: map[string]any{"newSetting": true, "name": "override"},
+			BrowserFingerPrint: &Fingerprint{
+				CoreVersion: "130",
+				Extra:       map[string]any{"webGPU": "1"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if body["newSetting"] != true {
+			t.Errorf("newSetting = %v, want true", body["newSetting"])
+		}
+		if body["name"] != "override" {
+			t.Errorf("name = %v, want override", body["name"])
+		}
+		fp, _ := body["browserFingerPrint"].(map[string]any)
+		if fp["webGPU"] != "1" || fp["coreVersion"] != "130" {
+			t.Errorf("browserFingerPrint = %v", fp)
+		}
+	})
+
+	t.Run("open config", func(t *testing.T) {
+		data, err := json.Marshal(OpenConfig{ID: "profile-1", Extra: map[string]any{"extractIp": false}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != `{"extractIp":false,"id":"profile-1"}` {
+			t.Errorf("data = %s", data)
+		}
+	})
+
+	t.Run("partial update", func(t *testing.T) {
+		data, err := json.Marshal(PartialUpdateRequest{
+			IDs:           []string{"profile-1"},
+			ProfileConfig: ProfileConfig{Name: "n", Extra: map[string]any{"newSetting": 1}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != `{"browserFingerPrint":null,"ids":["profile-1"],"name":"n","newSetting":1}` {
+			t.Errorf("data = %s", data)
+		}
+	})
+
+	t.Run("unknown response fields captured", func(t *testing.T) {
+		var detail ProfileDetail
+		err := json.Unmarshal([]byte(`{"id":"profile-1","Name":"n","newSetting":{"a":1}}`), &detail)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if detail.ID != "profile-1" || detail.Name != "n" {
+			t.Errorf("detail = %+v", detail)
+		}
+		if len(detail.Extras) != 1 || string(detail.Extras["newSetting"]) != `{"a":1}` {
+			t.Errorf("Extras = %v", detail.Extras)
+		}
+	})
+}
+
+func TestObjectMembers(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"empty", ` { } `, nil},
+		{"null", `null`, nil},
+		{"scalars", `{"a":1,"b":"x","c":true,"d":null}`, []string{`"a"=1`, `"b"="x"`, `"c"=true`, `"d"=null`}},
+		{"nested", `{"a":{"b":[1,{"c":"}"}]},"d":[]}`, []string{`"a"={"b":[1,{"c":"}"}]}`, `"d"=[]`}},
+		{"escapes", `{"a\"b":"c\\","d":"\"}"}`, []string{`"a\"b"="c\\"`, `"d"="\"}"`}},
+		{"whitespace", "{\n  \"a\" : 1 ,\n  \"b\": [ 2 ]\n}", []string{`"a"=1`, `"b"=[ 2 ]`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := objectMembers([]byte(tt.data), func(key, value []byte) error {
+				got = append(got, string(key)+"="+string(value))
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("members = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, data := range []string{``, `[]`, `{"a"}`, `{"a":1,}`, `{"a":1`, `{"a":1 "b":2}`} {
+		if err := objectMembers([]byte(data), func(key, value []byte) error { return nil }); err == nil {
+			t.Errorf("objectMembers(%q) succeeded, want error", data)
+		}
+	}
+}
+
+func TestMarshalWithExtraMatchesMap(t *testing.T) {
+	config := ProfileConfig{
+		Name:   "<n>",
+		Remark: "r ",
+		Extra:  map[string]any{"remark": "override", "zeta": []int{1}, "alpha": map[string]any{"k": "&"}, "é": 1},
+	}
+	got, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type plain ProfileConfig
+	data, _ := json.Marshal(plain(config))
+	var obj map[string]any
+	json.Unmarshal(data, &obj)
+	for key, value := range config.Extra {
+		obj[key] = value
+	}
+	want, _ := json.Marshal(obj)
+	if string(got) != string(want) {
+		t.Errorf("data = %s, want %s", got, want)
+	}
+}
+
+func TestUnknownFieldsEscapedKeys(t *testing.T) {
+	var detail ProfileDetail
+	err := json.Unmarshal([]byte(`{"id":"profile-1","NAME":"n","new\"Key":1}`), &detail)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if detail.ID != "profile-1" || detail.Name != "n" {
+		t.Errorf("detail = %+v", detail)
+	}
+	if len(detail.Extras) != 1 || string(detail.Extras[`new"Key`]) != "1" {
+		t.Errorf("Extras = %v", detail.Extras)
+	}
+}
@@ -0,0 +1,81 @@
+package bitbrowser
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// profileFields maps the JSON and Go names of ProfileConfig fields to their
+// field index.
+var profileFields = sync.OnceValue(func() map[string]int {
+	fields := make(map[string]int)
+	t := reflect.TypeFor[ProfileConfig]()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name != "" {
+			fields[name] = i
+		}
+		fields[f.Name] = i
+	}
+	return fields
+})
+
+// maskedFields returns the fields of config named in mask, keyed by JSON
+// name. Unknown names are reported as a *ValidationError.
+func maskedFields(config ProfileConfig, mask []string) (map[string]any, error) {
+	t := reflect.TypeFor[ProfileConfig]()
+	v := reflect.ValueOf(config)
+	values := make(map[string]any, len(mask))
+	for _, name := range mask {
+		i, ok := profileFields()[name]
+		if !ok {
+			return nil, &ValidationError{Field: "UpdateFields", Message: "unknown profile field", Value: name}
+		}
+		jsonName, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if jsonName == "" {
+			jsonName = t.Field(i).Name
+		}
+		values[jsonName] = v.Field(i).Interface()
+	}
+	return values, nil
+}
+
+// validate checks that the request names at least one profile and that
+// every UpdateFields entry is a known field.
+func (r PartialUpdateRequest) validate() error {
+	if len(r.IDs) == 0 {
+		return NewValidationError("IDs", "at least one profile ID is required")
+	}
+	if err := validateProfileIDs(r.IDs); err != nil {
+		return err
+	}
+	_, err := maskedFields(r.ProfileConfig, r.UpdateFields)
+	return err
+}
+
+// MarshalJSON encodes the request. With UpdateFields set, exactly the listed
+// fields of ProfileConfig are encoded, zero values included, plus Extra.
+func (r PartialUpdateRequest) MarshalJSON() ([]byte, error) {
+	if len(r.UpdateFields) == 0 {
+		return marshalWithExtra(r.ProfileConfig, map[string]any{"ids": r.IDs})
+	}
+
+	body, err := maskedFields(r.ProfileConfig, r.UpdateFields)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range r.Extra {
+		body[key] = value
+	}
+	body["ids"] = r.IDs
+	return json.Marshal(body)
+}
@@ -0,0 +1,85 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPartialUpdateFieldMask(t *testing.T) {
+	t.Run("sends zero values for masked fields", func(t *testing.T) {
+		var body map[string]any
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(data, &body); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			w.Write(successResponse(nil))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		err := client.UpdateProfilePartial(context.Background(), PartialUpdateRequest{
+			IDs:           []string{"profile-1"},
+			ProfileConfig: ProfileConfig{Name: "ignored", Remark: ""},
+			UpdateFields:  []string{"remark", "MuteAudio"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := map[string]any{
+			"ids":       []any{"profile-1"},
+			"remark":    "",
+			"muteAudio": false,
+		}
+		if !reflect.DeepEqual(body, want) {
+			t.Errorf("body = %v, want %v", body, want)
+		}
+	})
+
+	t.Run("without mask omits zero values", func(t *testing.T) {
+		data, err := json.Marshal(PartialUpdateRequest{
+			IDs:           []string{"profile-1"},
+			ProfileConfig: ProfileConfig{Name: "Updated"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var body map[string]any
+		json.Unmarshal(data, &body)
+		if body["name"] != "Updated" {
+			t.Errorf("name = %v, want Updated", body["name"])
+		}
+		if _, ok := body["muteAudio"]; ok {
+			t.Errorf("muteAudio present in %s", data)
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		client := mustNew(t, "http://127.0.0.1:1")
+		err := client.UpdateProfilePartial(context.Background(), PartialUpdateRequest{
+			IDs:          []string{"profile-1"},
+			UpdateFields: []string{"nope"},
+		})
+		var verr *ValidationError
+		if !errors.As(err, &verr) || verr.Field != "UpdateFields" {
+			t.Errorf("err = %v, want UpdateFields ValidationError", err)
+		}
+	})
+
+	t.Run("missing IDs", func(t *testing.T) {
+		client := mustNew(t, "http://127.0.0.1:1")
+		err := client.UpdateProfilePartial(context.Background(), PartialUpdateRequest{
+			UpdateFields: []string{"remark"},
+		})
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("err = %v, want ValidationError", err)
+		}
+	})
+}
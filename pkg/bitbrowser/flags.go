@@ -0,0 +1,131 @@
+package bitbrowser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChromeFlag is a Chrome command-line flag from the catalog Open validates
+// arguments against. Build arguments with String and With:
+//
+//	opts := &bitbrowser.OpenOptions{ExtraArgs: []string{
+//	    bitbrowser.FlagLang.With("de-DE"),
+//	    bitbrowser.FlagDisableBlinkFeatures.With("AutomationControlled"),
+//	    bitbrowser.FlagMuteAudio.String(),
+//	}}
+//
+// Flags outside the catalog may still be passed; they are not validated.
+type ChromeFlag string
+
+// Cataloged Chrome flags.
+const (
+	FlagHeadless               ChromeFlag = "headless"                 // Run without a window; "new" selects the new headless mode
+	FlagStartMaximized         ChromeFlag = "start-maximized"          // Open the window maximized
+	FlagStartFullscreen        ChromeFlag = "start-fullscreen"         // Open the window in full screen
+	FlagKiosk                  ChromeFlag = "kiosk"                    // Full screen without browser UI
+	FlagWindowSize             ChromeFlag = "window-size"              // Window size: width,height
+	FlagWindowPosition         ChromeFlag = "window-position"          // Window position: x,y
+	FlagLang                   ChromeFlag = "lang"                     // UI language, e.g. en-US
+	FlagAcceptLang             ChromeFlag = "accept-lang"              // Accept-Language list, e.g. en-US,en
+	FlagUserAgent              ChromeFlag = "user-agent"               // User-Agent header
+	FlagProxyServer            ChromeFlag = "proxy-server"             // Proxy replacing the profile's, e.g. socks5://host:1080
+	FlagProxyBypassList        ChromeFlag = "proxy-bypass-list"        // Hosts that skip the proxy, e.g. <local>,*.example.com
+	FlagDisableBlinkFeatures   ChromeFlag = "disable-blink-features"   // Blink features to disable, e.g. AutomationControlled
+	FlagDisableFeatures        ChromeFlag = "disable-features"         // Chrome features to disable
+	FlagEnableFeatures         ChromeFlag = "enable-features"          // Chrome features to enable
+	FlagIncognito              ChromeFlag = "incognito"                // Open in incognito mode
+	FlagDisableGPU             ChromeFlag = "disable-gpu"              // Disable GPU acceleration
+	FlagNoSandbox              ChromeFlag = "no-sandbox"               // Disable the sandbox, e.g. when running as root
+	FlagMuteAudio              ChromeFlag = "mute-audio"               // Mute all audio
+	FlagDisableExtensions      ChromeFlag = "disable-extensions"       // Disable all extensions
+	FlagLoadExtension          ChromeFlag = "load-extension"           // Unpacked extension directories
+	FlagRemoteDebuggingPort    ChromeFlag = "remote-debugging-port"    // DevTools port
+	FlagRemoteDebuggingAddress ChromeFlag = "remote-debugging-address" // DevTools listen address
+	FlagEnableLogging          ChromeFlag = "enable-logging"           // Write Chrome's log; "stderr" logs to standard error
+	FlagLogFile                ChromeFlag = "log-file"                 // Log file replacing chrome_debug.log in the user data directory
+	FlagCrashDumpsDir          ChromeFlag = "crash-dumps-dir"          // Directory for crash dumps
+)
+
+// chromeFlagInfo describes a cataloged flag.
+type chromeFlagInfo struct {
+	value     bool         // The flag needs a value
+	conflicts []ChromeFlag // Flags it cannot be combined with
+}
+
+// chromeFlags is the catalog. Conflicts are listed on one side only.
+var chromeFlags = map[ChromeFlag]chromeFlagInfo{
+	FlagHeadless:               {conflicts: []ChromeFlag{FlagStartMaximized, FlagStartFullscreen, FlagKiosk}},
+	FlagStartMaximized:         {conflicts: []ChromeFlag{FlagStartFullscreen, FlagKiosk, FlagWindowSize}},
+	FlagStartFullscreen:        {conflicts: []ChromeFlag{FlagKiosk}},
+	FlagKiosk:                  {},
+	FlagWindowSize:             {value: true},
+	FlagWindowPosition:         {value: true},
+	FlagLang:                   {value: true},
+	FlagAcceptLang:             {value: true},
+	FlagUserAgent:              {value: true},
+	FlagProxyServer:            {value: true},
+	FlagProxyBypassList:        {value: true},
+	FlagDisableBlinkFeatures:   {value: true},
+	FlagDisableFeatures:        {value: true},
+	FlagEnableFeatures:         {value: true},
+	FlagIncognito:              {},
+	FlagDisableGPU:             {},
+	FlagNoSandbox:              {},
+	FlagMuteAudio:              {},
+	FlagDisableExtensions:      {conflicts: []ChromeFlag{FlagLoadExtension}},
+	FlagLoadExtension:          {value: true},
+	FlagRemoteDebuggingPort:    {value: true},
+	FlagRemoteDebuggingAddress: {value: true},
+	FlagEnableLogging:          {},
+	FlagLogFile:                {value: true},
+	FlagCrashDumpsDir:          {value: true},
+}
+
+// String returns the flag as an argument without a value, e.g. "--mute-audio".
+func (f ChromeFlag) String() string {
+	return "--" + string(f)
+}
+
+// With returns the flag as an argument with values joined by commas, e.g.
+// FlagWindowSize.With("1280", "800") is "--window-size=1280,800".
+func (f ChromeFlag) With(values ...string) string {
+	return f.String() + "=" + strings.Join(values, ",")
+}
+
+// ValidateChromeArgs checks Chrome arguments against the flag catalog. It
+// reports cataloged flags missing their value, flags given twice with
+// different values (Chrome silently keeps the last) and combinations that
+// conflict, such as --headless with --start-maximized. Open runs it on the
+// final arguments, so ExtraArgs are also checked against the flags that
+// OpenOptions add.
+func ValidateChromeArgs(args []string) error {
+	seen := make(map[ChromeFlag]string)
+	var order []ChromeFlag
+	for _, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		flag := ChromeFlag(name)
+		info, ok := chromeFlags[flag]
+		if !ok || !strings.HasPrefix(arg, "-") {
+			continue // Not cataloged
+		}
+		if info.value && (!hasValue || value == "") {
+			return &ValidationError{Field: "ExtraArgs", Message: fmt.Sprintf("%s needs a value", flag), Value: arg}
+		}
+		if previous, dup := seen[flag]; dup {
+			if previous != value {
+				return &ValidationError{Field: "ExtraArgs", Message: fmt.Sprintf("%s is given twice with different values", flag), Value: arg}
+			}
+			continue
+		}
+		seen[flag] = value
+		order = append(order, flag)
+	}
+	for _, flag := range order {
+		for _, other := range chromeFlags[flag].conflicts {
+			if _, ok := seen[other]; ok {
+				return &ValidationError{Field: "ExtraArgs", Message: fmt.Sprintf("%s conflicts with %s", flag, other), Value: args}
+			}
+		}
+	}
+	return nil
+}
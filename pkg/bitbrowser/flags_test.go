@@ -0,0 +1,71 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestChromeFlag(t *testing.T) {
+	if got := FlagMuteAudio.String(); got != "--mute-audio" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := FlagWindowSize.With("1280", "800"); got != "--window-size=1280,800" {
+		t.Errorf("With() = %q", got)
+	}
+}
+
+func TestValidateChromeArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"valid", []string{"--headless=new", FlagLang.With("en-US"), "--some-uncataloged-flag", "--lang=en-US"}, ""},
+		{"missing value", []string{"--lang"}, "--lang needs a value"},
+		{"empty value", []string{"--proxy-bypass-list="}, "--proxy-bypass-list needs a value"},
+		{"different values", []string{"--remote-debugging-port=50000", "--remote-debugging-port=9222"}, "given twice"},
+		{"conflict", []string{"--start-maximized", "--headless"}, "--headless conflicts with --start-maximized"},
+		{"conflict with value flag", []string{"--window-size=800,600", "--start-maximized"}, "--start-maximized conflicts with --window-size"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateChromeArgs(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpenValidatesArgs(t *testing.T) {
+	var opened int
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		opened++
+		w.Write(successResponse(OpenResult{Ws: "ws://127.0.0.1:9222/devtools/browser/abc"}))
+	})
+	defer server.Close()
+	client := mustNew(t, server.URL)
+
+	// --headless comes from the option, --start-maximized from ExtraArgs
+	opts := &OpenOptions{Headless: true, ExtraArgs: []string{FlagStartMaximized.String()}}
+	if _, err := client.Open(context.Background(), "profile-1", opts); !errors.Is(err, ErrValidation) {
+		t.Errorf("err = %v, want ErrValidation", err)
+	}
+	if opened != 0 {
+		t.Errorf("sent %d open requests despite invalid args", opened)
+	}
+
+	opts.SkipArgValidation = true
+	if _, err := client.Open(context.Background(), "profile-1", opts); err != nil {
+		t.Errorf("Open with SkipArgValidation failed: %v", err)
+	}
+}
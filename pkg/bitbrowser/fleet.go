@@ -0,0 +1,435 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// FleetClient spreads work across several BitBrowser instances, typically
+// running on different machines.
+//
+// Profiles live on exactly one host, so calls that take a profile ID are
+// routed to the host that owns it. The owner is discovered by asking every
+// host and recorded in a FleetStore; later calls go straight to the recorded
+// host. If a recorded host no longer has the profile, the call fails with a
+// *ProfileNotOnHostError naming the host that does. New profiles are placed
+// on the least loaded host (see Loads).
+//
+// A FleetClient is safe for concurrent use.
+//
+// Example:
+//
+//	fleet, err := bitbrowser.NewFleetClient(
+//	    bitbrowser.WithFleetHost("node-a", clientA),
+//	    bitbrowser.WithFleetHost("node-b", clientB),
+//	)
+//	host, id, err := fleet.CreateProfile(ctx, config)
+//	result, err := fleet.Open(ctx, id, nil)
+//	fmt.Println(result.Host, result.Ws)
+type FleetClient struct {
+	hosts  []*fleetHost
+	byName map[string]*fleetHost
+	store  FleetStore
+}
+
+// fleetHost is a named member of a fleet.
+type fleetHost struct {
+	name        string
+	client      *Client
+	maxBrowsers int          // 0 means unlimited
+	agent       MetricsAgent // nil if the host has no agent
+}
+
+// FleetStore records which host owns each profile.
+//
+// Implementations must be safe for concurrent use. Sharing a store between
+// processes (e.g. backed by Redis or a database) lets them route without
+// rediscovering owners. Store errors are not fatal: a failed Lookup falls
+// back to asking every host, and a failed Store or Forget only means the
+// owner is rediscovered later.
+type FleetStore interface {
+	// Lookup returns the host recorded for a profile.
+	Lookup(ctx context.Context, id string) (host string, ok bool, err error)
+	// Store records host as the owner of a profile.
+	Store(ctx context.Context, id, host string) error
+	// Forget removes the record for a profile.
+	Forget(ctx context.Context, id string) error
+}
+
+// MemoryFleetStore is an in-process FleetStore. It is the default store of
+// a FleetClient.
+type MemoryFleetStore struct {
+	mu     sync.RWMutex
+	owners map[string]string
+}
+
+// NewMemoryFleetStore creates an empty MemoryFleetStore.
+func NewMemoryFleetStore() *MemoryFleetStore {
+	return &MemoryFleetStore{owners: make(map[string]string)}
+}
+
+// Lookup implements FleetStore.
+func (s *MemoryFleetStore) Lookup(_ context.Context, id string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	host, ok := s.owners[id]
+	return host, ok, nil
+}
+
+// Store implements FleetStore.
+func (s *MemoryFleetStore) Store(_ context.Context, id, host string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owners[id] = host
+	return nil
+}
+
+// Forget implements FleetStore.
+func (s *MemoryFleetStore) Forget(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.owners, id)
+	return nil
+}
+
+// FleetOption configures a FleetClient.
+type FleetOption func(*FleetClient) error
+
+// FleetHostOption configures a single fleet host.
+type FleetHostOption func(*fleetHost)
+
+// WithMaxBrowsers caps how many browsers may run on the host at once.
+// The fleet places no new profiles on a host at its cap and refuses to open
+// more browsers there. Default: 0 (unlimited).
+func WithMaxBrowsers(n int) FleetHostOption {
+	return func(h *fleetHost) {
+		h.maxBrowsers = n
+	}
+}
+
+// WithMetricsAgent sets an agent that reports the host's CPU and memory
+// usage for scheduling.
+func WithMetricsAgent(agent MetricsAgent) FleetHostOption {
+	return func(h *fleetHost) {
+		h.agent = agent
+	}
+}
+
+// WithFleetHost adds a BitBrowser instance to the fleet under a unique name.
+func WithFleetHost(name string, client *Client, opts ...FleetHostOption) FleetOption {
+	return func(f *FleetClient) error {
+		if name == "" {
+			return NewValidationError("name", "fleet host name is required")
+		}
+		if client == nil {
+			return NewValidationError("client", "fleet host client is required")
+		}
+		if _, ok := f.byName[name]; ok {
+			return &ValidationError{Field: "name", Message: "duplicate fleet host", Value: name}
+		}
+		h := &fleetHost{name: name, client: client}
+		for _, opt := range opts {
+			opt(h)
+		}
+		if h.maxBrowsers < 0 {
+			return &ValidationError{Field: "maxBrowsers", Message: "must not be negative", Value: h.maxBrowsers}
+		}
+		f.hosts = append(f.hosts, h)
+		f.byName[name] = h
+		return nil
+	}
+}
+
+// WithFleetStore sets where profile owners are recorded.
+// Default: a new MemoryFleetStore.
+func WithFleetStore(store FleetStore) FleetOption {
+	return func(f *FleetClient) error {
+		if store == nil {
+			return NewValidationError("store", "fleet store is required")
+		}
+		f.store = store
+		return nil
+	}
+}
+
+// NewFleetClient creates a FleetClient. At least one host is required.
+func NewFleetClient(opts ...FleetOption) (*FleetClient, error) {
+	f := &FleetClient{
+		byName: make(map[string]*fleetHost),
+	}
+	for _, opt := range opts {
+		if err := opt(f); err != nil {
+			return nil, err
+		}
+	}
+	if len(f.hosts) == 0 {
+		return nil, NewValidationError("hosts", "at least one fleet host is required")
+	}
+	if f.store == nil {
+		f.store = NewMemoryFleetStore()
+	}
+	return f, nil
+}
+
+// FleetOpenResult is an OpenResult together with the host that runs the browser.
+type FleetOpenResult struct {
+	Host string // Name of the host that opened the browser
+	*OpenResult
+}
+
+// FleetProfile is a profile together with the host that stores it.
+type FleetProfile struct {
+	Host string
+	ProfileDetail
+}
+
+// FleetPort is a debugging port together with the host it belongs to.
+type FleetPort struct {
+	Host string
+	Port int
+}
+
+// Hosts returns the host names in the order they were added.
+func (f *FleetClient) Hosts() []string {
+	names := make([]string, len(f.hosts))
+	for i, h := range f.hosts {
+		names[i] = h.name
+	}
+	return names
+}
+
+// Host returns the client for a host, or nil if there is no such host.
+func (f *FleetClient) Host(name string) *Client {
+	if h, ok := f.byName[name]; ok {
+		return h.client
+	}
+	return nil
+}
+
+// HostOf returns the name of the host that owns the profile.
+func (f *FleetClient) HostOf(ctx context.Context, id string) (string, error) {
+	h, err := f.locate(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return h.name, nil
+}
+
+// CreateProfile creates a profile on the least loaded host and returns the
+// host name and the new profile ID. If every host is at its browser cap or
+// unreachable, the error matches ErrNoCapacity.
+func (f *FleetClient) CreateProfile(ctx context.Context, config ProfileConfig) (host, id string, err error) {
+	h, err := f.schedule(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	id, err = h.client.CreateProfile(ctx, config)
+	if err != nil {
+		return "", "", &FleetError{Host: h.name, Err: err}
+	}
+	f.remember(ctx, id, h.name)
+	return h.name, id, nil
+}
+
+// Open opens the profile's browser on the host that owns it.
+//
+// If the host recorded in the store no longer has the profile, Open returns
+// a *ProfileNotOnHostError whose Hint names the current owner, if any. The
+// store is updated, so retrying the call reaches the right host.
+//
+// If the host has a browser cap that is already reached, Open fails with an
+// error matching ErrNoCapacity.
+func (f *FleetClient) Open(ctx context.Context, id string, opts *OpenOptions) (*FleetOpenResult, error) {
+	h, err := f.locate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.checkCapacity(ctx, h, id); err != nil {
+		return nil, err
+	}
+
+	result, err := h.client.Open(ctx, id, opts)
+	if err != nil {
+		if miss := f.misrouted(ctx, id, h, err); miss != nil {
+			return nil, miss
+		}
+		return nil, &FleetError{Host: h.name, Err: err}
+	}
+	return &FleetOpenResult{Host: h.name, OpenResult: result}, nil
+}
+
+// Close closes the profile's browser on the host that owns it.
+func (f *FleetClient) Close(ctx context.Context, id string) error {
+	h, err := f.locate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := h.client.Close(ctx, id); err != nil {
+		if miss := f.misrouted(ctx, id, h, err); miss != nil {
+			return miss
+		}
+		return &FleetError{Host: h.name, Err: err}
+	}
+	return nil
+}
+
+// ListProfiles runs req against every host and concatenates the results.
+// Paging applies to each host separately.
+//
+// If some hosts fail, the profiles from the others are returned together
+// with an error joining a *FleetError for each failed host.
+func (f *FleetClient) ListProfiles(ctx context.Context, req ListRequest) ([]FleetProfile, error) {
+	var mu sync.Mutex
+	var profiles []FleetProfile
+
+	err := f.forEach(ctx, func(ctx context.Context, h *fleetHost) error {
+		result, err := h.client.ListProfiles(ctx, req)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, p := range result.List {
+			profiles = append(profiles, FleetProfile{Host: h.name, ProfileDetail: p})
+			f.remember(ctx, p.ID, h.name)
+		}
+		return nil
+	})
+	return profiles, err
+}
+
+// GetPorts returns the debugging ports of open browsers on every host,
+// keyed by profile ID. Partial failures are reported as in ListProfiles.
+func (f *FleetClient) GetPorts(ctx context.Context) (map[string]FleetPort, error) {
+	var mu sync.Mutex
+	ports := make(map[string]FleetPort)
+
+	err := f.forEach(ctx, func(ctx context.Context, h *fleetHost) error {
+		hostPorts, err := h.client.GetPorts(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for id, port := range hostPorts {
+			ports[id] = FleetPort{Host: h.name, Port: port}
+			f.remember(ctx, id, h.name)
+		}
+		return nil
+	})
+	return ports, err
+}
+
+// forEach calls fn for every host concurrently and joins the failures.
+func (f *FleetClient) forEach(ctx context.Context, fn func(ctx context.Context, h *fleetHost) error) error {
+	errs := make([]error, len(f.hosts))
+	var wg sync.WaitGroup
+	for i, h := range f.hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(ctx, h); err != nil {
+				errs[i] = &FleetError{Host: h.name, Err: err}
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// locate finds the host that owns a profile: the host recorded in the
+// store, or else the one found by asking every host.
+func (f *FleetClient) locate(ctx context.Context, id string) (*fleetHost, error) {
+	if err := ValidateProfileID(id); err != nil {
+		return nil, err
+	}
+
+	if name, ok, err := f.store.Lookup(ctx, id); err == nil && ok {
+		if h, ok := f.byName[name]; ok {
+			return h, nil
+		}
+	}
+
+	owner, err := f.discover(ctx, id, nil)
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: locate profile %s failed: %w", id, err)
+	}
+	return owner, nil
+}
+
+// discover asks every host except skip for the profile and records the
+// owner. If no host has it, the error matches ErrProfileNotFound, unless
+// some host could not be asked.
+func (f *FleetClient) discover(ctx context.Context, id string, skip *fleetHost) (*fleetHost, error) {
+	var mu sync.Mutex
+	var owner *fleetHost
+	err := f.forEach(ctx, func(ctx context.Context, h *fleetHost) error {
+		if h == skip {
+			return nil
+		}
+		if _, err := h.client.GetProfileDetail(ctx, id); err != nil {
+			return err
+		}
+		mu.Lock()
+		if owner == nil {
+			owner = h
+		}
+		mu.Unlock()
+		return nil
+	})
+	if owner != nil {
+		f.remember(ctx, id, owner.name)
+		return owner, nil
+	}
+
+	// Only claim the profile is missing if every host answered
+	if err != nil && !isRejection(ctx, err) {
+		return nil, err
+	}
+	return nil, errors.Join(ErrProfileNotFound, err)
+}
+
+// misrouted checks whether err, returned by h for a profile, may mean the
+// profile is not on h. If h confirms it does not have the profile, the owner
+// is re-resolved and a *ProfileNotOnHostError is returned; otherwise nil.
+func (f *FleetClient) misrouted(ctx context.Context, id string, h *fleetHost, err error) error {
+	if !isRejection(ctx, err) || isTransientRejection(err) {
+		return nil
+	}
+	if _, err := h.client.GetProfileDetail(ctx, id); !isRejection(ctx, err) {
+		return nil
+	}
+
+	f.store.Forget(ctx, id)
+	miss := &ProfileNotOnHostError{ProfileID: id, Host: h.name}
+	if owner, err := f.discover(ctx, id, h); err == nil {
+		miss.Hint = owner.name
+	}
+	return miss
+}
+
+// isRejection reports whether err is an answer from the host rather than a
+// failure to reach it.
+func isRejection(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() == nil && !errors.Is(err, ErrNetwork) && !errors.Is(err, ErrTimeout)
+}
+
+// isTransientRejection reports whether err is a refusal that says nothing
+// about where the profile lives, such as a busy or locked profile.
+func isTransientRejection(err error) bool {
+	for _, sentinel := range []error{ErrBusy, ErrQuotaExceeded, ErrProfileLocked, ErrKernelDownloading, ErrInsufficientBalance} {
+		if errors.Is(err, sentinel) {
+			return true
+		}
+	}
+	return false
+}
+
+// remember records the owner of a profile. Store failures are ignored;
+// the owner is rediscovered when needed.
+func (f *FleetClient) remember(ctx context.Context, id, host string) {
+	f.store.Store(ctx, id, host)
+}
@@ -0,0 +1,323 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeHost emulates a BitBrowser instance that stores a set of profiles.
+type fakeHost struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	profiles map[string]bool
+	open     map[string]bool
+	calls    map[string]int
+}
+
+// newFakeHost starts a fake host that owns the given profiles.
+func newFakeHost(t *testing.T, ids ...string) *fakeHost {
+	t.Helper()
+	h := &fakeHost{profiles: make(map[string]bool), open: make(map[string]bool), calls: make(map[string]int)}
+	for _, id := range ids {
+		h.profiles[id] = true
+	}
+	h.Server = mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID string `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.calls[r.URL.Path]++
+
+		switch r.URL.Path {
+		case "/browser/detail":
+			if !h.profiles[req.ID] {
+				w.Write(errorResponse("browser not found"))
+				return
+			}
+			w.Write(successResponse(ProfileDetail{ID: req.ID}))
+		case "/browser/update":
+			id := fmt.Sprintf("%s-new-%d", t.Name(), len(h.profiles))
+			h.profiles[id] = true
+			w.Write(successResponse(map[string]string{"id": id}))
+		case "/browser/open":
+			if !h.profiles[req.ID] {
+				w.Write(errorResponse("browser not found"))
+				return
+			}
+			h.open[req.ID] = true
+			w.Write(successResponse(OpenResult{Ws: "ws://" + r.Host + "/devtools/browser/" + req.ID}))
+		case "/browser/close":
+			if !h.profiles[req.ID] {
+				w.Write(errorResponse("browser not found"))
+				return
+			}
+			delete(h.open, req.ID)
+			w.Write(successResponse(nil))
+		case "/browser/list":
+			var list []ProfileDetail
+			for id := range h.profiles {
+				list = append(list, ProfileDetail{ID: id})
+			}
+			w.Write(successResponse(ListResult{List: list, Total: len(list)}))
+		case "/browser/pids/all":
+			pids := make(map[string]int)
+			for id := range h.open {
+				pids[id] = 1000 + len(pids)
+			}
+			w.Write(successResponse(pids))
+		case "/browser/ports":
+			ports := make(map[string]string)
+			for id := range h.open {
+				ports[id] = "9222"
+			}
+			w.Write(successResponse(ports))
+		}
+	})
+	t.Cleanup(h.Close)
+	return h
+}
+
+// move transfers a profile from h to dst, as if an operator migrated it.
+func (h *fakeHost) move(id string, dst *fakeHost) {
+	h.mu.Lock()
+	delete(h.profiles, id)
+	h.mu.Unlock()
+	dst.mu.Lock()
+	dst.profiles[id] = true
+	dst.mu.Unlock()
+}
+
+// callsTo returns how many requests were made to path.
+func (h *fakeHost) callsTo(path string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.calls[path]
+}
+
+// mustNewFleet creates a FleetClient over the fake hosts named "a", "b", ...
+func mustNewFleet(t *testing.T, hosts ...*fakeHost) *FleetClient {
+	t.Helper()
+	return mustNewFleetWith(t, hosts)
+}
+
+// mustNewFleetWith is mustNewFleet with extra fleet options.
+func mustNewFleetWith(t *testing.T, hosts []*fakeHost, extra ...FleetOption) *FleetClient {
+	t.Helper()
+	var opts []FleetOption
+	for i, h := range hosts {
+		opts = append(opts, WithFleetHost(string(rune('a'+i)), mustNew(t, h.URL)))
+	}
+	fleet, err := NewFleetClient(append(opts, extra...)...)
+	if err != nil {
+		t.Fatalf("NewFleetClient failed: %v", err)
+	}
+	return fleet
+}
+
+func TestNewFleetClient(t *testing.T) {
+	client := mustNew(t, "http://127.0.0.1:54345")
+
+	if _, err := NewFleetClient(); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation without hosts, got %v", err)
+	}
+	if _, err := NewFleetClient(WithFleetHost("a", client), WithFleetHost("a", client)); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation for duplicate host, got %v", err)
+	}
+	if _, err := NewFleetClient(WithFleetHost("", client)); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation for empty name, got %v", err)
+	}
+	if _, err := NewFleetClient(WithFleetHost("a", client), WithFleetStore(nil)); !errors.Is(err, ErrValidation) {
+		t.Errorf("expected ErrValidation for nil store, got %v", err)
+	}
+
+	fleet, err := NewFleetClient(WithFleetHost("a", client), WithFleetHost("b", client))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hosts := fleet.Hosts(); len(hosts) != 2 || hosts[0] != "a" || hosts[1] != "b" {
+		t.Errorf("Hosts = %v", hosts)
+	}
+	if fleet.Host("a") != client || fleet.Host("missing") != nil {
+		t.Error("Host returned the wrong client")
+	}
+}
+
+func TestFleetClient(t *testing.T) {
+	t.Run("routes open to owning host", func(t *testing.T) {
+		a, b := newFakeHost(t, "p1"), newFakeHost(t, "p2")
+		fleet := mustNewFleet(t, a, b)
+
+		result, err := fleet.Open(context.Background(), "p2", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Host != "b" || result.Ws == "" {
+			t.Errorf("unexpected result: %+v", result)
+		}
+		if a.callsTo("/browser/open") != 0 || b.callsTo("/browser/open") != 1 {
+			t.Error("open was sent to the wrong host")
+		}
+
+		// The owner is remembered
+		fleet.Close(context.Background(), "p2")
+		if n := b.callsTo("/browser/detail"); n != 1 {
+			t.Errorf("detail lookups = %d, want 1", n)
+		}
+		if b.callsTo("/browser/close") != 1 {
+			t.Error("close was not sent to the owning host")
+		}
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		fleet := mustNewFleet(t, newFakeHost(t, "p1"), newFakeHost(t, "p2"))
+
+		_, err := fleet.Open(context.Background(), "missing", nil)
+		if !errors.Is(err, ErrProfileNotFound) {
+			t.Errorf("expected ErrProfileNotFound, got %v", err)
+		}
+	})
+
+	t.Run("unreachable host is not reported as not found", func(t *testing.T) {
+		down := newFakeHost(t)
+		down.Close()
+		fleet := mustNewFleet(t, newFakeHost(t, "p1"), down)
+
+		_, err := fleet.Open(context.Background(), "missing", nil)
+		if errors.Is(err, ErrProfileNotFound) || !errors.Is(err, ErrNetwork) {
+			t.Errorf("expected network error, got %v", err)
+		}
+		if !strings.Contains(fmt.Sprint(err), `fleet host "b"`) {
+			t.Errorf("expected failure for host b, got %v", err)
+		}
+	})
+
+	t.Run("creates on least loaded host", func(t *testing.T) {
+		a, b := newFakeHost(t, "p1"), newFakeHost(t)
+		fleet := mustNewFleet(t, a, b)
+
+		if _, err := fleet.Open(context.Background(), "p1", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		host, id, err := fleet.CreateProfile(context.Background(), ProfileConfig{Name: "new"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if host != "b" {
+			t.Errorf("host = %q, want b", host)
+		}
+		if owner, _ := fleet.HostOf(context.Background(), id); owner != "b" {
+			t.Errorf("HostOf = %q, want b", owner)
+		}
+	})
+
+	t.Run("aggregates list and ports", func(t *testing.T) {
+		a, b := newFakeHost(t, "p1"), newFakeHost(t, "p2", "p3")
+		fleet := mustNewFleet(t, a, b)
+		fleet.Open(context.Background(), "p3", nil)
+
+		profiles, err := fleet.ListProfiles(context.Background(), ListRequest{PageSize: 100})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		hosts := make(map[string]string)
+		for _, p := range profiles {
+			hosts[p.ID] = p.Host
+		}
+		if len(hosts) != 3 || hosts["p1"] != "a" || hosts["p2"] != "b" || hosts["p3"] != "b" {
+			t.Errorf("unexpected profiles: %v", hosts)
+		}
+
+		ports, err := fleet.GetPorts(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ports) != 1 || ports["p3"] != (FleetPort{Host: "b", Port: 9222}) {
+			t.Errorf("unexpected ports: %v", ports)
+		}
+	})
+
+	t.Run("partial failures return remaining results", func(t *testing.T) {
+		down := newFakeHost(t)
+		down.Close()
+		fleet := mustNewFleet(t, newFakeHost(t, "p1"), down)
+
+		profiles, err := fleet.ListProfiles(context.Background(), ListRequest{PageSize: 100})
+		if len(profiles) != 1 {
+			t.Errorf("profiles = %d, want 1", len(profiles))
+		}
+		var fleetErr *FleetError
+		if !errors.As(err, &fleetErr) || fleetErr.Host != "b" {
+			t.Errorf("expected FleetError for host b, got %v", err)
+		}
+	})
+
+	t.Run("routes through the store", func(t *testing.T) {
+		a, b := newFakeHost(t, "p1"), newFakeHost(t, "p2")
+		store := NewMemoryFleetStore()
+		store.Store(context.Background(), "p2", "b")
+		fleet := mustNewFleetWith(t, []*fakeHost{a, b}, WithFleetStore(store))
+
+		if _, err := fleet.Open(context.Background(), "p2", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if a.callsTo("/browser/detail") != 0 || b.callsTo("/browser/detail") != 0 {
+			t.Error("expected no discovery for a stored owner")
+		}
+
+		// Discovered owners are written back
+		fleet.Open(context.Background(), "p1", nil)
+		if host, ok, _ := store.Lookup(context.Background(), "p1"); !ok || host != "a" {
+			t.Errorf("stored owner = %q, %v; want a", host, ok)
+		}
+	})
+
+	t.Run("stale route returns host hint", func(t *testing.T) {
+		a, b := newFakeHost(t, "p1"), newFakeHost(t)
+		fleet := mustNewFleet(t, a, b)
+		if owner, _ := fleet.HostOf(context.Background(), "p1"); owner != "a" {
+			t.Fatalf("HostOf = %q, want a", owner)
+		}
+		a.move("p1", b)
+
+		_, err := fleet.Open(context.Background(), "p1", nil)
+		var miss *ProfileNotOnHostError
+		if !errors.As(err, &miss) || !errors.Is(err, ErrProfileNotOnHost) {
+			t.Fatalf("expected ProfileNotOnHostError, got %v", err)
+		}
+		if miss.Host != "a" || miss.Hint != "b" {
+			t.Errorf("Host = %q, Hint = %q; want a, b", miss.Host, miss.Hint)
+		}
+
+		// The store now points at the new owner
+		result, err := fleet.Open(context.Background(), "p1", nil)
+		if err != nil || result.Host != "b" {
+			t.Errorf("retry: result = %+v, err = %v", result, err)
+		}
+	})
+
+	t.Run("stale route without owner", func(t *testing.T) {
+		a := newFakeHost(t, "p1")
+		fleet := mustNewFleet(t, a, newFakeHost(t))
+		fleet.HostOf(context.Background(), "p1")
+		a.mu.Lock()
+		delete(a.profiles, "p1")
+		a.mu.Unlock()
+
+		err := fleet.Close(context.Background(), "p1")
+		var miss *ProfileNotOnHostError
+		if !errors.As(err, &miss) || miss.Hint != "" {
+			t.Errorf("expected ProfileNotOnHostError without hint, got %v", err)
+		}
+	})
+}
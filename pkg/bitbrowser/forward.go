@@ -0,0 +1,251 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PortForwarder opens connections to a port on a remote host's loopback
+// interface, where browsers opened without AllowLAN listen. SSHPortForwarder
+// tunnels over ssh; an AgentClient tunnels through antidetect-agent.
+type PortForwarder interface {
+	// DialRemote connects to 127.0.0.1:port as seen from host.
+	DialRemote(ctx context.Context, host string, port int) (net.Conn, error)
+}
+
+// WithPortForwarder sets how ForwardPort reaches loopback-only ports on
+// the BitBrowser host.
+func WithPortForwarder(forwarder PortForwarder) ClientOption {
+	return func(c *Client) {
+		c.portForwarder = forwarder
+	}
+}
+
+// PortForward is a local TCP proxy to a browser's debugging port on a
+// remote host, created by ForwardPort.
+type PortForward struct {
+	LocalAddr string // Local listening address, e.g. "127.0.0.1:53121"
+	Http      string // Local HTTP debug endpoint
+	Ws        string // Local DevTools WebSocket URL
+
+	ln     net.Listener
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// ForwardPort makes a browser that listens only on 127.0.0.1 of a remote
+// host controllable from here, e.g. a Native Mode browser opened without
+// AllowLAN. It listens on a local port, forwards every connection to
+// remotePort on remoteHost through the PortForwarder set with
+// WithPortForwarder, and returns the locally reachable WebSocket URL.
+//
+// Close the PortForward when done; it stops listening and closes the
+// forwarded connections.
+//
+// Example:
+//
+//	client, _ := bitbrowser.New("http://10.0.0.5:54345",
+//	    bitbrowser.WithPortForwarder(&bitbrowser.SSHPortForwarder{Args: []string{"-l", "admin"}}))
+//	result, _ := client.Open(ctx, id, nil) // result.Ws is ws://127.0.0.1:<port>/...
+//	fwd, err := client.ForwardPort(ctx, "10.0.0.5", port)
+//	defer fwd.Close()
+//	conn, err := cdp.Dial(ctx, fwd.Ws)
+func (c *Client) ForwardPort(ctx context.Context, remoteHost string, remotePort int) (*PortForward, error) {
+	if c.portForwarder == nil {
+		return nil, NewValidationError("PortForwarder", "no port forwarder configured; use WithPortForwarder")
+	}
+	if remoteHost == "" {
+		return nil, NewValidationError("remoteHost", "remote host is required")
+	}
+	if remotePort <= 0 || remotePort > 65535 {
+		return nil, &ValidationError{Field: "remotePort", Message: "port must be between 1 and 65535", Value: remotePort}
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: forward port: %w", err)
+	}
+	f := &PortForward{
+		LocalAddr: ln.Addr().String(),
+		ln:        ln,
+		conns:     make(map[net.Conn]struct{}),
+	}
+	f.Http = "http://" + f.LocalAddr
+	f.wg.Add(1)
+	go f.serve(func(ctx context.Context) (net.Conn, error) {
+		return c.portForwarder.DialRemote(ctx, remoteHost, remotePort)
+	})
+
+	version, err := c.GetBrowserVersion(ctx, f.Http)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	ws, err := url.Parse(version.WebSocketDebuggerURL)
+	if err != nil || ws.Host == "" {
+		f.Close()
+		return nil, NewAPIError("/json/version", 0, "invalid webSocketDebuggerUrl: "+version.WebSocketDebuggerURL)
+	}
+	ws.Host = f.LocalAddr
+	f.Ws = ws.String()
+	return f, nil
+}
+
+// Close stops listening and closes all forwarded connections.
+func (f *PortForward) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return nil
+	}
+	f.closed = true
+	err := f.ln.Close()
+	for conn := range f.conns {
+		conn.Close()
+	}
+	f.mu.Unlock()
+	f.wg.Wait()
+	return err
+}
+
+// serve accepts local connections and pipes each to a remote one.
+func (f *PortForward) serve(dial func(ctx context.Context) (net.Conn, error)) {
+	defer f.wg.Done()
+	for {
+		local, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			remote, err := dial(context.Background())
+			if err != nil {
+				local.Close()
+				return
+			}
+			if !f.track(local, remote) {
+				return
+			}
+			pipe(local, remote)
+			f.untrack(local, remote)
+		}()
+	}
+}
+
+// track registers open connections so Close can end them. It reports false,
+// closing them, if the forward is already closed.
+func (f *PortForward) track(conns ...net.Conn) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		for _, conn := range conns {
+			conn.Close()
+		}
+		return false
+	}
+	for _, conn := range conns {
+		f.conns[conn] = struct{}{}
+	}
+	return true
+}
+
+// untrack forgets connections that pipe has closed.
+func (f *PortForward) untrack(conns ...net.Conn) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, conn := range conns {
+		delete(f.conns, conn)
+	}
+}
+
+// pipe copies between a and b until either side is done, then closes both.
+func pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() { io.Copy(a, b); done <- struct{}{} }()
+	go func() { io.Copy(b, a); done <- struct{}{} }()
+	<-done
+	a.Close()
+	b.Close()
+	<-done
+}
+
+// SSHPortForwarder reaches remote loopback ports with "ssh -W", one ssh
+// process per connection. Authentication is left to ssh, so keys and users
+// come from Args or ~/.ssh/config.
+type SSHPortForwarder struct {
+	Args    []string // Extra ssh arguments placed before the host, e.g. "-l", "admin"
+	Command string   // ssh binary (default: "ssh")
+}
+
+// DialRemote starts "ssh <Args> -W 127.0.0.1:<port> <host>" and returns its
+// stdin and stdout as a connection.
+func (s *SSHPortForwarder) DialRemote(ctx context.Context, host string, port int) (net.Conn, error) {
+	command := s.Command
+	if command == "" {
+		command = "ssh"
+	}
+	args := append(append([]string{}, s.Args...), "-W", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)), host)
+	// Not bound to ctx: the tunnel must outlive the dial.
+	cmd := exec.Command(command, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%s: %w", command, err)
+	}
+	return &cmdConn{cmd: cmd, stdin: stdin, stdout: stdout, host: host}, nil
+}
+
+// cmdConn is a net.Conn over a subprocess's stdin and stdout.
+type cmdConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	host   string
+	once   sync.Once
+}
+
+func (c *cmdConn) Read(b []byte) (int, error)  { return c.stdout.Read(b) }
+func (c *cmdConn) Write(b []byte) (int, error) { return c.stdin.Write(b) }
+
+// Close ends the subprocess.
+func (c *cmdConn) Close() error {
+	c.once.Do(func() {
+		c.stdin.Close()
+		c.cmd.Process.Kill()
+		c.cmd.Wait()
+	})
+	return nil
+}
+
+func (c *cmdConn) LocalAddr() net.Addr  { return cmdAddr("local") }
+func (c *cmdConn) RemoteAddr() net.Addr { return cmdAddr(c.host) }
+
+// Deadlines are not supported on subprocess pipes.
+func (c *cmdConn) SetDeadline(time.Time) error      { return errDeadlineUnsupported }
+func (c *cmdConn) SetReadDeadline(time.Time) error  { return errDeadlineUnsupported }
+func (c *cmdConn) SetWriteDeadline(time.Time) error { return errDeadlineUnsupported }
+
+var errDeadlineUnsupported = errors.New("deadlines are not supported on ssh tunnels")
+
+// cmdAddr is the net.Addr of a cmdConn.
+type cmdAddr string
+
+func (a cmdAddr) Network() string { return "ssh" }
+func (a cmdAddr) String() string  { return string(a) }
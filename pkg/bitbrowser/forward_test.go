@@ -0,0 +1,96 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// directForwarder dials a fixed address instead of tunneling.
+type directForwarder struct {
+	addr  string
+	mu    sync.Mutex
+	hosts []string
+}
+
+func (d *directForwarder) DialRemote(ctx context.Context, host string, port int) (net.Conn, error) {
+	d.mu.Lock()
+	d.hosts = append(d.hosts, host)
+	d.mu.Unlock()
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", d.addr)
+}
+
+func TestForwardPort(t *testing.T) {
+	browser := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"webSocketDebuggerUrl":"ws://127.0.0.1:9222/devtools/browser/abc"}`))
+	})
+	defer browser.Close()
+	u, _ := url.Parse(browser.URL)
+
+	forwarder := &directForwarder{addr: u.Host}
+	client := mustNew(t, "http://10.0.0.5:54345", WithPortForwarder(forwarder))
+	fwd, err := client.ForwardPort(context.Background(), "10.0.0.5", 9222)
+	if err != nil {
+		t.Fatalf("ForwardPort() error = %v", err)
+	}
+	defer fwd.Close()
+
+	if want := "ws://" + fwd.LocalAddr + "/devtools/browser/abc"; fwd.Ws != want {
+		t.Errorf("Ws = %q, want %q", fwd.Ws, want)
+	}
+	if !strings.HasPrefix(fwd.LocalAddr, "127.0.0.1:") {
+		t.Errorf("LocalAddr = %q, want loopback", fwd.LocalAddr)
+	}
+	forwarder.mu.Lock()
+	if len(forwarder.hosts) == 0 || forwarder.hosts[0] != "10.0.0.5" {
+		t.Errorf("DialRemote hosts = %v", forwarder.hosts)
+	}
+	forwarder.mu.Unlock()
+
+	if err := fwd.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, err := net.Dial("tcp", fwd.LocalAddr); err == nil {
+		t.Error("forward still accepts connections after Close")
+	}
+}
+
+func TestForwardPortValidation(t *testing.T) {
+	ctx := context.Background()
+	if _, err := mustNew(t, "http://10.0.0.5:54345").ForwardPort(ctx, "10.0.0.5", 9222); !errors.Is(err, ErrValidation) {
+		t.Errorf("no forwarder: err = %v, want ErrValidation", err)
+	}
+	client := mustNew(t, "http://10.0.0.5:54345", WithPortForwarder(&directForwarder{}))
+	if _, err := client.ForwardPort(ctx, "10.0.0.5", 0); !errors.Is(err, ErrValidation) {
+		t.Errorf("bad port: err = %v, want ErrValidation", err)
+	}
+}
+
+func TestSSHPortForwarder(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX commands")
+	}
+	// "sh -c cat -- -W ... host" runs cat, which echoes the tunnel.
+	forwarder := &SSHPortForwarder{Command: "sh", Args: []string{"-c", "cat", "--"}}
+	conn, err := forwarder.DialRemote(context.Background(), "admin@host", 9222)
+	if err != nil {
+		t.Fatalf("DialRemote() error = %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "ping" {
+		t.Errorf("Read() = %q, %v; want ping", buf, err)
+	}
+}
@@ -0,0 +1,56 @@
+package bitbrowser
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+// ProxyGeoOverride returns the time zone, locale and position of a proxy,
+// as found by CheckProxy, for emulating them in a running browser whose
+// fingerprint cannot be edited mid-session. The locale is the first of the
+// proxy's languages. Values the check did not find are left empty.
+//
+// Example:
+//
+//	check, err := client.CheckProxy(ctx, req)
+//	override, err := bitbrowser.ProxyGeoOverride(check)
+//	err = session.Emulate(ctx, override)
+func ProxyGeoOverride(check *ProxyCheckResult) (cdp.GeoOverride, error) {
+	if check == nil || !check.Success {
+		return cdp.GeoOverride{}, NewValidationError("check", "proxy check did not succeed")
+	}
+	o := cdp.GeoOverride{Timezone: check.Data.TimeZone}
+	if lang, _, _ := strings.Cut(check.Data.Languages, ","); lang != "" {
+		lang, _, _ = strings.Cut(lang, ";")
+		o.Locale = strings.TrimSpace(lang)
+	}
+	if check.Data.Latitude != "" && check.Data.Longitude != "" {
+		lat, err := strconv.ParseFloat(check.Data.Latitude, 64)
+		if err != nil {
+			return cdp.GeoOverride{}, &ValidationError{Field: "latitude", Message: "not a number", Value: check.Data.Latitude}
+		}
+		long, err := strconv.ParseFloat(check.Data.Longitude, 64)
+		if err != nil {
+			return cdp.GeoOverride{}, &ValidationError{Field: "longitude", Message: "not a number", Value: check.Data.Longitude}
+		}
+		o.Geolocation = &cdp.Geolocation{Latitude: lat, Longitude: long}
+	}
+	return o, nil
+}
+
+// GeoOverrideHook returns a ReadyHook that emulates o for as long as the
+// browser runs.
+//
+// Example:
+//
+//	result, err := client.Open(ctx, id, &bitbrowser.OpenOptions{
+//	    OnReady: []bitbrowser.ReadyHook{bitbrowser.GeoOverrideHook(override)},
+//	})
+func GeoOverrideHook(o cdp.GeoOverride) ReadyHook {
+	return func(ctx context.Context, session *cdp.Session) error {
+		return session.Emulate(ctx, o)
+	}
+}
@@ -0,0 +1,57 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+func TestProxyGeoOverride(t *testing.T) {
+	var check ProxyCheckResult
+	check.Success = true
+	check.Data.TimeZone = "Europe/Berlin"
+	check.Data.Languages = "de-DE,de;q=0.9,en;q=0.8"
+	check.Data.Latitude = "52.52"
+	check.Data.Longitude = "13.405"
+
+	o, err := ProxyGeoOverride(&check)
+	if err != nil {
+		t.Fatalf("ProxyGeoOverride failed: %v", err)
+	}
+	if o.Timezone != "Europe/Berlin" || o.Locale != "de-DE" || o.Geolocation == nil ||
+		o.Geolocation.Latitude != 52.52 || o.Geolocation.Longitude != 13.405 {
+		t.Errorf("override = %+v, %+v", o, o.Geolocation)
+	}
+
+	check.Data.Longitude = ""
+	if o, err := ProxyGeoOverride(&check); err != nil || o.Geolocation != nil {
+		t.Errorf("without longitude: %+v, %v; want no geolocation", o, err)
+	}
+
+	check.Data.Longitude = "east"
+	var validationErr *ValidationError
+	if _, err := ProxyGeoOverride(&check); !errors.As(err, &validationErr) || validationErr.Field != "longitude" {
+		t.Errorf("error = %v, want a longitude ValidationError", err)
+	}
+	if _, err := ProxyGeoOverride(&ProxyCheckResult{}); err == nil {
+		t.Error("expected an error for a failed check")
+	}
+}
+
+func TestGeoOverrideHook(t *testing.T) {
+	devtools := newFakeDevTools(t)
+	client := devToolsClient(t, devtools)
+	_, err := client.Open(context.Background(), "profile-1", &OpenOptions{
+		OnReady: []ReadyHook{GeoOverrideHook(cdp.GeoOverride{Timezone: "Asia/Tokyo", Locale: "ja-JP"})},
+	})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	calls := strings.Join(devtools.calls(), ",")
+	if !strings.HasSuffix(calls, "Emulation.setTimezoneOverride,Emulation.setLocaleOverride") {
+		t.Errorf("DevTools calls = %s", calls)
+	}
+}
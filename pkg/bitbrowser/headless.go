@@ -0,0 +1,98 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+// OpenWarningCode identifies an OpenWarning.
+type OpenWarningCode string
+
+// Open warning codes.
+const (
+	// WarnStartURLNavigated: StartURL was left out of the headless launch
+	// and loaded over CDP afterwards.
+	WarnStartURLNavigated OpenWarningCode = "start_url_navigated"
+	// WarnStartURLDropped: StartURL was left out of the headless launch and
+	// loading it afterwards failed; the browser shows a blank page.
+	WarnStartURLDropped OpenWarningCode = "start_url_dropped"
+)
+
+// OpenWarning reports that Open did something differently from what
+// OpenOptions asked for, without failing. Warnings are listed in
+// OpenResult.Warnings.
+type OpenWarning struct {
+	Code    OpenWarningCode
+	Message string
+}
+
+func (w OpenWarning) String() string {
+	return string(w.Code) + ": " + w.Message
+}
+
+// openURLs returns the IgnoreDefaultUrls and NewPageUrl values to send.
+// BitBrowser requires NewPageUrl to be empty in headless mode, so unless
+// opts.KeepHeadlessURLs is set, headless launches ignore synced URLs and
+// leave StartURL to navigateStartURL.
+func openURLs(opts *OpenOptions) (ignoreDefaultUrls bool, startURL string) {
+	if opts.Headless && !opts.KeepHeadlessURLs {
+		return true, ""
+	}
+	return opts.IgnoreDefaultUrls, opts.StartURL
+}
+
+// headlessStartURL reports whether openURLs left out opts.StartURL.
+func headlessStartURL(opts *OpenOptions) bool {
+	return opts.Headless && !opts.KeepHeadlessURLs && opts.StartURL != ""
+}
+
+// navigateStartURL loads url in the first page of an opened browser and
+// records the outcome in result.Warnings.
+// Page.navigate
+func (c *Client) navigateStartURL(ctx context.Context, result *OpenResult, url string) {
+	err := func() error {
+		if result.Ws == "" {
+			return fmt.Errorf("no WebSocket URL")
+		}
+		conn, err := cdp.Dial(ctx, result.Ws)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		session, err := conn.AttachToPage(ctx)
+		if err != nil {
+			return err
+		}
+		var nav struct {
+			ErrorText string `json:"errorText"`
+		}
+		params := struct {
+			URL string `json:"url"`
+		}{URL: url}
+		if err := session.Call(ctx, "Page.navigate", params, &nav); err != nil {
+			return err
+		}
+		if nav.ErrorText != "" {
+			return errors.New(nav.ErrorText)
+		}
+		return nil
+	}()
+
+	if err != nil {
+		if c.logger != nil {
+			c.logger.WarnContext(ctx, "bitbrowser: failed to load start URL after headless launch", "url", url, "error", err)
+		}
+		result.Warnings = append(result.Warnings, OpenWarning{
+			Code:    WarnStartURLDropped,
+			Message: fmt.Sprintf("headless mode cannot open StartURL %s at launch and loading it afterwards failed: %v", url, err),
+		})
+		return
+	}
+	result.Warnings = append(result.Warnings, OpenWarning{
+		Code:    WarnStartURLNavigated,
+		Message: fmt.Sprintf("headless mode cannot open StartURL at launch; loaded %s afterwards", url),
+	})
+}
@@ -0,0 +1,71 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestOpenHeadlessStartURL(t *testing.T) {
+	// openServer records the open request; its ws URL does not speak CDP.
+	openServer := func(t *testing.T, config *OpenConfig) *Client {
+		t.Helper()
+		var url string
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(config)
+			w.Write(successResponse(OpenResult{Ws: "ws" + strings.TrimPrefix(url, "http") + "/devtools/browser/abc"}))
+		})
+		t.Cleanup(server.Close)
+		url = server.URL
+		return mustNew(t, server.URL)
+	}
+
+	t.Run("reports the dropped start URL", func(t *testing.T) {
+		var config OpenConfig
+		client := openServer(t, &config)
+		result, err := client.Open(context.Background(), "profile-1", &OpenOptions{
+			Headless: true,
+			StartURL: "https://example.com/",
+		})
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if !config.IgnoreDefaultUrls || config.NewPageUrl != "" {
+			t.Errorf("request = %+v, want IgnoreDefaultUrls and no NewPageUrl", config)
+		}
+		if len(result.Warnings) != 1 || result.Warnings[0].Code != WarnStartURLDropped ||
+			!strings.Contains(result.Warnings[0].Message, "https://example.com/") {
+			t.Errorf("Warnings = %v, want %s", result.Warnings, WarnStartURLDropped)
+		}
+	})
+
+	t.Run("KeepHeadlessURLs sends the options as given", func(t *testing.T) {
+		var config OpenConfig
+		client := openServer(t, &config)
+		result, err := client.Open(context.Background(), "profile-1", &OpenOptions{
+			Headless:         true,
+			StartURL:         "https://example.com/",
+			KeepHeadlessURLs: true,
+		})
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if config.IgnoreDefaultUrls || config.NewPageUrl != "https://example.com/" {
+			t.Errorf("request = %+v", config)
+		}
+		if len(result.Warnings) != 0 {
+			t.Errorf("Warnings = %v, want none", result.Warnings)
+		}
+	})
+
+	t.Run("no warning without a start URL", func(t *testing.T) {
+		var config OpenConfig
+		client := openServer(t, &config)
+		result, err := client.Open(context.Background(), "profile-1", &OpenOptions{Headless: true})
+		if err != nil || len(result.Warnings) != 0 {
+			t.Errorf("Open() = %+v, %v", result, err)
+		}
+	})
+}
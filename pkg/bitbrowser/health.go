@@ -0,0 +1,138 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// HealthProbes serves Kubernetes-style liveness and readiness endpoints for
+// a service built on a Client:
+//
+//	GET /healthz  200 if the BitBrowser API answers, 503 otherwise
+//	GET /readyz   200 if the API answers, the service is not draining and,
+//	              in Managed Mode, the port range has a free port
+//
+// Failing probes answer 503 with {"status": "unavailable", "error": "..."}.
+//
+// On shutdown, call Drain to fail readiness and close the browsers the
+// service opened.
+//
+// Example:
+//
+//	probes := bitbrowser.NewHealthProbes(client)
+//	mux.Handle("/healthz", probes)
+//	mux.Handle("/readyz", probes)
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+//	defer stop()
+//	<-ctx.Done()
+//	closed, err := probes.Drain(context.Background())
+type HealthProbes struct {
+	client   *Client
+	draining atomic.Bool
+}
+
+// NewHealthProbes creates health probes for client.
+func NewHealthProbes(client *Client) *HealthProbes {
+	return &HealthProbes{client: client}
+}
+
+// Live checks that the BitBrowser API is reachable.
+func (p *HealthProbes) Live(ctx context.Context) error {
+	return p.client.Health(ctx)
+}
+
+// Ready checks that the service can take new work: it is not draining, the
+// BitBrowser API is reachable and, in Managed Mode, a debugging port is free.
+func (p *HealthProbes) Ready(ctx context.Context) error {
+	if p.draining.Load() {
+		return ErrDraining
+	}
+	if err := p.client.Health(ctx); err != nil {
+		return err
+	}
+	if !p.client.portManager.IsActive() {
+		return nil
+	}
+
+	owned, err := p.client.managedBrowsers(ctx)
+	if err != nil {
+		return err
+	}
+	minPort, maxPort := p.client.portManager.GetConfig().Partition()
+	if len(owned) >= maxPort-minPort+1 {
+		return fmt.Errorf("bitbrowser: no free port in range [%d, %d]", minPort, maxPort)
+	}
+	return nil
+}
+
+// Drain marks the service as not ready and closes the browsers it owns,
+// i.e. those running on a port from the Managed Mode range. It returns how
+// many browsers were closed. In Native Mode no browsers are owned and Drain
+// only fails readiness.
+//
+// Drain closes every owned browser even if some fail, and returns the
+// failures joined.
+func (p *HealthProbes) Drain(ctx context.Context) (int, error) {
+	p.draining.Store(true)
+	if !p.client.portManager.IsActive() {
+		return 0, nil
+	}
+
+	owned, err := p.client.managedBrowsers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("bitbrowser: drain failed: %w", err)
+	}
+	closed := 0
+	var errs []error
+	for id := range owned {
+		if err := p.client.Close(ctx, id); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		closed++
+	}
+	return closed, errors.Join(errs...)
+}
+
+// Draining reports whether Drain has been called.
+func (p *HealthProbes) Draining() bool {
+	return p.draining.Load()
+}
+
+// ServeHTTP answers /readyz with Ready and any other path with Live.
+func (p *HealthProbes) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	check := p.Live
+	if r.URL.Path == "/readyz" {
+		check = p.Ready
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := check(r.Context()); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"status": "unavailable", "error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// managedBrowsers returns the open browsers whose debugging port lies in the
+// Managed Mode range (or this client's partition of it), keyed by profile ID.
+func (c *Client) managedBrowsers(ctx context.Context) (map[string]int, error) {
+	ports, err := c.GetPorts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	minPort, maxPort := c.portManager.GetConfig().Partition()
+	owned := make(map[string]int)
+	for id, port := range ports {
+		if port >= minPort && port <= maxPort {
+			owned[id] = port
+		}
+	}
+	return owned, nil
+}
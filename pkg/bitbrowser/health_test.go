@@ -0,0 +1,118 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// probeServer emulates BitBrowser with the given open browsers (profile ID -> port).
+func probeServer(t *testing.T, ports map[string]string) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var closed []string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/health":
+			w.Write(successResponse(nil))
+		case "/browser/ports":
+			w.Write(successResponse(ports))
+		case "/browser/close":
+			var req struct {
+				ID string `json:"id"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			closed = append(closed, req.ID)
+			w.Write(successResponse(nil))
+		}
+	})
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return closed
+	}
+}
+
+// probe requests path from probes and returns the status code.
+func probe(probes *HealthProbes, path string) int {
+	rec := httptest.NewRecorder()
+	probes.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code
+}
+
+func TestHealthProbes(t *testing.T) {
+	t.Run("healthy", func(t *testing.T) {
+		server, _ := probeServer(t, nil)
+		probes := NewHealthProbes(mustNew(t, server.URL))
+
+		if code := probe(probes, "/healthz"); code != http.StatusOK {
+			t.Errorf("/healthz = %d, want 200", code)
+		}
+		if code := probe(probes, "/readyz"); code != http.StatusOK {
+			t.Errorf("/readyz = %d, want 200", code)
+		}
+	})
+
+	t.Run("upstream down", func(t *testing.T) {
+		server, _ := probeServer(t, nil)
+		server.Close()
+		probes := NewHealthProbes(mustNew(t, server.URL))
+
+		if code := probe(probes, "/healthz"); code != http.StatusServiceUnavailable {
+			t.Errorf("/healthz = %d, want 503", code)
+		}
+		if code := probe(probes, "/readyz"); code != http.StatusServiceUnavailable {
+			t.Errorf("/readyz = %d, want 503", code)
+		}
+	})
+
+	t.Run("port range exhausted", func(t *testing.T) {
+		server, _ := probeServer(t, map[string]string{"p1": "50000", "p2": "50001", "other": "9222"})
+		probes := NewHealthProbes(mustNew(t, server.URL, WithPortRange(50000, 50001)))
+
+		if err := probes.Ready(context.Background()); err == nil {
+			t.Error("expected readiness failure with no free port")
+		}
+		if code := probe(probes, "/healthz"); code != http.StatusOK {
+			t.Errorf("/healthz = %d, want 200", code)
+		}
+	})
+
+	t.Run("drain closes owned browsers", func(t *testing.T) {
+		server, closed := probeServer(t, map[string]string{"p1": "50000", "other": "9222"})
+		probes := NewHealthProbes(mustNew(t, server.URL, WithPortRange(50000, 50010)))
+
+		n, err := probes.Drain(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := closed(); n != 1 || len(got) != 1 || got[0] != "p1" {
+			t.Errorf("closed %d: %v, want [p1]", n, got)
+		}
+		if err := probes.Ready(context.Background()); !errors.Is(err, ErrDraining) {
+			t.Errorf("expected ErrDraining, got %v", err)
+		}
+		if code := probe(probes, "/healthz"); code != http.StatusOK {
+			t.Errorf("/healthz = %d while draining, want 200", code)
+		}
+	})
+
+	t.Run("drain in native mode", func(t *testing.T) {
+		server, closed := probeServer(t, map[string]string{"p1": "50000"})
+		probes := NewHealthProbes(mustNew(t, server.URL))
+
+		if n, err := probes.Drain(context.Background()); n != 0 || err != nil || len(closed()) != 0 {
+			t.Errorf("Drain = %d, %v; closed %v", n, err, closed())
+		}
+		if !probes.Draining() {
+			t.Error("expected Draining after Drain")
+		}
+	})
+}
@@ -0,0 +1,376 @@
+package bitbrowser
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Default HealthScorer settings.
+const (
+	DefaultHealthScoreInterval = 15 * time.Minute
+	DefaultLoginMaxAge         = 7 * 24 * time.Hour
+	DefaultMaxFailures         = 5
+)
+
+// HealthSignal names an input of a profile's health score.
+type HealthSignal string
+
+// Health signals.
+const (
+	SignalCookies     HealthSignal = "cookies"     // Key cookies present and fresh
+	SignalLogin       HealthSignal = "login"       // Time since the last successful login
+	SignalProxy       HealthSignal = "proxy"       // Proxy check result
+	SignalFingerprint HealthSignal = "fingerprint" // Fingerprint self-test result
+	SignalFailures    HealthSignal = "failures"    // Consecutive failed runs
+)
+
+// DefaultHealthWeights are the signal weights used when
+// HealthScoreConfig.Weights is nil.
+var DefaultHealthWeights = map[HealthSignal]float64{
+	SignalCookies:     3,
+	SignalLogin:       2,
+	SignalProxy:       2,
+	SignalFingerprint: 1,
+	SignalFailures:    2,
+}
+
+// HealthScore is the health of a profile, for deciding which profiles to
+// use first and which to retire.
+type HealthScore struct {
+	ProfileID string `json:"profileId"`
+
+	// Score ranges from 0 (retire) to 100 (healthy). A profile without any
+	// known signal scores 100.
+	Score float64 `json:"score"`
+
+	// Components holds the score of each known signal, from 0 to 1.
+	Components map[HealthSignal]float64 `json:"components"`
+
+	Time time.Time `json:"time"` // When the score was computed
+}
+
+// profileSignals are the raw signals recorded for a profile.
+type profileSignals struct {
+	cookieAlerts   []CookieAlert
+	cookiesChecked bool
+	lastLogin      time.Time
+	proxyOK        *bool
+	fingerprintOK  *bool
+	failures       int
+	hasResult      bool
+}
+
+// HealthCollector refreshes signals of a profile, typically by checking
+// something and recording the outcome on s. HealthScorer.Run calls the
+// configured collectors before every recomputation.
+type HealthCollector func(ctx context.Context, s *HealthScorer, id string) error
+
+// CookieHealthCollector records the key cookie alerts of watcher.Check as
+// the cookie signal. The profile must be running. Webhook failures are
+// returned after the alerts were recorded.
+func CookieHealthCollector(watcher *CookieWatcher) HealthCollector {
+	return func(ctx context.Context, s *HealthScorer, id string) error {
+		alerts, err := watcher.Check(ctx, id)
+		if err != nil && alerts == nil {
+			return err
+		}
+		s.RecordCookies(id, alerts)
+		return err
+	}
+}
+
+// ProxyHealthCollector checks the proxy configured for a profile with
+// CheckProxy and records the outcome as the proxy signal. Profiles without
+// a proxy are skipped.
+func ProxyHealthCollector(client *Client) HealthCollector {
+	return func(ctx context.Context, s *HealthScorer, id string) error {
+		detail, err := client.GetProfileDetail(ctx, id)
+		if err != nil {
+			return err
+		}
+		if detail.Host == "" {
+			return nil
+		}
+		result, err := client.CheckProxy(ctx, proxyCheckRequest(detail))
+		if err != nil {
+			return err // Could not check; keep the last result
+		}
+		s.RecordProxy(id, result.Success)
+		return nil
+	}
+}
+
+// HealthScoreConfig configures a HealthScorer.
+type HealthScoreConfig struct {
+	// Weights are the relative weights of the signals
+	// (default: DefaultHealthWeights). Signals with no or zero weight are
+	// ignored.
+	Weights map[HealthSignal]float64
+
+	// LoginMaxAge is the time after a successful login at which the login
+	// signal reaches 0; it falls linearly until then
+	// (default: DefaultLoginMaxAge).
+	LoginMaxAge time.Duration
+
+	// MaxFailures is the number of consecutive failures at which the
+	// failure signal reaches 0 (default: DefaultMaxFailures).
+	MaxFailures int
+
+	// Interval is the time between recomputations in Run
+	// (default: DefaultHealthScoreInterval).
+	Interval time.Duration
+
+	// Collectors refresh signals before every recomputation in Run, e.g.
+	// CookieHealthCollector and ProxyHealthCollector.
+	Collectors []HealthCollector
+
+	// OnError is called by Run when a collector fails.
+	OnError func(id string, err error)
+
+	// Clock is the time source (default: SystemClock).
+	Clock Clock
+}
+
+// HealthScorer combines signals about profiles (cookie freshness, last
+// successful login, proxy status, fingerprint self-test and failure counts)
+// into a HealthScore per profile.
+//
+// Signals are recorded with the Record methods or by collectors; scores are
+// computed by Recompute, which Run calls on a schedule. Signals that were
+// never recorded for a profile are left out of its score.
+//
+// A HealthScorer is safe for concurrent use.
+//
+// Example:
+//
+//	scorer := bitbrowser.NewHealthScorer(bitbrowser.HealthScoreConfig{
+//	    Collectors: []bitbrowser.HealthCollector{bitbrowser.ProxyHealthCollector(client)},
+//	})
+//	go scorer.Run(ctx, ids...)
+//
+//	// After each run
+//	scorer.RecordResult(id, err)
+//
+//	for _, s := range scorer.Below(40) {
+//	    log.Printf("retire %s (score %.0f)", s.ProfileID, s.Score)
+//	}
+type HealthScorer struct {
+	config HealthScoreConfig
+
+	mu      sync.Mutex
+	signals map[string]*profileSignals
+	scores  map[string]HealthScore
+}
+
+// NewHealthScorer creates a HealthScorer.
+func NewHealthScorer(config HealthScoreConfig) *HealthScorer {
+	if config.Weights == nil {
+		config.Weights = DefaultHealthWeights
+	}
+	if config.LoginMaxAge <= 0 {
+		config.LoginMaxAge = DefaultLoginMaxAge
+	}
+	if config.MaxFailures <= 0 {
+		config.MaxFailures = DefaultMaxFailures
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultHealthScoreInterval
+	}
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+	return &HealthScorer{
+		config:  config,
+		signals: make(map[string]*profileSignals),
+		scores:  make(map[string]HealthScore),
+	}
+}
+
+// RecordCookies records the result of a key cookie check, as returned by
+// CookieWatcher.Check. No alerts means all key cookies are fresh.
+func (s *HealthScorer) RecordCookies(id string, alerts []CookieAlert) {
+	s.update(id, func(p *profileSignals) {
+		p.cookieAlerts = slices.Clone(alerts)
+		p.cookiesChecked = true
+	})
+}
+
+// RecordLogin records a successful login now.
+func (s *HealthScorer) RecordLogin(id string) {
+	now := s.config.Clock.Now()
+	s.update(id, func(p *profileSignals) { p.lastLogin = now })
+}
+
+// RecordProxy records whether the profile's proxy works.
+func (s *HealthScorer) RecordProxy(id string, ok bool) {
+	s.update(id, func(p *profileSignals) { p.proxyOK = &ok })
+}
+
+// RecordFingerprint records whether the profile passed a fingerprint
+// self-test, e.g. a check on a fingerprinting test page.
+func (s *HealthScorer) RecordFingerprint(id string, ok bool) {
+	s.update(id, func(p *profileSignals) { p.fingerprintOK = &ok })
+}
+
+// RecordResult records the outcome of a run with the profile. A failure
+// increases its consecutive failure count; a success resets it.
+func (s *HealthScorer) RecordResult(id string, err error) {
+	s.update(id, func(p *profileSignals) {
+		p.hasResult = true
+		if err != nil {
+			p.failures++
+		} else {
+			p.failures = 0
+		}
+	})
+}
+
+// Forget drops the signals and score of a profile, e.g. after it was
+// deleted.
+func (s *HealthScorer) Forget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.signals, id)
+	delete(s.scores, id)
+}
+
+// update applies fn to the signals of a profile.
+func (s *HealthScorer) update(id string, fn func(*profileSignals)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.signals[id]
+	if !ok {
+		p = &profileSignals{}
+		s.signals[id] = p
+	}
+	fn(p)
+}
+
+// Recompute computes the scores of all profiles with recorded signals from
+// their current signals.
+func (s *HealthScorer) Recompute() {
+	now := s.config.Clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, p := range s.signals {
+		s.scores[id] = s.score(id, p, now)
+	}
+}
+
+// score computes the health score of a profile at now.
+func (s *HealthScorer) score(id string, p *profileSignals, now time.Time) HealthScore {
+	components := make(map[HealthSignal]float64)
+	if p.cookiesChecked {
+		components[SignalCookies] = cookieComponent(p.cookieAlerts)
+	}
+	if !p.lastLogin.IsZero() {
+		age := now.Sub(p.lastLogin)
+		components[SignalLogin] = clamp01(1 - float64(age)/float64(s.config.LoginMaxAge))
+	}
+	if p.proxyOK != nil {
+		components[SignalProxy] = boolComponent(*p.proxyOK)
+	}
+	if p.fingerprintOK != nil {
+		components[SignalFingerprint] = boolComponent(*p.fingerprintOK)
+	}
+	if p.hasResult {
+		components[SignalFailures] = clamp01(1 - float64(p.failures)/float64(s.config.MaxFailures))
+	}
+
+	var sum, weights float64
+	for signal, c := range components {
+		if w := s.config.Weights[signal]; w > 0 {
+			sum += w * c
+			weights += w
+		}
+	}
+	score := 100.0
+	if weights > 0 {
+		score = 100 * sum / weights
+	}
+	return HealthScore{ProfileID: id, Score: score, Components: components, Time: now}
+}
+
+// cookieComponent is 0 if a key cookie is missing or expired, 0.5 if one
+// expires soon and 1 otherwise.
+func cookieComponent(alerts []CookieAlert) float64 {
+	c := 1.0
+	for _, a := range alerts {
+		if a.Reason == CookieExpiring {
+			c = min(c, 0.5)
+		} else {
+			return 0
+		}
+	}
+	return c
+}
+
+func boolComponent(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+func clamp01(v float64) float64 {
+	return max(0, min(1, v))
+}
+
+// Run runs the collectors for the profiles and recomputes all scores every
+// Interval, starting immediately, until ctx is done and then returns
+// ctx.Err(). Collector failures are reported to OnError.
+func (s *HealthScorer) Run(ctx context.Context, ids ...string) error {
+	for {
+		for _, id := range ids {
+			for _, collect := range s.config.Collectors {
+				if err := collect(ctx, s, id); err != nil && s.config.OnError != nil && ctx.Err() == nil {
+					s.config.OnError(id, fmt.Errorf("bitbrowser: health signal collection failed: %w", err))
+				}
+			}
+		}
+		s.Recompute()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.config.Clock.After(s.config.Interval):
+		}
+	}
+}
+
+// Score returns the last computed score of a profile, or false if none was
+// computed yet.
+func (s *HealthScorer) Score(id string) (HealthScore, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	score, ok := s.scores[id]
+	return score, ok
+}
+
+// Scores returns the last computed scores of all profiles, healthiest
+// first, e.g. to pick the profiles to use next.
+func (s *HealthScorer) Scores() []HealthScore {
+	s.mu.Lock()
+	scores := make([]HealthScore, 0, len(s.scores))
+	for _, score := range s.scores {
+		scores = append(scores, score)
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(scores, func(a, b HealthScore) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.ProfileID, b.ProfileID))
+	})
+	return scores
+}
+
+// Below returns the profiles scoring below threshold, least healthy first,
+// e.g. to retire them.
+func (s *HealthScorer) Below(threshold float64) []HealthScore {
+	scores := s.Scores()
+	below := slices.DeleteFunc(scores, func(score HealthScore) bool { return score.Score >= threshold })
+	slices.Reverse(below)
+	return below
+}
@@ -0,0 +1,185 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHealthScorer(t *testing.T) {
+	t.Run("no signals scores 100", func(t *testing.T) {
+		s := NewHealthScorer(HealthScoreConfig{})
+		if _, ok := s.Score("p1"); ok {
+			t.Error("Score() before any signal should report false")
+		}
+		s.RecordResult("p1", nil)
+		s.Forget("p1")
+		s.RecordCookies("p2", nil)
+		s.Recompute()
+		if _, ok := s.Score("p1"); ok {
+			t.Error("forgotten profile still has a score")
+		}
+		if score, _ := s.Score("p2"); score.Score != 100 {
+			t.Errorf("Score = %v, want 100", score.Score)
+		}
+	})
+
+	t.Run("weighted average of known signals", func(t *testing.T) {
+		clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		s := NewHealthScorer(HealthScoreConfig{Clock: clock, LoginMaxAge: 4 * 24 * time.Hour, MaxFailures: 4})
+
+		s.RecordCookies("p1", []CookieAlert{{Reason: CookieExpiring}})
+		s.RecordLogin("p1")
+		s.RecordProxy("p1", true)
+		s.RecordFingerprint("p1", false)
+		s.RecordResult("p1", errors.New("captcha"))
+		clock.Advance(24 * time.Hour)
+		s.Recompute()
+
+		score, ok := s.Score("p1")
+		if !ok {
+			t.Fatal("Score() reported no score")
+		}
+		want := map[HealthSignal]float64{
+			SignalCookies:     0.5,
+			SignalLogin:       0.75,
+			SignalProxy:       1,
+			SignalFingerprint: 0,
+			SignalFailures:    0.75,
+		}
+		for signal, v := range want {
+			if score.Components[signal] != v {
+				t.Errorf("Components[%s] = %v, want %v", signal, score.Components[signal], v)
+			}
+		}
+		// (3*0.5 + 2*0.75 + 2*1 + 1*0 + 2*0.75) / 10
+		if math.Abs(score.Score-65) > 1e-9 {
+			t.Errorf("Score = %v, want 65", score.Score)
+		}
+		if !score.Time.Equal(clock.Now()) {
+			t.Errorf("Time = %v, want %v", score.Time, clock.Now())
+		}
+	})
+
+	t.Run("failures reset on success", func(t *testing.T) {
+		s := NewHealthScorer(HealthScoreConfig{Weights: map[HealthSignal]float64{SignalFailures: 1}})
+		for range 10 {
+			s.RecordResult("p1", errors.New("failed"))
+		}
+		s.RecordCookies("p1", []CookieAlert{{Reason: CookieMissing}}) // Not weighted
+		s.Recompute()
+		if score, _ := s.Score("p1"); score.Score != 0 {
+			t.Errorf("Score after failures = %v, want 0", score.Score)
+		}
+		s.RecordResult("p1", nil)
+		s.Recompute()
+		if score, _ := s.Score("p1"); score.Score != 100 {
+			t.Errorf("Score after success = %v, want 100", score.Score)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		s := NewHealthScorer(HealthScoreConfig{})
+		s.RecordProxy("good", true)
+		s.RecordProxy("bad", false)
+		s.RecordCookies("mid", []CookieAlert{{Reason: CookieExpiring}})
+		s.Recompute()
+
+		var ranked []string
+		for _, score := range s.Scores() {
+			ranked = append(ranked, score.ProfileID)
+		}
+		if len(ranked) != 3 || ranked[0] != "good" || ranked[1] != "mid" || ranked[2] != "bad" {
+			t.Errorf("Scores() order = %v", ranked)
+		}
+		below := s.Below(60)
+		if len(below) != 2 || below[0].ProfileID != "bad" || below[1].ProfileID != "mid" {
+			t.Errorf("Below(60) = %+v", below)
+		}
+	})
+}
+
+func TestHealthScorerRun(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	var collected []string
+	var errs []error
+	s := NewHealthScorer(HealthScoreConfig{
+		Clock: clock,
+		Collectors: []HealthCollector{
+			func(ctx context.Context, s *HealthScorer, id string) error {
+				collected = append(collected, id)
+				s.RecordProxy(id, id == "p1")
+				return nil
+			},
+			func(ctx context.Context, s *HealthScorer, id string) error {
+				return errors.New("unavailable")
+			},
+		},
+		OnError: func(id string, err error) { errs = append(errs, err) },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx, "p1", "p2") }()
+
+	clock.BlockUntil(1)
+	if score, _ := s.Score("p2"); score.Score != 0 {
+		t.Errorf("p2 score = %v, want 0", score.Score)
+	}
+	clock.Advance(DefaultHealthScoreInterval)
+	clock.BlockUntil(1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+	if len(collected) != 4 || len(errs) != 4 {
+		t.Errorf("collected %v with %d errors, want 2 rounds for 2 profiles", collected, len(errs))
+	}
+}
+
+func TestProxyHealthCollector(t *testing.T) {
+	var checked ProxyCheckRequest
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/browser/detail":
+			var req struct{ ID string }
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.ID == "direct" {
+				w.Write(successResponse(map[string]any{"id": req.ID}))
+				return
+			}
+			w.Write(successResponse(map[string]any{"id": req.ID, "proxyType": "socks5", "host": "10.0.0.9", "port": 1080}))
+		case "/checkagent":
+			json.NewDecoder(r.Body).Decode(&checked)
+			w.Write(successResponse(map[string]any{"success": false}))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+	client := mustNew(t, server.URL)
+
+	s := NewHealthScorer(HealthScoreConfig{})
+	collect := ProxyHealthCollector(client)
+	if err := collect(context.Background(), s, "p1"); err != nil {
+		t.Fatalf("collect(p1) failed: %v", err)
+	}
+	if err := collect(context.Background(), s, "direct"); err != nil {
+		t.Fatalf("collect(direct) failed: %v", err)
+	}
+	s.Recompute()
+
+	if checked.Host != "10.0.0.9" || checked.Port != 1080 || checked.ProxyType != "socks5" {
+		t.Errorf("checked proxy = %+v", checked)
+	}
+	if score, _ := s.Score("p1"); score.Components[SignalProxy] != 0 {
+		t.Errorf("p1 proxy component = %v, want 0", score.Components[SignalProxy])
+	}
+	if _, ok := s.Score("direct"); ok {
+		t.Error("profile without proxy got a score")
+	}
+}
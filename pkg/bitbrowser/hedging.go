@@ -0,0 +1,164 @@
+package bitbrowser
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Default hedging settings.
+const (
+	defaultHedgeInitialDelay = time.Second
+	defaultHedgeMinDelay     = 50 * time.Millisecond
+	hedgeWindowSize          = 100 // Latency samples kept per endpoint
+	hedgeMinSamples          = 10  // Samples needed before P95 is trusted
+)
+
+// hedgeable reports whether requests to path may be hedged.
+func hedgeable(path string) bool {
+	return endpoints[path].hedge
+}
+
+// HedgingConfig configures hedged requests for idempotent read endpoints
+// such as GetPorts and GetProfileDetail.
+//
+// When a request has not completed after the hedge delay, a second identical
+// request is sent and whichever response arrives first is used. This smooths
+// out the occasional multi-second stalls of the local API at the cost of a
+// few extra requests.
+type HedgingConfig struct {
+	// Delay is a fixed delay before sending a hedge.
+	// If zero, the delay tracks the P95 latency of recent requests to the
+	// same endpoint.
+	Delay time.Duration
+
+	// InitialDelay is used until enough latency samples have been collected
+	// (default: 1s). Ignored when Delay is set.
+	InitialDelay time.Duration
+
+	// MinDelay is the lower bound for the adaptive delay (default: 50ms).
+	MinDelay time.Duration
+
+	// MaxHedges is the number of extra requests that may be sent for a
+	// single call (default: 1).
+	MaxHedges int
+}
+
+// hedger sends hedged requests and tracks per-endpoint latency.
+type hedger struct {
+	config HedgingConfig
+
+	mu        sync.Mutex
+	latencies map[string][]time.Duration // Ring buffers of recent latencies
+	next      map[string]int             // Next write position per endpoint
+}
+
+// newHedger creates a hedger, applying defaults to unset fields.
+func newHedger(config HedgingConfig) *hedger {
+	if config.InitialDelay <= 0 {
+		config.InitialDelay = defaultHedgeInitialDelay
+	}
+	if config.MinDelay <= 0 {
+		config.MinDelay = defaultHedgeMinDelay
+	}
+	if config.MaxHedges <= 0 {
+		config.MaxHedges = 1
+	}
+	return &hedger{
+		config:    config,
+		latencies: make(map[string][]time.Duration),
+		next:      make(map[string]int),
+	}
+}
+
+// delay returns how long to wait before hedging a request to path.
+func (h *hedger) delay(path string) time.Duration {
+	if h.config.Delay > 0 {
+		return h.config.Delay
+	}
+
+	h.mu.Lock()
+	samples := slices.Clone(h.latencies[path])
+	h.mu.Unlock()
+
+	if len(samples) < hedgeMinSamples {
+		return h.config.InitialDelay
+	}
+	slices.Sort(samples)
+	p95 := samples[(len(samples)*95-1)/100]
+	return max(p95, h.config.MinDelay)
+}
+
+// observe records the latency of a successful request to path.
+func (h *hedger) observe(path string, d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buf := h.latencies[path]
+	if len(buf) < hedgeWindowSize {
+		h.latencies[path] = append(buf, d)
+		return
+	}
+	i := h.next[path]
+	buf[i] = d
+	h.next[path] = (i + 1) % hedgeWindowSize
+}
+
+// hedgeResult is the outcome of a single hedged attempt.
+type hedgeResult struct {
+	body    []byte
+	err     error
+	latency time.Duration
+}
+
+// executeHedged performs a request, sending up to MaxHedges extra copies
+// if it is slow to complete. The first successful response wins and the
+// remaining requests are canceled. If every request fails, the last error
+// is returned.
+func (c *Client) executeHedged(ctx context.Context, path string, jsonData []byte, respBody any) error {
+	h := c.hedging
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult, h.config.MaxHedges+1)
+	send := func() {
+		start := time.Now()
+		body, err := c.executeRaw(ctx, path, jsonData)
+		results <- hedgeResult{body: body, err: err, latency: time.Since(start)}
+	}
+
+	go send()
+	inFlight, sent := 1, 1
+
+	timer := time.NewTimer(h.delay(path))
+	defer timer.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case <-timer.C:
+			if sent <= h.config.MaxHedges {
+				if c.logger != nil {
+					c.logger.DebugContext(ctx, "bitbrowser: hedging slow request",
+						withRequestID(ctx, []any{"path", path, "attempt", sent + 1})...)
+				}
+				go send()
+				inFlight++
+				sent++
+				timer.Reset(h.delay(path))
+			}
+		case res := <-results:
+			inFlight--
+			if res.err == nil {
+				h.observe(path, res.latency)
+				return decodeResponse(path, res.body, respBody)
+			}
+			lastErr = res.err
+			// Don't wait for a hedge that was never sent; let the retryer decide
+			if inFlight == 0 {
+				return lastErr
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package bitbrowser
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedging(t *testing.T) {
+	t.Run("hedge wins over stalled request", func(t *testing.T) {
+		var requests atomic.Int32
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			io.ReadAll(r.Body) // Lets the server notice the client canceling
+			if requests.Add(1) == 1 {
+				// Stall until the hedge wins and the request is canceled
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+				return
+			}
+			w.Write(successResponse(map[string]string{"profile-1": "9222"}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithHedging(HedgingConfig{Delay: 20 * time.Millisecond}))
+
+		start := time.Now()
+		ports, err := client.GetPorts(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ports["profile-1"] != 9222 {
+			t.Errorf("unexpected ports: %v", ports)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("hedged request took %v", elapsed)
+		}
+		if got := requests.Load(); got != 2 {
+			t.Errorf("requests = %d, want 2", got)
+		}
+	})
+
+	t.Run("fast requests are not hedged", func(t *testing.T) {
+		var requests atomic.Int32
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.Write(successResponse(map[string]string{}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithHedging(HedgingConfig{Delay: time.Second}))
+		if _, err := client.GetPorts(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("write endpoints are never hedged", func(t *testing.T) {
+		var requests atomic.Int32
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			time.Sleep(100 * time.Millisecond)
+			w.Write(successResponse(nil))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithHedging(HedgingConfig{Delay: 10 * time.Millisecond}))
+		if err := client.Close(context.Background(), "profile-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("returns error when only request fails", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithHedging(HedgingConfig{Delay: time.Second}))
+		start := time.Now()
+		if _, err := client.GetPorts(context.Background()); err == nil {
+			t.Error("expected error")
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("failure waited for hedge delay: %v", elapsed)
+		}
+	})
+}
+
+func TestHedgerDelay(t *testing.T) {
+	t.Run("uses initial delay without samples", func(t *testing.T) {
+		h := newHedger(HedgingConfig{InitialDelay: 300 * time.Millisecond})
+		if d := h.delay("/browser/ports"); d != 300*time.Millisecond {
+			t.Errorf("delay = %v, want 300ms", d)
+		}
+	})
+
+	t.Run("tracks P95 latency", func(t *testing.T) {
+		h := newHedger(HedgingConfig{})
+		for i := 1; i <= 100; i++ {
+			h.observe("/browser/ports", time.Duration(i)*time.Millisecond)
+		}
+		if d := h.delay("/browser/ports"); d != 95*time.Millisecond {
+			t.Errorf("delay = %v, want 95ms", d)
+		}
+		// Other endpoints keep their own samples
+		if d := h.delay("/browser/detail"); d != defaultHedgeInitialDelay {
+			t.Errorf("delay = %v, want %v", d, defaultHedgeInitialDelay)
+		}
+	})
+
+	t.Run("keeps a sliding window", func(t *testing.T) {
+		h := newHedger(HedgingConfig{})
+		for i := 0; i < hedgeWindowSize; i++ {
+			h.observe("/browser/ports", time.Second)
+		}
+		for i := 0; i < hedgeWindowSize; i++ {
+			h.observe("/browser/ports", 200*time.Millisecond)
+		}
+		if d := h.delay("/browser/ports"); d != 200*time.Millisecond {
+			t.Errorf("delay = %v, want 200ms", d)
+		}
+	})
+
+	t.Run("respects minimum delay", func(t *testing.T) {
+		h := newHedger(HedgingConfig{MinDelay: 100 * time.Millisecond})
+		for i := 0; i < hedgeMinSamples; i++ {
+			h.observe("/browser/ports", time.Millisecond)
+		}
+		if d := h.delay("/browser/ports"); d != 100*time.Millisecond {
+			t.Errorf("delay = %v, want 100ms", d)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// importServer records created profiles and refuses those named "taken".
+func importServer(t *testing.T, created *[]ProfileConfig) *Client {
+	t.Helper()
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/browser/update":
+			var config ProfileConfig
+			json.NewDecoder(r.Body).Decode(&config)
+			if config.Name == "taken" {
+				w.Write(errorResponse("duplicate username"))
+				return
+			}
+			*created = append(*created, config)
+			w.Write(successResponse(map[string]string{"id": "id-" + config.Name}))
+		case "/utils/readexcel":
+			w.Write(successResponse([]map[string]any{
+				{"Name": "x1", "Proxy": "10.0.0.1:8080", "Seq": 7},
+				{"Name": "x2", "Proxy": ""},
+			}))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+	t.Cleanup(server.Close)
+	return mustNew(t, server.URL)
+}
+
+func TestImportProfilesFromSpreadsheet(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "profiles.csv")
+		data := "\ufeffName,Proxy,Cookies,UA\n" +
+			"alpha,socks5://u:p@10.0.0.2:1080,,Mozilla/5.0 Test\n" +
+			"beta,10.0.0.3:3128:bob:secret,\"[{\"\"name\"\":\"\"sid\"\",\"\"value\"\":\"\"1\"\",\"\"domain\"\":\"\".example.com\"\"}]\",\n" +
+			",,,\n" +
+			"gamma,ftp://10.0.0.4:21,,\n" +
+			"delta,,not json,\n" +
+			"taken,,,\n"
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		var created []ProfileConfig
+		client := importServer(t, &created)
+		mapping := DefaultSpreadsheetMapping
+		mapping.Base.GroupID = "g1"
+		report, err := client.ImportProfilesFromSpreadsheet(context.Background(), path, mapping)
+		if err != nil {
+			t.Fatalf("ImportProfilesFromSpreadsheet failed: %v", err)
+		}
+
+		if len(report.Created) != 2 || report.Created[0] != (ImportedProfile{Row: 2, ID: "id-alpha", Name: "alpha"}) || report.Created[1].Row != 3 {
+			t.Errorf("Created = %+v", report.Created)
+		}
+		wantRejected := []RejectedRow{{Row: 5, Column: "proxy"}, {Row: 6, Column: "cookies"}, {Row: 7}}
+		if len(report.Rejected) != len(wantRejected) {
+			t.Fatalf("Rejected = %+v", report.Rejected)
+		}
+		for i, want := range wantRejected {
+			got := report.Rejected[i]
+			if got.Row != want.Row || got.Column != want.Column || got.Reason == "" {
+				t.Errorf("Rejected[%d] = %+v, want row %d column %q", i, got, want.Row, want.Column)
+			}
+		}
+
+		alpha, beta := created[0], created[1]
+		if alpha.GroupID != "g1" || alpha.ProxyMethod != ProxyMethodCustom || alpha.ProxyType != "socks5" ||
+			alpha.Host != "10.0.0.2" || alpha.Port != 1080 || alpha.ProxyUserName != "u" || alpha.ProxyPassword != "p" {
+			t.Errorf("alpha = %+v", alpha)
+		}
+		if alpha.BrowserFingerPrint == nil || alpha.BrowserFingerPrint.UserAgent != "Mozilla/5.0 Test" {
+			t.Errorf("alpha fingerprint = %+v", alpha.BrowserFingerPrint)
+		}
+		if beta.ProxyType != "http" || beta.ProxyUserName != "bob" || beta.ProxyPassword != "secret" {
+			t.Errorf("beta proxy = %+v", beta)
+		}
+		var cookies []Cookie
+		if err := json.Unmarshal([]byte(beta.Cookie), &cookies); err != nil || len(cookies) != 1 || cookies[0].Name != "sid" {
+			t.Errorf("beta cookies = %q", beta.Cookie)
+		}
+	})
+
+	t.Run("excel via ReadExcel", func(t *testing.T) {
+		var created []ProfileConfig
+		client := importServer(t, &created)
+		report, err := client.ImportProfilesFromSpreadsheet(context.Background(), `C:\data\profiles.xlsx`, DefaultSpreadsheetMapping)
+		if err != nil {
+			t.Fatalf("ImportProfilesFromSpreadsheet failed: %v", err)
+		}
+		if len(report.Created) != 2 || len(report.Rejected) != 0 {
+			t.Errorf("report = %+v", report)
+		}
+		if created[0].Host != "10.0.0.1" || created[0].Port != 8080 || created[1].Host != "" {
+			t.Errorf("created = %+v", created)
+		}
+	})
+
+	t.Run("no mapped column", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "profiles.csv")
+		os.WriteFile(path, []byte("foo,bar\n1,2\n"), 0o644)
+		var created []ProfileConfig
+		_, err := importServer(t, &created).ImportProfilesFromSpreadsheet(context.Background(), path, DefaultSpreadsheetMapping)
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("err = %v, want ErrValidation", err)
+		}
+	})
+
+	t.Run("quota", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(map[string]any{"list": []any{}, "totalNum": 9}))
+		})
+		defer server.Close()
+		path := filepath.Join(t.TempDir(), "profiles.csv")
+		os.WriteFile(path, []byte("name\na\nb\n"), 0o644)
+		client := mustNew(t, server.URL, WithProfileLimit(10))
+		if _, err := client.ImportProfilesFromSpreadsheet(context.Background(), path, DefaultSpreadsheetMapping); !errors.Is(err, ErrQuotaExceeded) {
+			t.Errorf("err = %v, want ErrQuotaExceeded", err)
+		}
+	})
+}
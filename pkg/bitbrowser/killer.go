@@ -0,0 +1,128 @@
+package bitbrowser
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ProcessKiller terminates a browser process on the machine running BitBrowser.
+// It is used by KillBrowserProcess and, as a last resort, by CloseAndWait.
+type ProcessKiller interface {
+	Kill(ctx context.Context, pid int) error
+}
+
+// ProcessKillerFunc adapts a function to the ProcessKiller interface.
+type ProcessKillerFunc func(ctx context.Context, pid int) error
+
+// Kill calls f(ctx, pid).
+func (f ProcessKillerFunc) Kill(ctx context.Context, pid int) error {
+	return f(ctx, pid)
+}
+
+// LocalProcessKiller kills processes on this machine with kill -9, or
+// taskkill /F /T on Windows so the browser's child processes go too. It is
+// only correct when BitBrowser runs on the same host as the client.
+var LocalProcessKiller ProcessKiller = ProcessKillerFunc(func(ctx context.Context, pid int) error {
+	args := killCommand(runtime.GOOS == "windows", pid)
+	return runKill(exec.CommandContext(ctx, args[0], args[1:]...))
+})
+
+// SSHProcessKiller kills processes on a remote BitBrowser host by running
+// kill (or taskkill) over ssh. Authentication is left to ssh, so keys and
+// host settings come from Args or ~/.ssh/config.
+//
+// Example:
+//
+//	killer := &bitbrowser.SSHProcessKiller{
+//	    Host:    "admin@10.0.0.5",
+//	    Args:    []string{"-i", "/keys/bitbrowser"},
+//	    Windows: true,
+//	}
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithProcessKiller(killer))
+type SSHProcessKiller struct {
+	Host    string   // ssh destination, e.g. "admin@10.0.0.5"
+	Args    []string // Extra ssh arguments placed before Host
+	Windows bool     // Host runs Windows: use taskkill instead of kill
+	Command string   // ssh binary (default: "ssh")
+}
+
+// Kill runs the kill command for pid on s.Host.
+func (s *SSHProcessKiller) Kill(ctx context.Context, pid int) error {
+	if s.Host == "" {
+		return NewValidationError("Host", "ssh host is required")
+	}
+	command := s.Command
+	if command == "" {
+		command = "ssh"
+	}
+	args := append(append([]string{}, s.Args...), s.Host, strings.Join(killCommand(s.Windows, pid), " "))
+	return runKill(exec.CommandContext(ctx, command, args...))
+}
+
+// killCommand returns the command line that force-kills pid and its children.
+func killCommand(windows bool, pid int) []string {
+	if windows {
+		return []string{"taskkill", "/F", "/T", "/PID", strconv.Itoa(pid)}
+	}
+	return []string{"kill", "-9", strconv.Itoa(pid)}
+}
+
+// runKill runs cmd, including its output in the error if it fails.
+func runKill(cmd *exec.Cmd) error {
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", cmd.Args[0], err, msg)
+		}
+		return fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	return nil
+}
+
+// WithProcessKiller sets how browser processes are killed on the BitBrowser
+// host, for KillBrowserProcess and for CloseAndWait's last resort. Without
+// it, KillBrowserProcess fails and CloseAndWait never kills.
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithProcessKiller(bitbrowser.LocalProcessKiller))
+func WithProcessKiller(killer ProcessKiller) ClientOption {
+	return func(c *Client) {
+		c.processKiller = killer
+	}
+}
+
+// KillBrowserProcess force-kills the browser process of a profile, for
+// browsers that ignore Close. The PID comes from GetAlivePIDs and the kill is
+// done by the ProcessKiller set with WithProcessKiller.
+//
+// It returns ErrBrowserNotRunning if the profile has no live process.
+func (c *Client) KillBrowserProcess(ctx context.Context, id string) error {
+	if c.processKiller == nil {
+		return NewValidationError("ProcessKiller", "no process killer configured; use WithProcessKiller")
+	}
+	pid, err := c.alivePID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if pid == 0 {
+		return ErrBrowserNotRunning
+	}
+	return c.killProcess(ctx, id, pid)
+}
+
+// killProcess kills pid with the configured ProcessKiller.
+func (c *Client) killProcess(ctx context.Context, id string, pid int) error {
+	if c.logger != nil {
+		c.logger.WarnContext(ctx, "bitbrowser: killing browser process", "id", id, "pid", pid)
+	}
+	if err := c.processKiller.Kill(ctx, pid); err != nil {
+		return fmt.Errorf("bitbrowser: kill process %d: %w", pid, err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestKillBrowserProcess(t *testing.T) {
+	alive := func(pids map[string]int) *http.ServeMux {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/browser/pids/alive", func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(pids))
+		})
+		return mux
+	}
+
+	t.Run("kills the profile's process", func(t *testing.T) {
+		server := mockServer(alive(map[string]int{"profile-1": 4242}).ServeHTTP)
+		defer server.Close()
+
+		var killed int
+		client := mustNew(t, server.URL, WithProcessKiller(ProcessKillerFunc(func(ctx context.Context, pid int) error {
+			killed = pid
+			return nil
+		})))
+		if err := client.KillBrowserProcess(context.Background(), "profile-1"); err != nil {
+			t.Fatalf("KillBrowserProcess() error = %v", err)
+		}
+		if killed != 4242 {
+			t.Errorf("killed = %d, want 4242", killed)
+		}
+	})
+
+	t.Run("not running", func(t *testing.T) {
+		server := mockServer(alive(map[string]int{}).ServeHTTP)
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithProcessKiller(ProcessKillerFunc(func(context.Context, int) error {
+			t.Error("Kill called for a stopped browser")
+			return nil
+		})))
+		err := client.KillBrowserProcess(context.Background(), "profile-1")
+		if !errors.Is(err, ErrBrowserNotRunning) {
+			t.Errorf("err = %v, want ErrBrowserNotRunning", err)
+		}
+	})
+
+	t.Run("requires a killer", func(t *testing.T) {
+		client := mustNew(t, "http://127.0.0.1:54345")
+		err := client.KillBrowserProcess(context.Background(), "profile-1")
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("err = %v, want ErrValidation", err)
+		}
+	})
+}
+
+func TestKillCommand(t *testing.T) {
+	if got, want := killCommand(false, 42), []string{"kill", "-9", "42"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unix = %v, want %v", got, want)
+	}
+	if got, want := killCommand(true, 42), []string{"taskkill", "/F", "/T", "/PID", "42"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("windows = %v, want %v", got, want)
+	}
+}
+
+func TestSSHProcessKiller(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX commands")
+	}
+
+	t.Run("success", func(t *testing.T) {
+		killer := &SSHProcessKiller{Host: "admin@host", Command: "true"}
+		if err := killer.Kill(context.Background(), 42); err != nil {
+			t.Errorf("Kill() error = %v", err)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		killer := &SSHProcessKiller{Host: "admin@host", Command: "false"}
+		if err := killer.Kill(context.Background(), 42); err == nil {
+			t.Error("Kill() error = nil, want error")
+		}
+	})
+
+	t.Run("requires a host", func(t *testing.T) {
+		err := (&SSHProcessKiller{}).Kill(context.Background(), 42)
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("err = %v, want ErrValidation", err)
+		}
+	})
+}
+
+func TestLocalProcessKiller(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX commands")
+	}
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+
+	if err := LocalProcessKiller.Kill(context.Background(), cmd.Process.Pid); err != nil {
+		t.Fatalf("Kill() error = %v", err)
+	}
+	if err := cmd.Wait(); err == nil {
+		t.Error("process exited cleanly, want killed")
+	}
+}
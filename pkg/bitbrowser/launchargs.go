@@ -0,0 +1,100 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// launchArgVar matches a {{variable}} in a launch argument.
+var launchArgVar = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// launchArgProfileVars are the variables that need the profile's details.
+var launchArgProfileVars = map[string]bool{
+	"profileSeq":  true,
+	"profileName": true,
+	"proxyHost":   true,
+	"proxyPort":   true,
+}
+
+// WithLaunchArgs adds Chrome arguments to every Open, before
+// OpenOptions.ExtraArgs, so flags can be managed in one place instead of at
+// every call site. Both may use variables that are resolved when the
+// browser is opened:
+//
+//	{{port}}        debugging port (Managed Mode or OpenOptions.CustomPort)
+//	{{profileId}}   profile ID
+//	{{profileSeq}}  profile sequence number
+//	{{profileName}} profile name
+//	{{proxyHost}}   proxy host of the profile
+//	{{proxyPort}}   proxy port of the profile
+//
+// The profile's details are only fetched when an argument needs them.
+//
+// Example:
+//
+//	client, err := bitbrowser.New(apiURL,
+//	    bitbrowser.WithPortRange(9200, 9300),
+//	    bitbrowser.WithLaunchArgs(
+//	        "--user-data-dir=/data/profiles/{{profileSeq}}",
+//	        "--proxy-bypass-list=<-loopback>;{{proxyHost}}",
+//	    ))
+func WithLaunchArgs(args ...string) ClientOption {
+	return func(c *Client) {
+		c.launchArgs = append(c.launchArgs, args...)
+	}
+}
+
+// resolveLaunchArgs returns opts with the client's launch arguments added
+// to ExtraArgs and all variables resolved. port is the debugging port, or 0
+// if it is not known.
+func (c *Client) resolveLaunchArgs(ctx context.Context, id string, port int, opts *OpenOptions) (*OpenOptions, error) {
+	args := append(append(c.crashLogArgs(), c.launchArgs...), opts.ExtraArgs...)
+
+	var templated, needsPort, needsDetail bool
+	for _, arg := range args {
+		for _, m := range launchArgVar.FindAllStringSubmatch(arg, -1) {
+			templated = true
+			switch name := m[1]; {
+			case name == "port":
+				needsPort = true
+			case name == "profileId":
+			case launchArgProfileVars[name]:
+				needsDetail = true
+			default:
+				return nil, &ValidationError{Field: "ExtraArgs", Message: "unknown launch argument variable " + m[0], Value: arg}
+			}
+		}
+	}
+	if len(c.launchArgs) == 0 && !templated {
+		return opts, nil
+	}
+	if needsPort && port == 0 {
+		return nil, NewValidationError("ExtraArgs", "{{port}} needs Managed Mode or CustomPort")
+	}
+
+	vars := map[string]string{"port": strconv.Itoa(port), "profileId": id}
+	if needsDetail {
+		detail, err := c.GetProfileDetail(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("bitbrowser: resolve launch args: %w", err)
+		}
+		vars["profileSeq"] = strconv.Itoa(detail.Seq)
+		vars["profileName"] = detail.Name
+		vars["proxyHost"] = detail.Host
+		vars["proxyPort"] = ""
+		if detail.Port > 0 {
+			vars["proxyPort"] = strconv.Itoa(detail.Port)
+		}
+	}
+
+	resolved := *opts
+	resolved.ExtraArgs = make([]string, len(args))
+	for i, arg := range args {
+		resolved.ExtraArgs[i] = launchArgVar.ReplaceAllStringFunc(arg, func(v string) string {
+			return vars[launchArgVar.FindStringSubmatch(v)[1]]
+		})
+	}
+	return &resolved, nil
+}
@@ -0,0 +1,121 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// openServer is a BitBrowser API that opens any profile. It records the
+// requests to /browser/open and counts the requests to /browser/detail,
+// which it answers with a fixed profile.
+type openServer struct {
+	mu      sync.Mutex
+	opens   []OpenConfig
+	details int
+}
+
+func newOpenServer(t *testing.T, detail ProfileDetail, opts ...ClientOption) (*openServer, *Client) {
+	t.Helper()
+	s := &openServer{}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch r.URL.Path {
+		case "/browser/ports":
+			w.Write(successResponse(map[string]string{}))
+		case "/browser/detail":
+			s.details++
+			w.Write(successResponse(detail))
+		case "/browser/open":
+			var config OpenConfig
+			json.NewDecoder(r.Body).Decode(&config)
+			s.opens = append(s.opens, config)
+			w.Write(successResponse(OpenResult{Ws: "ws://127.0.0.1:50000/devtools/browser/abc", Http: "127.0.0.1:50000"}))
+		}
+	})
+	t.Cleanup(server.Close)
+	return s, mustNew(t, server.URL, opts...)
+}
+
+// lastOpen returns the last open request.
+func (s *openServer) lastOpen() OpenConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.opens) == 0 {
+		return OpenConfig{}
+	}
+	return s.opens[len(s.opens)-1]
+}
+
+// detailRequests returns the number of detail requests and resets it.
+func (s *openServer) detailRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.details
+	s.details = 0
+	return n
+}
+
+func TestWithLaunchArgs(t *testing.T) {
+	t.Run("resolves variables at open time", func(t *testing.T) {
+		server, client := newOpenServer(t, ProfileDetail{ID: "profile-1", Seq: 42, Name: "shop", Host: "10.0.0.5", Port: 8080},
+			WithPortRange(50000, 50000), WithPortProbe(ProbeNone, 0),
+			WithLaunchArgs("--user-data-dir=/data/{{profileSeq}}", "--proxy-bypass-list={{ proxyHost }}:{{proxyPort}}"))
+		_, err := client.Open(context.Background(), "profile-1", &OpenOptions{
+			ExtraArgs: []string{"--log-file=/logs/{{profileId}}-{{port}}.log"},
+		})
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		for _, want := range []string{
+			"--user-data-dir=/data/42",
+			"--proxy-bypass-list=10.0.0.5:8080",
+			"--log-file=/logs/profile-1-50000.log",
+		} {
+			if args := server.lastOpen().Args; !slices.Contains(args, want) {
+				t.Errorf("args = %v, want %s", args, want)
+			}
+		}
+		if n := server.detailRequests(); n != 1 {
+			t.Errorf("detail requests = %d, want 1", n)
+		}
+	})
+
+	t.Run("skips the detail lookup when not needed", func(t *testing.T) {
+		server, client := newOpenServer(t, ProfileDetail{ID: "profile-1"}, WithLaunchArgs("--lang=en-US"))
+		_, err := client.Open(context.Background(), "profile-1", &OpenOptions{
+			CustomPort: 9222,
+			ExtraArgs:  []string{"--remote-allow-origins=http://localhost:{{port}}"},
+		})
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		args := server.lastOpen().Args
+		if !slices.Contains(args, "--lang=en-US") || !slices.Contains(args, "--remote-allow-origins=http://localhost:9222") {
+			t.Errorf("args = %v", args)
+		}
+		if n := server.detailRequests(); n != 0 {
+			t.Errorf("detail requests = %d, want 0", n)
+		}
+	})
+
+	t.Run("invalid templates", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		})
+		defer server.Close()
+		client := mustNew(t, server.URL)
+
+		for _, extra := range []string{"--x={{nope}}", "--port={{port}}"} {
+			_, err := client.Open(context.Background(), "profile-1", &OpenOptions{ExtraArgs: []string{extra}})
+			if !errors.Is(err, ErrValidation) {
+				t.Errorf("Open with %s: err = %v, want ErrValidation", extra, err)
+			}
+		}
+	})
+}
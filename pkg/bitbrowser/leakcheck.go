@@ -0,0 +1,251 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+// Defaults of LeakCheckConfig.
+const (
+	DefaultLeakCheckSTUNServer = "stun:stun.l.google.com:19302"
+	DefaultLeakCheckWait       = 3 * time.Second
+)
+
+// DNSResolver is a DNS resolver that looked up a canary host name.
+type DNSResolver struct {
+	IP          string `json:"ip"`
+	CountryCode string `json:"countryCode"` // ISO 3166-1 alpha-2
+}
+
+// DNSLeakService reports which resolvers looked up the canary host of a
+// leak check, typically an authoritative name server for the canary domain
+// that logs queries.
+type DNSLeakService interface {
+	Resolvers(ctx context.Context, token string) ([]DNSResolver, error)
+}
+
+// DNSLeakServiceFunc adapts a function to the DNSLeakService interface.
+type DNSLeakServiceFunc func(ctx context.Context, token string) ([]DNSResolver, error)
+
+// Resolvers calls fn(ctx, token).
+func (fn DNSLeakServiceFunc) Resolvers(ctx context.Context, token string) ([]DNSResolver, error) {
+	return fn(ctx, token)
+}
+
+// HTTPDNSLeakService returns a DNSLeakService that fetches url, with
+// {{token}} replaced by the check's token, with GET and expects a JSON
+// array such as [{"ip": "203.0.113.53", "countryCode": "DE"}].
+func HTTPDNSLeakService(url string) DNSLeakService {
+	return DNSLeakServiceFunc(func(ctx context.Context, token string) ([]DNSResolver, error) {
+		u := strings.ReplaceAll(url, "{{token}}", token)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, NewNetworkError("dns_leak_resolvers", u, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, NewAPIError(u, resp.StatusCode, resp.Status)
+		}
+		var resolvers []DNSResolver
+		if err := json.NewDecoder(resp.Body).Decode(&resolvers); err != nil {
+			return nil, fmt.Errorf("bitbrowser: failed to parse DNS resolvers: %w", err)
+		}
+		return resolvers, nil
+	})
+}
+
+// LeakCheckConfig configures CheckLeaks.
+type LeakCheckConfig struct {
+	// CanaryURL is loaded in the browser so that its host name is resolved
+	// through the browser's DNS path. It must contain {{token}} in the host
+	// name, e.g. "https://{{token}}.leak.example.com/", so that every check
+	// looks up a name no resolver has cached.
+	CanaryURL string
+
+	// DNS reports the resolvers that looked up the canary host. Without
+	// it, only WebRTC is checked.
+	DNS DNSLeakService
+
+	// STUNServer is asked for the browser's public address over WebRTC
+	// (default: DefaultLeakCheckSTUNServer).
+	STUNServer string
+
+	// Wait bounds the WebRTC candidate gathering (default:
+	// DefaultLeakCheckWait).
+	Wait time.Duration
+}
+
+// LeakReport is the result of a leak check.
+type LeakReport struct {
+	ProfileID string        `json:"profileId,omitempty"`
+	ExitIP    string        `json:"exitIp"`  // Proxy exit IP, from CheckProxy
+	Country   string        `json:"country"` // Proxy exit country code
+	WebRTCIPs []string      `json:"webrtcIps"`
+	Resolvers []DNSResolver `json:"resolvers"`
+
+	// WebRTCLeak is set if WebRTC exposes a public IP other than ExitIP.
+	WebRTCLeak bool `json:"webrtcLeak"`
+
+	// DNSLeak is set if a resolver is outside Country, which shows that
+	// names are resolved around the proxy.
+	DNSLeak bool `json:"dnsLeak"`
+}
+
+// Leaks reports whether the check found a WebRTC or DNS leak.
+func (r *LeakReport) Leaks() bool {
+	return r.WebRTCLeak || r.DNSLeak
+}
+
+// leakCheckJS loads the canary URL and collects the addresses of the
+// browser's WebRTC ICE candidates.
+const leakCheckJS = `(async (canary, stun, wait) => {
+	if (canary) {
+		try { await fetch(canary, {mode: "no-cors", cache: "no-store"}); } catch (e) {}
+	}
+	const ips = new Set();
+	try {
+		const pc = new RTCPeerConnection({iceServers: [{urls: stun}]});
+		pc.createDataChannel("");
+		pc.onicecandidate = (e) => {
+			if (e.candidate && e.candidate.candidate) {
+				const address = e.candidate.address || e.candidate.candidate.split(" ")[4];
+				if (address) ips.add(address);
+			}
+		};
+		await pc.setLocalDescription(await pc.createOffer());
+		await new Promise((resolve) => {
+			pc.onicegatheringstatechange = () => { if (pc.iceGatheringState === "complete") resolve(); };
+			setTimeout(resolve, wait);
+		});
+		pc.close();
+	} catch (e) {}
+	return [...ips];
+})(%s, %s, %d)`
+
+// CheckLeaks checks a running browser, given its WebSocket URL (typically
+// OpenResult.Ws), for WebRTC and DNS leaks around its proxy. proxy is the
+// CheckProxy result of the profile's proxy, which gives the expected exit IP
+// and country.
+//
+// WebRTC leaks when it exposes a public IP other than the proxy's exit IP.
+// DNS leaks when the canary host is resolved by a resolver outside the
+// proxy's country, which needs config.CanaryURL and config.DNS.
+//
+// Example:
+//
+//	check, err := client.CheckProxy(ctx, req)
+//	report, err := bitbrowser.CheckLeaks(ctx, result.Ws, check, bitbrowser.LeakCheckConfig{
+//	    CanaryURL: "https://{{token}}.leak.example.com/",
+//	    DNS:       bitbrowser.HTTPDNSLeakService("https://leak.example.com/resolvers/{{token}}"),
+//	})
+func CheckLeaks(ctx context.Context, ws string, proxy *ProxyCheckResult, config LeakCheckConfig) (*LeakReport, error) {
+	if proxy == nil || !proxy.Success {
+		return nil, NewValidationError("proxy", "proxy check did not succeed")
+	}
+	if config.DNS != nil && !strings.Contains(config.CanaryURL, "{{token}}") {
+		return nil, NewValidationError("CanaryURL", "canary URL with {{token}} is required to check DNS")
+	}
+	if config.STUNServer == "" {
+		config.STUNServer = DefaultLeakCheckSTUNServer
+	}
+	if config.Wait <= 0 {
+		config.Wait = DefaultLeakCheckWait
+	}
+
+	token := randomSession(16)
+	canary := ""
+	if config.DNS != nil {
+		canary = strings.ReplaceAll(config.CanaryURL, "{{token}}", token)
+	}
+	canaryJS, _ := json.Marshal(canary)
+	stunJS, _ := json.Marshal(config.STUNServer)
+
+	conn, err := cdp.Dial(ctx, ws)
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: check leaks: %w", err)
+	}
+	defer conn.Close()
+	session, err := conn.AttachToPage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: check leaks: %w", err)
+	}
+	var candidates []string
+	expr := fmt.Sprintf(leakCheckJS, canaryJS, stunJS, config.Wait.Milliseconds())
+	if err := session.Evaluate(ctx, expr, &candidates); err != nil {
+		return nil, fmt.Errorf("bitbrowser: check leaks: %w", err)
+	}
+
+	report := &LeakReport{ExitIP: proxy.Data.IP, Country: proxy.Data.CountryCode}
+	for _, candidate := range candidates {
+		ip := net.ParseIP(candidate) // mDNS ".local" names hide the address and are skipped
+		if ip == nil || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+			continue
+		}
+		report.WebRTCIPs = append(report.WebRTCIPs, ip.String())
+		if !ip.Equal(net.ParseIP(report.ExitIP)) {
+			report.WebRTCLeak = true
+		}
+	}
+	slices.Sort(report.WebRTCIPs)
+	report.WebRTCIPs = slices.Compact(report.WebRTCIPs)
+
+	if config.DNS != nil {
+		report.Resolvers, err = config.DNS.Resolvers(ctx, token)
+		if err != nil {
+			return nil, fmt.Errorf("bitbrowser: check leaks: %w", err)
+		}
+		for _, r := range report.Resolvers {
+			if report.Country != "" && !strings.EqualFold(r.CountryCode, report.Country) {
+				report.DNSLeak = true
+			}
+		}
+	}
+	return report, nil
+}
+
+// CheckProfileLeaks runs CheckLeaks on a profile: it checks the profile's
+// proxy with CheckProxy, opens the browser with GetOrOpen if it is not
+// running, and checks it. It fails with ErrProxyCheckFailed if the proxy
+// does not work.
+func (c *Client) CheckProfileLeaks(ctx context.Context, id string, config LeakCheckConfig) (*LeakReport, error) {
+	if err := ValidateProfileID(id); err != nil {
+		return nil, err
+	}
+	detail, err := c.GetProfileDetail(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Host == "" {
+		return nil, &ValidationError{Field: "host", Message: "profile has no proxy to check for leaks", Value: id}
+	}
+	check, err := c.CheckProxy(ctx, proxyCheckRequest(detail))
+	if err != nil {
+		return nil, err
+	}
+	if !check.Success {
+		return nil, fmt.Errorf("bitbrowser: check leaks of %s: %w", id, ErrProxyCheckFailed)
+	}
+	result, err := c.GetOrOpen(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	report, err := CheckLeaks(ctx, result.Ws, check, config)
+	if err != nil {
+		return nil, err
+	}
+	report.ProfileID = id
+	return report, nil
+}
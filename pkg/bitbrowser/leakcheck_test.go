@@ -0,0 +1,121 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// candidates makes Runtime.evaluate return the WebRTC candidate addresses.
+func candidates(ips ...string) map[string]any {
+	return map[string]any{"Runtime.evaluate": map[string]any{
+		"result": map[string]any{"type": "object", "value": ips},
+	}}
+}
+
+func germanProxy() *ProxyCheckResult {
+	check := &ProxyCheckResult{Success: true}
+	check.Data.IP = "203.0.113.7"
+	check.Data.CountryCode = "DE"
+	return check
+}
+
+func TestCheckLeaks(t *testing.T) {
+	devtools := newFakeDevTools(t)
+	devtools.results = candidates("192.168.1.20", "abc.local", "203.0.113.7", "203.0.113.7")
+	var token string
+	config := LeakCheckConfig{
+		CanaryURL: "https://{{token}}.leak.example.com/",
+		DNS: DNSLeakServiceFunc(func(ctx context.Context, tok string) ([]DNSResolver, error) {
+			token = tok
+			return []DNSResolver{{IP: "203.0.113.53", CountryCode: "de"}}, nil
+		}),
+	}
+
+	report, err := CheckLeaks(context.Background(), devtools.wsURL(), germanProxy(), config)
+	if err != nil {
+		t.Fatalf("CheckLeaks() error = %v", err)
+	}
+	if report.Leaks() || len(report.WebRTCIPs) != 1 || report.WebRTCIPs[0] != "203.0.113.7" || len(report.Resolvers) != 1 {
+		t.Errorf("report = %+v, want no leaks", report)
+	}
+	if len(token) != 16 {
+		t.Errorf("token = %q", token)
+	}
+
+	devtools = newFakeDevTools(t)
+	devtools.results = candidates("198.51.100.9")
+	config.DNS = DNSLeakServiceFunc(func(ctx context.Context, tok string) ([]DNSResolver, error) {
+		return []DNSResolver{{IP: "203.0.113.53", CountryCode: "DE"}, {IP: "8.8.8.8", CountryCode: "US"}}, nil
+	})
+	report, err = CheckLeaks(context.Background(), devtools.wsURL(), germanProxy(), config)
+	if err != nil {
+		t.Fatalf("CheckLeaks() error = %v", err)
+	}
+	if !report.WebRTCLeak || !report.DNSLeak {
+		t.Errorf("report = %+v, want WebRTC and DNS leaks", report)
+	}
+}
+
+func TestCheckLeaksValidation(t *testing.T) {
+	ctx := context.Background()
+	if _, err := CheckLeaks(ctx, "ws://127.0.0.1:1", &ProxyCheckResult{}, LeakCheckConfig{}); !errors.Is(err, ErrValidation) {
+		t.Errorf("failed proxy check: err = %v, want ErrValidation", err)
+	}
+	config := LeakCheckConfig{CanaryURL: "https://leak.example.com/", DNS: HTTPDNSLeakService("http://127.0.0.1:1")}
+	if _, err := CheckLeaks(ctx, "ws://127.0.0.1:1", germanProxy(), config); !errors.Is(err, ErrValidation) {
+		t.Errorf("canary without token: err = %v, want ErrValidation", err)
+	}
+}
+
+func TestHTTPDNSLeakService(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/resolvers/tok123" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode([]DNSResolver{{IP: "203.0.113.53", CountryCode: "DE"}})
+	}))
+	defer server.Close()
+
+	resolvers, err := HTTPDNSLeakService(server.URL+"/resolvers/{{token}}").Resolvers(context.Background(), "tok123")
+	if err != nil || len(resolvers) != 1 || resolvers[0].CountryCode != "DE" {
+		t.Errorf("Resolvers() = %+v, %v", resolvers, err)
+	}
+	if _, err := HTTPDNSLeakService(server.URL+"/other").Resolvers(context.Background(), "tok123"); !errors.Is(err, ErrAPI) {
+		t.Errorf("404: err = %v, want ErrAPI", err)
+	}
+}
+
+func TestCheckProfileLeaks(t *testing.T) {
+	devtools := newFakeDevTools(t)
+	devtools.results = candidates("203.0.113.7")
+	var paths []string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/browser/detail":
+			w.Write(successResponse(ProfileDetail{ID: "p1", ProxyType: ProxyTypeSOCKS5, Host: "10.0.0.1", Port: 1080}))
+		case "/checkagent":
+			w.Write(successResponse(map[string]any{"success": true, "data": map[string]any{"ip": "203.0.113.7", "countryCode": "DE"}}))
+		case "/browser/open":
+			w.Write(successResponse(map[string]any{"ws": devtools.wsURL(), "http": "127.0.0.1:1"}))
+		}
+	})
+	defer server.Close()
+
+	report, err := mustNew(t, server.URL).CheckProfileLeaks(context.Background(), "p1", LeakCheckConfig{})
+	if err != nil {
+		t.Fatalf("CheckProfileLeaks() error = %v", err)
+	}
+	if report.ProfileID != "p1" || report.Leaks() {
+		t.Errorf("report = %+v", report)
+	}
+	if got := strings.Join(paths, ","); got != "/browser/detail,/checkagent,/browser/open" {
+		t.Errorf("requests = %s", got)
+	}
+}
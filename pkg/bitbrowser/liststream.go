@@ -0,0 +1,191 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ListProfilesStream lists profiles like ListProfiles, but decodes the
+// response while it is read and calls fn with each profile, so that memory
+// stays flat however many profiles a page holds. An error from fn stops
+// the listing and is returned as is.
+//
+// A failed request is retried like any other read, but only until fn has
+// been called: profiles are never delivered twice. Hedging does not apply.
+//
+// Example:
+//
+//	err := client.ListProfilesStream(ctx, bitbrowser.ListRequest{PageSize: 10000},
+//	    func(p bitbrowser.ProfileDetail) error {
+//	        return index.Add(p.ID, p.Name)
+//	    })
+func (c *Client) ListProfilesStream(ctx context.Context, req ListRequest, fn func(ProfileDetail) error) error {
+	const path = "/browser/list"
+	op := endpoints[path].op
+	if err := c.checkSupported(path); err != nil {
+		return fmt.Errorf("bitbrowser: %s failed: %w", op, err)
+	}
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return &ValidationError{Field: "request_body", Message: "failed to marshal request: " + err.Error()}
+	}
+
+	ctx, cancel := c.endpointTimeout(ctx, path)
+	defer cancel()
+
+	c.logRequest(ctx, http.MethodPost, path, req)
+	start := time.Now()
+
+	var delivered bool
+	var fnErr error
+	yield := func(p ProfileDetail) error {
+		c.seqs.remember(p.Seq, p.ID)
+		if err := fn(p); err != nil {
+			fnErr = err
+			return err
+		}
+		delivered = true
+		return nil
+	}
+
+	r := newRetryer(c.retryConfigFor(path))
+	r.budget = c.retryBudget
+	r.clock = c.clock
+	retryIf := r.config.RetryIf
+	if retryIf == nil {
+		retryIf = IsRetryable
+	}
+	r.config.RetryIf = func(err error) bool {
+		return !delivered && fnErr == nil && retryIf(err)
+	}
+
+	attempt := 0
+	err = r.do(ctx, func() error {
+		attempt++
+		err := c.streamList(ctx, path, jsonData, yield)
+		if err != nil && fnErr == nil {
+			err = c.captureBody(err)
+			c.logError(ctx, path, err, attempt)
+		}
+		return err
+	})
+	c.logResponse(ctx, path, 0, time.Since(start), err == nil)
+
+	switch {
+	case fnErr != nil:
+		return fnErr
+	case err != nil:
+		c.recordUnsupported(path, err)
+		return fmt.Errorf("bitbrowser: %s failed: %w", op, err)
+	}
+	return nil
+}
+
+// streamList performs a single list request and passes each profile of
+// the response to yield.
+func (c *Client) streamList(ctx context.Context, path string, jsonData []byte, yield func(ProfileDetail) error) error {
+	resp, err := c.send(ctx, path, jsonData)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var yieldErr error
+	success, msg, err := decodeListStream(resp.Body, func(p ProfileDetail) error {
+		yieldErr = yield(p)
+		return yieldErr
+	})
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case yieldErr != nil:
+		return yieldErr
+	case errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, errBadObject) || err == io.EOF:
+		return NewAPIError(path, http.StatusOK, "failed to unmarshal response: "+err.Error())
+	case err != nil:
+		return NewNetworkError("read_response", c.apiURL+path, err)
+	}
+	if !success {
+		return c.failed(ctx, path, &APIError{Endpoint: path, Op: endpoints[path].op, Message: msg})
+	}
+	return nil
+}
+
+// decodeListStream decodes a /browser/list response from r, passing the
+// profiles in data.list to yield one at a time. Other members of data are
+// skipped.
+func decodeListStream(r io.Reader, yield func(ProfileDetail) error) (success bool, msg string, err error) {
+	dec := json.NewDecoder(r)
+	err = decodeObject(dec, func(key string) error {
+		switch {
+		case strings.EqualFold(key, "success"):
+			return dec.Decode(&success)
+		case strings.EqualFold(key, "msg"):
+			return dec.Decode(&msg)
+		case strings.EqualFold(key, "data"):
+			return decodeObject(dec, func(key string) error {
+				if !strings.EqualFold(key, "list") {
+					return dec.Decode(&json.RawMessage{})
+				}
+				return decodeArray(dec, func() error {
+					var p ProfileDetail
+					if err := dec.Decode(&p); err != nil {
+						return err
+					}
+					return yield(p)
+				})
+			})
+		default:
+			return dec.Decode(&json.RawMessage{})
+		}
+	})
+	return success, msg, err
+}
+
+// decodeObject reads a JSON object or null from dec, calling member with
+// each key; member must consume the value.
+func decodeObject(dec *json.Decoder, member func(key string) error) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return errBadObject
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if err := member(tok.(string)); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// decodeArray reads a JSON array or null from dec, calling elem for each
+// element; elem must consume it.
+func decodeArray(dec *json.Decoder, elem func() error) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+	if tok != json.Delim('[') {
+		return errBadObject
+	}
+	for dec.More() {
+		if err := elem(); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
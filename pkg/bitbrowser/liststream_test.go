@@ -0,0 +1,157 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestListProfilesStream(t *testing.T) {
+	quickRetry := WithRetryConfig(&RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond})
+
+	t.Run("streams profiles", func(t *testing.T) {
+		var req ListRequest
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&req)
+			w.Write([]byte(`{"msg":"","data":{"page":0,"extra":{"a":[1]},"list":[{"id":"p1","name":"one","newField":1},{"id":"p2"}],"totalNum":2},"success":true}`))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		var ids []string
+		err := client.ListProfilesStream(context.Background(), ListRequest{PageSize: 500}, func(p ProfileDetail) error {
+			ids = append(ids, p.ID)
+			if p.ID == "p1" && (p.Name != "one" || string(p.Extras["newField"]) != "1") {
+				t.Errorf("profile = %+v", p)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Join(ids, ",") != "p1,p2" {
+			t.Errorf("ids = %v, want [p1 p2]", ids)
+		}
+		if req.PageSize != 500 {
+			t.Errorf("PageSize = %d, want 500", req.PageSize)
+		}
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"success":true,"data":{"list":null,"totalNum":0}}`))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		err := client.ListProfilesStream(context.Background(), ListRequest{}, func(ProfileDetail) error {
+			t.Error("fn called for an empty list")
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("callback error stops listing", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(ListResult{List: []ProfileDetail{{ID: "p1"}, {ID: "p2"}}}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		errStop := errors.New("stop")
+		calls := 0
+		err := client.ListProfilesStream(context.Background(), ListRequest{}, func(ProfileDetail) error {
+			calls++
+			return errStop
+		})
+		if err != errStop {
+			t.Errorf("err = %v, want the callback's error", err)
+		}
+		if calls != 1 {
+			t.Errorf("fn called %d times, want 1", calls)
+		}
+	})
+
+	t.Run("API failure", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(errorResponse("no permission"))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		err := client.ListProfilesStream(context.Background(), ListRequest{}, func(ProfileDetail) error { return nil })
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Message != "no permission" {
+			t.Errorf("err = %v, want API error with the message", err)
+		}
+	})
+
+	t.Run("retries before the first profile", func(t *testing.T) {
+		var requests atomic.Int32
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write(successResponse(ListResult{List: []ProfileDetail{{ID: "p1"}}}))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, quickRetry)
+		calls := 0
+		err := client.ListProfilesStream(context.Background(), ListRequest{}, func(ProfileDetail) error {
+			calls++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if requests.Load() != 2 || calls != 1 {
+			t.Errorf("requests = %d, calls = %d, want 2 and 1", requests.Load(), calls)
+		}
+	})
+
+	t.Run("no retry after a profile was delivered", func(t *testing.T) {
+		var requests atomic.Int32
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			io.WriteString(w, `{"success":true,"data":{"list":[{"id":"p1"},{"id":`)
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, quickRetry)
+		calls := 0
+		err := client.ListProfilesStream(context.Background(), ListRequest{}, func(ProfileDetail) error {
+			calls++
+			return nil
+		})
+		if !errors.Is(err, ErrNetwork) {
+			t.Errorf("err = %v, want network error", err)
+		}
+		if requests.Load() != 1 || calls != 1 {
+			t.Errorf("requests = %d, calls = %d, want 1 and 1", requests.Load(), calls)
+		}
+	})
+
+	t.Run("malformed response", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"success":true,"data":{"list":{}}}`))
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		err := client.ListProfilesStream(context.Background(), ListRequest{}, func(ProfileDetail) error { return nil })
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !strings.Contains(apiErr.Message, "unmarshal") {
+			t.Errorf("err = %v, want unmarshal error", err)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package bitbrowser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the BitBrowser local API address used when no URL is configured.
+const DefaultAPIURL = "http://127.0.0.1:54345"
+
+// ConfigEnvPrefix is the prefix of environment variables read by ReadConfig,
+// e.g. ANTIDETECT_API_URL for the api_url key.
+const ConfigEnvPrefix = "ANTIDETECT_"
+
+// Config holds client settings loaded from a file or the environment.
+//
+// Supported keys (file key / environment variable):
+//
+//	api_url          ANTIDETECT_API_URL           BitBrowser API URL (default http://127.0.0.1:54345)
+//	api_key          ANTIDETECT_API_KEY           API token (x-api-key)
+//	unix_socket      ANTIDETECT_UNIX_SOCKET       Reach the API over a Unix socket
+//	min_port         ANTIDETECT_MIN_PORT          Managed Mode port range start
+//	max_port         ANTIDETECT_MAX_PORT          Managed Mode port range end
+//	retry_attempts   ANTIDETECT_RETRY_ATTEMPTS    Maximum attempts per request
+//	retry_base_delay ANTIDETECT_RETRY_BASE_DELAY  Initial backoff, e.g. "500ms"
+//	retry_max_delay  ANTIDETECT_RETRY_MAX_DELAY   Maximum backoff, e.g. "10s"
+//	log_level        ANTIDETECT_LOG_LEVEL         debug, info, warn or error (empty disables logging)
+//	log_format       ANTIDETECT_LOG_FORMAT        text (default) or json; logs go to stderr
+//	ca_file          ANTIDETECT_CA_FILE           Extra CA bundle to trust
+//	cert_file        ANTIDETECT_CERT_FILE         Client certificate for mTLS
+//	key_file         ANTIDETECT_KEY_FILE          Client private key for mTLS
+//	profile_limit    ANTIDETECT_PROFILE_LIMIT     Plan profile limit for quota checks
+//	dry_run          ANTIDETECT_DRY_RUN           Log mutating calls instead of sending them
+type Config struct {
+	APIURL         string
+	APIKey         string
+	UnixSocket     string
+	MinPort        int
+	MaxPort        int
+	RetryAttempts  int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	LogLevel       string
+	LogFormat      string
+	CAFile         string
+	CertFile       string
+	KeyFile        string
+	ProfileLimit   int
+	DryRun         bool
+}
+
+// configSetters parses a raw value into the matching Config field.
+var configSetters = map[string]func(c *Config, v string) error{
+	"api_url":          func(c *Config, v string) error { c.APIURL = v; return nil },
+	"api_key":          func(c *Config, v string) error { c.APIKey = v; return nil },
+	"unix_socket":      func(c *Config, v string) error { c.UnixSocket = v; return nil },
+	"min_port":         func(c *Config, v string) error { return parseInt(v, &c.MinPort) },
+	"max_port":         func(c *Config, v string) error { return parseInt(v, &c.MaxPort) },
+	"retry_attempts":   func(c *Config, v string) error { return parseInt(v, &c.RetryAttempts) },
+	"retry_base_delay": func(c *Config, v string) error { return parseDuration(v, &c.RetryBaseDelay) },
+	"retry_max_delay":  func(c *Config, v string) error { return parseDuration(v, &c.RetryMaxDelay) },
+	"log_level":        func(c *Config, v string) error { c.LogLevel = v; return nil },
+	"log_format":       func(c *Config, v string) error { c.LogFormat = v; return nil },
+	"ca_file":          func(c *Config, v string) error { c.CAFile = v; return nil },
+	"cert_file":        func(c *Config, v string) error { c.CertFile = v; return nil },
+	"key_file":         func(c *Config, v string) error { c.KeyFile = v; return nil },
+	"profile_limit":    func(c *Config, v string) error { return parseInt(v, &c.ProfileLimit) },
+	"dry_run": func(c *Config, v string) (err error) {
+		c.DryRun, err = strconv.ParseBool(v)
+		return err
+	},
+}
+
+// ReadConfig reads settings from the file at path and then from
+// ANTIDETECT_* environment variables, which take precedence over the file.
+// An empty path reads the environment only.
+//
+// The format is chosen by extension: .json, .yaml/.yml or .toml. YAML and
+// TOML files must be flat key/value lists; nested values are not supported.
+func ReadConfig(path string) (*Config, error) {
+	values := make(map[string]string)
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, NewValidationError("path", "failed to read config: "+err.Error())
+		}
+		if err := parseConfigFile(path, data, values); err != nil {
+			return nil, err
+		}
+	}
+
+	for key := range configSetters {
+		if v, ok := os.LookupEnv(ConfigEnvPrefix + strings.ToUpper(key)); ok {
+			values[key] = v
+		}
+	}
+
+	cfg := &Config{APIURL: DefaultAPIURL}
+	for key, v := range values {
+		set, ok := configSetters[key]
+		if !ok {
+			return nil, NewValidationError(key, "unknown config key")
+		}
+		if err := set(cfg, v); err != nil {
+			return nil, &ValidationError{Field: key, Message: err.Error(), Value: v}
+		}
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadConfig builds a Client from ReadConfig(path). Options passed here are
+// applied after the loaded settings and therefore take precedence.
+//
+// Precedence, lowest first: defaults, config file, environment, opts.
+//
+// Example:
+//
+//	client, err := bitbrowser.LoadConfig("/etc/antidetect.yaml")
+func LoadConfig(path string, opts ...ClientOption) (*Client, error) {
+	cfg, err := ReadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(cfg.APIURL, append(cfg.Options(), opts...)...)
+}
+
+// Options converts the configuration into client options.
+func (c *Config) Options() []ClientOption {
+	var opts []ClientOption
+	if c.APIKey != "" {
+		opts = append(opts, WithAPIKey(c.APIKey))
+	}
+	if c.UnixSocket != "" {
+		opts = append(opts, WithUnixSocket(c.UnixSocket))
+	}
+	if c.MinPort > 0 || c.MaxPort > 0 {
+		opts = append(opts, WithPortRange(c.MinPort, c.MaxPort))
+	}
+	if c.RetryAttempts > 0 || c.RetryBaseDelay > 0 || c.RetryMaxDelay > 0 {
+		retry := DefaultRetryConfig()
+		if c.RetryAttempts > 0 {
+			retry.MaxAttempts = c.RetryAttempts
+		}
+		if c.RetryBaseDelay > 0 {
+			retry.BaseDelay = c.RetryBaseDelay
+		}
+		if c.RetryMaxDelay > 0 {
+			retry.MaxDelay = c.RetryMaxDelay
+		}
+		opts = append(opts, WithRetryConfig(retry))
+	}
+	if logger := c.logger(); logger != nil {
+		opts = append(opts, WithLogger(logger))
+	}
+	if c.CAFile != "" {
+		opts = append(opts, WithCAFile(c.CAFile))
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		opts = append(opts, WithClientCertificate(c.CertFile, c.KeyFile))
+	}
+	if c.ProfileLimit > 0 {
+		opts = append(opts, WithProfileLimit(c.ProfileLimit))
+	}
+	if c.DryRun {
+		opts = append(opts, WithDryRun(true))
+	}
+	return opts
+}
+
+// validate checks values that cannot be verified while parsing.
+func (c *Config) validate() error {
+	if _, err := parseLogLevel(c.LogLevel); err != nil {
+		return &ValidationError{Field: "log_level", Message: err.Error(), Value: c.LogLevel}
+	}
+	switch strings.ToLower(c.LogFormat) {
+	case "", "text", "json":
+	default:
+		return &ValidationError{Field: "log_format", Message: "must be text or json", Value: c.LogFormat}
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return NewValidationError("cert_file", "cert_file and key_file must be set together")
+	}
+	return nil
+}
+
+// logger builds a stderr logger from LogLevel and LogFormat, or returns nil
+// if logging is disabled.
+func (c *Config) logger() *slog.Logger {
+	level, _ := parseLogLevel(c.LogLevel)
+	if level == nil {
+		return nil
+	}
+	handlerOpts := &slog.HandlerOptions{Level: *level}
+	if strings.EqualFold(c.LogFormat, "json") {
+		return slog.New(slog.NewJSONHandler(os.Stderr, handlerOpts))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, handlerOpts))
+}
+
+// parseLogLevel parses a level name. An empty name returns nil (no logging).
+func parseLogLevel(name string) (*slog.Level, error) {
+	if name == "" {
+		return nil, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return nil, fmt.Errorf("must be debug, info, warn or error")
+	}
+	return &level, nil
+}
+
+// parseConfigFile decodes a config file into raw key/value strings.
+func parseConfigFile(path string, data []byte, values map[string]string) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		var raw map[string]any
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return NewValidationError("path", "invalid JSON config: "+err.Error())
+		}
+		for key, v := range raw {
+			switch v := v.(type) {
+			case map[string]any, []any:
+				return NewValidationError(key, "nested values are not supported")
+			case nil:
+			default:
+				values[key] = fmt.Sprint(v)
+			}
+		}
+		return nil
+	case ".yaml", ".yml":
+		return parseFlatConfig(data, ":", values)
+	case ".toml":
+		return parseFlatConfig(data, "=", values)
+	default:
+		return NewValidationError("path", "unsupported config format "+strconv.Quote(ext)+" (use .json, .yaml or .toml)")
+	}
+}
+
+// parseFlatConfig parses "key: value" (YAML) or "key = value" (TOML) lines.
+// Comments, blank lines, YAML document markers and TOML table headers are skipped.
+func parseFlatConfig(data []byte, sep string, values map[string]string) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" ||
+			(strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]")) {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			return NewValidationError("path", fmt.Sprintf("line %d: nested values are not supported", lineNo))
+		}
+
+		key, value, ok := strings.Cut(trimmed, sep)
+		if !ok {
+			return NewValidationError("path", fmt.Sprintf("line %d: expected key%svalue", lineNo, sep))
+		}
+		values[strings.TrimSpace(key)] = unquoteConfigValue(strings.TrimSpace(value))
+	}
+	return scanner.Err()
+}
+
+// unquoteConfigValue strips quotes, or a trailing comment from unquoted values.
+func unquoteConfigValue(v string) string {
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') {
+		if end := strings.IndexByte(v[1:], v[0]); end >= 0 {
+			return v[1 : end+1]
+		}
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = strings.TrimSpace(v[:i])
+	}
+	return v
+}
+
+// parseInt parses a decimal integer into dst.
+func parseInt(v string, dst *int) error {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid integer %q", v)
+	}
+	*dst = n
+	return nil
+}
+
+// parseDuration parses a duration such as "500ms" or "2s" into dst.
+func parseDuration(v string, dst *time.Duration) error {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", v)
+	}
+	*dst = d
+	return nil
+}
@@ -0,0 +1,162 @@
+package bitbrowser
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeConfig writes content to a file named name in a temp directory.
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadConfig(t *testing.T) {
+	want := Config{
+		APIURL:         "http://10.0.0.5:54345",
+		APIKey:         "secret",
+		MinPort:        50000,
+		MaxPort:        51000,
+		RetryAttempts:  3,
+		RetryBaseDelay: 500 * time.Millisecond,
+		LogLevel:       "info",
+	}
+
+	formats := map[string]string{
+		"config.yaml": `# BitBrowser client
+---
+api_url: http://10.0.0.5:54345
+api_key: "secret"
+min_port: 50000
+max_port: 51000   # Managed Mode
+retry_attempts: 3
+retry_base_delay: 500ms
+log_level: info
+`,
+		"config.toml": `[bitbrowser]
+api_url = "http://10.0.0.5:54345"
+api_key = 'secret'
+min_port = 50000
+max_port = 51000
+retry_attempts = 3
+retry_base_delay = "500ms"
+log_level = "info"
+`,
+		"config.json": `{
+  "api_url": "http://10.0.0.5:54345",
+  "api_key": "secret",
+  "min_port": 50000,
+  "max_port": 51000,
+  "retry_attempts": 3,
+  "retry_base_delay": "500ms",
+  "log_level": "info"
+}`,
+	}
+
+	for name, content := range formats {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := ReadConfig(writeConfig(t, name, content))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *cfg != want {
+				t.Errorf("cfg = %+v, want %+v", *cfg, want)
+			}
+		})
+	}
+
+	t.Run("environment overrides file", func(t *testing.T) {
+		t.Setenv("ANTIDETECT_API_KEY", "from-env")
+		t.Setenv("ANTIDETECT_DRY_RUN", "true")
+		cfg, err := ReadConfig(writeConfig(t, "config.yaml", "api_key: from-file\n"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.APIKey != "from-env" || !cfg.DryRun {
+			t.Errorf("unexpected config: %+v", cfg)
+		}
+	})
+
+	t.Run("environment only with defaults", func(t *testing.T) {
+		t.Setenv("ANTIDETECT_PROFILE_LIMIT", "50")
+		cfg, err := ReadConfig("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.APIURL != DefaultAPIURL || cfg.ProfileLimit != 50 {
+			t.Errorf("unexpected config: %+v", cfg)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		cases := map[string]string{
+			"unknown key":      "api_urll: http://x\n",
+			"bad integer":      "min_port: lots\n",
+			"bad duration":     "retry_base_delay: soon\n",
+			"bad log level":    "log_level: loud\n",
+			"bad log format":   "log_format: xml\n",
+			"nested value":     "retry:\n  attempts: 3\n",
+			"half a cert pair": "cert_file: client.pem\n",
+		}
+		for name, content := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, err := ReadConfig(writeConfig(t, "config.yaml", content))
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("expected ErrValidation, got %v", err)
+				}
+			})
+		}
+
+		if _, err := ReadConfig(writeConfig(t, "config.ini", "")); !errors.Is(err, ErrValidation) {
+			t.Errorf("expected ErrValidation for unsupported format, got %v", err)
+		}
+		if _, err := ReadConfig(filepath.Join(t.TempDir(), "missing.yaml")); !errors.Is(err, ErrValidation) {
+			t.Errorf("expected ErrValidation for missing file, got %v", err)
+		}
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("builds client from file", func(t *testing.T) {
+		path := writeConfig(t, "config.yaml", `api_url: http://127.0.0.1:54345/
+api_key: secret
+min_port: 50000
+max_port: 51000
+retry_attempts: 4
+log_level: debug
+dry_run: true
+`)
+		client, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.apiURL != "http://127.0.0.1:54345" || client.apiKey != "secret" {
+			t.Errorf("apiURL = %q, apiKey = %q", client.apiURL, client.apiKey)
+		}
+		if client.portManager == nil {
+			t.Error("expected Managed Mode")
+		}
+		if client.retryConfig.MaxAttempts != 4 || client.logger == nil || !client.dryRun {
+			t.Errorf("options not applied: retry=%d logger=%v dryRun=%v",
+				client.retryConfig.MaxAttempts, client.logger != nil, client.dryRun)
+		}
+	})
+
+	t.Run("explicit options take precedence", func(t *testing.T) {
+		path := writeConfig(t, "config.toml", "api_key = \"from-file\"\n")
+		client, err := LoadConfig(path, WithAPIKey("from-code"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.apiKey != "from-code" {
+			t.Errorf("apiKey = %q, want from-code", client.apiKey)
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+// RunMacro opens a profile with GetOrOpen if it is not running and runs a
+// macro on its first page, returning what the macro's extract and
+// exportCookies steps stored (see cdp.Session.RunMacroOutput). The browser
+// stays open.
+//
+// Example:
+//
+//	steps, err := cdp.ParseMacro(data)
+//	output, err := client.RunMacro(ctx, id, steps)
+func (c *Client) RunMacro(ctx context.Context, id string, steps []cdp.MacroStep) (map[string]any, error) {
+	if len(steps) == 0 {
+		return nil, NewValidationError("steps", "macro has no steps")
+	}
+	result, err := c.GetOrOpen(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := cdp.Dial(ctx, result.Ws)
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: run macro: %w", err)
+	}
+	defer conn.Close()
+	session, err := conn.AttachToPage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: run macro: %w", err)
+	}
+	return session.RunMacroOutput(ctx, steps)
+}
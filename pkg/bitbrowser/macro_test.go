@@ -0,0 +1,35 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+func TestClientRunMacro(t *testing.T) {
+	devtools := newFakeDevTools(t)
+	devtools.results = map[string]any{"Storage.getCookies": map[string]any{
+		"cookies": []map[string]any{{"name": "sid", "value": "abc"}},
+	}}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(successResponse(OpenResult{Ws: devtools.wsURL()}))
+	})
+	defer server.Close()
+	client := mustNew(t, server.URL)
+
+	output, err := client.RunMacro(context.Background(), "p1", []cdp.MacroStep{
+		{Action: cdp.MacroExportCookies, Name: "session"},
+	})
+	if err != nil {
+		t.Fatalf("RunMacro() error = %v", err)
+	}
+	if cookies, _ := output["session"].([]map[string]any); len(cookies) != 1 || cookies[0]["value"] != "abc" {
+		t.Errorf("output = %v", output)
+	}
+	if _, err := client.RunMacro(context.Background(), "p1", nil); !errors.Is(err, ErrValidation) {
+		t.Errorf("no steps: err = %v, want ErrValidation", err)
+	}
+}
@@ -0,0 +1,179 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+	"github.com/lpg-it/go-antidetect/pkg/ua"
+)
+
+// OSType is the device class a profile emulates.
+type OSType string
+
+// OS types.
+const (
+	OSTypePC      OSType = "PC"
+	OSTypeAndroid OSType = "Android"
+	OSTypeIOS     OSType = "IOS"
+)
+
+// Mobile reports whether t is a phone OS type.
+func (t OSType) Mobile() bool {
+	return t == OSTypeAndroid || t == OSTypeIOS
+}
+
+// Device is a phone a mobile profile emulates.
+type Device struct {
+	Name             string
+	OSType           OSType
+	OS               string  // navigator.platform, e.g. "iPhone" or "Linux armv81"
+	OSVersion        string  // As in the user agent, e.g. "14" or "17_5"; empty for the default
+	Model            string  // Android model for the user agent; empty for Chrome's reduced "K"
+	Width            int     // Screen width in CSS pixels
+	Height           int     // Screen height in CSS pixels
+	DevicePixelRatio float64 // One of 1, 1.5, 2, 2.5 and 3, which BitBrowser accepts
+	MaxTouchPoints   int
+}
+
+// Devices is the catalog of phones LookupDevice finds.
+var Devices = []Device{
+	{Name: "iPhone 15 Pro", OSType: OSTypeIOS, OS: "iPhone", OSVersion: "17_5", Width: 393, Height: 852, DevicePixelRatio: 3, MaxTouchPoints: 5},
+	{Name: "iPhone 13", OSType: OSTypeIOS, OS: "iPhone", OSVersion: "16_6", Width: 390, Height: 844, DevicePixelRatio: 3, MaxTouchPoints: 5},
+	{Name: "iPhone SE", OSType: OSTypeIOS, OS: "iPhone", OSVersion: "16_6", Width: 375, Height: 667, DevicePixelRatio: 2, MaxTouchPoints: 5},
+	{Name: "Galaxy S23", OSType: OSTypeAndroid, OS: "Linux armv81", Width: 360, Height: 780, DevicePixelRatio: 3, MaxTouchPoints: 10},
+	{Name: "Galaxy S21", OSType: OSTypeAndroid, OS: "Linux armv81", Width: 360, Height: 800, DevicePixelRatio: 3, MaxTouchPoints: 10},
+	{Name: "Galaxy A12", OSType: OSTypeAndroid, OS: "Linux armv81", Width: 360, Height: 800, DevicePixelRatio: 2, MaxTouchPoints: 10},
+}
+
+// LookupDevice returns the device of Devices with the given name, ignoring
+// case.
+func LookupDevice(name string) (Device, bool) {
+	for _, d := range Devices {
+		if strings.EqualFold(d.Name, name) {
+			return d, true
+		}
+	}
+	return Device{}, false
+}
+
+// Fingerprint returns a fingerprint of the device for Chrome coreVersion
+// (default DefaultCoreVersion): its OS type and platform, screen size and
+// pixel ratio, a window of the screen's size and a matching user agent.
+//
+// Example:
+//
+//	device, _ := bitbrowser.LookupDevice("iPhone 15 Pro")
+//	fp, err := device.Fingerprint("130")
+//	id, err := client.CreateProfile(ctx, bitbrowser.ProfileConfig{Name: "mobile", BrowserFingerPrint: fp})
+func (d Device) Fingerprint(coreVersion string) (*Fingerprint, error) {
+	if !d.OSType.Mobile() {
+		return nil, &ValidationError{Field: "ostype", Message: "device must be Android or IOS", Value: d.OSType}
+	}
+	if d.Width <= 0 || d.Height <= 0 {
+		return nil, &ValidationError{Field: "resolution", Message: "device must have a screen size", Value: d.Name}
+	}
+	if coreVersion == "" {
+		coreVersion = DefaultCoreVersion
+	}
+	os := ua.Android
+	if d.OSType == OSTypeIOS {
+		os = ua.IOS
+	}
+	userAgent, err := ua.Generate(ua.Options{Version: coreVersion, OS: os, OSVersion: d.OSVersion, Device: d.Model})
+	if err != nil {
+		return nil, &ValidationError{Field: "browserFingerPrint", Message: err.Error()}
+	}
+	return &Fingerprint{
+		CoreVersion:      coreVersion,
+		OSType:           d.OSType,
+		OS:               d.OS,
+		Version:          coreVersion,
+		UserAgent:        userAgent,
+		OpenWidth:        d.Width,
+		OpenHeight:       d.Height,
+		ResolutionType:   "1",
+		Resolution:       fmt.Sprintf("%d x %d", d.Width, d.Height),
+		DevicePixelRatio: d.DevicePixelRatio,
+	}, nil
+}
+
+// Metrics returns the device metrics Session.SetDeviceMetrics emulates the
+// device with.
+func (d Device) Metrics() cdp.DeviceMetrics {
+	return cdp.DeviceMetrics{
+		Width:             d.Width,
+		Height:            d.Height,
+		DeviceScaleFactor: d.DevicePixelRatio,
+		Mobile:            d.OSType.Mobile(),
+		MaxTouchPoints:    d.MaxTouchPoints,
+	}
+}
+
+// DeviceFromFingerprint returns the device a mobile fingerprint describes,
+// from its OS type, platform, custom resolution and pixel ratio. It reports
+// false for desktop fingerprints and for those without a custom resolution.
+func DeviceFromFingerprint(fp *Fingerprint) (Device, bool) {
+	if fp == nil || !fp.OSType.Mobile() || fp.ResolutionType != "1" {
+		return Device{}, false
+	}
+	w, h, ok := strings.Cut(fp.Resolution, "x")
+	width, err1 := strconv.Atoi(strings.TrimSpace(w))
+	height, err2 := strconv.Atoi(strings.TrimSpace(h))
+	if !ok || err1 != nil || err2 != nil || width <= 0 || height <= 0 {
+		return Device{}, false
+	}
+	d := Device{
+		OSType:           fp.OSType,
+		OS:               fp.OS,
+		Width:            width,
+		Height:           height,
+		DevicePixelRatio: fp.DevicePixelRatio,
+		MaxTouchPoints:   10,
+	}
+	if fp.OSType == OSTypeIOS {
+		d.MaxTouchPoints = 5
+	}
+	return d, true
+}
+
+// DeviceMetricsHook returns a ReadyHook that emulates the device's screen,
+// pixel ratio and touch input for as long as the browser runs, so that
+// automation sees the same viewport as the fingerprint claims.
+//
+// Example:
+//
+//	result, err := client.Open(ctx, id, &bitbrowser.OpenOptions{
+//	    OnReady: []bitbrowser.ReadyHook{bitbrowser.DeviceMetricsHook(device)},
+//	})
+func DeviceMetricsHook(d Device) ReadyHook {
+	return func(ctx context.Context, session *cdp.Session) error {
+		m := d.Metrics()
+		return session.SetDeviceMetrics(ctx, &m)
+	}
+}
+
+// OpenMobile opens a profile like Open and, if its fingerprint is mobile,
+// emulates its device with DeviceMetricsHook, after the hooks in opts. The
+// device is read from the profile with DeviceFromFingerprint; desktop
+// profiles open unchanged.
+func (c *Client) OpenMobile(ctx context.Context, id string, opts *OpenOptions) (*OpenResult, error) {
+	if err := ValidateProfileID(id); err != nil {
+		return nil, err
+	}
+	detail, err := c.GetProfileDetail(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if device, ok := DeviceFromFingerprint(detail.BrowserFingerPrint); ok {
+		o := OpenOptions{}
+		if opts != nil {
+			o = *opts
+		}
+		o.OnReady = append(append([]ReadyHook(nil), o.OnReady...), DeviceMetricsHook(device))
+		opts = &o
+	}
+	return c.Open(ctx, id, opts)
+}
@@ -0,0 +1,73 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+func TestDeviceFingerprint(t *testing.T) {
+	for _, d := range Devices {
+		fp, err := d.Fingerprint("")
+		if err != nil {
+			t.Fatalf("%s: Fingerprint() error = %v", d.Name, err)
+		}
+		if err := ValidateUserAgent(fp); err != nil {
+			t.Errorf("%s: user agent %q does not match: %v", d.Name, fp.UserAgent, err)
+		}
+		back, ok := DeviceFromFingerprint(fp)
+		if !ok || back.Width != d.Width || back.Height != d.Height || back.DevicePixelRatio != d.DevicePixelRatio || back.MaxTouchPoints != d.MaxTouchPoints {
+			t.Errorf("%s: DeviceFromFingerprint() = %+v, %v", d.Name, back, ok)
+		}
+	}
+
+	d, ok := LookupDevice("iphone 15 pro")
+	if !ok {
+		t.Fatal("LookupDevice(iphone 15 pro) not found")
+	}
+	fp, _ := d.Fingerprint("131")
+	if fp.OS != "iPhone" || fp.Resolution != "393 x 852" || !strings.Contains(fp.UserAgent, "CriOS/131.0.0.0") {
+		t.Errorf("fingerprint = %+v", fp)
+	}
+	if _, err := (Device{OSType: OSTypePC, Width: 1920, Height: 1080}).Fingerprint(""); !errors.Is(err, ErrValidation) {
+		t.Errorf("PC device: err = %v, want ErrValidation", err)
+	}
+	if _, ok := DeviceFromFingerprint(&Fingerprint{OSType: OSTypePC, ResolutionType: "1", Resolution: "1920 x 1080"}); ok {
+		t.Error("DeviceFromFingerprint(PC) reported a device")
+	}
+}
+
+func TestOpenMobile(t *testing.T) {
+	devtools := newFakeDevTools(t)
+	device, _ := LookupDevice("Galaxy S23")
+	fp, _ := device.Fingerprint("")
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/browser/detail":
+			w.Write(successResponse(ProfileDetail{ID: "p1", BrowserFingerPrint: fp}))
+		case "/browser/open":
+			w.Write(successResponse(OpenResult{Ws: devtools.wsURL()}))
+		}
+	})
+	defer server.Close()
+
+	var ran bool
+	opts := &OpenOptions{OnReady: []ReadyHook{func(ctx context.Context, _ *cdp.Session) error {
+		ran = true
+		return nil
+	}}}
+	if _, err := mustNew(t, server.URL).OpenMobile(context.Background(), "p1", opts); err != nil {
+		t.Fatalf("OpenMobile() error = %v", err)
+	}
+	calls := strings.Join(devtools.calls(), ",")
+	if !ran || !strings.Contains(calls, "Emulation.setDeviceMetricsOverride") || !strings.Contains(calls, "Emulation.setTouchEmulationEnabled") {
+		t.Errorf("ran = %v, DevTools calls = %s", ran, calls)
+	}
+	if len(opts.OnReady) != 1 {
+		t.Errorf("OpenMobile changed the caller's options: %d hooks", len(opts.OnReady))
+	}
+}
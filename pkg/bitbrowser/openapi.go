@@ -0,0 +1,162 @@
+package bitbrowser
+
+import (
+	"encoding/json"
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// OpenAPISpec returns an OpenAPI 3.0 document describing the BitBrowser
+// local API as used by this SDK. Schemas are generated from the same Go
+// request and response types the client sends and decodes, so other teams
+// can generate clients or validate payloads against them.
+//
+// Every operation is a POST with a JSON body; responses are wrapped in
+// {"success": ..., "msg": ..., "data": ...}.
+func OpenAPISpec() ([]byte, error) {
+	g := &schemaGenerator{components: make(map[string]any)}
+
+	paths := make(map[string]any, len(endpoints))
+	for path, ep := range endpoints {
+		data := map[string]any{}
+		if ep.response != nil {
+			data = g.schema(ep.response)
+		}
+		paths[path] = map[string]any{
+			"post": map[string]any{
+				"operationId": ep.name,
+				"summary":     ep.op,
+				"requestBody": map[string]any{
+					"required": true,
+					"content":  jsonContent(g.schema(ep.request)),
+				},
+				"responses": map[string]any{
+					"200": map[string]any{
+						"description": "Result of the call; check success",
+						"content": jsonContent(map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"success": map[string]any{"type": "boolean"},
+								"msg":     map[string]any{"type": "string"},
+								"data":    data,
+							},
+							"required": []string{"success"},
+						}),
+					},
+				},
+			},
+		}
+	}
+
+	return json.MarshalIndent(map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "BitBrowser Local API",
+			"version": "1.0.0",
+		},
+		"servers": []any{map[string]any{"url": "http://127.0.0.1:54345"}},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": g.components,
+			"securitySchemes": map[string]any{
+				"apiKey": map[string]any{"type": "apiKey", "in": "header", "name": "x-api-key"},
+			},
+		},
+		"security": []any{map[string]any{"apiKey": []string{}}},
+	}, "", "  ")
+}
+
+// jsonContent wraps a schema in an application/json media type.
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// schemaGenerator converts Go types to OpenAPI schemas. Exported named
+// struct types become components referenced with $ref.
+type schemaGenerator struct {
+	components map[string]any
+}
+
+var rawMessageType = reflect.TypeFor[json.RawMessage]()
+
+// schema returns the schema of t.
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	if t == rawMessageType {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.schema(t.Elem())
+		if _, ref := s["$ref"]; ref {
+			return map[string]any{"allOf": []any{s}, "nullable": true}
+		}
+		s["nullable"] = true
+		return s
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" || !token.IsExported(name) {
+			return g.object(t)
+		}
+		if _, ok := g.components[name]; !ok {
+			g.components[name] = nil // Guards against recursive types
+			g.components[name] = g.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{} // any
+	}
+}
+
+// object returns the inline object schema of struct type t. Embedded
+// structs are flattened like encoding/json does, and an Extra map tagged
+// "-" allows additional properties.
+func (g *schemaGenerator) object(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	additional := false
+	g.fields(t, props, &additional)
+
+	s := map[string]any{"type": "object", "properties": props}
+	if additional {
+		s["additionalProperties"] = true
+	}
+	return s
+}
+
+// fields adds the JSON properties of struct type t to props.
+func (g *schemaGenerator) fields(t reflect.Type, props map[string]any, additional *bool) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		switch {
+		case name == "-":
+			if f.Name == "Extra" && f.Type.Kind() == reflect.Map {
+				*additional = true
+			}
+			continue
+		case f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct:
+			g.fields(f.Type, props, additional)
+			continue
+		case !f.IsExported():
+			continue
+		case name == "":
+			name = f.Name
+		}
+		props[name] = g.schema(f.Type)
+	}
+}
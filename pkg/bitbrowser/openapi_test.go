@@ -0,0 +1,67 @@
+package bitbrowser
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	data, err := OpenAPISpec()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]struct {
+			Post struct {
+				OperationID string `json:"operationId"`
+			} `json:"post"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties           map[string]any `json:"properties"`
+				AdditionalProperties bool           `json:"additionalProperties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if spec.OpenAPI != "3.0.3" {
+		t.Errorf("openapi = %q", spec.OpenAPI)
+	}
+	for path, ep := range endpoints {
+		if spec.Paths[path].Post.OperationID != ep.name {
+			t.Errorf("%s: operationId = %q, want %q", path, spec.Paths[path].Post.OperationID, ep.name)
+		}
+	}
+
+	t.Run("components", func(t *testing.T) {
+		config := spec.Components.Schemas["ProfileConfig"]
+		if _, ok := config.Properties["syncTabs"]; !ok {
+			t.Error("ProfileConfig lacks syncTabs")
+		}
+		if !config.AdditionalProperties {
+			t.Error("ProfileConfig should allow Extra properties")
+		}
+		partial := spec.Components.Schemas["PartialUpdateRequest"]
+		if _, ok := partial.Properties["ids"]; !ok {
+			t.Error("PartialUpdateRequest lacks ids")
+		}
+		if _, ok := partial.Properties["name"]; !ok {
+			t.Error("PartialUpdateRequest should include embedded ProfileConfig fields")
+		}
+	})
+
+	t.Run("references resolve", func(t *testing.T) {
+		for _, part := range strings.Split(string(data), `"#/components/schemas/`)[1:] {
+			name, _, _ := strings.Cut(part, `"`)
+			if _, ok := spec.Components.Schemas[name]; !ok {
+				t.Errorf("unresolved reference %q", name)
+			}
+		}
+	})
+}
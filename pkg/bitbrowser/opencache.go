@@ -0,0 +1,148 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultOpenCacheTTL is how long GetOrOpen reuses an open result by default.
+const defaultOpenCacheTTL = 5 * time.Minute
+
+// openCache holds the results of GetOrOpen by profile, and the opens in
+// flight so that concurrent callers share one.
+type openCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration // 0 means defaultOpenCacheTTL
+	entries map[string]openEntry
+	calls   map[string]*openCall
+}
+
+type openEntry struct {
+	result *OpenResult
+	at     time.Time
+}
+
+// openCall is an open in flight.
+type openCall struct {
+	done   chan struct{}
+	result *OpenResult
+	err    error
+}
+
+// WithOpenCacheTTL sets how long GetOrOpen reuses the result of an open
+// before opening again. Default is 5 minutes.
+func WithOpenCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.opens.ttl = ttl
+	}
+}
+
+// GetOrOpen returns the cached result of an earlier GetOrOpen for the profile
+// if it is younger than the TTL set with WithOpenCacheTTL and its debug
+// endpoint still answers (see VerifyDebugURL). Otherwise it opens the browser
+// with Open and caches the result. opts only apply when the browser is
+// opened.
+//
+// Concurrent calls for the same profile share one Open. If the caller that
+// started it gives up because its context is done, the others open anew.
+// Close and CloseAll drop cached results.
+//
+// Example:
+//
+//	// Many jobs for one profile, one browser
+//	result, err := client.GetOrOpen(ctx, profileID, nil)
+//	conn, err := cdp.Dial(ctx, result.Ws)
+func (c *Client) GetOrOpen(ctx context.Context, id string, opts *OpenOptions) (*OpenResult, error) {
+	if err := ValidateProfileID(id); err != nil {
+		return nil, err
+	}
+	for {
+		result, leader, err := c.getOrOpen(ctx, id, opts)
+		if leader || err == nil || ctx.Err() != nil ||
+			!(errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			return result, err
+		}
+		// The shared open was abandoned by the caller that started it
+	}
+}
+
+// getOrOpen is one attempt of GetOrOpen. leader reports whether this call
+// ran the open.
+func (c *Client) getOrOpen(ctx context.Context, id string, opts *OpenOptions) (result *OpenResult, leader bool, err error) {
+	p := &c.opens
+	p.mu.Lock()
+	if entry, ok := p.entries[id]; ok {
+		p.mu.Unlock()
+		if c.clock.Now().Sub(entry.at) < p.maxAge() && c.VerifyDebugURL(ctx, entry.result.Http) {
+			return copyOpenResult(entry.result), false, nil
+		}
+		p.mu.Lock()
+		if current, ok := p.entries[id]; ok && current == entry {
+			delete(p.entries, id)
+		}
+	}
+	if call, ok := p.calls[id]; ok {
+		p.mu.Unlock()
+		select {
+		case <-call.done:
+			return copyOpenResult(call.result), false, call.err
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+	call := &openCall{done: make(chan struct{})}
+	if p.calls == nil {
+		p.calls = make(map[string]*openCall)
+	}
+	p.calls[id] = call
+	p.mu.Unlock()
+
+	call.result, call.err = c.Open(ctx, id, opts)
+
+	p.mu.Lock()
+	delete(p.calls, id)
+	if call.err == nil {
+		if p.entries == nil {
+			p.entries = make(map[string]openEntry)
+		}
+		p.entries[id] = openEntry{result: call.result, at: c.clock.Now()}
+	}
+	p.mu.Unlock()
+	close(call.done)
+	return copyOpenResult(call.result), true, call.err
+}
+
+// forget drops the cached result of the profile id.
+func (p *openCache) forget(id string) {
+	p.mu.Lock()
+	delete(p.entries, id)
+	p.mu.Unlock()
+}
+
+// forgetAll drops all cached results.
+func (p *openCache) forgetAll() {
+	p.mu.Lock()
+	clear(p.entries)
+	p.mu.Unlock()
+}
+
+func (p *openCache) maxAge() time.Duration {
+	if p.ttl <= 0 {
+		return defaultOpenCacheTTL
+	}
+	return p.ttl
+}
+
+// copyOpenResult returns a copy of r, so callers sharing a result cannot
+// change each other's.
+func copyOpenResult(r *OpenResult) *OpenResult {
+	if r == nil {
+		return nil
+	}
+	cp := *r
+	cp.Warnings = slices.Clone(r.Warnings)
+	return &cp
+}
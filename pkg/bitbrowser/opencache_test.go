@@ -0,0 +1,175 @@
+package bitbrowser
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// openCacheServer is a fake API whose /browser/open results point back at
+// itself, so that their debug endpoints verify. release, if not nil, holds
+// opens until it is closed.
+func openCacheServer(t *testing.T, opens *atomic.Int32, release chan struct{}) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = mockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/browser/open":
+			opens.Add(1)
+			if release != nil {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+					return
+				}
+			}
+			w.Write(successResponse(OpenResult{Http: server.URL}))
+		case "/json/version":
+			w.Write([]byte(`{}`))
+		default:
+			w.Write(successResponse(nil))
+		}
+	})
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetOrOpen(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reuses a live result", func(t *testing.T) {
+		var opens atomic.Int32
+		client := mustNew(t, openCacheServer(t, &opens, nil).URL)
+
+		for range 3 {
+			if _, err := client.GetOrOpen(ctx, "profile-1", nil); err != nil {
+				t.Fatalf("GetOrOpen failed: %v", err)
+			}
+		}
+		if n := opens.Load(); n != 1 {
+			t.Errorf("opened %d times, want 1", n)
+		}
+	})
+
+	t.Run("reopens when the endpoint does not answer", func(t *testing.T) {
+		var opens atomic.Int32
+		client := mustNew(t, openCacheServer(t, &opens, nil).URL)
+		client.GetOrOpen(ctx, "profile-1", nil)
+
+		client.opens.mu.Lock()
+		entry := client.opens.entries["profile-1"]
+		entry.result.Http = "http://127.0.0.1:1"
+		client.opens.mu.Unlock()
+
+		if _, err := client.GetOrOpen(ctx, "profile-1", nil); err != nil {
+			t.Fatalf("GetOrOpen failed: %v", err)
+		}
+		if n := opens.Load(); n != 2 {
+			t.Errorf("opened %d times, want 2", n)
+		}
+	})
+
+	t.Run("reopens after the TTL", func(t *testing.T) {
+		var opens atomic.Int32
+		clock := NewFakeClock(time.Unix(0, 0))
+		client := mustNew(t, openCacheServer(t, &opens, nil).URL, WithClock(clock), WithOpenCacheTTL(time.Minute))
+
+		client.GetOrOpen(ctx, "profile-1", nil)
+		clock.Advance(59 * time.Second)
+		client.GetOrOpen(ctx, "profile-1", nil)
+		if n := opens.Load(); n != 1 {
+			t.Errorf("opened %d times within the TTL, want 1", n)
+		}
+		clock.Advance(time.Second)
+		client.GetOrOpen(ctx, "profile-1", nil)
+		if n := opens.Load(); n != 2 {
+			t.Errorf("opened %d times, want 2", n)
+		}
+	})
+
+	t.Run("Close drops the result", func(t *testing.T) {
+		var opens atomic.Int32
+		client := mustNew(t, openCacheServer(t, &opens, nil).URL)
+
+		client.GetOrOpen(ctx, "profile-1", nil)
+		if err := client.Close(ctx, "profile-1"); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		client.GetOrOpen(ctx, "profile-1", nil)
+		if n := opens.Load(); n != 2 {
+			t.Errorf("opened %d times, want 2", n)
+		}
+	})
+
+	t.Run("concurrent callers share one open", func(t *testing.T) {
+		var opens atomic.Int32
+		release := make(chan struct{})
+		client := mustNew(t, openCacheServer(t, &opens, release).URL)
+
+		var wg sync.WaitGroup
+		results := make([]*OpenResult, 5)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = client.GetOrOpen(ctx, "profile-1", nil)
+			}()
+		}
+		for opens.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(20 * time.Millisecond) // Let the others join
+		close(release)
+		wg.Wait()
+
+		if n := opens.Load(); n != 1 {
+			t.Errorf("opened %d times, want 1", n)
+		}
+		for i, r := range results {
+			if r == nil || r.Http == "" {
+				t.Fatalf("result %d = %+v", i, r)
+			}
+		}
+		if results[0] == results[1] {
+			t.Error("callers share one *OpenResult")
+		}
+	})
+
+	t.Run("callers open anew when the first gives up", func(t *testing.T) {
+		var opens atomic.Int32
+		release := make(chan struct{})
+		client := mustNew(t, openCacheServer(t, &opens, release).URL)
+
+		firstCtx, cancel := context.WithCancel(ctx)
+		firstDone := make(chan error, 1)
+		go func() {
+			_, err := client.GetOrOpen(firstCtx, "profile-1", nil)
+			firstDone <- err
+		}()
+		for opens.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		secondDone := make(chan error, 1)
+		go func() {
+			_, err := client.GetOrOpen(ctx, "profile-1", nil)
+			secondDone <- err
+		}()
+		time.Sleep(20 * time.Millisecond) // Let the second join
+		cancel()
+		if err := <-firstDone; err == nil {
+			t.Fatal("first caller succeeded, want its context error")
+		}
+		for opens.Load() < 2 {
+			time.Sleep(time.Millisecond)
+		}
+		close(release)
+		if err := <-secondDone; err != nil {
+			t.Errorf("second caller failed: %v", err)
+		}
+	})
+}
@@ -2,7 +2,11 @@ package bitbrowser
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"log/slog"
+	"net"
+	"net/http"
 	"time"
 )
 
@@ -35,16 +39,238 @@ func WithRetry(maxAttempts int) ClientOption {
 	}
 }
 
+// WithRetryBudget caps the total number of retries made by the client
+// (or by every client sharing the same budget). Once the budget is empty,
+// failing calls return immediately with a RetryError matching both
+// ErrRetryExhausted and ErrRetryBudgetExhausted.
+//
+// Example:
+//
+//	// At most 60 retries per minute across all goroutines
+//	client, err := bitbrowser.New(apiURL,
+//	    bitbrowser.WithRetry(3),
+//	    bitbrowser.WithRetryBudget(bitbrowser.NewRetryBudget(60, time.Minute)),
+//	)
+func WithRetryBudget(budget *RetryBudget) ClientOption {
+	return func(c *Client) {
+		c.retryBudget = budget
+	}
+}
+
+// WithEndpointRetryPolicy sets the retry configuration per endpoint,
+// overriding WithRetryConfig and WithRetry for the paths the policy
+// returns a configuration for. RetryReads retries reads but not mutations.
+//
+// Example:
+//
+//	client, err := bitbrowser.New(apiURL,
+//	    bitbrowser.WithEndpointRetryPolicy(func(path string) *bitbrowser.RetryConfig {
+//	        if path == "/browser/list" {
+//	            return &bitbrowser.RetryConfig{MaxAttempts: 5, BaseDelay: time.Second}
+//	        }
+//	        return nil // WithRetryConfig
+//	    }))
+func WithEndpointRetryPolicy(policy EndpointRetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithOpenBusyPolicy sets how Open reacts when BitBrowser reports that the
+// profile is busy. By default Open fails fast with an error matching ErrBusy.
+//
+// Example:
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithOpenBusyPolicy(bitbrowser.OpenBusyPolicy{
+//	    Wait:     true,
+//	    Interval: 3 * time.Second,
+//	    MaxWait:  2 * time.Minute,
+//	}))
+func WithOpenBusyPolicy(policy OpenBusyPolicy) ClientOption {
+	return func(c *Client) {
+		c.busyPolicy = policy
+	}
+}
+
+// WithClock sets the time source used for retry backoff, busy waiting and
+// readiness polling. Tests can pass a FakeClock to avoid real sleeps.
+// A nil clock is ignored.
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithAuditLogger records every mutating operation (CreateProfile,
+// DeleteProfile, UpdateProxy, SetCookies, ...) to the given AuditLogger.
+// Passwords, cookie values and other secrets are redacted from the
+// recorded parameters.
+//
+// Example:
+//
+//	audit, err := bitbrowser.OpenAuditLog("/var/log/bitbrowser-audit.jsonl")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer audit.Close()
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithAuditLogger(audit))
+//	ctx = bitbrowser.WithAuditActor(ctx, "worker-7")
+func WithAuditLogger(logger AuditLogger) ClientOption {
+	return func(c *Client) {
+		c.auditLogger = logger
+	}
+}
+
+// WithDryRun makes mutating calls (create, update, delete, open, close, ...)
+// log the request they would send and return a synthesized success without
+// contacting the API. Read-only calls still reach the API, so reconcile
+// scripts can be tested against production profile sets safely.
+//
+// In dry-run mode CreateProfile returns IDs of the form "dry-run-N" and Open
+// returns an empty OpenResult. Requests are logged at Info level with
+// secrets redacted, using slog.Default() if no logger is configured.
+func WithDryRun(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.dryRun = enabled
+	}
+}
+
+// WithPublicHost sets the host that replaces a wildcard address (0.0.0.0
+// or ::) in the endpoints Open returns, e.g. the BitBrowser machine's
+// address as seen through NAT. By default the API URL's host is used.
+func WithPublicHost(host string) ClientOption {
+	return func(c *Client) {
+		c.publicHost = host
+	}
+}
+
+// WithProfileLimit sets the account's profile limit (from the BitBrowser
+// plan), enabling GetQuota to report remaining capacity and CreateProfiles
+// to reject batches that would exceed it before creating anything.
+func WithProfileLimit(limit int) ClientOption {
+	return func(c *Client) {
+		c.profileLimit = limit
+	}
+}
+
+// WithHeader adds a header to every API request.
+// Use this for reverse proxies in front of BitBrowser that require bearer
+// tokens or other custom headers beyond x-api-key. Calling it multiple
+// times with the same key adds multiple values.
+//
+// Example:
+//
+//	client, err := bitbrowser.New(apiURL,
+//	    bitbrowser.WithHeader("Authorization", "Bearer "+token),
+//	)
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Add(key, value)
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every API request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Set("User-Agent", userAgent)
+	}
+}
+
+// WithRequestEditor registers a function that can modify every API request
+// right before it is sent, e.g. to sign requests or inject short-lived tokens.
+// Editors run in registration order, after all other headers are set.
+func WithRequestEditor(fn func(*http.Request)) ClientOption {
+	return func(c *Client) {
+		if fn != nil {
+			c.requestEditors = append(c.requestEditors, fn)
+		}
+	}
+}
+
+// WithDialContext sets a custom dialer for API requests.
+// Use this when the BitBrowser API is reached through a sidecar, tunnel or
+// Unix socket rather than a plain TCP address. The dialer is not used for
+// browser debug endpoints (VerifyDebugURL, GetBrowserVersion).
+//
+// The dialer is installed on a copy of the HTTP client's transport, which
+// must be nil or an *http.Transport; New returns an error otherwise.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) ClientOption {
+	return func(c *Client) {
+		c.dialContext = dial
+	}
+}
+
+// WithUnixSocket routes all API requests through the Unix socket at path.
+// The host in the API URL is still sent as the HTTP Host header, so use a
+// URL such as "http://localhost" or the name your proxy expects.
+//
+// Example:
+//
+//	client, err := bitbrowser.New("http://localhost",
+//	    bitbrowser.WithUnixSocket("/run/bitbrowser/api.sock"),
+//	)
+func WithUnixSocket(path string) ClientOption {
+	return WithDialContext(func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	})
+}
+
+// WithTLSConfig sets the TLS configuration for API requests and browser
+// debug endpoints (VerifyDebugURL, GetBrowserVersion).
+// Use this when BitBrowser is fronted by a TLS-terminating proxy.
+// The config is cloned; later changes by the caller have no effect.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = config.Clone()
+	}
+}
+
+// WithClientCertificate loads a PEM client certificate and key for mTLS.
+// The files are read when the client is created; New returns an error if
+// they cannot be loaded.
+//
+// Example:
+//
+//	client, err := bitbrowser.New("https://bitbrowser.internal:8443",
+//	    bitbrowser.WithClientCertificate("client.crt", "client.key"),
+//	    bitbrowser.WithCAFile("ca.crt"),
+//	)
+func WithClientCertificate(certFile, keyFile string) ClientOption {
+	return func(c *Client) {
+		c.tlsCertFile = certFile
+		c.tlsKeyFile = keyFile
+	}
+}
+
+// WithCAFile adds the PEM certificates in caFile to the trusted roots.
+// If WithTLSConfig provides RootCAs, the certificates are added to a copy of
+// that pool; otherwise they replace the system pool.
+func WithCAFile(caFile string) ClientOption {
+	return func(c *Client) {
+		c.tlsCAFile = caFile
+	}
+}
+
 // logRequest logs an outgoing request.
 func (c *Client) logRequest(ctx context.Context, method, path string, body any) {
 	if c.logger == nil {
 		return
 	}
 
-	c.logger.DebugContext(ctx, "bitbrowser: sending request",
+	c.logger.DebugContext(ctx, "bitbrowser: sending request", withRequestID(ctx, []any{
 		slog.String("method", method),
 		slog.String("path", path),
-	)
+	})...)
 }
 
 // logResponse logs a response from the API.
@@ -58,12 +284,12 @@ func (c *Client) logResponse(ctx context.Context, path string, statusCode int, d
 		level = slog.LevelWarn
 	}
 
-	c.logger.Log(ctx, level, "bitbrowser: received response",
+	c.logger.Log(ctx, level, "bitbrowser: received response", withRequestID(ctx, []any{
 		slog.String("path", path),
 		slog.Int("status_code", statusCode),
 		slog.Duration("duration", duration),
 		slog.Bool("success", success),
-	)
+	})...)
 }
 
 // logError logs an error.
@@ -81,7 +307,12 @@ func (c *Client) logError(ctx context.Context, path string, err error, attempt i
 		attrs = append(attrs, slog.Int("attempt", attempt))
 	}
 
-	c.logger.WarnContext(ctx, "bitbrowser: request failed", attrs...)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Body != "" {
+		attrs = append(attrs, slog.String("body", apiErr.Body))
+	}
+
+	c.logger.WarnContext(ctx, "bitbrowser: request failed", withRequestID(ctx, attrs)...)
 }
 
 // logRetry logs a retry attempt.
@@ -90,10 +321,24 @@ func (c *Client) logRetry(ctx context.Context, path string, attempt int, delay t
 		return
 	}
 
-	c.logger.InfoContext(ctx, "bitbrowser: retrying request",
+	c.logger.InfoContext(ctx, "bitbrowser: retrying request", withRequestID(ctx, []any{
 		slog.String("path", path),
 		slog.Int("attempt", attempt),
 		slog.Duration("delay", delay),
 		slog.String("previous_error", err.Error()),
-	)
+	})...)
+}
+
+// WithHedging enables hedged requests for idempotent read endpoints such as
+// GetPorts, GetProfileDetail and ListProfiles. A request that has not
+// completed after the hedge delay (by default the endpoint's recent P95
+// latency) is sent again, and the first response wins.
+//
+// Example:
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithHedging(bitbrowser.HedgingConfig{}))
+func WithHedging(config HedgingConfig) ClientOption {
+	return func(c *Client) {
+		c.hedging = newHedger(config)
+	}
 }
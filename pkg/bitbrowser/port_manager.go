@@ -1,8 +1,11 @@
 package bitbrowser
 
 import (
+	"context"
 	"fmt"
 	"math/rand/v2"
+	"net"
+	"strconv"
 )
 
 // PortManager handles port allocation in Managed Mode.
@@ -35,6 +38,12 @@ func NewPortManager(config *PortConfig, host string) (*PortManager, error) {
 	if host == "" {
 		return nil, fmt.Errorf("bitbrowser: host is required for Managed Mode port probing")
 	}
+	if err := config.validatePartition(); err != nil {
+		return nil, err
+	}
+	if err := config.ProbeMode.validate(); err != nil {
+		return nil, err
+	}
 	return &PortManager{config: config, host: host}, nil
 }
 
@@ -63,7 +72,8 @@ func (pm *PortManager) PickPortExcluding(excluded map[int]bool) (int, error) {
 
 	ports := pm.generateShuffledPorts()
 	if len(ports) == 0 {
-		return 0, fmt.Errorf("no ports in range [%d, %d]", pm.config.MinPort, pm.config.MaxPort)
+		minPort, maxPort := pm.config.Partition()
+		return 0, fmt.Errorf("no ports in range [%d, %d]", minPort, maxPort)
 	}
 
 	// Find first port not in excluded set
@@ -74,17 +84,69 @@ func (pm *PortManager) PickPortExcluding(excluded map[int]bool) (int, error) {
 		return port, nil
 	}
 
+	minPort, maxPort := pm.config.Partition()
 	return 0, fmt.Errorf("no available port in range [%d, %d]: all %d ports are excluded (BitBrowser is using them)",
-		pm.config.MinPort, pm.config.MaxPort, len(excluded))
+		minPort, maxPort, len(excluded))
+}
+
+// PickPortContext is like PickPortExcluding, but when the probe mode is
+// ProbeTCP or ProbeBoth it also skips ports that accept TCP connections on
+// the host, i.e. ports held by programs BitBrowser does not know about.
+func (pm *PortManager) PickPortContext(ctx context.Context, excluded map[int]bool) (int, error) {
+	if pm == nil || pm.config == nil || !pm.config.IsManaged() {
+		return 0, fmt.Errorf("port manager not configured")
+	}
+	if !pm.config.ProbeMode.usesTCP() {
+		return pm.PickPortExcluding(excluded)
+	}
+
+	inUse := 0
+	for _, port := range pm.generateShuffledPorts() {
+		if excluded[port] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		if pm.isPortAvailable(ctx, port) {
+			return port, nil
+		}
+		inUse++
+	}
+	minPort, maxPort := pm.config.Partition()
+	return 0, fmt.Errorf("no available port in range [%d, %d]: %d excluded by BitBrowser, %d accepting connections",
+		minPort, maxPort, len(excluded), inUse)
+}
+
+// isPortAvailable reports whether nothing accepts TCP connections on port.
+// A probe that times out counts as available: a firewall that drops the
+// probe would otherwise make every port look taken.
+func (pm *PortManager) isPortAvailable(ctx context.Context, port int) bool {
+	timeout := pm.config.ProbeTimeout
+	if timeout <= 0 {
+		timeout = DefaultProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(pm.host, strconv.Itoa(port)))
+	if err != nil {
+		return true
+	}
+	conn.Close()
+	return false
 }
 
-// generateShuffledPorts creates a randomly shuffled slice of all ports in the range.
+// generateShuffledPorts creates a randomly shuffled slice of all ports in
+// the range, or in this client's partition of it.
 func (pm *PortManager) generateShuffledPorts() []int {
-	size := pm.config.PortRangeSize()
+	minPort, maxPort := pm.config.Partition()
+	size := maxPort - minPort + 1
 	ports := make([]int, size)
 
 	for i := range size {
-		ports[i] = pm.config.MinPort + i
+		ports[i] = minPort + i
 	}
 
 	// Fisher-Yates shuffle
@@ -116,3 +178,54 @@ func (pm *PortManager) GetHost() string {
 	}
 	return pm.host
 }
+
+// BindAddress returns the --remote-debugging-address browsers should bind
+// so that they are reachable over the same address family as the API:
+// "::" when the API host is IPv6, "0.0.0.0" otherwise.
+func (pm *PortManager) BindAddress(ctx context.Context) string {
+	if pm == nil {
+		return "0.0.0.0"
+	}
+	return wildcardAddress(ctx, pm.host)
+}
+
+// Endpoint returns the HTTP debug endpoint of port on the managed host,
+// with IPv6 hosts bracketed, e.g. "http://[fd00::5]:50123".
+func (pm *PortManager) Endpoint(port int) string {
+	if pm == nil {
+		return ""
+	}
+	return "http://" + net.JoinHostPort(pm.host, strconv.Itoa(port))
+}
+
+// wildcardAddress returns the unspecified address of the family host is
+// reached over: "::" for IPv6 literals and names that resolve to IPv6
+// addresses only, "0.0.0.0" otherwise. IPv4 wins for dual-stack names, as
+// it does for dialing, and lookup failures fall back to IPv4.
+func wildcardAddress(ctx context.Context, host string) string {
+	if isIPv6Host(ctx, host) {
+		return "::"
+	}
+	return "0.0.0.0"
+}
+
+// isIPv6Host reports whether host is an IPv6 literal or a name with only
+// AAAA records.
+func isIPv6Host(ctx context.Context, host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.To4() == nil
+	}
+	if host == "localhost" {
+		return false
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return false
+	}
+	for _, addr := range addrs {
+		if addr.IP.To4() != nil {
+			return false
+		}
+	}
+	return true
+}
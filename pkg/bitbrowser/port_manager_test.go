@@ -1,6 +1,14 @@
 package bitbrowser
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
 	"testing"
 )
 
@@ -138,6 +146,15 @@ func TestNewPortManager(t *testing.T) {
 			t.Error("NewPortManager should return error for empty host")
 		}
 	})
+
+	t.Run("rejects unknown probe modes", func(t *testing.T) {
+		for _, mode := range []ProbeMode{"TCP", "tcp+api"} {
+			config := &PortConfig{MinPort: 50000, MaxPort: 51000, ProbeMode: mode}
+			if _, err := NewPortManager(config, "127.0.0.1"); !errors.Is(err, ErrValidation) {
+				t.Errorf("ProbeMode %q: err = %v, want ErrValidation", mode, err)
+			}
+		}
+	})
 }
 
 func TestPortManager_PickPort(t *testing.T) {
@@ -316,3 +333,253 @@ func TestWithPortRange(t *testing.T) {
 		}
 	})
 }
+
+// mockServer6 is like mockServer but listens on [::1]. It skips the test if
+// the host has no IPv6 loopback.
+func mockServer6(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	ln, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = ln
+	server.Start()
+	return server
+}
+
+func TestPortManagerIPv6(t *testing.T) {
+	config := &PortConfig{MinPort: 50000, MaxPort: 51000}
+
+	t.Run("BindAddress follows the host family", func(t *testing.T) {
+		tests := []struct {
+			host string
+			want string
+		}{
+			{"127.0.0.1", "0.0.0.0"},
+			{"10.0.0.5", "0.0.0.0"},
+			{"localhost", "0.0.0.0"},
+			{"::1", "::"},
+			{"fd00::5", "::"},
+			{"::ffff:10.0.0.5", "0.0.0.0"},
+		}
+		for _, tt := range tests {
+			pm := mustNewPortManager(t, config, tt.host)
+			if got := pm.BindAddress(context.Background()); got != tt.want {
+				t.Errorf("BindAddress() for %q = %q, want %q", tt.host, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("Endpoint brackets IPv6 hosts", func(t *testing.T) {
+		if got := mustNewPortManager(t, config, "fd00::5").Endpoint(50123); got != "http://[fd00::5]:50123" {
+			t.Errorf("Endpoint() = %q", got)
+		}
+		if got := mustNewPortManager(t, config, "10.0.0.5").Endpoint(50123); got != "http://10.0.0.5:50123" {
+			t.Errorf("Endpoint() = %q", got)
+		}
+	})
+
+	t.Run("New strips brackets from the API host", func(t *testing.T) {
+		client := mustNew(t, "http://[fd00::5]:54345", WithPortRange(50000, 51000))
+		if got := client.portManager.GetHost(); got != "fd00::5" {
+			t.Errorf("GetHost() = %q, want %q", got, "fd00::5")
+		}
+	})
+
+	t.Run("Managed Mode binds :: for an IPv6 API", func(t *testing.T) {
+		var args []string
+		server := mockServer6(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/browser/ports":
+				w.Write(successResponse(map[string]string{}))
+			case "/browser/open":
+				var config OpenConfig
+				json.NewDecoder(r.Body).Decode(&config)
+				args = config.Args
+				w.Write(successResponse(OpenResult{Ws: "ws://[::1]:50001/devtools/browser/abc", Http: "[::1]:50001"}))
+			}
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithPortRange(50000, 51000))
+		result, err := client.Open(context.Background(), "profile-1", nil)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if !slices.Contains(args, "--remote-debugging-address=::") {
+			t.Errorf("args = %v, want --remote-debugging-address=::", args)
+		}
+		if result.Http != "http://[::1]:50001" {
+			t.Errorf("Http = %q", result.Http)
+		}
+	})
+
+	t.Run("DebugURL keeps IPv6 hosts bracketed", func(t *testing.T) {
+		var port string
+		server := mockServer6(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/browser/ports":
+				w.Write(successResponse(map[string]string{"profile-1": port}))
+			case "/json/version":
+				w.Write([]byte(`{"webSocketDebuggerUrl":"ws://127.0.0.1:1/devtools/browser/abc"}`))
+			}
+		})
+		defer server.Close()
+		u, _ := url.Parse(server.URL)
+		port = u.Port()
+
+		got, err := mustNew(t, server.URL).DebugURL(context.Background(), "profile-1")
+		if err != nil {
+			t.Fatalf("DebugURL failed: %v", err)
+		}
+		if want := "ws://[::1]:" + port + "/devtools/browser/abc"; got != want {
+			t.Errorf("DebugURL() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestPickPortContext(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	busy := ln.Addr().(*net.TCPAddr).Port
+
+	t.Run("ProbeTCP skips ports that accept connections", func(t *testing.T) {
+		config := &PortConfig{MinPort: busy, MaxPort: busy, ProbeMode: ProbeTCP}
+		pm := mustNewPortManager(t, config, "127.0.0.1")
+		if port, err := pm.PickPortContext(context.Background(), nil); err == nil {
+			t.Errorf("PickPortContext() = %d, want error for a port in use", port)
+		}
+	})
+
+	t.Run("ProbeAPIOnly does not dial", func(t *testing.T) {
+		config := &PortConfig{MinPort: busy, MaxPort: busy, ProbeMode: ProbeAPIOnly}
+		pm := mustNewPortManager(t, config, "127.0.0.1")
+		port, err := pm.PickPortContext(context.Background(), nil)
+		if err != nil || port != busy {
+			t.Errorf("PickPortContext() = %d, %v; want %d", port, err, busy)
+		}
+	})
+
+	t.Run("ProbeBoth honors exclusions and probes", func(t *testing.T) {
+		free, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		freePort := free.Addr().(*net.TCPAddr).Port
+		free.Close()
+
+		config := &PortConfig{MinPort: min(busy, freePort), MaxPort: max(busy, freePort), ProbeMode: ProbeBoth}
+		pm := mustNewPortManager(t, config, "127.0.0.1")
+		excluded := make(map[int]bool)
+		for port := config.MinPort; port <= config.MaxPort; port++ {
+			if port != busy && port != freePort {
+				excluded[port] = true
+			}
+		}
+		port, err := pm.PickPortContext(context.Background(), excluded)
+		if err != nil || port != freePort {
+			t.Errorf("PickPortContext() = %d, %v; want %d", port, err, freePort)
+		}
+	})
+}
+
+func TestWithPortProbe(t *testing.T) {
+	var portsCalls int
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/browser/ports":
+			portsCalls++
+			w.Write(successResponse(map[string]string{}))
+		case "/browser/open":
+			w.Write(successResponse(OpenResult{Ws: "ws://127.0.0.1:50001/devtools/browser/abc", Http: "127.0.0.1:50001"}))
+		}
+	})
+	defer server.Close()
+
+	client := mustNew(t, server.URL, WithPortRange(50000, 51000), WithPortProbe(ProbeNone, 0))
+	if _, err := client.Open(context.Background(), "profile-1", nil); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if portsCalls != 0 {
+		t.Errorf("ProbeNone queried /browser/ports %d times", portsCalls)
+	}
+
+	client = mustNew(t, server.URL, WithPortRange(50000, 51000))
+	if _, err := client.Open(context.Background(), "profile-1", nil); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if portsCalls != 1 {
+		t.Errorf("default mode queried /browser/ports %d times, want 1", portsCalls)
+	}
+}
+
+func TestPortPartition(t *testing.T) {
+	t.Run("slices cover the range without overlap", func(t *testing.T) {
+		want := [][2]int{{50000, 50003}, {50004, 50006}, {50007, 50009}}
+		for i, bounds := range want {
+			config := &PortConfig{MinPort: 50000, MaxPort: 50009, PartitionIndex: i, PartitionCount: 3}
+			minPort, maxPort := config.Partition()
+			if minPort != bounds[0] || maxPort != bounds[1] {
+				t.Errorf("Partition(%d) = [%d, %d], want %v", i, minPort, maxPort, bounds)
+			}
+		}
+	})
+
+	t.Run("picks stay in the partition", func(t *testing.T) {
+		client := mustNew(t, "http://127.0.0.1:54345", WithPortRange(50000, 50009), WithPortPartition(1, 3))
+		for range 50 {
+			port, err := client.portManager.PickPortExcluding(nil)
+			if err != nil {
+				t.Fatalf("PickPortExcluding failed: %v", err)
+			}
+			if port < 50004 || port > 50006 {
+				t.Fatalf("picked %d outside [50004, 50006]", port)
+			}
+		}
+	})
+
+	t.Run("invalid partitions are rejected", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			index, count int
+		}{
+			{"negative count", 0, -1},
+			{"index out of range", 3, 3},
+			{"negative index", -1, 3},
+			{"more workers than ports", 0, 11},
+		}
+		for _, tt := range tests {
+			_, err := New("http://127.0.0.1:54345", WithPortRange(50000, 50009), WithPortPartition(tt.index, tt.count))
+			var vErr *ValidationError
+			if !errors.As(err, &vErr) {
+				t.Errorf("%s: err = %v, want *ValidationError", tt.name, err)
+			}
+		}
+	})
+
+	t.Run("Drain only owns its partition", func(t *testing.T) {
+		var closed []string
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/browser/ports":
+				w.Write(successResponse(map[string]string{"a": "50001", "b": "50005"}))
+			case "/browser/close":
+				var req map[string]string
+				json.NewDecoder(r.Body).Decode(&req)
+				closed = append(closed, req["id"])
+				w.Write(successResponse(nil))
+			}
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithPortRange(50000, 50009), WithPortPartition(1, 3))
+		n, err := NewHealthProbes(client).Drain(context.Background())
+		if err != nil || n != 1 || len(closed) != 1 || closed[0] != "b" {
+			t.Errorf("Drain() = %d, %v; closed %v, want [b]", n, err, closed)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// PortOf returns the debugging port of a running profile. It returns
+// ErrBrowserNotRunning if the profile has no debugging port.
+func (c *Client) PortOf(ctx context.Context, id string) (int, error) {
+	if err := ValidateProfileID(id); err != nil {
+		return 0, err
+	}
+	ports, err := c.GetPorts(ctx)
+	if err != nil {
+		return 0, err
+	}
+	port, ok := ports[id]
+	if !ok {
+		return 0, ErrBrowserNotRunning
+	}
+	return port, nil
+}
+
+// ProfileOnPort returns the ID of the profile whose browser listens on
+// port, e.g. to find the owner of a port that conflicts. It returns
+// ErrBrowserNotRunning if no open browser uses the port.
+func (c *Client) ProfileOnPort(ctx context.Context, port int) (string, error) {
+	ports, err := c.GetPorts(ctx)
+	if err != nil {
+		return "", err
+	}
+	for id, p := range ports {
+		if p == port {
+			return id, nil
+		}
+	}
+	return "", ErrBrowserNotRunning
+}
+
+// parsePorts converts the string ports of /browser/ports to numbers.
+// Empty ports belong to browsers that are still starting and are skipped;
+// anything else that is not a valid port is an error.
+func parsePorts(raw map[string]string) (map[string]int, error) {
+	ports := make(map[string]int, len(raw))
+	for id, s := range raw {
+		if s == "" {
+			continue
+		}
+		port, err := strconv.Atoi(s)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, NewAPIError("/browser/ports", 0, fmt.Sprintf("invalid port %q for profile %s", s, id))
+		}
+		ports[id] = port
+	}
+	return ports, nil
+}
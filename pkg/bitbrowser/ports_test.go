@@ -0,0 +1,58 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestPortOf(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(successResponse(map[string]string{"profile-1": "9222", "profile-2": ""}))
+	})
+	defer server.Close()
+	client := mustNew(t, server.URL)
+
+	port, err := client.PortOf(context.Background(), "profile-1")
+	if err != nil || port != 9222 {
+		t.Errorf("PortOf(profile-1) = %d, %v; want 9222", port, err)
+	}
+	if _, err := client.PortOf(context.Background(), "profile-2"); !errors.Is(err, ErrBrowserNotRunning) {
+		t.Errorf("PortOf(starting profile) err = %v, want ErrBrowserNotRunning", err)
+	}
+	if _, err := client.PortOf(context.Background(), "profile-3"); !errors.Is(err, ErrBrowserNotRunning) {
+		t.Errorf("PortOf(closed profile) err = %v, want ErrBrowserNotRunning", err)
+	}
+}
+
+func TestProfileOnPort(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(successResponse(map[string]string{"profile-1": "9222", "profile-2": "9223"}))
+	})
+	defer server.Close()
+	client := mustNew(t, server.URL)
+
+	id, err := client.ProfileOnPort(context.Background(), 9223)
+	if err != nil || id != "profile-2" {
+		t.Errorf("ProfileOnPort(9223) = %q, %v; want profile-2", id, err)
+	}
+	if _, err := client.ProfileOnPort(context.Background(), 9224); !errors.Is(err, ErrBrowserNotRunning) {
+		t.Errorf("ProfileOnPort(unused) err = %v, want ErrBrowserNotRunning", err)
+	}
+}
+
+func TestGetPortsInvalid(t *testing.T) {
+	for _, bad := range []string{"abc", "0", "70000"} {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(map[string]string{"profile-1": bad}))
+		})
+		_, err := mustNew(t, server.URL).GetPorts(context.Background())
+		server.Close()
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("GetPorts with port %q: err = %v, want *APIError", bad, err)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+	"github.com/lpg-it/go-antidetect/pkg/ua"
+)
+
+// RuntimeFingerprint is the fingerprint a running browser shows to pages.
+type RuntimeFingerprint struct {
+	UserAgent           string   `json:"userAgent"`
+	Platform            string   `json:"platform"` // navigator.platform, as Fingerprint.OS
+	Languages           []string `json:"languages"`
+	HardwareConcurrency int      `json:"hardwareConcurrency"`
+	DeviceMemory        float64  `json:"deviceMemory"` // 0 if not exposed, as in Firefox
+	DoNotTrack          string   `json:"doNotTrack"`   // "1", "0" or "" if unset
+	ScreenWidth         int      `json:"screenWidth"`
+	ScreenHeight        int      `json:"screenHeight"`
+	DevicePixelRatio    float64  `json:"devicePixelRatio"`
+	WebGLVendor         string   `json:"webglVendor"`   // Unmasked; empty without WebGL
+	WebGLRenderer       string   `json:"webglRenderer"` // Unmasked; empty without WebGL
+	TimeZone            string   `json:"timeZone"`
+	TimeZoneOffset      int      `json:"timeZoneOffset"` // As Date.getTimezoneOffset
+}
+
+// probeJS collects a RuntimeFingerprint.
+const probeJS = `(() => {
+	let webglVendor = "", webglRenderer = "";
+	try {
+		const gl = document.createElement("canvas").getContext("webgl");
+		const info = gl && gl.getExtension("WEBGL_debug_renderer_info");
+		if (info) {
+			webglVendor = gl.getParameter(info.UNMASKED_VENDOR_WEBGL);
+			webglRenderer = gl.getParameter(info.UNMASKED_RENDERER_WEBGL);
+		}
+	} catch (e) {}
+	return {
+		userAgent: navigator.userAgent,
+		platform: navigator.platform,
+		languages: navigator.languages,
+		hardwareConcurrency: navigator.hardwareConcurrency || 0,
+		deviceMemory: navigator.deviceMemory || 0,
+		doNotTrack: navigator.doNotTrack || "",
+		screenWidth: screen.width,
+		screenHeight: screen.height,
+		devicePixelRatio: window.devicePixelRatio,
+		webglVendor,
+		webglRenderer,
+		timeZone: Intl.DateTimeFormat().resolvedOptions().timeZone,
+		timeZoneOffset: new Date().getTimezoneOffset(),
+	};
+})()`
+
+// ProbeRuntimeFingerprint connects to a browser WebSocket URL (typically
+// OpenResult.Ws) and reads the fingerprint its first page shows: navigator
+// properties, the screen, the WebGL vendor and renderer, the time zone and
+// the languages. Compare the result with the stored fingerprint to catch
+// settings a BitBrowser or core update stopped applying.
+//
+// Example:
+//
+//	probe, err := bitbrowser.ProbeRuntimeFingerprint(ctx, result.Ws)
+//	for _, m := range probe.Compare(config.BrowserFingerPrint) {
+//	    log.Println(m)
+//	}
+func ProbeRuntimeFingerprint(ctx context.Context, ws string) (*RuntimeFingerprint, error) {
+	conn, err := cdp.Dial(ctx, ws)
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: probe fingerprint: %w", err)
+	}
+	defer conn.Close()
+	session, err := conn.AttachToPage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: probe fingerprint: %w", err)
+	}
+	var probe RuntimeFingerprint
+	if err := session.Evaluate(ctx, probeJS, &probe); err != nil {
+		return nil, fmt.Errorf("bitbrowser: probe fingerprint: %w", err)
+	}
+	return &probe, nil
+}
+
+// FingerprintMismatch is a fingerprint setting a running browser does not
+// show, as reported by RuntimeFingerprint.Compare.
+type FingerprintMismatch struct {
+	Field string // JSON name of the Fingerprint field
+	Want  string // Configured value
+	Got   string // Runtime value
+}
+
+// String describes the mismatch, e.g. for logs.
+func (m FingerprintMismatch) String() string {
+	return fmt.Sprintf("%s: configured %q, browser shows %q", m.Field, m.Want, m.Got)
+}
+
+// Compare returns the settings of fp that the browser does not show. Only
+// values fp pins are compared: settings left to BitBrowser, such as
+// IP-based time zones and languages or random WebGL, are skipped.
+func (r *RuntimeFingerprint) Compare(fp *Fingerprint) []FingerprintMismatch {
+	if fp == nil {
+		return nil
+	}
+	var mismatches []FingerprintMismatch
+	check := func(field, want, got string) {
+		if want != got {
+			mismatches = append(mismatches, FingerprintMismatch{Field: field, Want: want, Got: got})
+		}
+	}
+
+	if fp.UserAgent != "" {
+		check("userAgent", fp.UserAgent, r.UserAgent)
+	} else if fp.CoreVersion != "" {
+		if agent, err := ua.Parse(r.UserAgent); err == nil {
+			check("coreVersion", fp.CoreVersion, strconv.Itoa(agent.Major()))
+		}
+	}
+	if fp.OS != "" {
+		check("os", fp.OS, r.Platform)
+	}
+	if fp.IsIpCreateLanguage != nil && !*fp.IsIpCreateLanguage && fp.Languages != "" {
+		check("languages", fp.Languages, strings.Join(r.Languages, ","))
+	}
+	if fp.IsIpCreateTimeZone != nil && !*fp.IsIpCreateTimeZone && fp.TimeZone != "" {
+		check("timeZone", fp.TimeZone, r.TimeZone)
+		if fp.TimeZoneOffset != 0 {
+			check("timeZoneOffset", strconv.Itoa(fp.TimeZoneOffset), strconv.Itoa(r.TimeZoneOffset))
+		}
+	}
+	if fp.HardwareConcurrency != "" {
+		check("hardwareConcurrency", fp.HardwareConcurrency, strconv.Itoa(r.HardwareConcurrency))
+	}
+	if fp.DeviceMemory != "" && r.DeviceMemory != 0 {
+		check("deviceMemory", fp.DeviceMemory, strconv.FormatFloat(r.DeviceMemory, 'f', -1, 64))
+	}
+	if fp.DoNotTrack == "1" || (fp.DoNotTrack == "0" && r.DoNotTrack == "1") {
+		check("doNotTrack", fp.DoNotTrack, r.DoNotTrack)
+	}
+	if fp.ResolutionType == "1" && fp.Resolution != "" {
+		got := fmt.Sprintf("%d x %d", r.ScreenWidth, r.ScreenHeight)
+		if strings.ReplaceAll(fp.Resolution, " ", "") != strings.ReplaceAll(got, " ", "") {
+			check("resolution", fp.Resolution, got)
+		}
+	}
+	if fp.DevicePixelRatio != 0 {
+		check("devicePixelRatio", strconv.FormatFloat(fp.DevicePixelRatio, 'f', -1, 64),
+			strconv.FormatFloat(r.DevicePixelRatio, 'f', -1, 64))
+	}
+	if fp.WebGLMeta == "0" {
+		if fp.WebGLManufacturer != "" {
+			check("webGLManufacturer", fp.WebGLManufacturer, r.WebGLVendor)
+		}
+		if fp.WebGLRender != "" {
+			check("webGLRender", fp.WebGLRender, r.WebGLRenderer)
+		}
+	}
+	return mismatches
+}
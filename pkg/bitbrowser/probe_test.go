@@ -0,0 +1,78 @@
+package bitbrowser
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestProbeRuntimeFingerprint(t *testing.T) {
+	devtools := newFakeDevTools(t)
+	devtools.results = map[string]any{"Runtime.evaluate": map[string]any{
+		"result": map[string]any{"type": "object", "value": map[string]any{
+			"userAgent":           "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.0.0 Safari/537.36",
+			"platform":            "Win32",
+			"languages":           []string{"en-US", "en"},
+			"hardwareConcurrency": 8,
+			"deviceMemory":        8,
+			"screenWidth":         1920,
+			"screenHeight":        1080,
+			"devicePixelRatio":    1,
+			"webglVendor":         "Google Inc. (NVIDIA)",
+			"webglRenderer":       "ANGLE (NVIDIA, NVIDIA GeForce GTX 1060 Direct3D11 vs_5_0 ps_5_0, D3D11)",
+			"timeZone":            "America/New_York",
+			"timeZoneOffset":      240,
+		}},
+	}}
+
+	probe, err := ProbeRuntimeFingerprint(context.Background(), devtools.wsURL())
+	if err != nil {
+		t.Fatalf("ProbeRuntimeFingerprint failed: %v", err)
+	}
+	if probe.Platform != "Win32" || probe.ScreenWidth != 1920 || probe.TimeZone != "America/New_York" || len(probe.Languages) != 2 {
+		t.Errorf("probe = %+v", probe)
+	}
+	if !strings.Contains(strings.Join(devtools.calls(), ","), "Runtime.evaluate") {
+		t.Errorf("DevTools calls = %v", devtools.calls())
+	}
+
+	matching := &Fingerprint{
+		CoreVersion:         "130",
+		OS:                  "Win32",
+		IsIpCreateLanguage:  Bool(false),
+		Languages:           "en-US,en",
+		IsIpCreateTimeZone:  Bool(false),
+		TimeZone:            "America/New_York",
+		TimeZoneOffset:      240,
+		HardwareConcurrency: "8",
+		DeviceMemory:        "8",
+		ResolutionType:      "1",
+		Resolution:          "1920 x 1080",
+		DevicePixelRatio:    1,
+		WebGLMeta:           "0",
+		WebGLManufacturer:   "Google Inc. (NVIDIA)",
+	}
+	if m := probe.Compare(matching); len(m) != 0 {
+		t.Errorf("Compare(matching) = %v, want none", m)
+	}
+
+	drifted := &Fingerprint{
+		CoreVersion:         "131",
+		OS:                  "MacIntel",
+		Languages:           "de-DE", // IP-based languages are not compared
+		HardwareConcurrency: "4",
+		DoNotTrack:          "1",
+		ResolutionType:      "1",
+		Resolution:          "2560 x 1440",
+	}
+	var fields []string
+	for _, m := range probe.Compare(drifted) {
+		fields = append(fields, m.Field)
+	}
+	if got := strings.Join(fields, ","); got != "coreVersion,os,hardwareConcurrency,doNotTrack,resolution" {
+		t.Errorf("mismatched fields = %s", got)
+	}
+	if got := probe.Compare(drifted)[4].String(); got != `resolution: configured "2560 x 1440", browser shows "1920 x 1080"` {
+		t.Errorf("String() = %s", got)
+	}
+}
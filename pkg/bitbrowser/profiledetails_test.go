@@ -0,0 +1,109 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetProfileDetails(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		var req idBody
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.ID == "missing" {
+			w.Write(errorResponse("profile not found"))
+			return
+		}
+		// Later IDs answer sooner, so completion order differs from ID order
+		var i int
+		fmt.Sscanf(req.ID, "p%d", &i)
+		time.Sleep(time.Duration(10-i) * time.Millisecond)
+		w.Write(successResponse(ProfileDetail{ID: req.ID, Name: "name-" + req.ID}))
+	})
+	defer server.Close()
+	client := mustNew(t, server.URL)
+
+	t.Run("preserves order", func(t *testing.T) {
+		ids := []string{"p0", "p1", "p2", "p3", "p4", "p5", "p6", "p7", "p8", "p9"}
+		maxInFlight.Store(0)
+		details, err := client.GetProfileDetails(context.Background(), ids, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(details) != len(ids) {
+			t.Fatalf("got %d details, want %d", len(details), len(ids))
+		}
+		for i, d := range details {
+			if d == nil || d.ID != ids[i] || d.Name != "name-"+ids[i] {
+				t.Errorf("details[%d] = %+v, want %s", i, d, ids[i])
+			}
+		}
+		if m := maxInFlight.Load(); m > 3 {
+			t.Errorf("%d requests in flight, want at most 3", m)
+		}
+	})
+
+	t.Run("reports partial failures", func(t *testing.T) {
+		details, err := client.GetProfileDetails(context.Background(), []string{"p1", "missing", "p2"}, 0)
+		if details[0] == nil || details[1] != nil || details[2] == nil {
+			t.Errorf("details = %v, want only the missing profile nil", details)
+		}
+		errs := ProfileErrors(err)
+		if len(errs) != 1 || errs[0].ProfileID != "missing" {
+			t.Fatalf("ProfileErrors() = %v, want one for missing", errs)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.Message != "profile not found" {
+			t.Errorf("err = %v, want the API error", err)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		details, err := client.GetProfileDetails(context.Background(), nil, 4)
+		if len(details) != 0 || err != nil {
+			t.Errorf("GetProfileDetails(nil) = %v, %v", details, err)
+		}
+	})
+}
+
+func TestGetProfileDetailsCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer server.Close()
+	defer close(release)
+	client := mustNew(t, server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	details, err := client.GetProfileDetails(ctx, []string{"p1", "p2", "p3"}, 1)
+	if len(ProfileErrors(err)) != 3 {
+		t.Errorf("err = %v, want a failure per profile", err)
+	}
+	for i, d := range details {
+		if d != nil {
+			t.Errorf("details[%d] = %+v, want nil", i, d)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ValidateProfileID checks that id can be a profile ID, so that a mistake
+// such as an empty ID or one read from a file with its trailing newline
+// fails locally with ErrValidation instead of with a confusing message from
+// BitBrowser. The check is deliberately loose: BitBrowser IDs are 32
+// hexadecimal characters, but any non-empty, valid UTF-8 ID without spaces
+// or control characters is accepted.
+//
+// Every method that sends a profile ID checks it with ValidateProfileID.
+func ValidateProfileID(id string) error {
+	if id == "" {
+		return NewValidationError("id", "profile ID is required")
+	}
+	if i := strings.IndexFunc(id, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r) || r == utf8.RuneError
+	}); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(id[i:])
+		return &ValidationError{Field: "id", Message: fmt.Sprintf("profile ID has invalid character %q", r), Value: id}
+	}
+	return nil
+}
+
+// validateProfileIDs checks every ID of a batch with ValidateProfileID.
+func validateProfileIDs(ids []string) error {
+	for i, id := range ids {
+		if err := ValidateProfileID(id); err != nil {
+			var validationErr *ValidationError
+			errors.As(err, &validationErr)
+			return &ValidationError{Field: fmt.Sprintf("ids[%d]", i), Message: validationErr.Message, Value: id}
+		}
+	}
+	return nil
+}
+
+// Exists reports whether a profile exists. It returns false and no error
+// when BitBrowser answers that the profile does not exist, and an error if
+// it could not tell.
+//
+// Example:
+//
+//	if ok, err := client.Exists(ctx, id); err == nil && !ok {
+//	    id, err = client.CreateProfile(ctx, config)
+//	}
+func (c *Client) Exists(ctx context.Context, id string) (bool, error) {
+	detail, err := c.GetProfileDetail(ctx, id)
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.StatusCode == 0 && isNotFoundMessage(apiErr.Message):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return detail.ID != "", nil
+}
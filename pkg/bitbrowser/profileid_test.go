@@ -0,0 +1,102 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestValidateProfileID(t *testing.T) {
+	for _, id := range []string{"2c1f1b2c9f5d4a7e8b3c6d0e1f2a3b4c", "profile-1", "dry-run-3"} {
+		if err := ValidateProfileID(id); err != nil {
+			t.Errorf("ValidateProfileID(%q) = %v, want nil", id, err)
+		}
+	}
+	for _, id := range []string{"", " profile-1", "profile-1\n", "pro file", "profile\x00", "profile\xff"} {
+		err := ValidateProfileID(id)
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("ValidateProfileID(%q) = %v, want ErrValidation", id, err)
+		}
+	}
+
+	err := validateProfileIDs([]string{"p1", "p2 "})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "ids[1]" || validationErr.Value != "p2 " {
+		t.Errorf("validateProfileIDs() = %v, want error on ids[1]", err)
+	}
+}
+
+func TestInvalidProfileIDsFailLocally(t *testing.T) {
+	var requests atomic.Int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write(successResponse(nil))
+	})
+	defer server.Close()
+	client := mustNew(t, server.URL)
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"GetProfileDetail": func() error { _, err := client.GetProfileDetail(ctx, ""); return err },
+		"UpdateProfile":    func() error { return client.UpdateProfile(ctx, ProfileConfig{Name: "n"}) },
+		"DeleteProfile":    func() error { return client.DeleteProfile(ctx, "") },
+		"DeleteProfiles":   func() error { return client.DeleteProfiles(ctx, []string{"p1", ""}) },
+		"Open":             func() error { _, err := client.Open(ctx, "", nil); return err },
+		"OpenRaw":          func() error { _, err := client.OpenRaw(ctx, OpenConfig{}); return err },
+		"Close":            func() error { return client.Close(ctx, "p1\n") },
+		"GetAlivePIDs":     func() error { _, err := client.GetAlivePIDs(ctx, []string{""}); return err },
+		"PortOf":           func() error { _, err := client.PortOf(ctx, ""); return err },
+		"GetCookies":       func() error { _, err := client.GetCookies(ctx, ""); return err },
+		"UpdateProfilePartial": func() error {
+			return client.UpdateProfilePartial(ctx, PartialUpdateRequest{IDs: []string{""}})
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrValidation) {
+			t.Errorf("%s: err = %v, want ErrValidation", name, err)
+		}
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("%d requests sent, want none", n)
+	}
+}
+
+func TestExists(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req idBody
+		json.NewDecoder(r.Body).Decode(&req)
+		switch req.ID {
+		case "p1":
+			w.Write(successResponse(ProfileDetail{ID: "p1"}))
+		case "deleted":
+			w.Write(errorResponse("浏览器不存在"))
+		case "empty":
+			w.Write([]byte(`{"success":true,"data":null}`))
+		default:
+			w.Write(errorResponse("internal error"))
+		}
+	})
+	defer server.Close()
+	client := mustNew(t, server.URL)
+
+	tests := []struct {
+		id      string
+		want    bool
+		wantErr bool
+	}{
+		{"p1", true, false},
+		{"deleted", false, false},
+		{"empty", false, false},
+		{"other", false, true},
+		{"", false, true},
+	}
+	for _, tt := range tests {
+		got, err := client.Exists(context.Background(), tt.id)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("Exists(%q) = %v, %v; want %v, error %v", tt.id, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
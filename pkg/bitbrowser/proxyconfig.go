@@ -0,0 +1,123 @@
+package bitbrowser
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ProxyType is the protocol of a profile's proxy.
+type ProxyType string
+
+// Proxy types.
+const (
+	ProxyTypeNone   ProxyType = "noproxy" // Direct connection
+	ProxyTypeHTTP   ProxyType = "http"
+	ProxyTypeHTTPS  ProxyType = "https"
+	ProxyTypeSOCKS5 ProxyType = "socks5"
+	ProxyTypeSSH    ProxyType = "ssh"
+)
+
+// Workbench is what a profile shows on its workbench page when it opens.
+type Workbench string
+
+// Workbench settings.
+const (
+	WorkbenchLocalServer Workbench = "localserver"
+	WorkbenchDisable     Workbench = "disable"
+)
+
+// DynamicIpChannel is the provider of an extracted-IP proxy
+// (ProxyMethodExtract).
+type DynamicIpChannel string
+
+// Dynamic IP channels. BitBrowser adds providers over time, so other values
+// are sent as they are.
+const (
+	DynamicIpChannelRola    DynamicIpChannel = "rola"
+	DynamicIpChannelIpidea  DynamicIpChannel = "ipidea"
+	DynamicIpChannelDoveip  DynamicIpChannel = "doveip"
+	DynamicIpChannelCloudam DynamicIpChannel = "cloudam"
+	DynamicIpChannelCommon  DynamicIpChannel = "common"
+)
+
+// IpCheckService is the service BitBrowser asks for a proxy's exit IP.
+// Like DynamicIpChannel, other values are sent as they are.
+type IpCheckService string
+
+// IP check services.
+const (
+	IpCheckServiceIP123in  IpCheckService = "ip123in"
+	IpCheckServiceIPAPI    IpCheckService = "ip-api"
+	IpCheckServiceLuminati IpCheckService = "luminati"
+)
+
+// proxySettings are the proxy fields shared by ProfileConfig and
+// ProxyUpdateRequest.
+type proxySettings struct {
+	method       int
+	proxyType    ProxyType
+	host         string
+	port         int
+	password     string
+	dynamicIpUrl string
+}
+
+// validateProxy checks the proxy settings that BitBrowser would otherwise
+// reject or save as a profile that cannot connect:
+//
+//   - ProxyMethod is unset, ProxyMethodCustom or ProxyMethodExtract
+//   - ProxyType is unset or one of the ProxyType constants
+//   - ProxyMethodExtract has a DynamicIpUrl to extract from
+//   - a custom proxy other than ProxyTypeNone has a Host and a valid Port
+//   - an SSH proxy has a password, not a private key (see SSHProxy)
+func validateProxy(p proxySettings) error {
+	switch p.method {
+	case 0, ProxyMethodCustom, ProxyMethodExtract:
+	default:
+		return &ValidationError{Field: "proxyMethod", Message: "proxyMethod must be ProxyMethodCustom or ProxyMethodExtract", Value: strconv.Itoa(p.method)}
+	}
+	switch p.proxyType {
+	case "", ProxyTypeNone, ProxyTypeHTTP, ProxyTypeHTTPS, ProxyTypeSOCKS5, ProxyTypeSSH:
+	default:
+		return &ValidationError{Field: "proxyType", Message: "unknown proxy type", Value: string(p.proxyType)}
+	}
+
+	if p.method == ProxyMethodExtract {
+		if p.dynamicIpUrl == "" {
+			return NewValidationError("dynamicIpUrl", "dynamicIpUrl is required with ProxyMethodExtract")
+		}
+		return nil
+	}
+	if p.proxyType == "" || p.proxyType == ProxyTypeNone {
+		return nil
+	}
+	if p.host == "" {
+		return NewValidationError("host", fmt.Sprintf("host is required for a %s proxy", p.proxyType))
+	}
+	if p.port <= 0 || p.port > 65535 {
+		return &ValidationError{Field: "port", Message: "port must be between 1 and 65535", Value: strconv.Itoa(p.port)}
+	}
+	if p.proxyType == ProxyTypeSSH && isPrivateKey(p.password) {
+		// The key itself is left out of the error
+		return NewValidationError("proxyPassword", "BitBrowser ssh proxies only support password authentication, not private keys")
+	}
+	return nil
+}
+
+// validateConfig checks the proxy and workbench settings of a profile
+// before CreateProfile or UpdateProfile sends it.
+func validateConfig(config *ProfileConfig) error {
+	switch config.Workbench {
+	case "", WorkbenchLocalServer, WorkbenchDisable:
+	default:
+		return &ValidationError{Field: "workbench", Message: "workbench must be WorkbenchLocalServer or WorkbenchDisable", Value: string(config.Workbench)}
+	}
+	return validateProxy(proxySettings{
+		method:       config.ProxyMethod,
+		proxyType:    config.ProxyType,
+		host:         config.Host,
+		port:         config.Port,
+		password:     config.ProxyPassword,
+		dynamicIpUrl: config.DynamicIpUrl,
+	})
+}
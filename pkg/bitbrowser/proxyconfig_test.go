@@ -0,0 +1,86 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ProfileConfig
+		field  string
+	}{
+		{"no proxy settings", ProfileConfig{}, ""},
+		{"direct connection", ProfileConfig{ProxyMethod: ProxyMethodCustom, ProxyType: ProxyTypeNone}, ""},
+		{"custom proxy", ProfileConfig{ProxyMethod: ProxyMethodCustom, ProxyType: ProxyTypeSOCKS5, Host: "10.0.0.1", Port: 1080}, ""},
+		{"proxy without method", ProfileConfig{ProxyType: ProxyTypeHTTP, Host: "10.0.0.1", Port: 3128}, ""},
+		{"extract", ProfileConfig{ProxyMethod: ProxyMethodExtract, DynamicIpUrl: "https://ip.example/get", DynamicIpChannel: DynamicIpChannelCommon}, ""},
+		{"workbench", ProfileConfig{Workbench: WorkbenchDisable}, ""},
+		{"unknown channel passes", ProfileConfig{ProxyMethod: ProxyMethodExtract, DynamicIpUrl: "https://ip.example/get", DynamicIpChannel: "newvendor"}, ""},
+		{"extract without URL", ProfileConfig{ProxyMethod: ProxyMethodExtract, DynamicIpChannel: DynamicIpChannelRola}, "dynamicIpUrl"},
+		{"missing host", ProfileConfig{ProxyMethod: ProxyMethodCustom, ProxyType: ProxyTypeHTTP, Port: 8080}, "host"},
+		{"missing port", ProfileConfig{ProxyMethod: ProxyMethodCustom, ProxyType: ProxyTypeSSH, Host: "10.0.0.1"}, "port"},
+		{"port out of range", ProfileConfig{ProxyType: ProxyTypeHTTPS, Host: "10.0.0.1", Port: 70000}, "port"},
+		{"unknown method", ProfileConfig{ProxyMethod: 1}, "proxyMethod"},
+		{"unknown type", ProfileConfig{ProxyType: "socks4", Host: "10.0.0.1", Port: 1080}, "proxyType"},
+		{"unknown workbench", ProfileConfig{Workbench: "remote"}, "workbench"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateConfig(&tt.config)
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("validateConfig() = %v, want nil", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("validateConfig() = %v, want a %s error", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestProxyValidatedBeforeRequest(t *testing.T) {
+	called := false
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.Write(successResponse(map[string]string{"id": "new"}))
+	})
+	defer server.Close()
+	client := mustNew(t, server.URL)
+	ctx := context.Background()
+
+	_, err := client.CreateProfile(ctx, ProfileConfig{ProxyMethod: ProxyMethodExtract})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("CreateProfile() err = %v, want ErrValidation", err)
+	}
+	err = client.UpdateProfile(ctx, ProfileConfig{ID: "p1", ProxyType: ProxyTypeHTTP})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("UpdateProfile() err = %v, want ErrValidation", err)
+	}
+	err = client.UpdateProxy(ctx, ProxyUpdateRequest{IDs: []string{"p1"}, ProxyMethod: ProxyMethodExtract})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("UpdateProxy() err = %v, want ErrValidation", err)
+	}
+	if called {
+		t.Error("invalid settings were sent to BitBrowser")
+	}
+}
+
+func TestProxyTypeJSON(t *testing.T) {
+	data, err := json.Marshal(ProxyUpdateRequest{ProxyType: ProxyTypeSOCKS5, DynamicIpChannel: DynamicIpChannelIpidea})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	json.Unmarshal(data, &raw)
+	if raw["proxyType"] != "socks5" || raw["dynamicIpChannel"] != "ipidea" {
+		t.Errorf("encoded %s", data)
+	}
+}
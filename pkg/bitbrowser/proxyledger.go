@@ -0,0 +1,309 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProxyAssignment records the proxy of a profile and the exit IP it had
+// when it was assigned.
+type ProxyAssignment struct {
+	ProfileID string    `json:"profileId"`
+	GroupID   string    `json:"groupId,omitempty"`
+	Platform  string    `json:"platform,omitempty"`
+	Proxy     string    `json:"proxy"`          // "type://host:port", without credentials
+	ExitIP    string    `json:"exitIp"`         // From CheckProxy
+	Used      bool      `json:"used,omitempty"` // BitBrowser reported the exit IP as used before
+	Time      time.Time `json:"time"`
+}
+
+// ProxyLedgerStore persists proxy assignments by profile ID.
+// FileProxyLedgerStore keeps them in a JSON file.
+type ProxyLedgerStore interface {
+	// LoadAssignments returns all stored assignments keyed by profile ID.
+	LoadAssignments(ctx context.Context) (map[string]ProxyAssignment, error)
+
+	// SaveAssignment stores the assignment of a profile, or removes it if
+	// assignment is nil.
+	SaveAssignment(ctx context.Context, id string, assignment *ProxyAssignment) error
+}
+
+// FileProxyLedgerStore stores proxy assignments in a JSON file. It is safe
+// for concurrent use within one process.
+type FileProxyLedgerStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileProxyLedgerStore creates a ProxyLedgerStore backed by the JSON
+// file at path. The file is created on the first save.
+func NewFileProxyLedgerStore(path string) *FileProxyLedgerStore {
+	return &FileProxyLedgerStore{path: path}
+}
+
+// LoadAssignments reads the file. A missing file holds no assignments.
+func (s *FileProxyLedgerStore) LoadAssignments(ctx context.Context) (map[string]ProxyAssignment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// SaveAssignment updates the file, replacing it atomically.
+func (s *FileProxyLedgerStore) SaveAssignment(ctx context.Context, id string, assignment *ProxyAssignment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assignments, err := s.load()
+	if err != nil {
+		return err
+	}
+	if assignment == nil {
+		delete(assignments, id)
+	} else {
+		assignments[id] = *assignment
+	}
+
+	data, err := json.MarshalIndent(assignments, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("bitbrowser: save proxy ledger: %w", err)
+	}
+	return nil
+}
+
+// load reads the file; the caller holds mu.
+func (s *FileProxyLedgerStore) load() (map[string]ProxyAssignment, error) {
+	assignments := make(map[string]ProxyAssignment)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return assignments, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: load proxy ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, &assignments); err != nil {
+		return nil, fmt.Errorf("bitbrowser: load proxy ledger from %s: %w", s.path, err)
+	}
+	return assignments, nil
+}
+
+// ProxyCollision is an exit IP shared by several profiles of the same group
+// or platform.
+type ProxyCollision struct {
+	ExitIP     string   `json:"exitIp"`
+	Scope      string   `json:"scope"` // "group" or "platform"
+	Value      string   `json:"value"` // Group ID or platform
+	ProfileIDs []string `json:"profileIds"`
+}
+
+// ProxyLedger records which proxy and exit IP each profile uses, so that
+// profiles of the same group or platform never share an exit IP, which
+// links their accounts. Exit IPs come from CheckProxy.
+//
+// Example:
+//
+//	ledger := bitbrowser.NewProxyLedger(client, bitbrowser.NewFileProxyLedgerStore("proxies.json"))
+//	err := ledger.Assign(ctx, id, bitbrowser.ProxyUpdateRequest{
+//	    ProxyType: bitbrowser.ProxyTypeSOCKS5, Host: "10.0.0.9", Port: 1080,
+//	})
+//	if errors.Is(err, bitbrowser.ErrProxyInUse) {
+//	    // Pick another proxy
+//	}
+type ProxyLedger struct {
+	client *Client
+	store  ProxyLedgerStore
+	mu     sync.Mutex // Serializes Assign's check and save
+}
+
+// NewProxyLedger creates a ProxyLedger for the client's profiles, backed by
+// store.
+func NewProxyLedger(client *Client, store ProxyLedgerStore) *ProxyLedger {
+	return &ProxyLedger{client: client, store: store}
+}
+
+// Assign checks proxy to find its exit IP and, unless another profile of the
+// same group or platform has that exit IP, sets it as the profile's proxy
+// and records it; IDs is ignored. It fails with ErrProxyInUse on a
+// collision and with ErrProxyCheckFailed if the proxy does not work.
+// Uniqueness is checked within this process.
+func (l *ProxyLedger) Assign(ctx context.Context, id string, proxy ProxyUpdateRequest) error {
+	if err := ValidateProfileID(id); err != nil {
+		return err
+	}
+	if proxy.ProxyMethod == 0 {
+		proxy.ProxyMethod = ProxyMethodCustom
+	}
+	proxy.IDs = []string{id}
+	err := validateProxy(proxySettings{
+		method:    proxy.ProxyMethod,
+		proxyType: proxy.ProxyType,
+		host:      proxy.Host,
+		port:      proxy.Port,
+		password:  proxy.ProxyPassword,
+	})
+	if err != nil {
+		return err
+	}
+	if proxy.ProxyMethod != ProxyMethodCustom || proxy.Host == "" {
+		return NewValidationError("host", "the ledger only records custom proxies")
+	}
+
+	detail, err := l.client.GetProfileDetail(ctx, id)
+	if err != nil {
+		return err
+	}
+	assignment, err := l.check(ctx, detail, ProxyCheckRequest{
+		Host:           proxy.Host,
+		Port:           proxy.Port,
+		ProxyType:      proxy.ProxyType,
+		ProxyUserName:  proxy.ProxyUserName,
+		ProxyPassword:  proxy.ProxyPassword,
+		IpCheckService: proxy.IpCheckService,
+	})
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	assignments, err := l.store.LoadAssignments(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range slices.Sorted(maps.Keys(assignments)) {
+		if other == id {
+			continue
+		}
+		if scope := proxyConflict(*assignment, assignments[other]); scope != "" {
+			return fmt.Errorf("%w: exit IP %s is used by profile %s in the same %s", ErrProxyInUse, assignment.ExitIP, other, scope)
+		}
+	}
+	if err := l.client.UpdateProxy(ctx, proxy); err != nil {
+		return err
+	}
+	return l.store.SaveAssignment(ctx, id, assignment)
+}
+
+// Record checks the current proxies of the profiles and records them
+// without changing anything, for profiles whose proxies were set before the
+// ledger was used. Profiles without a proxy are released. Collisions among
+// them are then reported by Collisions; failures are reported per profile
+// as *ProfileError.
+func (l *ProxyLedger) Record(ctx context.Context, ids ...string) error {
+	if err := validateProfileIDs(ids); err != nil {
+		return err
+	}
+	var errs []error
+	for _, id := range ids {
+		if err := l.record(ctx, id); err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			errs = append(errs, &ProfileError{ProfileID: id, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// record records the current proxy of a profile.
+func (l *ProxyLedger) record(ctx context.Context, id string) error {
+	detail, err := l.client.GetProfileDetail(ctx, id)
+	if err != nil {
+		return err
+	}
+	if detail.Host == "" {
+		return l.Release(ctx, id)
+	}
+	assignment, err := l.check(ctx, detail, proxyCheckRequest(detail))
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.store.SaveAssignment(ctx, id, assignment)
+}
+
+// Release removes the assignment of a profile, e.g. after it was deleted.
+func (l *ProxyLedger) Release(ctx context.Context, id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.store.SaveAssignment(ctx, id, nil)
+}
+
+// Collisions reports the exit IPs that several profiles of the same group
+// or platform share, ordered by exit IP.
+func (l *ProxyLedger) Collisions(ctx context.Context) ([]ProxyCollision, error) {
+	assignments, err := l.store.LoadAssignments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	shared := make(map[[3]string][]string) // exit IP, scope, value -> profile IDs
+	for _, id := range slices.Sorted(maps.Keys(assignments)) {
+		a := assignments[id]
+		if a.ExitIP == "" {
+			continue
+		}
+		if a.GroupID != "" {
+			key := [3]string{a.ExitIP, "group", a.GroupID}
+			shared[key] = append(shared[key], id)
+		}
+		if a.Platform != "" {
+			key := [3]string{a.ExitIP, "platform", strings.ToLower(a.Platform)}
+			shared[key] = append(shared[key], id)
+		}
+	}
+
+	var collisions []ProxyCollision
+	for _, key := range slices.SortedFunc(maps.Keys(shared), func(a, b [3]string) int {
+		return slices.Compare(a[:], b[:])
+	}) {
+		if ids := shared[key]; len(ids) > 1 {
+			collisions = append(collisions, ProxyCollision{ExitIP: key[0], Scope: key[1], Value: key[2], ProfileIDs: ids})
+		}
+	}
+	return collisions, nil
+}
+
+// check runs a proxy check for a profile and returns its assignment.
+func (l *ProxyLedger) check(ctx context.Context, detail *ProfileDetail, req ProxyCheckRequest) (*ProxyAssignment, error) {
+	req.CheckExists = 1
+	result, err := l.client.CheckProxy(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success || result.Data.IP == "" {
+		return nil, fmt.Errorf("bitbrowser: check proxy %s:%d failed: %w", req.Host, req.Port, ErrProxyCheckFailed)
+	}
+	return &ProxyAssignment{
+		ProfileID: detail.ID,
+		GroupID:   detail.GroupID,
+		Platform:  detail.Platform,
+		Proxy:     fmt.Sprintf("%s://%s:%d", req.ProxyType, req.Host, req.Port),
+		ExitIP:    result.Data.IP,
+		Used:      result.Data.Used,
+		Time:      l.client.clock.Now(),
+	}, nil
+}
+
+// proxyConflict returns the scope, "group" or "platform", in which a and b
+// share an exit IP, or "" if they do not.
+func proxyConflict(a, b ProxyAssignment) string {
+	switch {
+	case a.ExitIP == "" || a.ExitIP != b.ExitIP:
+		return ""
+	case a.GroupID != "" && a.GroupID == b.GroupID:
+		return "group"
+	case a.Platform != "" && strings.EqualFold(a.Platform, b.Platform):
+		return "platform"
+	}
+	return ""
+}
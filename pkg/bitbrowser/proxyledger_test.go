@@ -0,0 +1,142 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// ledgerServer serves profiles with a group and platform, answers proxy
+// checks with the exit IP in exits, and records proxy updates.
+type ledgerServer struct {
+	mu       sync.Mutex
+	profiles map[string]ProfileDetail
+	exits    map[string]string // Proxy host -> exit IP
+	updates  int
+}
+
+func newLedger(t *testing.T) (*ledgerServer, *ProxyLedger) {
+	t.Helper()
+	s := &ledgerServer{
+		profiles: map[string]ProfileDetail{
+			"a1": {ID: "a1", GroupID: "ga", Platform: "https://www.facebook.com"},
+			"a2": {ID: "a2", GroupID: "ga"},
+			"b1": {ID: "b1", GroupID: "gb", Platform: "https://www.facebook.com"},
+			"c1": {ID: "c1", GroupID: "gc", Platform: "https://www.amazon.com"},
+		},
+		exits: map[string]string{"10.0.0.1": "203.0.113.1", "10.0.0.2": "203.0.113.1", "10.0.0.3": "203.0.113.3"},
+	}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ID   string `json:"id"`
+			Host string `json:"host"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch r.URL.Path {
+		case "/browser/detail":
+			w.Write(successResponse(s.profiles[body.ID]))
+		case "/checkagent":
+			ip, ok := s.exits[body.Host]
+			w.Write(successResponse(map[string]any{"success": ok, "data": map[string]any{"ip": ip}}))
+		case "/browser/proxy/update":
+			s.updates++
+			w.Write(successResponse(nil))
+		}
+	})
+	t.Cleanup(server.Close)
+	store := NewFileProxyLedgerStore(filepath.Join(t.TempDir(), "proxies.json"))
+	return s, NewProxyLedger(mustNew(t, server.URL), store)
+}
+
+func socks(host string) ProxyUpdateRequest {
+	return ProxyUpdateRequest{ProxyType: ProxyTypeSOCKS5, Host: host, Port: 1080}
+}
+
+func TestProxyLedgerAssign(t *testing.T) {
+	s, ledger := newLedger(t)
+	ctx := context.Background()
+
+	if err := ledger.Assign(ctx, "a1", socks("10.0.0.1")); err != nil {
+		t.Fatalf("Assign(a1) error = %v", err)
+	}
+	// Another host with the same exit IP, same group
+	if err := ledger.Assign(ctx, "a2", socks("10.0.0.2")); !errors.Is(err, ErrProxyInUse) {
+		t.Errorf("Assign(a2) err = %v, want ErrProxyInUse", err)
+	}
+	// Other group, same platform
+	if err := ledger.Assign(ctx, "b1", socks("10.0.0.1")); !errors.Is(err, ErrProxyInUse) {
+		t.Errorf("Assign(b1) err = %v, want ErrProxyInUse", err)
+	}
+	// Neither group nor platform shared
+	if err := ledger.Assign(ctx, "c1", socks("10.0.0.1")); err != nil {
+		t.Errorf("Assign(c1) error = %v", err)
+	}
+	// Reassigning the same profile is no collision
+	if err := ledger.Assign(ctx, "a1", socks("10.0.0.2")); err != nil {
+		t.Errorf("Assign(a1) again error = %v", err)
+	}
+	if err := ledger.Assign(ctx, "a2", socks("10.9.9.9")); !errors.Is(err, ErrProxyCheckFailed) {
+		t.Errorf("Assign(dead proxy) err = %v, want ErrProxyCheckFailed", err)
+	}
+	if s.updates != 3 {
+		t.Errorf("%d proxy updates, want 3", s.updates)
+	}
+
+	assignments, _ := ledger.store.LoadAssignments(ctx)
+	if a := assignments["a1"]; a.ExitIP != "203.0.113.1" || a.Proxy != "socks5://10.0.0.2:1080" || a.GroupID != "ga" {
+		t.Errorf("assignment = %+v", a)
+	}
+}
+
+func TestProxyLedgerRecordCollisions(t *testing.T) {
+	s, ledger := newLedger(t)
+	ctx := context.Background()
+	for id, host := range map[string]string{"a1": "10.0.0.1", "a2": "10.0.0.2", "b1": "10.0.0.2", "c1": "10.0.0.3"} {
+		p := s.profiles[id]
+		p.Host, p.Port, p.ProxyType = host, 1080, ProxyTypeSOCKS5
+		s.profiles[id] = p
+	}
+
+	if err := ledger.Record(ctx, "a1", "a2", "b1", "c1"); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if s.updates != 0 {
+		t.Errorf("Record changed %d proxies", s.updates)
+	}
+	collisions, err := ledger.Collisions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := fmt.Sprint(collisions)
+	want := "[{203.0.113.1 group ga [a1 a2]} {203.0.113.1 platform https://www.facebook.com [a1 b1]}]"
+	if got != want {
+		t.Errorf("Collisions() = %s, want %s", got, want)
+	}
+
+	if err := ledger.Release(ctx, "a2"); err != nil {
+		t.Fatal(err)
+	}
+	if collisions, _ := ledger.Collisions(ctx); len(collisions) != 1 {
+		t.Errorf("Collisions() after Release = %v, want 1", collisions)
+	}
+}
+
+func TestProxyLedgerRecordFailures(t *testing.T) {
+	s, ledger := newLedger(t)
+	p := s.profiles["a1"]
+	p.Host, p.Port, p.ProxyType = "10.9.9.9", 1080, ProxyTypeHTTP
+	s.profiles["a1"] = p
+
+	err := ledger.Record(context.Background(), "a1", "a2")
+	errs := ProfileErrors(err)
+	if len(errs) != 1 || errs[0].ProfileID != "a1" || !errors.Is(err, ErrProxyCheckFailed) {
+		t.Errorf("Record() err = %v, want a check failure for a1", err)
+	}
+}
@@ -0,0 +1,167 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ProxyPool is the set of proxies AssignProxyMatching picks from.
+type ProxyPool struct {
+	// Proxies are the candidates; their IDs are ignored.
+	Proxies []ProxyUpdateRequest
+
+	// LookupASN returns the autonomous system number of an exit IP, e.g.
+	// from a local ASN database. CheckProxy does not report it, so
+	// ProxyCriteria.ASN needs it.
+	LookupASN func(ctx context.Context, ip string) (int, error)
+
+	next atomic.Uint32 // Where the next search starts, to spread assignments
+}
+
+// WithProxyPool sets the proxies AssignProxyMatching picks from.
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithProxyPool(&bitbrowser.ProxyPool{
+//	    Proxies: proxies,
+//	}))
+func WithProxyPool(pool *ProxyPool) ClientOption {
+	return func(c *Client) {
+		c.proxyPool = pool
+	}
+}
+
+// ProxyCriteria selects proxies by where their exit IP is. Empty fields
+// match anything.
+type ProxyCriteria struct {
+	Country string // ISO code or name, e.g. "DE" or "Germany"
+	City    string
+	ASN     int // Needs ProxyPool.LookupASN
+}
+
+// String describes the criteria, e.g. "country=DE city=Berlin".
+func (c ProxyCriteria) String() string {
+	var parts []string
+	if c.Country != "" {
+		parts = append(parts, "country="+c.Country)
+	}
+	if c.City != "" {
+		parts = append(parts, "city="+c.City)
+	}
+	if c.ASN != 0 {
+		parts = append(parts, "asn="+strconv.Itoa(c.ASN))
+	}
+	if len(parts) == 0 {
+		return "any location"
+	}
+	return strings.Join(parts, " ")
+}
+
+// matches reports whether the exit IP of a check satisfies c; the ASN is
+// compared by the caller.
+func (c ProxyCriteria) matches(check *ProxyCheckResult) bool {
+	d := check.Data
+	return (c.Country == "" || strings.EqualFold(c.Country, d.CountryCode) || strings.EqualFold(c.Country, d.CountryName)) &&
+		(c.City == "" || strings.EqualFold(c.City, d.City))
+}
+
+// ProxyMatch is the proxy AssignProxyMatching applied.
+type ProxyMatch struct {
+	Proxy ProxyUpdateRequest
+	Check *ProxyCheckResult
+	ASN   int // 0 without ProxyPool.LookupASN or if the lookup failed
+}
+
+// AssignProxyMatching checks the proxies of the pool set with WithProxyPool
+// until one works and its exit IP matches criteria, and sets it as the
+// profile's proxy. Each call starts with the proxy after the one the
+// previous call began with, so that profiles are spread over the pool. It
+// fails with ErrNoMatchingProxy, saying how many proxies failed the check
+// and how many were elsewhere, if none qualifies.
+//
+// Example:
+//
+//	match, err := client.AssignProxyMatching(ctx, id, bitbrowser.ProxyCriteria{Country: "DE", City: "Berlin"})
+//	if errors.Is(err, bitbrowser.ErrNoMatchingProxy) {
+//	    // Buy more German proxies
+//	}
+func (c *Client) AssignProxyMatching(ctx context.Context, id string, criteria ProxyCriteria) (*ProxyMatch, error) {
+	if err := ValidateProfileID(id); err != nil {
+		return nil, err
+	}
+	if c.proxyPool == nil || len(c.proxyPool.Proxies) == 0 {
+		return nil, NewValidationError("ProxyPool", "no proxy pool configured; use WithProxyPool")
+	}
+	return c.assignProxyFrom(ctx, c.proxyPool, id, criteria)
+}
+
+// assignProxyFrom sets the first proxy of pool that works and matches
+// criteria as the profile's proxy.
+func (c *Client) assignProxyFrom(ctx context.Context, pool *ProxyPool, id string, criteria ProxyCriteria) (*ProxyMatch, error) {
+	if criteria.ASN != 0 && pool.LookupASN == nil {
+		return nil, NewValidationError("ASN", "matching by ASN needs ProxyPool.LookupASN")
+	}
+	match, err := c.findProxy(ctx, pool, criteria)
+	if err != nil {
+		return nil, err
+	}
+	req := match.Proxy
+	req.IDs = []string{id}
+	if err := c.UpdateProxy(ctx, req); err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+// findProxy returns the first proxy of the pool that works and matches
+// criteria.
+func (c *Client) findProxy(ctx context.Context, pool *ProxyPool, criteria ProxyCriteria) (*ProxyMatch, error) {
+	n := len(pool.Proxies)
+	start := int(pool.next.Add(1)-1) % n
+	var failed, elsewhere int
+	for i := range n {
+		proxy := pool.Proxies[(start+i)%n]
+		if proxy.ProxyMethod == 0 {
+			proxy.ProxyMethod = ProxyMethodCustom
+		}
+		check, err := c.CheckProxy(ctx, ProxyCheckRequest{
+			Host:           proxy.Host,
+			Port:           proxy.Port,
+			ProxyType:      proxy.ProxyType,
+			ProxyUserName:  proxy.ProxyUserName,
+			ProxyPassword:  proxy.ProxyPassword,
+			IpCheckService: proxy.IpCheckService,
+		})
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil || !check.Success {
+			failed++
+			continue
+		}
+		if !criteria.matches(check) {
+			elsewhere++
+			continue
+		}
+
+		match := &ProxyMatch{Proxy: proxy, Check: check}
+		if pool.LookupASN != nil {
+			asn, err := pool.LookupASN(ctx, check.Data.IP)
+			if err != nil && criteria.ASN != 0 {
+				failed++
+				continue
+			}
+			if err == nil {
+				match.ASN = asn
+			}
+		}
+		if criteria.ASN != 0 && match.ASN != criteria.ASN {
+			elsewhere++
+			continue
+		}
+		return match, nil
+	}
+	return nil, fmt.Errorf("%w for %s: %d of %d proxies failed the check, %d are elsewhere",
+		ErrNoMatchingProxy, criteria, failed, n, elsewhere)
+}
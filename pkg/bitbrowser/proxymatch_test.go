@@ -0,0 +1,108 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// geoServer answers proxy checks from the locations in geo, keyed by proxy
+// host; other hosts fail. It records the hosts set by proxy updates.
+func geoServer(t *testing.T, geo map[string][3]string, assigned *[]string) string {
+	t.Helper()
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Host string `json:"host"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/checkagent":
+			loc, ok := geo[body.Host]
+			w.Write(successResponse(map[string]any{"success": ok, "data": map[string]any{
+				"ip": loc[0], "countryCode": loc[1], "countryName": "Name of " + loc[1], "city": loc[2],
+			}}))
+		case "/browser/proxy/update":
+			*assigned = append(*assigned, body.Host)
+			w.Write(successResponse(nil))
+		}
+	})
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestAssignProxyMatching(t *testing.T) {
+	geo := map[string][3]string{
+		"us1": {"198.51.100.1", "US", "Dallas"},
+		"de1": {"203.0.113.1", "DE", "Munich"},
+		"de2": {"203.0.113.2", "DE", "Berlin"},
+		"de3": {"203.0.113.3", "DE", "Berlin"},
+	}
+	var proxies []ProxyUpdateRequest
+	for _, host := range []string{"dead", "us1", "de1", "de2", "de3"} {
+		proxies = append(proxies, ProxyUpdateRequest{ProxyType: ProxyTypeHTTP, Host: host, Port: 8080})
+	}
+	asns := map[string]int{"203.0.113.2": 3320, "203.0.113.3": 8881}
+	pool := &ProxyPool{Proxies: proxies, LookupASN: func(ctx context.Context, ip string) (int, error) {
+		return asns[ip], nil
+	}}
+	var assigned []string
+	client := mustNew(t, geoServer(t, geo, &assigned), WithProxyPool(pool))
+	ctx := context.Background()
+
+	match, err := client.AssignProxyMatching(ctx, "p1", ProxyCriteria{Country: "de", City: "berlin"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if match.Proxy.Host != "de2" || match.ASN != 3320 || match.Check.Data.IP != "203.0.113.2" {
+		t.Errorf("match = %+v", match)
+	}
+
+	match, err = client.AssignProxyMatching(ctx, "p2", ProxyCriteria{Country: "Name of DE", ASN: 8881})
+	if err != nil || match.Proxy.Host != "de3" {
+		t.Errorf("AssignProxyMatching(ASN) = %+v, %v", match, err)
+	}
+	if strings.Join(assigned, ",") != "de2,de3" {
+		t.Errorf("assigned %v, want [de2 de3]", assigned)
+	}
+
+	_, err = client.AssignProxyMatching(ctx, "p3", ProxyCriteria{Country: "FR"})
+	if !errors.Is(err, ErrNoMatchingProxy) || !strings.Contains(err.Error(), "1 of 5 proxies failed the check, 4 are elsewhere") {
+		t.Errorf("err = %v, want ErrNoMatchingProxy with counts", err)
+	}
+	if len(assigned) != 2 {
+		t.Errorf("assigned %v after a failed match", assigned)
+	}
+}
+
+func TestAssignProxyMatchingSpreads(t *testing.T) {
+	geo := map[string][3]string{"a": {"192.0.2.1", "US", ""}, "b": {"192.0.2.2", "US", ""}}
+	pool := &ProxyPool{Proxies: []ProxyUpdateRequest{
+		{ProxyType: ProxyTypeSOCKS5, Host: "a", Port: 1080},
+		{ProxyType: ProxyTypeSOCKS5, Host: "b", Port: 1080},
+	}}
+	var assigned []string
+	client := mustNew(t, geoServer(t, geo, &assigned), WithProxyPool(pool))
+	for _, id := range []string{"p1", "p2", "p3"} {
+		if _, err := client.AssignProxyMatching(context.Background(), id, ProxyCriteria{Country: "US"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if strings.Join(assigned, ",") != "a,b,a" {
+		t.Errorf("assigned %v, want proxies in turn", assigned)
+	}
+}
+
+func TestAssignProxyMatchingValidation(t *testing.T) {
+	ctx := context.Background()
+	client := mustNew(t, "http://127.0.0.1:1")
+	if _, err := client.AssignProxyMatching(ctx, "p1", ProxyCriteria{}); !errors.Is(err, ErrValidation) {
+		t.Errorf("without pool: err = %v, want ErrValidation", err)
+	}
+	client = mustNew(t, "http://127.0.0.1:1", WithProxyPool(&ProxyPool{Proxies: make([]ProxyUpdateRequest, 1)}))
+	if _, err := client.AssignProxyMatching(ctx, "p1", ProxyCriteria{ASN: 3320}); !errors.Is(err, ErrValidation) {
+		t.Errorf("ASN without lookup: err = %v, want ErrValidation", err)
+	}
+}
@@ -0,0 +1,315 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults of ProxyRotationConfig.
+const (
+	DefaultProxySessionTTL    = 10 * time.Minute
+	DefaultProxySessionLength = 8
+)
+
+// Reasons of a ProxyRotation.
+const (
+	RotateExpired = "expired" // The session reached its TTL
+	RotateNew     = "new"     // The profile had no session from this rotator yet
+)
+
+// proxySessionVars are the variables a ProxyRotationConfig.Proxy may use.
+var proxySessionVars = map[string]bool{"session": true, "profileId": true}
+
+// ProxyRotationConfig configures a ProxyRotator.
+type ProxyRotationConfig struct {
+	// Proxy is the proxy every rotation sets; IDs is ignored. Host,
+	// ProxyUserName and ProxyPassword may use variables:
+	//
+	//	{{session}}   the new session ID
+	//	{{profileId}} profile ID
+	//
+	// Most residential providers take the session in the user name, e.g.
+	// "customer-acme-session-{{session}}".
+	Proxy ProxyUpdateRequest
+
+	// TTL is how long a session lasts; a profile's session is replaced
+	// this long after it was set (default: DefaultProxySessionTTL). Set it
+	// a little below the provider's session lifetime.
+	TTL time.Duration
+
+	// NewSession returns a session ID for a profile (default:
+	// DefaultProxySessionLength random lowercase letters and digits).
+	NewSession func(profileID string) string
+
+	// Restart closes and reopens browsers that are running when their
+	// proxy is rotated, since a running browser keeps the proxy it was
+	// opened with. Without it, the new session applies from the next open.
+	Restart bool
+
+	// CloseTimeout bounds the close of a restart; see CloseAndWait
+	// (default: 30 seconds).
+	CloseTimeout time.Duration
+
+	// OpenOptions are used to reopen restarted browsers.
+	OpenOptions *OpenOptions
+
+	// OnRotate is called after every rotation, including failed ones.
+	OnRotate func(ProxyRotation)
+
+	// OnError is called by Run when a check fails.
+	OnError func(err error)
+
+	// Clock is the time source (default: SystemClock).
+	Clock Clock
+}
+
+// ProxyRotation reports the rotation of a profile's proxy session.
+type ProxyRotation struct {
+	ProfileID string    `json:"profileId"`
+	Session   string    `json:"session"`
+	Reason    string    `json:"reason"` // RotateExpired, RotateNew or the reason given to Rotate
+	Restarted bool      `json:"restarted"`
+	Time      time.Time `json:"time"`
+	Err       error     `json:"-"` // Why the update or restart failed
+}
+
+// ProxyRotator keeps profiles on fresh proxy sessions, for proxies whose
+// sessions expire after a while (sticky residential sessions). It sets a
+// new session ID through UpdateProxy when a profile's session reaches its
+// TTL, or right away when Rotate is called, e.g. after a block or captcha.
+//
+// Example:
+//
+//	rotator, err := bitbrowser.NewProxyRotator(client, bitbrowser.ProxyRotationConfig{
+//	    Proxy: bitbrowser.ProxyUpdateRequest{
+//	        ProxyType:     bitbrowser.ProxyTypeHTTP,
+//	        Host:          "gate.example-proxy.com",
+//	        Port:          7000,
+//	        ProxyUserName: "customer-acme-session-{{session}}",
+//	        ProxyPassword: "secret",
+//	    },
+//	    TTL:     25 * time.Minute,
+//	    Restart: true,
+//	})
+//	go rotator.Run(ctx, ids...)
+//
+//	// A worker that got blocked:
+//	rotator.Rotate(ctx, id, "captcha")
+type ProxyRotator struct {
+	client *Client
+	config ProxyRotationConfig
+
+	mu       sync.Mutex
+	rotated  map[string]time.Time // Last rotation of each profile
+	sessions map[string]string
+}
+
+// NewProxyRotator creates a ProxyRotator that updates the client's
+// profiles. It fails if config.Proxy uses unknown variables or is not a
+// valid proxy.
+func NewProxyRotator(client *Client, config ProxyRotationConfig) (*ProxyRotator, error) {
+	for field, value := range map[string]string{
+		"host":          config.Proxy.Host,
+		"proxyUserName": config.Proxy.ProxyUserName,
+		"proxyPassword": config.Proxy.ProxyPassword,
+	} {
+		for _, m := range launchArgVar.FindAllStringSubmatch(value, -1) {
+			if !proxySessionVars[m[1]] {
+				return nil, &ValidationError{Field: field, Message: "unknown proxy variable " + m[0]}
+			}
+		}
+	}
+	if config.Proxy.ProxyMethod == 0 {
+		config.Proxy.ProxyMethod = ProxyMethodCustom
+	}
+	err := validateProxy(proxySettings{
+		method:       config.Proxy.ProxyMethod,
+		proxyType:    config.Proxy.ProxyType,
+		host:         config.Proxy.Host,
+		port:         config.Proxy.Port,
+		dynamicIpUrl: config.Proxy.DynamicIpUrl,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if config.TTL <= 0 {
+		config.TTL = DefaultProxySessionTTL
+	}
+	if config.NewSession == nil {
+		config.NewSession = func(string) string { return randomSession(DefaultProxySessionLength) }
+	}
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+	return &ProxyRotator{
+		client:   client,
+		config:   config,
+		rotated:  make(map[string]time.Time),
+		sessions: make(map[string]string),
+	}, nil
+}
+
+// Session returns the current session ID of a profile, or "" if the
+// rotator has not set one.
+func (r *ProxyRotator) Session(id string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sessions[id]
+}
+
+// Check rotates the sessions of the profiles that reached their TTL, and
+// of those the rotator has not rotated yet, and returns a rotation for
+// each. Failed rotations are retried on the next Check.
+func (r *ProxyRotator) Check(ctx context.Context, ids ...string) ([]ProxyRotation, error) {
+	if err := validateProfileIDs(ids); err != nil {
+		return nil, err
+	}
+	now := r.config.Clock.Now()
+	var rotations []ProxyRotation
+	for _, id := range ids {
+		r.mu.Lock()
+		last, ok := r.rotated[id]
+		r.mu.Unlock()
+
+		reason := RotateNew
+		if ok {
+			if now.Sub(last) < r.config.TTL {
+				continue
+			}
+			reason = RotateExpired
+		}
+		rotations = append(rotations, r.rotate(ctx, id, reason))
+		if ctx.Err() != nil {
+			return rotations, ctx.Err()
+		}
+	}
+	return rotations, nil
+}
+
+// Rotate sets a new session for the profile right away, whatever the age of
+// its current one. reason is reported in the ProxyRotation, e.g. "blocked".
+// The returned error is the rotation's Err.
+func (r *ProxyRotator) Rotate(ctx context.Context, id, reason string) (ProxyRotation, error) {
+	if err := ValidateProfileID(id); err != nil {
+		return ProxyRotation{}, err
+	}
+	rotation := r.rotate(ctx, id, reason)
+	return rotation, rotation.Err
+}
+
+// rotate updates the profile's proxy with a new session and, with Restart,
+// restarts its running browser.
+func (r *ProxyRotator) rotate(ctx context.Context, id, reason string) ProxyRotation {
+	session := r.config.NewSession(id)
+	req := r.config.Proxy
+	req.IDs = []string{id}
+	req.Host = expandProxyVars(req.Host, id, session)
+	req.ProxyUserName = expandProxyVars(req.ProxyUserName, id, session)
+	req.ProxyPassword = expandProxyVars(req.ProxyPassword, id, session)
+
+	rotation := ProxyRotation{ProfileID: id, Session: session, Reason: reason}
+	rotation.Err = r.client.UpdateProxy(ctx, req)
+	if rotation.Err == nil {
+		r.mu.Lock()
+		r.rotated[id] = r.config.Clock.Now()
+		r.sessions[id] = session
+		r.mu.Unlock()
+		if r.config.Restart {
+			rotation.Restarted, rotation.Err = r.restart(ctx, id)
+		}
+	} else {
+		rotation.Err = fmt.Errorf("bitbrowser: rotate proxy session: %w", rotation.Err)
+	}
+	rotation.Time = r.config.Clock.Now()
+
+	if logger := r.client.logger; logger != nil {
+		logger.InfoContext(ctx, "bitbrowser: rotated proxy session", "id", id, "reason", reason, "restarted", rotation.Restarted, "error", rotation.Err)
+	}
+	if r.config.OnRotate != nil {
+		r.config.OnRotate(rotation)
+	}
+	return rotation
+}
+
+// restart closes and reopens the profile's browser if it is running, so
+// that it connects through the new session.
+func (r *ProxyRotator) restart(ctx context.Context, id string) (bool, error) {
+	pids, err := r.client.GetAlivePIDs(ctx, []string{id})
+	if err != nil {
+		return false, fmt.Errorf("bitbrowser: rotate proxy session: %w", err)
+	}
+	if _, running := pids[id]; !running {
+		return false, nil
+	}
+	if err := r.client.CloseAndWait(ctx, id, r.config.CloseTimeout); err != nil {
+		return false, fmt.Errorf("bitbrowser: rotate proxy session: restart: %w", err)
+	}
+	if _, err := r.client.Open(ctx, id, r.config.OpenOptions); err != nil {
+		return false, fmt.Errorf("bitbrowser: rotate proxy session: restart: %w", err)
+	}
+	return true, nil
+}
+
+// Run rotates the profiles' sessions as they expire until ctx is done and
+// then returns ctx.Err(). Each profile gets a new session when Run starts.
+// Failed checks are reported to OnError.
+func (r *ProxyRotator) Run(ctx context.Context, ids ...string) error {
+	for {
+		if _, err := r.Check(ctx, ids...); err != nil && r.config.OnError != nil && ctx.Err() == nil {
+			r.config.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.config.Clock.After(r.nextDue(ids)):
+		}
+	}
+}
+
+// nextDue returns the time until the first of the profiles' sessions
+// expires. Profiles without a session, such as after a failed rotation,
+// are due after a tenth of the TTL.
+func (r *ProxyRotator) nextDue(ids []string) time.Duration {
+	now := r.config.Clock.Now()
+	wait := r.config.TTL
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		last, ok := r.rotated[id]
+		if !ok {
+			wait = min(wait, r.config.TTL/10)
+			continue
+		}
+		wait = min(wait, last.Add(r.config.TTL).Sub(now))
+	}
+	return max(wait, time.Second)
+}
+
+// expandProxyVars resolves the variables of a ProxyRotationConfig.Proxy
+// field.
+func expandProxyVars(s, id, session string) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return launchArgVar.ReplaceAllStringFunc(s, func(v string) string {
+		if launchArgVar.FindStringSubmatch(v)[1] == "session" {
+			return session
+		}
+		return id
+	})
+}
+
+// randomSession returns n random lowercase letters and digits.
+func randomSession(n int) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[rand.IntN(len(alphabet))]
+	}
+	return string(b)
+}
@@ -0,0 +1,201 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// rotationServer records the proxy updates it receives and reports the
+// profiles in running as open.
+type rotationServer struct {
+	mu      sync.Mutex
+	updates []ProxyUpdateRequest
+	running map[string]bool
+	closed  []string
+	fail    bool
+}
+
+func newRotationServer(t *testing.T) (*rotationServer, *Client) {
+	t.Helper()
+	s := &rotationServer{running: make(map[string]bool)}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch r.URL.Path {
+		case "/browser/proxy/update":
+			if s.fail {
+				w.Write(errorResponse("proxy update failed"))
+				return
+			}
+			var req ProxyUpdateRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			s.updates = append(s.updates, req)
+			w.Write(successResponse(nil))
+		case "/browser/pids/alive":
+			pids := map[string]int{}
+			for id := range s.running {
+				pids[id] = 100
+			}
+			w.Write(successResponse(pids))
+		case "/browser/close":
+			var req idBody
+			json.NewDecoder(r.Body).Decode(&req)
+			s.closed = append(s.closed, req.ID)
+			delete(s.running, req.ID)
+			w.Write(successResponse(nil))
+		case "/browser/open":
+			var req idBody
+			json.NewDecoder(r.Body).Decode(&req)
+			s.running[req.ID] = true
+			w.Write(successResponse(OpenResult{Http: "127.0.0.1:9222"}))
+		default:
+			w.Write(successResponse(nil))
+		}
+	})
+	t.Cleanup(server.Close)
+	return s, mustNew(t, server.URL)
+}
+
+func (s *rotationServer) users() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var users []string
+	for _, u := range s.updates {
+		users = append(users, u.IDs[0]+":"+u.ProxyUserName)
+	}
+	return users
+}
+
+func rotationConfig(clock Clock) ProxyRotationConfig {
+	n := 0
+	return ProxyRotationConfig{
+		Proxy: ProxyUpdateRequest{
+			ProxyType:     ProxyTypeHTTP,
+			Host:          "gate.example.com",
+			Port:          7000,
+			ProxyUserName: "acme-{{profileId}}-session-{{ session }}",
+			ProxyPassword: "secret",
+		},
+		TTL:        10 * time.Minute,
+		NewSession: func(string) string { n++; return fmt.Sprint("s", n) },
+		Clock:      clock,
+	}
+}
+
+func TestProxyRotatorCheck(t *testing.T) {
+	s, client := newRotationServer(t)
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	rotator, err := NewProxyRotator(client, rotationConfig(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	rotations, err := rotator.Check(ctx, "p1", "p2")
+	if err != nil || len(rotations) != 2 || rotations[0].Reason != RotateNew {
+		t.Fatalf("first Check() = %+v, %v", rotations, err)
+	}
+	if got := fmt.Sprint(s.users()); got != "[p1:acme-p1-session-s1 p2:acme-p2-session-s2]" {
+		t.Errorf("updates = %s", got)
+	}
+	if s.updates[0].ProxyMethod != ProxyMethodCustom {
+		t.Errorf("ProxyMethod = %d, want ProxyMethodCustom", s.updates[0].ProxyMethod)
+	}
+
+	clock.Advance(5 * time.Minute)
+	if _, err := rotator.Rotate(ctx, "p2", "captcha"); err != nil {
+		t.Fatal(err)
+	}
+	if rotator.Session("p2") != "s3" {
+		t.Errorf("Session(p2) = %q, want s3", rotator.Session("p2"))
+	}
+
+	clock.Advance(5 * time.Minute)
+	rotations, _ = rotator.Check(ctx, "p1", "p2")
+	if len(rotations) != 1 || rotations[0].ProfileID != "p1" || rotations[0].Reason != RotateExpired {
+		t.Errorf("Check() after TTL = %+v, want only p1 expired", rotations)
+	}
+}
+
+func TestProxyRotatorFailure(t *testing.T) {
+	s, client := newRotationServer(t)
+	clock := NewFakeClock(time.Now())
+	var reported []ProxyRotation
+	config := rotationConfig(clock)
+	config.OnRotate = func(r ProxyRotation) { reported = append(reported, r) }
+	rotator, _ := NewProxyRotator(client, config)
+
+	s.fail = true
+	if _, err := rotator.Rotate(context.Background(), "p1", "blocked"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(reported) != 1 || reported[0].Err == nil || rotator.Session("p1") != "" {
+		t.Errorf("reported = %+v, session = %q", reported, rotator.Session("p1"))
+	}
+
+	s.fail = false
+	rotations, _ := rotator.Check(context.Background(), "p1")
+	if len(rotations) != 1 || rotations[0].Err != nil {
+		t.Errorf("Check() = %+v, want the failed profile rotated", rotations)
+	}
+}
+
+func TestProxyRotatorRestart(t *testing.T) {
+	s, client := newRotationServer(t)
+	s.running["p1"] = true
+	config := rotationConfig(NewFakeClock(time.Now()))
+	config.Restart = true
+	rotator, _ := NewProxyRotator(client, config)
+
+	rotations, err := rotator.Check(context.Background(), "p1", "p2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rotations[0].Restarted || rotations[1].Restarted {
+		t.Errorf("rotations = %+v, want only the running p1 restarted", rotations)
+	}
+	if len(s.closed) != 1 || !s.running["p1"] {
+		t.Errorf("closed = %v, running = %v", s.closed, s.running)
+	}
+}
+
+func TestProxyRotatorRun(t *testing.T) {
+	s, client := newRotationServer(t)
+	clock := NewFakeClock(time.Now())
+	rotator, _ := NewProxyRotator(client, rotationConfig(clock))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- rotator.Run(ctx, "p1") }()
+
+	clock.BlockUntil(1)
+	clock.Advance(10 * time.Minute)
+	clock.BlockUntil(1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+	if n := len(s.users()); n != 2 {
+		t.Errorf("%d rotations, want 2", n)
+	}
+}
+
+func TestNewProxyRotatorValidates(t *testing.T) {
+	client := mustNew(t, "http://127.0.0.1:1")
+	config := rotationConfig(nil)
+	config.Proxy.ProxyUserName = "user-{{sesion}}"
+	if _, err := NewProxyRotator(client, config); !errors.Is(err, ErrValidation) {
+		t.Errorf("unknown variable: err = %v, want ErrValidation", err)
+	}
+	config = rotationConfig(nil)
+	config.Proxy.Port = 0
+	if _, err := NewProxyRotator(client, config); !errors.Is(err, ErrValidation) {
+		t.Errorf("missing port: err = %v, want ErrValidation", err)
+	}
+}
@@ -0,0 +1,99 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+// ReadyHook sets up a browser after Open has launched it, through a
+// DevTools session attached to its first page: e.g. to set extra headers,
+// block resource types or add scripts that run in every document.
+type ReadyHook func(ctx context.Context, session *cdp.Session) error
+
+// WithReadyHook registers hooks that run after every Open, before the
+// hooks in OpenOptions.OnReady, so that all browsers are set up uniformly.
+//
+// Example:
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithReadyHook(
+//	    func(ctx context.Context, s *cdp.Session) error {
+//	        params := map[string]any{"urls": []string{"*.png", "*.jpg", "*.woff2"}}
+//	        if err := s.Call(ctx, "Network.enable", nil, nil); err != nil {
+//	            return err
+//	        }
+//	        return s.Call(ctx, "Network.setBlockedURLs", params, nil)
+//	    }))
+func WithReadyHook(hooks ...ReadyHook) ClientOption {
+	return func(c *Client) {
+		c.readyHooks = append(c.readyHooks, hooks...)
+	}
+}
+
+// runReadyHooks starts traffic accounting and runs the client's and opts'
+// ready hooks on one session. Settings made through a DevTools session
+// end with it, so on success the connection stays open until the browser
+// closes.
+func (c *Client) runReadyHooks(ctx context.Context, id string, result *OpenResult, opts *OpenOptions) error {
+	hooks := append(append([]ReadyHook(nil), c.readyHooks...), opts.OnReady...)
+	if len(hooks) == 0 && c.traffic == nil {
+		return nil
+	}
+	conn, err := cdp.Dial(ctx, result.Ws)
+	if err != nil {
+		return fmt.Errorf("bitbrowser: ready hooks: %w", err)
+	}
+	session, err := conn.AttachToPage(ctx)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("bitbrowser: ready hooks: %w", err)
+	}
+	if c.traffic != nil {
+		meter, err := session.MeterTraffic(ctx)
+		if err != nil {
+			conn.Close()
+			return fmt.Errorf("bitbrowser: traffic accounting: %w", err)
+		}
+		c.traffic.meter(id, meter, c.clock.Now())
+	}
+	for i, hook := range hooks {
+		if err := hook(ctx, session); err != nil {
+			conn.Close()
+			return fmt.Errorf("bitbrowser: ready hook %d failed: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// BlockResourcesHook returns a ReadyHook that blocks requests matching
+// rules for as long as the browser runs, e.g. to save bandwidth on metered
+// proxies.
+//
+// Example:
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithReadyHook(bitbrowser.BlockResourcesHook(cdp.BlockMedia)))
+func BlockResourcesHook(rules cdp.BlockRules) ReadyHook {
+	return func(ctx context.Context, session *cdp.Session) error {
+		_, err := session.BlockResources(ctx, rules)
+		return err
+	}
+}
+
+// PermissionsHook returns a ReadyHook that applies permission rules with
+// Session.SetPermissions, so that automation is not stopped by permission
+// prompts. Unlike ProfileConfig.DisableNotifications, which only blocks
+// notifications, rules can grant or deny any permission per origin.
+//
+// Example:
+//
+//	hook := bitbrowser.PermissionsHook(
+//	    cdp.PermissionRule{Permissions: []cdp.Permission{cdp.PermissionNotifications}, Setting: cdp.PermissionDenied},
+//	    cdp.PermissionRule{Origin: "https://maps.example.com", Permissions: []cdp.Permission{cdp.PermissionGeolocation}, Setting: cdp.PermissionGranted},
+//	)
+//	result, err := client.Open(ctx, id, &bitbrowser.OpenOptions{OnReady: []bitbrowser.ReadyHook{hook}})
+func PermissionsHook(rules ...cdp.PermissionRule) ReadyHook {
+	return func(ctx context.Context, session *cdp.Session) error {
+		return session.SetPermissions(ctx, rules...)
+	}
+}
@@ -0,0 +1,252 @@
+package bitbrowser
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+// fakeDevTools is a minimal DevTools WebSocket endpoint with one page. It
+// answers commands with the result set in results, or an empty one, and
+// records the methods.
+type fakeDevTools struct {
+	server  *httptest.Server
+	results map[string]any // By method; set before connecting
+
+	mu      sync.Mutex
+	methods []string
+	conn    net.Conn
+}
+
+func newFakeDevTools(t *testing.T) *fakeDevTools {
+	t.Helper()
+	d := &fakeDevTools{}
+	d.server = httptest.NewServer(http.HandlerFunc(d.serve))
+	t.Cleanup(d.server.Close)
+	return d
+}
+
+// wsURL returns the browser WebSocket URL.
+func (d *fakeDevTools) wsURL() string {
+	return "ws" + strings.TrimPrefix(d.server.URL, "http") + "/devtools/browser/fake"
+}
+
+func (d *fakeDevTools) calls() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.methods...)
+}
+
+func (d *fakeDevTools) serve(w http.ResponseWriter, r *http.Request) {
+	conn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	d.mu.Lock()
+	d.conn = conn
+	d.mu.Unlock()
+	sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	brw.Flush()
+
+	for {
+		payload, err := readClientFrame(brw.Reader)
+		if err != nil {
+			return
+		}
+		var msg struct {
+			ID        int64  `json:"id"`
+			Method    string `json:"method"`
+			SessionID string `json:"sessionId"`
+		}
+		json.Unmarshal(payload, &msg)
+		d.mu.Lock()
+		d.methods = append(d.methods, msg.Method)
+		d.mu.Unlock()
+
+		var result any = struct{}{}
+		if r, ok := d.results[msg.Method]; ok {
+			result = r
+		}
+		switch msg.Method {
+		case "Target.getTargets":
+			result = map[string]any{"targetInfos": []map[string]string{{"targetId": "P1", "type": "page"}}}
+		case "Target.attachToTarget":
+			result = map[string]string{"sessionId": "S1"}
+		}
+		resp, _ := json.Marshal(map[string]any{"id": msg.ID, "sessionId": msg.SessionID, "result": result})
+		if d.write(resp) != nil {
+			return
+		}
+	}
+}
+
+// emit sends an event for session S1.
+func (d *fakeDevTools) emit(method string, params any) error {
+	raw, _ := json.Marshal(map[string]any{"method": method, "sessionId": "S1", "params": params})
+	return d.write(raw)
+}
+
+// write sends a text frame to the connected client.
+func (d *fakeDevTools) write(payload []byte) error {
+	header := []byte{0x81, byte(len(payload))}
+	if len(payload) > 125 {
+		header = []byte{0x81, 126, byte(len(payload) >> 8), byte(len(payload))}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err := d.conn.Write(append(header, payload...))
+	return err
+}
+
+// readClientFrame reads a masked, unfragmented client frame.
+func readClientFrame(r *bufio.Reader) ([]byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return nil, err
+	}
+	if head[0]&0x0f == 0x8 {
+		return nil, io.EOF
+	}
+	n := uint64(head[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return nil, err
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return payload, nil
+}
+
+// devToolsClient opens browsers whose ws URL is devtools.
+func devToolsClient(t *testing.T, devtools *fakeDevTools, opts ...ClientOption) *Client {
+	t.Helper()
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(successResponse(OpenResult{Ws: devtools.wsURL()}))
+	})
+	t.Cleanup(server.Close)
+	return mustNew(t, server.URL, opts...)
+}
+
+func TestReadyHooks(t *testing.T) {
+	t.Run("runs client hooks then option hooks", func(t *testing.T) {
+		devtools := newFakeDevTools(t)
+		var order []string
+		hook := func(name, method string) ReadyHook {
+			return func(ctx context.Context, s *cdp.Session) error {
+				order = append(order, name)
+				return s.Call(ctx, method, nil, nil)
+			}
+		}
+		client := devToolsClient(t, devtools, WithReadyHook(hook("global", "Network.enable")))
+
+		_, err := client.Open(context.Background(), "profile-1", &OpenOptions{
+			OnReady: []ReadyHook{hook("call", "Page.enable")},
+		})
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if strings.Join(order, ",") != "global,call" {
+			t.Errorf("hook order = %v", order)
+		}
+		calls := strings.Join(devtools.calls(), ",")
+		if !strings.HasSuffix(calls, "Target.attachToTarget,Network.enable,Page.enable") {
+			t.Errorf("DevTools calls = %s", calls)
+		}
+	})
+
+	t.Run("failing hook returns the result with the error", func(t *testing.T) {
+		devtools := newFakeDevTools(t)
+		errBlocked := errors.New("blocked")
+		client := devToolsClient(t, devtools)
+		result, err := client.Open(context.Background(), "profile-1", &OpenOptions{
+			OnReady: []ReadyHook{func(ctx context.Context, s *cdp.Session) error { return errBlocked }},
+		})
+		if !errors.Is(err, errBlocked) || result == nil {
+			t.Errorf("Open() = %v, %v; want the result and the hook's error", result, err)
+		}
+	})
+
+	t.Run("headless start URL loads after the hooks", func(t *testing.T) {
+		devtools := newFakeDevTools(t)
+		client := devToolsClient(t, devtools, WithReadyHook(func(ctx context.Context, s *cdp.Session) error {
+			return s.Call(ctx, "Page.addScriptToEvaluateOnNewDocument", map[string]string{"source": "1"}, nil)
+		}))
+		result, err := client.Open(context.Background(), "profile-1", &OpenOptions{Headless: true, StartURL: "https://example.com/"})
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if len(result.Warnings) != 1 || result.Warnings[0].Code != WarnStartURLNavigated {
+			t.Errorf("Warnings = %v, want %s", result.Warnings, WarnStartURLNavigated)
+		}
+		calls := strings.Join(devtools.calls(), ",")
+		if strings.Index(calls, "Page.addScriptToEvaluateOnNewDocument") > strings.Index(calls, "Page.navigate") {
+			t.Errorf("DevTools calls = %s, want the hook before Page.navigate", calls)
+		}
+	})
+
+	t.Run("BlockResourcesHook", func(t *testing.T) {
+		devtools := newFakeDevTools(t)
+		client := devToolsClient(t, devtools)
+		_, err := client.Open(context.Background(), "profile-1", &OpenOptions{
+			OnReady: []ReadyHook{BlockResourcesHook(cdp.BlockImages)},
+		})
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if calls := devtools.calls(); calls[len(calls)-1] != "Fetch.enable" {
+			t.Errorf("DevTools calls = %v, want Fetch.enable last", calls)
+		}
+	})
+
+	t.Run("applies permission rules", func(t *testing.T) {
+		devtools := newFakeDevTools(t)
+		client := devToolsClient(t, devtools)
+		_, err := client.Open(context.Background(), "profile-1", &OpenOptions{
+			OnReady: []ReadyHook{PermissionsHook(cdp.PermissionRule{
+				Permissions: []cdp.Permission{cdp.PermissionNotifications, cdp.PermissionGeolocation},
+				Setting:     cdp.PermissionDenied,
+			})},
+		})
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if calls := strings.Join(devtools.calls(), ","); !strings.HasSuffix(calls, "Browser.setPermission,Browser.setPermission") {
+			t.Errorf("DevTools calls = %s", calls)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package bitbrowser
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BitBrowser has no endpoint that reports browsers as they start, so
+// readiness is polled with GetPorts. A client shares one polling loop
+// between all of its readiness waits: N browsers starting at once cost one
+// request per interval instead of N.
+
+// readyPoller is a client's shared GetPorts loop for readiness waits.
+type readyPoller struct {
+	mu      sync.Mutex
+	waiters map[*readyWaiter]struct{}
+	running bool
+	cancel  context.CancelFunc // Cancels the loop's pending GetPorts
+}
+
+// readyWaiter is one profile waiting for its debug port.
+type readyWaiter struct {
+	id       string
+	interval time.Duration
+	jitter   float64
+	deadline time.Time
+	port     chan int // Receives the port, or 0 once the deadline has passed
+}
+
+// waitForPort waits until GetPorts lists the profile id and returns its
+// port. Ports are checked every interval, moved by up to jitterFraction,
+// or more often if another wait asks for a shorter interval. Once timeout
+// has passed, the port is checked one last time before giving up with
+// ok false.
+func (c *Client) waitForPort(ctx context.Context, id string, interval, timeout time.Duration, jitterFraction float64) (port int, ok bool, err error) {
+	w := &readyWaiter{
+		id:       id,
+		interval: interval,
+		jitter:   jitterFraction,
+		deadline: c.clock.Now().Add(timeout),
+		port:     make(chan int, 1),
+	}
+	p := &c.ready
+	p.mu.Lock()
+	if p.waiters == nil {
+		p.waiters = make(map[*readyWaiter]struct{})
+	}
+	p.waiters[w] = struct{}{}
+	if !p.running {
+		p.running = true
+		go c.pollReady()
+	}
+	p.mu.Unlock()
+
+	select {
+	case port := <-w.port:
+		return port, port != 0, nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		delete(p.waiters, w)
+		if len(p.waiters) == 0 && p.cancel != nil {
+			p.cancel()
+		}
+		p.mu.Unlock()
+		return 0, false, ctx.Err()
+	}
+}
+
+// pollReady runs the shared loop until no waits are left.
+func (c *Client) pollReady() {
+	p := &c.ready
+	for {
+		p.mu.Lock()
+		if len(p.waiters) == 0 {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		var interval time.Duration
+		var jitterFraction float64
+		for w := range p.waiters {
+			if interval == 0 || w.interval < interval {
+				interval = w.interval
+			}
+			jitterFraction = max(jitterFraction, w.jitter)
+		}
+		p.mu.Unlock()
+
+		<-c.clock.After(jitter(interval, jitterFraction))
+
+		p.mu.Lock()
+		if len(p.waiters) == 0 {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		p.cancel = cancel
+		p.mu.Unlock()
+
+		// Errors are treated as "not ready yet", like a missing port
+		ports, _ := c.GetPorts(ctx)
+		cancel()
+		now := c.clock.Now()
+
+		p.mu.Lock()
+		p.cancel = nil
+		for w := range p.waiters {
+			port, ok := ports[w.id]
+			switch {
+			case ok:
+				w.port <- port
+			case !now.Before(w.deadline):
+				w.port <- 0
+			default:
+				continue
+			}
+			delete(p.waiters, w)
+		}
+		p.mu.Unlock()
+	}
+}
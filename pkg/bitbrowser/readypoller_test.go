@@ -0,0 +1,122 @@
+package bitbrowser
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// readyWaiters returns the number of readiness waits in c's shared loop.
+func readyWaiters(c *Client) int {
+	c.ready.mu.Lock()
+	defer c.ready.mu.Unlock()
+	return len(c.ready.waiters)
+}
+
+// awaitReadyWaiters blocks until c's shared loop has n readiness waits.
+func awaitReadyWaiters(t *testing.T, c *Client, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for readyWaiters(c) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d readiness waits, want %d", readyWaiters(c), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReadyPoller(t *testing.T) {
+	t.Run("multiplexes waits into one GetPorts loop", func(t *testing.T) {
+		var polls atomic.Int32
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			polls.Add(1)
+			w.Write(successResponse(map[string]string{"p1": "9221", "p2": "9222", "p3": "9223"}))
+		})
+		defer server.Close()
+		clock := NewFakeClock(time.Unix(0, 0))
+		client := mustNew(t, server.URL, WithClock(clock))
+
+		ports := make(chan int, 3)
+		for _, id := range []string{"p1", "p2", "p3"} {
+			go func() {
+				port, _, _ := client.waitForPort(context.Background(), id, time.Second, time.Minute, 0)
+				ports <- port
+			}()
+		}
+		awaitReadyWaiters(t, client, 3)
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+
+		sum := 0
+		for range 3 {
+			sum += <-ports
+		}
+		if sum != 9221+9222+9223 {
+			t.Errorf("ports sum to %d", sum)
+		}
+		if n := polls.Load(); n != 1 {
+			t.Errorf("GetPorts called %d times, want 1", n)
+		}
+	})
+
+	t.Run("gives up after the timeout", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(map[string]string{}))
+		})
+		defer server.Close()
+		clock := NewFakeClock(time.Unix(0, 0))
+		client := mustNew(t, server.URL, WithClock(clock))
+
+		done := make(chan bool, 1)
+		go func() {
+			_, ok, _ := client.waitForPort(context.Background(), "p1", 500*time.Millisecond, time.Second, 0)
+			done <- ok
+		}()
+		for range 2 {
+			clock.BlockUntil(1)
+			clock.Advance(500 * time.Millisecond)
+		}
+		if <-done {
+			t.Error("ok = true, want a timeout")
+		}
+	})
+
+	t.Run("stops when the last wait is canceled", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(map[string]string{}))
+		})
+		defer server.Close()
+		clock := NewFakeClock(time.Unix(0, 0))
+		client := mustNew(t, server.URL, WithClock(clock))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			_, _, err := client.waitForPort(ctx, "p1", time.Second, time.Minute, 0)
+			done <- err
+		}()
+		awaitReadyWaiters(t, client, 1)
+		cancel()
+		if err := <-done; err != context.Canceled {
+			t.Fatalf("err = %v, want context.Canceled", err)
+		}
+
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			client.ready.mu.Lock()
+			running := client.ready.running
+			client.ready.mu.Unlock()
+			if !running {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("loop still running")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+}
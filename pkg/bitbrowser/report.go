@@ -0,0 +1,362 @@
+package bitbrowser
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Report is a table for operations staff, written as CSV or as an Excel
+// workbook with a single sheet. Build one with ProfilesReport,
+// ProxyCheckReport or UsageReport, or fill it directly.
+//
+// Cells may be strings, integers, floats, bools or time.Time values; numbers
+// stay numeric in Excel so columns can be sorted and summed.
+type Report struct {
+	Title   string // Sheet name in Excel workbooks (default: "Report")
+	Headers []string
+	Rows    [][]any
+}
+
+// Save writes the report to path, as an Excel workbook if the extension is
+// ".xlsx" and as CSV otherwise.
+//
+// Example:
+//
+//	profiles, _ := client.ListProfiles(ctx, bitbrowser.ListRequest{PageSize: 100})
+//	err := bitbrowser.ProfilesReport(profiles.List).Save("profiles.xlsx")
+func (r *Report) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("bitbrowser: save report: %w", err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".xlsx") {
+		err = r.WriteXLSX(f)
+	} else {
+		err = r.WriteCSV(f)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("bitbrowser: save report %s: %w", path, err)
+	}
+	return nil
+}
+
+// WriteCSV writes the report as CSV with a header row. It starts with a
+// UTF-8 byte order mark so that Excel detects the encoding.
+func (r *Report) WriteCSV(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("\ufeff")
+	cw := csv.NewWriter(bw)
+	cw.Write(r.Headers)
+	for _, row := range r.Rows {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			record[i] = reportCell(cell)
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// WriteXLSX writes the report as an Excel workbook.
+func (r *Report) WriteXLSX(w io.Writer) error {
+	title := r.Title
+	if title == "" {
+		title = "Report"
+	}
+	title = strings.Map(func(c rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, c) {
+			return '_'
+		}
+		return c
+	}, title)
+	if len([]rune(title)) > 31 {
+		title = string([]rune(title)[:31]) // Excel's limit
+	}
+
+	var sheet bytes.Buffer
+	sheet.WriteString(xml.Header)
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	writeXLSXRow(&sheet, 1, stringCells(r.Headers))
+	for i, row := range r.Rows {
+		writeXLSXRow(&sheet, i+2, row)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	var escapedTitle bytes.Buffer
+	xml.EscapeText(&escapedTitle, []byte(title))
+	files := []struct{ name, body string }{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="` + escapedTitle.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+		{"xl/worksheets/sheet1.xml", sheet.String()},
+	}
+
+	zw := zip.NewWriter(w)
+	for _, file := range files {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, file.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// stringCells converts strings to report cells.
+func stringCells(values []string) []any {
+	cells := make([]any, len(values))
+	for i, v := range values {
+		cells[i] = v
+	}
+	return cells
+}
+
+// writeXLSXRow writes a sheet row. Numbers become numeric cells and
+// everything else inline strings.
+func writeXLSXRow(buf *bytes.Buffer, n int, cells []any) {
+	fmt.Fprintf(buf, `<row r="%d">`, n)
+	for i, cell := range cells {
+		ref := xlsxColumn(i) + strconv.Itoa(n)
+		if num, ok := reportNumber(cell); ok {
+			fmt.Fprintf(buf, `<c r="%s"><v>%s</v></c>`, ref, num)
+			continue
+		}
+		text := reportCell(cell)
+		if text == "" {
+			continue
+		}
+		fmt.Fprintf(buf, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		xml.EscapeText(buf, []byte(text))
+		buf.WriteString(`</t></is></c>`)
+	}
+	buf.WriteString(`</row>`)
+}
+
+// xlsxColumn returns the column letters of a zero-based column index.
+func xlsxColumn(i int) string {
+	var name []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = append([]byte{byte('A' + (i-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+// reportNumber formats numeric cells.
+func reportNumber(cell any) (string, bool) {
+	switch v := cell.(type) {
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// reportCell formats a cell as text.
+func reportCell(cell any) string {
+	if num, ok := reportNumber(cell); ok {
+		return num
+	}
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ProfilesReport lists profiles with their group, proxy and last known IP.
+// Proxy passwords are left out.
+func ProfilesReport(profiles []ProfileDetail) *Report {
+	r := &Report{
+		Title:   "Profiles",
+		Headers: []string{"Seq", "ID", "Name", "Group", "Remark", "Platform", "User", "Proxy Type", "Proxy", "Last IP", "Last Country", "Created"},
+	}
+	for _, p := range profiles {
+		proxy := ""
+		if p.Host != "" {
+			proxy = net.JoinHostPort(p.Host, strconv.Itoa(p.Port))
+			if p.ProxyUserName != "" {
+				proxy = p.ProxyUserName + "@" + proxy
+			}
+		}
+		r.Rows = append(r.Rows, []any{p.Seq, p.ID, p.Name, p.GroupID, p.Remark, p.Platform, p.UserName, p.ProxyType, proxy, p.LastIp, p.LastCountry, p.CreatedTime})
+	}
+	return r
+}
+
+// ProxyCheck is the outcome of checking a profile's proxy, as listed in a
+// ProxyCheckReport.
+type ProxyCheck struct {
+	ProfileID string
+	Request   ProxyCheckRequest
+	Result    *ProxyCheckResult // Nil if the check failed
+	Err       error
+	Time      time.Time
+}
+
+// CheckProfileProxies checks the proxy of each profile with CheckProxy.
+// Profiles without a proxy are left out; failed checks are returned with
+// their error rather than stopping the others.
+//
+// Example:
+//
+//	checks, err := client.CheckProfileProxies(ctx, ids)
+//	err = bitbrowser.ProxyCheckReport(checks).Save("proxies.xlsx")
+func (c *Client) CheckProfileProxies(ctx context.Context, ids []string) ([]ProxyCheck, error) {
+	if err := validateProfileIDs(ids); err != nil {
+		return nil, err
+	}
+	var checks []ProxyCheck
+	for _, id := range ids {
+		detail, err := c.GetProfileDetail(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return checks, err
+			}
+			checks = append(checks, ProxyCheck{ProfileID: id, Err: err, Time: c.clock.Now()})
+			continue
+		}
+		if detail.Host == "" {
+			continue
+		}
+		req := proxyCheckRequest(detail)
+		result, err := c.CheckProxy(ctx, req)
+		if err != nil && ctx.Err() != nil {
+			return checks, err
+		}
+		checks = append(checks, ProxyCheck{ProfileID: id, Request: req, Result: result, Err: err, Time: c.clock.Now()})
+	}
+	return checks, nil
+}
+
+// proxyCheckRequest returns the request that checks a profile's proxy.
+func proxyCheckRequest(detail *ProfileDetail) ProxyCheckRequest {
+	return ProxyCheckRequest{
+		Host:          detail.Host,
+		Port:          detail.Port,
+		ProxyType:     detail.ProxyType,
+		ProxyUserName: detail.ProxyUserName,
+		ProxyPassword: detail.ProxyPassword,
+	}
+}
+
+// ProxyCheckReport lists proxy check results with the exit IP and location.
+// Proxy passwords are left out.
+func ProxyCheckReport(checks []ProxyCheck) *Report {
+	r := &Report{
+		Title:   "Proxy Checks",
+		Headers: []string{"Profile ID", "Proxy Type", "Proxy", "OK", "IP", "Country", "Region", "City", "Time Zone", "Error", "Checked"},
+	}
+	for _, c := range checks {
+		proxy := ""
+		if c.Request.Host != "" {
+			proxy = net.JoinHostPort(c.Request.Host, strconv.Itoa(c.Request.Port))
+		}
+		row := []any{c.ProfileID, c.Request.ProxyType, proxy, false, "", "", "", "", "", "", c.Time}
+		if c.Result != nil {
+			d := c.Result.Data
+			row[3], row[4], row[5], row[6], row[7], row[8] = c.Result.Success, d.IP, d.CountryName, d.Region, d.City, d.TimeZone
+		}
+		if c.Err != nil {
+			row[9] = c.Err.Error()
+		}
+		r.Rows = append(r.Rows, row)
+	}
+	return r
+}
+
+// UsageReport lists the browser count and resource usage of fleet hosts,
+// as returned by FleetClient.Loads. Usage columns are percentages.
+//
+// Example:
+//
+//	loads, err := fleet.Loads(ctx)
+//	err = bitbrowser.UsageReport(loads).Save("usage.csv")
+func UsageReport(loads []HostLoad) *Report {
+	r := &Report{
+		Title:   "Usage",
+		Headers: []string{"Host", "Browsers", "Max Browsers", "CPU %", "Memory %", "Utilization %"},
+	}
+	for _, l := range loads {
+		row := []any{l.Host, l.Browsers, nil, nil, nil, l.Utilization() * 100}
+		if l.MaxBrowsers > 0 {
+			row[2] = l.MaxBrowsers
+		}
+		if l.Metrics != nil {
+			row[3], row[4] = l.Metrics.CPU, l.Metrics.Memory
+		}
+		r.Rows = append(r.Rows, row)
+	}
+	return r
+}
+
+// TrafficReport lists the traffic of profiles, as returned by
+// ListTrafficStats, for attributing proxy bandwidth. Byte columns are
+// exact; the MB column is rounded to two decimals.
+//
+// Example:
+//
+//	stats, err := client.ListTrafficStats()
+//	err = bitbrowser.TrafficReport(stats).Save("traffic.xlsx")
+func TrafficReport(stats []TrafficStats) *Report {
+	r := &Report{
+		Title:   "Traffic",
+		Headers: []string{"Profile ID", "Requests", "Bytes Sent", "Bytes Received", "Total MB", "Since"},
+	}
+	for _, s := range stats {
+		mb := math.Round(float64(s.BytesSent+s.BytesReceived)/(1<<20)*100) / 100
+		r.Rows = append(r.Rows, []any{s.ProfileID, s.Requests, s.BytesSent, s.BytesReceived, mb, s.Since})
+	}
+	return r
+}
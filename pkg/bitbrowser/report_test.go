@@ -0,0 +1,180 @@
+package bitbrowser
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	checked := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := &Report{
+		Title:   "Ops: weekly/report",
+		Headers: []string{"Name", "Count", "OK", "Checked", "Note"},
+		Rows: [][]any{
+			{"a<b>&c", 3, true, checked, nil},
+			{"émoji ✓", 2.5, false, time.Time{}, "line1\nline2"},
+		},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := r.WriteCSV(&buf); err != nil {
+			t.Fatalf("WriteCSV failed: %v", err)
+		}
+		data, ok := strings.CutPrefix(buf.String(), "\ufeff")
+		if !ok {
+			t.Error("CSV does not start with a byte order mark")
+		}
+		records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+		if err != nil {
+			t.Fatalf("reading CSV back failed: %v", err)
+		}
+		want := [][]string{
+			{"Name", "Count", "OK", "Checked", "Note"},
+			{"a<b>&c", "3", "yes", "2026-03-01 12:00:00", ""},
+			{"émoji ✓", "2.5", "no", "", "line1\nline2"},
+		}
+		if len(records) != len(want) {
+			t.Fatalf("records = %q", records)
+		}
+		for i := range want {
+			if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+				t.Errorf("record %d = %q, want %q", i, records[i], want[i])
+			}
+		}
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := r.WriteXLSX(&buf); err != nil {
+			t.Fatalf("WriteXLSX failed: %v", err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatalf("workbook is not a zip archive: %v", err)
+		}
+		files := make(map[string]string)
+		for _, f := range zr.File {
+			rc, _ := f.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			files[f.Name] = string(data)
+		}
+		for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"} {
+			if _, ok := files[name]; !ok {
+				t.Errorf("workbook lacks %s", name)
+			}
+		}
+		if !strings.Contains(files["xl/workbook.xml"], `name="Ops_ weekly_report"`) {
+			t.Errorf("sheet name not sanitized: %s", files["xl/workbook.xml"])
+		}
+		sheet := files["xl/worksheets/sheet1.xml"]
+		for _, want := range []string{
+			`<c r="A1" t="inlineStr"><is><t xml:space="preserve">Name</t></is></c>`,
+			`<t xml:space="preserve">a&lt;b&gt;&amp;c</t>`,
+			`<c r="B2"><v>3</v></c>`,
+			`<c r="B3"><v>2.5</v></c>`,
+			`<c r="C2" t="inlineStr"><is><t xml:space="preserve">yes</t></is></c>`,
+		} {
+			if !strings.Contains(sheet, want) {
+				t.Errorf("sheet lacks %s", want)
+			}
+		}
+	})
+
+	t.Run("save by extension", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, name := range []string{"r.xlsx", "r.csv"} {
+			path := filepath.Join(dir, name)
+			if err := r.Save(path); err != nil {
+				t.Fatalf("Save(%s) failed: %v", name, err)
+			}
+			data, _ := os.ReadFile(path)
+			if isZip := bytes.HasPrefix(data, []byte("PK")); isZip != strings.HasSuffix(name, ".xlsx") {
+				t.Errorf("%s written as zip = %v", name, isZip)
+			}
+		}
+	})
+}
+
+func TestXLSXColumn(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 701: "ZZ", 702: "AAA"} {
+		if got := xlsxColumn(i); got != want {
+			t.Errorf("xlsxColumn(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestReportBuilders(t *testing.T) {
+	profiles := ProfilesReport([]ProfileDetail{
+		{Seq: 1, ID: "p1", Name: "one", Host: "10.0.0.1", Port: 1080, ProxyType: "socks5", ProxyUserName: "u", ProxyPassword: "secret"},
+		{Seq: 2, ID: "p2", Name: "two"},
+	})
+	if len(profiles.Rows) != 2 || profiles.Rows[0][8] != "u@10.0.0.1:1080" || profiles.Rows[1][8] != "" {
+		t.Errorf("ProfilesReport rows = %v", profiles.Rows)
+	}
+	for _, row := range profiles.Rows {
+		for _, cell := range row {
+			if cell == "secret" {
+				t.Error("ProfilesReport includes the proxy password")
+			}
+		}
+	}
+
+	usage := UsageReport([]HostLoad{
+		{Host: "a", Browsers: 5, MaxBrowsers: 10, Metrics: &HostMetrics{CPU: 80, Memory: 40}},
+		{Host: "b", Browsers: 2},
+	})
+	if usage.Rows[0][5] != 80.0 || usage.Rows[1][2] != nil || usage.Rows[1][3] != nil {
+		t.Errorf("UsageReport rows = %v", usage.Rows)
+	}
+}
+
+func TestCheckProfileProxies(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		switch r.URL.Path {
+		case "/browser/detail":
+			switch req["id"] {
+			case "direct":
+				w.Write(successResponse(map[string]any{"id": "direct"}))
+			case "gone":
+				w.Write(errorResponse("profile not found"))
+			default:
+				w.Write(successResponse(map[string]any{"id": req["id"], "proxyType": "http", "host": "10.0.0.7", "port": 3128}))
+			}
+		case "/checkagent":
+			w.Write(successResponse(map[string]any{"success": true, "data": map[string]any{"ip": "203.0.113.9", "countryName": "Germany"}}))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	checks, err := mustNew(t, server.URL).CheckProfileProxies(context.Background(), []string{"p1", "direct", "gone"})
+	if err != nil {
+		t.Fatalf("CheckProfileProxies failed: %v", err)
+	}
+	if len(checks) != 2 || checks[0].ProfileID != "p1" || checks[1].ProfileID != "gone" || checks[1].Err == nil {
+		t.Fatalf("checks = %+v", checks)
+	}
+
+	rows := ProxyCheckReport(checks).Rows
+	if rows[0][2] != "10.0.0.7:3128" || rows[0][3] != true || rows[0][4] != "203.0.113.9" || rows[0][5] != "Germany" {
+		t.Errorf("row for p1 = %v", rows[0])
+	}
+	if rows[1][3] != false || rows[1][9] == "" {
+		t.Errorf("row for gone = %v", rows[1])
+	}
+}
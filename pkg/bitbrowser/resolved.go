@@ -0,0 +1,122 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ResolvedEndpoints are the parsed connection endpoints of an OpenResult.
+type ResolvedEndpoints struct {
+	Host string // Host the browser listens on, without brackets, e.g. "127.0.0.1", "0.0.0.0" or "::1"
+	Port int    // Debugging port
+	Http string // HTTP debug endpoint with scheme, e.g. "http://127.0.0.1:9222"
+	Ws   string // DevTools WebSocket URL with scheme; empty if BitBrowser did not report one
+}
+
+// Endpoints parses Ws and Http into host, port and normalized URLs, so
+// callers need not add "http://" prefixes or pick hosts apart themselves.
+// The WebSocket URL is preferred; Http is used when Ws is empty.
+//
+// Example:
+//
+//	result, _ := client.Open(ctx, id, nil)
+//	ep, err := result.Endpoints()
+//	if ep.Unspecified() {
+//	    wsURL = ep.DebugURLFor("10.0.0.5") // 0.0.0.0 is not dialable
+//	}
+func (r *OpenResult) Endpoints() (*ResolvedEndpoints, error) {
+	ws := strings.TrimSpace(r.Ws)
+	httpAddr := strings.TrimSpace(r.Http)
+	if ws == "" && httpAddr == "" {
+		return nil, NewValidationError("OpenResult", "no ws or http endpoint")
+	}
+
+	var hostport string
+	if ws != "" {
+		if !strings.Contains(ws, "://") {
+			ws = "ws://" + ws
+		}
+		u, err := url.Parse(ws)
+		if err != nil || u.Host == "" {
+			return nil, &ValidationError{Field: "Ws", Message: "invalid WebSocket URL", Value: r.Ws}
+		}
+		ws = u.String()
+		hostport = u.Host
+	} else {
+		hostport = strings.TrimPrefix(strings.TrimPrefix(httpAddr, "http://"), "https://")
+		hostport = strings.TrimRight(hostport, "/")
+	}
+
+	host, portStr, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return nil, &ValidationError{Field: "OpenResult", Message: fmt.Sprintf("invalid endpoint: %v", err), Value: hostport}
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port <= 0 || port > 65535 {
+		return nil, &ValidationError{Field: "OpenResult", Message: "invalid port", Value: portStr}
+	}
+	return &ResolvedEndpoints{
+		Host: host,
+		Port: port,
+		Http: "http://" + net.JoinHostPort(host, portStr),
+		Ws:   ws,
+	}, nil
+}
+
+// Unspecified reports whether the browser reported a wildcard host
+// (0.0.0.0 or ::), which is a bind address rather than one to dial.
+func (e *ResolvedEndpoints) Unspecified() bool {
+	ip := net.ParseIP(e.Host)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// DebugURLFor returns the WebSocket URL with its host replaced by host,
+// e.g. the BitBrowser machine's address when the browser reported
+// 0.0.0.0 or 127.0.0.1. IPv6 hosts are bracketed. It returns "" if no
+// WebSocket URL is known.
+func (e *ResolvedEndpoints) DebugURLFor(host string) string {
+	if e.Ws == "" {
+		return ""
+	}
+	u, err := url.Parse(e.Ws)
+	if err != nil {
+		return ""
+	}
+	u.Host = net.JoinHostPort(host, strconv.Itoa(e.Port))
+	return u.String()
+}
+
+// HttpFor returns the HTTP debug endpoint on host instead of Host.
+func (e *ResolvedEndpoints) HttpFor(host string) string {
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(e.Port))
+}
+
+// dialableResult replaces a wildcard host in result with the public host
+// and checks that the browser answers there. An unreachable endpoint is
+// returned as a *NetworkError alongside the result.
+func (c *Client) dialableResult(ctx context.Context, result *OpenResult) (*OpenResult, error) {
+	ep, err := result.Endpoints()
+	if err != nil || !ep.Unspecified() {
+		return result, nil
+	}
+	host := c.publicHost
+	if host == "" {
+		if host, err = extractHost(c.apiURL); err != nil {
+			return result, nil
+		}
+	}
+
+	result.Http = ep.HttpFor(host)
+	if ep.Ws != "" {
+		result.Ws = ep.DebugURLFor(host)
+	}
+	if !c.VerifyDebugURL(ctx, result.Http) {
+		return result, NewNetworkError("open", result.Http, errors.New("debug endpoint is not reachable"))
+	}
+	return result, nil
+}
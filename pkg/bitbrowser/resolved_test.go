@@ -0,0 +1,133 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestOpenResultEndpoints(t *testing.T) {
+	tests := []struct {
+		name   string
+		result OpenResult
+		want   ResolvedEndpoints
+	}{
+		{
+			name:   "ws and bare http",
+			result: OpenResult{Ws: "ws://127.0.0.1:9222/devtools/browser/abc", Http: "127.0.0.1:9222"},
+			want:   ResolvedEndpoints{Host: "127.0.0.1", Port: 9222, Http: "http://127.0.0.1:9222", Ws: "ws://127.0.0.1:9222/devtools/browser/abc"},
+		},
+		{
+			name:   "ws without scheme",
+			result: OpenResult{Ws: " 0.0.0.0:50001/devtools/browser/abc "},
+			want:   ResolvedEndpoints{Host: "0.0.0.0", Port: 50001, Http: "http://0.0.0.0:50001", Ws: "ws://0.0.0.0:50001/devtools/browser/abc"},
+		},
+		{
+			name:   "http only",
+			result: OpenResult{Http: "http://127.0.0.1:9222/"},
+			want:   ResolvedEndpoints{Host: "127.0.0.1", Port: 9222, Http: "http://127.0.0.1:9222"},
+		},
+		{
+			name:   "IPv6",
+			result: OpenResult{Ws: "ws://[::]:50001/devtools/browser/abc"},
+			want:   ResolvedEndpoints{Host: "::", Port: 50001, Http: "http://[::]:50001", Ws: "ws://[::]:50001/devtools/browser/abc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.result.Endpoints()
+			if err != nil {
+				t.Fatalf("Endpoints() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("Endpoints() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid results", func(t *testing.T) {
+		for _, r := range []OpenResult{{}, {Http: "127.0.0.1"}, {Ws: "ws://127.0.0.1:0/x"}} {
+			var vErr *ValidationError
+			if _, err := r.Endpoints(); !errors.As(err, &vErr) {
+				t.Errorf("Endpoints(%+v) err = %v, want *ValidationError", r, err)
+			}
+		}
+	})
+}
+
+func TestResolvedEndpointsFor(t *testing.T) {
+	ep, err := (&OpenResult{Ws: "ws://0.0.0.0:50001/devtools/browser/abc"}).Endpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ep.Unspecified() {
+		t.Error("Unspecified() = false for 0.0.0.0")
+	}
+	if got := ep.DebugURLFor("10.0.0.5"); got != "ws://10.0.0.5:50001/devtools/browser/abc" {
+		t.Errorf("DebugURLFor() = %q", got)
+	}
+	if got := ep.DebugURLFor("fd00::5"); got != "ws://[fd00::5]:50001/devtools/browser/abc" {
+		t.Errorf("DebugURLFor(IPv6) = %q", got)
+	}
+	if got := ep.HttpFor("10.0.0.5"); got != "http://10.0.0.5:50001" {
+		t.Errorf("HttpFor() = %q", got)
+	}
+
+	local, _ := (&OpenResult{Http: "127.0.0.1:9222"}).Endpoints()
+	if local.Unspecified() || local.DebugURLFor("10.0.0.5") != "" {
+		t.Errorf("unexpected endpoints for http-only result: %+v", local)
+	}
+}
+
+func TestOpenReplacesWildcardHost(t *testing.T) {
+	var port string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/browser/open":
+			w.Write(successResponse(OpenResult{Ws: "ws://0.0.0.0:" + port + "/devtools/browser/abc", Http: "0.0.0.0:" + port}))
+		case "/json/version":
+			w.Write([]byte(`{"Browser":"Chrome/130.0.0.0"}`))
+		}
+	})
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port = u.Port()
+
+	t.Run("API host", func(t *testing.T) {
+		result, err := mustNew(t, server.URL).Open(context.Background(), "profile-1", &OpenOptions{AllowLAN: true})
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if want := "ws://127.0.0.1:" + port + "/devtools/browser/abc"; result.Ws != want {
+			t.Errorf("Ws = %q, want %q", result.Ws, want)
+		}
+		if want := "http://127.0.0.1:" + port; result.Http != want {
+			t.Errorf("Http = %q, want %q", result.Http, want)
+		}
+	})
+
+	t.Run("public host", func(t *testing.T) {
+		result, err := mustNew(t, server.URL, WithPublicHost("localhost")).Open(context.Background(), "profile-1", nil)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if want := "ws://localhost:" + port + "/devtools/browser/abc"; result.Ws != want {
+			t.Errorf("Ws = %q, want %q", result.Ws, want)
+		}
+	})
+
+	t.Run("unreachable endpoint", func(t *testing.T) {
+		port = "1" // Nothing listens on port 1
+		defer func() { port = u.Port() }()
+		result, err := mustNew(t, server.URL).Open(context.Background(), "profile-1", nil)
+		var netErr *NetworkError
+		if !errors.As(err, &netErr) {
+			t.Fatalf("err = %v, want *NetworkError", err)
+		}
+		if result == nil || result.Ws != "ws://127.0.0.1:1/devtools/browser/abc" {
+			t.Errorf("result = %+v, want the rewritten endpoints", result)
+		}
+	})
+}
@@ -0,0 +1,289 @@
+package bitbrowser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// processSampleInterval is how long LocalProcessMonitor samples CPU usage for.
+const processSampleInterval = 200 * time.Millisecond
+
+// ProcessStats is the resource usage of a browser: its main process and all
+// of its descendants (renderers, GPU process, utilities).
+type ProcessStats struct {
+	PID       int     `json:"pid"`       // Main browser process
+	Processes int     `json:"processes"` // Processes in the tree
+	RSS       uint64  `json:"rss"`       // Resident memory, in bytes
+	CPU       float64 `json:"cpu"`       // CPU usage in percent of one core, sampled briefly
+}
+
+// ProcessMonitor reports the resource usage of browser processes on the
+// machine running BitBrowser. It is used by ResourceStats.
+type ProcessMonitor interface {
+	// ProcessStats returns the usage of each process tree rooted at one of
+	// pids. Processes that do not exist are left out.
+	ProcessStats(ctx context.Context, pids []int) (map[int]ProcessStats, error)
+}
+
+// ProcessMonitorFunc adapts a function to the ProcessMonitor interface.
+type ProcessMonitorFunc func(ctx context.Context, pids []int) (map[int]ProcessStats, error)
+
+// ProcessStats calls f(ctx, pids).
+func (f ProcessMonitorFunc) ProcessStats(ctx context.Context, pids []int) (map[int]ProcessStats, error) {
+	return f(ctx, pids)
+}
+
+// LocalProcessMonitor reads process usage on this machine, from /proc on
+// Linux and with PowerShell's Get-CimInstance on Windows; other systems are
+// not supported. It is only correct when BitBrowser runs on the same host as
+// the client. Use an AgentClient for remote hosts.
+var LocalProcessMonitor ProcessMonitor = ProcessMonitorFunc(localProcessStats)
+
+// WithProcessMonitor sets how ResourceStats reads the usage of browser
+// processes on the BitBrowser host.
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithProcessMonitor(bitbrowser.LocalProcessMonitor))
+func WithProcessMonitor(monitor ProcessMonitor) ClientOption {
+	return func(c *Client) {
+		c.processMonitor = monitor
+	}
+}
+
+// ResourceStats reports the memory and CPU usage of the profiles' browsers,
+// keyed by profile ID, with the ProcessMonitor set with WithProcessMonitor.
+// Without ids, all running browsers are reported. Profiles without a live
+// browser are left out.
+//
+// Example:
+//
+//	stats, err := client.ResourceStats(ctx, nil)
+//	for id, s := range stats {
+//	    fmt.Printf("%s: %d MiB, %.0f%% CPU\n", id, s.RSS>>20, s.CPU)
+//	}
+func (c *Client) ResourceStats(ctx context.Context, ids []string) (map[string]ProcessStats, error) {
+	if c.processMonitor == nil {
+		return nil, NewValidationError("ProcessMonitor", "no process monitor configured; use WithProcessMonitor")
+	}
+	if err := validateProfileIDs(ids); err != nil {
+		return nil, err
+	}
+	var pids map[string]int
+	var err error
+	if len(ids) == 0 {
+		pids, err = c.GetAllPIDs(ctx)
+	} else {
+		pids, err = c.GetAlivePIDs(ctx, ids)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]int, 0, len(pids))
+	for _, pid := range pids {
+		if pid > 0 {
+			list = append(list, pid)
+		}
+	}
+	if len(list) == 0 {
+		return map[string]ProcessStats{}, nil
+	}
+	byPID, err := c.processMonitor.ProcessStats(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("bitbrowser: resource stats: %w", err)
+	}
+
+	stats := make(map[string]ProcessStats, len(pids))
+	for id, pid := range pids {
+		if s, ok := byPID[pid]; ok {
+			stats[id] = s
+		}
+	}
+	return stats, nil
+}
+
+// processInfo is one process of a process table.
+type processInfo struct {
+	ppid int
+	rss  uint64        // Bytes
+	cpu  time.Duration // User and system time used so far
+}
+
+// localProcessStats samples the process table twice and sums each tree.
+func localProcessStats(ctx context.Context, pids []int) (map[int]ProcessStats, error) {
+	before, err := processTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(processSampleInterval):
+	}
+	after, err := processTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return treeStats(before, after, pids, time.Since(start)), nil
+}
+
+// treeStats sums the processes of each tree rooted at one of pids in after.
+// CPU usage is the CPU time the tree used between before and after, over
+// elapsed.
+func treeStats(before, after map[int]processInfo, pids []int, elapsed time.Duration) map[int]ProcessStats {
+	children := make(map[int][]int)
+	for pid, p := range after {
+		children[p.ppid] = append(children[p.ppid], pid)
+	}
+
+	stats := make(map[int]ProcessStats, len(pids))
+	for _, root := range pids {
+		if _, ok := after[root]; !ok {
+			continue
+		}
+		s := ProcessStats{PID: root}
+		var used time.Duration
+		seen := map[int]bool{}
+		queue := []int{root}
+		for len(queue) > 0 {
+			pid := queue[0]
+			queue = queue[1:]
+			if seen[pid] {
+				continue
+			}
+			seen[pid] = true
+			p := after[pid]
+			s.Processes++
+			s.RSS += p.rss
+			if prev, ok := before[pid]; ok && p.cpu > prev.cpu {
+				used += p.cpu - prev.cpu
+			}
+			queue = append(queue, children[pid]...)
+		}
+		if elapsed > 0 {
+			s.CPU = 100 * float64(used) / float64(elapsed)
+		}
+		stats[root] = s
+	}
+	return stats
+}
+
+// processTable lists the processes of this machine.
+func processTable(ctx context.Context) (map[int]processInfo, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return procTable()
+	case "windows":
+		return cimProcessTable(ctx)
+	}
+	return nil, fmt.Errorf("process stats on %s: %w", runtime.GOOS, errors.ErrUnsupported)
+}
+
+// clockTicks is the unit of CPU times in /proc/[pid]/stat (USER_HZ), which
+// is 100 on all common Linux platforms.
+const clockTicks = 100
+
+// procTable reads the process table from /proc.
+func procTable() (map[int]processInfo, error) {
+	paths, err := filepath.Glob("/proc/[0-9]*/stat")
+	if err != nil {
+		return nil, err
+	}
+	pageSize := uint64(os.Getpagesize())
+	table := make(map[int]processInfo, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue // The process exited
+		}
+		pid, p, err := parseProcStat(string(data), pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		table[pid] = p
+	}
+	return table, nil
+}
+
+// parseProcStat parses a /proc/[pid]/stat line.
+func parseProcStat(line string, pageSize uint64) (int, processInfo, error) {
+	// The command name may contain spaces and parentheses; it ends at the
+	// last ')'.
+	open, end := strings.IndexByte(line, '('), strings.LastIndexByte(line, ')')
+	if open < 0 || end < open {
+		return 0, processInfo{}, fmt.Errorf("unexpected stat line %q", line)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(line[:open]))
+	if err != nil {
+		return 0, processInfo{}, err
+	}
+	// Fields from state (field 3) on
+	fields := strings.Fields(line[end+1:])
+	if len(fields) < 22 {
+		return 0, processInfo{}, fmt.Errorf("unexpected stat line %q", line)
+	}
+	field := func(n int) uint64 { // n is the field number of proc(5)
+		v, _ := strconv.ParseUint(fields[n-3], 10, 64)
+		return v
+	}
+	ticks := field(14) + field(15) // utime + stime
+	return pid, processInfo{
+		ppid: int(field(4)),
+		rss:  field(24) * pageSize,
+		cpu:  time.Duration(ticks) * time.Second / clockTicks,
+	}, nil
+}
+
+// cimProcessScript lists the processes with PowerShell as JSON.
+const cimProcessScript = "Get-CimInstance Win32_Process | " +
+	"Select-Object ProcessId,ParentProcessId,WorkingSetSize,UserModeTime,KernelModeTime | " +
+	"ConvertTo-Json -Compress"
+
+// cimProcessTable reads the process table on Windows.
+func cimProcessTable(ctx context.Context) (map[int]processInfo, error) {
+	var out, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", cimProcessScript)
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("powershell: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("powershell: %w", err)
+	}
+	return parseCIMProcesses(out.Bytes())
+}
+
+// parseCIMProcesses parses Win32_Process objects as written by
+// cimProcessScript. CPU times are in units of 100 ns.
+func parseCIMProcesses(data []byte) (map[int]processInfo, error) {
+	var list []struct {
+		ProcessID       int    `json:"ProcessId"`
+		ParentProcessID int    `json:"ParentProcessId"`
+		WorkingSetSize  uint64 `json:"WorkingSetSize"`
+		UserModeTime    uint64 `json:"UserModeTime"`
+		KernelModeTime  uint64 `json:"KernelModeTime"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse process list: %w", err)
+	}
+	table := make(map[int]processInfo, len(list))
+	for _, p := range list {
+		table[p.ProcessID] = processInfo{
+			ppid: p.ParentProcessID,
+			rss:  p.WorkingSetSize,
+			cpu:  time.Duration(p.UserModeTime+p.KernelModeTime) * 100,
+		}
+	}
+	return table, nil
+}
@@ -0,0 +1,117 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestTreeStats(t *testing.T) {
+	before := map[int]processInfo{
+		100: {ppid: 1, rss: 100, cpu: time.Second},
+		101: {ppid: 100, rss: 50, cpu: time.Second},
+	}
+	after := map[int]processInfo{
+		1:   {ppid: 0, rss: 1000, cpu: time.Hour},
+		100: {ppid: 1, rss: 100, cpu: 1500 * time.Millisecond},
+		101: {ppid: 100, rss: 50, cpu: 1250 * time.Millisecond},
+		102: {ppid: 101, rss: 25},                 // Started between the samples
+		200: {ppid: 1, rss: 10, cpu: time.Minute}, // Another tree
+	}
+
+	stats := treeStats(before, after, []int{100, 300}, time.Second)
+	want := ProcessStats{PID: 100, Processes: 3, RSS: 175, CPU: 75}
+	if got := stats[100]; got != want {
+		t.Errorf("stats[100] = %+v, want %+v", got, want)
+	}
+	if _, ok := stats[300]; ok {
+		t.Error("stats include a process that does not exist")
+	}
+}
+
+func TestParseProcStat(t *testing.T) {
+	line := "4242 (chrome (renderer)) S 4200 4200 4200 0 -1 4194560 1 0 0 0 250 50 0 0 20 0 12 0 100 1000000 2048 18446744073709551615\n"
+	pid, p, err := parseProcStat(line, 4096)
+	if err != nil {
+		t.Fatalf("parseProcStat failed: %v", err)
+	}
+	want := processInfo{ppid: 4200, rss: 2048 * 4096, cpu: 3 * time.Second}
+	if pid != 4242 || p != want {
+		t.Errorf("parseProcStat = %d, %+v; want 4242, %+v", pid, p, want)
+	}
+
+	if _, _, err := parseProcStat("4242 chrome S", 4096); err == nil {
+		t.Error("parseProcStat accepted a malformed line")
+	}
+}
+
+func TestParseCIMProcesses(t *testing.T) {
+	data := []byte(`[{"ProcessId":4242,"ParentProcessId":4200,"WorkingSetSize":1048576,"UserModeTime":20000000,"KernelModeTime":10000000}]`)
+	table, err := parseCIMProcesses(data)
+	if err != nil {
+		t.Fatalf("parseCIMProcesses failed: %v", err)
+	}
+	want := processInfo{ppid: 4200, rss: 1 << 20, cpu: 3 * time.Second}
+	if got := table[4242]; got != want {
+		t.Errorf("table[4242] = %+v, want %+v", got, want)
+	}
+}
+
+func TestLocalProcessMonitor(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("reads /proc")
+	}
+	pid := os.Getpid()
+	stats, err := LocalProcessMonitor.ProcessStats(context.Background(), []int{pid})
+	if err != nil {
+		t.Fatalf("ProcessStats failed: %v", err)
+	}
+	if s := stats[pid]; s.PID != pid || s.Processes < 1 || s.RSS == 0 {
+		t.Errorf("stats = %+v, want this process", s)
+	}
+}
+
+func TestResourceStats(t *testing.T) {
+	ctx := context.Background()
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/browser/pids/all":
+			w.Write(successResponse(map[string]int{"p1": 101, "p2": 102}))
+		case "/browser/pids/alive":
+			w.Write(successResponse(map[string]int{"p2": 102}))
+		}
+	})
+	defer server.Close()
+
+	var asked []int
+	monitor := ProcessMonitorFunc(func(_ context.Context, pids []int) (map[int]ProcessStats, error) {
+		asked = pids
+		return map[int]ProcessStats{101: {PID: 101, RSS: 1 << 30}, 102: {PID: 102, RSS: 2 << 30}}, nil
+	})
+	client := mustNew(t, server.URL, WithProcessMonitor(monitor))
+
+	stats, err := client.ResourceStats(ctx, nil)
+	if err != nil {
+		t.Fatalf("ResourceStats failed: %v", err)
+	}
+	if len(asked) != 2 || stats["p1"].RSS != 1<<30 || stats["p2"].RSS != 2<<30 {
+		t.Errorf("stats = %+v for pids %v", stats, asked)
+	}
+
+	stats, err = client.ResourceStats(ctx, []string{"p2"})
+	if err != nil {
+		t.Fatalf("ResourceStats(p2) failed: %v", err)
+	}
+	if len(stats) != 1 || stats["p2"].PID != 102 {
+		t.Errorf("stats = %+v, want only p2", stats)
+	}
+
+	client = mustNew(t, server.URL)
+	if _, err := client.ResourceStats(ctx, nil); !errors.Is(err, ErrValidation) {
+		t.Errorf("err = %v without a monitor, want ErrValidation", err)
+	}
+}
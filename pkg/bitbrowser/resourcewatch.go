@@ -0,0 +1,182 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Defaults of ResourceWatchConfig.
+const (
+	DefaultResourceWatchInterval = 30 * time.Second
+	DefaultResourceWatchStrikes  = 3
+)
+
+// ResourceLimits are thresholds on a browser's usage. Zero values are not
+// checked.
+type ResourceLimits struct {
+	MaxRSS uint64  // Resident memory of the process tree, in bytes
+	MaxCPU float64 // CPU usage in percent of one core
+}
+
+// exceeded describes the first limit s exceeds, or returns "".
+func (l ResourceLimits) exceeded(s ProcessStats) string {
+	switch {
+	case l.MaxRSS > 0 && s.RSS > l.MaxRSS:
+		return fmt.Sprintf("memory %d MiB over limit of %d MiB", s.RSS>>20, l.MaxRSS>>20)
+	case l.MaxCPU > 0 && s.CPU > l.MaxCPU:
+		return fmt.Sprintf("CPU %.0f%% over limit of %.0f%%", s.CPU, l.MaxCPU)
+	}
+	return ""
+}
+
+// ResourceAlert reports a browser that stayed over its limits.
+type ResourceAlert struct {
+	ProfileID string       `json:"profileId"`
+	Stats     ProcessStats `json:"stats"`
+	Reason    string       `json:"reason"`
+	Restarted bool         `json:"restarted"`
+	Err       error        `json:"-"` // Why the restart failed
+}
+
+// ResourceWatchConfig configures a ResourceWatchdog.
+type ResourceWatchConfig struct {
+	// Limits apply to every browser.
+	Limits ResourceLimits
+
+	// Strikes is how many checks in a row a browser must exceed a limit
+	// before it is restarted, so that short spikes are tolerated
+	// (default: DefaultResourceWatchStrikes).
+	Strikes int
+
+	// Interval is the time between checks in Watch
+	// (default: DefaultResourceWatchInterval).
+	Interval time.Duration
+
+	// CloseTimeout bounds the close of a restart; see CloseAndWait
+	// (default: 30 seconds).
+	CloseTimeout time.Duration
+
+	// OpenOptions are used to reopen restarted browsers.
+	OpenOptions *OpenOptions
+
+	// DryRun reports runaway browsers without restarting them.
+	DryRun bool
+
+	// OnAlert is called for every runaway browser, after the restart.
+	OnAlert func(ResourceAlert)
+
+	// OnError is called by Watch when a check fails.
+	OnError func(err error)
+
+	// Clock is the time source (default: SystemClock).
+	Clock Clock
+}
+
+// ResourceWatchdog restarts browsers that use too much memory or CPU, such
+// as pages that leak memory or spin in a loop, before they starve the other
+// browsers on the host. Usage comes from Client.ResourceStats, so the client
+// needs a ProcessMonitor.
+//
+// Example:
+//
+//	watchdog := bitbrowser.NewResourceWatchdog(client, bitbrowser.ResourceWatchConfig{
+//	    Limits:  bitbrowser.ResourceLimits{MaxRSS: 4 << 30, MaxCPU: 150},
+//	    OnAlert: func(a bitbrowser.ResourceAlert) { log.Printf("%s: %s", a.ProfileID, a.Reason) },
+//	})
+//	err := watchdog.Watch(ctx) // All running browsers
+type ResourceWatchdog struct {
+	client *Client
+	config ResourceWatchConfig
+
+	mu      sync.Mutex
+	strikes map[string]int
+}
+
+// NewResourceWatchdog creates a ResourceWatchdog for the client's browsers.
+func NewResourceWatchdog(client *Client, config ResourceWatchConfig) *ResourceWatchdog {
+	if config.Strikes <= 0 {
+		config.Strikes = DefaultResourceWatchStrikes
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultResourceWatchInterval
+	}
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+	return &ResourceWatchdog{client: client, config: config, strikes: make(map[string]int)}
+}
+
+// Check reads the usage of the profiles' browsers once (all running browsers
+// without ids) and restarts those that exceeded a limit Strikes checks in a
+// row. It returns an alert for each of them.
+func (w *ResourceWatchdog) Check(ctx context.Context, ids ...string) ([]ResourceAlert, error) {
+	stats, err := w.client.ResourceStats(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []ResourceAlert
+	w.mu.Lock()
+	for id := range w.strikes {
+		if _, running := stats[id]; !running {
+			delete(w.strikes, id)
+		}
+	}
+	for id, s := range stats {
+		reason := w.config.Limits.exceeded(s)
+		if reason == "" {
+			delete(w.strikes, id)
+			continue
+		}
+		w.strikes[id]++
+		if w.strikes[id] >= w.config.Strikes {
+			delete(w.strikes, id)
+			alerts = append(alerts, ResourceAlert{ProfileID: id, Stats: s, Reason: reason})
+		}
+	}
+	w.mu.Unlock()
+
+	for i := range alerts {
+		a := &alerts[i]
+		if !w.config.DryRun {
+			a.Err = w.restart(ctx, a.ProfileID)
+			a.Restarted = a.Err == nil
+		}
+		if w.config.OnAlert != nil {
+			w.config.OnAlert(*a)
+		}
+	}
+	return alerts, nil
+}
+
+// restart closes the browser, killing it if it does not close, and opens it
+// again.
+func (w *ResourceWatchdog) restart(ctx context.Context, id string) error {
+	if logger := w.client.logger; logger != nil {
+		logger.WarnContext(ctx, "bitbrowser: restarting runaway browser", "id", id)
+	}
+	if err := w.client.CloseAndWait(ctx, id, w.config.CloseTimeout); err != nil {
+		return fmt.Errorf("bitbrowser: restart: %w", err)
+	}
+	if _, err := w.client.Open(ctx, id, w.config.OpenOptions); err != nil {
+		return fmt.Errorf("bitbrowser: restart: %w", err)
+	}
+	return nil
+}
+
+// Watch checks the browsers every Interval until ctx is done and then
+// returns ctx.Err(). Failed checks are reported to OnError.
+func (w *ResourceWatchdog) Watch(ctx context.Context, ids ...string) error {
+	for {
+		if _, err := w.Check(ctx, ids...); err != nil && w.config.OnError != nil && ctx.Err() == nil {
+			w.config.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.config.Clock.After(w.config.Interval):
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package bitbrowser
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestResourceWatchdog(t *testing.T) {
+	ctx := context.Background()
+
+	// newClient returns a client of a fake API running p1 and p2, where p1
+	// uses rss bytes of memory.
+	newClient := func(t *testing.T, rss uint64) (*Client, *[]string) {
+		t.Helper()
+		var mu sync.Mutex
+		running := map[string]int{"p1": 101, "p2": 102}
+		var calls []string
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch r.URL.Path {
+			case "/browser/pids/all", "/browser/pids/alive":
+				w.Write(successResponse(running))
+			case "/browser/close":
+				calls = append(calls, "close")
+				delete(running, "p1")
+				w.Write(successResponse(nil))
+			case "/browser/open":
+				calls = append(calls, "open")
+				running["p1"] = 103
+				w.Write(successResponse(OpenResult{Http: "http://127.0.0.1:9222"}))
+			default:
+				w.Write(successResponse(nil))
+			}
+		})
+		t.Cleanup(server.Close)
+		monitor := ProcessMonitorFunc(func(_ context.Context, pids []int) (map[int]ProcessStats, error) {
+			stats := make(map[int]ProcessStats)
+			for _, pid := range pids {
+				stats[pid] = ProcessStats{PID: pid, RSS: 1 << 20}
+			}
+			stats[101] = ProcessStats{PID: 101, RSS: rss}
+			return stats, nil
+		})
+		return mustNew(t, server.URL, WithProcessMonitor(monitor)), &calls
+	}
+
+	t.Run("restarts after enough strikes", func(t *testing.T) {
+		client, calls := newClient(t, 8<<30)
+		var alerted []ResourceAlert
+		watchdog := NewResourceWatchdog(client, ResourceWatchConfig{
+			Limits:  ResourceLimits{MaxRSS: 4 << 30},
+			Strikes: 2,
+			OnAlert: func(a ResourceAlert) { alerted = append(alerted, a) },
+		})
+
+		alerts, err := watchdog.Check(ctx)
+		if err != nil || len(alerts) != 0 {
+			t.Fatalf("first Check = %v, %v; want no alerts", alerts, err)
+		}
+		alerts, err = watchdog.Check(ctx)
+		if err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+		if len(alerts) != 1 || alerts[0].ProfileID != "p1" || !alerts[0].Restarted || alerts[0].Reason == "" {
+			t.Fatalf("alerts = %+v, want p1 restarted", alerts)
+		}
+		if len(alerted) != 1 {
+			t.Errorf("OnAlert called %d times, want 1", len(alerted))
+		}
+		if len(*calls) != 2 || (*calls)[0] != "close" || (*calls)[1] != "open" {
+			t.Errorf("calls = %v, want close then open", *calls)
+		}
+	})
+
+	t.Run("tolerates short spikes", func(t *testing.T) {
+		client, _ := newClient(t, 8<<30)
+		watchdog := NewResourceWatchdog(client, ResourceWatchConfig{
+			Limits:  ResourceLimits{MaxRSS: 4 << 30},
+			Strikes: 2,
+		})
+		watchdog.Check(ctx)
+		watchdog.config.Limits.MaxRSS = 16 << 30 // The spike ends
+		watchdog.Check(ctx)
+		watchdog.config.Limits.MaxRSS = 4 << 30
+		if alerts, _ := watchdog.Check(ctx); len(alerts) != 0 {
+			t.Errorf("alerts = %+v, want strikes reset", alerts)
+		}
+	})
+
+	t.Run("dry run only reports", func(t *testing.T) {
+		client, calls := newClient(t, 8<<30)
+		watchdog := NewResourceWatchdog(client, ResourceWatchConfig{
+			Limits:  ResourceLimits{MaxRSS: 4 << 30},
+			Strikes: 1,
+			DryRun:  true,
+		})
+		alerts, err := watchdog.Check(ctx)
+		if err != nil || len(alerts) != 1 || alerts[0].Restarted {
+			t.Fatalf("Check = %+v, %v; want one unrestarted alert", alerts, err)
+		}
+		if len(*calls) != 0 {
+			t.Errorf("calls = %v, want none", *calls)
+		}
+	})
+
+	t.Run("within limits", func(t *testing.T) {
+		client, _ := newClient(t, 1<<30)
+		watchdog := NewResourceWatchdog(client, ResourceWatchConfig{
+			Limits:  ResourceLimits{MaxRSS: 4 << 30},
+			Strikes: 1,
+		})
+		if alerts, err := watchdog.Check(ctx); err != nil || len(alerts) != 0 {
+			t.Errorf("Check = %+v, %v; want no alerts", alerts, err)
+		}
+	})
+}
@@ -2,8 +2,10 @@ package bitbrowser
 
 import (
 	"context"
+	"errors"
 	"math"
 	"math/rand/v2"
+	"sync"
 	"time"
 )
 
@@ -17,8 +19,9 @@ type RetryConfig struct {
 	// Default is 1 second.
 	BaseDelay time.Duration
 
-	// MaxDelay is the maximum delay between retries.
-	// Default is 30 seconds.
+	// MaxDelay is the maximum delay between retries, including delays a
+	// server suggests with Retry-After. Default is 30 seconds; Retry-After
+	// delays are capped at 30 seconds even if MaxDelay is not set.
 	MaxDelay time.Duration
 
 	// Multiplier is the factor by which the delay increases after each retry.
@@ -48,9 +51,229 @@ func DefaultRetryConfig() *RetryConfig {
 	}
 }
 
-// retryer handles retry logic for operations.
+// RetryBudget limits the total number of retries across all calls that
+// share it, so an outage does not multiply into thousands of retries from
+// hundreds of goroutines.
+//
+// It is a token bucket: each retry (not the initial attempt) consumes one
+// token, and tokens refill continuously at maxRetries per window. When the
+// budget is empty, calls stop retrying and fail with their last error.
+//
+// A RetryBudget is safe for concurrent use and can be shared by several clients.
+type RetryBudget struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 // Tokens per nanosecond
+	last     time.Time
+	clock    Clock
+}
+
+// NewRetryBudget creates a budget allowing maxRetries retries per window.
+// Returns nil (unlimited) if maxRetries or window is not positive.
+func NewRetryBudget(maxRetries int, window time.Duration) *RetryBudget {
+	return NewRetryBudgetWithClock(maxRetries, window, SystemClock)
+}
+
+// NewRetryBudgetWithClock is like NewRetryBudget but refills tokens
+// according to the given clock.
+func NewRetryBudgetWithClock(maxRetries int, window time.Duration, clock Clock) *RetryBudget {
+	if maxRetries <= 0 || window <= 0 {
+		return nil
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &RetryBudget{
+		capacity: float64(maxRetries),
+		tokens:   float64(maxRetries),
+		rate:     float64(maxRetries) / float64(window),
+		last:     clock.Now(),
+		clock:    clock,
+	}
+}
+
+// Allow consumes one retry token and reports whether the retry may proceed.
+// A nil budget always allows retries.
+func (b *RetryBudget) Allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Remaining returns the number of retries currently available.
+func (b *RetryBudget) Remaining() int {
+	if b == nil {
+		return math.MaxInt
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	return int(b.tokens)
+}
+
+// refill adds tokens for the time elapsed since the last call.
+// The caller must hold b.mu.
+func (b *RetryBudget) refill() {
+	now := b.clock.Now()
+	elapsed := now.Sub(b.last)
+	b.last = now
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens = min(b.capacity, b.tokens+float64(elapsed)*b.rate)
+}
+
+// OpenBusyPolicy controls what Open does when BitBrowser reports that the
+// profile is busy (e.g. "正在打开" while a previous open is still running).
+//
+// The zero value fails fast: the busy error is returned immediately and
+// matches ErrBusy.
+type OpenBusyPolicy struct {
+	// Wait retries the open while the profile is busy.
+	Wait bool
+
+	// Interval is the delay between attempts when the server does not
+	// suggest one via Retry-After. Default is 2 seconds.
+	Interval time.Duration
+
+	// MaxWait is the total time to keep waiting before returning the busy
+	// error. Default is 60 seconds. The context deadline also applies.
+	MaxWait time.Duration
+}
+
+// interval returns the configured interval or its default.
+func (p OpenBusyPolicy) interval() time.Duration {
+	if p.Interval <= 0 {
+		return 2 * time.Second
+	}
+	return p.Interval
+}
+
+// maxWait returns the configured maximum wait or its default.
+func (p OpenBusyPolicy) maxWait() time.Duration {
+	if p.MaxWait <= 0 {
+		return 60 * time.Second
+	}
+	return p.MaxWait
+}
+
+// OpenRetryPolicy retries Open when launching the browser fails for reasons
+// that go away on their own, such as a busy profile or a browser kernel that
+// is still downloading.
+//
+// It is separate from the transport retries set with WithRetry, which retry
+// single HTTP requests on network errors and 5xx responses. Each open
+// attempt still uses those transport retries.
+type OpenRetryPolicy struct {
+	// MaxAttempts is the maximum number of open attempts, including the
+	// first. Default is 3.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. Default is 2 seconds.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts. Default is 30 seconds.
+	MaxDelay time.Duration
+
+	// Multiplier is the factor by which the delay grows after each retry.
+	// Default is 2.0.
+	Multiplier float64
+
+	// RetryIf decides whether an open error is retried. If nil, errors
+	// matching ErrBusy or ErrKernelDownloading are retried.
+	RetryIf func(error) bool
+}
+
+// retryConfig converts the policy to a RetryConfig, applying defaults.
+func (p OpenRetryPolicy) retryConfig() *RetryConfig {
+	config := &RetryConfig{
+		MaxAttempts: p.MaxAttempts,
+		BaseDelay:   p.BaseDelay,
+		MaxDelay:    p.MaxDelay,
+		Multiplier:  p.Multiplier,
+		Jitter:      0.1,
+		RetryIf:     p.RetryIf,
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = 2 * time.Second
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = 30 * time.Second
+	}
+	if config.Multiplier <= 0 {
+		config.Multiplier = 2.0
+	}
+	if config.RetryIf == nil {
+		config.RetryIf = isOpenRetryable
+	}
+	return config
+}
+
+// isOpenRetryable reports whether an open error is expected to clear up
+// by itself.
+func isOpenRetryable(err error) bool {
+	return errors.Is(err, ErrBusy) || errors.Is(err, ErrKernelDownloading)
+}
+
+// EndpointRetryPolicy chooses the retry configuration of each API call by
+// endpoint path, e.g. "/browser/list". Returning nil uses the client's
+// configuration from WithRetryConfig or WithRetry.
+type EndpointRetryPolicy func(path string) *RetryConfig
+
+// RetryReads returns a policy that retries reads with config and sends
+// requests that change state in BitBrowser (creating, updating, opening or
+// closing profiles and so on) only once. A mutation that times out may
+// already have been applied, and repeating it could, for example, create a
+// profile twice.
+//
+// Example:
+//
+//	client, err := bitbrowser.New(apiURL,
+//	    bitbrowser.WithEndpointRetryPolicy(bitbrowser.RetryReads(&bitbrowser.RetryConfig{
+//	        MaxAttempts: 4,
+//	        BaseDelay:   500 * time.Millisecond,
+//	    })))
+func RetryReads(config *RetryConfig) EndpointRetryPolicy {
+	once := &RetryConfig{MaxAttempts: 1}
+	return func(path string) *RetryConfig {
+		if isMutating(path) {
+			return once
+		}
+		return config
+	}
+}
+
+// retryConfigFor returns the retry configuration of a call to path.
+func (c *Client) retryConfigFor(path string) *RetryConfig {
+	if c.retryPolicy != nil {
+		if config := c.retryPolicy(path); config != nil {
+			return config
+		}
+	}
+	return c.retryConfig
+}
+
+// retryer handles retry logic for one operation. It holds a copy of the
+// configuration, so that concurrent calls sharing a client's RetryConfig
+// never write to it.
 type retryer struct {
-	config *RetryConfig
+	config RetryConfig
+	budget *RetryBudget // Shared retry budget (nil means unlimited)
+	clock  Clock
 }
 
 // newRetryer creates a new retryer with the given configuration.
@@ -58,15 +281,13 @@ func newRetryer(config *RetryConfig) *retryer {
 	if config == nil {
 		config = DefaultRetryConfig()
 	}
-	return &retryer{config: config}
+	return &retryer{config: *config, clock: SystemClock}
 }
 
 // do executes the given function with retry logic.
 // It respects context cancellation and returns early if the context is done.
 func (r *retryer) do(ctx context.Context, fn func() error) error {
-	if r.config.MaxAttempts <= 0 {
-		r.config.MaxAttempts = 1
-	}
+	maxAttempts := max(r.config.MaxAttempts, 1)
 
 	retryIf := r.config.RetryIf
 	if retryIf == nil {
@@ -74,7 +295,7 @@ func (r *retryer) do(ctx context.Context, fn func() error) error {
 	}
 
 	var lastErr error
-	for attempt := 1; attempt <= r.config.MaxAttempts; attempt++ {
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
 		// Check context before each attempt
 		if err := ctx.Err(); err != nil {
 			if lastErr != nil {
@@ -90,7 +311,7 @@ func (r *retryer) do(ctx context.Context, fn func() error) error {
 		}
 
 		// Check if we should retry
-		if attempt >= r.config.MaxAttempts {
+		if attempt >= maxAttempts {
 			break
 		}
 
@@ -98,25 +319,42 @@ func (r *retryer) do(ctx context.Context, fn func() error) error {
 			return lastErr
 		}
 
-		// Calculate delay with exponential backoff
+		if !r.budget.Allow() {
+			return &RetryError{Attempts: attempt, LastErr: lastErr, BudgetExhausted: true}
+		}
+
+		// Calculate delay with exponential backoff, honoring a longer
+		// server-suggested delay (Retry-After) up to MaxDelay
 		delay := r.calculateDelay(attempt)
+		if suggested := min(retryAfter(lastErr), r.maxRetryAfter()); suggested > delay {
+			delay = suggested
+		}
 
 		// Wait with context awareness
 		select {
 		case <-ctx.Done():
 			return NewRetryError(attempt, lastErr)
-		case <-time.After(delay):
+		case <-r.clock.After(delay):
 			// Continue to next attempt
 		}
 	}
 
 	// All attempts exhausted
-	if r.config.MaxAttempts > 1 {
-		return NewRetryError(r.config.MaxAttempts, lastErr)
+	if maxAttempts > 1 {
+		return NewRetryError(maxAttempts, lastErr)
 	}
 	return lastErr
 }
 
+// maxRetryAfter returns the longest server-suggested delay to wait, so
+// that a large Retry-After cannot park a call indefinitely.
+func (r *retryer) maxRetryAfter() time.Duration {
+	if r.config.MaxDelay > 0 {
+		return r.config.MaxDelay
+	}
+	return DefaultRetryConfig().MaxDelay
+}
+
 // calculateDelay computes the delay for the given attempt number.
 // attempt is 1-indexed (first attempt is 1).
 func (r *retryer) calculateDelay(attempt int) time.Duration {
@@ -3,6 +3,8 @@ package bitbrowser
 import (
 	"context"
 	"errors"
+	"net/http"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -333,11 +335,7 @@ func TestRetryer_ZeroMaxAttempts(t *testing.T) {
 	}
 }
 
-func TestRetryer_ActualBackoffTiming(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping timing test in short mode")
-	}
-
+func TestRetryer_BackoffTiming(t *testing.T) {
 	config := &RetryConfig{
 		MaxAttempts: 3,
 		BaseDelay:   50 * time.Millisecond,
@@ -346,28 +344,265 @@ func TestRetryer_ActualBackoffTiming(t *testing.T) {
 		Jitter:      0,
 		RetryIf:     func(err error) bool { return true }, // Always retry
 	}
+	clock := NewFakeClock(time.Unix(0, 0))
 	r := newRetryer(config)
+	r.clock = clock
+
+	var attempts atomic.Int32
+	done := make(chan error, 1)
+	go func() {
+		done <- r.do(context.Background(), func() error {
+			attempts.Add(1)
+			return errors.New("always fail")
+		})
+	}()
 
-	start := time.Now()
-	attempts := 0
+	// Expected: attempt 1 (immediate) + 50ms delay + attempt 2 + 100ms delay + attempt 3
+	for _, delay := range []time.Duration{50 * time.Millisecond, 100 * time.Millisecond} {
+		clock.BlockUntil(1)
+		clock.Advance(delay - time.Millisecond)
+		if clock.Waiters() != 1 {
+			t.Fatalf("backoff of %v fired early", delay)
+		}
+		clock.Advance(time.Millisecond)
+	}
 
-	_ = r.do(context.Background(), func() error {
-		attempts++
-		return errors.New("always fail")
+	if err := <-done; !errors.Is(err, ErrRetryExhausted) {
+		t.Errorf("expected ErrRetryExhausted, got %v", err)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("attempts = %d, want 3", attempts.Load())
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	t.Run("nil budget is unlimited", func(t *testing.T) {
+		var b *RetryBudget
+		for range 100 {
+			if !b.Allow() {
+				t.Fatal("nil budget should always allow")
+			}
+		}
+		if NewRetryBudget(0, time.Minute) != nil {
+			t.Error("non-positive maxRetries should return nil")
+		}
 	})
 
-	elapsed := time.Since(start)
+	t.Run("denies once exhausted", func(t *testing.T) {
+		b := NewRetryBudget(3, time.Hour)
+		for i := range 3 {
+			if !b.Allow() {
+				t.Fatalf("retry %d should be allowed", i+1)
+			}
+		}
+		if b.Allow() {
+			t.Error("fourth retry should be denied")
+		}
+		if b.Remaining() != 0 {
+			t.Errorf("Remaining = %d, want 0", b.Remaining())
+		}
+	})
 
-	if attempts != 3 {
-		t.Errorf("attempts = %d, want 3", attempts)
+	t.Run("refills over time", func(t *testing.T) {
+		clock := NewFakeClock(time.Unix(0, 0))
+		b := NewRetryBudgetWithClock(2, 20*time.Millisecond, clock)
+		b.Allow()
+		b.Allow()
+		if b.Allow() {
+			t.Fatal("budget should be empty")
+		}
+		clock.Advance(9 * time.Millisecond)
+		if b.Allow() {
+			t.Fatal("budget should not refill a token early")
+		}
+		clock.Advance(2 * time.Millisecond)
+		if !b.Allow() {
+			t.Error("budget should have refilled")
+		}
+	})
+
+	t.Run("stops retryer when exhausted", func(t *testing.T) {
+		budget := NewRetryBudget(1, time.Hour)
+		config := &RetryConfig{MaxAttempts: 5, BaseDelay: time.Millisecond}
+
+		var attempts atomic.Int32
+		fail := func() error {
+			attempts.Add(1)
+			return NewNetworkError("connect", "http://localhost", errors.New("refused"))
+		}
+
+		r := newRetryer(config)
+		r.budget = budget
+		err := r.do(context.Background(), fail)
+
+		if attempts.Load() != 2 {
+			t.Errorf("attempts = %d, want 2 (initial + one budgeted retry)", attempts.Load())
+		}
+		if !errors.Is(err, ErrRetryBudgetExhausted) {
+			t.Errorf("expected ErrRetryBudgetExhausted, got %v", err)
+		}
+		if !errors.Is(err, ErrRetryExhausted) {
+			t.Errorf("expected ErrRetryExhausted, got %v", err)
+		}
+		if !errors.Is(err, ErrNetwork) {
+			t.Errorf("expected underlying ErrNetwork, got %v", err)
+		}
+
+		// A second call sharing the budget gets no retries at all
+		attempts.Store(0)
+		r2 := newRetryer(config)
+		r2.budget = budget
+		r2.do(context.Background(), fail)
+		if attempts.Load() != 1 {
+			t.Errorf("attempts = %d, want 1 once the budget is empty", attempts.Load())
+		}
+	})
+
+	t.Run("shared by client requests", func(t *testing.T) {
+		var requests atomic.Int32
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer server.Close()
+
+		client := mustNew(t, server.URL,
+			WithRetryConfig(&RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+			WithRetryBudget(NewRetryBudget(2, time.Hour)),
+		)
+		for range 3 {
+			client.Health(context.Background())
+		}
+
+		// 3 initial attempts + 2 budgeted retries
+		if requests.Load() != 5 {
+			t.Errorf("requests = %d, want 5", requests.Load())
+		}
+	})
+}
+
+func TestRetryer_HonorsRetryAfter(t *testing.T) {
+	config := &RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	clock := NewFakeClock(time.Unix(0, 0))
+	r := newRetryer(config)
+	r.clock = clock
+
+	var attempts atomic.Int32
+	done := make(chan error, 1)
+	go func() {
+		done <- r.do(context.Background(), func() error {
+			if attempts.Add(1) == 1 {
+				apiErr := NewAPIError("/browser/open", http.StatusTooManyRequests, "slow down")
+				apiErr.RetryAfter = 50 * time.Millisecond
+				return apiErr
+			}
+			return nil
+		})
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(49 * time.Millisecond)
+	if attempts.Load() != 1 || clock.Waiters() != 1 {
+		t.Fatal("retried before the Retry-After delay")
 	}
+	clock.Advance(time.Millisecond)
 
-	// Expected: attempt 1 (immediate) + 50ms delay + attempt 2 + 100ms delay + attempt 3
-	// Total delay: ~150ms (allowing some margin)
-	expectedMin := 140 * time.Millisecond
-	expectedMax := 250 * time.Millisecond
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("attempts = %d, want 2", attempts.Load())
+	}
+}
 
-	if elapsed < expectedMin || elapsed > expectedMax {
-		t.Errorf("elapsed = %v, want between %v and %v", elapsed, expectedMin, expectedMax)
+func TestRetryer_CapsRetryAfter(t *testing.T) {
+	for name, config := range map[string]*RetryConfig{
+		"MaxDelay":         {MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Second},
+		"default MaxDelay": {MaxAttempts: 2, BaseDelay: time.Millisecond},
+	} {
+		t.Run(name, func(t *testing.T) {
+			clock := NewFakeClock(time.Unix(0, 0))
+			r := newRetryer(config)
+			r.clock = clock
+
+			done := make(chan error, 1)
+			var attempts atomic.Int32
+			go func() {
+				done <- r.do(context.Background(), func() error {
+					if attempts.Add(1) == 1 {
+						apiErr := NewAPIError("/browser/open", http.StatusTooManyRequests, "slow down")
+						apiErr.RetryAfter = 24 * time.Hour
+						return apiErr
+					}
+					return nil
+				})
+			}()
+
+			clock.BlockUntil(1)
+			want := config.MaxDelay
+			if want == 0 {
+				want = DefaultRetryConfig().MaxDelay
+			}
+			clock.Advance(want)
+			if err := <-done; err != nil || attempts.Load() != 2 {
+				t.Errorf("err = %v after %d attempts, want a retry after %s", err, attempts.Load(), want)
+			}
+		})
 	}
 }
+
+func TestRetryer_SharedConfig(t *testing.T) {
+	config := &RetryConfig{MaxAttempts: 0, BaseDelay: time.Millisecond}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			newRetryer(config).do(context.Background(), func() error { return nil })
+		}()
+	}
+	wg.Wait()
+	if config.MaxAttempts != 0 {
+		t.Errorf("MaxAttempts = %d, want the shared config left untouched", config.MaxAttempts)
+	}
+}
+
+func TestWithEndpointRetryPolicy(t *testing.T) {
+	ctx := context.Background()
+	var calls sync.Map // Path -> *atomic.Int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := calls.LoadOrStore(r.URL.Path, new(atomic.Int32))
+		n.(*atomic.Int32).Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+	count := func(path string) int32 {
+		n, ok := calls.Load(path)
+		if !ok {
+			return 0
+		}
+		return n.(*atomic.Int32).Load()
+	}
+
+	retry := &RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	client := mustNew(t, server.URL, WithRetryConfig(retry), WithEndpointRetryPolicy(RetryReads(retry)))
+
+	client.ListProfiles(ctx, ListRequest{PageSize: 10})
+	if n := count("/browser/list"); n != 3 {
+		t.Errorf("read attempts = %d, want 3", n)
+	}
+	client.CreateProfile(ctx, ProfileConfig{Name: "shop"})
+	if n := count("/browser/update"); n != 1 {
+		t.Errorf("mutation attempts = %d, want 1", n)
+	}
+
+	t.Run("nil falls back to the client config", func(t *testing.T) {
+		client := mustNew(t, server.URL, WithRetryConfig(retry),
+			WithEndpointRetryPolicy(func(string) *RetryConfig { return nil }))
+		client.GetPorts(ctx)
+		if n := count("/browser/ports"); n != 3 {
+			t.Errorf("attempts = %d, want 3", n)
+		}
+	})
+}
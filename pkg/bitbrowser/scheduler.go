@@ -0,0 +1,164 @@
+package bitbrowser
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// HostMetrics is the resource usage of a host, in percent (0-100).
+type HostMetrics struct {
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+}
+
+// MetricsAgent reports the resource usage of a fleet host, typically via a
+// small agent running next to BitBrowser.
+type MetricsAgent interface {
+	Metrics(ctx context.Context) (HostMetrics, error)
+}
+
+// MetricsAgentFunc adapts a function to the MetricsAgent interface.
+type MetricsAgentFunc func(ctx context.Context) (HostMetrics, error)
+
+// Metrics calls fn(ctx).
+func (fn MetricsAgentFunc) Metrics(ctx context.Context) (HostMetrics, error) {
+	return fn(ctx)
+}
+
+// HTTPMetricsAgent returns a MetricsAgent that fetches url with GET and
+// expects a JSON body such as {"cpu": 42.5, "memory": 61.0}.
+func HTTPMetricsAgent(url string) MetricsAgent {
+	return MetricsAgentFunc(func(ctx context.Context) (HostMetrics, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return HostMetrics{}, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return HostMetrics{}, NewNetworkError("metrics", url, err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return HostMetrics{}, NewAPIError(url, resp.StatusCode, resp.Status)
+		}
+		var m HostMetrics
+		if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+			return HostMetrics{}, fmt.Errorf("bitbrowser: failed to parse metrics: %w", err)
+		}
+		return m, nil
+	})
+}
+
+// HostLoad is the load of a fleet host as seen by the scheduler.
+type HostLoad struct {
+	Host        string
+	Browsers    int          // Running browsers, from GetAllPIDs
+	MaxBrowsers int          // Browser cap, 0 if unlimited
+	Metrics     *HostMetrics // Agent report, nil without an agent or if it failed
+}
+
+// Full reports whether the host has reached its browser cap.
+func (l HostLoad) Full() bool {
+	return l.MaxBrowsers > 0 && l.Browsers >= l.MaxBrowsers
+}
+
+// Utilization is the highest of the host's CPU usage, memory usage and
+// browser count relative to its cap, as a fraction. Measures that are not
+// available are left out; with none available it is 0.
+func (l HostLoad) Utilization() float64 {
+	var u float64
+	if l.MaxBrowsers > 0 {
+		u = float64(l.Browsers) / float64(l.MaxBrowsers)
+	}
+	if l.Metrics != nil {
+		u = max(u, l.Metrics.CPU/100, l.Metrics.Memory/100)
+	}
+	return u
+}
+
+// Loads reports the current load of every host, in host order. Hosts whose
+// browser count cannot be read are left out and reported in the error as in
+// ListProfiles. Agent failures are not errors; Metrics is nil instead.
+func (f *FleetClient) Loads(ctx context.Context) ([]HostLoad, error) {
+	loads := make([]*HostLoad, len(f.hosts))
+	index := make(map[*fleetHost]int, len(f.hosts))
+	for i, h := range f.hosts {
+		index[h] = i
+	}
+
+	var mu sync.Mutex
+	err := f.forEach(ctx, func(ctx context.Context, h *fleetHost) error {
+		load, err := h.load(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		loads[index[h]] = load
+		mu.Unlock()
+		return nil
+	})
+
+	var result []HostLoad
+	for _, load := range loads {
+		if load != nil {
+			result = append(result, *load)
+		}
+	}
+	return result, err
+}
+
+// schedule picks the host for a new profile: the host below its cap with the
+// lowest utilization, then the fewest browsers, then the earliest position.
+func (f *FleetClient) schedule(ctx context.Context) (*fleetHost, error) {
+	loads, err := f.Loads(ctx)
+	loads = slices.DeleteFunc(loads, HostLoad.Full)
+	if len(loads) == 0 {
+		return nil, fmt.Errorf("bitbrowser: schedule failed: %w", errors.Join(ErrNoCapacity, err))
+	}
+
+	best := slices.MinFunc(loads, func(a, b HostLoad) int {
+		return cmp.Or(
+			cmp.Compare(a.Utilization(), b.Utilization()),
+			cmp.Compare(a.Browsers, b.Browsers),
+		)
+	})
+	return f.byName[best.Host], nil
+}
+
+// checkCapacity fails if opening the profile on h would exceed its browser
+// cap. Browsers that are already running do not count as new.
+func (f *FleetClient) checkCapacity(ctx context.Context, h *fleetHost, id string) error {
+	if h.maxBrowsers == 0 {
+		return nil
+	}
+	pids, err := h.client.GetAllPIDs(ctx)
+	if err != nil {
+		return &FleetError{Host: h.name, Err: err}
+	}
+	if _, running := pids[id]; !running && len(pids) >= h.maxBrowsers {
+		return &FleetError{Host: h.name, Err: fmt.Errorf("%w: %d of %d browsers running", ErrNoCapacity, len(pids), h.maxBrowsers)}
+	}
+	return nil
+}
+
+// load reads the host's browser count and, if it has an agent, its metrics.
+func (h *fleetHost) load(ctx context.Context) (*HostLoad, error) {
+	pids, err := h.client.GetAllPIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	load := &HostLoad{Host: h.name, Browsers: len(pids), MaxBrowsers: h.maxBrowsers}
+	if h.agent != nil {
+		if m, err := h.agent.Metrics(ctx); err == nil {
+			load.Metrics = &m
+		}
+	}
+	return load, nil
+}
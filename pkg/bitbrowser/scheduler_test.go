@@ -0,0 +1,189 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// openOn marks profiles as running on the fake host.
+func (h *fakeHost) openOn(ids ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, id := range ids {
+		h.profiles[id] = true
+		h.open[id] = true
+	}
+}
+
+// staticAgent reports fixed metrics.
+func staticAgent(cpu, memory float64) MetricsAgent {
+	return MetricsAgentFunc(func(ctx context.Context) (HostMetrics, error) {
+		return HostMetrics{CPU: cpu, Memory: memory}, nil
+	})
+}
+
+func TestHostLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		load HostLoad
+		full bool
+		want float64
+	}{
+		{"nothing known", HostLoad{Browsers: 5}, false, 0},
+		{"cap", HostLoad{Browsers: 5, MaxBrowsers: 10}, false, 0.5},
+		{"at cap", HostLoad{Browsers: 10, MaxBrowsers: 10}, true, 1},
+		{"metrics win", HostLoad{Browsers: 1, MaxBrowsers: 10, Metrics: &HostMetrics{CPU: 80, Memory: 30}}, false, 0.8},
+		{"cap wins", HostLoad{Browsers: 9, MaxBrowsers: 10, Metrics: &HostMetrics{CPU: 20, Memory: 30}}, false, 0.9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.load.Full(); got != tt.full {
+				t.Errorf("Full() = %v, want %v", got, tt.full)
+			}
+			if got := tt.load.Utilization(); got != tt.want {
+				t.Errorf("Utilization() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFleetScheduling(t *testing.T) {
+	newFleet := func(t *testing.T, a, b *fakeHost, optsA, optsB []FleetHostOption) *FleetClient {
+		t.Helper()
+		fleet, err := NewFleetClient(
+			WithFleetHost("a", mustNew(t, a.URL), optsA...),
+			WithFleetHost("b", mustNew(t, b.URL), optsB...),
+		)
+		if err != nil {
+			t.Fatalf("NewFleetClient failed: %v", err)
+		}
+		return fleet
+	}
+
+	t.Run("counts running browsers", func(t *testing.T) {
+		a, b := newFakeHost(t), newFakeHost(t)
+		a.openOn("x1", "x2")
+		b.openOn("y1")
+		fleet := newFleet(t, a, b, nil, nil)
+
+		host, _, err := fleet.CreateProfile(context.Background(), ProfileConfig{Name: "new"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if host != "b" {
+			t.Errorf("host = %q, want b", host)
+		}
+	})
+
+	t.Run("prefers lower utilization", func(t *testing.T) {
+		a, b := newFakeHost(t), newFakeHost(t)
+		b.openOn("y1", "y2")
+		fleet := newFleet(t, a, b,
+			[]FleetHostOption{WithMetricsAgent(staticAgent(90, 40))},
+			[]FleetHostOption{WithMetricsAgent(staticAgent(10, 20)), WithMaxBrowsers(10)},
+		)
+
+		loads, err := fleet.Loads(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(loads) != 2 || loads[0].Host != "a" || loads[1].Browsers != 2 || loads[1].Metrics == nil {
+			t.Errorf("unexpected loads: %+v", loads)
+		}
+
+		host, _, err := fleet.CreateProfile(context.Background(), ProfileConfig{Name: "new"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if host != "b" {
+			t.Errorf("host = %q, want b", host)
+		}
+	})
+
+	t.Run("skips hosts at cap", func(t *testing.T) {
+		a, b := newFakeHost(t), newFakeHost(t)
+		a.openOn("x1")
+		b.openOn("y1", "y2")
+		fleet := newFleet(t, a, b, []FleetHostOption{WithMaxBrowsers(1)}, nil)
+
+		host, _, err := fleet.CreateProfile(context.Background(), ProfileConfig{Name: "new"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if host != "b" {
+			t.Errorf("host = %q, want b", host)
+		}
+	})
+
+	t.Run("no capacity", func(t *testing.T) {
+		a, b := newFakeHost(t), newFakeHost(t)
+		a.openOn("x1")
+		b.Close()
+		fleet := newFleet(t, a, b, []FleetHostOption{WithMaxBrowsers(1)}, nil)
+
+		_, _, err := fleet.CreateProfile(context.Background(), ProfileConfig{Name: "new"})
+		if !errors.Is(err, ErrNoCapacity) || !errors.Is(err, ErrNetwork) {
+			t.Errorf("expected ErrNoCapacity with the host failure, got %v", err)
+		}
+		if a.callsTo("/browser/update") != 0 {
+			t.Error("profile was created on a full host")
+		}
+	})
+
+	t.Run("open respects cap", func(t *testing.T) {
+		a, b := newFakeHost(t, "p1"), newFakeHost(t)
+		a.openOn("x1")
+		fleet := newFleet(t, a, b, []FleetHostOption{WithMaxBrowsers(1)}, nil)
+
+		if _, err := fleet.Open(context.Background(), "p1", nil); !errors.Is(err, ErrNoCapacity) {
+			t.Errorf("expected ErrNoCapacity, got %v", err)
+		}
+		if a.callsTo("/browser/open") != 0 {
+			t.Error("open was sent to a full host")
+		}
+
+		// Reopening a running browser is not a new browser
+		if _, err := fleet.Open(context.Background(), "x1", nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("failing agent is ignored", func(t *testing.T) {
+		a, b := newFakeHost(t), newFakeHost(t)
+		broken := MetricsAgentFunc(func(ctx context.Context) (HostMetrics, error) {
+			return HostMetrics{}, errors.New("agent down")
+		})
+		fleet := newFleet(t, a, b, []FleetHostOption{WithMetricsAgent(broken)}, nil)
+
+		loads, err := fleet.Loads(context.Background())
+		if err != nil || len(loads) != 2 || loads[0].Metrics != nil {
+			t.Errorf("loads = %+v, err = %v", loads, err)
+		}
+	})
+
+	t.Run("negative cap", func(t *testing.T) {
+		a := newFakeHost(t)
+		if _, err := NewFleetClient(WithFleetHost("a", mustNew(t, a.URL), WithMaxBrowsers(-1))); !errors.Is(err, ErrValidation) {
+			t.Errorf("expected ErrValidation, got %v", err)
+		}
+	})
+}
+
+func TestHTTPMetricsAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(HostMetrics{CPU: 42.5, Memory: 61})
+	}))
+	defer server.Close()
+
+	m, err := HTTPMetricsAgent(server.URL).Metrics(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m != (HostMetrics{CPU: 42.5, Memory: 61}) {
+		t.Errorf("metrics = %+v", m)
+	}
+}
@@ -0,0 +1,155 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// seqCache maps window sequence numbers, as the BitBrowser GUI shows them,
+// to profile IDs and back. A profile keeps its sequence number for life, so
+// entries are only dropped when the profile is deleted. It is filled by
+// every call that returns profile details.
+type seqCache struct {
+	mu   sync.Mutex
+	ids  map[int]string // Sequence number -> profile ID
+	seqs map[string]int // Profile ID -> sequence number
+}
+
+// remember records the sequence number of a profile.
+func (s *seqCache) remember(seq int, id string) {
+	if seq <= 0 || id == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ids == nil {
+		s.ids = make(map[int]string)
+		s.seqs = make(map[string]int)
+	}
+	if old, ok := s.seqs[id]; ok && old != seq {
+		delete(s.ids, old)
+	}
+	if old, ok := s.ids[seq]; ok && old != id {
+		delete(s.seqs, old)
+	}
+	s.ids[seq] = id
+	s.seqs[id] = seq
+}
+
+// rememberAll records the sequence numbers of listed profiles.
+func (s *seqCache) rememberAll(profiles []ProfileDetail) {
+	for _, p := range profiles {
+		s.remember(p.Seq, p.ID)
+	}
+}
+
+// id returns the cached profile ID of a sequence number.
+func (s *seqCache) id(seq int) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.ids[seq]
+	return id, ok
+}
+
+// seq returns the cached sequence number of a profile.
+func (s *seqCache) seq(id string) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seq, ok := s.seqs[id]
+	return seq, ok
+}
+
+// forget drops deleted profiles.
+func (s *seqCache) forget(ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		if seq, ok := s.seqs[id]; ok {
+			delete(s.ids, seq)
+			delete(s.seqs, id)
+		}
+	}
+}
+
+// validateSeq checks that seq can be a window sequence number.
+func validateSeq(seq int) error {
+	if seq <= 0 {
+		return &ValidationError{Field: "seq", Message: "sequence number must be positive", Value: seq}
+	}
+	return nil
+}
+
+// GetProfileBySeq gets the details of the profile with a window sequence
+// number, as shown in the BitBrowser GUI. If no profile has it, the error
+// matches ErrProfileNotFound.
+func (c *Client) GetProfileBySeq(ctx context.Context, seq int) (*ProfileDetail, error) {
+	if err := validateSeq(seq); err != nil {
+		return nil, err
+	}
+	result, err := c.ListProfiles(ctx, ListRequest{Seq: seq, PageSize: 10})
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range result.List {
+		if p.Seq == seq {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("bitbrowser: no profile has seq %d: %w", seq, ErrProfileNotFound)
+}
+
+// ResolveSeq returns the ID of the profile with a window sequence number.
+// Results are cached: sequence numbers never move to another profile, and
+// profiles returned by ListProfiles and GetProfileDetail are recorded too,
+// so a listing resolves every seq on it without further requests.
+func (c *Client) ResolveSeq(ctx context.Context, seq int) (string, error) {
+	if err := validateSeq(seq); err != nil {
+		return "", err
+	}
+	if id, ok := c.seqs.id(seq); ok {
+		return id, nil
+	}
+	p, err := c.GetProfileBySeq(ctx, seq)
+	if err != nil {
+		return "", err
+	}
+	return p.ID, nil
+}
+
+// SeqOf returns the window sequence number of a profile, from the cache
+// described at ResolveSeq or else from GetProfileDetail.
+func (c *Client) SeqOf(ctx context.Context, id string) (int, error) {
+	if seq, ok := c.seqs.seq(id); ok {
+		return seq, nil
+	}
+	detail, err := c.GetProfileDetail(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return detail.Seq, nil
+}
+
+// OpenBySeq opens the profile with a window sequence number, like Open.
+//
+// Example:
+//
+//	// "Open window 42" from an operator who reads seqs off the GUI
+//	result, err := client.OpenBySeq(ctx, 42, &bitbrowser.OpenOptions{WaitReady: true})
+func (c *Client) OpenBySeq(ctx context.Context, seq int, opts *OpenOptions) (*OpenResult, error) {
+	id, err := c.ResolveSeq(ctx, seq)
+	if err != nil {
+		return nil, err
+	}
+	return c.Open(ctx, id, opts)
+}
+
+// CloseBySeq closes the browser of the profile with a window sequence
+// number, like Close. Use CloseBySeqs to close several at once.
+func (c *Client) CloseBySeq(ctx context.Context, seq int) error {
+	id, err := c.ResolveSeq(ctx, seq)
+	if err != nil {
+		return err
+	}
+	return c.Close(ctx, id)
+}
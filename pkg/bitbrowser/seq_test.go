@@ -0,0 +1,167 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// seqServer serves profiles p1 (seq 1) and p2 (seq 2) and records the
+// requests it receives.
+type seqServer struct {
+	mu       sync.Mutex
+	requests map[string]int
+	opened   string
+	closed   string
+}
+
+func newSeqServer(t *testing.T) (*seqServer, *Client) {
+	t.Helper()
+	s := &seqServer{requests: make(map[string]int)}
+	profiles := []ProfileDetail{{ID: "p1", Seq: 1}, {ID: "p2", Seq: 2}}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ID  string `json:"id"`
+			Seq int    `json:"seq"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/browser/list":
+			var list []ProfileDetail
+			for _, p := range profiles {
+				if body.Seq == 0 || p.Seq == body.Seq {
+					list = append(list, p)
+				}
+			}
+			w.Write(successResponse(ListResult{List: list, Total: len(list)}))
+		case "/browser/detail":
+			for _, p := range profiles {
+				if p.ID == body.ID {
+					w.Write(successResponse(p))
+					return
+				}
+			}
+			w.Write(errorResponse("browser not found"))
+		case "/browser/open":
+			s.opened = body.ID
+			w.Write(successResponse(OpenResult{Http: "127.0.0.1:9222"}))
+		case "/browser/close":
+			s.closed = body.ID
+			w.Write(successResponse(nil))
+		default:
+			w.Write(successResponse(nil))
+		}
+	})
+	t.Cleanup(server.Close)
+	return s, mustNew(t, server.URL)
+}
+
+func (s *seqServer) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+func TestGetProfileBySeq(t *testing.T) {
+	_, client := newSeqServer(t)
+
+	p, err := client.GetProfileBySeq(context.Background(), 2)
+	if err != nil || p.ID != "p2" {
+		t.Fatalf("GetProfileBySeq(2) = %+v, %v", p, err)
+	}
+	if _, err := client.GetProfileBySeq(context.Background(), 7); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("GetProfileBySeq(7) err = %v, want ErrProfileNotFound", err)
+	}
+	if _, err := client.GetProfileBySeq(context.Background(), 0); !errors.Is(err, ErrValidation) {
+		t.Errorf("GetProfileBySeq(0) err = %v, want ErrValidation", err)
+	}
+}
+
+func TestResolveSeq(t *testing.T) {
+	t.Run("caches results", func(t *testing.T) {
+		s, client := newSeqServer(t)
+		for range 3 {
+			id, err := client.ResolveSeq(context.Background(), 1)
+			if err != nil || id != "p1" {
+				t.Fatalf("ResolveSeq(1) = %q, %v", id, err)
+			}
+		}
+		if n := s.count("/browser/list"); n != 1 {
+			t.Errorf("%d list requests, want 1", n)
+		}
+	})
+
+	t.Run("filled by listings", func(t *testing.T) {
+		s, client := newSeqServer(t)
+		if _, err := client.ListProfiles(context.Background(), ListRequest{PageSize: 100}); err != nil {
+			t.Fatal(err)
+		}
+		if id, err := client.ResolveSeq(context.Background(), 2); err != nil || id != "p2" {
+			t.Errorf("ResolveSeq(2) = %q, %v", id, err)
+		}
+		if seq, err := client.SeqOf(context.Background(), "p1"); err != nil || seq != 1 {
+			t.Errorf("SeqOf(p1) = %d, %v", seq, err)
+		}
+		if n := s.count("/browser/list") + s.count("/browser/detail"); n != 1 {
+			t.Errorf("%d requests, want only the listing", n)
+		}
+	})
+
+	t.Run("forgets deleted profiles", func(t *testing.T) {
+		s, client := newSeqServer(t)
+		client.ResolveSeq(context.Background(), 1)
+		if err := client.DeleteProfile(context.Background(), "p1"); err != nil {
+			t.Fatal(err)
+		}
+		client.ResolveSeq(context.Background(), 1)
+		if n := s.count("/browser/list"); n != 2 {
+			t.Errorf("%d list requests, want 2", n)
+		}
+	})
+
+	t.Run("SeqOf falls back to detail", func(t *testing.T) {
+		_, client := newSeqServer(t)
+		if seq, err := client.SeqOf(context.Background(), "p2"); err != nil || seq != 2 {
+			t.Errorf("SeqOf(p2) = %d, %v", seq, err)
+		}
+	})
+}
+
+func TestOpenCloseBySeq(t *testing.T) {
+	s, client := newSeqServer(t)
+
+	if _, err := client.OpenBySeq(context.Background(), 2, nil); err != nil {
+		t.Fatalf("OpenBySeq() error = %v", err)
+	}
+	if err := client.CloseBySeq(context.Background(), 2); err != nil {
+		t.Fatalf("CloseBySeq() error = %v", err)
+	}
+	if s.opened != "p2" || s.closed != "p2" {
+		t.Errorf("opened %q, closed %q, want p2", s.opened, s.closed)
+	}
+	if err := client.CloseBySeq(context.Background(), 9); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("CloseBySeq(9) err = %v, want ErrProfileNotFound", err)
+	}
+}
+
+func TestSeqCacheRemember(t *testing.T) {
+	var s seqCache
+	s.remember(1, "a")
+	s.remember(1, "b") // Seq reassigned, e.g. after a restore
+	if _, ok := s.seq("a"); ok {
+		t.Error("stale ID kept after its seq moved")
+	}
+	s.remember(2, "b") // Profile renumbered
+	if _, ok := s.id(1); ok {
+		t.Error("stale seq kept after its profile moved")
+	}
+	if id, _ := s.id(2); id != "b" {
+		t.Errorf("id(2) = %q, want b", id)
+	}
+}
@@ -0,0 +1,42 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// renderURL renders url as a text/template over the details of profile id,
+// e.g. "https://example.com/ref/{{.Seq}}" or "?name={{urlquery .Name}}".
+// URLs without actions are returned unchanged, without fetching the details.
+// field names the option in errors.
+func (c *Client) renderURL(ctx context.Context, id, field, url string) (string, error) {
+	if !strings.Contains(url, "{{") {
+		return url, nil
+	}
+	tmpl, err := template.New(field).Option("missingkey=error").Parse(url)
+	if err != nil {
+		return "", &ValidationError{Field: field, Message: err.Error(), Value: url}
+	}
+	detail, err := c.GetProfileDetail(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("bitbrowser: render %s: %w", field, err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, detail); err != nil {
+		return "", &ValidationError{Field: field, Message: err.Error(), Value: url}
+	}
+	return b.String(), nil
+}
+
+// renderStartURL returns opts with StartURL rendered by renderURL.
+func (c *Client) renderStartURL(ctx context.Context, id string, opts *OpenOptions) (*OpenOptions, error) {
+	url, err := c.renderURL(ctx, id, "StartURL", opts.StartURL)
+	if err != nil || url == opts.StartURL {
+		return opts, err
+	}
+	o := *opts
+	o.StartURL = url
+	return &o, nil
+}
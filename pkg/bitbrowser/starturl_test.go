@@ -0,0 +1,44 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStartURLTemplate(t *testing.T) {
+	ctx := context.Background()
+	server, client := newOpenServer(t, ProfileDetail{ID: "profile-1", Seq: 42, Name: "shop one"})
+
+	_, err := client.Open(ctx, "profile-1", &OpenOptions{
+		IgnoreDefaultUrls: true,
+		StartURL:          "https://example.com/ref/{{.Seq}}?name={{urlquery .Name}}",
+	})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if url, n := server.lastOpen().NewPageUrl, server.detailRequests(); url != "https://example.com/ref/42?name=shop+one" || n != 1 {
+		t.Errorf("newPageUrl = %q after %d detail requests", url, n)
+	}
+
+	if _, err := client.OpenRaw(ctx, OpenConfig{ID: "profile-1", NewPageUrl: "https://example.com/{{.ID}}"}); err != nil {
+		t.Fatalf("OpenRaw failed: %v", err)
+	}
+	if url := server.lastOpen().NewPageUrl; url != "https://example.com/profile-1" {
+		t.Errorf("raw newPageUrl = %q", url)
+	}
+
+	server.detailRequests()
+	if _, err := client.Open(ctx, "profile-1", &OpenOptions{IgnoreDefaultUrls: true, StartURL: "https://example.com/"}); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if url, n := server.lastOpen().NewPageUrl, server.detailRequests(); url != "https://example.com/" || n != 0 {
+		t.Errorf("plain newPageUrl = %q after %d detail requests", url, n)
+	}
+
+	for _, bad := range []string{"https://example.com/{{.Seq", "https://example.com/{{.Nope}}"} {
+		if _, err := client.Open(ctx, "profile-1", &OpenOptions{StartURL: bad}); !errors.Is(err, ErrValidation) {
+			t.Errorf("%s: err = %v, want ErrValidation", bad, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package bitbrowser
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// DefaultEndpointTimeouts returns the timeouts applied to API calls whose
+// context has no deadline, keyed by endpoint path. The key "" applies to
+// all endpoints not listed; it is not set by default.
+//
+//	/browser/open   120s (all of Open, including readiness and hooks)
+//	/browser/close   30s
+//	/browser/ports    5s
+func DefaultEndpointTimeouts() map[string]time.Duration {
+	return map[string]time.Duration{
+		"/browser/open":  120 * time.Second,
+		"/browser/close": 30 * time.Second,
+		"/browser/ports": 5 * time.Second,
+	}
+}
+
+// WithEndpointTimeouts sets timeouts for API calls made without a context
+// deadline, so that a forgotten context.WithTimeout cannot hang a worker
+// forever. The timeouts are merged into DefaultEndpointTimeouts; a zero
+// timeout removes an endpoint's default. A context deadline always takes
+// precedence.
+//
+// Example:
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithEndpointTimeouts(map[string]time.Duration{
+//	    "/browser/open": 3 * time.Minute, // Slow proxies
+//	    "":              time.Minute,     // Every other endpoint
+//	}))
+func WithEndpointTimeouts(timeouts map[string]time.Duration) ClientOption {
+	return func(c *Client) {
+		merged := maps.Clone(c.timeouts)
+		if merged == nil {
+			merged = DefaultEndpointTimeouts()
+		}
+		for path, timeout := range timeouts {
+			if timeout > 0 {
+				merged[path] = timeout
+			} else {
+				delete(merged, path)
+			}
+		}
+		c.timeouts = merged
+	}
+}
+
+// endpointTimeout bounds ctx by the timeout of path, unless ctx already has
+// a deadline.
+func (c *Client) endpointTimeout(ctx context.Context, path string) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	timeout, ok := c.timeouts[path]
+	if !ok {
+		timeout = c.timeouts[""]
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,95 @@
+package bitbrowser
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithEndpointTimeouts(t *testing.T) {
+	client := mustNew(t, "http://127.0.0.1:1", WithEndpointTimeouts(map[string]time.Duration{
+		"/browser/open":  time.Minute,
+		"/browser/close": 0,
+		"":               10 * time.Second,
+	}))
+	want := map[string]time.Duration{
+		"/browser/open":  time.Minute,
+		"/browser/ports": 5 * time.Second,
+		"":               10 * time.Second,
+	}
+	if len(client.timeouts) != len(want) {
+		t.Fatalf("timeouts = %v, want %v", client.timeouts, want)
+	}
+	for path, timeout := range want {
+		if client.timeouts[path] != timeout {
+			t.Errorf("timeouts[%q] = %v, want %v", path, client.timeouts[path], timeout)
+		}
+	}
+	if DefaultEndpointTimeouts()["/browser/close"] != 30*time.Second {
+		t.Error("WithEndpointTimeouts changed the defaults")
+	}
+}
+
+func TestEndpointTimeouts(t *testing.T) {
+	// The server answers after delay, or never if the request is canceled.
+	newServer := func(t *testing.T, delay time.Duration) string {
+		t.Helper()
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body) // Lets the server notice canceled requests
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+			switch r.URL.Path {
+			case "/browser/open":
+				w.Write(successResponse(OpenResult{Ws: "ws://127.0.0.1:9222/devtools/browser/abc"}))
+			case "/browser/pids/all":
+				w.Write(successResponse(map[string]int{"p1": 101}))
+			default:
+				w.Write(successResponse(map[string]string{"p1": "9222"}))
+			}
+		})
+		t.Cleanup(server.Close)
+		return server.URL
+	}
+
+	t.Run("bounds calls without a deadline", func(t *testing.T) {
+		client := mustNew(t, newServer(t, time.Minute), WithEndpointTimeouts(map[string]time.Duration{
+			"/browser/ports": 20 * time.Millisecond,
+			"/browser/open":  20 * time.Millisecond,
+		}))
+		start := time.Now()
+		if _, err := client.GetPorts(context.Background()); err == nil {
+			t.Error("GetPorts succeeded, want a timeout")
+		}
+		if _, err := client.Open(context.Background(), "p1", nil); err == nil {
+			t.Error("Open succeeded, want a timeout")
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("calls took %v", elapsed)
+		}
+	})
+
+	t.Run("the context deadline takes precedence", func(t *testing.T) {
+		client := mustNew(t, newServer(t, 50*time.Millisecond), WithEndpointTimeouts(map[string]time.Duration{
+			"/browser/ports": 10 * time.Millisecond,
+		}))
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := client.GetPorts(ctx); err != nil {
+			t.Errorf("GetPorts failed: %v", err)
+		}
+	})
+
+	t.Run("other endpoints are not bounded by default", func(t *testing.T) {
+		client := mustNew(t, newServer(t, 50*time.Millisecond), WithEndpointTimeouts(map[string]time.Duration{
+			"/browser/ports": 10 * time.Millisecond,
+		}))
+		if _, err := client.GetAllPIDs(context.Background()); err != nil {
+			t.Errorf("GetAllPIDs failed: %v", err)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package bitbrowser
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeZoneOffset returns the offset of an IANA time zone at a given time,
+// as Fingerprint.TimeZoneOffset expects it: the value of JavaScript's
+// Date.getTimezoneOffset, i.e. minutes behind UTC, such as -60 for
+// "Europe/Berlin" in winter and -120 in summer.
+func TimeZoneOffset(zone string, at time.Time) (int, error) {
+	loc, err := loadZone(zone)
+	if err != nil {
+		return 0, err
+	}
+	return zoneOffset(loc, at), nil
+}
+
+// ValidateTimeZone checks the time zone settings of a fingerprint: a
+// manual TimeZone must be a zone of the IANA tz database and only applies
+// with IsIpCreateTimeZone set to false, and a TimeZoneOffset must match the
+// zone at the given time, daylight saving included. A TimeZoneOffset of 0
+// is not checked, as it is also what an unset offset encodes to.
+// CreateProfile and UpdateProfile run it with the current time.
+func ValidateTimeZone(fp *Fingerprint, at time.Time) error {
+	if fp == nil || fp.TimeZone == "" {
+		if fp != nil && fp.TimeZoneOffset != 0 {
+			return &ValidationError{Field: "timeZoneOffset", Message: "an offset needs a manual timeZone", Value: fp.TimeZoneOffset}
+		}
+		return nil
+	}
+	if fp.IsIpCreateTimeZone == nil || *fp.IsIpCreateTimeZone {
+		return &ValidationError{Field: "isIpCreateTimeZone", Message: "must be false for the manual timeZone to apply", Value: fp.TimeZone}
+	}
+	loc, err := loadZone(fp.TimeZone)
+	if err != nil {
+		return err
+	}
+	if fp.TimeZoneOffset == 0 {
+		return nil
+	}
+
+	want := zoneOffset(loc, at)
+	if fp.TimeZoneOffset == want {
+		return nil
+	}
+	// Tell an offset from the other half of the year apart from a wrong one.
+	for _, month := range []time.Month{time.January, time.July} {
+		other := time.Date(at.Year(), month, 1, 12, 0, 0, 0, loc)
+		if zoneOffset(loc, other) == fp.TimeZoneOffset {
+			return &ValidationError{
+				Field: "timeZoneOffset",
+				Message: fmt.Sprintf("%d is the offset of %s in %s; on %s it is %d",
+					fp.TimeZoneOffset, fp.TimeZone, month, at.Format(time.DateOnly), want),
+				Value: fp.TimeZoneOffset,
+			}
+		}
+	}
+	return &ValidationError{
+		Field:   "timeZoneOffset",
+		Message: fmt.Sprintf("%s has offset %d on %s", fp.TimeZone, want, at.Format(time.DateOnly)),
+		Value:   fp.TimeZoneOffset,
+	}
+}
+
+// loadZone loads an IANA time zone, rejecting the names time.LoadLocation
+// accepts that are not zones of the database.
+func loadZone(zone string) (*time.Location, error) {
+	if zone == "" || zone == "Local" {
+		return nil, &ValidationError{Field: "timeZone", Message: `not an IANA time zone, e.g. "Europe/Berlin"`, Value: zone}
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, &ValidationError{Field: "timeZone", Message: `not an IANA time zone, e.g. "Europe/Berlin"`, Value: zone}
+	}
+	return loc, nil
+}
+
+// zoneOffset returns the Date.getTimezoneOffset value of loc at t.
+func zoneOffset(loc *time.Location, t time.Time) int {
+	_, seconds := t.In(loc).Zone()
+	return -seconds / 60
+}
@@ -0,0 +1,84 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // Do not depend on the system's tz database
+)
+
+func TestValidateTimeZone(t *testing.T) {
+	manual := func(zone string, offset int) *Fingerprint {
+		return &Fingerprint{IsIpCreateTimeZone: Bool(false), TimeZone: zone, TimeZoneOffset: offset}
+	}
+	summer := time.Date(2026, time.July, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		fp      *Fingerprint
+		field   string // Empty if valid
+		message string
+	}{
+		{"nil", nil, "", ""},
+		{"IP time zone", &Fingerprint{}, "", ""},
+		{"zone without offset", manual("Asia/Tokyo", 0), "", ""},
+		{"matching summer offset", manual("Europe/Berlin", -120), "", ""},
+		{"southern hemisphere", manual("Australia/Sydney", -600), "", ""},
+		{"offset without zone", &Fingerprint{TimeZoneOffset: -60}, "timeZoneOffset", "needs a manual timeZone"},
+		{"IP time zone still on", &Fingerprint{TimeZone: "Europe/Berlin"}, "isIpCreateTimeZone", "must be false"},
+		{"unknown zone", manual("Europe/Atlantis", 0), "timeZone", "not an IANA time zone"},
+		{"abbreviation", manual("GMT+8", 0), "timeZone", "not an IANA time zone"},
+		{"Local", manual("Local", 0), "timeZone", "not an IANA time zone"},
+		{"winter offset in summer", manual("Europe/Berlin", -60), "timeZoneOffset", "offset of Europe/Berlin in January; on 2026-07-15 it is -120"},
+		{"wrong sign", manual("America/New_York", -240), "timeZoneOffset", "America/New_York has offset 240 on 2026-07-15"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTimeZone(tt.fp, summer)
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("ValidateTimeZone() = %v, want nil", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("ValidateTimeZone() = %v, want a %s error containing %q", err, tt.field, tt.message)
+			}
+		})
+	}
+}
+
+func TestTimeZoneOffset(t *testing.T) {
+	winter := time.Date(2026, time.January, 15, 12, 0, 0, 0, time.UTC)
+	if offset, err := TimeZoneOffset("Asia/Kolkata", winter); err != nil || offset != -330 {
+		t.Errorf("TimeZoneOffset(Asia/Kolkata) = %d, %v; want -330", offset, err)
+	}
+	if offset, err := TimeZoneOffset("America/New_York", winter); err != nil || offset != 300 {
+		t.Errorf("TimeZoneOffset(America/New_York) = %d, %v; want 300", offset, err)
+	}
+	if _, err := TimeZoneOffset("Mars/Olympus", winter); err == nil {
+		t.Error("expected an error for an unknown zone")
+	}
+}
+
+func TestCreateProfileValidatesTimeZone(t *testing.T) {
+	called := false
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.Write(successResponse(map[string]string{"id": "new"}))
+	})
+	defer server.Close()
+	client := mustNew(t, server.URL)
+
+	_, err := client.CreateProfile(context.Background(), ProfileConfig{
+		BrowserFingerPrint: &Fingerprint{TimeZone: "Europe/Berlin"},
+	})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || called {
+		t.Errorf("CreateProfile() = %v, called = %v; want a ValidationError before the request", err, called)
+	}
+}
@@ -0,0 +1,168 @@
+package bitbrowser
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSelfSignedCert writes a self-signed certificate and key to dir and
+// returns their paths.
+func writeSelfSignedCert(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return certFile, keyFile
+}
+
+// writeServerCA writes the test server's certificate as a PEM CA file.
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.crt")
+	cert := server.Certificate()
+	os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o600)
+	return path
+}
+
+func TestTLSOptions(t *testing.T) {
+	t.Run("trusts custom CA file", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(nil))
+		}))
+		defer server.Close()
+
+		client := mustNew(t, server.URL, WithCAFile(writeServerCA(t, server)))
+		if err := client.Health(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("fails without trusted CA", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(nil))
+		}))
+		defer server.Close()
+
+		client := mustNew(t, server.URL)
+		if err := client.Health(context.Background()); err == nil {
+			t.Fatal("expected certificate error, got nil")
+		}
+	})
+
+	t.Run("presents client certificate", func(t *testing.T) {
+		var gotCN string
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.TLS.PeerCertificates) > 0 {
+				gotCN = r.TLS.PeerCertificates[0].Subject.CommonName
+			}
+			w.Write(successResponse(nil))
+		}))
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+		server.StartTLS()
+		defer server.Close()
+
+		certFile, keyFile := writeSelfSignedCert(t, t.TempDir(), "worker-1")
+		client := mustNew(t, server.URL,
+			WithCAFile(writeServerCA(t, server)),
+			WithClientCertificate(certFile, keyFile),
+		)
+		if err := client.Health(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if gotCN != "worker-1" {
+			t.Errorf("client certificate CN = %q, want worker-1", gotCN)
+		}
+	})
+
+	t.Run("applies to debug URL verification", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(BrowserVersion{Browser: "Chrome/130.0.0.0"})
+		}))
+		defer server.Close()
+
+		pool := x509.NewCertPool()
+		pool.AddCert(server.Certificate())
+		client := mustNew(t, "http://localhost:54345", WithTLSConfig(&tls.Config{RootCAs: pool}))
+
+		if !client.VerifyDebugURL(context.Background(), server.URL) {
+			t.Error("expected debug URL to verify over TLS")
+		}
+		if _, err := client.GetBrowserVersion(context.Background(), server.URL); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("missing certificate files", func(t *testing.T) {
+		_, err := New("https://localhost", WithClientCertificate("/nonexistent.crt", "/nonexistent.key"))
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("key without certificate", func(t *testing.T) {
+		_, keyFile := writeSelfSignedCert(t, t.TempDir(), "client")
+		_, err := New("https://localhost", WithClientCertificate("", keyFile))
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("leaves caller's RootCAs unchanged", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(nil))
+		}))
+		defer server.Close()
+
+		pool := x509.NewCertPool()
+		mustNew(t, server.URL, WithTLSConfig(&tls.Config{RootCAs: pool}), WithCAFile(writeServerCA(t, server)))
+		if !pool.Equal(x509.NewCertPool()) {
+			t.Error("WithCAFile added certificates to the WithTLSConfig pool")
+		}
+	})
+
+	t.Run("invalid CA file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bad.crt")
+		os.WriteFile(path, []byte("not a certificate"), 0o600)
+
+		_, err := New("https://localhost", WithCAFile(path))
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("expected ErrValidation, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package bitbrowser
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+// TrafficStats is the network traffic of a profile's browser pages, as
+// counted with WithTrafficAccounting.
+type TrafficStats struct {
+	ProfileID     string
+	Requests      int64
+	BytesSent     int64 // Estimated; see cdp.Session.MeterTraffic
+	BytesReceived int64
+	Since         time.Time // When counting started; zero if nothing was counted
+}
+
+// trafficBook holds the traffic meters of opened profiles.
+type trafficBook struct {
+	mu       sync.Mutex
+	profiles map[string]*profileTraffic
+}
+
+// profileTraffic is the traffic of one profile: the meter of its current
+// browser and the totals of earlier ones.
+type profileTraffic struct {
+	since time.Time
+	done  cdp.TrafficStats  // Traffic of retired meters
+	meter *cdp.TrafficMeter // Meter of the current browser, if any
+	base  cdp.TrafficStats  // What meter had counted at the last reset
+}
+
+// total returns the traffic counted so far.
+func (p *profileTraffic) total() cdp.TrafficStats {
+	t := p.done
+	if p.meter != nil {
+		s := p.meter.Stats()
+		t.Requests += s.Requests - p.base.Requests
+		t.BytesSent += s.BytesSent - p.base.BytesSent
+		t.BytesReceived += s.BytesReceived - p.base.BytesReceived
+	}
+	return t
+}
+
+// retire stops the current meter and adds its traffic to the totals.
+func (p *profileTraffic) retire() {
+	if p.meter != nil {
+		p.done = p.total()
+		p.meter.Stop()
+		p.meter, p.base = nil, cdp.TrafficStats{}
+	}
+}
+
+// stats returns the profile's traffic as TrafficStats.
+func (p *profileTraffic) stats(id string) TrafficStats {
+	t := p.total()
+	return TrafficStats{ProfileID: id, Requests: t.Requests, BytesSent: t.BytesSent, BytesReceived: t.BytesReceived, Since: p.since}
+}
+
+// WithTrafficAccounting counts the bytes every browser opened with Open
+// sends and receives through its first page, per profile, so that proxy
+// bandwidth can be attributed to profiles and campaigns. Query the counts
+// with GetTrafficStats and ListTrafficStats; TrafficReport turns them into
+// a spreadsheet.
+//
+// Counting runs on the DevTools connection kept open for ready hooks, and
+// continues across reopens of the same profile until ResetTrafficStats.
+//
+// Example:
+//
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithTrafficAccounting())
+//	// ... open profiles and run automation ...
+//	stats, err := client.GetTrafficStats(id)
+//	fmt.Printf("%s used %d MB\n", id, (stats.BytesSent+stats.BytesReceived)>>20)
+func WithTrafficAccounting() ClientOption {
+	return func(c *Client) {
+		c.traffic = &trafficBook{profiles: make(map[string]*profileTraffic)}
+	}
+}
+
+// meter records the traffic meter of a profile's newly opened browser.
+// Opening a running browser again returns the same page, so the previous
+// meter is retired rather than counting the page twice.
+func (b *trafficBook) meter(id string, m *cdp.TrafficMeter, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p := b.profiles[id]
+	if p == nil {
+		p = &profileTraffic{since: now}
+		b.profiles[id] = p
+	}
+	p.retire()
+	p.meter = m
+}
+
+// GetTrafficStats returns the traffic counted for a profile since it was
+// first opened, or since ResetTrafficStats. A profile that has not been
+// opened has zero traffic.
+func (c *Client) GetTrafficStats(id string) (*TrafficStats, error) {
+	if c.traffic == nil {
+		return nil, NewValidationError("Traffic", "traffic accounting is not enabled; use WithTrafficAccounting")
+	}
+	c.traffic.mu.Lock()
+	defer c.traffic.mu.Unlock()
+	p := c.traffic.profiles[id]
+	if p == nil {
+		return &TrafficStats{ProfileID: id}, nil
+	}
+	stats := p.stats(id)
+	return &stats, nil
+}
+
+// ListTrafficStats returns the traffic of every profile opened since
+// accounting started, sorted by profile ID.
+func (c *Client) ListTrafficStats() ([]TrafficStats, error) {
+	if c.traffic == nil {
+		return nil, NewValidationError("Traffic", "traffic accounting is not enabled; use WithTrafficAccounting")
+	}
+	c.traffic.mu.Lock()
+	defer c.traffic.mu.Unlock()
+	list := make([]TrafficStats, 0, len(c.traffic.profiles))
+	for id, p := range c.traffic.profiles {
+		list = append(list, p.stats(id))
+	}
+	slices.SortFunc(list, func(a, b TrafficStats) int { return strings.Compare(a.ProfileID, b.ProfileID) })
+	return list, nil
+}
+
+// ResetTrafficStats clears the traffic counted for a profile, e.g. after
+// billing it. A running browser is counted again from zero.
+func (c *Client) ResetTrafficStats(id string) {
+	if c.traffic == nil {
+		return
+	}
+	c.traffic.mu.Lock()
+	defer c.traffic.mu.Unlock()
+	if p := c.traffic.profiles[id]; p != nil {
+		p.done, p.since = cdp.TrafficStats{}, c.clock.Now()
+		if p.meter != nil {
+			p.base = p.meter.Stats()
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitTraffic polls until the profile's received bytes reach want.
+func waitTraffic(t *testing.T, client *Client, id string, want int64) *TrafficStats {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		stats, err := client.GetTrafficStats(id)
+		if err != nil {
+			t.Fatalf("GetTrafficStats failed: %v", err)
+		}
+		if stats.BytesReceived >= want || time.Now().After(deadline) {
+			return stats
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTrafficAccounting(t *testing.T) {
+	ctx := context.Background()
+	devtools := newFakeDevTools(t)
+	clock := NewFakeClock(time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC))
+	client := devToolsClient(t, devtools, WithTrafficAccounting(), WithClock(clock))
+
+	if _, err := client.Open(ctx, "profile-1", nil); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	devtools.emit("Network.requestWillBeSent", map[string]any{"request": map[string]any{"url": "http://a/", "method": "GET"}})
+	devtools.emit("Network.loadingFinished", map[string]any{"encodedDataLength": 2048})
+
+	stats := waitTraffic(t, client, "profile-1", 2048)
+	if stats.Requests != 1 || stats.BytesReceived != 2048 || stats.BytesSent == 0 || !stats.Since.Equal(clock.Now()) {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// Reopening the running browser keeps counting without double counting.
+	if _, err := client.Open(ctx, "profile-1", nil); err != nil {
+		t.Fatalf("second Open failed: %v", err)
+	}
+	devtools.emit("Network.loadingFinished", map[string]any{"encodedDataLength": 1000})
+	if stats := waitTraffic(t, client, "profile-1", 3048); stats.BytesReceived != 3048 {
+		t.Errorf("BytesReceived after reopen = %d, want 3048", stats.BytesReceived)
+	}
+
+	list, err := client.ListTrafficStats()
+	if err != nil || len(list) != 1 || list[0].ProfileID != "profile-1" {
+		t.Errorf("ListTrafficStats() = %+v, %v", list, err)
+	}
+	if report := TrafficReport(list); len(report.Rows) != 1 || report.Rows[0][3] != int64(3048) {
+		t.Errorf("TrafficReport rows = %v", report.Rows)
+	}
+
+	client.ResetTrafficStats("profile-1")
+	if stats, _ := client.GetTrafficStats("profile-1"); stats.BytesReceived != 0 || stats.Requests != 0 {
+		t.Errorf("stats after reset = %+v", stats)
+	}
+	devtools.emit("Network.loadingFinished", map[string]any{"encodedDataLength": 10})
+	if stats := waitTraffic(t, client, "profile-1", 10); stats.BytesReceived != 10 {
+		t.Errorf("BytesReceived after reset = %d, want 10", stats.BytesReceived)
+	}
+
+	if stats, err := client.GetTrafficStats("never-opened"); err != nil || stats.BytesReceived != 0 {
+		t.Errorf("GetTrafficStats(never-opened) = %+v, %v", stats, err)
+	}
+}
+
+func TestTrafficAccountingDisabled(t *testing.T) {
+	client := mustNew(t, "http://127.0.0.1:54345")
+	if _, err := client.GetTrafficStats("profile-1"); !errors.Is(err, ErrValidation) {
+		t.Errorf("err = %v, want ErrValidation", err)
+	}
+}
@@ -0,0 +1,101 @@
+package bitbrowser
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Settings of DefaultTransport.
+const (
+	transportMaxIdleConnsPerHost = 64
+	transportKeepAlive           = 30 * time.Second
+	transportIdleConnTimeout     = 90 * time.Second
+	transportPingInterval        = 30 * time.Second
+	transportPingTimeout         = 15 * time.Second
+)
+
+// Response bodies of up to maxSizedBody bytes with a known length are read
+// into a slice of that size. Others are read into a pooled buffer, which is
+// only returned to the pool if it stayed below maxPooledBuffer.
+const (
+	maxSizedBody    = 32 << 20
+	maxPooledBuffer = 1 << 20
+)
+
+// bodyBuffers holds *bytes.Buffer for reading response bodies.
+var bodyBuffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// DefaultTransport returns the transport a Client uses unless WithTransport
+// or WithHTTPClient replaces it. It is tuned for many goroutines polling one
+// BitBrowser host (GetPorts, GetAlivePIDs, readiness waits):
+//
+//   - Up to 64 idle connections are kept per host, instead of net/http's 2,
+//     so that bursts of concurrent calls reuse connections rather than
+//     opening and closing one per call and leaving sockets in TIME_WAIT.
+//   - TCP keepalives every 30 seconds, and HTTP/2 pings after 30 idle
+//     seconds with a 15-second timeout, detect dead connections to a host
+//     that rebooted or dropped off the network.
+//   - Compression is disabled: responses are small JSON documents on a LAN,
+//     where gzip costs more CPU than it saves in bytes.
+//
+// Each call returns a new transport that may be modified.
+func DefaultTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: transportKeepAlive,
+	}).DialContext
+	t.MaxIdleConns = 0 // No limit across hosts
+	t.MaxIdleConnsPerHost = transportMaxIdleConnsPerHost
+	t.IdleConnTimeout = transportIdleConnTimeout
+	t.ForceAttemptHTTP2 = true
+	t.DisableCompression = true
+	t.HTTP2 = &http.HTTP2Config{
+		SendPingTimeout: transportPingInterval,
+		PingTimeout:     transportPingTimeout,
+	}
+	return t
+}
+
+// WithTransport replaces the transport of the client's HTTP client, keeping
+// its other settings (e.g. from WithHTTPClient). TLS options and WithDialContext
+// still apply if the transport is an *http.Transport.
+//
+// Example:
+//
+//	transport := bitbrowser.DefaultTransport()
+//	transport.MaxIdleConnsPerHost = 256 // Hundreds of workers on one host
+//	client, err := bitbrowser.New(apiURL, bitbrowser.WithTransport(transport))
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// readBody reads the body of resp with a single allocation of its size,
+// rather than growing a slice as io.ReadAll does.
+func readBody(resp *http.Response) ([]byte, error) {
+	if n := resp.ContentLength; n >= 0 && n <= maxSizedBody {
+		body := make([]byte, n)
+		if _, err := io.ReadFull(resp.Body, body); err != nil {
+			return nil, err
+		}
+		return body, nil
+	}
+
+	buf := bodyBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			bodyBuffers.Put(buf)
+		}
+	}()
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, err
+	}
+	return bytes.Clone(buf.Bytes()), nil
+}
@@ -0,0 +1,154 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// portsServer answers every request with one port after delay and counts
+// the connections it accepts.
+func portsServer(tb testing.TB, conns *atomic.Int32, delay time.Duration) *httptest.Server {
+	tb.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Write(successResponse(map[string]string{"profile-1": "9222"}))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	tb.Cleanup(server.Close)
+	return server
+}
+
+func TestDefaultTransport(t *testing.T) {
+	client := mustNew(t, "http://127.0.0.1:54345")
+	transport, ok := client.apiClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport = %T, want *http.Transport", client.apiClient.Transport)
+	}
+	if transport.MaxIdleConnsPerHost != transportMaxIdleConnsPerHost || !transport.DisableCompression {
+		t.Errorf("transport = %+v, want DefaultTransport settings", transport)
+	}
+	if DefaultTransport() == DefaultTransport() {
+		t.Error("DefaultTransport returned a shared transport")
+	}
+}
+
+func TestConnectionReuse(t *testing.T) {
+	var conns atomic.Int32
+	server := portsServer(t, &conns, 20*time.Millisecond) // Keeps the calls overlapping
+	client := mustNew(t, server.URL)
+
+	const workers = 16
+	for range 5 {
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.GetPorts(context.Background()); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	if n := conns.Load(); n > workers {
+		t.Errorf("opened %d connections for %d concurrent pollers, want at most %d", n, workers, workers)
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	var calls atomic.Int32
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls.Add(1)
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(successResponse(map[string]string{}))
+	})
+	defer server.Close()
+
+	httpClient := &http.Client{Timeout: time.Minute}
+	client := mustNew(t, server.URL, WithHTTPClient(httpClient), WithTransport(transport))
+	if _, err := client.GetPorts(context.Background()); err != nil {
+		t.Fatalf("GetPorts failed: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("transport used %d times, want 1", calls.Load())
+	}
+	if client.httpClient.Timeout != time.Minute || httpClient.Transport != nil {
+		t.Error("WithTransport did not keep the HTTP client's settings or modified it")
+	}
+}
+
+// BenchmarkGetPortsParallel compares net/http's default transport with
+// DefaultTransport for many goroutines polling one host.
+func BenchmarkGetPortsParallel(b *testing.B) {
+	for _, bm := range []struct {
+		name      string
+		transport http.RoundTripper
+	}{
+		{"net/http", http.DefaultTransport.(*http.Transport).Clone()},
+		{"DefaultTransport", DefaultTransport()},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			var conns atomic.Int32
+			server := portsServer(b, &conns, 0)
+			client, err := New(server.URL, WithTransport(bm.transport))
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.SetParallelism(16)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := client.GetPorts(context.Background()); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.ReportMetric(float64(conns.Load()), "conns")
+		})
+	}
+}
+
+func TestReadBody(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		length int64
+	}{
+		{"known length", 5},
+		{"unknown length", -1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 3 { // Reuses pooled buffers
+				resp := &http.Response{Body: io.NopCloser(strings.NewReader("hello")), ContentLength: tt.length}
+				body, err := readBody(resp)
+				if err != nil || string(body) != "hello" {
+					t.Fatalf("readBody() = %q, %v", body, err)
+				}
+			}
+		})
+	}
+
+	t.Run("short body", func(t *testing.T) {
+		resp := &http.Response{Body: io.NopCloser(strings.NewReader("hel")), ContentLength: 5}
+		if _, err := readBody(resp); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("err = %v, want io.ErrUnexpectedEOF", err)
+		}
+	})
+}
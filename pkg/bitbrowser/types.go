@@ -4,7 +4,10 @@ package bitbrowser
 // Based on BitBrowser's official API documentation.
 // All endpoints use POST method with JSON body.
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ============================================================================
 // Common Response Structure
@@ -15,12 +18,24 @@ type Response struct {
 	Success bool            `json:"success"`
 	Msg     string          `json:"msg,omitempty"`
 	Data    json.RawMessage `json:"data,omitempty"`
+
+	raw []byte // Undecoded body, for error reporting
 }
 
 // ============================================================================
 // Fingerprint Configuration
 // ============================================================================
 
+// Bool returns a pointer to v, for the *bool fields of ProfileConfig and
+// Fingerprint.
+//
+// Those fields default to true on the BitBrowser side, so a plain bool could
+// never turn them off: false would be omitted from the request. Leave them nil
+// to keep BitBrowser's default, or set Bool(false) to disable them.
+func Bool(v bool) *bool {
+	return &v
+}
+
 // Fingerprint represents the browser fingerprint configuration.
 type Fingerprint struct {
 	// Core settings
@@ -28,7 +43,7 @@ type Fingerprint struct {
 	CoreVersion string `json:"coreVersion,omitempty"` // e.g., "130" for Chrome, "128" for Firefox
 
 	// OS settings
-	OSType    string `json:"ostype,omitempty"`    // "PC" | "Android" | "IOS"
+	OSType    OSType `json:"ostype,omitempty"`    // OSTypePC, OSTypeAndroid or OSTypeIOS
 	OS        string `json:"os,omitempty"`        // "Win32", "MacIntel", "Linux x86_64", "iPhone", "Linux armv81"
 	OSVersion string `json:"osVersion,omitempty"` // e.g., "11,10" for Windows
 
@@ -37,9 +52,9 @@ type Fingerprint struct {
 	UserAgent string `json:"userAgent,omitempty"` // Custom UA, leave empty for auto-generation
 
 	// Timezone settings
-	IsIpCreateTimeZone bool   `json:"isIpCreateTimeZone,omitempty"` // Generate timezone based on IP
+	IsIpCreateTimeZone *bool  `json:"isIpCreateTimeZone,omitempty"` // Generate timezone based on IP, default true
 	TimeZone           string `json:"timeZone,omitempty"`           // Manual timezone
-	TimeZoneOffset     int    `json:"timeZoneOffset,omitempty"`     // Timezone offset
+	TimeZoneOffset     int    `json:"timeZoneOffset,omitempty"`     // Timezone offset in minutes, as Date.getTimezoneOffset
 
 	// WebRTC settings: "0"=replace, "1"=allow, "2"=disable, "3"=privacy
 	WebRTC string `json:"webRTC,omitempty"`
@@ -49,13 +64,13 @@ type Fingerprint struct {
 
 	// Geolocation: "0"=ask, "1"=allow, "2"=disable
 	Position           string `json:"position,omitempty"`
-	IsIpCreatePosition bool   `json:"isIpCreatePosition,omitempty"` // Generate position based on IP
+	IsIpCreatePosition *bool  `json:"isIpCreatePosition,omitempty"` // Generate position based on IP, default true
 	Lat                string `json:"lat,omitempty"`                // Latitude
 	Lng                string `json:"lng,omitempty"`                // Longitude
 	PrecisionData      string `json:"precisionData,omitempty"`      // Precision in meters
 
 	// Language settings
-	IsIpCreateLanguage        bool   `json:"isIpCreateLanguage,omitempty"`
+	IsIpCreateLanguage        *bool  `json:"isIpCreateLanguage,omitempty"` // Default true
 	Languages                 string `json:"languages,omitempty"`
 	IsIpCreateDisplayLanguage bool   `json:"isIpCreateDisplayLanguage,omitempty"`
 	DisplayLanguages          string `json:"displayLanguages,omitempty"`
@@ -67,7 +82,7 @@ type Fingerprint struct {
 	// Resolution: "0"=follow system, "1"=custom
 	ResolutionType  string `json:"resolutionType,omitempty"`
 	Resolution      string `json:"resolution,omitempty"`      // e.g., "1920 x 1080"
-	WindowSizeLimit bool   `json:"windowSizeLimit,omitempty"` // Limit window size to resolution
+	WindowSizeLimit *bool  `json:"windowSizeLimit,omitempty"` // Limit window size to resolution, default true
 
 	// Display
 	DevicePixelRatio float64 `json:"devicePixelRatio,omitempty"` // 1, 1.5, 2, 2.5, 3
@@ -101,14 +116,14 @@ type Fingerprint struct {
 	DoNotTrack string `json:"doNotTrack,omitempty"`
 
 	// ClientRects noise
-	ClientRectNoiseEnabled bool `json:"clientRectNoiseEnabled,omitempty"`
+	ClientRectNoiseEnabled *bool `json:"clientRectNoiseEnabled,omitempty"` // Default true
 
 	// Port scan protection: "0"=enabled, "1"=disabled
 	PortScanProtect string `json:"portScanProtect,omitempty"`
 	PortWhiteList   string `json:"portWhiteList,omitempty"` // Comma-separated ports
 
 	// Device info
-	DeviceInfoEnabled bool   `json:"deviceInfoEnabled,omitempty"`
+	DeviceInfoEnabled *bool  `json:"deviceInfoEnabled,omitempty"` // Default true
 	ComputerName      string `json:"computerName,omitempty"`
 	MacAddr           string `json:"macAddr,omitempty"`
 	HostIP            string `json:"hostIP,omitempty"`
@@ -123,6 +138,10 @@ type Fingerprint struct {
 
 	// Launch arguments (comma-separated, e.g., "--incognito,--no-sandbox")
 	LaunchArgs string `json:"launchArgs,omitempty"`
+
+	// Extra holds fingerprint settings the SDK does not model yet. They are
+	// merged into the browserFingerPrint object.
+	Extra map[string]any `json:"-"`
 }
 
 // ============================================================================
@@ -132,7 +151,7 @@ type Fingerprint struct {
 // ProfileConfig represents the full configuration for creating/updating a browser profile.
 type ProfileConfig struct {
 	// Basic info
-	ID      string `json:"id,omitempty"`      // Only for updates
+	ID      string `json:"id,omitempty"` // Only for updates
 	Name    string `json:"name,omitempty"`
 	GroupID string `json:"groupId,omitempty"` // Group ID, defaults to "API" group
 	Remark  string `json:"remark,omitempty"`
@@ -149,18 +168,18 @@ type ProfileConfig struct {
 	IsSynOpen bool `json:"isSynOpen,omitempty"` // Allow multiple opens of same profile
 
 	// Proxy settings
-	ProxyMethod   int    `json:"proxyMethod,omitempty"`   // 2=custom, 3=extract IP
-	ProxyType     string `json:"proxyType,omitempty"`     // "noproxy", "http", "https", "socks5", "ssh"
-	Host          string `json:"host,omitempty"`
-	Port          int    `json:"port,omitempty"`
-	ProxyUserName string `json:"proxyUserName,omitempty"`
-	ProxyPassword string `json:"proxyPassword,omitempty"`
+	ProxyMethod   int       `json:"proxyMethod,omitempty"` // 2=custom, 3=extract IP
+	ProxyType     ProxyType `json:"proxyType,omitempty"`   // ProxyTypeNone, ProxyTypeHTTP, ...
+	Host          string    `json:"host,omitempty"`
+	Port          int       `json:"port,omitempty"`
+	ProxyUserName string    `json:"proxyUserName,omitempty"`
+	ProxyPassword string    `json:"proxyPassword,omitempty"`
 
 	// IP settings
-	IpCheckService  string `json:"ipCheckService,omitempty"`  // "ip123in", "ip-api", "luminati"
-	IsIpv6          bool   `json:"isIpv6,omitempty"`
-	RefreshProxyUrl string `json:"refreshProxyUrl,omitempty"` // Proxy refresh URL
-	EnableSocks5Udp bool   `json:"enableSocks5Udp,omitempty"` // Enable UDP for SOCKS5
+	IpCheckService  IpCheckService `json:"ipCheckService,omitempty"` // "ip123in", "ip-api", "luminati"
+	IsIpv6          bool           `json:"isIpv6,omitempty"`
+	RefreshProxyUrl string         `json:"refreshProxyUrl,omitempty"` // Proxy refresh URL
+	EnableSocks5Udp bool           `json:"enableSocks5Udp,omitempty"` // Enable UDP for SOCKS5
 
 	// Location for dynamic proxy
 	Country  string `json:"country,omitempty"`
@@ -168,14 +187,14 @@ type ProfileConfig struct {
 	City     string `json:"city,omitempty"`
 
 	// Dynamic IP settings
-	DynamicIpUrl       string `json:"dynamicIpUrl,omitempty"`       // Extract IP URL
-	DynamicIpChannel   string `json:"dynamicIpChannel,omitempty"`   // "rola", "doveip", "cloudam", "common"
-	IsDynamicIpChangeIp bool   `json:"isDynamicIpChangeIp,omitempty"` // Extract new IP on each open
-	DuplicateCheck     int    `json:"duplicateCheck,omitempty"`     // 1=check, 0=no check
-	IsGlobalProxyInfo  bool   `json:"isGlobalProxyInfo,omitempty"`  // Use global dynamic proxy info
+	DynamicIpUrl        string           `json:"dynamicIpUrl,omitempty"`        // Extract IP URL
+	DynamicIpChannel    DynamicIpChannel `json:"dynamicIpChannel,omitempty"`    // "rola", "doveip", "cloudam", "common"
+	IsDynamicIpChangeIp bool             `json:"isDynamicIpChangeIp,omitempty"` // Extract new IP on each open
+	DuplicateCheck      int              `json:"duplicateCheck,omitempty"`      // 1=check, 0=no check
+	IsGlobalProxyInfo   bool             `json:"isGlobalProxyInfo,omitempty"`   // Use global dynamic proxy info
 
-	// Workbench: "localserver" or "disable"
-	Workbench string `json:"workbench,omitempty"`
+	// Workbench: WorkbenchLocalServer or WorkbenchDisable
+	Workbench Workbench `json:"workbench,omitempty"`
 
 	// Media settings
 	AbortImage        bool `json:"abortImage,omitempty"`        // Block images
@@ -189,14 +208,14 @@ type ProfileConfig struct {
 	StopWhileCountryChange bool `json:"stopWhileCountryChange,omitempty"`
 
 	// Sync settings
-	SyncTabs          bool `json:"syncTabs,omitempty"`          // Default true
-	SyncCookies       bool `json:"syncCookies,omitempty"`       // Default true
-	SyncIndexedDb     bool `json:"syncIndexedDb,omitempty"`
-	SyncLocalStorage  bool `json:"syncLocalStorage,omitempty"`
-	SyncBookmarks     bool `json:"syncBookmarks,omitempty"`
-	SyncAuthorization bool `json:"syncAuthorization,omitempty"` // Sync saved passwords
-	SyncHistory       bool `json:"syncHistory,omitempty"`
-	SyncExtensions    bool `json:"syncExtensions,omitempty"`
+	SyncTabs          *bool `json:"syncTabs,omitempty"`    // Default true
+	SyncCookies       *bool `json:"syncCookies,omitempty"` // Default true
+	SyncIndexedDb     bool  `json:"syncIndexedDb,omitempty"`
+	SyncLocalStorage  bool  `json:"syncLocalStorage,omitempty"`
+	SyncBookmarks     bool  `json:"syncBookmarks,omitempty"`
+	SyncAuthorization bool  `json:"syncAuthorization,omitempty"` // Sync saved passwords
+	SyncHistory       bool  `json:"syncHistory,omitempty"`
+	SyncExtensions    bool  `json:"syncExtensions,omitempty"`
 
 	// Credentials
 	CredentialsEnableService bool `json:"credentialsEnableService,omitempty"` // Disable save password popup
@@ -206,10 +225,10 @@ type ProfileConfig struct {
 	IsValidUsername bool `json:"isValidUsername,omitempty"` // Check duplicate by platform/username/password
 
 	// Clear before launch
-	ClearCacheFilesBeforeLaunch    bool `json:"clearCacheFilesBeforeLaunch,omitempty"`
-	ClearCacheWithoutExtensions    bool `json:"clearCacheWithoutExtensions,omitempty"`
-	ClearCookiesBeforeLaunch       bool `json:"clearCookiesBeforeLaunch,omitempty"`
-	ClearHistoriesBeforeLaunch     bool `json:"clearHistoriesBeforeLaunch,omitempty"`
+	ClearCacheFilesBeforeLaunch bool `json:"clearCacheFilesBeforeLaunch,omitempty"`
+	ClearCacheWithoutExtensions bool `json:"clearCacheWithoutExtensions,omitempty"`
+	ClearCookiesBeforeLaunch    bool `json:"clearCookiesBeforeLaunch,omitempty"`
+	ClearHistoriesBeforeLaunch  bool `json:"clearHistoriesBeforeLaunch,omitempty"`
 
 	// Random fingerprint on each launch
 	RandomFingerprint bool `json:"randomFingerprint,omitempty"`
@@ -223,6 +242,10 @@ type ProfileConfig struct {
 
 	// Fingerprint object (required, can be empty {})
 	BrowserFingerPrint *Fingerprint `json:"browserFingerPrint"`
+
+	// Extra holds fields the SDK does not model yet. They are merged into the
+	// request JSON and replace fields of the same name.
+	Extra map[string]any `json:"-"`
 }
 
 // ============================================================================
@@ -230,9 +253,23 @@ type ProfileConfig struct {
 // ============================================================================
 
 // PartialUpdateRequest represents a batch partial update request.
+//
+// Without UpdateFields, fields of ProfileConfig that hold their zero value
+// are omitted, so they cannot be set to false, 0 or "". List the fields to
+// change in UpdateFields to send exactly those fields, zero values included:
+//
+//	req := PartialUpdateRequest{
+//	    IDs:           ids,
+//	    ProfileConfig: ProfileConfig{Remark: "", MuteAudio: false},
+//	    UpdateFields:  []string{"remark", "muteAudio"},
+//	}
 type PartialUpdateRequest struct {
 	IDs []string `json:"ids"` // Profile IDs to update
 	ProfileConfig
+
+	// UpdateFields is a field mask of ProfileConfig fields to send, by JSON
+	// name ("muteAudio") or Go name ("MuteAudio").
+	UpdateFields []string `json:"-"`
 }
 
 // ============================================================================
@@ -260,8 +297,17 @@ type OpenOptions struct {
 
 	// StartURL specifies a URL to open when the browser starts.
 	// Only works when IgnoreDefaultUrls is true.
+	// It may be a template over the profile's details, e.g.
+	// "https://example.com/ref/{{.Seq}}"; see Client.Open.
+	// In headless mode BitBrowser requires it to be empty, so Open loads it
+	// over CDP once the browser is up and reports an OpenWarning.
 	StartURL string
 
+	// KeepHeadlessURLs sends IgnoreDefaultUrls and StartURL as given in
+	// headless mode. By default headless mode forces IgnoreDefaultUrls and
+	// loads StartURL after launch instead.
+	KeepHeadlessURLs bool
+
 	// CustomPort specifies a fixed debugging port.
 	// If 0, a random port will be assigned by BitBrowser.
 	// Useful when you need a predictable port.
@@ -276,9 +322,23 @@ type OpenOptions struct {
 	LoadExtensions string
 
 	// ExtraArgs allows passing additional Chrome arguments.
-	// These are appended to the args array.
+	// These are appended to the args array, after those set by WithLaunchArgs,
+	// and may use the same {{variables}}.
 	ExtraArgs []string
 
+	// SkipArgValidation sends the arguments without checking them with
+	// ValidateChromeArgs.
+	SkipArgValidation bool
+
+	// OnReady hooks run after the browser has opened, after those
+	// registered with WithReadyHook. See ReadyHook.
+	OnReady []ReadyHook
+
+	// RandomizeViewport opens the browser with a random realistic window
+	// size within the range, so that sessions do not share one size. The
+	// size is reported in OpenResult.Viewport. See ViewportRange.
+	RandomizeViewport *ViewportRange
+
 	// WaitReady waits for the browser to be fully ready before returning.
 	// If the browser is still starting, it will poll until ready.
 	// Default timeout is 30 seconds.
@@ -286,11 +346,65 @@ type OpenOptions struct {
 
 	// WaitTimeout specifies the maximum time in seconds to wait for browser ready.
 	// Only used when WaitReady is true. Default is 30 seconds.
+	// WaitTimeoutDuration takes precedence.
 	WaitTimeout int
 
 	// PollInterval specifies the interval in seconds between browser ready checks.
 	// Only used when WaitReady is true. Default is 2 seconds.
+	// PollIntervalDuration takes precedence.
 	PollInterval int
+
+	// WaitTimeoutDuration is WaitTimeout as a time.Duration. If set, it
+	// replaces WaitTimeout.
+	WaitTimeoutDuration time.Duration
+
+	// PollIntervalDuration is PollInterval as a time.Duration, allowing
+	// sub-second polling. If set, it replaces PollInterval.
+	PollIntervalDuration time.Duration
+
+	// PollJitter randomizes each poll interval by up to this fraction in
+	// either direction, so that many workers opening browsers at once do
+	// not poll in step. Value between 0 and 1; default is 0 (no jitter).
+	PollJitter float64
+
+	// ProxyOverride updates the profile's proxy right before opening.
+	// This lets one stored profile be launched through many rotating proxies.
+	// If nil, the profile's stored proxy is used.
+	ProxyOverride *ProxyOverride
+
+	// RetryPolicy retries the open when it fails because the profile is
+	// busy or the browser kernel is still downloading. When set, it replaces
+	// the client's OpenBusyPolicy for this call.
+	RetryPolicy *OpenRetryPolicy
+
+	// WaitForCapacity waits for a browser to close when the cap set with
+	// WithMaxOpenBrowsers is reached, instead of failing with ErrNoCapacity.
+	// The context bounds the wait.
+	WaitForCapacity bool
+}
+
+// ProxyOverride describes a proxy applied to a profile just before it is opened.
+//
+// The override is written to the profile via the proxy update API, because
+// BitBrowser has no per-launch proxy parameter. Set Restore to put the
+// original proxy back once the browser has launched; the running browser
+// keeps using the override.
+type ProxyOverride struct {
+	ProxyMethod   int       // 2=custom, 3=extract IP; defaults to ProxyMethodCustom
+	ProxyType     ProxyType // "http", "https", "socks5", "ssh", "noproxy"
+	Host          string
+	Port          int
+	ProxyUserName string
+	ProxyPassword string
+
+	// DynamicIpUrl and DynamicIpChannel configure ProxyMethodExtract, which
+	// requires DynamicIpUrl.
+	DynamicIpUrl     string
+	DynamicIpChannel DynamicIpChannel
+
+	// Restore restores the profile's previous proxy settings after the open
+	// attempt, whether it succeeded or not.
+	Restore bool
 }
 
 // OpenConfig represents the raw API request for opening a browser.
@@ -300,7 +414,11 @@ type OpenConfig struct {
 	Args              []string `json:"args,omitempty"`              // Chromium launch arguments
 	Queue             bool     `json:"queue,omitempty"`             // Queue mode to prevent concurrent errors
 	IgnoreDefaultUrls bool     `json:"ignoreDefaultUrls,omitempty"` // Ignore synced URLs
-	NewPageUrl        string   `json:"newPageUrl,omitempty"`        // URL to open (requires IgnoreDefaultUrls)
+	NewPageUrl        string   `json:"newPageUrl,omitempty"`        // URL to open (requires IgnoreDefaultUrls); OpenRaw renders it like OpenOptions.StartURL
+
+	// Extra is merged into the request JSON, for open parameters the SDK
+	// does not model yet.
+	Extra map[string]any `json:"-"`
 }
 
 // OpenResult contains the browser connection information after opening.
@@ -314,6 +432,12 @@ type OpenResult struct {
 	Remark      string `json:"remark"`      // Profile remark
 	GroupID     string `json:"groupId"`     // Group ID
 	PID         int    `json:"pid"`         // Process ID
+
+	// Warnings lists what Open did differently from what OpenOptions asked.
+	Warnings []OpenWarning `json:"-"`
+
+	// Viewport is the size picked for OpenOptions.RandomizeViewport.
+	Viewport Viewport `json:"-"`
 }
 
 // ============================================================================
@@ -336,28 +460,37 @@ type BrowserVersion struct {
 
 // ProfileDetail contains detailed information about a browser profile.
 type ProfileDetail struct {
-	ID                   string       `json:"id"`
-	Seq                  int          `json:"seq"`
-	Name                 string       `json:"name"`
-	Remark               string       `json:"remark"`
-	Platform             string       `json:"platform"`
-	URL                  string       `json:"url"`
-	UserName             string       `json:"userName"`
-	Password             string       `json:"password"`
-	Cookie               string       `json:"cookie"`
-	Status               int          `json:"status"`
-	GroupID              string       `json:"groupId"`
-	CreatedTime          string       `json:"createdTime"`
-	ProxyMethod          int          `json:"proxyMethod"`
-	ProxyType            string       `json:"proxyType"`
-	Host                 string       `json:"host"`
-	Port                 int          `json:"port"`
-	ProxyUserName        string       `json:"proxyUserName"`
-	ProxyPassword        string       `json:"proxyPassword"`
-	LastIp               string       `json:"lastIp"`
-	LastCountry          string       `json:"lastCountry"`
-	BrowserFingerPrint   *Fingerprint `json:"browserFingerPrint"`
-	// ... many more fields available in full response
+	ID                  string           `json:"id"`
+	Seq                 int              `json:"seq"`
+	Name                string           `json:"name"`
+	Remark              string           `json:"remark"`
+	Platform            string           `json:"platform"`
+	URL                 string           `json:"url"`
+	UserName            string           `json:"userName"`
+	Password            string           `json:"password"`
+	Cookie              string           `json:"cookie"`
+	Status              int              `json:"status"`
+	GroupID             string           `json:"groupId"`
+	CreatedTime         string           `json:"createdTime"`
+	ProxyMethod         int              `json:"proxyMethod"`
+	ProxyType           ProxyType        `json:"proxyType"`
+	Host                string           `json:"host"`
+	Port                int              `json:"port"`
+	ProxyUserName       string           `json:"proxyUserName"`
+	ProxyPassword       string           `json:"proxyPassword"`
+	IpCheckService      IpCheckService   `json:"ipCheckService"`
+	IsIpv6              bool             `json:"isIpv6"`
+	RefreshProxyUrl     string           `json:"refreshProxyUrl"`
+	DynamicIpUrl        string           `json:"dynamicIpUrl"`
+	DynamicIpChannel    DynamicIpChannel `json:"dynamicIpChannel"`
+	IsDynamicIpChangeIp bool             `json:"isDynamicIpChangeIp"`
+	LastIp              string           `json:"lastIp"`
+	LastCountry         string           `json:"lastCountry"`
+	BrowserFingerPrint  *Fingerprint     `json:"browserFingerPrint"`
+
+	// Extras holds the response fields not listed above, such as newer
+	// BitBrowser settings, keyed by their JSON name.
+	Extras map[string]json.RawMessage `json:"-"`
 }
 
 // ============================================================================
@@ -384,25 +517,52 @@ type ListResult struct {
 	Total int             `json:"totalNum"`
 }
 
+// Quota describes profile usage against the account's plan limit.
+type Quota struct {
+	Limit     int // Maximum number of profiles (0 if unknown)
+	Used      int // Number of existing profiles
+	Remaining int // Profiles that can still be created (-1 if unknown)
+}
+
+// DeleteOptions configures DeleteProfilesByFilter.
+type DeleteOptions struct {
+	// BatchSize is the number of profiles deleted per request.
+	// Default and maximum is 100.
+	BatchSize int
+
+	// Confirm must be true; it guards against accidental bulk deletes.
+	Confirm bool
+
+	// Progress is called after each batch is deleted.
+	Progress func(DeleteProgress)
+}
+
+// DeleteProgress reports the progress of DeleteProfilesByFilter.
+type DeleteProgress struct {
+	Batch   []string // IDs deleted in this batch
+	Deleted int      // Profiles deleted so far
+	Total   int      // Profiles matched by the filter
+}
+
 // ============================================================================
 // Proxy Update
 // ============================================================================
 
 // ProxyUpdateRequest represents a batch proxy update request.
 type ProxyUpdateRequest struct {
-	IDs               []string `json:"ids"`                         // Profile IDs
-	IpCheckService    string   `json:"ipCheckService,omitempty"`    // "ip123in", "ip-api", "luminati"
-	ProxyMethod       int      `json:"proxyMethod"`                 // 2=custom, 3=extract IP
-	ProxyType         string   `json:"proxyType"`                   // "http", "https", "socks5", "ssh", "noproxy"
-	Host              string   `json:"host"`
-	Port              int      `json:"port"`
-	ProxyUserName     string   `json:"proxyUserName"`
-	ProxyPassword     string   `json:"proxyPassword"`
-	RefreshProxyUrl   string   `json:"refreshProxyUrl,omitempty"`
-	DynamicIpUrl      string   `json:"dynamicIpUrl,omitempty"`
-	DynamicIpChannel  string   `json:"dynamicIpChannel,omitempty"`  // "rola", "ipidea", "doveip", "cloudam", "common"
-	IsDynamicIpChangeIp bool   `json:"isDynamicIpChangeIp,omitempty"`
-	IsIpv6            bool     `json:"isIpv6,omitempty"`
+	IDs                 []string         `json:"ids"`                      // Profile IDs
+	IpCheckService      IpCheckService   `json:"ipCheckService,omitempty"` // "ip123in", "ip-api", "luminati"
+	ProxyMethod         int              `json:"proxyMethod"`              // 2=custom, 3=extract IP
+	ProxyType           ProxyType        `json:"proxyType"`                // "http", "https", "socks5", "ssh", "noproxy"
+	Host                string           `json:"host"`
+	Port                int              `json:"port"`
+	ProxyUserName       string           `json:"proxyUserName"`
+	ProxyPassword       string           `json:"proxyPassword"`
+	RefreshProxyUrl     string           `json:"refreshProxyUrl,omitempty"`
+	DynamicIpUrl        string           `json:"dynamicIpUrl,omitempty"`
+	DynamicIpChannel    DynamicIpChannel `json:"dynamicIpChannel,omitempty"` // "rola", "ipidea", "doveip", "cloudam", "common"
+	IsDynamicIpChangeIp bool             `json:"isDynamicIpChangeIp,omitempty"`
+	IsIpv6              bool             `json:"isIpv6,omitempty"`
 }
 
 // ============================================================================
@@ -411,13 +571,13 @@ type ProxyUpdateRequest struct {
 
 // ProxyCheckRequest represents a proxy check request.
 type ProxyCheckRequest struct {
-	Host           string `json:"host"`
-	Port           int    `json:"port"`
-	ProxyType      string `json:"proxyType"`      // "http", "socks5", "ssh"
-	ProxyUserName  string `json:"proxyUserName"`
-	ProxyPassword  string `json:"proxyPassword"`
-	IpCheckService string `json:"ipCheckService"` // "ip123in", "ip-api"
-	CheckExists    int    `json:"checkExists"`    // 1=check if used, 0=no check
+	Host           string         `json:"host"`
+	Port           int            `json:"port"`
+	ProxyType      ProxyType      `json:"proxyType"` // "http", "socks5", "ssh"
+	ProxyUserName  string         `json:"proxyUserName"`
+	ProxyPassword  string         `json:"proxyPassword"`
+	IpCheckService IpCheckService `json:"ipCheckService"` // "ip123in", "ip-api"
+	CheckExists    int            `json:"checkExists"`    // 1=check if used, 0=no check
 }
 
 // ProxyCheckResult contains proxy check results.
@@ -0,0 +1,73 @@
+package bitbrowser
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/ua"
+)
+
+// validateFingerprint runs the checks CreateProfile and UpdateProfile make
+// before sending a fingerprint.
+func validateFingerprint(fp *Fingerprint, now time.Time) error {
+	if err := ValidateTimeZone(fp, now); err != nil {
+		return err
+	}
+	return ValidateUserAgent(fp)
+}
+
+// ValidateUserAgent checks a manual Fingerprint.UserAgent against the
+// fingerprint's CoreProduct, CoreVersion and OS (or OSType), so that a
+// profile does not claim to be a different browser than it runs. Empty
+// fields are not checked, and neither are user agents package ua does not
+// recognize or a fingerprint without a UserAgent, which BitBrowser then
+// generates itself. CreateProfile and UpdateProfile run it.
+func ValidateUserAgent(fp *Fingerprint) error {
+	if fp == nil || fp.UserAgent == "" {
+		return nil
+	}
+	err := ua.Validate(fp.UserAgent, userAgentExpect(fp))
+	if err != nil && !errors.Is(err, ua.ErrUnrecognized) {
+		return &ValidationError{Field: "userAgent", Message: err.Error(), Value: fp.UserAgent}
+	}
+	return nil
+}
+
+// GenerateUserAgent returns a realistic user agent for the fingerprint's
+// CoreProduct, CoreVersion and OS, for setting UserAgent manually.
+//
+// Example:
+//
+//	fp := &bitbrowser.Fingerprint{CoreVersion: "130", OS: "MacIntel"}
+//	fp.UserAgent, err = bitbrowser.GenerateUserAgent(fp)
+func GenerateUserAgent(fp *Fingerprint) (string, error) {
+	if fp == nil {
+		return "", NewValidationError("browserFingerPrint", "fingerprint is required")
+	}
+	want := userAgentExpect(fp)
+	if want.Version == "" {
+		want.Version = DefaultCoreVersion
+	}
+	s, err := ua.Generate(ua.Options{Browser: want.Browser, Version: want.Version, OS: want.OS})
+	if err != nil {
+		return "", &ValidationError{Field: "browserFingerPrint", Message: err.Error()}
+	}
+	return s, nil
+}
+
+// userAgentExpect returns what a fingerprint's user agent must match.
+func userAgentExpect(fp *Fingerprint) ua.Expect {
+	want := ua.Expect{Browser: ua.Chrome, Version: fp.CoreVersion, OS: ua.PlatformOS(fp.OS)}
+	if fp.CoreProduct != "" {
+		want.Browser = ua.Browser(fp.CoreProduct)
+	}
+	if want.OS == "" {
+		switch fp.OSType {
+		case OSTypeAndroid:
+			want.OS = ua.Android
+		case OSTypeIOS:
+			want.OS = ua.IOS
+		}
+	}
+	return want
+}
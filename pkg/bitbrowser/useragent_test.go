@@ -0,0 +1,64 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateUserAgent(t *testing.T) {
+	const chrome130Mac = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.0.0 Safari/537.36"
+
+	if err := ValidateUserAgent(&Fingerprint{CoreVersion: "130", OS: "MacIntel", UserAgent: chrome130Mac}); err != nil {
+		t.Errorf("matching user agent: %v", err)
+	}
+	if err := ValidateUserAgent(&Fingerprint{CoreVersion: "130", OS: "Win32"}); err != nil {
+		t.Errorf("generated user agent: %v", err)
+	}
+	if err := ValidateUserAgent(&Fingerprint{CoreVersion: "130", UserAgent: "MyCrawler/1.0"}); err != nil {
+		t.Errorf("unrecognized user agent: %v", err)
+	}
+
+	err := ValidateUserAgent(&Fingerprint{CoreProduct: "firefox", CoreVersion: "128", OS: "Win32", UserAgent: chrome130Mac})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "userAgent" {
+		t.Fatalf("ValidateUserAgent() = %v, want a userAgent ValidationError", err)
+	}
+	for _, want := range []string{"want firefox", "want 128", "want Windows"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	err = ValidateUserAgent(&Fingerprint{OSType: "Android", UserAgent: chrome130Mac})
+	if err == nil || !strings.Contains(err.Error(), "want Android") {
+		t.Errorf("OSType mismatch: %v", err)
+	}
+}
+
+func TestGenerateUserAgent(t *testing.T) {
+	s, err := GenerateUserAgent(&Fingerprint{OSType: "Android", OS: "Linux armv81"})
+	if err != nil {
+		t.Fatalf("GenerateUserAgent failed: %v", err)
+	}
+	want := "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/" + DefaultCoreVersion + ".0.0.0 Mobile Safari/537.36"
+	if s != want {
+		t.Errorf("GenerateUserAgent() = %q, want %q", s, want)
+	}
+	if _, err := GenerateUserAgent(&Fingerprint{CoreProduct: "firefox", OS: "iPhone"}); err == nil {
+		t.Error("expected an error for Firefox on iOS")
+	}
+}
+
+func TestUpdateProfileValidatesUserAgent(t *testing.T) {
+	client := mustNew(t, "http://127.0.0.1:1")
+	err := client.UpdateProfile(context.Background(), ProfileConfig{
+		ID:                 "profile-1",
+		BrowserFingerPrint: &Fingerprint{CoreVersion: "131", UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:131.0) Gecko/20100101 Firefox/131.0"},
+	})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "userAgent" {
+		t.Errorf("UpdateProfile() = %v, want a userAgent ValidationError", err)
+	}
+}
@@ -0,0 +1,90 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIVersion returns the version of the BitBrowser application, as reported
+// in the data of its /health response. Older BitBrowser versions do not
+// report one; for those the error matches ErrNotSupportedByVersion.
+func (c *Client) APIVersion(ctx context.Context) (string, error) {
+	data, err := c.post(ctx, "/health", struct{}{})
+	if err != nil {
+		return "", err
+	}
+	if version := parseVersion(data); version != "" {
+		return version, nil
+	}
+	return "", fmt.Errorf("bitbrowser: api version failed: health response has no version: %w", ErrNotSupportedByVersion)
+}
+
+// parseVersion extracts a version from health data, which is either the
+// version string itself or an object with a "version" field.
+func parseVersion(data json.RawMessage) string {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		return strings.TrimSpace(s)
+	}
+	var v struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(data, &v) == nil {
+		return strings.TrimSpace(v.Version)
+	}
+	return ""
+}
+
+// Supports reports whether the installed BitBrowser has the endpoint at path,
+// e.g. "/browser/cookies/clear".
+//
+// Endpoints that answered 404 before are reported unsupported without a
+// request. Read-only endpoints are probed with an empty request: only a 404
+// makes them unsupported, any other answer means the endpoint exists.
+// Mutating endpoints are never probed; they are reported supported until a
+// call to them returns 404. Paths the SDK does not know return a
+// *ValidationError.
+func (c *Client) Supports(ctx context.Context, path string) (bool, error) {
+	ep, ok := endpoints[path]
+	if !ok {
+		return false, &ValidationError{Field: "path", Message: "unknown endpoint", Value: path}
+	}
+	if c.checkSupported(path) != nil {
+		return false, nil
+	}
+	if ep.mutates {
+		return true, nil
+	}
+
+	var resp Response
+	err := c.doRequest(ctx, path, struct{}{}, &resp)
+	c.recordUnsupported(path, err)
+	if errors.Is(err, ErrNotSupportedByVersion) {
+		return false, nil
+	}
+	var apiErr *APIError
+	if err != nil && !errors.As(err, &apiErr) {
+		return false, err // Could not reach BitBrowser
+	}
+	return true, nil
+}
+
+// checkSupported fails with a 404 *APIError if path answered 404 before, so
+// that SDK features the installed version lacks fail fast and consistently.
+func (c *Client) checkSupported(path string) error {
+	if _, ok := c.unsupported.Load(path); ok {
+		return &APIError{Endpoint: path, StatusCode: http.StatusNotFound, Message: ErrNotSupportedByVersion.Error()}
+	}
+	return nil
+}
+
+// recordUnsupported remembers path as unsupported if err is a 404.
+func (c *Client) recordUnsupported(path string, err error) {
+	if errors.Is(err, ErrNotSupportedByVersion) {
+		c.unsupported.Store(path, true)
+	}
+}
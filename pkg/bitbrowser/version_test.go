@@ -0,0 +1,108 @@
+package bitbrowser
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAPIVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		data any
+		want string
+	}{
+		{"string", "7.2.1", "7.2.1"},
+		{"object", map[string]string{"version": "8.0.0"}, "8.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(successResponse(tt.data))
+			})
+			defer server.Close()
+
+			got, err := mustNew(t, server.URL).APIVersion(context.Background())
+			if err != nil || got != tt.want {
+				t.Errorf("APIVersion() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	t.Run("not reported", func(t *testing.T) {
+		server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(successResponse(nil))
+		})
+		defer server.Close()
+
+		_, err := mustNew(t, server.URL).APIVersion(context.Background())
+		if !errors.Is(err, ErrNotSupportedByVersion) {
+			t.Errorf("err = %v, want ErrNotSupportedByVersion", err)
+		}
+	})
+}
+
+func TestNotSupportedByVersion(t *testing.T) {
+	var requests atomic.Int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/browser/cookies/clear" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(successResponse(nil))
+	})
+	defer server.Close()
+
+	client := mustNew(t, server.URL)
+	ctx := context.Background()
+	for range 2 {
+		err := client.ClearCookies(ctx, "profile-1", false)
+		if !errors.Is(err, ErrNotSupportedByVersion) {
+			t.Fatalf("err = %v, want ErrNotSupportedByVersion", err)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1 (second call fails fast)", n)
+	}
+	if err := client.Close(ctx, "profile-1"); err != nil {
+		t.Errorf("other endpoints are unaffected: %v", err)
+	}
+}
+
+func TestSupports(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/browser/pids/alive":
+			http.NotFound(w, r)
+		case "/browser/ports":
+			w.Write(errorResponse("bad request"))
+		default:
+			t.Errorf("unexpected probe of %s", r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	client := mustNew(t, server.URL)
+	ctx := context.Background()
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/browser/pids/alive", false},
+		{"/browser/ports", true},
+		{"/browser/close/all", true}, // Mutating: not probed
+	}
+	for _, tt := range tests {
+		got, err := client.Supports(ctx, tt.path)
+		if err != nil || got != tt.want {
+			t.Errorf("Supports(%q) = %v, %v; want %v", tt.path, got, err, tt.want)
+		}
+	}
+
+	if _, err := client.Supports(ctx, "/nope"); !errors.Is(err, ErrValidation) {
+		t.Errorf("unknown path err = %v, want ErrValidation", err)
+	}
+}
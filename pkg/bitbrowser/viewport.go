@@ -0,0 +1,103 @@
+package bitbrowser
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+// DefaultViewportJitter is the default of ViewportRange.Jitter.
+const DefaultViewportJitter = 80
+
+// Viewport is a window or viewport size in CSS pixels.
+type Viewport struct {
+	Width  int
+	Height int
+}
+
+// String returns the size as "WIDTHxHEIGHT", e.g. "1536x864".
+func (v Viewport) String() string {
+	return fmt.Sprintf("%dx%d", v.Width, v.Height)
+}
+
+// CommonViewports are common desktop screen sizes, the default sizes of
+// ViewportRange.
+var CommonViewports = []Viewport{
+	{1920, 1080}, {1536, 864}, {1366, 768}, {1440, 900}, {1280, 720},
+	{1600, 900}, {1280, 800}, {1680, 1050}, {2560, 1440},
+}
+
+// ViewportRange selects the viewport of OpenOptions.RandomizeViewport. The
+// zero value picks one of CommonViewports and shrinks it by up to
+// DefaultViewportJitter pixels per side.
+type ViewportRange struct {
+	// Sizes are the screen sizes to pick from (default: CommonViewports).
+	Sizes []Viewport
+
+	// Min and Max bound the sizes picked; zero fields are not bounded.
+	Min Viewport
+	Max Viewport
+
+	// Jitter is the most pixels taken off the picked width and height, as
+	// window frames, task bars and docked panels do on real desktops
+	// (default: DefaultViewportJitter; negative disables it). The result
+	// does not shrink below Min.
+	Jitter int
+
+	// Emulate applies the viewport through DevTools with
+	// Emulation.setDeviceMetricsOverride, for as long as the browser runs,
+	// instead of launching the browser with --window-size. Use it when the
+	// window size must not depend on the profile's window settings.
+	Emulate bool
+}
+
+// Pick returns a random viewport within r.
+func (r ViewportRange) Pick() (Viewport, error) {
+	sizes := r.Sizes
+	if len(sizes) == 0 {
+		sizes = CommonViewports
+	}
+	var fits []Viewport
+	for _, s := range sizes {
+		if s.Width >= r.Min.Width && s.Height >= r.Min.Height &&
+			(r.Max.Width == 0 || s.Width <= r.Max.Width) && (r.Max.Height == 0 || s.Height <= r.Max.Height) {
+			fits = append(fits, s)
+		}
+	}
+	if len(fits) == 0 {
+		return Viewport{}, &ValidationError{Field: "RandomizeViewport", Message: fmt.Sprintf("no size between %s and %s", r.Min, r.Max), Value: sizes}
+	}
+
+	v := fits[rand.IntN(len(fits))]
+	jitter := r.Jitter
+	if jitter == 0 {
+		jitter = DefaultViewportJitter
+	}
+	if jitter > 0 {
+		v.Width = max(v.Width-rand.IntN(jitter+1), r.Min.Width)
+		v.Height = max(v.Height-rand.IntN(jitter+1), r.Min.Height)
+	}
+	return v, nil
+}
+
+// randomizeViewport picks the viewport of opts.RandomizeViewport and returns
+// a copy of opts that applies it.
+func randomizeViewport(opts *OpenOptions) (*OpenOptions, Viewport, error) {
+	v, err := opts.RandomizeViewport.Pick()
+	if err != nil {
+		return nil, Viewport{}, err
+	}
+	o := *opts
+	if opts.RandomizeViewport.Emulate {
+		o.OnReady = append(append([]ReadyHook(nil), opts.OnReady...), func(ctx context.Context, session *cdp.Session) error {
+			return session.SetDeviceMetrics(ctx, &cdp.DeviceMetrics{Width: v.Width, Height: v.Height})
+		})
+	} else {
+		size := FlagWindowSize.With(strconv.Itoa(v.Width), strconv.Itoa(v.Height))
+		o.ExtraArgs = append(append([]string(nil), opts.ExtraArgs...), size)
+	}
+	return &o, v, nil
+}
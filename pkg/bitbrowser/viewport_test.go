@@ -0,0 +1,75 @@
+package bitbrowser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestViewportRangePick(t *testing.T) {
+	r := ViewportRange{Min: Viewport{1300, 700}, Max: Viewport{1600, 0}, Jitter: 100}
+	seen := make(map[Viewport]bool)
+	for range 200 {
+		v, err := r.Pick()
+		if err != nil {
+			t.Fatalf("Pick() error = %v", err)
+		}
+		if v.Width < 1300 || v.Width > 1600 || v.Height < 700 || v.Height > 900 {
+			t.Fatalf("Pick() = %s, outside the range", v)
+		}
+		seen[v] = true
+	}
+	if len(seen) < 20 {
+		t.Errorf("Pick() returned only %d sizes", len(seen))
+	}
+
+	exact := ViewportRange{Sizes: []Viewport{{1440, 900}}, Jitter: -1}
+	if v, _ := exact.Pick(); v != (Viewport{1440, 900}) {
+		t.Errorf("Pick() without jitter = %s", v)
+	}
+	if _, err := (ViewportRange{Min: Viewport{4000, 0}}).Pick(); !errors.Is(err, ErrValidation) {
+		t.Errorf("Pick() of an empty range: err = %v, want ErrValidation", err)
+	}
+}
+
+func TestOpenRandomizeViewport(t *testing.T) {
+	devtools := newFakeDevTools(t)
+	var args []string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var config OpenConfig
+		json.NewDecoder(r.Body).Decode(&config)
+		args = config.Args
+		w.Write(successResponse(OpenResult{Ws: devtools.wsURL()}))
+	})
+	defer server.Close()
+	client := mustNew(t, server.URL)
+	ctx := context.Background()
+
+	only := []Viewport{{1366, 768}}
+	opts := &OpenOptions{ExtraArgs: []string{"--lang=de"}, RandomizeViewport: &ViewportRange{Sizes: only, Jitter: -1}}
+	result, err := client.Open(ctx, "p1", opts)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if result.Viewport != (Viewport{1366, 768}) || !slices.Contains(args, "--window-size=1366,768") {
+		t.Errorf("viewport = %s, args = %v", result.Viewport, args)
+	}
+	if len(opts.ExtraArgs) != 1 {
+		t.Errorf("Open changed the caller's ExtraArgs: %v", opts.ExtraArgs)
+	}
+
+	result, err = client.Open(ctx, "p1", &OpenOptions{RandomizeViewport: &ViewportRange{Sizes: only, Emulate: true}})
+	if err != nil {
+		t.Fatalf("Open(Emulate) error = %v", err)
+	}
+	if slices.ContainsFunc(args, func(a string) bool { return strings.HasPrefix(a, "--window-size") }) {
+		t.Errorf("Emulate launched with %v", args)
+	}
+	if result.Viewport.Width < 1366-DefaultViewportJitter || !slices.Contains(devtools.calls(), "Emulation.setDeviceMetricsOverride") {
+		t.Errorf("viewport = %s, DevTools calls = %v", result.Viewport, devtools.calls())
+	}
+}
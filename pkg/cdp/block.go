@@ -0,0 +1,149 @@
+package cdp
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// ResourceType is a Network.ResourceType that BlockResources can block.
+type ResourceType string
+
+// Resource types.
+const (
+	ResourceImage      ResourceType = "Image"
+	ResourceFont       ResourceType = "Font"
+	ResourceMedia      ResourceType = "Media" // Audio and video
+	ResourceStylesheet ResourceType = "Stylesheet"
+	ResourceScript     ResourceType = "Script"
+	ResourceXHR        ResourceType = "XHR"
+	ResourceFetch      ResourceType = "Fetch"
+	ResourceWebSocket  ResourceType = "WebSocket"
+	ResourceOther      ResourceType = "Other"
+)
+
+// BlockRules selects the requests BlockResources fails.
+type BlockRules struct {
+	Types []ResourceType
+
+	// URLPatterns are wildcard patterns (* and ?) matched against the full
+	// URL, e.g. "*://*.doubleclick.net/*" or "*.mp4".
+	URLPatterns []string
+}
+
+// Blocking presets. Blocking images is the same saving as a profile's
+// AbortImage setting, but can be switched on per session.
+var (
+	// BlockImages blocks images.
+	BlockImages = BlockRules{Types: []ResourceType{ResourceImage}}
+
+	// BlockMedia blocks images, fonts, audio and video, which are most of
+	// the bytes of a typical page but rarely needed for scraping.
+	BlockMedia = BlockRules{Types: []ResourceType{ResourceImage, ResourceFont, ResourceMedia}}
+)
+
+// ResourceBlocker fails requests matching BlockRules until stopped. Start
+// one with Session.BlockResources.
+type ResourceBlocker struct {
+	session *Session
+	sub     *Subscription
+	stop    context.CancelFunc
+	done    chan struct{}
+	blocked atomic.Int64
+}
+
+// BlockResources intercepts requests of the session's page that match
+// rules and fails them before they are sent, so they use no bandwidth.
+// Requests that match no rule are not intercepted at all. The blocking
+// lasts until Stop is called or the connection closes.
+//
+// Example:
+//
+//	blocker, err := session.BlockResources(ctx, cdp.BlockRules{
+//	    Types:       []cdp.ResourceType{cdp.ResourceImage, cdp.ResourceFont},
+//	    URLPatterns: []string{"*://*.google-analytics.com/*"},
+//	})
+//	defer blocker.Stop(ctx)
+//
+// Fetch.enable
+func (s *Session) BlockResources(ctx context.Context, rules BlockRules) (*ResourceBlocker, error) {
+	type requestPattern struct {
+		URLPattern   string       `json:"urlPattern,omitempty"`
+		ResourceType ResourceType `json:"resourceType,omitempty"`
+		RequestStage string       `json:"requestStage"`
+	}
+	var patterns []requestPattern
+	for _, t := range rules.Types {
+		patterns = append(patterns, requestPattern{URLPattern: "*", ResourceType: t, RequestStage: "Request"})
+	}
+	for _, p := range rules.URLPatterns {
+		patterns = append(patterns, requestPattern{URLPattern: p, RequestStage: "Request"})
+	}
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("cdp: no resource types or URL patterns to block")
+	}
+
+	// Subscribe first so no request paused right after the call is missed.
+	sub := s.conn.Subscribe("Fetch.requestPaused")
+	params := struct {
+		Patterns []requestPattern `json:"patterns"`
+	}{Patterns: patterns}
+	if err := s.Call(ctx, "Fetch.enable", params, nil); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	loopCtx, stop := context.WithCancel(context.Background())
+	b := &ResourceBlocker{
+		session: s,
+		sub:     sub,
+		stop:    stop,
+		done:    make(chan struct{}),
+	}
+	go b.run(loopCtx)
+	return b, nil
+}
+
+// Blocked returns the number of requests blocked so far.
+func (b *ResourceBlocker) Blocked() int64 {
+	return b.blocked.Load()
+}
+
+// Stop ends the blocking.
+// Fetch.disable
+func (b *ResourceBlocker) Stop(ctx context.Context) error {
+	err := b.session.Call(ctx, "Fetch.disable", nil, nil)
+	b.stop()
+	<-b.done
+	b.sub.Close()
+	return err
+}
+
+// run fails every paused request of the session until ctx is done. Only
+// requests matching the rules are paused.
+// Fetch.failRequest
+func (b *ResourceBlocker) run(ctx context.Context) {
+	defer close(b.done)
+	for {
+		ev, err := b.sub.Next(ctx)
+		if err != nil {
+			return
+		}
+		if ev.SessionID != b.session.id {
+			continue
+		}
+		var p struct {
+			RequestID string `json:"requestId"`
+		}
+		if ev.Unmarshal(&p) != nil {
+			continue
+		}
+		params := struct {
+			RequestID   string `json:"requestId"`
+			ErrorReason string `json:"errorReason"`
+		}{RequestID: p.RequestID, ErrorReason: "BlockedByClient"}
+		if b.session.Call(ctx, "Fetch.failRequest", params, nil) == nil {
+			b.blocked.Add(1)
+		}
+	}
+}
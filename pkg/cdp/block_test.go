@@ -0,0 +1,83 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// emitPaused sends a Fetch.requestPaused event for a session.
+func emitPaused(t *testing.T, b *fakeBrowser, sessionID, requestID string) {
+	t.Helper()
+	params, _ := json.Marshal(map[string]any{"requestId": requestID, "resourceType": "Image"})
+	data, _ := json.Marshal(message{Method: "Fetch.requestPaused", SessionID: sessionID, Params: params})
+	b.mu.Lock()
+	ws := b.ws
+	b.mu.Unlock()
+	if err := ws.writeMessage(data); err != nil {
+		t.Fatalf("emit requestPaused: %v", err)
+	}
+}
+
+func TestBlockResources(t *testing.T) {
+	b := newFakeBrowser(t)
+	handlePage(b)
+	conn := mustDial(t, b)
+	defer conn.Close()
+	ctx := context.Background()
+
+	session, err := conn.AttachToPage(ctx)
+	if err != nil {
+		t.Fatalf("AttachToPage failed: %v", err)
+	}
+	if _, err := session.BlockResources(ctx, BlockRules{}); err == nil {
+		t.Error("expected an error for empty rules")
+	}
+
+	rules := BlockMedia
+	rules.URLPatterns = []string{"*://*.doubleclick.net/*"}
+	blocker, err := session.BlockResources(ctx, rules)
+	if err != nil {
+		t.Fatalf("BlockResources failed: %v", err)
+	}
+
+	enable := b.callsTo("Fetch.enable")
+	if len(enable) != 1 || enable[0].SessionID != "S1" {
+		t.Fatalf("Fetch.enable calls = %+v", enable)
+	}
+	var p struct {
+		Patterns []struct{ URLPattern, ResourceType, RequestStage string }
+	}
+	json.Unmarshal(enable[0].Params, &p)
+	if len(p.Patterns) != 4 || p.Patterns[0].ResourceType != "Image" || p.Patterns[3].URLPattern != "*://*.doubleclick.net/*" ||
+		p.Patterns[3].ResourceType != "" || p.Patterns[3].RequestStage != "Request" {
+		t.Errorf("patterns = %+v", p.Patterns)
+	}
+
+	emitPaused(t, b, "S1", "R1")
+	emitPaused(t, b, "OTHER", "R2")
+	emitPaused(t, b, "S1", "R3")
+	deadline := time.Now().Add(5 * time.Second)
+	for blocker.Blocked() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if blocker.Blocked() != 2 {
+		t.Fatalf("Blocked() = %d, want 2", blocker.Blocked())
+	}
+	var failed []string
+	for _, m := range b.callsTo("Fetch.failRequest") {
+		failed = append(failed, string(m.Params))
+	}
+	if len(failed) != 2 || !strings.Contains(failed[0], `"R1"`) || !strings.Contains(failed[1], `"BlockedByClient"`) {
+		t.Errorf("failRequest calls = %v", failed)
+	}
+
+	if err := blocker.Stop(ctx); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if len(b.callsTo("Fetch.disable")) != 1 {
+		t.Error("Fetch.disable was not called")
+	}
+}
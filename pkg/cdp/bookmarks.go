@@ -0,0 +1,194 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// bookmarksPageURL is the WebUI page that exposes the chrome.bookmarks API.
+const bookmarksPageURL = "chrome://bookmarks/"
+
+// webUIReadyTimeout bounds how long a WebUI page may take to expose its API.
+const webUIReadyTimeout = 10 * time.Second
+
+// Bookmark is a node of the bookmark tree: a link if URL is set, otherwise
+// a folder with Children.
+type Bookmark struct {
+	Title     string     `json:"title"`
+	URL       string     `json:"url,omitempty"`
+	DateAdded float64    `json:"dateAdded,omitempty"` // Milliseconds since the epoch
+	Children  []Bookmark `json:"children,omitempty"`
+}
+
+// Bookmarks is a profile's bookmark tree, by root folder.
+type Bookmarks struct {
+	Bar    []Bookmark `json:"bar,omitempty"`    // Bookmarks bar
+	Other  []Bookmark `json:"other,omitempty"`  // Other bookmarks
+	Mobile []Bookmark `json:"mobile,omitempty"` // Mobile bookmarks
+}
+
+// exportBookmarksJS reads the tree below the three root folders, whose IDs
+// are fixed ("1" bar, "2" other, "3" mobile).
+const exportBookmarksJS = `(async function() {
+	const strip = n => ({title: n.title, url: n.url, dateAdded: n.dateAdded,
+		children: n.children ? n.children.map(strip) : undefined});
+	const [root] = await chrome.bookmarks.getTree();
+	const byID = {};
+	for (const f of root.children) byID[f.id] = (f.children || []).map(strip);
+	return {bar: byID['1'], other: byID['2'], mobile: byID['3']};
+})()`
+
+// importBookmarksJS adds bookmarks below the root folders, skipping links
+// whose URL is already bookmarked and reusing folders with the same title.
+// It returns the number of links created.
+const importBookmarksJS = `(async function(tree) {
+	let created = 0;
+	async function add(parentId, nodes) {
+		const existing = await chrome.bookmarks.getChildren(parentId);
+		for (const n of nodes || []) {
+			if (n.url) {
+				if ((await chrome.bookmarks.search({url: n.url})).length) continue;
+				await chrome.bookmarks.create({parentId, title: n.title, url: n.url});
+				created++;
+				continue;
+			}
+			let folder = existing.find(e => !e.url && e.title === n.title);
+			if (!folder) folder = await chrome.bookmarks.create({parentId, title: n.title});
+			await add(folder.id, n.children);
+		}
+	}
+	await add('1', tree.bar);
+	await add('2', tree.other);
+	await add('3', tree.mobile);
+	return created;
+})(%s)`
+
+// ExportBookmarks returns the browser's bookmarks. It opens chrome://bookmarks
+// in a background tab to reach the bookmarks API and closes it afterwards.
+func (c *Conn) ExportBookmarks(ctx context.Context) (*Bookmarks, error) {
+	s, done, err := c.openWebUI(ctx, bookmarksPageURL, "chrome.bookmarks")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var bookmarks Bookmarks
+	if err := s.Evaluate(ctx, exportBookmarksJS, &bookmarks); err != nil {
+		return nil, fmt.Errorf("cdp: export bookmarks failed: %w", err)
+	}
+	return &bookmarks, nil
+}
+
+// ImportBookmarks adds bookmarks to the browser, e.g. ones exported from
+// another profile, and returns the number of links created. Links whose URL
+// is already bookmarked are skipped and folders are merged by title, so
+// importing the same tree twice adds nothing.
+func (c *Conn) ImportBookmarks(ctx context.Context, bookmarks *Bookmarks) (int, error) {
+	tree, err := json.Marshal(bookmarks)
+	if err != nil {
+		return 0, err
+	}
+	s, done, err := c.openWebUI(ctx, bookmarksPageURL, "chrome.bookmarks")
+	if err != nil {
+		return 0, err
+	}
+	defer done()
+
+	var created int
+	if err := s.Evaluate(ctx, fmt.Sprintf(importBookmarksJS, tree), &created); err != nil {
+		return created, fmt.Errorf("cdp: import bookmarks failed: %w", err)
+	}
+	return created, nil
+}
+
+// VisitURLs loads each URL in a background tab, which records it in the
+// browser history, and returns how many URLs were loaded. It is a way to
+// give a new or cloned profile a plausible history; the DevTools protocol
+// has no API to write or read history directly. URLs that fail to load are
+// skipped.
+// Page.navigate
+func (c *Conn) VisitURLs(ctx context.Context, urls []string) (int, error) {
+	s, done, err := c.openTab(ctx, "about:blank")
+	if err != nil {
+		return 0, err
+	}
+	defer done()
+
+	visited := 0
+	for _, u := range urls {
+		var result struct {
+			ErrorText string `json:"errorText"`
+		}
+		params := struct {
+			URL string `json:"url"`
+		}{URL: u}
+		if err := s.Call(ctx, "Page.navigate", params, &result); err != nil {
+			if ctx.Err() != nil {
+				return visited, err
+			}
+			continue
+		}
+		if result.ErrorText == "" {
+			visited++
+		}
+	}
+	return visited, nil
+}
+
+// openTab opens url in a new background tab and attaches to it. done closes
+// the tab.
+func (c *Conn) openTab(ctx context.Context, url string) (*Session, func(), error) {
+	var created struct {
+		TargetID string `json:"targetId"`
+	}
+	params := struct {
+		URL        string `json:"url"`
+		Background bool   `json:"background"`
+	}{URL: url, Background: true}
+	if err := c.Call(ctx, "Target.createTarget", params, &created); err != nil {
+		return nil, nil, err
+	}
+	done := func() {
+		closeParams := struct {
+			TargetID string `json:"targetId"`
+		}{TargetID: created.TargetID}
+		c.Call(context.WithoutCancel(ctx), "Target.closeTarget", closeParams, nil)
+	}
+
+	s, err := c.Attach(ctx, created.TargetID)
+	if err != nil {
+		done()
+		return nil, nil, err
+	}
+	return s, done, nil
+}
+
+// openWebUI opens a WebUI page and waits until the JavaScript object api
+// (e.g. "chrome.bookmarks") is available in it.
+func (c *Conn) openWebUI(ctx context.Context, url, api string) (*Session, func(), error) {
+	s, done, err := c.openTab(ctx, url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deadline := time.Now().Add(webUIReadyTimeout)
+	check := fmt.Sprintf("typeof chrome !== 'undefined' && !!%s", api)
+	for {
+		var ready bool
+		if err := s.Evaluate(ctx, check, &ready); err == nil && ready {
+			return s, done, nil
+		}
+		if time.Now().After(deadline) {
+			done()
+			return nil, nil, fmt.Errorf("cdp: %s is not available on %s", api, url)
+		}
+		select {
+		case <-ctx.Done():
+			done()
+			return nil, nil, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
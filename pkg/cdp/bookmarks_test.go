@@ -0,0 +1,111 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// handleWebUI registers target handlers for a tab "T1" attached as session
+// "S1" and answers Runtime.evaluate with eval.
+func handleWebUI(b *fakeBrowser, eval func(expr string) any) {
+	b.handle("Target.createTarget", func(msg message) (any, *Error) {
+		return map[string]string{"targetId": "T1"}, nil
+	})
+	b.handle("Target.attachToTarget", func(msg message) (any, *Error) {
+		return map[string]string{"sessionId": "S1"}, nil
+	})
+	b.handle("Runtime.evaluate", func(msg message) (any, *Error) {
+		var p struct {
+			Expression string `json:"expression"`
+		}
+		json.Unmarshal(msg.Params, &p)
+		value, _ := json.Marshal(eval(p.Expression))
+		return map[string]any{"result": map[string]any{"type": "object", "value": json.RawMessage(value)}}, nil
+	})
+}
+
+func TestExportBookmarks(t *testing.T) {
+	b := newFakeBrowser(t)
+	checks := 0
+	handleWebUI(b, func(expr string) any {
+		if strings.HasPrefix(expr, "typeof chrome") {
+			checks++
+			return checks > 1 // Not ready on the first check
+		}
+		return Bookmarks{
+			Bar:   []Bookmark{{Title: "News", Children: []Bookmark{{Title: "Example", URL: "https://example.com/"}}}},
+			Other: []Bookmark{{Title: "Docs", URL: "https://go.dev/doc/"}},
+		}
+	})
+	conn := mustDial(t, b)
+
+	bookmarks, err := conn.ExportBookmarks(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bookmarks.Bar) != 1 || bookmarks.Bar[0].Children[0].URL != "https://example.com/" || len(bookmarks.Other) != 1 {
+		t.Errorf("unexpected bookmarks: %+v", bookmarks)
+	}
+
+	create := b.callsTo("Target.createTarget")
+	if len(create) != 1 || !containsJSON(create[0].Params, `"url":"chrome://bookmarks/"`) {
+		t.Errorf("unexpected createTarget calls: %+v", create)
+	}
+	if closed := b.callsTo("Target.closeTarget"); len(closed) != 1 || !containsJSON(closed[0].Params, `"targetId":"T1"`) {
+		t.Error("bookmarks tab was not closed")
+	}
+}
+
+func TestImportBookmarks(t *testing.T) {
+	b := newFakeBrowser(t)
+	var script string
+	handleWebUI(b, func(expr string) any {
+		if strings.HasPrefix(expr, "typeof chrome") {
+			return true
+		}
+		script = expr
+		return 2
+	})
+	conn := mustDial(t, b)
+
+	created, err := conn.ImportBookmarks(context.Background(), &Bookmarks{
+		Bar: []Bookmark{{Title: "Shop", URL: "https://shop.example.com/"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created != 2 {
+		t.Errorf("created = %d, want 2", created)
+	}
+	if !strings.Contains(script, `"url":"https://shop.example.com/"`) {
+		t.Errorf("tree not passed to script: %s", script)
+	}
+}
+
+func TestVisitURLs(t *testing.T) {
+	b := newFakeBrowser(t)
+	handleWebUI(b, func(expr string) any { return nil })
+	b.handle("Page.navigate", func(msg message) (any, *Error) {
+		if containsJSON(msg.Params, "unreachable") {
+			return map[string]string{"errorText": "net::ERR_NAME_NOT_RESOLVED"}, nil
+		}
+		return map[string]string{"frameId": "F1"}, nil
+	})
+	conn := mustDial(t, b)
+
+	visited, err := conn.VisitURLs(context.Background(), []string{"https://a.example/", "https://unreachable.invalid/", "https://b.example/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if visited != 2 {
+		t.Errorf("visited = %d, want 2", visited)
+	}
+	if calls := b.callsTo("Page.navigate"); len(calls) != 3 || calls[0].SessionID != "S1" {
+		t.Errorf("unexpected navigate calls: %+v", calls)
+	}
+	if len(b.callsTo("Target.closeTarget")) != 1 {
+		t.Error("tab was not closed")
+	}
+}
@@ -0,0 +1,75 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// setClipboardJS writes its argument to the clipboard, falling back to a
+// hidden textarea and execCommand("copy") when the async clipboard API is
+// refused, e.g. because the page is not focused.
+const setClipboardJS = `(async function(text) {
+	try {
+		await navigator.clipboard.writeText(text);
+		return true;
+	} catch (e) {}
+	const ta = document.createElement("textarea");
+	ta.value = text;
+	ta.style.position = "fixed";
+	ta.style.opacity = "0";
+	(document.body || document.documentElement).appendChild(ta);
+	ta.select();
+	const ok = document.execCommand("copy");
+	ta.remove();
+	return ok;
+})(%s)`
+
+// SetClipboard sets the clipboard content from the page, so that pasting,
+// e.g. with bitbrowser's AutoPaste, inserts text. It grants the page
+// clipboard access first. The browser's clipboard is the desktop clipboard
+// of the machine it runs on.
+//
+// A session can serve as bitbrowser's ClipboardWriter:
+//
+//	client, _ := bitbrowser.New(apiURL, bitbrowser.WithClipboard(
+//	    bitbrowser.ClipboardWriterFunc(session.SetClipboard)))
+func (s *Session) SetClipboard(ctx context.Context, text string) error {
+	grant := struct {
+		Permissions []string `json:"permissions"`
+	}{Permissions: []string{"clipboardReadWrite", "clipboardSanitizedWrite"}}
+	// Best effort: without the grant, the execCommand fallback still works.
+	_ = s.conn.Call(ctx, "Browser.grantPermissions", grant, nil)
+
+	encoded, err := json.Marshal(text)
+	if err != nil {
+		return fmt.Errorf("cdp: failed to encode clipboard text: %w", err)
+	}
+	params := struct {
+		Expression    string `json:"expression"`
+		ReturnByValue bool   `json:"returnByValue"`
+		AwaitPromise  bool   `json:"awaitPromise"`
+		UserGesture   bool   `json:"userGesture"`
+	}{
+		Expression:    fmt.Sprintf(setClipboardJS, encoded),
+		ReturnByValue: true,
+		AwaitPromise:  true,
+		UserGesture:   true,
+	}
+	var result struct {
+		Result           remoteObject      `json:"result"`
+		ExceptionDetails *exceptionDetails `json:"exceptionDetails,omitempty"`
+	}
+	if err := s.Call(ctx, "Runtime.evaluate", params, &result); err != nil {
+		return err
+	}
+	if result.ExceptionDetails != nil {
+		return fmt.Errorf("cdp: JavaScript exception: %s", result.ExceptionDetails.message())
+	}
+	var ok bool
+	if json.Unmarshal(result.Result.Value, &ok) != nil || !ok {
+		return errors.New("cdp: the page refused to write the clipboard")
+	}
+	return nil
+}
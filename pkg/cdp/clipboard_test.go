@@ -0,0 +1,57 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSetClipboard(t *testing.T) {
+	setup := func(t *testing.T, written bool) (*fakeBrowser, *Session) {
+		b := newFakeBrowser(t)
+		handlePage(b)
+		b.handle("Browser.grantPermissions", func(msg message) (any, *Error) {
+			return nil, &Error{Code: -32000, Message: "not supported"} // Ignored
+		})
+		b.handle("Runtime.evaluate", func(msg message) (any, *Error) {
+			return map[string]any{"result": map[string]any{"type": "boolean", "value": written}}, nil
+		})
+		s, err := mustDial(t, b).AttachToPage(context.Background())
+		if err != nil {
+			t.Fatalf("AttachToPage failed: %v", err)
+		}
+		return b, s
+	}
+
+	t.Run("writes the text", func(t *testing.T) {
+		b, s := setup(t, true)
+		if err := s.SetClipboard(context.Background(), "jane@example.com\n\"quoted\""); err != nil {
+			t.Fatalf("SetClipboard failed: %v", err)
+		}
+
+		grants := b.callsTo("Browser.grantPermissions")
+		if len(grants) != 1 || grants[0].SessionID != "" || !strings.Contains(string(grants[0].Params), "clipboardSanitizedWrite") {
+			t.Errorf("grantPermissions calls = %+v", grants)
+		}
+		evals := b.callsTo("Runtime.evaluate")
+		if len(evals) != 1 || evals[0].SessionID != "S1" {
+			t.Fatalf("evaluate calls = %+v", evals)
+		}
+		var params struct {
+			Expression  string
+			UserGesture bool
+		}
+		json.Unmarshal(evals[0].Params, &params)
+		if !params.UserGesture || !strings.Contains(params.Expression, `"jane@example.com\n\"quoted\""`) {
+			t.Errorf("evaluate params = %+v", params)
+		}
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		_, s := setup(t, false)
+		if err := s.SetClipboard(context.Background(), "x"); err == nil {
+			t.Error("expected an error when the page refuses the write")
+		}
+	})
+}
@@ -0,0 +1,422 @@
+package cdp
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Conn is a connection to a browser's DevTools WebSocket endpoint.
+//
+// A Conn is safe for concurrent use. Commands are matched to their
+// responses by ID, and events are fanned out to every Subscription
+// whose method filter matches.
+type Conn struct {
+	ws       *wsConn
+	nextID   atomic.Int64
+	recorder *Recorder // Receives every message sent and received (nil means disabled)
+
+	mu      sync.Mutex
+	pending map[int64]chan *message
+	subs    map[*Subscription]struct{}
+	err     error // Set once the read loop exits
+
+	done chan struct{}
+}
+
+// message is the wire format shared by commands, responses and events.
+type message struct {
+	ID        int64           `json:"id,omitempty"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Method    string          `json:"method,omitempty"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     *Error          `json:"error,omitempty"`
+}
+
+// Event is a DevTools protocol event.
+type Event struct {
+	Method    string          // Event name, e.g. "Browser.downloadProgress"
+	SessionID string          // Session the event belongs to ("" for the browser target)
+	Params    json.RawMessage // Raw event parameters
+}
+
+// Unmarshal decodes the event parameters into v.
+func (e Event) Unmarshal(v any) error {
+	if err := json.Unmarshal(e.Params, v); err != nil {
+		return fmt.Errorf("cdp: failed to parse %s event: %w", e.Method, err)
+	}
+	return nil
+}
+
+// dialConfig holds the settings used to establish a connection.
+type dialConfig struct {
+	dialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	tlsConfig   *tls.Config
+	header      http.Header
+	recorder    *Recorder
+}
+
+// dial opens the underlying network connection.
+func (cfg *dialConfig) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	if cfg.dialContext != nil {
+		return cfg.dialContext(ctx, network, addr)
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, network, addr)
+}
+
+// DialOption configures how Dial connects to the browser.
+type DialOption func(*dialConfig)
+
+// WithHeader adds an HTTP header to the WebSocket handshake request.
+func WithHeader(key, value string) DialOption {
+	return func(cfg *dialConfig) {
+		if cfg.header == nil {
+			cfg.header = make(http.Header)
+		}
+		cfg.header.Add(key, value)
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used for wss:// URLs, e.g. when
+// the debug endpoint is fronted by a TLS proxy with a private CA.
+func WithTLSConfig(config *tls.Config) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.tlsConfig = config
+	}
+}
+
+// Dial connects to a DevTools WebSocket URL, typically OpenResult.Ws.
+//
+// The context bounds the connection handshake only. Use Close to release
+// the connection when done.
+//
+// Example:
+//
+//	result, _ := client.Open(ctx, id, nil)
+//	conn, err := cdp.Dial(ctx, result.Ws)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer conn.Close()
+func Dial(ctx context.Context, wsURL string, opts ...DialOption) (*Conn, error) {
+	if wsURL == "" {
+		return nil, fmt.Errorf("cdp: WebSocket URL is required")
+	}
+
+	cfg := &dialConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	ws, err := dialWebSocket(ctx, wsURL, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newConn(ws, cfg.recorder), nil
+}
+
+// newConn starts the read loop for an established WebSocket.
+func newConn(ws *wsConn, recorder *Recorder) *Conn {
+	c := &Conn{
+		ws:       ws,
+		recorder: recorder,
+		pending:  make(map[int64]chan *message),
+		subs:     make(map[*Subscription]struct{}),
+		done:     make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// Call invokes a method on the browser target and decodes the result into
+// result. params and result may be nil.
+func (c *Conn) Call(ctx context.Context, method string, params, result any) error {
+	return c.call(ctx, "", method, params, result)
+}
+
+// Close closes the connection. Pending calls fail with ErrClosed.
+func (c *Conn) Close() error {
+	select {
+	case <-c.done:
+		return nil
+	default:
+	}
+	return c.ws.close()
+}
+
+// Ping sends a WebSocket ping and waits for the browser's pong. It checks
+// that the connection is alive without involving the DevTools protocol.
+func (c *Conn) Ping(ctx context.Context) error {
+	// Drop a stale pong from an earlier, timed out ping.
+	select {
+	case <-c.ws.pong:
+	default:
+	}
+	if err := c.ws.writeFrame(opPing, nil); err != nil {
+		return fmt.Errorf("cdp: send ping: %w", err)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.done:
+		return c.Err()
+	case <-c.ws.pong:
+		return nil
+	}
+}
+
+// Done returns a channel that is closed when the connection is lost.
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the reason the connection was lost, or nil if it is alive.
+func (c *Conn) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// call sends a command, optionally scoped to a session, and waits for its response.
+func (c *Conn) call(ctx context.Context, sessionID, method string, params, result any) error {
+	msg := message{
+		ID:        c.nextID.Add(1),
+		SessionID: sessionID,
+		Method:    method,
+	}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("cdp: failed to marshal %s params: %w", method, err)
+		}
+		msg.Params = raw
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("cdp: failed to marshal %s: %w", method, err)
+	}
+
+	ch := make(chan *message, 1)
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return err
+	}
+	c.pending[msg.ID] = ch
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, msg.ID)
+		c.mu.Unlock()
+	}()
+
+	// Recorded before sending so the response cannot be recorded first.
+	c.recorder.record(DirectionSend, data)
+	if err := c.ws.writeMessage(data); err != nil {
+		return fmt.Errorf("cdp: send %s: %w", method, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.done:
+		select {
+		case resp := <-ch:
+			return decodeResult(method, resp, result)
+		default:
+		}
+		return c.Err()
+	case resp := <-ch:
+		return decodeResult(method, resp, result)
+	}
+}
+
+// decodeResult converts a response message into an error or a decoded result.
+func decodeResult(method string, resp *message, result any) error {
+	if resp.Error != nil {
+		resp.Error.Method = method
+		return resp.Error
+	}
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("cdp: failed to parse %s result: %w", method, err)
+	}
+	return nil
+}
+
+// readLoop dispatches incoming messages until the connection fails.
+func (c *Conn) readLoop() {
+	var err error
+	for {
+		var data []byte
+		data, err = c.ws.readMessage()
+		if err != nil {
+			break
+		}
+		c.recorder.record(DirectionReceive, data)
+
+		var msg message
+		if json.Unmarshal(data, &msg) != nil {
+			continue // Ignore malformed messages rather than killing the connection
+		}
+
+		if msg.ID != 0 {
+			c.mu.Lock()
+			ch, ok := c.pending[msg.ID]
+			c.mu.Unlock()
+			if ok {
+				ch <- &msg
+			}
+			continue
+		}
+
+		if msg.Method != "" {
+			c.dispatch(Event{Method: msg.Method, SessionID: msg.SessionID, Params: msg.Params})
+		}
+	}
+
+	c.mu.Lock()
+	c.err = &ClosedError{Err: err}
+	subs := make([]*Subscription, 0, len(c.subs))
+	for s := range c.subs {
+		subs = append(subs, s)
+	}
+	c.mu.Unlock()
+
+	c.ws.conn.Close()
+	close(c.done)
+	for _, s := range subs {
+		s.notify()
+	}
+}
+
+// dispatch delivers an event to all matching subscriptions.
+func (c *Conn) dispatch(ev Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for s := range c.subs {
+		if s.matches(ev) {
+			s.push(ev)
+		}
+	}
+}
+
+// ============================================================================
+// Subscriptions
+// ============================================================================
+
+// Subscription receives events from a Conn.
+// Events are queued without limit, so a slow consumer never causes events
+// to be dropped. Always Close a subscription when it is no longer needed.
+type Subscription struct {
+	conn    *Conn
+	methods map[string]bool // nil means all events
+
+	mu     sync.Mutex
+	queue  []Event
+	closed bool
+	wake   chan struct{}
+}
+
+// Subscribe returns a Subscription for the given event methods.
+// With no methods, every event is delivered.
+//
+// Remember that most domains only emit events after being enabled
+// (e.g. "Page.enable").
+func (c *Conn) Subscribe(methods ...string) *Subscription {
+	s := &Subscription{
+		conn: c,
+		wake: make(chan struct{}, 1),
+	}
+	if len(methods) > 0 {
+		s.methods = make(map[string]bool, len(methods))
+		for _, m := range methods {
+			s.methods[m] = true
+		}
+	}
+
+	c.mu.Lock()
+	c.subs[s] = struct{}{}
+	c.mu.Unlock()
+	return s
+}
+
+// Next blocks until the next event arrives, the context is done, the
+// subscription is closed or the connection is lost.
+func (s *Subscription) Next(ctx context.Context) (Event, error) {
+	for {
+		s.mu.Lock()
+		if len(s.queue) > 0 {
+			ev := s.queue[0]
+			s.queue[0] = Event{}
+			s.queue = s.queue[1:]
+			s.mu.Unlock()
+			return ev, nil
+		}
+		closed := s.closed
+		s.mu.Unlock()
+
+		if closed {
+			return Event{}, ErrClosed
+		}
+		select {
+		case <-s.conn.done:
+			return Event{}, s.conn.Err()
+		default:
+		}
+
+		select {
+		case <-ctx.Done():
+			return Event{}, ctx.Err()
+		case <-s.wake:
+		case <-s.conn.done:
+		}
+	}
+}
+
+// Close stops event delivery. Pending Next calls return ErrClosed.
+func (s *Subscription) Close() {
+	s.conn.mu.Lock()
+	delete(s.conn.subs, s)
+	s.conn.mu.Unlock()
+
+	s.mu.Lock()
+	s.closed = true
+	s.queue = nil
+	s.mu.Unlock()
+	s.notify()
+}
+
+// matches reports whether the subscription wants the event.
+func (s *Subscription) matches(ev Event) bool {
+	return s.methods == nil || s.methods[ev.Method]
+}
+
+// push appends an event to the queue and wakes a waiting reader.
+func (s *Subscription) push(ev Event) {
+	s.mu.Lock()
+	if !s.closed {
+		s.queue = append(s.queue, ev)
+	}
+	s.mu.Unlock()
+	s.notify()
+}
+
+// notify wakes a waiting reader without blocking.
+func (s *Subscription) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
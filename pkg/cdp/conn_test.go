@@ -0,0 +1,373 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBrowser is a DevTools WebSocket server for tests.
+// Commands are answered by registered handlers; unknown methods return {}.
+type fakeBrowser struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]func(msg message) (any, *Error)
+	calls    []message
+	ws       *wsConn
+	ready    chan struct{}
+	once     sync.Once // Closes ready on the first connection
+}
+
+// newFakeBrowser starts a fake browser and registers cleanup with t.
+func newFakeBrowser(t *testing.T) *fakeBrowser {
+	t.Helper()
+	b := &fakeBrowser{
+		t:        t,
+		handlers: make(map[string]func(msg message) (any, *Error)),
+		ready:    make(chan struct{}),
+	}
+	b.server = httptest.NewServer(http.HandlerFunc(b.serve))
+	t.Cleanup(b.server.Close)
+	return b
+}
+
+// wsURL returns the browser-level DevTools URL of the fake browser.
+func (b *fakeBrowser) wsURL() string {
+	return "ws" + strings.TrimPrefix(b.server.URL, "http") + "/devtools/browser/fake"
+}
+
+// handle registers a handler for a method.
+func (b *fakeBrowser) handle(method string, fn func(msg message) (any, *Error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[method] = fn
+}
+
+// emit sends an event to the connected client.
+func (b *fakeBrowser) emit(method string, params any) {
+	b.t.Helper()
+	<-b.ready
+	raw, _ := json.Marshal(params)
+	data, _ := json.Marshal(message{Method: method, Params: raw})
+
+	b.mu.Lock()
+	ws := b.ws
+	b.mu.Unlock()
+	if err := ws.writeMessage(data); err != nil {
+		b.t.Errorf("emit %s: %v", method, err)
+	}
+}
+
+// callsTo returns the recorded calls to a method.
+func (b *fakeBrowser) callsTo(method string) []message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []message
+	for _, m := range b.calls {
+		if m.Method == method {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func (b *fakeBrowser) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Upgrade") != "websocket" {
+		http.Error(w, "expected websocket", http.StatusBadRequest)
+		return
+	}
+	conn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		b.t.Errorf("hijack: %v", err)
+		return
+	}
+	brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+	brw.Flush()
+
+	ws := newWSConn(conn, brw.Reader, false)
+	b.mu.Lock()
+	b.ws = ws
+	b.mu.Unlock()
+	b.once.Do(func() { close(b.ready) })
+
+	for {
+		data, err := ws.readMessage()
+		if err != nil {
+			conn.Close()
+			return
+		}
+		var msg message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			b.t.Errorf("server received malformed message: %v", err)
+			continue
+		}
+
+		b.mu.Lock()
+		b.calls = append(b.calls, msg)
+		fn := b.handlers[msg.Method]
+		b.mu.Unlock()
+
+		resp := message{ID: msg.ID, SessionID: msg.SessionID, Result: json.RawMessage(`{}`)}
+		if fn != nil {
+			result, perr := fn(msg)
+			if perr != nil {
+				resp.Error = perr
+				resp.Result = nil
+			} else if result != nil {
+				resp.Result, _ = json.Marshal(result)
+			}
+		}
+		out, _ := json.Marshal(resp)
+		ws.writeMessage(out)
+	}
+}
+
+// mustDial is a test helper that connects to a fake browser.
+func mustDial(t *testing.T, b *fakeBrowser) *Conn {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := Dial(ctx, b.wsURL())
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestDial(t *testing.T) {
+	t.Run("empty URL", func(t *testing.T) {
+		_, err := Dial(context.Background(), "")
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		_, err := Dial(context.Background(), "ftp://127.0.0.1:9222")
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("non-websocket endpoint", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		_, err := Dial(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"))
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "404") {
+			t.Errorf("error should mention status, got: %v", err)
+		}
+	})
+
+	t.Run("sends custom headers", func(t *testing.T) {
+		var got string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Get("X-Test")
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		Dial(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"), WithHeader("X-Test", "value"))
+		if got != "value" {
+			t.Errorf("X-Test = %q, want %q", got, "value")
+		}
+	})
+}
+
+func TestCall(t *testing.T) {
+	t.Run("decodes result", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		b.handle("Browser.getVersion", func(msg message) (any, *Error) {
+			return map[string]string{"product": "Chrome/130.0.0.0"}, nil
+		})
+		conn := mustDial(t, b)
+
+		var version struct {
+			Product string `json:"product"`
+		}
+		if err := conn.Call(context.Background(), "Browser.getVersion", nil, &version); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if version.Product != "Chrome/130.0.0.0" {
+			t.Errorf("Product = %q", version.Product)
+		}
+	})
+
+	t.Run("sends params", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		conn := mustDial(t, b)
+
+		params := map[string]string{"url": "https://example.com"}
+		if err := conn.Call(context.Background(), "Target.createTarget", params, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		calls := b.callsTo("Target.createTarget")
+		if len(calls) != 1 {
+			t.Fatalf("calls = %d, want 1", len(calls))
+		}
+		if !strings.Contains(string(calls[0].Params), "https://example.com") {
+			t.Errorf("params = %s", calls[0].Params)
+		}
+	})
+
+	t.Run("protocol error", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		b.handle("DOM.getDocument", func(msg message) (any, *Error) {
+			return nil, &Error{Code: -32000, Message: "Not allowed"}
+		})
+		conn := mustDial(t, b)
+
+		err := conn.Call(context.Background(), "DOM.getDocument", nil, nil)
+		var perr *Error
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *Error, got %T: %v", err, err)
+		}
+		if perr.Code != -32000 || perr.Method != "DOM.getDocument" {
+			t.Errorf("unexpected error fields: %+v", perr)
+		}
+	})
+
+	t.Run("concurrent calls", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		b.handle("Echo", func(msg message) (any, *Error) {
+			return msg.Params, nil
+		})
+		conn := mustDial(t, b)
+
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var out struct{ N int }
+				if err := conn.Call(context.Background(), "Echo", struct{ N int }{i}, &out); err != nil {
+					t.Errorf("call %d: %v", i, err)
+					return
+				}
+				if out.N != i {
+					t.Errorf("call %d got response for %d", i, out.N)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		block := make(chan struct{})
+		defer close(block)
+		b.handle("Slow", func(msg message) (any, *Error) {
+			<-block
+			return nil, nil
+		})
+		conn := mustDial(t, b)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := conn.Call(ctx, "Slow", nil, nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("fails after close", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		conn := mustDial(t, b)
+		conn.Close()
+
+		select {
+		case <-conn.Done():
+		case <-time.After(2 * time.Second):
+			t.Fatal("connection did not report closure")
+		}
+		err := conn.Call(context.Background(), "Browser.getVersion", nil, nil)
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("expected ErrClosed, got %v", err)
+		}
+	})
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Run("filters by method", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		conn := mustDial(t, b)
+
+		sub := conn.Subscribe("Target.targetCreated")
+		defer sub.Close()
+
+		b.emit("Page.loadEventFired", map[string]any{})
+		b.emit("Target.targetCreated", map[string]any{"targetInfo": map[string]string{"targetId": "T1"}})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		ev, err := sub.Next(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ev.Method != "Target.targetCreated" {
+			t.Errorf("Method = %q", ev.Method)
+		}
+
+		var p struct {
+			TargetInfo struct {
+				TargetID string `json:"targetId"`
+			} `json:"targetInfo"`
+		}
+		if err := ev.Unmarshal(&p); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		if p.TargetInfo.TargetID != "T1" {
+			t.Errorf("targetId = %q", p.TargetInfo.TargetID)
+		}
+	})
+
+	t.Run("does not drop queued events", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		conn := mustDial(t, b)
+
+		sub := conn.Subscribe()
+		defer sub.Close()
+
+		for range 50 {
+			b.emit("Test.event", map[string]any{})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		for i := range 50 {
+			if _, err := sub.Next(ctx); err != nil {
+				t.Fatalf("event %d: %v", i, err)
+			}
+		}
+	})
+
+	t.Run("closed subscription", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		conn := mustDial(t, b)
+
+		sub := conn.Subscribe()
+		sub.Close()
+
+		_, err := sub.Next(context.Background())
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("expected ErrClosed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+// Package cdp provides lightweight Chrome DevTools Protocol helpers for
+// browsers opened through the SDK.
+//
+// The package talks to the browser directly over the WebSocket URL returned
+// in OpenResult.Ws, without requiring a full automation framework such as
+// chromedp or rod. It has no dependencies outside the standard library.
+//
+// # Usage
+//
+//	result, err := client.Open(ctx, id, nil)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	conn, err := cdp.Dial(ctx, result.Ws)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer conn.Close()
+//
+//	var version struct {
+//	    Product string `json:"product"`
+//	}
+//	err = conn.Call(ctx, "Browser.getVersion", nil, &version)
+//
+// # Downloads
+//
+// WatchDownloads configures the download directory and reports completed
+// files:
+//
+//	w, err := conn.WatchDownloads(ctx, "/tmp/downloads")
+//	defer w.Close()
+//	d, err := w.Wait(ctx)
+//	fmt.Println(d.Path)
+//
+// # File Uploads
+//
+// SetFileInput fills an <input type="file"> on the current page. Selectors
+// can cross shadow roots with ">>>":
+//
+//	err := cdp.SetFileInput(ctx, result.Ws, "my-uploader >>> input[type=file]",
+//	    []string{"/data/a.png", "/data/b.png"})
+//
+// # Bookmarks and History
+//
+// ExportBookmarks and ImportBookmarks copy bookmarks between profiles, and
+// VisitURLs seeds the history by loading pages, e.g. to make a cloned
+// profile look lived-in:
+//
+//	bookmarks, err := src.ExportBookmarks(ctx)
+//	created, err := dst.ImportBookmarks(ctx, bookmarks)
+//	visited, err := dst.VisitURLs(ctx, []string{"https://example.com/"})
+//
+// # Keepalive and Reconnect
+//
+// DialManaged returns a ManagedConnection that pings the browser, detects
+// drops and reconnects to the URL returned by a Resolver, such as
+// bitbrowser's Client.DebugURL:
+//
+//	mc, err := cdp.DialManaged(ctx, func(ctx context.Context) (string, error) {
+//	    return client.DebugURL(ctx, id)
+//	}, cdp.ManagedConfig{})
+//	defer mc.Close()
+//	err = mc.Call(ctx, "Browser.getVersion", nil, &version)
+//
+// # Screencasts
+//
+// StartScreencast captures the frames a page renders. WriteFrames saves
+// them as images with an ffmpeg concat list, and EncodeVideo turns them into
+// a WebM or MP4 file with ffmpeg:
+//
+//	sc, err := session.StartScreencast(ctx, nil)
+//	frames, err := sc.Stop(ctx)
+//	err = sc.EncodeVideo(ctx, "session.webm")
+//
+// # Session Recording
+//
+// A Recorder writes every message of a connection as JSON lines, for
+// debugging failed automation or reviewing what a bot did. ReadRecording
+// loads a recording and Replay sends its commands again:
+//
+//	f, _ := os.Create("session.jsonl")
+//	conn, err := cdp.Dial(ctx, result.Ws, cdp.WithRecorder(cdp.NewRecorder(f)))
+//	...
+//	records, err := cdp.ReadRecording(f)
+//	err = cdp.Replay(ctx, other, records)
+//
+// # Verification Codes
+//
+// A VerificationProvider fetches one-time codes sent by SMS or email.
+// EnterVerificationCode pauses until the provider has the code and types it
+// into the page:
+//
+//	code, err := session.EnterVerificationCode(ctx, provider, cdp.VerificationRequest{
+//	    Channel: cdp.VerificationEmail, Target: "bot@example.com",
+//	}, "#otp")
+//
+// # Resource Blocking
+//
+// BlockResources fails requests for images, fonts, media or URL patterns
+// before they are sent, to save bandwidth on metered proxies:
+//
+//	blocker, err := session.BlockResources(ctx, cdp.BlockMedia)
+//	defer blocker.Stop(ctx)
+//
+// MeterTraffic counts the bytes a page sends and receives:
+//
+//	meter, err := session.MeterTraffic(ctx)
+//	stats := meter.Stop()
+//
+// # Geo and Locale Emulation
+//
+// Emulate overrides the time zone, locale and geolocation of a page while
+// the session stays attached, e.g. after switching a running profile to a
+// proxy in another country:
+//
+//	err := session.Emulate(ctx, cdp.GeoOverride{Timezone: "Asia/Tokyo", Locale: "ja-JP"})
+//
+// SetPermissions grants or denies permissions such as notifications and the
+// clipboard, for every origin or one, so pages do not stop on prompts:
+//
+//	err := session.SetPermissions(ctx, cdp.PermissionRule{
+//	    Permissions: []cdp.Permission{cdp.PermissionNotifications},
+//	    Setting:     cdp.PermissionDenied,
+//	})
+//
+// # Macros
+//
+// RunMacro runs a sequence of navigate, click, type and wait steps, waiting
+// for pages to load and elements to appear:
+//
+//	err := session.RunMacro(ctx, []cdp.MacroStep{
+//	    {Action: cdp.MacroNavigate, URL: "https://example.com/login"},
+//	    {Action: cdp.MacroClick, Selector: "#accept-cookies"},
+//	})
+//
+// Macros can be stored as JSON or YAML and read with ParseMacro. RunMacroOutput
+// also returns the values of extract and exportCookies steps:
+//
+//	steps, err := cdp.ParseMacro(data)
+//	output, err := session.RunMacroOutput(ctx, steps)
+//	fmt.Println(output["title"])
+package cdp
@@ -0,0 +1,233 @@
+package cdp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Download behaviors accepted by Browser.setDownloadBehavior.
+const (
+	// DownloadBehaviorDeny rejects all downloads.
+	DownloadBehaviorDeny = "deny"
+	// DownloadBehaviorAllow saves downloads under their suggested file name.
+	DownloadBehaviorAllow = "allow"
+	// DownloadBehaviorAllowAndName saves downloads under their GUID, giving
+	// a predictable path that does not collide with existing files.
+	DownloadBehaviorAllowAndName = "allowAndName"
+	// DownloadBehaviorDefault restores the browser's default behavior.
+	DownloadBehaviorDefault = "default"
+)
+
+// Download states reported by Browser.downloadProgress.
+const (
+	DownloadInProgress = "inProgress"
+	DownloadCompleted  = "completed"
+	DownloadCanceled   = "canceled"
+)
+
+// SetDownloadBehavior configures where and how the browser saves downloads.
+// dir is a directory on the machine running the browser and is required
+// for the allow behaviors. Progress events are always enabled so that a
+// DownloadWatcher can track the files.
+// Browser.setDownloadBehavior
+func (c *Conn) SetDownloadBehavior(ctx context.Context, behavior, dir string) error {
+	if (behavior == DownloadBehaviorAllow || behavior == DownloadBehaviorAllowAndName) && dir == "" {
+		return fmt.Errorf("cdp: download directory is required for behavior %q", behavior)
+	}
+
+	params := struct {
+		Behavior      string `json:"behavior"`
+		DownloadPath  string `json:"downloadPath,omitempty"`
+		EventsEnabled bool   `json:"eventsEnabled"`
+	}{
+		Behavior:      behavior,
+		DownloadPath:  dir,
+		EventsEnabled: true,
+	}
+	return c.Call(ctx, "Browser.setDownloadBehavior", params, nil)
+}
+
+// Download describes a file downloaded by the browser.
+type Download struct {
+	GUID              string // Unique download identifier assigned by the browser
+	URL               string // Source URL
+	SuggestedFilename string // File name proposed by the server
+	Path              string // Location on the browser host once completed
+	TotalBytes        int64  // Expected size (0 if unknown)
+	ReceivedBytes     int64  // Bytes received so far
+	State             string // DownloadInProgress, DownloadCompleted or DownloadCanceled
+}
+
+// DownloadWatcher tracks downloads on a browser connection.
+//
+// Example:
+//
+//	w, err := conn.WatchDownloads(ctx, `C:\downloads`)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer w.Close()
+//
+//	// ... trigger the download via your automation framework ...
+//
+//	d, err := w.Wait(ctx)
+//	fmt.Println("saved to", d.Path)
+type DownloadWatcher struct {
+	sub      *Subscription
+	dir      string
+	behavior string
+
+	mu        sync.Mutex
+	active    map[string]*Download
+	completed []Download
+}
+
+// WatchDownloads sets the download directory and starts tracking downloads.
+// Files are saved under their GUID (DownloadBehaviorAllowAndName) so their
+// final path is known in advance; Download.SuggestedFilename carries the
+// original name.
+func (c *Conn) WatchDownloads(ctx context.Context, dir string) (*DownloadWatcher, error) {
+	return c.WatchDownloadsWithBehavior(ctx, DownloadBehaviorAllowAndName, dir)
+}
+
+// WatchDownloadsWithBehavior is like WatchDownloads but lets the caller pick
+// the download behavior. With DownloadBehaviorAllow the reported path uses
+// the suggested file name, which the browser may alter to avoid collisions.
+func (c *Conn) WatchDownloadsWithBehavior(ctx context.Context, behavior, dir string) (*DownloadWatcher, error) {
+	// Subscribe first so no event emitted right after the call is missed.
+	sub := c.Subscribe("Browser.downloadWillBegin", "Browser.downloadProgress")
+	if err := c.SetDownloadBehavior(ctx, behavior, dir); err != nil {
+		sub.Close()
+		return nil, err
+	}
+	return &DownloadWatcher{
+		sub:      sub,
+		dir:      dir,
+		behavior: behavior,
+		active:   make(map[string]*Download),
+	}, nil
+}
+
+// Wait blocks until the next download completes and returns it.
+// A canceled download returns ErrDownloadCanceled along with its details.
+func (w *DownloadWatcher) Wait(ctx context.Context) (*Download, error) {
+	for {
+		ev, err := w.sub.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		d, done, err := w.handle(ev)
+		if err != nil {
+			return nil, err
+		}
+		if !done {
+			continue
+		}
+		if d.State == DownloadCanceled {
+			return d, fmt.Errorf("%w: %s", ErrDownloadCanceled, d.URL)
+		}
+		return d, nil
+	}
+}
+
+// Completed returns all downloads that finished successfully so far.
+func (w *DownloadWatcher) Completed() []Download {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Download(nil), w.completed...)
+}
+
+// Paths returns the file paths of all completed downloads.
+func (w *DownloadWatcher) Paths() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	paths := make([]string, len(w.completed))
+	for i, d := range w.completed {
+		paths[i] = d.Path
+	}
+	return paths
+}
+
+// Close stops tracking downloads. The download behavior is left unchanged.
+func (w *DownloadWatcher) Close() {
+	w.sub.Close()
+}
+
+// handle updates the watcher state from a download event.
+// done is true when the download reached a terminal state.
+func (w *DownloadWatcher) handle(ev Event) (d *Download, done bool, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	switch ev.Method {
+	case "Browser.downloadWillBegin":
+		var p struct {
+			GUID              string `json:"guid"`
+			URL               string `json:"url"`
+			SuggestedFilename string `json:"suggestedFilename"`
+		}
+		if err := ev.Unmarshal(&p); err != nil {
+			return nil, false, err
+		}
+		w.active[p.GUID] = &Download{
+			GUID:              p.GUID,
+			URL:               p.URL,
+			SuggestedFilename: p.SuggestedFilename,
+			State:             DownloadInProgress,
+		}
+		return nil, false, nil
+
+	case "Browser.downloadProgress":
+		var p struct {
+			GUID          string  `json:"guid"`
+			TotalBytes    float64 `json:"totalBytes"`
+			ReceivedBytes float64 `json:"receivedBytes"`
+			State         string  `json:"state"`
+		}
+		if err := ev.Unmarshal(&p); err != nil {
+			return nil, false, err
+		}
+		d, ok := w.active[p.GUID]
+		if !ok {
+			d = &Download{GUID: p.GUID}
+			w.active[p.GUID] = d
+		}
+		d.TotalBytes = int64(p.TotalBytes)
+		d.ReceivedBytes = int64(p.ReceivedBytes)
+		d.State = p.State
+
+		if p.State != DownloadCompleted && p.State != DownloadCanceled {
+			return nil, false, nil
+		}
+		delete(w.active, p.GUID)
+		if p.State == DownloadCompleted {
+			d.Path = w.pathFor(d)
+			w.completed = append(w.completed, *d)
+		}
+		result := *d
+		return &result, true, nil
+	}
+	return nil, false, nil
+}
+
+// pathFor computes where the browser saved a download.
+func (w *DownloadWatcher) pathFor(d *Download) string {
+	name := d.GUID
+	if w.behavior == DownloadBehaviorAllow && d.SuggestedFilename != "" {
+		name = d.SuggestedFilename
+	}
+	return joinRemotePath(w.dir, name)
+}
+
+// joinRemotePath joins a directory and file name using the separator style
+// of the directory, since the browser may run on a different OS than the SDK.
+func joinRemotePath(dir, name string) string {
+	sep := "/"
+	if strings.Contains(dir, `\`) && !strings.Contains(dir, "/") {
+		sep = `\`
+	}
+	return strings.TrimRight(dir, `/\`) + sep + name
+}
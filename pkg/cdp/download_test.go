@@ -0,0 +1,140 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetDownloadBehavior(t *testing.T) {
+	t.Run("sends path and enables events", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		conn := mustDial(t, b)
+
+		if err := conn.SetDownloadBehavior(context.Background(), DownloadBehaviorAllow, "/tmp/dl"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		calls := b.callsTo("Browser.setDownloadBehavior")
+		if len(calls) != 1 {
+			t.Fatalf("calls = %d, want 1", len(calls))
+		}
+		var p struct {
+			Behavior      string `json:"behavior"`
+			DownloadPath  string `json:"downloadPath"`
+			EventsEnabled bool   `json:"eventsEnabled"`
+		}
+		json.Unmarshal(calls[0].Params, &p)
+		if p.Behavior != "allow" || p.DownloadPath != "/tmp/dl" || !p.EventsEnabled {
+			t.Errorf("unexpected params: %+v", p)
+		}
+	})
+
+	t.Run("requires directory", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		conn := mustDial(t, b)
+
+		if err := conn.SetDownloadBehavior(context.Background(), DownloadBehaviorAllowAndName, ""); err == nil {
+			t.Error("expected error, got nil")
+		}
+		if len(b.callsTo("Browser.setDownloadBehavior")) != 0 {
+			t.Error("should not call the browser without a directory")
+		}
+	})
+}
+
+func TestDownloadWatcher(t *testing.T) {
+	t.Run("waits for completion", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		conn := mustDial(t, b)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		w, err := conn.WatchDownloads(ctx, "/tmp/dl/")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer w.Close()
+
+		b.emit("Browser.downloadWillBegin", map[string]any{
+			"guid": "g-1", "url": "https://example.com/report.pdf", "suggestedFilename": "report.pdf",
+		})
+		b.emit("Browser.downloadProgress", map[string]any{
+			"guid": "g-1", "totalBytes": 100, "receivedBytes": 50, "state": "inProgress",
+		})
+		b.emit("Browser.downloadProgress", map[string]any{
+			"guid": "g-1", "totalBytes": 100, "receivedBytes": 100, "state": "completed",
+		})
+
+		d, err := w.Wait(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Path != "/tmp/dl/g-1" {
+			t.Errorf("Path = %q, want %q", d.Path, "/tmp/dl/g-1")
+		}
+		if d.SuggestedFilename != "report.pdf" || d.ReceivedBytes != 100 {
+			t.Errorf("unexpected download: %+v", d)
+		}
+		if paths := w.Paths(); len(paths) != 1 || paths[0] != "/tmp/dl/g-1" {
+			t.Errorf("Paths = %v", paths)
+		}
+	})
+
+	t.Run("uses suggested name with allow behavior", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		conn := mustDial(t, b)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		w, err := conn.WatchDownloadsWithBehavior(ctx, DownloadBehaviorAllow, `C:\Downloads`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer w.Close()
+
+		b.emit("Browser.downloadWillBegin", map[string]any{
+			"guid": "g-2", "url": "https://example.com/a.csv", "suggestedFilename": "a.csv",
+		})
+		b.emit("Browser.downloadProgress", map[string]any{"guid": "g-2", "state": "completed"})
+
+		d, err := w.Wait(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if d.Path != `C:\Downloads\a.csv` {
+			t.Errorf("Path = %q", d.Path)
+		}
+	})
+
+	t.Run("reports cancellation", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		conn := mustDial(t, b)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		w, err := conn.WatchDownloads(ctx, "/tmp/dl")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer w.Close()
+
+		b.emit("Browser.downloadProgress", map[string]any{"guid": "g-3", "state": "canceled"})
+
+		d, err := w.Wait(ctx)
+		if !errors.Is(err, ErrDownloadCanceled) {
+			t.Errorf("expected ErrDownloadCanceled, got %v", err)
+		}
+		if d == nil || d.GUID != "g-3" {
+			t.Errorf("expected canceled download details, got %+v", d)
+		}
+		if len(w.Completed()) != 0 {
+			t.Error("canceled download should not be listed as completed")
+		}
+	})
+}
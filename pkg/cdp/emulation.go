@@ -0,0 +1,155 @@
+package cdp
+
+import (
+	"context"
+	"fmt"
+)
+
+// Geolocation is a position reported to the Geolocation API.
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Accuracy  float64 `json:"accuracy"` // Meters; 0 means 100
+}
+
+// GeoOverride is a set of location settings for Session.Emulate. Empty
+// fields are left unchanged.
+type GeoOverride struct {
+	Timezone    string       `json:"timezone,omitempty"`    // IANA ID, e.g. "Europe/Berlin"
+	Locale      string       `json:"locale,omitempty"`      // ICU locale, e.g. "de-DE"
+	Geolocation *Geolocation `json:"geolocation,omitempty"` // nil leaves the position unchanged
+}
+
+// SetTimezone overrides the page's time zone with an IANA ID such as
+// "America/New_York", e.g. to match a proxy switched after the profile was
+// opened. An empty id restores the profile's time zone. Like every
+// emulation setting, the override ends when the session detaches.
+// Emulation.setTimezoneOverride
+func (s *Session) SetTimezone(ctx context.Context, id string) error {
+	params := struct {
+		TimezoneID string `json:"timezoneId"`
+	}{TimezoneID: id}
+	if err := s.Call(ctx, "Emulation.setTimezoneOverride", params, nil); err != nil {
+		return fmt.Errorf("cdp: failed to set time zone %q: %w", id, err)
+	}
+	return nil
+}
+
+// SetLocale overrides the locale used by Intl and date formatting, e.g.
+// "de-DE". An empty locale restores the profile's locale. It does not
+// change navigator.language or the Accept-Language header.
+// Emulation.setLocaleOverride
+func (s *Session) SetLocale(ctx context.Context, locale string) error {
+	params := struct {
+		Locale string `json:"locale,omitempty"`
+	}{Locale: locale}
+	if err := s.Call(ctx, "Emulation.setLocaleOverride", params, nil); err != nil {
+		return fmt.Errorf("cdp: failed to set locale %q: %w", locale, err)
+	}
+	return nil
+}
+
+// SetGeolocation overrides the position reported to the Geolocation API
+// and grants pages the geolocation permission, so that they get it without
+// a prompt. A nil loc clears the override.
+// Emulation.setGeolocationOverride
+func (s *Session) SetGeolocation(ctx context.Context, loc *Geolocation) error {
+	if loc == nil {
+		return s.Call(ctx, "Emulation.clearGeolocationOverride", nil, nil)
+	}
+	if loc.Latitude < -90 || loc.Latitude > 90 || loc.Longitude < -180 || loc.Longitude > 180 {
+		return fmt.Errorf("cdp: invalid geolocation %v,%v", loc.Latitude, loc.Longitude)
+	}
+	params := *loc
+	if params.Accuracy <= 0 {
+		params.Accuracy = 100
+	}
+	grant := struct {
+		Permissions []string `json:"permissions"`
+	}{Permissions: []string{"geolocation"}}
+	// Best effort: without the grant, pages still get the position after a prompt.
+	_ = s.conn.Call(ctx, "Browser.grantPermissions", grant, nil)
+
+	if err := s.Call(ctx, "Emulation.setGeolocationOverride", params, nil); err != nil {
+		return fmt.Errorf("cdp: failed to set geolocation: %w", err)
+	}
+	return nil
+}
+
+// Emulate applies the non-empty settings of o: the time zone, the locale
+// and the geolocation. It stops at the first failing setting.
+//
+// Example:
+//
+//	err := session.Emulate(ctx, cdp.GeoOverride{
+//	    Timezone:    "Europe/Berlin",
+//	    Locale:      "de-DE",
+//	    Geolocation: &cdp.Geolocation{Latitude: 52.52, Longitude: 13.405},
+//	})
+func (s *Session) Emulate(ctx context.Context, o GeoOverride) error {
+	if o.Timezone != "" {
+		if err := s.SetTimezone(ctx, o.Timezone); err != nil {
+			return err
+		}
+	}
+	if o.Locale != "" {
+		if err := s.SetLocale(ctx, o.Locale); err != nil {
+			return err
+		}
+	}
+	if o.Geolocation != nil {
+		return s.SetGeolocation(ctx, o.Geolocation)
+	}
+	return nil
+}
+
+// DeviceMetrics is a device screen for Session.SetDeviceMetrics.
+type DeviceMetrics struct {
+	Width             int     // Viewport and screen width in CSS pixels
+	Height            int     // Viewport and screen height in CSS pixels
+	DeviceScaleFactor float64 // window.devicePixelRatio; 0 keeps the browser's
+	Mobile            bool    // Emulate a mobile viewport and scrollbars
+	MaxTouchPoints    int     // Touch points to emulate; 0 disables touch
+}
+
+// SetDeviceMetrics overrides the page's viewport, screen size and pixel
+// ratio, and emulates touch input with m.MaxTouchPoints points, so that
+// pages lay out and respond as on the device. A nil m clears both
+// overrides. The overrides end when the session detaches.
+// Emulation.setDeviceMetricsOverride, Emulation.setTouchEmulationEnabled
+func (s *Session) SetDeviceMetrics(ctx context.Context, m *DeviceMetrics) error {
+	if m == nil {
+		if err := s.Call(ctx, "Emulation.clearDeviceMetricsOverride", nil, nil); err != nil {
+			return fmt.Errorf("cdp: failed to clear device metrics: %w", err)
+		}
+		return s.setTouch(ctx, 0)
+	}
+	if m.Width <= 0 || m.Height <= 0 || m.DeviceScaleFactor < 0 {
+		return fmt.Errorf("cdp: invalid device metrics %dx%d@%v", m.Width, m.Height, m.DeviceScaleFactor)
+	}
+	params := struct {
+		Width             int     `json:"width"`
+		Height            int     `json:"height"`
+		DeviceScaleFactor float64 `json:"deviceScaleFactor"`
+		Mobile            bool    `json:"mobile"`
+		ScreenWidth       int     `json:"screenWidth"`
+		ScreenHeight      int     `json:"screenHeight"`
+	}{m.Width, m.Height, m.DeviceScaleFactor, m.Mobile, m.Width, m.Height}
+	if err := s.Call(ctx, "Emulation.setDeviceMetricsOverride", params, nil); err != nil {
+		return fmt.Errorf("cdp: failed to set device metrics: %w", err)
+	}
+	return s.setTouch(ctx, m.MaxTouchPoints)
+}
+
+// setTouch enables touch emulation with points touch points, or disables it
+// if points is 0.
+func (s *Session) setTouch(ctx context.Context, points int) error {
+	params := struct {
+		Enabled        bool `json:"enabled"`
+		MaxTouchPoints int  `json:"maxTouchPoints,omitempty"`
+	}{Enabled: points > 0, MaxTouchPoints: points}
+	if err := s.Call(ctx, "Emulation.setTouchEmulationEnabled", params, nil); err != nil {
+		return fmt.Errorf("cdp: failed to set touch emulation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEmulate(t *testing.T) {
+	setup := func(t *testing.T) (*fakeBrowser, *Session) {
+		b := newFakeBrowser(t)
+		handlePage(b)
+		s, err := mustDial(t, b).AttachToPage(context.Background())
+		if err != nil {
+			t.Fatalf("AttachToPage failed: %v", err)
+		}
+		return b, s
+	}
+
+	t.Run("applies the set fields", func(t *testing.T) {
+		b, s := setup(t)
+		err := s.Emulate(context.Background(), GeoOverride{
+			Timezone:    "Europe/Berlin",
+			Geolocation: &Geolocation{Latitude: 52.52, Longitude: 13.405},
+		})
+		if err != nil {
+			t.Fatalf("Emulate failed: %v", err)
+		}
+
+		tz := b.callsTo("Emulation.setTimezoneOverride")
+		if len(tz) != 1 || tz[0].SessionID != "S1" || !strings.Contains(string(tz[0].Params), `"Europe/Berlin"`) {
+			t.Errorf("setTimezoneOverride calls = %+v", tz)
+		}
+		if n := len(b.callsTo("Emulation.setLocaleOverride")); n != 0 {
+			t.Errorf("setLocaleOverride called %d times, want 0", n)
+		}
+		geo := b.callsTo("Emulation.setGeolocationOverride")
+		if len(geo) != 1 {
+			t.Fatalf("setGeolocationOverride calls = %+v", geo)
+		}
+		var loc Geolocation
+		json.Unmarshal(geo[0].Params, &loc)
+		if loc != (Geolocation{Latitude: 52.52, Longitude: 13.405, Accuracy: 100}) {
+			t.Errorf("geolocation = %+v", loc)
+		}
+		grants := b.callsTo("Browser.grantPermissions")
+		if len(grants) != 1 || !strings.Contains(string(grants[0].Params), "geolocation") {
+			t.Errorf("grantPermissions calls = %+v", grants)
+		}
+	})
+
+	t.Run("empty values clear the overrides", func(t *testing.T) {
+		b, s := setup(t)
+		ctx := context.Background()
+		if err := s.SetLocale(ctx, ""); err != nil {
+			t.Fatalf("SetLocale failed: %v", err)
+		}
+		if err := s.SetGeolocation(ctx, nil); err != nil {
+			t.Fatalf("SetGeolocation failed: %v", err)
+		}
+		if locale := b.callsTo("Emulation.setLocaleOverride"); len(locale) != 1 || string(locale[0].Params) != "{}" {
+			t.Errorf("setLocaleOverride calls = %+v", locale)
+		}
+		if len(b.callsTo("Emulation.clearGeolocationOverride")) != 1 {
+			t.Error("clearGeolocationOverride was not called")
+		}
+	})
+
+	t.Run("rejects an invalid position", func(t *testing.T) {
+		b, s := setup(t)
+		if err := s.SetGeolocation(context.Background(), &Geolocation{Latitude: 91}); err == nil {
+			t.Error("expected an error for latitude 91")
+		}
+		if len(b.callsTo("Emulation.setGeolocationOverride")) != 0 {
+			t.Error("setGeolocationOverride was called")
+		}
+	})
+}
+
+func TestSetDeviceMetrics(t *testing.T) {
+	b := newFakeBrowser(t)
+	handlePage(b)
+	s, err := mustDial(t, b).AttachToPage(context.Background())
+	if err != nil {
+		t.Fatalf("AttachToPage failed: %v", err)
+	}
+	ctx := context.Background()
+
+	err = s.SetDeviceMetrics(ctx, &DeviceMetrics{Width: 393, Height: 852, DeviceScaleFactor: 3, Mobile: true, MaxTouchPoints: 5})
+	if err != nil {
+		t.Fatalf("SetDeviceMetrics failed: %v", err)
+	}
+	metrics := b.callsTo("Emulation.setDeviceMetricsOverride")
+	want := `{"width":393,"height":852,"deviceScaleFactor":3,"mobile":true,"screenWidth":393,"screenHeight":852}`
+	if len(metrics) != 1 || string(metrics[0].Params) != want {
+		t.Errorf("setDeviceMetricsOverride calls = %+v", metrics)
+	}
+	if touch := b.callsTo("Emulation.setTouchEmulationEnabled"); len(touch) != 1 || string(touch[0].Params) != `{"enabled":true,"maxTouchPoints":5}` {
+		t.Errorf("setTouchEmulationEnabled calls = %+v", touch)
+	}
+
+	if err := s.SetDeviceMetrics(ctx, nil); err != nil {
+		t.Fatalf("SetDeviceMetrics(nil) failed: %v", err)
+	}
+	if len(b.callsTo("Emulation.clearDeviceMetricsOverride")) != 1 {
+		t.Error("clearDeviceMetricsOverride was not called")
+	}
+	if touch := b.callsTo("Emulation.setTouchEmulationEnabled"); len(touch) != 2 || string(touch[1].Params) != `{"enabled":false}` {
+		t.Errorf("setTouchEmulationEnabled calls = %+v", touch)
+	}
+
+	if err := s.SetDeviceMetrics(ctx, &DeviceMetrics{Width: 0, Height: 852}); err == nil {
+		t.Error("expected an error for width 0")
+	}
+}
@@ -0,0 +1,53 @@
+package cdp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors for error type checking using errors.Is().
+var (
+	// ErrClosed indicates the connection or subscription has been closed.
+	ErrClosed = errors.New("cdp: connection closed")
+
+	// ErrNotFound indicates a target or element could not be found.
+	ErrNotFound = errors.New("cdp: not found")
+
+	// ErrDownloadCanceled indicates a download was canceled before completion.
+	ErrDownloadCanceled = errors.New("cdp: download canceled")
+)
+
+// Error is a protocol-level error returned by the browser for a command.
+type Error struct {
+	Code    int    `json:"code"`    // JSON-RPC error code (e.g. -32000)
+	Message string `json:"message"` // Error message from the browser
+	Data    string `json:"data"`    // Optional extra detail
+	Method  string `json:"-"`       // Method that failed
+}
+
+func (e *Error) Error() string {
+	if e.Data != "" {
+		return fmt.Sprintf("cdp: %s failed (code %d): %s: %s", e.Method, e.Code, e.Message, e.Data)
+	}
+	return fmt.Sprintf("cdp: %s failed (code %d): %s", e.Method, e.Code, e.Message)
+}
+
+// ClosedError reports that the connection was lost.
+type ClosedError struct {
+	Err error // Underlying read error (io.EOF for a clean close)
+}
+
+func (e *ClosedError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("cdp: connection closed: %v", e.Err)
+	}
+	return "cdp: connection closed"
+}
+
+func (e *ClosedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ClosedError) Is(target error) bool {
+	return target == ErrClosed
+}
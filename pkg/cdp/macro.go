@@ -0,0 +1,522 @@
+package cdp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Macro step actions.
+const (
+	MacroNavigate = "navigate" // Load URL and wait for the page to finish loading
+	MacroClick    = "click"    // Click the element matching Selector
+	MacroType     = "type"     // Type Text into Selector, or the focused element
+	MacroWait     = "wait"     // Wait for Selector to appear, or sleep Duration
+	MacroWaitFor  = "waitFor"  // Same as MacroWait
+
+	MacroExtract       = "extract"       // Store the text or Attribute of Selector as Name
+	MacroExportCookies = "exportCookies" // Store the browser's cookies as Name (default "cookies")
+)
+
+// defaultMacroTimeout bounds how long a step waits for a page or element.
+const defaultMacroTimeout = 30 * time.Second
+
+// MacroStep is one step of a macro run by RunMacro. Steps are plain data,
+// so macros can be stored as JSON; see ParseMacro.
+type MacroStep struct {
+	Action    string        `json:"action"`
+	URL       string        `json:"url,omitempty"`       // MacroNavigate
+	Selector  string        `json:"selector,omitempty"`  // MacroClick, MacroType, MacroWait and MacroExtract
+	Text      string        `json:"text,omitempty"`      // MacroType
+	Duration  time.Duration `json:"duration,omitempty"`  // MacroWait without a Selector
+	Timeout   time.Duration `json:"timeout,omitempty"`   // Wait for the page or element (default: 30s)
+	Name      string        `json:"name,omitempty"`      // MacroExtract and MacroExportCookies
+	Attribute string        `json:"attribute,omitempty"` // MacroExtract; empty for the text content
+	All       bool          `json:"all,omitempty"`       // MacroExtract every match, without crossing shadow roots
+}
+
+// UnmarshalJSON decodes a step whose Duration and Timeout are either
+// nanoseconds or strings such as "1.5s", as time.ParseDuration reads them.
+func (m *MacroStep) UnmarshalJSON(data []byte) error {
+	type step MacroStep
+	var raw struct {
+		*step
+		Duration json.RawMessage `json:"duration,omitempty"`
+		Timeout  json.RawMessage `json:"timeout,omitempty"`
+	}
+	raw.step = (*step)(m)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	if m.Duration, err = parseMacroDuration(raw.Duration); err != nil {
+		return fmt.Errorf("duration: %w", err)
+	}
+	if m.Timeout, err = parseMacroDuration(raw.Timeout); err != nil {
+		return fmt.Errorf("timeout: %w", err)
+	}
+	return nil
+}
+
+// parseMacroDuration decodes a duration given in nanoseconds or as a string.
+func parseMacroDuration(raw json.RawMessage) (time.Duration, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return time.ParseDuration(s)
+	}
+	var n int64
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return 0, fmt.Errorf("want nanoseconds or a string such as \"5s\", got %s", raw)
+	}
+	return time.Duration(n), nil
+}
+
+// ParseMacro decodes a macro from JSON: an array of steps, or an object
+// with the steps in "steps". It checks that every step has a known action
+// and the fields the action needs, so that a bad file fails before a
+// browser is opened.
+//
+//	{"steps": [
+//	    {"action": "navigate", "url": "https://example.com/"},
+//	    {"action": "waitFor", "selector": "h1", "timeout": "10s"},
+//	    {"action": "extract", "selector": "h1", "name": "title"},
+//	    {"action": "exportCookies"}
+//	]}
+//
+// Data that does not start with "[" or "{" is read as YAML: a list of
+// steps, at the top level or under "steps:". Only flat step maps with
+// scalar values are supported, which is all a step needs:
+//
+//	steps:
+//	  - action: navigate
+//	    url: https://example.com/
+//	  - action: extract
+//	    selector: a
+//	    attribute: href
+//	    all: true
+//	    name: links
+func ParseMacro(data []byte) ([]MacroStep, error) {
+	var steps []MacroStep
+	var err error
+	switch trimmed := strings.TrimSpace(string(data)); {
+	case strings.HasPrefix(trimmed, "{"):
+		var macro struct {
+			Steps []MacroStep `json:"steps"`
+		}
+		err = json.Unmarshal(data, &macro)
+		steps = macro.Steps
+	case !strings.HasPrefix(trimmed, "["):
+		if data, err = macroYAMLToJSON(data); err != nil {
+			return nil, fmt.Errorf("cdp: parse macro: %w", err)
+		}
+		fallthrough
+	default:
+		err = json.Unmarshal(data, &steps)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cdp: parse macro: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("cdp: parse macro: no steps")
+	}
+	for i, step := range steps {
+		if err := step.validate(); err != nil {
+			return nil, fmt.Errorf("cdp: macro step %d (%s): %w", i+1, step.Action, err)
+		}
+	}
+	return steps, nil
+}
+
+// macroYAMLToJSON converts the YAML subset read by ParseMacro to a JSON
+// array of steps. Like bitbrowser.ReadConfig, it reads YAML line by line
+// rather than depending on a YAML library. "all" is decoded as a boolean
+// and a duration or timeout given as a plain integer as nanoseconds;
+// every other value is a string.
+func macroYAMLToJSON(data []byte) ([]byte, error) {
+	var steps []map[string]any
+	itemIndent := -1
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if trimmed == "steps:" && indent == 0 && itemIndent < 0 {
+			continue
+		}
+
+		if trimmed == "-" || strings.HasPrefix(trimmed, "- ") {
+			if itemIndent >= 0 && indent != itemIndent {
+				return nil, fmt.Errorf("line %d: nested lists are not supported", lineNo)
+			}
+			itemIndent = indent
+			steps = append(steps, make(map[string]any))
+			if trimmed = strings.TrimSpace(trimmed[1:]); trimmed == "" {
+				continue
+			}
+		} else if itemIndent < 0 || indent <= itemIndent {
+			return nil, fmt.Errorf("line %d: expected a list of steps", lineNo)
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected key: value", lineNo)
+		}
+		if value == "" {
+			return nil, fmt.Errorf("line %d: %s: nested values are not supported", lineNo, key)
+		}
+		v, err := macroYAMLValue(key, value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", lineNo, key, err)
+		}
+		steps[len(steps)-1][key] = v
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(steps)
+}
+
+// macroYAMLValue decodes the scalar value of key: a quoted string, or an
+// unquoted one without its trailing comment.
+func macroYAMLValue(key, value string) (any, error) {
+	quoted := true
+	switch value[0] {
+	case '"':
+		prefix, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("unterminated string")
+		}
+		value, _ = strconv.Unquote(prefix)
+	case '\'':
+		end := strings.Index(value[1:], "'")
+		for end >= 0 && strings.HasPrefix(value[end+2:], "'") {
+			next := strings.Index(value[end+3:], "'")
+			if next < 0 {
+				end = -1
+				break
+			}
+			end += 2 + next
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated string")
+		}
+		value = strings.ReplaceAll(value[1:end+1], "''", "'")
+	default:
+		quoted = false
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+	}
+
+	switch key {
+	case "all":
+		return strconv.ParseBool(value)
+	case "duration", "timeout":
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil && !quoted {
+			return n, nil
+		}
+	}
+	return value, nil
+}
+
+// validate checks that the step has the fields its action needs.
+func (m MacroStep) validate() error {
+	switch m.Action {
+	case MacroNavigate:
+		if strings.TrimSpace(m.URL) == "" {
+			return fmt.Errorf("url is required")
+		}
+	case MacroClick, MacroExtract:
+		if strings.TrimSpace(m.Selector) == "" {
+			return fmt.Errorf("selector is required")
+		}
+		if m.Action == MacroExtract && m.Name == "" {
+			return fmt.Errorf("name is required")
+		}
+	case MacroType, MacroWait, MacroWaitFor, MacroExportCookies:
+	default:
+		return fmt.Errorf("unknown action %q", m.Action)
+	}
+	return nil
+}
+
+// elementCenterJS scrolls the element found by queryElementJS into view
+// and returns the viewport coordinates of its center, or null.
+const elementCenterJS = `(function(el) {
+	if (!el) return null;
+	el.scrollIntoView({block: "center", inline: "center"});
+	const r = el.getBoundingClientRect();
+	return {x: r.left + r.width / 2, y: r.top + r.height / 2};
+})(%s)`
+
+// RunMacro runs steps in order on the page, stopping at the first step
+// that fails. Clicks are real mouse events at the element's center, and
+// text is typed one character at a time, so pages see input as if it came
+// from a user. Selectors may cross shadow roots using ShadowPierce.
+//
+// Example:
+//
+//	err := session.RunMacro(ctx, []cdp.MacroStep{
+//	    {Action: cdp.MacroNavigate, URL: "https://example.com/login"},
+//	    {Action: cdp.MacroType, Selector: "#email", Text: "jane@example.com"},
+//	    {Action: cdp.MacroClick, Selector: "button[type=submit]"},
+//	    {Action: cdp.MacroWait, Selector: ".dashboard"},
+//	})
+func (s *Session) RunMacro(ctx context.Context, steps []MacroStep) error {
+	_, err := s.RunMacroOutput(ctx, steps)
+	return err
+}
+
+// RunMacroOutput runs steps like RunMacro and returns what the MacroExtract
+// and MacroExportCookies steps stored, by name. Extracted values are
+// strings, or lists of strings with All; cookies are the objects of
+// Storage.getCookies. On failure it returns what was stored before the
+// failing step.
+func (s *Session) RunMacroOutput(ctx context.Context, steps []MacroStep) (map[string]any, error) {
+	output := make(map[string]any)
+	for i, step := range steps {
+		if err := s.runMacroStep(ctx, step, output); err != nil {
+			return output, fmt.Errorf("cdp: macro step %d (%s): %w", i+1, step.Action, err)
+		}
+	}
+	return output, nil
+}
+
+// RunMacro connects to a browser WebSocket URL (typically OpenResult.Ws),
+// attaches to the first page and runs a macro. See Session.RunMacro.
+func RunMacro(ctx context.Context, wsURL string, steps []MacroStep) error {
+	conn, err := Dial(ctx, wsURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	session, err := conn.AttachToPage(ctx)
+	if err != nil {
+		return err
+	}
+	return session.RunMacro(ctx, steps)
+}
+
+// runMacroStep runs a single step, storing extracted values in output.
+func (s *Session) runMacroStep(ctx context.Context, step MacroStep, output map[string]any) error {
+	timeout := step.Timeout
+	if timeout <= 0 {
+		timeout = defaultMacroTimeout
+	}
+	switch step.Action {
+	case MacroNavigate:
+		return s.navigate(ctx, step.URL, timeout)
+	case MacroClick:
+		return s.click(ctx, step.Selector, timeout)
+	case MacroType:
+		if step.Selector == "" {
+			return s.insertText(ctx, step.Text)
+		}
+		if err := s.waitForElement(ctx, step.Selector, timeout); err != nil {
+			return err
+		}
+		return s.Type(ctx, step.Selector, step.Text)
+	case MacroWait, MacroWaitFor:
+		if step.Selector != "" {
+			return s.waitForElement(ctx, step.Selector, timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(step.Duration):
+			return nil
+		}
+	case MacroExtract:
+		if step.Name == "" {
+			return fmt.Errorf("name is required")
+		}
+		if err := s.waitForElement(ctx, step.Selector, timeout); err != nil {
+			return err
+		}
+		value, err := s.extract(ctx, step.Selector, step.Attribute, step.All)
+		if err != nil {
+			return err
+		}
+		output[step.Name] = value
+		return nil
+	case MacroExportCookies:
+		var result struct {
+			Cookies []map[string]any `json:"cookies"`
+		}
+		if err := s.conn.Call(ctx, "Storage.getCookies", nil, &result); err != nil {
+			return err
+		}
+		name := step.Name
+		if name == "" {
+			name = "cookies"
+		}
+		output[name] = result.Cookies
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q", step.Action)
+	}
+}
+
+// extractJS returns the trimmed text content, or the attribute, of an
+// element.
+const extractJS = `(function(el, attr) {
+	if (!el) return null;
+	return attr ? el.getAttribute(attr) : el.textContent.trim();
+})`
+
+// extract returns the text or attribute of the element matching selector
+// or, with all, of every element matching it in the document.
+func (s *Session) extract(ctx context.Context, selector, attribute string, all bool) (any, error) {
+	attr, err := json.Marshal(attribute)
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		sel, err := json.Marshal(selector)
+		if err != nil {
+			return nil, err
+		}
+		var values []string
+		expr := fmt.Sprintf(`Array.from(document.querySelectorAll(%s), (el) => %s(el, %s) ?? "")`, sel, extractJS, attr)
+		if err := s.Evaluate(ctx, expr, &values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+	expr, err := elementExpression(extractJS+"(%s, "+strings.ReplaceAll(string(attr), "%", "%%")+")", selector)
+	if err != nil {
+		return nil, err
+	}
+	var value *string
+	if err := s.Evaluate(ctx, expr, &value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return "", nil
+	}
+	return *value, nil
+}
+
+// navigate loads url and waits until the document has finished loading.
+// Page.navigate
+func (s *Session) navigate(ctx context.Context, url string, timeout time.Duration) error {
+	if strings.TrimSpace(url) == "" {
+		return fmt.Errorf("url is required")
+	}
+	var result struct {
+		ErrorText string `json:"errorText"`
+	}
+	params := struct {
+		URL string `json:"url"`
+	}{URL: url}
+	if err := s.Call(ctx, "Page.navigate", params, &result); err != nil {
+		return err
+	}
+	if result.ErrorText != "" {
+		return fmt.Errorf("load %s: %s", url, result.ErrorText)
+	}
+	return s.poll(ctx, timeout, `document.readyState === "complete"`, func(raw json.RawMessage) bool {
+		var ready bool
+		return json.Unmarshal(raw, &ready) == nil && ready
+	}, func() error {
+		return fmt.Errorf("%s did not finish loading within %s", url, timeout)
+	})
+}
+
+// click dispatches a left click at the center of the element matching
+// selector, waiting for it to appear.
+// Input.dispatchMouseEvent
+func (s *Session) click(ctx context.Context, selector string, timeout time.Duration) error {
+	expr, err := elementExpression(elementCenterJS, selector)
+	if err != nil {
+		return err
+	}
+	var center struct{ X, Y float64 }
+	err = s.poll(ctx, timeout, expr, func(raw json.RawMessage) bool {
+		return string(raw) != "null" && json.Unmarshal(raw, &center) == nil
+	}, func() error {
+		return fmt.Errorf("%w: no element matches %q", ErrNotFound, selector)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, typ := range []string{"mouseMoved", "mousePressed", "mouseReleased"} {
+		params := struct {
+			Type       string  `json:"type"`
+			X          float64 `json:"x"`
+			Y          float64 `json:"y"`
+			Button     string  `json:"button"`
+			ClickCount int     `json:"clickCount,omitempty"`
+		}{Type: typ, X: center.X, Y: center.Y, Button: "left"}
+		if typ != "mouseMoved" {
+			params.ClickCount = 1
+		} else {
+			params.Button = "none"
+		}
+		if err := s.Call(ctx, "Input.dispatchMouseEvent", params, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForElement waits until an element matches selector.
+func (s *Session) waitForElement(ctx context.Context, selector string, timeout time.Duration) error {
+	expr, err := elementExpression("(%s) !== null", selector)
+	if err != nil {
+		return err
+	}
+	return s.poll(ctx, timeout, expr, func(raw json.RawMessage) bool {
+		var found bool
+		return json.Unmarshal(raw, &found) == nil && found
+	}, func() error {
+		return fmt.Errorf("%w: no element matches %q within %s", ErrNotFound, selector, timeout)
+	})
+}
+
+// elementExpression wraps queryElementJS for selector in format.
+func elementExpression(format, selector string) (string, error) {
+	if strings.TrimSpace(selector) == "" {
+		return "", fmt.Errorf("selector is required")
+	}
+	encoded, err := json.Marshal(splitSelector(selector))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode selector: %w", err)
+	}
+	return fmt.Sprintf(format, fmt.Sprintf(queryElementJS, encoded)), nil
+}
+
+// poll evaluates expr every 100ms until done accepts its result, returning
+// the error from expired once timeout has passed. Evaluation errors are
+// retried, since they are expected while a page is navigating.
+func (s *Session) poll(ctx context.Context, timeout time.Duration, expr string, done func(json.RawMessage) bool, expired func() error) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		var raw json.RawMessage
+		if err := s.Evaluate(ctx, expr, &raw); err == nil && done(raw) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return expired()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// macroPage is a fake browser whose page has every element except those
+// matching missing.
+func macroPage(t *testing.T, missing string) (*fakeBrowser, *Session) {
+	t.Helper()
+	b := newFakeBrowser(t)
+	handlePage(b)
+	b.handle("Page.navigate", func(msg message) (any, *Error) {
+		return map[string]any{"frameId": "F1"}, nil
+	})
+	b.handle("Runtime.evaluate", func(msg message) (any, *Error) {
+		var p struct{ Expression string }
+		json.Unmarshal(msg.Params, &p)
+		var value any = true
+		switch {
+		case missing != "" && strings.Contains(p.Expression, missing):
+			value = false
+			if strings.Contains(p.Expression, "getBoundingClientRect") {
+				value = nil
+			}
+		case strings.Contains(p.Expression, "getBoundingClientRect"):
+			value = map[string]float64{"x": 40, "y": 12.5}
+		}
+		return map[string]any{"result": map[string]any{"type": "object", "value": value}}, nil
+	})
+	conn := mustDial(t, b)
+	s, err := conn.AttachToPage(context.Background())
+	if err != nil {
+		t.Fatalf("AttachToPage failed: %v", err)
+	}
+	return b, s
+}
+
+func TestRunMacro(t *testing.T) {
+	t.Run("runs steps in order", func(t *testing.T) {
+		b, s := macroPage(t, "")
+		err := s.RunMacro(context.Background(), []MacroStep{
+			{Action: MacroNavigate, URL: "https://example.com/login"},
+			{Action: MacroType, Selector: "#email", Text: "ab"},
+			{Action: MacroClick, Selector: "button[type=submit]"},
+			{Action: MacroWait, Duration: time.Millisecond},
+			{Action: MacroType, Text: "c"},
+			{Action: MacroWait, Selector: ".dashboard"},
+		})
+		if err != nil {
+			t.Fatalf("RunMacro failed: %v", err)
+		}
+
+		navs := b.callsTo("Page.navigate")
+		if len(navs) != 1 || !strings.Contains(string(navs[0].Params), "https://example.com/login") {
+			t.Errorf("navigate calls = %+v", navs)
+		}
+		var typed strings.Builder
+		for _, m := range b.callsTo("Input.insertText") {
+			var p struct{ Text string }
+			json.Unmarshal(m.Params, &p)
+			typed.WriteString(p.Text)
+		}
+		if typed.String() != "abc" {
+			t.Errorf("typed %q, want abc", typed.String())
+		}
+
+		var events []string
+		for _, m := range b.callsTo("Input.dispatchMouseEvent") {
+			var p struct {
+				Type   string
+				X, Y   float64
+				Button string
+			}
+			json.Unmarshal(m.Params, &p)
+			if p.X != 40 || p.Y != 12.5 {
+				t.Errorf("%s at (%v, %v), want (40, 12.5)", p.Type, p.X, p.Y)
+			}
+			events = append(events, p.Type+":"+p.Button)
+		}
+		if got := strings.Join(events, ","); got != "mouseMoved:none,mousePressed:left,mouseReleased:left" {
+			t.Errorf("mouse events = %s", got)
+		}
+	})
+
+	t.Run("missing element stops the macro", func(t *testing.T) {
+		b, s := macroPage(t, "#missing")
+		err := s.RunMacro(context.Background(), []MacroStep{
+			{Action: MacroClick, Selector: "#missing", Timeout: 150 * time.Millisecond},
+			{Action: MacroType, Selector: "#email", Text: "never"},
+		})
+		if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "step 1 (click)") {
+			t.Errorf("err = %v, want ErrNotFound for step 1", err)
+		}
+		if len(b.callsTo("Input.insertText")) != 0 || len(b.callsTo("Input.dispatchMouseEvent")) != 0 {
+			t.Error("input dispatched after a failed step")
+		}
+	})
+
+	t.Run("navigation error", func(t *testing.T) {
+		b, s := macroPage(t, "")
+		b.handle("Page.navigate", func(msg message) (any, *Error) {
+			return map[string]any{"errorText": "net::ERR_NAME_NOT_RESOLVED"}, nil
+		})
+		err := s.RunMacro(context.Background(), []MacroStep{{Action: MacroNavigate, URL: "https://nowhere.invalid"}})
+		if err == nil || !strings.Contains(err.Error(), "ERR_NAME_NOT_RESOLVED") {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		_, s := macroPage(t, "")
+		err := s.RunMacro(context.Background(), []MacroStep{{Action: MacroWait}, {Action: "scroll"}})
+		if err == nil || !strings.Contains(err.Error(), `step 2 (scroll): unknown action "scroll"`) {
+			t.Errorf("err = %v", err)
+		}
+	})
+
+	t.Run("returns extracted values and cookies", func(t *testing.T) {
+		b, s := macroPage(t, "")
+		b.handle("Runtime.evaluate", func(msg message) (any, *Error) {
+			var p struct{ Expression string }
+			json.Unmarshal(msg.Params, &p)
+			var value any = true
+			switch {
+			case strings.Contains(p.Expression, "querySelectorAll(\"li\")"):
+				value = []string{"one", "two"}
+			case strings.Contains(p.Expression, `"href")`):
+				value = "/next"
+			case strings.Contains(p.Expression, "getAttribute"):
+				value = "Welcome"
+			}
+			return map[string]any{"result": map[string]any{"type": "object", "value": value}}, nil
+		})
+		b.handle("Storage.getCookies", func(msg message) (any, *Error) {
+			return map[string]any{"cookies": []map[string]any{{"name": "sid", "value": "abc"}}}, nil
+		})
+
+		output, err := s.RunMacroOutput(context.Background(), []MacroStep{
+			{Action: MacroWaitFor, Selector: "h1"},
+			{Action: MacroExtract, Selector: "h1", Name: "title"},
+			{Action: MacroExtract, Selector: "a.next", Attribute: "href", Name: "next"},
+			{Action: MacroExtract, Selector: "li", All: true, Name: "items"},
+			{Action: MacroExportCookies},
+		})
+		if err != nil {
+			t.Fatalf("RunMacroOutput failed: %v", err)
+		}
+		if output["title"] != "Welcome" || output["next"] != "/next" || strings.Join(output["items"].([]string), ",") != "one,two" {
+			t.Errorf("output = %v", output)
+		}
+		if cookies, _ := output["cookies"].([]map[string]any); len(cookies) != 1 || cookies[0]["name"] != "sid" {
+			t.Errorf("cookies = %v", output["cookies"])
+		}
+	})
+}
+
+func TestParseMacro(t *testing.T) {
+	steps, err := ParseMacro([]byte(`{"steps": [
+		{"action": "navigate", "url": "https://example.com/"},
+		{"action": "waitFor", "selector": "h1", "timeout": "10s"},
+		{"action": "wait", "duration": 1500000000},
+		{"action": "extract", "selector": "h1", "name": "title"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseMacro failed: %v", err)
+	}
+	if len(steps) != 4 || steps[1].Timeout != 10*time.Second || steps[2].Duration != 1500*time.Millisecond || steps[3].Name != "title" {
+		t.Errorf("steps = %+v", steps)
+	}
+	if steps, err := ParseMacro([]byte(`[{"action": "exportCookies"}]`)); err != nil || len(steps) != 1 {
+		t.Errorf("ParseMacro(array) = %+v, %v", steps, err)
+	}
+
+	for input, want := range map[string]string{
+		`[]`:                       "no steps",
+		`[{"action": "navigate"}]`: "step 1 (navigate): url is required",
+		`[{"action": "extract", "selector": "h1"}]`: "name is required",
+		`[{"action": "scroll"}]`:                    `unknown action "scroll"`,
+		`[{"action": "wait", "duration": "soon"}]`:  "duration",
+	} {
+		if _, err := ParseMacro([]byte(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseMacro(%s) err = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestParseMacroYAML(t *testing.T) {
+	steps, err := ParseMacro([]byte(`# Collect the links
+steps:
+  - action: navigate
+    url: https://example.com/?q=a#top
+  - action: type
+    selector: "#q"
+    text: 'it''s: 1'
+  - action: wait
+    duration: 1500000000
+  -
+    action: extract # every link
+    selector: a
+    attribute: href
+    all: true
+    name: links
+    timeout: "10s"
+`))
+	if err != nil {
+		t.Fatalf("ParseMacro failed: %v", err)
+	}
+	want := []MacroStep{
+		{Action: MacroNavigate, URL: "https://example.com/?q=a#top"},
+		{Action: MacroType, Selector: "#q", Text: "it's: 1"},
+		{Action: MacroWait, Duration: 1500 * time.Millisecond},
+		{Action: MacroExtract, Selector: "a", Attribute: "href", All: true, Name: "links", Timeout: 10 * time.Second},
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("steps = %+v, want %+v", steps, want)
+	}
+	if steps, err := ParseMacro([]byte("- action: exportCookies\n")); err != nil || len(steps) != 1 {
+		t.Errorf("ParseMacro(list) = %+v, %v", steps, err)
+	}
+
+	for input, want := range map[string]string{
+		"action: navigate\n":                      "line 1: expected a list of steps",
+		"- action: extract\n  selector:\n    a\n": "line 2: selector: nested values are not supported",
+		"- action: click\n  - selector: a\n":      "line 2: nested lists are not supported",
+		"- action: extract\n  all: yes\n":         "line 2: all",
+		"- action: type\n  text: \"ab\n":          "unterminated string",
+		"- action: navigate\n":                    "step 1 (navigate): url is required",
+	} {
+		if _, err := ParseMacro([]byte(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseMacro(%q) err = %v, want %q", input, err, want)
+		}
+	}
+}
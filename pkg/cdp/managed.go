@@ -0,0 +1,292 @@
+package cdp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Default ManagedConfig settings.
+const (
+	DefaultKeepaliveInterval = 15 * time.Second
+	DefaultPingTimeout       = 5 * time.Second
+	DefaultReconnectDelay    = 500 * time.Millisecond
+	DefaultMaxReconnectDelay = 30 * time.Second
+)
+
+// Resolver returns the current DevTools WebSocket URL of a browser. It is
+// called before every (re)connect, because the debugging port can change,
+// e.g. after the browser was restarted. bitbrowser's Client.DebugURL is a
+// typical implementation.
+type Resolver func(ctx context.Context) (string, error)
+
+// ManagedConfig configures a ManagedConnection.
+type ManagedConfig struct {
+	// KeepaliveInterval is how often the connection is pinged.
+	// Default: DefaultKeepaliveInterval.
+	KeepaliveInterval time.Duration
+
+	// PingTimeout is how long to wait for a pong before the connection is
+	// considered dropped. Default: DefaultPingTimeout.
+	PingTimeout time.Duration
+
+	// ReconnectDelay is the first delay between reconnect attempts; it
+	// doubles up to MaxReconnectDelay. Defaults: DefaultReconnectDelay and
+	// DefaultMaxReconnectDelay.
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+
+	// OnReconnect is called with the new connection after a reconnect,
+	// before calls resume. Use it to re-enable domains and re-subscribe to
+	// events, which do not survive a drop.
+	OnReconnect func(conn *Conn)
+}
+
+// ManagedConnection is a Conn that survives drops. It pings the browser
+// periodically, detects lost connections, re-resolves the debug URL and
+// reconnects in the background.
+//
+// Calls interrupted by a drop are retried once on the new connection, so
+// they may run twice. Subscriptions belong to the underlying Conn and end
+// with it; re-create them in ManagedConfig.OnReconnect.
+//
+// A ManagedConnection is safe for concurrent use.
+//
+// Example:
+//
+//	mc, err := cdp.DialManaged(ctx, func(ctx context.Context) (string, error) {
+//	    return client.DebugURL(ctx, id)
+//	}, cdp.ManagedConfig{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer mc.Close()
+//	err = mc.Call(ctx, "Browser.getVersion", nil, &version)
+type ManagedConnection struct {
+	resolve Resolver
+	config  ManagedConfig
+	opts    []DialOption
+
+	mu      sync.Mutex
+	conn    *Conn
+	wsURL   string
+	changed chan struct{} // Closed and replaced whenever conn changes
+	err     error         // Set once closed
+
+	closing chan struct{}
+	stopped chan struct{}
+}
+
+// DialManaged resolves the debug URL, connects and starts keeping the
+// connection alive. The context bounds the first connection only.
+func DialManaged(ctx context.Context, resolve Resolver, config ManagedConfig, opts ...DialOption) (*ManagedConnection, error) {
+	if resolve == nil {
+		return nil, errors.New("cdp: resolver is required")
+	}
+	if config.KeepaliveInterval <= 0 {
+		config.KeepaliveInterval = DefaultKeepaliveInterval
+	}
+	if config.PingTimeout <= 0 {
+		config.PingTimeout = DefaultPingTimeout
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = DefaultReconnectDelay
+	}
+	if config.MaxReconnectDelay <= 0 {
+		config.MaxReconnectDelay = DefaultMaxReconnectDelay
+	}
+
+	m := &ManagedConnection{
+		resolve: resolve,
+		config:  config,
+		opts:    opts,
+		changed: make(chan struct{}),
+		closing: make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	conn, wsURL, err := m.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.conn, m.wsURL = conn, wsURL
+	go m.run()
+	return m, nil
+}
+
+// Call invokes a method on the current connection. If the connection drops
+// during the call, it waits for the reconnect and retries once.
+func (m *ManagedConnection) Call(ctx context.Context, method string, params, result any) error {
+	conn, err := m.current(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = conn.Call(ctx, method, params, result)
+	if !errors.Is(err, ErrClosed) {
+		return err
+	}
+	if conn, err = m.current(ctx, conn); err != nil {
+		return err
+	}
+	return conn.Call(ctx, method, params, result)
+}
+
+// Conn returns the current underlying connection. It is replaced after a
+// reconnect; prefer Call for commands.
+func (m *ManagedConnection) Conn() *Conn {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.conn
+}
+
+// URL returns the WebSocket URL of the current connection, e.g. to point a
+// chromedp remote allocator or rod at it after a reconnect.
+func (m *ManagedConnection) URL() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.wsURL
+}
+
+// DialContext dials the host and port of the browser's current debug URL,
+// whatever address is requested. Automation libraries that accept a custom
+// dialer (e.g. a websocket.Dialer's NetDialContext or an http.Transport's
+// DialContext) can use it to keep reaching the browser after its debugging
+// port changed.
+func (m *ManagedConnection) DialContext(ctx context.Context, network, _ string) (net.Conn, error) {
+	wsURL, err := m.resolve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cdp: resolve debug URL: %w", err)
+	}
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return nil, fmt.Errorf("cdp: invalid WebSocket URL %q: %w", wsURL, err)
+	}
+	cfg := &dialConfig{}
+	for _, opt := range m.opts {
+		opt(cfg)
+	}
+	return cfg.dial(ctx, network, u.Host)
+}
+
+// Close stops keepalive and reconnects and closes the connection.
+func (m *ManagedConnection) Close() error {
+	m.mu.Lock()
+	if m.err != nil {
+		m.mu.Unlock()
+		return nil
+	}
+	m.err = ErrClosed
+	conn := m.conn
+	close(m.closing)
+	m.mu.Unlock()
+
+	err := conn.Close()
+	<-m.stopped
+	return err
+}
+
+// current returns the live connection, waiting for a reconnect if it is
+// stale (the connection a failed call used).
+func (m *ManagedConnection) current(ctx context.Context, stale *Conn) (*Conn, error) {
+	for {
+		m.mu.Lock()
+		conn, changed, err := m.conn, m.changed, m.err
+		m.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		if conn != stale {
+			return conn, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// run pings the connection and reconnects when it drops, until Close.
+func (m *ManagedConnection) run() {
+	defer close(m.stopped)
+	ticker := time.NewTicker(m.config.KeepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		conn := m.Conn()
+		select {
+		case <-m.closing:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), m.config.PingTimeout)
+			err := conn.Ping(ctx)
+			cancel()
+			if err != nil {
+				conn.Close() // Dropped: the next iteration reconnects
+			}
+		case <-conn.Done():
+			if !m.reconnect() {
+				return
+			}
+		}
+	}
+}
+
+// reconnect dials until it succeeds or the connection is closed. It
+// reports whether a new connection is in place.
+func (m *ManagedConnection) reconnect() bool {
+	delay := m.config.ReconnectDelay
+	for {
+		// Each attempt is bounded and abandoned as soon as Close is called.
+		ctx, cancel := context.WithTimeout(context.Background(), m.config.MaxReconnectDelay)
+		go func() {
+			select {
+			case <-m.closing:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		conn, wsURL, err := m.connect(ctx)
+		cancel()
+
+		if err == nil {
+			if m.config.OnReconnect != nil {
+				m.config.OnReconnect(conn)
+			}
+			m.mu.Lock()
+			if m.err != nil {
+				m.mu.Unlock()
+				conn.Close()
+				return false
+			}
+			m.conn, m.wsURL = conn, wsURL
+			close(m.changed)
+			m.changed = make(chan struct{})
+			m.mu.Unlock()
+			return true
+		}
+
+		select {
+		case <-m.closing:
+			return false
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, m.config.MaxReconnectDelay)
+	}
+}
+
+// connect resolves the debug URL and dials it.
+func (m *ManagedConnection) connect(ctx context.Context) (*Conn, string, error) {
+	wsURL, err := m.resolve(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("cdp: resolve debug URL: %w", err)
+	}
+	conn, err := Dial(ctx, wsURL, m.opts...)
+	if err != nil {
+		return nil, "", err
+	}
+	return conn, wsURL, nil
+}
@@ -0,0 +1,139 @@
+package cdp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// dropConnection closes the fake browser's side of the current connection.
+func (b *fakeBrowser) dropConnection() {
+	b.mu.Lock()
+	ws := b.ws
+	b.mu.Unlock()
+	ws.conn.Close()
+}
+
+func TestPing(t *testing.T) {
+	b := newFakeBrowser(t)
+	conn := mustDial(t, b)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := conn.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	conn.Close()
+	if err := conn.Ping(ctx); err == nil {
+		t.Error("Ping() on a closed connection succeeded")
+	}
+}
+
+func TestManagedConnection(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("reconnects after a drop", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		b.handle("Browser.getVersion", func(msg message) (any, *Error) {
+			return map[string]string{"product": "Chrome/120"}, nil
+		})
+		var resolves, reconnects atomic.Int32
+		mc, err := DialManaged(ctx, func(context.Context) (string, error) {
+			resolves.Add(1)
+			return b.wsURL(), nil
+		}, ManagedConfig{
+			KeepaliveInterval: time.Hour,
+			ReconnectDelay:    10 * time.Millisecond,
+			OnReconnect:       func(*Conn) { reconnects.Add(1) },
+		})
+		if err != nil {
+			t.Fatalf("DialManaged() error = %v", err)
+		}
+		defer mc.Close()
+
+		first := mc.Conn()
+		b.dropConnection()
+		<-first.Done()
+
+		var version struct {
+			Product string `json:"product"`
+		}
+		if err := mc.Call(ctx, "Browser.getVersion", nil, &version); err != nil {
+			t.Fatalf("Call() after drop error = %v", err)
+		}
+		if version.Product != "Chrome/120" {
+			t.Errorf("Product = %q", version.Product)
+		}
+		if mc.Conn() == first {
+			t.Error("Conn() was not replaced")
+		}
+		if resolves.Load() != 2 || reconnects.Load() != 1 {
+			t.Errorf("resolves = %d, reconnects = %d; want 2, 1", resolves.Load(), reconnects.Load())
+		}
+		if mc.URL() != b.wsURL() {
+			t.Errorf("URL() = %q", mc.URL())
+		}
+	})
+
+	t.Run("keepalive pings", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		mc, err := DialManaged(ctx, func(context.Context) (string, error) {
+			return b.wsURL(), nil
+		}, ManagedConfig{KeepaliveInterval: 10 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("DialManaged() error = %v", err)
+		}
+		defer mc.Close()
+
+		conn := mc.Conn()
+		time.Sleep(50 * time.Millisecond)
+		if mc.Conn() != conn {
+			t.Error("a healthy connection was replaced")
+		}
+	})
+
+	t.Run("calls fail after Close", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		mc, err := DialManaged(ctx, func(context.Context) (string, error) {
+			return b.wsURL(), nil
+		}, ManagedConfig{})
+		if err != nil {
+			t.Fatalf("DialManaged() error = %v", err)
+		}
+		mc.Close()
+		if err := mc.Call(ctx, "Browser.getVersion", nil, nil); !errors.Is(err, ErrClosed) {
+			t.Errorf("err = %v, want ErrClosed", err)
+		}
+	})
+
+	t.Run("resolver error", func(t *testing.T) {
+		resolveErr := errors.New("browser not running")
+		_, err := DialManaged(ctx, func(context.Context) (string, error) {
+			return "", resolveErr
+		}, ManagedConfig{})
+		if !errors.Is(err, resolveErr) {
+			t.Errorf("err = %v, want %v", err, resolveErr)
+		}
+	})
+
+	t.Run("dialer follows the debug URL", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		mc, err := DialManaged(ctx, func(context.Context) (string, error) {
+			return b.wsURL(), nil
+		}, ManagedConfig{})
+		if err != nil {
+			t.Fatalf("DialManaged() error = %v", err)
+		}
+		defer mc.Close()
+
+		conn, err := mc.DialContext(ctx, "tcp", "stale-host:9222")
+		if err != nil {
+			t.Fatalf("DialContext() error = %v", err)
+		}
+		conn.Close()
+	})
+}
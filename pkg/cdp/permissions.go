@@ -0,0 +1,88 @@
+package cdp
+
+import (
+	"context"
+	"fmt"
+)
+
+// Permission is a browser permission, named as in the Permissions API.
+type Permission string
+
+// Permissions pages commonly prompt for.
+const (
+	PermissionNotifications  Permission = "notifications"
+	PermissionGeolocation    Permission = "geolocation"
+	PermissionClipboardRead  Permission = "clipboard-read"
+	PermissionClipboardWrite Permission = "clipboard-write"
+	PermissionCamera         Permission = "camera"
+	PermissionMicrophone     Permission = "microphone"
+)
+
+// PermissionSetting is the state a PermissionRule sets.
+type PermissionSetting string
+
+// Permission settings.
+const (
+	PermissionGranted PermissionSetting = "granted"
+	PermissionDenied  PermissionSetting = "denied"
+	PermissionPrompt  PermissionSetting = "prompt" // Ask again, as by default
+)
+
+// PermissionRule sets permissions for an origin.
+type PermissionRule struct {
+	Origin      string // e.g. "https://example.com"; empty for every origin
+	Permissions []Permission
+	Setting     PermissionSetting
+}
+
+// SetPermissions applies rules in order for the whole browser, so that
+// pages get or are refused the permissions without a prompt, e.g. to deny
+// notifications everywhere and grant the clipboard to one site. Rules last
+// until the browser closes or ResetPermissions is called.
+// Browser.setPermission
+//
+// Example:
+//
+//	err := session.SetPermissions(ctx,
+//	    cdp.PermissionRule{Permissions: []cdp.Permission{cdp.PermissionNotifications}, Setting: cdp.PermissionDenied},
+//	    cdp.PermissionRule{Origin: "https://example.com", Setting: cdp.PermissionGranted,
+//	        Permissions: []cdp.Permission{cdp.PermissionClipboardRead, cdp.PermissionClipboardWrite}},
+//	)
+func (s *Session) SetPermissions(ctx context.Context, rules ...PermissionRule) error {
+	for _, rule := range rules {
+		switch rule.Setting {
+		case PermissionGranted, PermissionDenied, PermissionPrompt:
+		default:
+			return fmt.Errorf("cdp: invalid permission setting %q", rule.Setting)
+		}
+		if len(rule.Permissions) == 0 {
+			return fmt.Errorf("cdp: permission rule for %q has no permissions", rule.Origin)
+		}
+	}
+	for _, rule := range rules {
+		for _, p := range rule.Permissions {
+			params := struct {
+				Permission struct {
+					Name Permission `json:"name"`
+				} `json:"permission"`
+				Setting PermissionSetting `json:"setting"`
+				Origin  string            `json:"origin,omitempty"`
+			}{Setting: rule.Setting, Origin: rule.Origin}
+			params.Permission.Name = p
+			if err := s.conn.Call(ctx, "Browser.setPermission", params, nil); err != nil {
+				return fmt.Errorf("cdp: failed to set permission %s to %s: %w", p, rule.Setting, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ResetPermissions undoes SetPermissions and every other permission
+// override, such as the grants of SetGeolocation and SetClipboard.
+// Browser.resetPermissions
+func (s *Session) ResetPermissions(ctx context.Context) error {
+	if err := s.conn.Call(ctx, "Browser.resetPermissions", nil, nil); err != nil {
+		return fmt.Errorf("cdp: failed to reset permissions: %w", err)
+	}
+	return nil
+}
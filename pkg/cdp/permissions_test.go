@@ -0,0 +1,51 @@
+package cdp
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSetPermissions(t *testing.T) {
+	b := newFakeBrowser(t)
+	handlePage(b)
+	ctx := context.Background()
+	session, err := mustDial(t, b).AttachToPage(ctx)
+	if err != nil {
+		t.Fatalf("AttachToPage failed: %v", err)
+	}
+
+	err = session.SetPermissions(ctx,
+		PermissionRule{Permissions: []Permission{PermissionNotifications}, Setting: PermissionDenied},
+		PermissionRule{Origin: "https://example.com", Permissions: []Permission{PermissionClipboardRead, PermissionClipboardWrite}, Setting: PermissionGranted},
+	)
+	if err != nil {
+		t.Fatalf("SetPermissions failed: %v", err)
+	}
+	calls := b.callsTo("Browser.setPermission")
+	if len(calls) != 3 {
+		t.Fatalf("setPermission calls = %+v", calls)
+	}
+	if got := string(calls[0].Params); got != `{"permission":{"name":"notifications"},"setting":"denied"}` || calls[0].SessionID != "" {
+		t.Errorf("first call = %s on session %q", got, calls[0].SessionID)
+	}
+	if got := string(calls[2].Params); !strings.Contains(got, `"clipboard-write"`) || !strings.Contains(got, `"origin":"https://example.com"`) {
+		t.Errorf("last call = %s", got)
+	}
+
+	for _, bad := range []PermissionRule{
+		{Permissions: []Permission{PermissionCamera}, Setting: "allow"},
+		{Setting: PermissionGranted},
+	} {
+		if err := session.SetPermissions(ctx, bad); err == nil {
+			t.Errorf("SetPermissions(%+v) succeeded", bad)
+		}
+	}
+	if n := len(b.callsTo("Browser.setPermission")); n != 3 {
+		t.Errorf("invalid rules sent %d calls", n-3)
+	}
+
+	if err := session.ResetPermissions(ctx); err != nil || len(b.callsTo("Browser.resetPermissions")) != 1 {
+		t.Errorf("ResetPermissions() = %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Directions of recorded messages.
+const (
+	DirectionSend    = "send" // Command sent to the browser
+	DirectionReceive = "recv" // Response or event received from the browser
+)
+
+// Record is one recorded DevTools message. A recording is a stream of
+// Records, one JSON object per line.
+type Record struct {
+	Time      time.Time       `json:"time"`
+	Direction string          `json:"dir"` // DirectionSend or DirectionReceive
+	Message   json.RawMessage `json:"msg"` // The message exactly as sent or received
+}
+
+// Recorder writes every DevTools message of the connections it is attached
+// to, for debugging automation failures or reviewing what a bot did inside
+// a profile. Attach it with WithRecorder; one Recorder may serve several
+// connections, e.g. all connections of a ManagedConnection.
+//
+// Recording never fails a call: the first write error stops the recording
+// and is reported by Err.
+//
+// A Recorder is safe for concurrent use.
+type Recorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+	now func() time.Time
+}
+
+// NewRecorder returns a Recorder that writes JSON lines to w. The caller
+// owns w and closes it after the connections are closed.
+//
+// Example:
+//
+//	f, _ := os.Create("session.jsonl")
+//	defer f.Close()
+//	conn, err := cdp.Dial(ctx, result.Ws, cdp.WithRecorder(cdp.NewRecorder(f)))
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w), now: time.Now}
+}
+
+// WithRecorder records all messages of the connection with r.
+func WithRecorder(r *Recorder) DialOption {
+	return func(cfg *dialConfig) {
+		cfg.recorder = r
+	}
+}
+
+// Err returns the write error that stopped the recording, if any.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// record writes one message. It is a no-op on a nil Recorder.
+func (r *Recorder) record(direction string, data []byte) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+	r.err = r.enc.Encode(Record{Time: r.now(), Direction: direction, Message: data})
+}
+
+// ReadRecording reads the Records a Recorder wrote.
+func ReadRecording(rd io.Reader) ([]Record, error) {
+	var records []Record
+	dec := json.NewDecoder(rd)
+	for {
+		var rec Record
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, fmt.Errorf("cdp: invalid recording after %d records: %w", len(records), err)
+		}
+		records = append(records, rec)
+	}
+}
+
+// Replay sends the recorded commands to conn again, in order and with
+// their original session IDs, waiting for each response. Received
+// messages are skipped. It stops at the first failed command.
+//
+// Commands that refer to IDs issued by the browser (node, target or
+// session IDs) only replay against a browser in the same state.
+func Replay(ctx context.Context, conn *Conn, records []Record) error {
+	for i, rec := range records {
+		if rec.Direction != DirectionSend {
+			continue
+		}
+		var msg message
+		if err := json.Unmarshal(rec.Message, &msg); err != nil {
+			return fmt.Errorf("cdp: invalid recorded message %d: %w", i, err)
+		}
+		if msg.Method == "" {
+			continue
+		}
+		var params any
+		if len(msg.Params) > 0 {
+			params = msg.Params
+		}
+		if err := conn.call(ctx, msg.SessionID, msg.Method, params, nil); err != nil {
+			return fmt.Errorf("cdp: replay record %d: %w", i, err)
+		}
+	}
+	return nil
+}
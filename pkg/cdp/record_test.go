@@ -0,0 +1,115 @@
+package cdp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	b := newFakeBrowser(t)
+	b.handle("Browser.getVersion", func(msg message) (any, *Error) {
+		return map[string]string{"product": "Chrome/130"}, nil
+	})
+
+	var buf bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := Dial(ctx, b.wsURL(), WithRecorder(NewRecorder(&buf)))
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	if err := conn.Call(ctx, "Browser.getVersion", nil, nil); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if err := conn.call(ctx, "session-1", "Page.navigate", map[string]string{"url": "https://example.com/"}, nil); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	conn.Close()
+	<-conn.Done()
+
+	records, err := ReadRecording(&buf)
+	if err != nil {
+		t.Fatalf("ReadRecording failed: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 4", len(records))
+	}
+	wantDirs := []string{DirectionSend, DirectionReceive, DirectionSend, DirectionReceive}
+	for i, rec := range records {
+		if rec.Direction != wantDirs[i] || rec.Time.IsZero() {
+			t.Errorf("record %d = %s at %v, want %s", i, rec.Direction, rec.Time, wantDirs[i])
+		}
+	}
+	if !strings.Contains(string(records[1].Message), "Chrome/130") {
+		t.Errorf("response not recorded: %s", records[1].Message)
+	}
+
+	t.Run("Replay", func(t *testing.T) {
+		b2 := newFakeBrowser(t)
+		conn2 := mustDial(t, b2)
+		defer conn2.Close()
+		if err := Replay(ctx, conn2, records); err != nil {
+			t.Fatalf("Replay failed: %v", err)
+		}
+		navs := b2.callsTo("Page.navigate")
+		if len(b2.callsTo("Browser.getVersion")) != 1 || len(navs) != 1 {
+			t.Fatalf("replayed calls: %+v", b2.calls)
+		}
+		var params map[string]string
+		json.Unmarshal(navs[0].Params, &params)
+		if navs[0].SessionID != "session-1" || params["url"] != "https://example.com/" {
+			t.Errorf("replayed %+v", navs[0])
+		}
+	})
+
+	t.Run("Replay stops at the first failure", func(t *testing.T) {
+		b3 := newFakeBrowser(t)
+		b3.handle("Browser.getVersion", func(msg message) (any, *Error) {
+			return nil, &Error{Code: -32000, Message: "boom"}
+		})
+		conn3 := mustDial(t, b3)
+		defer conn3.Close()
+		err := Replay(ctx, conn3, records)
+		var cdpErr *Error
+		if !errors.As(err, &cdpErr) {
+			t.Fatalf("err = %v, want *Error", err)
+		}
+		if len(b3.callsTo("Page.navigate")) != 0 {
+			t.Error("Replay continued after a failure")
+		}
+	})
+}
+
+func TestReadRecordingInvalid(t *testing.T) {
+	records, err := ReadRecording(strings.NewReader(`{"dir":"send","msg":{}}` + "\n" + `{oops`))
+	if err == nil || len(records) != 1 {
+		t.Errorf("ReadRecording() = %d records, %v; want 1 record and an error", len(records), err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestRecorderWriteError(t *testing.T) {
+	b := newFakeBrowser(t)
+	r := NewRecorder(failingWriter{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := Dial(ctx, b.wsURL(), WithRecorder(r))
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.Call(ctx, "Browser.getVersion", nil, nil); err != nil {
+		t.Fatalf("Call failed despite recording error: %v", err)
+	}
+	if r.Err() == nil {
+		t.Error("Err() = nil, want the write error")
+	}
+}
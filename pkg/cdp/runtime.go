@@ -0,0 +1,84 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// remoteObject mirrors Runtime.RemoteObject.
+type remoteObject struct {
+	Type        string          `json:"type"`
+	Subtype     string          `json:"subtype,omitempty"`
+	ObjectID    string          `json:"objectId,omitempty"`
+	Value       json.RawMessage `json:"value,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+// exceptionDetails mirrors the parts of Runtime.ExceptionDetails we report.
+type exceptionDetails struct {
+	Text      string        `json:"text"`
+	Exception *remoteObject `json:"exception,omitempty"`
+}
+
+func (e *exceptionDetails) message() string {
+	if e.Exception != nil && e.Exception.Description != "" {
+		return e.Exception.Description
+	}
+	return e.Text
+}
+
+// Evaluate runs a JavaScript expression in the page and decodes its
+// JSON-serializable result into out. Promises are awaited. out may be nil.
+// Runtime.evaluate
+func (s *Session) Evaluate(ctx context.Context, expression string, out any) error {
+	obj, err := s.evaluate(ctx, expression, true)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(obj.Value) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(obj.Value, out); err != nil {
+		return fmt.Errorf("cdp: failed to parse evaluation result: %w", err)
+	}
+	return nil
+}
+
+// evaluate runs an expression and returns the resulting remote object.
+// With byValue false the caller owns the returned object and must release it.
+func (s *Session) evaluate(ctx context.Context, expression string, byValue bool) (*remoteObject, error) {
+	params := struct {
+		Expression    string `json:"expression"`
+		ReturnByValue bool   `json:"returnByValue"`
+		AwaitPromise  bool   `json:"awaitPromise"`
+	}{
+		Expression:    expression,
+		ReturnByValue: byValue,
+		AwaitPromise:  true,
+	}
+
+	var result struct {
+		Result           remoteObject      `json:"result"`
+		ExceptionDetails *exceptionDetails `json:"exceptionDetails,omitempty"`
+	}
+	if err := s.Call(ctx, "Runtime.evaluate", params, &result); err != nil {
+		return nil, err
+	}
+	if result.ExceptionDetails != nil {
+		return nil, fmt.Errorf("cdp: JavaScript exception: %s", result.ExceptionDetails.message())
+	}
+	return &result.Result, nil
+}
+
+// releaseObject frees a remote object handle. Errors are ignored since the
+// object is garbage collected with its execution context anyway.
+func (s *Session) releaseObject(ctx context.Context, objectID string) {
+	if objectID == "" {
+		return
+	}
+	params := struct {
+		ObjectID string `json:"objectId"`
+	}{ObjectID: objectID}
+	s.Call(ctx, "Runtime.releaseObject", params, nil)
+}
@@ -0,0 +1,219 @@
+package cdp
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScreencastOptions configures Page.startScreencast. Zero values use the
+// browser's defaults.
+type ScreencastOptions struct {
+	Format        string // "jpeg" (default) or "png"
+	Quality       int    // JPEG quality, 0-100
+	MaxWidth      int    // Maximum frame width in pixels
+	MaxHeight     int    // Maximum frame height in pixels
+	EveryNthFrame int    // Capture every n-th frame only
+}
+
+// Frame is one screencast frame.
+type Frame struct {
+	Data []byte    // Encoded image in the screencast format
+	Time time.Time // When the browser captured the frame
+}
+
+// Screencast records the frames a page renders, for human review of bot
+// sessions. Start one with Session.StartScreencast and end it with Stop.
+//
+// Example:
+//
+//	sc, err := session.StartScreencast(ctx, nil)
+//	// ... run the automation ...
+//	frames, err := sc.Stop(ctx)
+//	err = sc.EncodeVideo(ctx, "session.webm") // Needs ffmpeg
+type Screencast struct {
+	session *Session
+	sub     *Subscription
+	ext     string
+	stop    context.CancelFunc
+	done    chan struct{}
+
+	mu     sync.Mutex
+	frames []Frame
+}
+
+// StartScreencast starts capturing frames of the session's page. Frames
+// are acknowledged as they arrive, so the browser keeps sending them.
+// Page.startScreencast
+func (s *Session) StartScreencast(ctx context.Context, opts *ScreencastOptions) (*Screencast, error) {
+	if opts == nil {
+		opts = &ScreencastOptions{}
+	}
+	format := opts.Format
+	if format == "" {
+		format = "jpeg"
+	}
+	params := struct {
+		Format        string `json:"format"`
+		Quality       int    `json:"quality,omitempty"`
+		MaxWidth      int    `json:"maxWidth,omitempty"`
+		MaxHeight     int    `json:"maxHeight,omitempty"`
+		EveryNthFrame int    `json:"everyNthFrame,omitempty"`
+	}{format, opts.Quality, opts.MaxWidth, opts.MaxHeight, opts.EveryNthFrame}
+
+	// Subscribe first so no frame sent right after the call is missed.
+	sub := s.conn.Subscribe("Page.screencastFrame")
+	if err := s.Call(ctx, "Page.startScreencast", params, nil); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	loopCtx, stop := context.WithCancel(context.Background())
+	sc := &Screencast{
+		session: s,
+		sub:     sub,
+		ext:     strings.Replace(format, "jpeg", "jpg", 1),
+		stop:    stop,
+		done:    make(chan struct{}),
+	}
+	go sc.collect(loopCtx)
+	return sc, nil
+}
+
+// Stop stops capturing and returns the frames.
+// Page.stopScreencast
+func (sc *Screencast) Stop(ctx context.Context) ([]Frame, error) {
+	err := sc.session.Call(ctx, "Page.stopScreencast", nil, nil)
+	sc.stop()
+	<-sc.done
+	sc.sub.Close()
+	return sc.Frames(), err
+}
+
+// Frames returns the frames captured so far.
+func (sc *Screencast) Frames() []Frame {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return append([]Frame(nil), sc.frames...)
+}
+
+// collect stores and acknowledges frames of the session until ctx is done.
+func (sc *Screencast) collect(ctx context.Context) {
+	defer close(sc.done)
+	for {
+		ev, err := sc.sub.Next(ctx)
+		if err != nil {
+			return
+		}
+		if ev.SessionID != sc.session.id {
+			continue
+		}
+		var p struct {
+			Data      string `json:"data"`
+			SessionID int    `json:"sessionId"`
+			Metadata  struct {
+				Timestamp float64 `json:"timestamp"`
+			} `json:"metadata"`
+		}
+		if ev.Unmarshal(&p) != nil {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(p.Data)
+		if err != nil {
+			continue
+		}
+		frame := Frame{Data: data, Time: time.Now()}
+		if p.Metadata.Timestamp > 0 {
+			frame.Time = time.UnixMilli(int64(p.Metadata.Timestamp * 1000))
+		}
+		sc.mu.Lock()
+		sc.frames = append(sc.frames, frame)
+		sc.mu.Unlock()
+
+		ack := struct {
+			SessionID int `json:"sessionId"`
+		}{p.SessionID}
+		sc.session.Call(ctx, "Page.screencastFrameAck", ack, nil)
+	}
+}
+
+// WriteFrames writes the frames to dir as frame-00001.jpg, ... and a
+// frames.ffconcat file that replays them with their original timing in
+// ffmpeg. It returns the path of the ffconcat file.
+func (sc *Screencast) WriteFrames(dir string) (string, error) {
+	frames := sc.Frames()
+	if len(frames) == 0 {
+		return "", errors.New("cdp: screencast has no frames")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	var list strings.Builder
+	list.WriteString("ffconcat version 1.0\n")
+	var name string
+	for i, f := range frames {
+		name = fmt.Sprintf("frame-%05d.%s", i+1, sc.ext)
+		if err := os.WriteFile(filepath.Join(dir, name), f.Data, 0o644); err != nil {
+			return "", err
+		}
+		duration := 100 * time.Millisecond // Last frame
+		if i+1 < len(frames) {
+			duration = max(frames[i+1].Time.Sub(f.Time), time.Millisecond)
+		}
+		fmt.Fprintf(&list, "file '%s'\nduration %.3f\n", name, duration.Seconds())
+	}
+	// ffmpeg ignores the duration of the last entry unless it is repeated.
+	fmt.Fprintf(&list, "file '%s'\n", name)
+
+	path := filepath.Join(dir, "frames.ffconcat")
+	if err := os.WriteFile(path, []byte(list.String()), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// EncodeVideo encodes the frames into a video file with ffmpeg, which must
+// be on PATH. The container follows the extension of output: ".webm"
+// (VP9) or ".mp4" (H.264).
+func (sc *Screencast) EncodeVideo(ctx context.Context, output string) error {
+	var codec []string
+	switch strings.ToLower(filepath.Ext(output)) {
+	case ".webm":
+		codec = []string{"-c:v", "libvpx-vp9", "-b:v", "0", "-crf", "40"}
+	case ".mp4":
+		codec = []string{"-c:v", "libx264", "-pix_fmt", "yuv420p"}
+	default:
+		return fmt.Errorf("cdp: unsupported video format %q (use .webm or .mp4)", filepath.Ext(output))
+	}
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("cdp: encode video: %w", err)
+	}
+
+	dir, err := os.MkdirTemp("", "screencast-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	list, err := sc.WriteFrames(dir)
+	if err != nil {
+		return err
+	}
+
+	// Even dimensions are required by the encoders' pixel formats.
+	args := append([]string{"-y", "-loglevel", "error", "-f", "concat", "-safe", "0", "-i", list,
+		"-vf", "pad=ceil(iw/2)*2:ceil(ih/2)*2", "-vsync", "vfr"}, codec...)
+	args = append(args, output)
+	if out, err := exec.CommandContext(ctx, ffmpeg, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("cdp: ffmpeg failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
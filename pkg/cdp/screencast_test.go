@@ -0,0 +1,119 @@
+package cdp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// emitFrame sends a Page.screencastFrame event for a session.
+func emitFrame(t *testing.T, b *fakeBrowser, sessionID string, n int, ts float64) {
+	t.Helper()
+	params, _ := json.Marshal(map[string]any{
+		"data":      base64.StdEncoding.EncodeToString([]byte{byte(n)}),
+		"sessionId": n,
+		"metadata":  map[string]any{"timestamp": ts},
+	})
+	data, _ := json.Marshal(message{Method: "Page.screencastFrame", SessionID: sessionID, Params: params})
+	b.mu.Lock()
+	ws := b.ws
+	b.mu.Unlock()
+	if err := ws.writeMessage(data); err != nil {
+		t.Fatalf("emit frame: %v", err)
+	}
+}
+
+func TestScreencast(t *testing.T) {
+	b := newFakeBrowser(t)
+	handlePage(b)
+	conn := mustDial(t, b)
+	defer conn.Close()
+	ctx := context.Background()
+
+	session, err := conn.AttachToPage(ctx)
+	if err != nil {
+		t.Fatalf("AttachToPage failed: %v", err)
+	}
+	sc, err := session.StartScreencast(ctx, &ScreencastOptions{Quality: 60})
+	if err != nil {
+		t.Fatalf("StartScreencast failed: %v", err)
+	}
+
+	emitFrame(t, b, "S1", 1, 1700000000.0)
+	emitFrame(t, b, "OTHER", 9, 1700000000.1)
+	emitFrame(t, b, "S1", 2, 1700000000.5)
+	emitFrame(t, b, "S1", 3, 1700000001.0)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(b.callsTo("Page.screencastFrameAck")) < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	frames, err := sc.Stop(ctx)
+	if err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if len(frames) != 3 || frames[0].Data[0] != 1 || frames[2].Data[0] != 3 {
+		t.Fatalf("frames = %+v", frames)
+	}
+	if got := frames[1].Time.Sub(frames[0].Time); got != 500*time.Millisecond {
+		t.Errorf("frame interval = %v, want 500ms", got)
+	}
+
+	start := b.callsTo("Page.startScreencast")
+	if len(start) != 1 || start[0].SessionID != "S1" || !strings.Contains(string(start[0].Params), `"quality":60`) {
+		t.Errorf("startScreencast calls = %+v", start)
+	}
+	if len(b.callsTo("Page.stopScreencast")) != 1 {
+		t.Error("stopScreencast was not called")
+	}
+
+	t.Run("WriteFrames", func(t *testing.T) {
+		dir := t.TempDir()
+		list, err := sc.WriteFrames(dir)
+		if err != nil {
+			t.Fatalf("WriteFrames failed: %v", err)
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, "frame-00003.jpg")); err != nil || data[0] != 3 {
+			t.Errorf("frame-00003.jpg = %v, %v", data, err)
+		}
+		data, _ := os.ReadFile(list)
+		want := "ffconcat version 1.0\n" +
+			"file 'frame-00001.jpg'\nduration 0.500\n" +
+			"file 'frame-00002.jpg'\nduration 0.500\n" +
+			"file 'frame-00003.jpg'\nduration 0.100\n" +
+			"file 'frame-00003.jpg'\n"
+		if string(data) != want {
+			t.Errorf("frames.ffconcat =\n%s\nwant\n%s", data, want)
+		}
+	})
+
+	t.Run("EncodeVideo rejects unknown formats", func(t *testing.T) {
+		if err := sc.EncodeVideo(ctx, filepath.Join(t.TempDir(), "out.gif")); err == nil {
+			t.Error("EncodeVideo(.gif) should fail")
+		}
+	})
+}
+
+func TestScreencastEncodeVideo(t *testing.T) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		t.Skip("ffmpeg not installed")
+	}
+	// A 2x2 white PNG, repeated as three frames.
+	png, _ := base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAIAAAACCAIAAAD91JpzAAAAEklEQVR4nGP8//8/AxJgYkAFAC75AwGwGEUDAAAAAElFTkSuQmCC")
+	now := time.Now()
+	sc := &Screencast{ext: "png", frames: []Frame{{png, now}, {png, now.Add(time.Second)}, {png, now.Add(2 * time.Second)}}}
+
+	out := filepath.Join(t.TempDir(), "session.mp4")
+	if err := sc.EncodeVideo(context.Background(), out); err != nil {
+		t.Fatalf("EncodeVideo failed: %v", err)
+	}
+	if info, err := os.Stat(out); err != nil || info.Size() == 0 {
+		t.Errorf("video not written: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package cdp
+
+import (
+	"context"
+	"fmt"
+)
+
+// TargetInfo describes a DevTools target such as a page or service worker.
+type TargetInfo struct {
+	TargetID string `json:"targetId"`
+	Type     string `json:"type"` // "page", "iframe", "service_worker", "browser", ...
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Attached bool   `json:"attached"`
+}
+
+// Session is a flattened DevTools session attached to a single target.
+// Page-level domains such as DOM, Runtime and Page must be used through a
+// Session, since OpenResult.Ws points at the browser target.
+type Session struct {
+	conn *Conn
+	id   string
+}
+
+// Targets lists the browser's current targets.
+// Target.getTargets
+func (c *Conn) Targets(ctx context.Context) ([]TargetInfo, error) {
+	var result struct {
+		TargetInfos []TargetInfo `json:"targetInfos"`
+	}
+	if err := c.Call(ctx, "Target.getTargets", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.TargetInfos, nil
+}
+
+// Attach attaches to a target and returns a session for it.
+// Target.attachToTarget
+func (c *Conn) Attach(ctx context.Context, targetID string) (*Session, error) {
+	params := struct {
+		TargetID string `json:"targetId"`
+		Flatten  bool   `json:"flatten"`
+	}{TargetID: targetID, Flatten: true}
+
+	var result struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := c.Call(ctx, "Target.attachToTarget", params, &result); err != nil {
+		return nil, err
+	}
+	if result.SessionID == "" {
+		return nil, fmt.Errorf("cdp: attach to target %s returned no session", targetID)
+	}
+	return &Session{conn: c, id: result.SessionID}, nil
+}
+
+// AttachToPage attaches to the first open page, creating a blank page if
+// the browser has none.
+func (c *Conn) AttachToPage(ctx context.Context) (*Session, error) {
+	targets, err := c.Targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range targets {
+		if t.Type == "page" {
+			return c.Attach(ctx, t.TargetID)
+		}
+	}
+
+	var created struct {
+		TargetID string `json:"targetId"`
+	}
+	params := struct {
+		URL string `json:"url"`
+	}{URL: "about:blank"}
+	if err := c.Call(ctx, "Target.createTarget", params, &created); err != nil {
+		return nil, err
+	}
+	return c.Attach(ctx, created.TargetID)
+}
+
+// ID returns the session identifier.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Conn returns the connection the session belongs to.
+func (s *Session) Conn() *Conn {
+	return s.conn
+}
+
+// Call invokes a method within the session and decodes the result into
+// result. params and result may be nil.
+func (s *Session) Call(ctx context.Context, method string, params, result any) error {
+	return s.conn.call(ctx, s.id, method, params, result)
+}
+
+// Detach detaches the session from its target. The target keeps running.
+// Target.detachFromTarget
+func (s *Session) Detach(ctx context.Context) error {
+	params := struct {
+		SessionID string `json:"sessionId"`
+	}{SessionID: s.id}
+	return s.conn.Call(ctx, "Target.detachFromTarget", params, nil)
+}
@@ -0,0 +1,123 @@
+package cdp
+
+import (
+	"context"
+	"testing"
+)
+
+// handlePage registers target handlers that expose a single page "P1"
+// attached as session "S1".
+func handlePage(b *fakeBrowser) {
+	b.handle("Target.getTargets", func(msg message) (any, *Error) {
+		return map[string]any{"targetInfos": []TargetInfo{
+			{TargetID: "SW", Type: "service_worker"},
+			{TargetID: "P1", Type: "page", URL: "about:blank"},
+		}}, nil
+	})
+	b.handle("Target.attachToTarget", func(msg message) (any, *Error) {
+		return map[string]string{"sessionId": "S1"}, nil
+	})
+}
+
+func TestAttachToPage(t *testing.T) {
+	t.Run("attaches to existing page", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		handlePage(b)
+		conn := mustDial(t, b)
+
+		s, err := conn.AttachToPage(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if s.ID() != "S1" {
+			t.Errorf("ID = %q, want S1", s.ID())
+		}
+
+		calls := b.callsTo("Target.attachToTarget")
+		if len(calls) != 1 || !containsJSON(calls[0].Params, `"targetId":"P1"`) || !containsJSON(calls[0].Params, `"flatten":true`) {
+			t.Errorf("unexpected attach params: %+v", calls)
+		}
+		if len(b.callsTo("Target.createTarget")) != 0 {
+			t.Error("should not create a page when one exists")
+		}
+	})
+
+	t.Run("creates page when none exist", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		b.handle("Target.getTargets", func(msg message) (any, *Error) {
+			return map[string]any{"targetInfos": []TargetInfo{}}, nil
+		})
+		b.handle("Target.createTarget", func(msg message) (any, *Error) {
+			return map[string]string{"targetId": "NEW"}, nil
+		})
+		b.handle("Target.attachToTarget", func(msg message) (any, *Error) {
+			return map[string]string{"sessionId": "S2"}, nil
+		})
+		conn := mustDial(t, b)
+
+		s, err := conn.AttachToPage(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if s.ID() != "S2" {
+			t.Errorf("ID = %q, want S2", s.ID())
+		}
+	})
+
+	t.Run("session calls carry session ID", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		handlePage(b)
+		conn := mustDial(t, b)
+
+		s, err := conn.AttachToPage(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := s.Call(context.Background(), "Page.enable", nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		calls := b.callsTo("Page.enable")
+		if len(calls) != 1 || calls[0].SessionID != "S1" {
+			t.Errorf("expected Page.enable with session S1, got %+v", calls)
+		}
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Run("decodes value", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		handlePage(b)
+		b.handle("Runtime.evaluate", func(msg message) (any, *Error) {
+			return map[string]any{"result": map[string]any{"type": "number", "value": 42}}, nil
+		})
+		conn := mustDial(t, b)
+		s, _ := conn.AttachToPage(context.Background())
+
+		var n int
+		if err := s.Evaluate(context.Background(), "6*7", &n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != 42 {
+			t.Errorf("n = %d, want 42", n)
+		}
+	})
+
+	t.Run("reports exceptions", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		handlePage(b)
+		b.handle("Runtime.evaluate", func(msg message) (any, *Error) {
+			return map[string]any{
+				"result":           map[string]any{"type": "object"},
+				"exceptionDetails": map[string]any{"text": "Uncaught", "exception": map[string]any{"description": "ReferenceError: x is not defined"}},
+			}, nil
+		})
+		conn := mustDial(t, b)
+		s, _ := conn.AttachToPage(context.Background())
+
+		err := s.Evaluate(context.Background(), "x", nil)
+		if err == nil || !containsJSON([]byte(err.Error()), "ReferenceError") {
+			t.Errorf("expected ReferenceError, got %v", err)
+		}
+	})
+}
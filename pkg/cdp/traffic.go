@@ -0,0 +1,129 @@
+package cdp
+
+import (
+	"context"
+	"sync"
+)
+
+// TrafficStats counts the network traffic of a page.
+type TrafficStats struct {
+	Requests      int64 // Requests sent, including WebSocket handshakes
+	BytesSent     int64 // Request lines, headers, bodies and WebSocket frames
+	BytesReceived int64 // Encoded response sizes and WebSocket frames
+}
+
+// TrafficMeter counts the traffic of a session's page until stopped or the
+// connection closes. Start one with Session.MeterTraffic.
+type TrafficMeter struct {
+	session *Session
+	sub     *Subscription
+	stop    context.CancelFunc
+	done    chan struct{}
+
+	mu    sync.Mutex
+	stats TrafficStats
+}
+
+// MeterTraffic starts counting the bytes the session's page sends and
+// receives, e.g. to attribute proxy bandwidth to profiles. Received bytes
+// are the encoded (compressed) sizes reported by the browser, headers
+// included, so responses served from the browser cache add nothing. Sent
+// bytes are estimated from the requests, as the protocol does not report
+// them.
+//
+// Example:
+//
+//	meter, err := session.MeterTraffic(ctx)
+//	// ... run the automation ...
+//	stats := meter.Stop()
+//
+// Network.enable
+func (s *Session) MeterTraffic(ctx context.Context) (*TrafficMeter, error) {
+	// Subscribe first so no event sent right after the call is missed.
+	sub := s.conn.Subscribe("Network.requestWillBeSent", "Network.loadingFinished",
+		"Network.webSocketFrameSent", "Network.webSocketFrameReceived")
+	if err := s.Call(ctx, "Network.enable", nil, nil); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	loopCtx, stop := context.WithCancel(context.Background())
+	m := &TrafficMeter{
+		session: s,
+		sub:     sub,
+		stop:    stop,
+		done:    make(chan struct{}),
+	}
+	go m.count(loopCtx)
+	return m, nil
+}
+
+// Stats returns the traffic counted so far.
+func (m *TrafficMeter) Stats() TrafficStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
+
+// Stop stops counting and returns the traffic counted. The Network domain
+// stays enabled, as other users of the session may rely on it.
+func (m *TrafficMeter) Stop() TrafficStats {
+	m.stop()
+	<-m.done
+	m.sub.Close()
+	return m.Stats()
+}
+
+// count adds up network events of the session until ctx is done.
+func (m *TrafficMeter) count(ctx context.Context) {
+	defer close(m.done)
+	for {
+		ev, err := m.sub.Next(ctx)
+		if err != nil {
+			return
+		}
+		if ev.SessionID != m.session.id {
+			continue
+		}
+		var p struct {
+			Request *struct {
+				URL      string            `json:"url"`
+				Method   string            `json:"method"`
+				Headers  map[string]string `json:"headers"`
+				PostData string            `json:"postData"`
+			} `json:"request"`
+			EncodedDataLength float64 `json:"encodedDataLength"`
+			Response          *struct {
+				PayloadData string `json:"payloadData"`
+			} `json:"response"`
+		}
+		if ev.Unmarshal(&p) != nil {
+			continue
+		}
+
+		m.mu.Lock()
+		switch ev.Method {
+		case "Network.requestWillBeSent":
+			if r := p.Request; r != nil {
+				m.stats.Requests++
+				// "METHOD URL HTTP/1.1\r\n", "Name: value\r\n" per header and "\r\n"
+				n := len(r.Method) + len(r.URL) + 12
+				for k, v := range r.Headers {
+					n += len(k) + len(v) + 4
+				}
+				m.stats.BytesSent += int64(n + 2 + len(r.PostData))
+			}
+		case "Network.loadingFinished":
+			m.stats.BytesReceived += int64(p.EncodedDataLength)
+		case "Network.webSocketFrameSent":
+			if p.Response != nil {
+				m.stats.BytesSent += int64(len(p.Response.PayloadData))
+			}
+		case "Network.webSocketFrameReceived":
+			if p.Response != nil {
+				m.stats.BytesReceived += int64(len(p.Response.PayloadData))
+			}
+		}
+		m.mu.Unlock()
+	}
+}
@@ -0,0 +1,59 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// emitEvent sends an event for a session.
+func emitEvent(t *testing.T, b *fakeBrowser, sessionID, method string, params any) {
+	t.Helper()
+	raw, _ := json.Marshal(params)
+	data, _ := json.Marshal(message{Method: method, SessionID: sessionID, Params: raw})
+	b.mu.Lock()
+	ws := b.ws
+	b.mu.Unlock()
+	if err := ws.writeMessage(data); err != nil {
+		t.Fatalf("emit %s: %v", method, err)
+	}
+}
+
+func TestMeterTraffic(t *testing.T) {
+	b := newFakeBrowser(t)
+	handlePage(b)
+	conn := mustDial(t, b)
+	defer conn.Close()
+	ctx := context.Background()
+
+	session, err := conn.AttachToPage(ctx)
+	if err != nil {
+		t.Fatalf("AttachToPage failed: %v", err)
+	}
+	meter, err := session.MeterTraffic(ctx)
+	if err != nil {
+		t.Fatalf("MeterTraffic failed: %v", err)
+	}
+	if enable := b.callsTo("Network.enable"); len(enable) != 1 || enable[0].SessionID != "S1" {
+		t.Fatalf("Network.enable calls = %+v", enable)
+	}
+
+	// "GET http://a/ HTTP/1.1\r\n" (24) + "Host: a\r\n" (9) + "\r\n" (2) + body (3)
+	emitEvent(t, b, "S1", "Network.requestWillBeSent", map[string]any{
+		"request": map[string]any{"url": "http://a/", "method": "GET", "headers": map[string]string{"Host": "a"}, "postData": "x=1"},
+	})
+	emitEvent(t, b, "S1", "Network.loadingFinished", map[string]any{"encodedDataLength": 1500.0})
+	emitEvent(t, b, "OTHER", "Network.loadingFinished", map[string]any{"encodedDataLength": 99999.0})
+	emitEvent(t, b, "S1", "Network.webSocketFrameSent", map[string]any{"response": map[string]any{"payloadData": "ping"}})
+	emitEvent(t, b, "S1", "Network.webSocketFrameReceived", map[string]any{"response": map[string]any{"payloadData": "pong!"}})
+
+	want := TrafficStats{Requests: 1, BytesSent: 38 + 4, BytesReceived: 1505}
+	deadline := time.Now().Add(5 * time.Second)
+	for meter.Stats() != want && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := meter.Stop(); got != want {
+		t.Errorf("Stop() = %+v, want %+v", got, want)
+	}
+}
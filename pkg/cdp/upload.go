@@ -0,0 +1,130 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ShadowPierce separates selector segments that cross a shadow root
+// boundary, e.g. "my-uploader >>> input[type=file]". Each segment is
+// matched inside the shadow root of the element found by the previous one.
+const ShadowPierce = ">>>"
+
+// queryElementJS finds an element by selector segments.
+// Each segment is searched in the current root first and then in every
+// open shadow root below it, so a plain selector also finds elements
+// nested inside web components.
+const queryElementJS = `(function(parts) {
+	function deep(root, sel) {
+		const el = root.querySelector(sel);
+		if (el) return el;
+		for (const node of root.querySelectorAll('*')) {
+			if (node.shadowRoot) {
+				const found = deep(node.shadowRoot, sel);
+				if (found) return found;
+			}
+		}
+		return null;
+	}
+	let root = document;
+	let el = null;
+	for (let i = 0; i < parts.length; i++) {
+		el = deep(root, parts[i]);
+		if (!el) return null;
+		if (i < parts.length - 1) {
+			root = el.shadowRoot;
+			if (!root) return null;
+		}
+	}
+	return el;
+})(%s)`
+
+// fileInputCheckJS validates the element found by queryElementJS.
+const fileInputCheckJS = `(function(el, count) {
+	if (!el) return null;
+	if (el.tagName !== 'INPUT' || el.type !== 'file') throw new Error('element is not a file input');
+	if (count > 1 && !el.multiple) throw new Error('file input does not accept multiple files');
+	return el;
+})(%s, %d)`
+
+// SetFileInput sets the files of an <input type="file"> element.
+//
+// paths are absolute paths on the machine running the browser. Passing
+// more than one path requires the input to have the "multiple" attribute.
+// The selector may cross shadow roots using ShadowPierce; plain selectors
+// also search open shadow roots when nothing matches in the document.
+// DOM.setFileInputFiles
+func (s *Session) SetFileInput(ctx context.Context, selector string, paths []string) error {
+	if strings.TrimSpace(selector) == "" {
+		return fmt.Errorf("cdp: selector is required")
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("cdp: at least one file path is required")
+	}
+
+	obj, err := s.queryFileInput(ctx, selector, len(paths))
+	if err != nil {
+		return err
+	}
+	defer s.releaseObject(ctx, obj.ObjectID)
+
+	params := struct {
+		Files    []string `json:"files"`
+		ObjectID string   `json:"objectId"`
+	}{Files: paths, ObjectID: obj.ObjectID}
+	return s.Call(ctx, "DOM.setFileInputFiles", params, nil)
+}
+
+// SetFileInput connects to a browser WebSocket URL (typically OpenResult.Ws),
+// attaches to the first page and sets the files of a file input.
+// See Session.SetFileInput for selector and path semantics.
+//
+// Example:
+//
+//	err := cdp.SetFileInput(ctx, result.Ws, "input[type=file]", []string{`C:\files\avatar.png`})
+func SetFileInput(ctx context.Context, wsURL, selector string, paths []string) error {
+	conn, err := Dial(ctx, wsURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	session, err := conn.AttachToPage(ctx)
+	if err != nil {
+		return err
+	}
+	return session.SetFileInput(ctx, selector, paths)
+}
+
+// queryFileInput resolves a selector to a validated file input handle.
+func (s *Session) queryFileInput(ctx context.Context, selector string, count int) (*remoteObject, error) {
+	parts := splitSelector(selector)
+	encoded, err := json.Marshal(parts)
+	if err != nil {
+		return nil, fmt.Errorf("cdp: failed to encode selector: %w", err)
+	}
+
+	expr := fmt.Sprintf(fileInputCheckJS, fmt.Sprintf(queryElementJS, encoded), count)
+	obj, err := s.evaluate(ctx, expr, false)
+	if err != nil {
+		return nil, fmt.Errorf("cdp: query %q: %w", selector, err)
+	}
+	if obj.ObjectID == "" || obj.Subtype == "null" {
+		return nil, fmt.Errorf("%w: no element matches %q", ErrNotFound, selector)
+	}
+	return obj, nil
+}
+
+// splitSelector splits a selector on ShadowPierce and trims each segment.
+func splitSelector(selector string) []string {
+	raw := strings.Split(selector, ShadowPierce)
+	parts := make([]string, 0, len(raw))
+	for _, p := range raw {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,130 @@
+package cdp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// containsJSON reports whether raw contains substr.
+func containsJSON(raw []byte, substr string) bool {
+	return bytes.Contains(raw, []byte(substr))
+}
+
+// handleFileInput registers a Runtime.evaluate handler that returns the
+// given remote object and records the evaluated expression.
+func handleFileInput(b *fakeBrowser, obj map[string]any, expr *string) {
+	b.handle("Runtime.evaluate", func(msg message) (any, *Error) {
+		var p struct {
+			Expression string `json:"expression"`
+		}
+		json.Unmarshal(msg.Params, &p)
+		if expr != nil {
+			*expr = p.Expression
+		}
+		return map[string]any{"result": obj}, nil
+	})
+}
+
+func TestSetFileInput(t *testing.T) {
+	t.Run("sets multiple files", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		handlePage(b)
+		var expr string
+		handleFileInput(b, map[string]any{"type": "object", "subtype": "node", "objectId": "obj-1"}, &expr)
+		conn := mustDial(t, b)
+		s, _ := conn.AttachToPage(context.Background())
+
+		paths := []string{"/files/a.png", "/files/b.png"}
+		if err := s.SetFileInput(context.Background(), "#upload", paths); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		calls := b.callsTo("DOM.setFileInputFiles")
+		if len(calls) != 1 {
+			t.Fatalf("calls = %d, want 1", len(calls))
+		}
+		if calls[0].SessionID != "S1" {
+			t.Errorf("SessionID = %q, want S1", calls[0].SessionID)
+		}
+		var p struct {
+			Files    []string `json:"files"`
+			ObjectID string   `json:"objectId"`
+		}
+		json.Unmarshal(calls[0].Params, &p)
+		if !reflect.DeepEqual(p.Files, paths) || p.ObjectID != "obj-1" {
+			t.Errorf("unexpected params: %+v", p)
+		}
+		if !strings.Contains(expr, `["#upload"]`) || !strings.Contains(expr, ", 2)") {
+			t.Errorf("unexpected expression: %s", expr)
+		}
+		if len(b.callsTo("Runtime.releaseObject")) != 1 {
+			t.Error("expected element handle to be released")
+		}
+	})
+
+	t.Run("pierces shadow roots", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		handlePage(b)
+		var expr string
+		handleFileInput(b, map[string]any{"type": "object", "subtype": "node", "objectId": "obj-2"}, &expr)
+		conn := mustDial(t, b)
+		s, _ := conn.AttachToPage(context.Background())
+
+		err := s.SetFileInput(context.Background(), "my-uploader >>> input[type=file]", []string{"/a.txt"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(expr, `["my-uploader","input[type=file]"]`) {
+			t.Errorf("selector not split on shadow boundary: %s", expr)
+		}
+	})
+
+	t.Run("element not found", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		handlePage(b)
+		handleFileInput(b, map[string]any{"type": "object", "subtype": "null"}, nil)
+		conn := mustDial(t, b)
+		s, _ := conn.AttachToPage(context.Background())
+
+		err := s.SetFileInput(context.Background(), "#missing", []string{"/a.txt"})
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		if len(b.callsTo("DOM.setFileInputFiles")) != 0 {
+			t.Error("should not set files when element is missing")
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		handlePage(b)
+		conn := mustDial(t, b)
+		s, _ := conn.AttachToPage(context.Background())
+
+		if err := s.SetFileInput(context.Background(), "", []string{"/a.txt"}); err == nil {
+			t.Error("expected error for empty selector")
+		}
+		if err := s.SetFileInput(context.Background(), "#upload", nil); err == nil {
+			t.Error("expected error for empty paths")
+		}
+	})
+
+	t.Run("package-level helper dials and attaches", func(t *testing.T) {
+		b := newFakeBrowser(t)
+		handlePage(b)
+		handleFileInput(b, map[string]any{"type": "object", "subtype": "node", "objectId": "obj-3"}, nil)
+
+		err := SetFileInput(context.Background(), b.wsURL(), "#upload", []string{"/a.txt"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(b.callsTo("DOM.setFileInputFiles")) != 1 {
+			t.Error("expected DOM.setFileInputFiles to be called")
+		}
+	})
+}
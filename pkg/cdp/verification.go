@@ -0,0 +1,113 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Verification channels.
+const (
+	VerificationSMS   = "sms"
+	VerificationEmail = "email"
+)
+
+// VerificationRequest asks a VerificationProvider for a code.
+type VerificationRequest struct {
+	Channel string    // VerificationSMS or VerificationEmail
+	Target  string    // Phone number or email address the code was sent to
+	Since   time.Time // Only codes received after this time count
+}
+
+// VerificationProvider supplies one-time codes sent by SMS or email, e.g.
+// from an SMS activation service or a mailbox. Code blocks until the code
+// arrives or ctx is done.
+type VerificationProvider interface {
+	Code(ctx context.Context, req VerificationRequest) (string, error)
+}
+
+// VerificationProviderFunc adapts a function to VerificationProvider.
+type VerificationProviderFunc func(ctx context.Context, req VerificationRequest) (string, error)
+
+// Code calls f.
+func (f VerificationProviderFunc) Code(ctx context.Context, req VerificationRequest) (string, error) {
+	return f(ctx, req)
+}
+
+// focusElementJS focuses the element found by queryElementJS.
+const focusElementJS = `(function(el) {
+	if (!el) return false;
+	el.focus();
+	return true;
+})(%s)`
+
+// EnterVerificationCode waits for provider to deliver the code for req and
+// types it into the input matching selector, returning the code. Call it
+// after the page has sent the code; a zero req.Since means now.
+//
+// The code is typed one character at a time, so inputs split into one box
+// per digit work as long as the page moves focus along. The selector may
+// cross shadow roots using ShadowPierce.
+//
+// Example:
+//
+//	code, err := session.EnterVerificationCode(ctx, smsProvider, cdp.VerificationRequest{
+//	    Channel: cdp.VerificationSMS, Target: "+15550100",
+//	}, "input[name=otp]")
+func (s *Session) EnterVerificationCode(ctx context.Context, provider VerificationProvider, req VerificationRequest, selector string) (string, error) {
+	if provider == nil {
+		return "", fmt.Errorf("cdp: verification provider is required")
+	}
+	if req.Since.IsZero() {
+		req.Since = time.Now()
+	}
+	code, err := provider.Code(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("cdp: get %s verification code for %s: %w", req.Channel, req.Target, err)
+	}
+	if code == "" {
+		return "", fmt.Errorf("cdp: %s verification code for %s is empty", req.Channel, req.Target)
+	}
+	if err := s.Type(ctx, selector, code); err != nil {
+		return code, err
+	}
+	return code, nil
+}
+
+// Type focuses the element matching selector and types text into it, one
+// character at a time with Input.insertText, as if entered from the
+// keyboard.
+func (s *Session) Type(ctx context.Context, selector, text string) error {
+	if strings.TrimSpace(selector) == "" {
+		return fmt.Errorf("cdp: selector is required")
+	}
+	encoded, err := json.Marshal(splitSelector(selector))
+	if err != nil {
+		return fmt.Errorf("cdp: failed to encode selector: %w", err)
+	}
+
+	var focused bool
+	if err := s.Evaluate(ctx, fmt.Sprintf(focusElementJS, fmt.Sprintf(queryElementJS, encoded)), &focused); err != nil {
+		return fmt.Errorf("cdp: query %q: %w", selector, err)
+	}
+	if !focused {
+		return fmt.Errorf("%w: no element matches %q", ErrNotFound, selector)
+	}
+	return s.insertText(ctx, text)
+}
+
+// insertText types text into the focused element, one character at a time.
+// Input.insertText
+func (s *Session) insertText(ctx context.Context, text string) error {
+	for _, r := range text {
+		params := struct {
+			Text string `json:"text"`
+		}{Text: string(r)}
+		if err := s.Call(ctx, "Input.insertText", params, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package cdp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// verificationPage is a fake browser whose page has an input when found is true.
+func verificationPage(t *testing.T, found bool) (*fakeBrowser, *Session) {
+	t.Helper()
+	b := newFakeBrowser(t)
+	handlePage(b)
+	b.handle("Runtime.evaluate", func(msg message) (any, *Error) {
+		return map[string]any{"result": map[string]any{"type": "boolean", "value": found}}, nil
+	})
+	conn := mustDial(t, b)
+	s, err := conn.AttachToPage(context.Background())
+	if err != nil {
+		t.Fatalf("AttachToPage failed: %v", err)
+	}
+	return b, s
+}
+
+func TestEnterVerificationCode(t *testing.T) {
+	t.Run("types the provider's code", func(t *testing.T) {
+		b, s := verificationPage(t, true)
+		var got VerificationRequest
+		provider := VerificationProviderFunc(func(ctx context.Context, req VerificationRequest) (string, error) {
+			got = req
+			return "4821", nil
+		})
+
+		code, err := s.EnterVerificationCode(context.Background(), provider,
+			VerificationRequest{Channel: VerificationSMS, Target: "+15550100"}, "my-form >>> input[name=otp]")
+		if err != nil || code != "4821" {
+			t.Fatalf("EnterVerificationCode() = %q, %v", code, err)
+		}
+		if got.Target != "+15550100" || got.Since.IsZero() {
+			t.Errorf("provider request = %+v", got)
+		}
+
+		evals := b.callsTo("Runtime.evaluate")
+		if len(evals) != 1 || !strings.Contains(string(evals[0].Params), `input[name=otp]`) {
+			t.Errorf("evaluate calls = %+v", evals)
+		}
+		var typed strings.Builder
+		for _, m := range b.callsTo("Input.insertText") {
+			var p struct{ Text string }
+			json.Unmarshal(m.Params, &p)
+			typed.WriteString(p.Text)
+			if m.SessionID != "S1" {
+				t.Errorf("insertText sent to session %q", m.SessionID)
+			}
+		}
+		if typed.String() != "4821" || len(b.callsTo("Input.insertText")) != 4 {
+			t.Errorf("typed %q in %d calls", typed.String(), len(b.callsTo("Input.insertText")))
+		}
+	})
+
+	t.Run("provider failure", func(t *testing.T) {
+		b, s := verificationPage(t, true)
+		errNoSMS := errors.New("no sms")
+		_, err := s.EnterVerificationCode(context.Background(), VerificationProviderFunc(func(ctx context.Context, req VerificationRequest) (string, error) {
+			return "", errNoSMS
+		}), VerificationRequest{Channel: VerificationSMS, Target: "+15550100"}, "input")
+		if !errors.Is(err, errNoSMS) {
+			t.Errorf("err = %v, want errNoSMS", err)
+		}
+		if len(b.callsTo("Input.insertText")) != 0 {
+			t.Error("typed despite provider failure")
+		}
+	})
+
+	t.Run("missing input", func(t *testing.T) {
+		_, s := verificationPage(t, false)
+		code, err := s.EnterVerificationCode(context.Background(), VerificationProviderFunc(func(ctx context.Context, req VerificationRequest) (string, error) {
+			return "123456", nil
+		}), VerificationRequest{Channel: VerificationEmail, Target: "bot@example.com"}, "#otp")
+		if !errors.Is(err, ErrNotFound) || code != "123456" {
+			t.Errorf("EnterVerificationCode() = %q, %v; want the code and ErrNotFound", code, err)
+		}
+	})
+
+	t.Run("nil provider", func(t *testing.T) {
+		_, s := verificationPage(t, true)
+		if _, err := s.EnterVerificationCode(context.Background(), nil, VerificationRequest{}, "input"); err == nil {
+			t.Error("expected an error for a nil provider")
+		}
+	})
+}
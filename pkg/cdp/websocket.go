@@ -0,0 +1,347 @@
+package cdp
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes (RFC 6455, section 5.2).
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// maxMessageSize caps the size of a single reassembled message.
+// CDP screenshots and large DOM snapshots can reach tens of megabytes.
+const maxMessageSize = 256 << 20
+
+// websocketGUID is the magic value used to compute Sec-WebSocket-Accept.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsConn is a minimal RFC 6455 WebSocket connection.
+// It supports exactly what the DevTools protocol needs: text messages,
+// fragmentation, ping/pong and close. Extensions are not negotiated.
+type wsConn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool          // Client connections mask every outgoing frame
+	pong   chan struct{} // Signaled when a pong arrives
+
+	wmu sync.Mutex // Serializes frame writes
+}
+
+// newWSConn wraps an already upgraded network connection.
+func newWSConn(conn net.Conn, br *bufio.Reader, client bool) *wsConn {
+	if br == nil {
+		br = bufio.NewReader(conn)
+	}
+	return &wsConn{conn: conn, br: br, client: client, pong: make(chan struct{}, 1)}
+}
+
+// dialWebSocket performs the opening handshake against a ws:// or wss:// URL.
+func dialWebSocket(ctx context.Context, rawURL string, cfg *dialConfig) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("cdp: invalid WebSocket URL %q: %w", rawURL, err)
+	}
+
+	var secure bool
+	switch u.Scheme {
+	case "ws", "http":
+	case "wss", "https":
+		secure = true
+	default:
+		return nil, fmt.Errorf("cdp: unsupported WebSocket scheme %q", u.Scheme)
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		if secure {
+			addr = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			addr = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	conn, err := cfg.dial(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("cdp: dial %s: %w", addr, err)
+	}
+
+	// Bound the handshake by the context deadline, then clear it so the
+	// long-lived connection is not affected.
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	if secure {
+		tlsConfig := cfg.tlsConfig.Clone()
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = u.Hostname()
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("cdp: TLS handshake with %s: %w", addr, err)
+		}
+		conn = tlsConn
+	}
+
+	ws, err := handshake(conn, u, cfg.header)
+	if err != nil {
+		conn.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Time{})
+	return ws, nil
+}
+
+// handshake sends the HTTP upgrade request and validates the response.
+func handshake(conn net.Conn, u *url.URL, header http.Header) (*wsConn, error) {
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, fmt.Errorf("cdp: generate WebSocket key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+
+	reqURL := *u
+	switch reqURL.Scheme {
+	case "ws":
+		reqURL.Scheme = "http"
+	case "wss":
+		reqURL.Scheme = "https"
+	}
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &reqURL,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Host:       u.Host,
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	if err := req.Write(conn); err != nil {
+		return nil, fmt.Errorf("cdp: send WebSocket handshake: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, fmt.Errorf("cdp: read WebSocket handshake: %w", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("cdp: WebSocket handshake failed with status %d: %s",
+			resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") {
+		return nil, errors.New("cdp: WebSocket handshake missing Upgrade header")
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, errors.New("cdp: WebSocket handshake returned an invalid accept key")
+	}
+
+	return newWSConn(conn, br, true), nil
+}
+
+// acceptKey computes the Sec-WebSocket-Accept value for a handshake key.
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// readMessage returns the next complete data message.
+// Control frames are handled transparently. A close frame from the peer
+// is answered and reported as io.EOF.
+func (c *wsConn) readMessage() ([]byte, error) {
+	var msg []byte
+	started := false
+
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			select {
+			case c.pong <- struct{}{}:
+			default:
+			}
+			continue
+		case opClose:
+			c.writeFrame(opClose, payload)
+			return nil, io.EOF
+		case opText, opBinary:
+			if started {
+				return nil, errors.New("cdp: unexpected data frame inside fragmented message")
+			}
+			started = true
+			msg = payload
+		case opContinuation:
+			if !started {
+				return nil, errors.New("cdp: unexpected continuation frame")
+			}
+			if len(msg)+len(payload) > maxMessageSize {
+				return nil, errors.New("cdp: message exceeds maximum size")
+			}
+			msg = append(msg, payload...)
+		default:
+			return nil, fmt.Errorf("cdp: unknown WebSocket opcode %#x", opcode)
+		}
+
+		if fin {
+			return msg, nil
+		}
+	}
+}
+
+// readFrame reads a single frame, unmasking the payload if necessary.
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err = io.ReadFull(c.br, hdr[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = hdr[0]&0x80 != 0
+	opcode = hdr[0] & 0x0F
+	masked := hdr[1]&0x80 != 0
+	length := uint64(hdr[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxMessageSize {
+		return false, 0, nil, errors.New("cdp: frame exceeds maximum size")
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		maskBytes(mask, payload)
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeMessage sends a single unfragmented text message.
+func (c *wsConn) writeMessage(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// writeFrame sends a single final frame with the given opcode.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	buf := make([]byte, 0, 14+len(payload))
+	buf = append(buf, 0x80|opcode)
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+
+	switch n := len(payload); {
+	case n <= 125:
+		buf = append(buf, maskBit|byte(n))
+	case n <= 0xFFFF:
+		buf = append(buf, maskBit|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		buf = append(buf, mask[:]...)
+		start := len(buf)
+		buf = append(buf, payload...)
+		maskBytes(mask, buf[start:])
+	} else {
+		buf = append(buf, payload...)
+	}
+
+	_, err := c.conn.Write(buf)
+	return err
+}
+
+// close sends a normal closure frame and closes the connection.
+func (c *wsConn) close() error {
+	c.writeFrame(opClose, []byte{0x03, 0xE8}) // 1000: normal closure
+	return c.conn.Close()
+}
+
+// maskBytes applies the WebSocket masking algorithm in place.
+func maskBytes(mask [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= mask[i%4]
+	}
+}
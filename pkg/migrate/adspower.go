@@ -0,0 +1,99 @@
+package migrate
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// adsPowerProfile is a profile object of the AdsPower local API
+// (user/list, user/create).
+type adsPowerProfile struct {
+	UserID       string          `json:"user_id"`
+	SerialNumber flexString      `json:"serial_number"`
+	Name         string          `json:"name"`
+	DomainName   string          `json:"domain_name"`
+	OpenURLs     []string        `json:"open_urls"`
+	Username     string          `json:"username"`
+	Password     string          `json:"password"`
+	FAKey        string          `json:"fakey"`
+	Remark       string          `json:"remark"`
+	Cookie       json.RawMessage `json:"cookie"`
+
+	Proxy struct {
+		Soft     string     `json:"proxy_soft"` // "other" for custom proxies, else a provider
+		Type     string     `json:"proxy_type"`
+		Host     string     `json:"proxy_host"`
+		Port     flexString `json:"proxy_port"`
+		User     string     `json:"proxy_user"`
+		Password string     `json:"proxy_password"`
+	} `json:"user_proxy_config"`
+
+	Fingerprint struct {
+		AutomaticTimezone   flexString `json:"automatic_timezone"` // "1" follows the IP
+		Timezone            string     `json:"timezone"`
+		LanguageSwitch      flexString `json:"language_switch"` // "1" follows the IP
+		Language            []string   `json:"language"`
+		UA                  string     `json:"ua"`
+		ScreenResolution    string     `json:"screen_resolution"` // "1920_1080"; "none" or "random" otherwise
+		LocationSwitch      flexString `json:"location_switch"`   // "1" follows the IP
+		Longitude           flexString `json:"longitude"`
+		Latitude            flexString `json:"latitude"`
+		Accuracy            flexString `json:"accuracy"`
+		HardwareConcurrency flexString `json:"hardware_concurrency"`
+		DeviceMemory        flexString `json:"device_memory"`
+		DoNotTrack          string     `json:"do_not_track"` // "true", "false" or "default"
+		WebGLConfig         struct {
+			Vendor   string `json:"unmasked_vendor"`
+			Renderer string `json:"unmasked_renderer"`
+		} `json:"webgl_config"`
+	} `json:"fingerprint_config"`
+}
+
+func convertAdsPower(data json.RawMessage) (Profile, error) {
+	var src adsPowerProfile
+	if err := json.Unmarshal(data, &src); err != nil {
+		return Profile{}, err
+	}
+	name := src.Name
+	if name == "" && src.SerialNumber != "" {
+		name = "AdsPower " + string(src.SerialNumber)
+	}
+	b := newBuilder(src.UserID, name)
+	c := &b.p.Config
+	c.Remark = src.Remark
+	c.UserName = src.Username
+	c.Password = src.Password
+	c.FaSecretKey = src.FAKey
+	if src.DomainName != "" {
+		c.Platform = "https://" + strings.TrimPrefix(strings.TrimPrefix(src.DomainName, "https://"), "http://")
+	}
+	c.URL = strings.Join(src.OpenURLs, ",")
+	if err := b.cookies(src.Cookie); err != nil {
+		b.warn("cookies were not imported: %v", err)
+	}
+
+	if soft := src.Proxy.Soft; soft != "" && soft != "other" && soft != "no_proxy" {
+		b.warn("proxy provider %q is not supported; the profile has no proxy", soft)
+	} else {
+		b.proxy(src.Proxy.Type, src.Proxy.Host, src.Proxy.Port.int(), src.Proxy.User, src.Proxy.Password)
+	}
+
+	fp := src.Fingerprint
+	b.userAgent(fp.UA)
+	if fp.AutomaticTimezone == "0" {
+		b.timeZone(fp.Timezone)
+	}
+	if fp.LanguageSwitch == "0" {
+		b.languages(strings.Join(fp.Language, ","))
+	}
+	if fp.LocationSwitch == "0" {
+		b.geolocation(fp.Latitude.float(), fp.Longitude.float(), fp.Accuracy.float())
+	}
+	if res := fp.ScreenResolution; res != "" && res != "none" && res != "random" {
+		b.resolution(res)
+	}
+	b.hardware(fp.HardwareConcurrency.int(), fp.DeviceMemory.int())
+	b.webGL(fp.WebGLConfig.Vendor, fp.WebGLConfig.Renderer)
+	b.doNotTrack(fp.DoNotTrack == "true")
+	return b.p, nil
+}
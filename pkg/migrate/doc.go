@@ -0,0 +1,27 @@
+// Package migrate converts profile exports of other antidetect browsers
+// into BitBrowser profiles, so that switching vendors does not mean
+// recreating every profile by hand.
+//
+// It reads the JSON profile formats of AdsPower (the local API's profile
+// objects), GoLogin (the API's browser profiles, with cookies) and Dolphin
+// Anty (browser profiles, with cookies). A file may hold one profile, an
+// array of profiles or an API response listing them. Each profile becomes
+// a bitbrowser.ProfileConfig with its name, notes, proxy, cookies and the
+// fingerprint settings BitBrowser has equivalents for: user agent, time
+// zone, languages, geolocation, screen resolution, CPU cores, memory, WebGL
+// vendor and renderer, and Do Not Track. Settings that cannot be carried
+// over are listed in Profile.Warnings; noise modes are not carried over, as
+// BitBrowser adds canvas, WebGL and audio noise by default.
+//
+// # Usage
+//
+//	data, err := os.ReadFile("gologin-profiles.json")
+//	profiles, err := migrate.Parse(migrate.GoLogin, data)
+//	for i := range profiles {
+//	    profiles[i].Config.GroupID = groupID
+//	}
+//	report, err := migrate.Import(ctx, client, profiles)
+//	for _, f := range report.Failed {
+//	    log.Printf("%s (%s): %s", f.Name, f.SourceID, f.Reason)
+//	}
+package migrate
@@ -0,0 +1,109 @@
+package migrate
+
+import (
+	"encoding/json"
+
+	"github.com/lpg-it/go-antidetect/pkg/ua"
+)
+
+// dolphinSetting is a Dolphin Anty setting with a mode: "manual" values
+// apply, other modes ("auto", "real", "off") leave them to the browser.
+type dolphinSetting struct {
+	Mode       string     `json:"mode"`
+	Value      flexString `json:"value"`
+	Resolution string     `json:"resolution"`
+	Vendor     string     `json:"vendor"`
+	Renderer   string     `json:"renderer"`
+	Latitude   flexString `json:"latitude"`
+	Longitude  flexString `json:"longitude"`
+	Accuracy   flexString `json:"accuracy"`
+}
+
+func (s dolphinSetting) manual() bool { return s.Mode == "manual" }
+
+// dolphinProfile is a Dolphin Anty browser profile, optionally with the
+// profile's cookies added as "cookies".
+type dolphinProfile struct {
+	ID          flexString      `json:"id"`
+	Name        string          `json:"name"`
+	Platform    string          `json:"platform"` // "windows", "macos", "linux", "android" or "ios"
+	MainWebsite string          `json:"mainWebsite"`
+	Cookies     json.RawMessage `json:"cookies"`
+	DoNotTrack  bool            `json:"doNotTrack"`
+	Notes       struct {
+		Content string `json:"content"`
+	} `json:"notes"`
+
+	UserAgent   dolphinSetting `json:"useragent"`
+	Timezone    dolphinSetting `json:"timezone"`
+	Locale      dolphinSetting `json:"locale"` // "en_US"
+	Geolocation dolphinSetting `json:"geolocation"`
+	CPU         dolphinSetting `json:"cpu"`
+	Memory      dolphinSetting `json:"memory"`
+	Screen      dolphinSetting `json:"screen"`
+	WebGLInfo   dolphinSetting `json:"webglInfo"`
+
+	Proxy *struct {
+		Type     string     `json:"type"`
+		Host     string     `json:"host"`
+		Port     flexString `json:"port"`
+		Login    string     `json:"login"`
+		Password string     `json:"password"`
+	} `json:"proxy"`
+}
+
+// dolphinOS maps Dolphin Anty's platform values.
+var dolphinOS = map[string]ua.OS{"windows": ua.Windows, "macos": ua.MacOS, "linux": ua.Linux, "android": ua.Android, "ios": ua.IOS}
+
+// dolphinWebsites maps Dolphin Anty's main websites to platform URLs.
+var dolphinWebsites = map[string]string{
+	"facebook": "https://www.facebook.com",
+	"google":   "https://www.google.com",
+	"tiktok":   "https://www.tiktok.com",
+}
+
+func convertDolphin(data json.RawMessage) (Profile, error) {
+	var src dolphinProfile
+	if err := json.Unmarshal(data, &src); err != nil {
+		return Profile{}, err
+	}
+	b := newBuilder(string(src.ID), src.Name)
+	b.p.Config.Remark = src.Notes.Content
+	b.p.Config.Platform = dolphinWebsites[src.MainWebsite]
+	if err := b.cookies(src.Cookies); err != nil {
+		b.warn("cookies were not imported: %v", err)
+	}
+	if p := src.Proxy; p != nil {
+		b.proxy(p.Type, p.Host, p.Port.int(), p.Login, p.Password)
+	}
+
+	b.os(dolphinOS[src.Platform])
+	if src.UserAgent.manual() {
+		b.userAgent(string(src.UserAgent.Value))
+	}
+	if src.Timezone.manual() {
+		b.timeZone(string(src.Timezone.Value))
+	}
+	if src.Locale.manual() {
+		b.languages(string(src.Locale.Value))
+	}
+	if geo := src.Geolocation; geo.manual() {
+		b.geolocation(geo.Latitude.float(), geo.Longitude.float(), geo.Accuracy.float())
+	}
+	if src.Screen.manual() {
+		b.resolution(src.Screen.Resolution)
+	}
+	var cores, memory int
+	if src.CPU.manual() {
+		cores = src.CPU.Value.int()
+	}
+	if src.Memory.manual() {
+		memory = src.Memory.Value.int()
+	}
+	b.hardware(cores, memory)
+	if src.WebGLInfo.manual() {
+		b.webGL(src.WebGLInfo.Vendor, src.WebGLInfo.Renderer)
+	}
+	b.doNotTrack(src.DoNotTrack)
+	return b.p, nil
+}
@@ -0,0 +1,88 @@
+package migrate
+
+import (
+	"encoding/json"
+
+	"github.com/lpg-it/go-antidetect/pkg/ua"
+)
+
+// goLoginProfile is a browser profile of the GoLogin API (GET /browser/{id}),
+// optionally with the profile's cookies added as "cookies".
+type goLoginProfile struct {
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Notes    string          `json:"notes"`
+	OS       string          `json:"os"` // "win", "mac", "lin" or "android"
+	StartURL string          `json:"startUrl"`
+	Cookies  json.RawMessage `json:"cookies"`
+
+	Navigator struct {
+		UserAgent           string     `json:"userAgent"`
+		Resolution          string     `json:"resolution"` // "1920x1080"
+		Language            string     `json:"language"`   // "en-US,en;q=0.9"
+		HardwareConcurrency flexString `json:"hardwareConcurrency"`
+		DeviceMemory        flexString `json:"deviceMemory"`
+		DoNotTrack          bool       `json:"doNotTrack"`
+	} `json:"navigator"`
+
+	Proxy struct {
+		Mode     string     `json:"mode"` // "http", "socks4", "socks5", "none", "gologin" or "tor"
+		Host     string     `json:"host"`
+		Port     flexString `json:"port"`
+		Username string     `json:"username"`
+		Password string     `json:"password"`
+	} `json:"proxy"`
+
+	Timezone struct {
+		FillBasedOnIP bool   `json:"fillBasedOnIp"`
+		Timezone      string `json:"timezone"`
+	} `json:"timezone"`
+
+	Geolocation struct {
+		FillBasedOnIP bool       `json:"fillBasedOnIp"`
+		Latitude      flexString `json:"latitude"`
+		Longitude     flexString `json:"longitude"`
+		Accuracy      flexString `json:"accuracy"`
+	} `json:"geolocation"`
+
+	WebGLMetadata struct {
+		Mode     string `json:"mode"` // "mask" to report vendor and renderer
+		Vendor   string `json:"vendor"`
+		Renderer string `json:"renderer"`
+	} `json:"webGLMetadata"`
+}
+
+// goLoginOS maps GoLogin's os values.
+var goLoginOS = map[string]ua.OS{"win": ua.Windows, "mac": ua.MacOS, "lin": ua.Linux, "android": ua.Android}
+
+func convertGoLogin(data json.RawMessage) (Profile, error) {
+	var src goLoginProfile
+	if err := json.Unmarshal(data, &src); err != nil {
+		return Profile{}, err
+	}
+	b := newBuilder(src.ID, src.Name)
+	b.p.Config.Remark = src.Notes
+	b.p.Config.URL = src.StartURL
+	if err := b.cookies(src.Cookies); err != nil {
+		b.warn("cookies were not imported: %v", err)
+	}
+	b.proxy(src.Proxy.Mode, src.Proxy.Host, src.Proxy.Port.int(), src.Proxy.Username, src.Proxy.Password)
+
+	b.os(goLoginOS[src.OS])
+	nav := src.Navigator
+	b.userAgent(nav.UserAgent)
+	if !src.Timezone.FillBasedOnIP {
+		b.timeZone(src.Timezone.Timezone)
+	}
+	b.languages(nav.Language)
+	if geo := src.Geolocation; !geo.FillBasedOnIP {
+		b.geolocation(geo.Latitude.float(), geo.Longitude.float(), geo.Accuracy.float())
+	}
+	b.resolution(nav.Resolution)
+	b.hardware(nav.HardwareConcurrency.int(), nav.DeviceMemory.int())
+	if src.WebGLMetadata.Mode == "mask" {
+		b.webGL(src.WebGLMetadata.Vendor, src.WebGLMetadata.Renderer)
+	}
+	b.doNotTrack(nav.DoNotTrack)
+	return b.p, nil
+}
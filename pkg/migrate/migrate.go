@@ -0,0 +1,344 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+	"github.com/lpg-it/go-antidetect/pkg/ua"
+)
+
+// Vendor is an antidetect browser profiles can be migrated from.
+type Vendor string
+
+// Vendors.
+const (
+	AdsPower Vendor = "adspower"
+	GoLogin  Vendor = "gologin"
+	Dolphin  Vendor = "dolphin"
+)
+
+// Profile is a profile converted from another vendor's export.
+type Profile struct {
+	SourceID string // The profile's ID at the source vendor
+	Config   bitbrowser.ProfileConfig
+	Cookies  []bitbrowser.Cookie // Also set as Config.Cookie
+	Warnings []string            // Settings that were not carried over
+}
+
+// Parse converts a JSON export of vendor. The export may be one profile, an
+// array of profiles or an API response whose "data", "list", "profiles" or
+// "browsers" field lists them.
+func Parse(vendor Vendor, data []byte) ([]Profile, error) {
+	var convert func(json.RawMessage) (Profile, error)
+	switch vendor {
+	case AdsPower:
+		convert = convertAdsPower
+	case GoLogin:
+		convert = convertGoLogin
+	case Dolphin:
+		convert = convertDolphin
+	default:
+		return nil, fmt.Errorf("migrate: unknown vendor %q", vendor)
+	}
+
+	objects, err := profileObjects(data)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: parse %s export: %w", vendor, err)
+	}
+	profiles := make([]Profile, 0, len(objects))
+	for i, obj := range objects {
+		p, err := convert(obj)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %s profile %d: %w", vendor, i+1, err)
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, nil
+}
+
+// profileObjects finds the profile objects of an export.
+func profileObjects(data []byte) ([]json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err == nil {
+		return list, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"data", "list", "profiles", "browsers"} {
+		if inner, ok := obj[key]; ok && len(inner) > 0 && (inner[0] == '[' || inner[0] == '{') {
+			return profileObjects(inner)
+		}
+	}
+	return []json.RawMessage{data}, nil
+}
+
+// Created is a profile Import created.
+type Created struct {
+	SourceID string `json:"sourceId"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+}
+
+// Failed is a profile Import could not create.
+type Failed struct {
+	SourceID string `json:"sourceId"`
+	Name     string `json:"name"`
+	Reason   string `json:"reason"`
+}
+
+// Report is the outcome of Import.
+type Report struct {
+	Created []Created `json:"created"`
+	Failed  []Failed  `json:"failed"`
+}
+
+// Import creates a BitBrowser profile, cookies included, for each profile.
+// Profiles BitBrowser refuses are listed in the report's Failed profiles
+// and do not stop the import; an error is returned only if ctx is done, with
+// the report of what was imported so far.
+func Import(ctx context.Context, client *bitbrowser.Client, profiles []Profile) (*Report, error) {
+	report := &Report{}
+	for _, p := range profiles {
+		id, err := client.CreateProfile(ctx, p.Config)
+		if err != nil {
+			if ctx.Err() != nil {
+				return report, err
+			}
+			report.Failed = append(report.Failed, Failed{SourceID: p.SourceID, Name: p.Config.Name, Reason: err.Error()})
+			continue
+		}
+		report.Created = append(report.Created, Created{SourceID: p.SourceID, ID: id, Name: p.Config.Name})
+	}
+	return report, nil
+}
+
+// builder collects the settings of one converted profile.
+type builder struct {
+	p Profile
+}
+
+func newBuilder(sourceID, name string) *builder {
+	return &builder{p: Profile{
+		SourceID: sourceID,
+		Config: bitbrowser.ProfileConfig{
+			Name:               name,
+			BrowserFingerPrint: &bitbrowser.Fingerprint{CoreVersion: bitbrowser.DefaultCoreVersion},
+		},
+	}}
+}
+
+func (b *builder) warn(format string, args ...any) {
+	b.p.Warnings = append(b.p.Warnings, fmt.Sprintf(format, args...))
+}
+
+// proxy sets a custom proxy. Types BitBrowser lacks are dropped with a
+// warning.
+func (b *builder) proxy(proxyType, host string, port int, user, pass string) {
+	proxyType = strings.ToLower(proxyType)
+	switch proxyType {
+	case "", "none", "noproxy", "direct":
+		return
+	case "http", "https", "socks5", "ssh":
+	default:
+		b.warn("proxy type %q is not supported; the profile has no proxy", proxyType)
+		return
+	}
+	if host == "" || port <= 0 || port > 65535 {
+		b.warn("proxy %s:%d is incomplete; the profile has no proxy", host, port)
+		return
+	}
+	c := &b.p.Config
+	c.ProxyMethod = bitbrowser.ProxyMethodCustom
+	c.ProxyType = bitbrowser.ProxyType(proxyType)
+	c.Host = host
+	c.Port = port
+	c.ProxyUserName = user
+	c.ProxyPassword = pass
+}
+
+// userAgent sets a manual user agent and the core and OS it names.
+func (b *builder) userAgent(s string) {
+	if s == "" {
+		return
+	}
+	fp := b.p.Config.BrowserFingerPrint
+	fp.UserAgent = s
+	agent, err := ua.Parse(s)
+	if err != nil {
+		b.warn("user agent is not recognized; check the core version and OS")
+		return
+	}
+	switch agent.Browser {
+	case ua.Chrome, ua.Firefox:
+		fp.CoreProduct = string(agent.Browser)
+		fp.CoreVersion = strconv.Itoa(agent.Major())
+	default:
+		b.warn("user agent is %s, which BitBrowser cannot run", agent.Browser)
+	}
+	if fp.OS == "" {
+		b.os(agent.OS)
+	}
+}
+
+// platforms maps operating systems to Fingerprint.OSType and OS.
+var platforms = map[ua.OS][2]string{
+	ua.Windows: {"PC", "Win32"},
+	ua.MacOS:   {"PC", "MacIntel"},
+	ua.Linux:   {"PC", "Linux x86_64"},
+	ua.Android: {"Android", "Linux armv81"},
+	ua.IOS:     {"IOS", "iPhone"},
+}
+
+func (b *builder) os(os ua.OS) {
+	if p, ok := platforms[os]; ok {
+		fp := b.p.Config.BrowserFingerPrint
+		fp.OSType, fp.OS = bitbrowser.OSType(p[0]), p[1]
+	}
+}
+
+func (b *builder) timeZone(zone string) {
+	if zone != "" {
+		fp := b.p.Config.BrowserFingerPrint
+		fp.IsIpCreateTimeZone = bitbrowser.Bool(false)
+		fp.TimeZone = zone
+	}
+}
+
+// languages sets manual languages from a list such as "en-US,en;q=0.9".
+func (b *builder) languages(list string) {
+	var langs []string
+	for _, lang := range strings.Split(list, ",") {
+		lang, _, _ = strings.Cut(lang, ";")
+		if lang = strings.TrimSpace(strings.ReplaceAll(lang, "_", "-")); lang != "" {
+			langs = append(langs, lang)
+		}
+	}
+	if len(langs) > 0 {
+		fp := b.p.Config.BrowserFingerPrint
+		fp.IsIpCreateLanguage = bitbrowser.Bool(false)
+		fp.Languages = strings.Join(langs, ",")
+	}
+}
+
+func (b *builder) geolocation(lat, lng, accuracy float64) {
+	if lat == 0 && lng == 0 {
+		return
+	}
+	fp := b.p.Config.BrowserFingerPrint
+	fp.IsIpCreatePosition = bitbrowser.Bool(false)
+	fp.Lat = strconv.FormatFloat(lat, 'f', -1, 64)
+	fp.Lng = strconv.FormatFloat(lng, 'f', -1, 64)
+	if accuracy > 0 {
+		fp.PrecisionData = strconv.FormatFloat(accuracy, 'f', -1, 64)
+	}
+}
+
+// resolution sets a custom screen resolution from "1920x1080" or
+// "1920_1080".
+func (b *builder) resolution(s string) {
+	w, h, ok := strings.Cut(strings.NewReplacer("_", "x", " ", "").Replace(s), "x")
+	width, err1 := strconv.Atoi(w)
+	height, err2 := strconv.Atoi(h)
+	if !ok || err1 != nil || err2 != nil {
+		if s != "" {
+			b.warn("screen resolution %q is not recognized", s)
+		}
+		return
+	}
+	fp := b.p.Config.BrowserFingerPrint
+	fp.ResolutionType = "1"
+	fp.Resolution = fmt.Sprintf("%d x %d", width, height)
+}
+
+func (b *builder) hardware(cores, memoryGB int) {
+	fp := b.p.Config.BrowserFingerPrint
+	if cores > 0 {
+		fp.HardwareConcurrency = strconv.Itoa(cores)
+	}
+	if memoryGB > 0 {
+		fp.DeviceMemory = strconv.Itoa(min(memoryGB, 8))
+	}
+}
+
+func (b *builder) webGL(vendor, renderer string) {
+	if vendor != "" || renderer != "" {
+		fp := b.p.Config.BrowserFingerPrint
+		fp.WebGLMeta = "0"
+		fp.WebGLManufacturer = vendor
+		fp.WebGLRender = renderer
+	}
+}
+
+func (b *builder) doNotTrack(enabled bool) {
+	if enabled {
+		b.p.Config.BrowserFingerPrint.DoNotTrack = "1"
+	}
+}
+
+// cookies sets cookies from a cookie array or a string holding one.
+func (b *builder) cookies(raw json.RawMessage) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" || string(raw) == `""` {
+		return nil
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		raw = json.RawMessage(s)
+	}
+	cookies, err := bitbrowser.ParseCookies(raw)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(cookies)
+	if err != nil {
+		return err
+	}
+	b.p.Cookies = cookies
+	b.p.Config.Cookie = string(data)
+	return nil
+}
+
+// flexString decodes a JSON string or number, as exports write numbers
+// either way.
+type flexString string
+
+func (f *flexString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*f = flexString(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*f = flexString(n)
+	return nil
+}
+
+func (f flexString) int() int {
+	n, _ := strconv.Atoi(strings.TrimSpace(string(f)))
+	return n
+}
+
+func (f flexString) float() float64 {
+	n, _ := strconv.ParseFloat(strings.TrimSpace(string(f)), 64)
+	return n
+}
@@ -0,0 +1,172 @@
+package migrate
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser/bitbrowsertest"
+)
+
+const chrome126Win = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+
+func TestParseAdsPower(t *testing.T) {
+	export := `{"code": 0, "data": {"list": [{
+		"user_id": "jb1x2y3", "serial_number": 12, "name": "", "domain_name": "facebook.com",
+		"username": "jane@example.com", "password": "secret", "remark": "warm",
+		"cookie": "[{\"name\":\"c_user\",\"value\":\"1\",\"domain\":\".facebook.com\",\"expirationDate\":1900000000}]",
+		"user_proxy_config": {"proxy_soft": "other", "proxy_type": "socks5", "proxy_host": "1.2.3.4", "proxy_port": "1080", "proxy_user": "u", "proxy_password": "p"},
+		"fingerprint_config": {
+			"automatic_timezone": "0", "timezone": "Europe/Berlin",
+			"language_switch": "0", "language": ["de-DE", "de"],
+			"ua": "` + chrome126Win + `",
+			"screen_resolution": "1920_1080", "hardware_concurrency": "8", "device_memory": "16",
+			"location_switch": "1", "latitude": "52.5", "longitude": "13.4",
+			"do_not_track": "true",
+			"webgl_config": {"unmasked_vendor": "Google Inc. (Intel)", "unmasked_renderer": "ANGLE (Intel)"}
+		}
+	}]}}`
+
+	profiles, err := Parse(AdsPower, []byte(export))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(profiles) != 1 {
+		t.Fatalf("got %d profiles, want 1", len(profiles))
+	}
+	p := profiles[0]
+	c := p.Config
+	if p.SourceID != "jb1x2y3" || c.Name != "AdsPower 12" || c.Platform != "https://facebook.com" || c.UserName != "jane@example.com" || c.Remark != "warm" {
+		t.Errorf("config = %+v", c)
+	}
+	if c.ProxyType != "socks5" || c.Host != "1.2.3.4" || c.Port != 1080 || c.ProxyUserName != "u" || c.ProxyMethod != bitbrowser.ProxyMethodCustom {
+		t.Errorf("proxy = %s %s:%d %s", c.ProxyType, c.Host, c.Port, c.ProxyUserName)
+	}
+	if len(p.Cookies) != 1 || p.Cookies[0].Expires != 1900000000 || !strings.Contains(c.Cookie, "c_user") {
+		t.Errorf("cookies = %+v, Cookie = %s", p.Cookies, c.Cookie)
+	}
+
+	want := bitbrowser.Fingerprint{
+		CoreProduct: "chrome", CoreVersion: "126", OSType: "PC", OS: "Win32", UserAgent: chrome126Win,
+		IsIpCreateTimeZone: bitbrowser.Bool(false), TimeZone: "Europe/Berlin",
+		IsIpCreateLanguage: bitbrowser.Bool(false), Languages: "de-DE,de",
+		ResolutionType: "1", Resolution: "1920 x 1080",
+		HardwareConcurrency: "8", DeviceMemory: "8", DoNotTrack: "1",
+		WebGLMeta: "0", WebGLManufacturer: "Google Inc. (Intel)", WebGLRender: "ANGLE (Intel)",
+	}
+	if !reflect.DeepEqual(*c.BrowserFingerPrint, want) {
+		t.Errorf("fingerprint = %+v\nwant %+v", *c.BrowserFingerPrint, want)
+	}
+	if len(p.Warnings) != 0 {
+		t.Errorf("warnings = %v", p.Warnings)
+	}
+}
+
+func TestParseGoLogin(t *testing.T) {
+	export := `[{
+		"id": "64f0c", "name": "shop-1", "notes": "n", "os": "mac", "startUrl": "https://example.com",
+		"navigator": {"userAgent": "", "resolution": "1440x900", "language": "en-US,en;q=0.9", "hardwareConcurrency": 4, "deviceMemory": 8},
+		"proxy": {"mode": "socks4", "host": "5.6.7.8", "port": 1080},
+		"timezone": {"fillBasedOnIp": true, "timezone": "America/New_York"},
+		"geolocation": {"fillBasedOnIp": false, "latitude": 40.7, "longitude": -74, "accuracy": 10},
+		"webGLMetadata": {"mode": "off", "vendor": "x"},
+		"cookies": [{"name": "sid", "value": "1", "domain": "example.com", "path": "/"}]
+	}, {"id": "64f0d", "name": "shop-2", "os": "win", "navigator": {}, "proxy": {"mode": "none"}}]`
+
+	profiles, err := Parse(GoLogin, []byte(export))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("got %d profiles, want 2", len(profiles))
+	}
+	p := profiles[0]
+	fp := p.Config.BrowserFingerPrint
+	if p.Config.Host != "" || len(p.Warnings) != 1 || !strings.Contains(p.Warnings[0], "socks4") {
+		t.Errorf("proxy = %q, warnings = %v; want socks4 dropped with a warning", p.Config.Host, p.Warnings)
+	}
+	if fp.OS != "MacIntel" || fp.CoreVersion != bitbrowser.DefaultCoreVersion || fp.TimeZone != "" || fp.Languages != "en-US,en" ||
+		fp.Lat != "40.7" || fp.Lng != "-74" || fp.PrecisionData != "10" || fp.Resolution != "1440 x 900" || fp.WebGLMeta != "" {
+		t.Errorf("fingerprint = %+v", fp)
+	}
+	if len(p.Cookies) != 1 || p.Config.URL != "https://example.com" {
+		t.Errorf("cookies = %+v, URL = %q", p.Cookies, p.Config.URL)
+	}
+	if profiles[1].Config.BrowserFingerPrint.OS != "Win32" || len(profiles[1].Warnings) != 0 {
+		t.Errorf("second profile = %+v, %v", profiles[1].Config.BrowserFingerPrint, profiles[1].Warnings)
+	}
+}
+
+func TestParseDolphin(t *testing.T) {
+	export := `{"data": [{
+		"id": 4711, "name": "fb-7", "platform": "windows", "mainWebsite": "facebook", "doNotTrack": false,
+		"notes": {"content": "ok"},
+		"useragent": {"mode": "manual", "value": "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:128.0) Gecko/20100101 Firefox/128.0"},
+		"timezone": {"mode": "manual", "value": "Europe/Paris"},
+		"locale": {"mode": "manual", "value": "fr_FR"},
+		"geolocation": {"mode": "auto"},
+		"cpu": {"mode": "manual", "value": 6},
+		"memory": {"mode": "real", "value": 32},
+		"screen": {"mode": "manual", "resolution": "2560x1440"},
+		"webglInfo": {"mode": "manual", "vendor": "Google Inc. (AMD)", "renderer": "ANGLE (AMD)"},
+		"proxy": {"type": "http", "host": "proxy.example", "port": "8080", "login": "l", "password": "p"}
+	}]}`
+
+	profiles, err := Parse(Dolphin, []byte(export))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	p := profiles[0]
+	fp := p.Config.BrowserFingerPrint
+	if p.SourceID != "4711" || p.Config.Remark != "ok" || p.Config.Platform != "https://www.facebook.com" ||
+		p.Config.Host != "proxy.example" || p.Config.Port != 8080 {
+		t.Errorf("config = %+v", p.Config)
+	}
+	if fp.CoreProduct != "firefox" || fp.CoreVersion != "128" || fp.OS != "Win32" || fp.TimeZone != "Europe/Paris" ||
+		fp.Languages != "fr-FR" || fp.Lat != "" || fp.HardwareConcurrency != "6" || fp.DeviceMemory != "" ||
+		fp.Resolution != "2560 x 1440" || fp.WebGLManufacturer != "Google Inc. (AMD)" {
+		t.Errorf("fingerprint = %+v", fp)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse("multilogin", []byte(`[]`)); err == nil {
+		t.Error("expected an error for an unknown vendor")
+	}
+	if _, err := Parse(GoLogin, []byte(`not json`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+	profiles, err := Parse(AdsPower, []byte(`{"user_id": "a", "name": "x", "cookie": "nonsense"}`))
+	if err != nil || len(profiles[0].Warnings) != 1 || !strings.Contains(profiles[0].Warnings[0], "cookies") {
+		t.Errorf("Parse() = %+v, %v; want a cookie warning", profiles, err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	server := bitbrowsertest.NewServer()
+	defer server.Close()
+	client, err := bitbrowser.New(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := Parse(GoLogin, []byte(`[{"id": "g1", "name": "one", "os": "win"}, {"id": "g2", "name": "two", "os": "lin"}]`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	// A time zone offset without a zone is refused before the request.
+	profiles[1].Config.BrowserFingerPrint.TimeZoneOffset = 60
+
+	report, err := Import(context.Background(), client, profiles)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(report.Created) != 1 || report.Created[0].SourceID != "g1" || report.Created[0].ID == "" {
+		t.Errorf("Created = %+v", report.Created)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].SourceID != "g2" || !strings.Contains(report.Failed[0].Reason, "timeZoneOffset") {
+		t.Errorf("Failed = %+v", report.Failed)
+	}
+}
@@ -0,0 +1,36 @@
+// Package ua parses, checks and generates browser user agent strings for
+// BitBrowser fingerprints whose UserAgent is set manually.
+//
+// A manual user agent must agree with the rest of the fingerprint: a
+// Chrome 130 profile on Windows that reports Firefox or macOS in its user
+// agent is easy to detect. Validate checks a string against the expected
+// browser, version and OS, and Generate builds the string a real browser
+// sends, including the frozen parts of Chrome's reduced user agent.
+//
+// # Usage
+//
+//	s, err := ua.Generate(ua.Options{Browser: ua.Chrome, Version: "130", OS: ua.Windows})
+//	// Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.0.0 Safari/537.36
+//
+//	agent, err := ua.Parse(s)
+//	fmt.Println(agent.Browser, agent.Major(), agent.OS) // chrome 130 Windows
+//
+//	err = ua.Validate(s, ua.Expect{Browser: ua.Chrome, Version: "131", OS: ua.Windows})
+//	// ua: version is 130.0.0.0, want 131
+//
+// bitbrowser.ValidateUserAgent runs Validate on a Fingerprint, and
+// bitbrowser.GenerateUserAgent builds a user agent from one.
+//
+// # Client Hints
+//
+// Chromium-based browsers also describe themselves in client hints:
+// navigator.userAgentData and the Sec-CH-UA headers. GenerateClientHints
+// returns the hints Chrome reports for a version and OS, including its
+// GREASE brand, and CheckClientHints compares hints with an Expect:
+//
+//	hints, err := ua.GenerateClientHints(ua.Options{Version: "130", OS: ua.MacOS})
+//	req.Header.Set("Sec-CH-UA", hints.Headers()["Sec-CH-UA"])
+//
+// bitbrowser.CheckClientHints reads the hints of a running browser and
+// checks them against its fingerprint.
+package ua
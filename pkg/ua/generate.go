@@ -0,0 +1,88 @@
+package ua
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Options selects the user agent Generate builds.
+type Options struct {
+	Browser Browser // Chrome or Firefox; default Chrome
+	Version string  // Major version such as "130", or a full version
+	OS      OS      // Default Windows
+
+	// OSVersion overrides the OS version, written as in the user agent:
+	// "10.0" for Windows, "10_15_7" for macOS, "14" for Android or "17_5"
+	// for iOS. The defaults are the values current browsers freeze:
+	// Windows 11 also reports "10.0" and Chrome reports macOS "10_15_7" and
+	// Android "10".
+	OSVersion string
+
+	// Device is the Android device model. The default is "K", which Chrome
+	// reports in place of the model since it reduced its user agent.
+	Device string
+}
+
+// Generate returns the user agent a real browser sends for opts. Chrome
+// versions are reduced to the major version, as Chrome reports them
+// ("130.0.0.0"), unless a full version is given.
+func Generate(opts Options) (string, error) {
+	if opts.Version == "" {
+		return "", fmt.Errorf("ua: version is required")
+	}
+	if opts.OS == "" {
+		opts.OS = Windows
+	}
+	major, _, _ := strings.Cut(opts.Version, ".")
+
+	switch opts.Browser {
+	case Chrome, "":
+		version := opts.Version
+		if !strings.Contains(version, ".") {
+			version += ".0.0.0"
+		}
+		const webkit = "AppleWebKit/537.36 (KHTML, like Gecko)"
+		switch opts.OS {
+		case Windows:
+			return fmt.Sprintf("Mozilla/5.0 (Windows NT %s; Win64; x64) %s Chrome/%s Safari/537.36",
+				or(opts.OSVersion, "10.0"), webkit, version), nil
+		case MacOS:
+			return fmt.Sprintf("Mozilla/5.0 (Macintosh; Intel Mac OS X %s) %s Chrome/%s Safari/537.36",
+				or(opts.OSVersion, "10_15_7"), webkit, version), nil
+		case Linux:
+			return fmt.Sprintf("Mozilla/5.0 (X11; Linux x86_64) %s Chrome/%s Safari/537.36", webkit, version), nil
+		case Android:
+			return fmt.Sprintf("Mozilla/5.0 (Linux; Android %s; %s) %s Chrome/%s Mobile Safari/537.36",
+				or(opts.OSVersion, "10"), or(opts.Device, "K"), webkit, version), nil
+		case IOS:
+			return fmt.Sprintf("Mozilla/5.0 (iPhone; CPU iPhone OS %s like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/%s Mobile/15E148 Safari/604.1",
+				or(opts.OSVersion, "17_5"), version), nil
+		}
+	case Firefox:
+		rv := major + ".0"
+		switch opts.OS {
+		case Windows:
+			return fmt.Sprintf("Mozilla/5.0 (Windows NT %s; Win64; x64; rv:%s) Gecko/20100101 Firefox/%s",
+				or(opts.OSVersion, "10.0"), rv, rv), nil
+		case MacOS:
+			return fmt.Sprintf("Mozilla/5.0 (Macintosh; Intel Mac OS X %s; rv:%s) Gecko/20100101 Firefox/%s",
+				or(opts.OSVersion, "10.15"), rv, rv), nil
+		case Linux:
+			return fmt.Sprintf("Mozilla/5.0 (X11; Linux x86_64; rv:%s) Gecko/20100101 Firefox/%s", rv, rv), nil
+		case Android:
+			return fmt.Sprintf("Mozilla/5.0 (Android %s; Mobile; rv:%s) Gecko/%s Firefox/%s",
+				or(opts.OSVersion, "14"), rv, rv, rv), nil
+		}
+	default:
+		return "", fmt.Errorf("ua: cannot generate a %s user agent", opts.Browser)
+	}
+	return "", fmt.Errorf("ua: cannot generate a %s user agent for %s", or(string(opts.Browser), string(Chrome)), opts.OS)
+}
+
+// or returns s, or def if s is empty.
+func or(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,134 @@
+package ua
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Brand is a browser brand and version in client hints.
+type Brand struct {
+	Brand   string `json:"brand"`
+	Version string `json:"version"`
+}
+
+// ClientHints are the user agent client hints of a Chromium-based browser:
+// navigator.userAgentData in pages and the Sec-CH-UA request headers.
+// Firefox and Safari do not send them.
+type ClientHints struct {
+	Brands          []Brand `json:"brands"` // Major versions
+	Mobile          bool    `json:"mobile"`
+	Platform        string  `json:"platform"`                  // e.g. "Windows" or "macOS"
+	PlatformVersion string  `json:"platformVersion,omitempty"` // High entropy
+	Model           string  `json:"model,omitempty"`           // High entropy, Android only
+	FullVersionList []Brand `json:"fullVersionList,omitempty"` // High entropy
+}
+
+// Headers returns the client hint headers browsers send with every
+// request, for HTTP clients that pose as the browser.
+//
+//	Sec-CH-UA: "Chromium";v="130", "Google Chrome";v="130", "Not?A_Brand";v="99"
+//	Sec-CH-UA-Mobile: ?0
+//	Sec-CH-UA-Platform: "Windows"
+func (h ClientHints) Headers() map[string]string {
+	brands := make([]string, len(h.Brands))
+	for i, b := range h.Brands {
+		brands[i] = strconv.Quote(b.Brand) + ";v=" + strconv.Quote(b.Version)
+	}
+	mobile := "?0"
+	if h.Mobile {
+		mobile = "?1"
+	}
+	return map[string]string{
+		"Sec-CH-UA":          strings.Join(brands, ", "),
+		"Sec-CH-UA-Mobile":   mobile,
+		"Sec-CH-UA-Platform": strconv.Quote(h.Platform),
+	}
+}
+
+// GenerateClientHints returns the low-entropy client hints Chrome reports
+// for opts: the Chromium, Google Chrome and GREASE brands in Chrome's
+// order for the version, the platform and whether it is mobile.
+func GenerateClientHints(opts Options) (ClientHints, error) {
+	if opts.Browser != Chrome && opts.Browser != "" {
+		return ClientHints{}, fmt.Errorf("ua: %s does not send client hints", opts.Browser)
+	}
+	if opts.OS == "" {
+		opts.OS = Windows
+	}
+	major, _, _ := strings.Cut(opts.Version, ".")
+	seed, err := strconv.Atoi(major)
+	if err != nil || seed <= 0 {
+		return ClientHints{}, fmt.Errorf("ua: invalid version %q", opts.Version)
+	}
+
+	// Chrome's GREASE brand and brand order are derived from the major
+	// version, so every Chrome of a version reports the same list.
+	const greaseChars = " (:-./);=?_"
+	greaseVersions := []string{"8", "99", "24"}
+	orders := [6][3]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+	grease := Brand{
+		Brand:   "Not" + string(greaseChars[seed%11]) + "A" + string(greaseChars[(seed+1)%11]) + "Brand",
+		Version: greaseVersions[seed%3],
+	}
+	order := orders[seed%6]
+	brands := make([]Brand, 3)
+	brands[order[0]] = grease
+	brands[order[1]] = Brand{Brand: "Chromium", Version: major}
+	brands[order[2]] = Brand{Brand: "Google Chrome", Version: major}
+
+	return ClientHints{
+		Brands:   brands,
+		Mobile:   opts.OS == Android || opts.OS == IOS,
+		Platform: string(opts.OS),
+	}, nil
+}
+
+// CheckClientHints checks client hints against want. Chrome must report a
+// Chromium or Google Chrome brand of the wanted major version, other
+// browsers no hints at all, and the platform and mobile flag must match
+// the OS. All mismatches are reported, joined into one error.
+func CheckClientHints(h ClientHints, want Expect) error {
+	var errs []error
+	switch want.Browser {
+	case Chrome:
+		brand := chromiumBrand(h.Brands)
+		if brand == nil {
+			errs = append(errs, fmt.Errorf("ua: client hints have no Chromium brand: %v", h.Brands))
+			break
+		}
+		wantMajor, _, _ := strings.Cut(want.Version, ".")
+		if wantMajor != "" && brand.Version != wantMajor {
+			errs = append(errs, fmt.Errorf("ua: client hint brand %s is version %s, want %s", brand.Brand, brand.Version, wantMajor))
+		}
+	case "":
+	default:
+		if len(h.Brands) > 0 {
+			errs = append(errs, fmt.Errorf("ua: client hints are reported, but %s does not send them", want.Browser))
+		}
+	}
+	if want.OS != "" && len(h.Brands) > 0 {
+		if h.Platform != string(want.OS) {
+			errs = append(errs, fmt.Errorf("ua: client hint platform is %q, want %q", h.Platform, string(want.OS)))
+		}
+		if mobile := want.OS == Android || want.OS == IOS; h.Mobile != mobile {
+			errs = append(errs, fmt.Errorf("ua: client hint mobile is %t, want %t", h.Mobile, mobile))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// chromiumBrand returns the Google Chrome or, failing that, Chromium brand.
+func chromiumBrand(brands []Brand) *Brand {
+	var chromium *Brand
+	for i, b := range brands {
+		switch b.Brand {
+		case "Google Chrome":
+			return &brands[i]
+		case "Chromium":
+			chromium = &brands[i]
+		}
+	}
+	return chromium
+}
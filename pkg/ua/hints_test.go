@@ -0,0 +1,62 @@
+package ua
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateClientHints(t *testing.T) {
+	// As reported by Chrome 130 and 131 on Windows.
+	tests := []struct {
+		version string
+		header  string
+	}{
+		{"130", `"Chromium";v="130", "Google Chrome";v="130", "Not?A_Brand";v="99"`},
+		{"131.0.6778.86", `"Google Chrome";v="131", "Chromium";v="131", "Not_A Brand";v="24"`},
+	}
+	for _, tt := range tests {
+		h, err := GenerateClientHints(Options{Version: tt.version})
+		if err != nil {
+			t.Fatalf("GenerateClientHints(%s) failed: %v", tt.version, err)
+		}
+		want := map[string]string{"Sec-CH-UA": tt.header, "Sec-CH-UA-Mobile": "?0", "Sec-CH-UA-Platform": `"Windows"`}
+		if got := h.Headers(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Headers() = %v, want %v", got, want)
+		}
+	}
+
+	h, err := GenerateClientHints(Options{Version: "130", OS: Android})
+	if err != nil || !h.Mobile || h.Platform != "Android" {
+		t.Errorf("Android hints = %+v, %v", h, err)
+	}
+	if _, err := GenerateClientHints(Options{Browser: Firefox, Version: "128"}); err == nil {
+		t.Error("expected an error for Firefox")
+	}
+	if _, err := GenerateClientHints(Options{}); err == nil {
+		t.Error("expected an error without a version")
+	}
+}
+
+func TestCheckClientHints(t *testing.T) {
+	h, _ := GenerateClientHints(Options{Version: "130", OS: MacOS})
+	if err := CheckClientHints(h, Expect{Browser: Chrome, Version: "130", OS: MacOS}); err != nil {
+		t.Errorf("CheckClientHints() = %v, want nil", err)
+	}
+	if err := CheckClientHints(ClientHints{}, Expect{Browser: Firefox, Version: "128", OS: Windows}); err != nil {
+		t.Errorf("Firefox without hints: %v", err)
+	}
+
+	err := CheckClientHints(h, Expect{Browser: Chrome, Version: "131", OS: Android})
+	for _, want := range []string{"version 130, want 131", `platform is "macOS", want "Android"`, "mobile is false, want true"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("CheckClientHints() = %v, want it to contain %q", err, want)
+		}
+	}
+	if err := CheckClientHints(ClientHints{}, Expect{Browser: Chrome}); err == nil {
+		t.Error("expected an error for Chrome without hints")
+	}
+	if err := CheckClientHints(h, Expect{Browser: Firefox}); err == nil {
+		t.Error("expected an error for Firefox with hints")
+	}
+}
@@ -0,0 +1,178 @@
+package ua
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnrecognized is returned by Parse for strings that are not the user
+// agent of a known browser.
+var ErrUnrecognized = errors.New("ua: unrecognized user agent")
+
+// Browser is a browser family. Chrome and Firefox match the values of
+// Fingerprint.CoreProduct.
+type Browser string
+
+// Browsers.
+const (
+	Chrome  Browser = "chrome" // Including Chromium-based browsers such as Edge
+	Firefox Browser = "firefox"
+	Safari  Browser = "safari"
+)
+
+// OS is an operating system.
+type OS string
+
+// Operating systems. The values are also the platforms of client hints.
+const (
+	Windows OS = "Windows"
+	MacOS   OS = "macOS"
+	Linux   OS = "Linux"
+	Android OS = "Android"
+	IOS     OS = "iOS"
+)
+
+// UserAgent is a parsed user agent string.
+type UserAgent struct {
+	Browser   Browser
+	Version   string // e.g. "130.0.0.0" or "128.0"
+	OS        OS
+	OSVersion string // As written, e.g. "10.0", "10_15_7" or "14"
+	Device    string // Android device model, e.g. "K" or "Pixel 8"
+	Mobile    bool
+}
+
+// Major returns the major browser version, or 0 if it is not a number.
+func (u UserAgent) Major() int {
+	major, _, _ := strings.Cut(u.Version, ".")
+	n, _ := strconv.Atoi(major)
+	return n
+}
+
+// Parse splits a user agent string into its components. It recognizes
+// Chrome, Firefox and Safari on Windows, macOS, Linux, Android and iOS.
+func Parse(s string) (UserAgent, error) {
+	rest, ok := strings.CutPrefix(s, "Mozilla/5.0 (")
+	if !ok {
+		return UserAgent{}, fmt.Errorf("%w: %q", ErrUnrecognized, s)
+	}
+	platform, rest, ok := strings.Cut(rest, ")")
+	if !ok {
+		return UserAgent{}, fmt.Errorf("%w: %q", ErrUnrecognized, s)
+	}
+
+	var u UserAgent
+	if !u.parsePlatform(platform) {
+		return UserAgent{}, fmt.Errorf("%w: unknown platform %q", ErrUnrecognized, platform)
+	}
+	switch {
+	case productVersion(rest, "Firefox/") != "":
+		u.Browser, u.Version = Firefox, productVersion(rest, "Firefox/")
+	case productVersion(rest, "FxiOS/") != "":
+		u.Browser, u.Version = Firefox, productVersion(rest, "FxiOS/")
+	case productVersion(rest, "CriOS/") != "":
+		u.Browser, u.Version = Chrome, productVersion(rest, "CriOS/")
+	case productVersion(rest, "Chrome/") != "":
+		u.Browser, u.Version = Chrome, productVersion(rest, "Chrome/")
+	case productVersion(rest, "Version/") != "" && strings.Contains(rest, "Safari/"):
+		u.Browser, u.Version = Safari, productVersion(rest, "Version/")
+	default:
+		return UserAgent{}, fmt.Errorf("%w: unknown browser in %q", ErrUnrecognized, s)
+	}
+	u.Mobile = strings.Contains(s, "Mobile")
+	return u, nil
+}
+
+// parsePlatform reads the parenthesized platform token of a user agent.
+func (u *UserAgent) parsePlatform(platform string) bool {
+	parts := strings.Split(platform, "; ")
+	switch {
+	case strings.HasPrefix(parts[0], "Windows NT "):
+		u.OS, u.OSVersion = Windows, strings.TrimPrefix(parts[0], "Windows NT ")
+	case parts[0] == "Macintosh" && len(parts) > 1:
+		u.OS, u.OSVersion = MacOS, strings.TrimPrefix(parts[1], "Intel Mac OS X ")
+	case parts[0] == "iPhone" || parts[0] == "iPad":
+		u.OS = IOS
+		if len(parts) > 1 {
+			version := strings.TrimSuffix(parts[1], " like Mac OS X")
+			u.OSVersion = version[strings.LastIndexByte(version, ' ')+1:]
+		}
+	case strings.HasPrefix(parts[0], "Android "): // Firefox
+		u.OS, u.OSVersion = Android, strings.TrimPrefix(parts[0], "Android ")
+	case parts[0] == "Linux" && len(parts) > 1 && strings.HasPrefix(parts[1], "Android"): // Chrome
+		u.OS, u.OSVersion = Android, strings.TrimSpace(strings.TrimPrefix(parts[1], "Android"))
+		if len(parts) > 2 && !strings.HasPrefix(parts[2], "rv:") {
+			u.Device = parts[2]
+		}
+	case parts[0] == "X11":
+		u.OS = Linux
+	default:
+		return false
+	}
+	return true
+}
+
+// productVersion returns the version following product in s, such as
+// "130.0.0.0" for "Chrome/".
+func productVersion(s, product string) string {
+	i := strings.Index(s, product)
+	if i < 0 {
+		return ""
+	}
+	version, _, _ := strings.Cut(s[i+len(product):], " ")
+	return version
+}
+
+// Expect lists what a user agent must match. Empty fields are not checked.
+type Expect struct {
+	Browser Browser
+	Version string // A major version such as "130" or a full one
+	OS      OS
+}
+
+// Validate parses s and checks it against want. All mismatches are
+// reported, joined into one error.
+func Validate(s string, want Expect) error {
+	u, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	if want.Browser != "" && u.Browser != want.Browser {
+		errs = append(errs, fmt.Errorf("ua: browser is %s, want %s", u.Browser, want.Browser))
+	}
+	if want.Version != "" {
+		got := u.Version
+		if !strings.Contains(want.Version, ".") {
+			got = strconv.Itoa(u.Major())
+		}
+		if got != want.Version {
+			errs = append(errs, fmt.Errorf("ua: version is %s, want %s", u.Version, want.Version))
+		}
+	}
+	if want.OS != "" && u.OS != want.OS {
+		errs = append(errs, fmt.Errorf("ua: OS is %s, want %s", u.OS, want.OS))
+	}
+	return errors.Join(errs...)
+}
+
+// PlatformOS returns the OS of a navigator.platform value as used in
+// Fingerprint.OS, such as "Win32" or "MacIntel", or "" if it is unknown.
+// "Linux armv81" and other ARM Linux platforms are Android.
+func PlatformOS(platform string) OS {
+	switch {
+	case strings.HasPrefix(platform, "Win"):
+		return Windows
+	case strings.HasPrefix(platform, "Mac"):
+		return MacOS
+	case platform == "iPhone" || platform == "iPad":
+		return IOS
+	case strings.HasPrefix(platform, "Linux arm"), strings.HasPrefix(platform, "Linux aarch"):
+		return Android
+	case strings.HasPrefix(platform, "Linux"):
+		return Linux
+	}
+	return ""
+}
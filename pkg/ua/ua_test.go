@@ -0,0 +1,139 @@
+package ua
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want UserAgent
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.0.0 Safari/537.36",
+			UserAgent{Browser: Chrome, Version: "130.0.0.0", OS: Windows, OSVersion: "10.0"},
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.0.0 Safari/537.36 Edg/130.0.0.0",
+			UserAgent{Browser: Chrome, Version: "130.0.0.0", OS: Windows, OSVersion: "10.0"},
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:128.0) Gecko/20100101 Firefox/128.0",
+			UserAgent{Browser: Firefox, Version: "128.0", OS: MacOS, OSVersion: "10.15"},
+		},
+		{
+			"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0",
+			UserAgent{Browser: Firefox, Version: "128.0", OS: Linux},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.6778.81 Mobile Safari/537.36",
+			UserAgent{Browser: Chrome, Version: "131.0.6778.81", OS: Android, OSVersion: "14", Device: "Pixel 8", Mobile: true},
+		},
+		{
+			"Mozilla/5.0 (Android 14; Mobile; rv:128.0) Gecko/128.0 Firefox/128.0",
+			UserAgent{Browser: Firefox, Version: "128.0", OS: Android, OSVersion: "14", Mobile: true},
+		},
+		{
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+			UserAgent{Browser: Safari, Version: "17.5", OS: IOS, OSVersion: "17_5", Mobile: true},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.s)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %+v, %v; want %+v", tt.s, got, err, tt.want)
+		}
+	}
+
+	for _, s := range []string{"", "curl/8.5.0", "Mozilla/5.0 (Windows NT 10.0) Unknown/1.0", "Mozilla/5.0 (PlayStation 5) Chrome/1"} {
+		if _, err := Parse(s); !errors.Is(err, ErrUnrecognized) {
+			t.Errorf("Parse(%q) error = %v, want ErrUnrecognized", s, err)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		opts Options
+		want string
+	}{
+		{
+			Options{Version: "130"},
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.0.0 Safari/537.36",
+		},
+		{
+			Options{Browser: Chrome, Version: "130.0.6723.92", OS: MacOS},
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.6723.92 Safari/537.36",
+		},
+		{
+			Options{Browser: Chrome, Version: "130", OS: Android},
+			"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.0.0 Mobile Safari/537.36",
+		},
+		{
+			Options{Browser: Firefox, Version: "128", OS: Linux},
+			"Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0",
+		},
+	}
+	for _, tt := range tests {
+		got, err := Generate(tt.opts)
+		if err != nil || got != tt.want {
+			t.Errorf("Generate(%+v) = %q, %v; want %q", tt.opts, got, err, tt.want)
+		}
+	}
+
+	// Every generated user agent parses back to what was asked for.
+	for _, b := range []Browser{Chrome, Firefox} {
+		for _, os := range []OS{Windows, MacOS, Linux, Android, IOS} {
+			s, err := Generate(Options{Browser: b, Version: "128", OS: os})
+			if b == Firefox && os == IOS {
+				if err == nil {
+					t.Errorf("Generate(firefox, iOS) = %q, want an error", s)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("Generate(%s, %s) failed: %v", b, os, err)
+			}
+			if err := Validate(s, Expect{Browser: b, Version: "128", OS: os}); err != nil {
+				t.Errorf("Validate(%q) = %v", s, err)
+			}
+		}
+	}
+
+	if _, err := Generate(Options{OS: Windows}); err == nil {
+		t.Error("expected an error without a version")
+	}
+	if _, err := Generate(Options{Browser: Safari, Version: "17"}); err == nil {
+		t.Error("expected an error for Safari")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/130.0.0.0 Safari/537.36"
+	if err := Validate(s, Expect{Browser: Chrome, Version: "130.0.0.0", OS: Windows}); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	if err := Validate(s, Expect{}); err != nil {
+		t.Errorf("Validate() with no expectations = %v, want nil", err)
+	}
+
+	err := Validate(s, Expect{Browser: Firefox, Version: "131", OS: MacOS})
+	for _, want := range []string{"browser is chrome, want firefox", "version is 130.0.0.0, want 131", "OS is Windows, want macOS"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestPlatformOS(t *testing.T) {
+	for platform, want := range map[string]OS{
+		"Win32": Windows, "MacIntel": MacOS, "Linux x86_64": Linux,
+		"Linux armv81": Android, "iPhone": IOS, "PlayStation": "",
+	} {
+		if got := PlatformOS(platform); got != want {
+			t.Errorf("PlatformOS(%q) = %q, want %q", platform, got, want)
+		}
+	}
+}
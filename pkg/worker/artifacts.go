@@ -0,0 +1,213 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/lpg-it/go-antidetect/pkg/cdp"
+)
+
+// Content types of common artifacts.
+const (
+	ContentTypePNG      = "image/png"
+	ContentTypeJSON     = "application/json"
+	ContentTypeHAR      = "application/har+json"
+	ContentTypeWebM     = "video/webm"
+	ContentTypeMP4      = "video/mp4"
+	ContentTypeText     = "text/plain; charset=utf-8"
+	ContentTypeMinidump = "application/x-dmp"
+)
+
+// ErrNoArtifactStore is returned by SaveArtifact when the worker has no
+// ArtifactStore (see WithArtifactStore).
+var ErrNoArtifactStore = errors.New("worker: no artifact store configured")
+
+// ArtifactStore stores files produced by jobs, such as screenshots, HARs
+// and exported cookies.
+type ArtifactStore interface {
+	// Put stores data under key, a slash-separated relative path, and
+	// returns a URL where it can be retrieved.
+	Put(ctx context.Context, key, contentType string, data io.Reader) (string, error)
+}
+
+// DirStore is an ArtifactStore that writes files below a local directory.
+type DirStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewDirStore creates a DirStore writing below dir. Returned URLs are
+// baseURL joined with the key, e.g. when dir is served over HTTP, or
+// file:// URLs if baseURL is empty.
+func NewDirStore(dir, baseURL string) *DirStore {
+	return &DirStore{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Put implements ArtifactStore.
+func (s *DirStore) Put(ctx context.Context, key, contentType string, data io.Reader) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("worker: invalid artifact key %q", key)
+	}
+	name := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return "", fmt.Errorf("worker: store artifact failed: %w", err)
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return "", fmt.Errorf("worker: store artifact failed: %w", err)
+	}
+	if _, err := io.Copy(f, data); err != nil {
+		f.Close()
+		return "", fmt.Errorf("worker: store artifact failed: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("worker: store artifact failed: %w", err)
+	}
+
+	if s.baseURL != "" {
+		return s.baseURL + "/" + escapeKey(key), nil
+	}
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String(), nil
+}
+
+// artifactsKey is the context key of the running job's artifacts.
+type artifactsKey struct{}
+
+// artifacts collects the artifacts saved during one attempt of a job.
+type artifacts struct {
+	store  ArtifactStore
+	prefix string
+
+	mu   sync.Mutex
+	urls map[string]string // Artifact name -> URL
+}
+
+// saved returns a copy of the saved artifact URLs, or nil if there are none.
+func (a *artifacts) saved() map[string]string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.urls) == 0 {
+		return nil
+	}
+	urls := make(map[string]string, len(a.urls))
+	for name, u := range a.urls {
+		urls[name] = u
+	}
+	return urls
+}
+
+// withArtifacts attaches an artifact collector for one attempt of a job.
+func withArtifacts(ctx context.Context, store ArtifactStore, job Job, attempt int) (context.Context, *artifacts) {
+	a := &artifacts{
+		store:  store,
+		prefix: path.Join(job.ID, "attempt-"+strconv.Itoa(attempt)),
+		urls:   make(map[string]string),
+	}
+	return context.WithValue(ctx, artifactsKey{}, a), a
+}
+
+// SaveArtifact stores data for the running job under name (e.g.
+// "screenshot.png") and returns its URL. The URL is also reported in the
+// job's Result.Artifacts. ctx must be the context passed to the Handler.
+//
+// Artifacts are stored under "<job ID>/attempt-<n>/<name>", so retries do
+// not overwrite earlier attempts.
+func SaveArtifact(ctx context.Context, name, contentType string, data []byte) (string, error) {
+	a, _ := ctx.Value(artifactsKey{}).(*artifacts)
+	if a == nil || a.store == nil {
+		return "", ErrNoArtifactStore
+	}
+	if name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("worker: invalid artifact name %q", name)
+	}
+
+	u, err := a.store.Put(ctx, path.Join(a.prefix, name), contentType, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	a.mu.Lock()
+	a.urls[name] = u
+	a.mu.Unlock()
+	return u, nil
+}
+
+// SaveScreenshot captures the page attached to session as PNG and stores
+// it as an artifact named name.
+// Page.captureScreenshot
+func SaveScreenshot(ctx context.Context, session *cdp.Session, name string) (string, error) {
+	var result struct {
+		Data string `json:"data"`
+	}
+	if err := session.Call(ctx, "Page.captureScreenshot", map[string]string{"format": "png"}, &result); err != nil {
+		return "", err
+	}
+	png, err := base64.StdEncoding.DecodeString(result.Data)
+	if err != nil {
+		return "", fmt.Errorf("worker: decode screenshot failed: %w", err)
+	}
+	return SaveArtifact(ctx, name, ContentTypePNG, png)
+}
+
+// SaveCookies exports all cookies of the browser behind conn as JSON and
+// stores them as an artifact named name.
+// Storage.getCookies
+func SaveCookies(ctx context.Context, conn *cdp.Conn, name string) (string, error) {
+	var result struct {
+		Cookies json.RawMessage `json:"cookies"`
+	}
+	if err := conn.Call(ctx, "Storage.getCookies", nil, &result); err != nil {
+		return "", err
+	}
+	return SaveArtifact(ctx, name, ContentTypeJSON, result.Cookies)
+}
+
+// SaveScreencast encodes the frames of a stopped screencast into a video
+// with ffmpeg and stores it as an artifact named name. The extension of
+// name selects the format: ".webm" or ".mp4".
+func SaveScreencast(ctx context.Context, sc *cdp.Screencast, name string) (string, error) {
+	contentType := ContentTypeWebM
+	if strings.EqualFold(path.Ext(name), ".mp4") {
+		contentType = ContentTypeMP4
+	}
+	dir, err := os.MkdirTemp("", "screencast-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	video := filepath.Join(dir, path.Base(name))
+	if err := sc.EncodeVideo(ctx, video); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(video)
+	if err != nil {
+		return "", err
+	}
+	return SaveArtifact(ctx, name, contentType, data)
+}
+
+// escapeKey escapes each segment of a slash-separated key for use in a URL.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+// ErrBrowserCrashed marks a failed attempt whose browser was no longer
+// running when the handler returned.
+var ErrBrowserCrashed = errors.New("worker: browser crashed")
+
+// CrashReporter is implemented by Browsers that can collect the logs of a
+// browser that died, such as a *bitbrowser.Client configured with
+// bitbrowser.WithCrashLogs. CrashLogs returns nil while the browser is
+// still running.
+type CrashReporter interface {
+	CrashLogs(ctx context.Context, id string) (*bitbrowser.CrashLogs, error)
+}
+
+// checkCrash is called when an attempt failed, before its browser is
+// closed. If the browser died, its Chrome log and crash dumps are saved as
+// artifacts and err is marked with ErrBrowserCrashed.
+func (w *Worker) checkCrash(ctx context.Context, job Job, err error) error {
+	reporter, ok := w.browsers.(CrashReporter)
+	if !ok {
+		return err
+	}
+	// The attempt may have timed out; keep its values for SaveArtifact
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultCloseTimeout)
+	defer cancel()
+
+	logs, crashErr := reporter.CrashLogs(ctx, job.ProfileID)
+	if crashErr != nil {
+		w.log(slog.LevelWarn, "Collect crash logs failed", "job", job.ID, "profile", job.ProfileID, "error", crashErr)
+		return err
+	}
+	if logs == nil {
+		return err // Still running
+	}
+
+	saveErr := func() error {
+		if len(logs.Log) > 0 {
+			if _, err := SaveArtifact(ctx, "chrome_debug.log", ContentTypeText, logs.Log); err != nil {
+				return err
+			}
+		}
+		for _, dump := range logs.Dumps {
+			if dump.Data == nil {
+				continue // Too large to collect
+			}
+			if _, err := SaveArtifact(ctx, dump.Name, ContentTypeMinidump, dump.Data); err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	if saveErr != nil {
+		w.log(slog.LevelWarn, "Browser crashed", "job", job.ID, "profile", job.ProfileID,
+			"log", string(lastLines(logs.Log, 20)), "dumps", len(logs.Dumps), "error", saveErr)
+	}
+	return fmt.Errorf("%w: %w", ErrBrowserCrashed, err)
+}
+
+// lastLines returns the last n lines of data.
+func lastLines(data []byte, n int) []byte {
+	data = bytes.TrimRight(data, "\n")
+	for i := len(data) - 1; i >= 0; i-- {
+		if data[i] == '\n' {
+			if n--; n == 0 {
+				return data[i+1:]
+			}
+		}
+	}
+	return data
+}
@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+// crashingBrowsers is a fakeBrowsers whose browsers die when a handler
+// fails.
+type crashingBrowsers struct {
+	*fakeBrowsers
+	logs *bitbrowser.CrashLogs
+}
+
+func (b *crashingBrowsers) CrashLogs(ctx context.Context, id string) (*bitbrowser.CrashLogs, error) {
+	return b.logs, nil
+}
+
+func TestWorker_CrashLogs(t *testing.T) {
+	failing := func(ctx context.Context, job Job, browser *bitbrowser.OpenResult) (json.RawMessage, error) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	t.Run("saves logs of a crashed browser", func(t *testing.T) {
+		dir := t.TempDir()
+		browsers := &crashingBrowsers{fakeBrowsers: newFakeBrowsers(), logs: &bitbrowser.CrashLogs{
+			Log:   []byte("[ERROR:gpu_process_host.cc] GPU process exited unexpectedly\n"),
+			Dumps: []bitbrowser.CrashDump{{Name: "1a2b.dmp", Data: []byte("MDMP")}, {Name: "huge.dmp"}},
+		}}
+		r := runJobs(t, browsers, failing, []Job{{ID: "j1", ProfileID: "p1"}},
+			WithMaxAttempts(1), WithArtifactStore(NewDirStore(dir, "")))["j1"]
+
+		if r.Success || !strings.Contains(r.Error, "browser crashed") {
+			t.Errorf("result error = %q, want a crash", r.Error)
+		}
+		for _, name := range []string{"chrome_debug.log", "1a2b.dmp"} {
+			if r.Artifacts[name] == "" {
+				t.Errorf("artifact %s missing from %v", name, r.Artifacts)
+			}
+		}
+		if _, ok := r.Artifacts["huge.dmp"]; ok {
+			t.Error("a dump without data was saved")
+		}
+		if _, err := os.Stat(filepath.Join(dir, "j1", "attempt-1", "chrome_debug.log")); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("running browsers are not reported", func(t *testing.T) {
+		browsers := &crashingBrowsers{fakeBrowsers: newFakeBrowsers()}
+		r := runJobs(t, browsers, failing, []Job{{ID: "j1", ProfileID: "p1"}}, WithMaxAttempts(1))["j1"]
+		if strings.Contains(r.Error, "browser crashed") || len(r.Artifacts) != 0 {
+			t.Errorf("result = %+v, want a plain failure", r)
+		}
+	})
+}
+
+func TestCheckCrash(t *testing.T) {
+	w := New(nil, &crashingBrowsers{fakeBrowsers: newFakeBrowsers(), logs: &bitbrowser.CrashLogs{}}, nil)
+	err := w.checkCrash(context.Background(), Job{ID: "j1", ProfileID: "p1"}, io.EOF)
+	if !errors.Is(err, ErrBrowserCrashed) || !errors.Is(err, io.EOF) {
+		t.Errorf("err = %v, want ErrBrowserCrashed and io.EOF", err)
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	if got := string(lastLines([]byte("a\nb\nc\n"), 2)); got != "b\nc" {
+		t.Errorf("lastLines = %q", got)
+	}
+	if got := string(lastLines([]byte("a"), 2)); got != "a" {
+		t.Errorf("lastLines = %q", got)
+	}
+}
@@ -0,0 +1,76 @@
+// Package worker runs browser jobs taken from a message queue.
+//
+// Each job names a profile. The worker opens the profile's browser, calls a
+// Handler with the connection info, closes the browser again and publishes
+// a Result. Jobs are bounded by a timeout and retried on failure.
+//
+// # Usage
+//
+//	w := worker.New(queue, client, func(ctx context.Context, job worker.Job, browser *bitbrowser.OpenResult) (json.RawMessage, error) {
+//	    conn, err := cdp.Dial(ctx, browser.Ws)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    defer conn.Close()
+//	    // ...
+//	    return json.Marshal(map[string]string{"status": "done"})
+//	}, worker.WithConcurrency(4), worker.WithJobTimeout(5*time.Minute))
+//
+//	err := w.Run(ctx) // until ctx is canceled
+//
+// # Artifacts
+//
+// With WithArtifactStore, handlers can store screenshots, HARs, exported
+// cookies, session videos and other files with SaveArtifact,
+// SaveScreenshot, SaveCookies and SaveScreencast.
+// The returned URLs are listed in Result.Artifacts. DirStore writes to a
+// local directory and S3Store uploads to S3 or an S3-compatible service.
+//
+//	store, err := worker.NewS3Store(worker.S3Config{
+//	    Bucket: "job-artifacts", Region: "us-east-1",
+//	    AccessKeyID: id, SecretAccessKey: secret,
+//	})
+//	w := worker.New(queue, client, handler, worker.WithArtifactStore(store))
+//
+//	// In the handler:
+//	url, err := worker.SaveScreenshot(ctx, session, "final.png")
+//
+// # Crashes
+//
+// When a job fails and its browser is no longer running, a worker whose
+// Browsers implement CrashReporter (a *bitbrowser.Client configured with
+// bitbrowser.WithCrashLogs) saves the browser's chrome_debug.log and crash
+// dumps as artifacts of the attempt, and the error matches
+// ErrBrowserCrashed.
+//
+// # Headful on Failure
+//
+// WithHeadfulOnFailure runs jobs headless but reopens the profile with a
+// visible window once a job has failed a number of times, so an operator
+// can solve a CAPTCHA or verification step. Handlers check Headful:
+//
+//	worker.WithHeadfulOnFailure(worker.HeadfulPolicy{AfterFailures: 2, Timeout: 15 * time.Minute})
+//
+// # Queues
+//
+// The broker is reached through the Queue interface: Receive returns the
+// next Delivery, whose Ack and Nack settle it, and PublishResult sends job
+// results back. MemoryQueue is an in-process implementation.
+//
+// NATSQueue pulls jobs from a NATS JetStream consumer and maps Ack and Nack
+// to JetStream acknowledgements. KafkaQueue consumes a Kafka topic through
+// the Kafka REST Proxy and commits offsets as jobs are settled. Both speak
+// their protocol with the standard library, so the module keeps no other
+// dependencies:
+//
+//	queue, err := worker.DialNATS(ctx, worker.NATSConfig{
+//	    URL:    "nats://10.0.0.2:4222",
+//	    Stream: "JOBS", Consumer: "browsers", ResultSubject: "jobs.results",
+//	})
+//
+// Other brokers implement Queue in the same way.
+//
+// Job bodies are JSON:
+//
+//	{"id": "job-1", "profileId": "abc123", "payload": {...}, "timeoutMs": 60000}
+package worker
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+// HeadfulPolicy reopens a failing job's profile with a visible window, so
+// that an operator can step in, e.g. to solve a CAPTCHA or a verification
+// step. The profile keeps its cookies and storage between attempts.
+type HeadfulPolicy struct {
+	// AfterFailures is how many failed attempts switch the job to headful.
+	// It must be below the worker's max attempts to have an effect; 0
+	// disables the policy.
+	AfterFailures int
+
+	// Timeout replaces the job timeout for headful attempts, leaving time
+	// for manual intervention. 0 keeps the job timeout.
+	Timeout time.Duration
+}
+
+// WithHeadfulOnFailure runs jobs with the configured open options
+// (typically headless) and switches to headful once a job has failed
+// policy.AfterFailures times. Handlers can check Headful to, for example,
+// wait for an operator instead of failing on a CAPTCHA.
+//
+//	w := worker.New(queue, client, handler,
+//	    worker.WithOpenOptions(&bitbrowser.OpenOptions{Headless: true}),
+//	    worker.WithMaxAttempts(3),
+//	    worker.WithHeadfulOnFailure(worker.HeadfulPolicy{AfterFailures: 2, Timeout: 15 * time.Minute}),
+//	)
+func WithHeadfulOnFailure(policy HeadfulPolicy) Option {
+	return func(w *Worker) {
+		w.headful = policy
+	}
+}
+
+type headfulKey struct{}
+
+// Headful reports whether the running attempt was opened headful by a
+// HeadfulPolicy. ctx must be the context passed to the Handler.
+func Headful(ctx context.Context) bool {
+	headful, _ := ctx.Value(headfulKey{}).(bool)
+	return headful
+}
+
+// headfulAttempt reports whether attempt n of a job runs headful.
+func (w *Worker) headfulAttempt(n int) bool {
+	return w.headful.AfterFailures > 0 && n > w.headful.AfterFailures
+}
+
+// headfulOptions returns the worker's open options with Headless off.
+func (w *Worker) headfulOptions() *bitbrowser.OpenOptions {
+	opts := bitbrowser.OpenOptions{}
+	if w.openOptions != nil {
+		opts = *w.openOptions
+	}
+	opts.Headless = false
+	return &opts
+}
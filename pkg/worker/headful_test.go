@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+// optionsBrowsers records the options of every Open.
+type optionsBrowsers struct {
+	*fakeBrowsers
+	mu       sync.Mutex
+	headless []bool
+}
+
+func (b *optionsBrowsers) Open(ctx context.Context, id string, opts *bitbrowser.OpenOptions) (*bitbrowser.OpenResult, error) {
+	b.mu.Lock()
+	b.headless = append(b.headless, opts != nil && opts.Headless)
+	b.mu.Unlock()
+	return b.fakeBrowsers.Open(ctx, id, opts)
+}
+
+func TestWithHeadfulOnFailure(t *testing.T) {
+	browsers := &optionsBrowsers{fakeBrowsers: newFakeBrowsers()}
+	var headful []bool
+	handler := func(ctx context.Context, job Job, browser *bitbrowser.OpenResult) (json.RawMessage, error) {
+		headful = append(headful, Headful(ctx))
+		if !Headful(ctx) {
+			return nil, errors.New("captcha")
+		}
+		return json.RawMessage(`"solved"`), nil
+	}
+
+	r := runJobs(t, browsers, handler, []Job{{ID: "j1", ProfileID: "p1"}},
+		WithRetryDelay(0), WithMaxAttempts(4),
+		WithOpenOptions(&bitbrowser.OpenOptions{Headless: true, IgnoreDefaultUrls: true}),
+		WithHeadfulOnFailure(HeadfulPolicy{AfterFailures: 2}))["j1"]
+
+	if !r.Success || r.Attempts != 3 {
+		t.Fatalf("result = %+v, want success on attempt 3", r)
+	}
+	if want := []bool{true, true, false}; !slices.Equal(browsers.headless, want) {
+		t.Errorf("headless opens = %v, want %v", browsers.headless, want)
+	}
+	if want := []bool{false, false, true}; !slices.Equal(headful, want) {
+		t.Errorf("Headful(ctx) = %v, want %v", headful, want)
+	}
+}
+
+func TestHeadfulOptionsKeepOthers(t *testing.T) {
+	w := New(nil, nil, nil, WithOpenOptions(&bitbrowser.OpenOptions{Headless: true, Incognito: true}))
+	opts := w.headfulOptions()
+	if opts.Headless || !opts.Incognito {
+		t.Errorf("headfulOptions() = %+v", opts)
+	}
+	if !w.openOptions.Headless {
+		t.Error("headfulOptions modified the worker's options")
+	}
+}
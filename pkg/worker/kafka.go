@@ -0,0 +1,265 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultKafkaFetchTimeout is the default of KafkaConfig.FetchTimeout.
+const DefaultKafkaFetchTimeout = 5 * time.Second
+
+// kafkaContentType is the REST Proxy v2 media type with base64 values.
+const kafkaContentType = "application/vnd.kafka.binary.v2+json"
+
+// KafkaConfig configures a KafkaQueue.
+type KafkaConfig struct {
+	// RESTURL is the Kafka REST Proxy (API v2), e.g. "http://10.0.0.2:8082".
+	RESTURL string
+
+	// Topic holds jobs, read by the consumer group Group. ResultTopic
+	// receives results.
+	Topic       string
+	Group       string
+	ResultTopic string
+
+	// Instance names this worker's consumer instance in the group
+	// (default: a random name).
+	Instance string
+
+	// FetchTimeout is how long one fetch waits for jobs
+	// (default: DefaultKafkaFetchTimeout).
+	FetchTimeout time.Duration
+
+	// Username and Password authenticate with HTTP basic auth if set.
+	Username string
+	Password string
+
+	// HTTPClient sends the requests. Default: http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// KafkaQueue is a Queue that consumes jobs from a Kafka topic and produces
+// results to another, through the Kafka REST Proxy so that no Kafka client
+// library is needed. Ack commits the job's offset once every earlier job of
+// its partition is settled too, so a crash redelivers unsettled jobs
+// rather than skipping them. Kafka cannot return a message to a topic, so
+// Nack produces the job to the end of Topic again and then settles it.
+type KafkaQueue struct {
+	config   KafkaConfig
+	base     string // REST Proxy URL without a trailing slash
+	instance string // Consumer instance URL
+
+	fetchMu  sync.Mutex
+	buffered []kafkaRecord
+
+	mu      sync.Mutex
+	pending map[int][]*kafkaDelivery // Unsettled or uncommitted jobs by partition, in offset order
+}
+
+// kafkaRecord is a record returned by the REST Proxy.
+type kafkaRecord struct {
+	Topic     string `json:"topic"`
+	Value     []byte `json:"value"`
+	Partition int    `json:"partition"`
+	Offset    int64  `json:"offset"`
+}
+
+// NewKafkaQueue creates a consumer instance in the REST Proxy, subscribed
+// to config.Topic. Call Close to remove it.
+//
+// Example:
+//
+//	queue, err := worker.NewKafkaQueue(ctx, worker.KafkaConfig{
+//	    RESTURL: "http://10.0.0.2:8082",
+//	    Topic:   "browser-jobs", Group: "browsers", ResultTopic: "browser-results",
+//	})
+//	defer queue.Close(context.Background())
+//	err = worker.New(queue, client, handler).Run(ctx)
+func NewKafkaQueue(ctx context.Context, config KafkaConfig) (*KafkaQueue, error) {
+	if config.Topic == "" || config.Group == "" || config.ResultTopic == "" {
+		return nil, fmt.Errorf("worker: Kafka topic, group and result topic are required")
+	}
+	if u, err := url.Parse(config.RESTURL); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("worker: invalid Kafka REST Proxy URL %q", config.RESTURL)
+	}
+	if config.Instance == "" {
+		id := make([]byte, 8)
+		rand.Read(id)
+		config.Instance = "worker-" + hex.EncodeToString(id)
+	}
+	if config.FetchTimeout <= 0 {
+		config.FetchTimeout = DefaultKafkaFetchTimeout
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+
+	base := strings.TrimRight(config.RESTURL, "/")
+	q := &KafkaQueue{
+		config:   config,
+		base:     base,
+		instance: base + "/consumers/" + url.PathEscape(config.Group) + "/instances/" + url.PathEscape(config.Instance),
+		pending:  make(map[int][]*kafkaDelivery),
+	}
+	create := map[string]string{
+		"name":               config.Instance,
+		"format":             "binary",
+		"auto.offset.reset":  "earliest",
+		"auto.commit.enable": "false",
+	}
+	if err := q.do(ctx, http.MethodPost, base+"/consumers/"+url.PathEscape(config.Group), create, nil); err != nil {
+		return nil, fmt.Errorf("worker: Kafka consumer create failed: %w", err)
+	}
+	subscribe := map[string][]string{"topics": {config.Topic}}
+	if err := q.do(ctx, http.MethodPost, q.instance+"/subscription", subscribe, nil); err != nil {
+		q.Close(ctx)
+		return nil, fmt.Errorf("worker: Kafka subscribe failed: %w", err)
+	}
+	return q, nil
+}
+
+// Receive implements Queue. It fetches records until a job arrives or ctx
+// is done.
+func (q *KafkaQueue) Receive(ctx context.Context) (Delivery, error) {
+	q.fetchMu.Lock()
+	defer q.fetchMu.Unlock()
+	for len(q.buffered) == 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		timeout := strconv.FormatInt(q.config.FetchTimeout.Milliseconds(), 10)
+		if err := q.do(ctx, http.MethodGet, q.instance+"/records?timeout="+timeout, nil, &q.buffered); err != nil {
+			return nil, fmt.Errorf("worker: Kafka fetch failed: %w", err)
+		}
+	}
+	record := q.buffered[0]
+	q.buffered = q.buffered[1:]
+
+	d := &kafkaDelivery{queue: q, record: record}
+	q.mu.Lock()
+	q.pending[record.Partition] = append(q.pending[record.Partition], d)
+	q.mu.Unlock()
+	return d, nil
+}
+
+// PublishResult implements Queue.
+func (q *KafkaQueue) PublishResult(ctx context.Context, result Result) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	if err := q.produce(ctx, q.config.ResultTopic, data); err != nil {
+		return fmt.Errorf("worker: Kafka publish result failed: %w", err)
+	}
+	return nil
+}
+
+// Close removes the consumer instance from the REST Proxy. Jobs received
+// but not committed are redelivered to the group.
+func (q *KafkaQueue) Close(ctx context.Context) error {
+	if err := q.do(ctx, http.MethodDelete, q.instance, nil, nil); err != nil {
+		return fmt.Errorf("worker: Kafka consumer delete failed: %w", err)
+	}
+	return nil
+}
+
+// produce sends one record to topic.
+func (q *KafkaQueue) produce(ctx context.Context, topic string, value []byte) error {
+	records := map[string]any{"records": []map[string][]byte{{"value": value}}}
+	return q.do(ctx, http.MethodPost, q.base+"/topics/"+url.PathEscape(topic), records, nil)
+}
+
+// settle marks d as settled and commits the offset of the last job of its
+// partition before which every job is settled.
+func (q *KafkaQueue) settle(ctx context.Context, d *kafkaDelivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	d.settled = true
+	pending := q.pending[d.record.Partition]
+	n := 0
+	for n < len(pending) && pending[n].settled {
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	last := pending[n-1].record
+	commit := map[string]any{"offsets": []map[string]any{{
+		"topic": last.Topic, "partition": last.Partition, "offset": last.Offset,
+	}}}
+	if err := q.do(ctx, http.MethodPost, q.instance+"/offsets", commit, nil); err != nil {
+		return fmt.Errorf("worker: Kafka commit failed: %w", err)
+	}
+	q.pending[d.record.Partition] = pending[n:]
+	return nil
+}
+
+// do sends a REST Proxy request and decodes the response into out, if set.
+func (q *KafkaQueue) do(ctx context.Context, method, url string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", kafkaContentType)
+	if in != nil {
+		req.Header.Set("Content-Type", kafkaContentType)
+	}
+	if q.config.Username != "" {
+		req.SetBasicAuth(q.config.Username, q.config.Password)
+	}
+
+	resp, err := q.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// kafkaDelivery is a job received from a KafkaQueue.
+type kafkaDelivery struct {
+	queue   *KafkaQueue
+	record  kafkaRecord
+	settled bool // Guarded by queue.mu
+}
+
+func (d *kafkaDelivery) Body() []byte {
+	return d.record.Value
+}
+
+func (d *kafkaDelivery) Ack(ctx context.Context) error {
+	return d.queue.settle(ctx, d)
+}
+
+func (d *kafkaDelivery) Nack(ctx context.Context) error {
+	if err := d.queue.produce(ctx, d.record.Topic, d.record.Value); err != nil {
+		return fmt.Errorf("worker: Kafka requeue failed: %w", err)
+	}
+	return d.queue.settle(ctx, d)
+}
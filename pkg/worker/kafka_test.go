@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeKafkaREST is a Kafka REST Proxy serving records once and recording
+// other requests as "METHOD path body".
+type fakeKafkaREST struct {
+	mu       sync.Mutex
+	records  []kafkaRecord
+	requests []string
+}
+
+func (s *fakeKafkaREST) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if strings.HasSuffix(r.URL.Path, "/records") {
+		json.NewEncoder(w).Encode(s.records)
+		s.records = nil
+		return
+	}
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path+" "+string(body))
+	if r.Method == http.MethodPost && r.URL.Path == "/consumers/browsers" {
+		w.Write([]byte(`{"instance_id":"w1","base_uri":"http://proxy/consumers/browsers/instances/w1"}`))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *fakeKafkaREST) requestsMatching(prefix string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matching []string
+	for _, r := range s.requests {
+		if strings.HasPrefix(r, prefix) {
+			matching = append(matching, r)
+		}
+	}
+	return matching
+}
+
+func TestKafkaQueue(t *testing.T) {
+	proxy := &fakeKafkaREST{records: []kafkaRecord{
+		{Topic: "jobs", Partition: 0, Offset: 7, Value: []byte(`{"id":"job-1","profileId":"p1"}`)},
+		{Topic: "jobs", Partition: 0, Offset: 8, Value: []byte(`{"id":"job-2","profileId":"p2"}`)},
+	}}
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+	ctx := context.Background()
+
+	queue, err := NewKafkaQueue(ctx, KafkaConfig{RESTURL: server.URL, Topic: "jobs", Group: "browsers", ResultTopic: "results", Instance: "w1"})
+	if err != nil {
+		t.Fatalf("NewKafkaQueue failed: %v", err)
+	}
+	if subs := proxy.requestsMatching("POST /consumers/browsers/instances/w1/subscription"); len(subs) != 1 || !strings.Contains(subs[0], `["jobs"]`) {
+		t.Errorf("subscriptions = %v", subs)
+	}
+
+	first, err := queue.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	second, _ := queue.Receive(ctx)
+	if !strings.Contains(string(second.Body()), "job-2") {
+		t.Fatalf("second body = %s", second.Body())
+	}
+
+	// job-2 is not committed before job-1 is settled
+	second.Ack(ctx)
+	if commits := proxy.requestsMatching("POST /consumers/browsers/instances/w1/offsets"); len(commits) != 0 {
+		t.Errorf("committed before job-1 was settled: %v", commits)
+	}
+	if err := first.Nack(ctx); err != nil {
+		t.Fatalf("Nack failed: %v", err)
+	}
+	commits := proxy.requestsMatching("POST /consumers/browsers/instances/w1/offsets")
+	if len(commits) != 1 || !strings.Contains(commits[0], `"offset":8`) {
+		t.Errorf("commits = %v", commits)
+	}
+	if requeued := proxy.requestsMatching("POST /topics/jobs"); len(requeued) != 1 {
+		t.Errorf("requeued = %v", requeued)
+	}
+
+	if err := queue.PublishResult(ctx, Result{JobID: "job-2", Success: true}); err != nil {
+		t.Fatalf("PublishResult failed: %v", err)
+	}
+	if results := proxy.requestsMatching("POST /topics/results"); len(results) != 1 {
+		t.Errorf("results = %v", results)
+	}
+	if err := queue.Close(ctx); err != nil || len(proxy.requestsMatching("DELETE /consumers/browsers/instances/w1")) != 1 {
+		t.Errorf("Close() = %v", err)
+	}
+}
+
+func TestNewKafkaQueueValidation(t *testing.T) {
+	if _, err := NewKafkaQueue(context.Background(), KafkaConfig{RESTURL: "http://127.0.0.1:1", Topic: "jobs"}); err == nil {
+		t.Error("expected an error without group and result topic")
+	}
+}
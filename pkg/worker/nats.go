@@ -0,0 +1,415 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultNATSFetchWait is the default of NATSConfig.FetchWait.
+const DefaultNATSFetchWait = 5 * time.Second
+
+// errNATSClosed is returned by a NATSQueue after Close.
+var errNATSClosed = errors.New("worker: NATS connection closed")
+
+// NATSConfig configures a NATSQueue.
+type NATSConfig struct {
+	// URL is the NATS server, e.g. "nats://127.0.0.1:4222". User and
+	// password in the URL are sent on connect. The "tls" scheme connects
+	// with TLS, as does any server that requires it.
+	URL string
+
+	// Token authenticates with a token instead of a user and password.
+	Token string
+
+	// Stream and Consumer name the JetStream stream holding jobs and its
+	// durable pull consumer. Both must exist; the consumer's AckWait bounds
+	// how long a job may run before it is redelivered.
+	Stream   string
+	Consumer string
+
+	// ResultSubject is the subject results are published to. A stream
+	// capturing it keeps results that nobody is subscribed to.
+	ResultSubject string
+
+	// FetchWait is how long one pull request waits for a job
+	// (default: DefaultNATSFetchWait).
+	FetchWait time.Duration
+
+	// TLSConfig configures TLS. Default: verify the server's certificate
+	// for the URL's host.
+	TLSConfig *tls.Config
+}
+
+// NATSQueue is a Queue that pulls jobs from a NATS JetStream consumer and
+// publishes results to a subject. Ack and Nack map to JetStream's +ACK and
+// -NAK, so nacked jobs are redelivered by the server. It speaks the NATS
+// client protocol directly and does not reconnect: once the connection is
+// lost, Receive fails and the worker stops.
+type NATSQueue struct {
+	config NATSConfig
+	conn   net.Conn
+	inbox  string
+	next   atomic.Uint64
+
+	wmu sync.Mutex // Serializes writes
+	w   *bufio.Writer
+
+	mu      sync.Mutex
+	replies map[string]chan natsMsg
+	err     error
+	done    chan struct{}
+}
+
+// natsMsg is a message received on a subscription.
+type natsMsg struct {
+	subject string
+	reply   string
+	status  string // Status code of a header-only reply, e.g. "404"
+	data    []byte
+}
+
+// natsInfo is the part of the server's INFO the queue uses.
+type natsInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	Headers     bool `json:"headers"`
+}
+
+// DialNATS connects to a NATS server for a NATSQueue.
+//
+// Example:
+//
+//	queue, err := worker.DialNATS(ctx, worker.NATSConfig{
+//	    URL:    "nats://10.0.0.2:4222",
+//	    Stream: "JOBS", Consumer: "browsers", ResultSubject: "jobs.results",
+//	})
+//	defer queue.Close()
+//	err = worker.New(queue, client, handler).Run(ctx)
+func DialNATS(ctx context.Context, config NATSConfig) (*NATSQueue, error) {
+	if config.Stream == "" || config.Consumer == "" || config.ResultSubject == "" {
+		return nil, fmt.Errorf("worker: NATS stream, consumer and result subject are required")
+	}
+	if config.FetchWait <= 0 {
+		config.FetchWait = DefaultNATSFetchWait
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "nats" && u.Scheme != "tls") {
+		return nil, fmt.Errorf("worker: invalid NATS URL %q", config.URL)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("worker: NATS connect failed: %w", err)
+	}
+	q, err := handshakeNATS(ctx, conn, u, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return q, nil
+}
+
+// handshakeNATS reads the server's INFO, upgrades to TLS if needed,
+// authenticates and subscribes to the queue's reply inbox.
+func handshakeNATS(ctx context.Context, conn net.Conn, u *url.URL, config NATSConfig) (*NATSQueue, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(10 * time.Second)
+	}
+	conn.SetDeadline(deadline)
+
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("worker: NATS handshake failed: %w", err)
+	}
+	payload, found := strings.CutPrefix(strings.TrimSpace(line), "INFO ")
+	var info natsInfo
+	if !found || json.Unmarshal([]byte(payload), &info) != nil {
+		return nil, fmt.Errorf("worker: NATS handshake failed: unexpected %q", strings.TrimSpace(line))
+	}
+	if u.Scheme == "tls" || info.TLSRequired {
+		tlsConfig := config.TLSConfig.Clone()
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = u.Hostname()
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, fmt.Errorf("worker: NATS TLS handshake failed: %w", err)
+		}
+		conn, r = tlsConn, bufio.NewReader(tlsConn)
+	}
+
+	connect := map[string]any{
+		"verbose": false, "pedantic": false, "lang": "go", "version": "1",
+		"name": "go-antidetect worker", "protocol": 1, "headers": true, "no_responders": true,
+	}
+	if u.User != nil {
+		connect["user"] = u.User.Username()
+		connect["pass"], _ = u.User.Password()
+	}
+	if config.Token != "" {
+		connect["auth_token"] = config.Token
+	}
+	connectJSON, _ := json.Marshal(connect)
+
+	inboxID := make([]byte, 8)
+	rand.Read(inboxID)
+	q := &NATSQueue{
+		config:  config,
+		conn:    conn,
+		inbox:   "_INBOX." + hex.EncodeToString(inboxID),
+		w:       bufio.NewWriter(conn),
+		replies: make(map[string]chan natsMsg),
+		done:    make(chan struct{}),
+	}
+	if err := q.write("CONNECT %s\r\nPING\r\n", connectJSON); err != nil {
+		return nil, fmt.Errorf("worker: NATS handshake failed: %w", err)
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("worker: NATS handshake failed: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "PONG" {
+			break
+		}
+		if msg, ok := strings.CutPrefix(line, "-ERR "); ok {
+			return nil, fmt.Errorf("worker: NATS connect refused: %s", strings.Trim(msg, "'"))
+		}
+	}
+	if !info.Headers {
+		return nil, fmt.Errorf("worker: NATS server does not support headers, which JetStream needs")
+	}
+	if err := q.write("SUB %s.* 1\r\n", q.inbox); err != nil {
+		return nil, fmt.Errorf("worker: NATS subscribe failed: %w", err)
+	}
+
+	conn.SetDeadline(time.Time{})
+	go q.readLoop(r)
+	return q, nil
+}
+
+// Receive implements Queue. It sends pull requests to the consumer until a
+// job arrives or ctx is done.
+func (q *NATSQueue) Receive(ctx context.Context) (Delivery, error) {
+	request, _ := json.Marshal(map[string]any{"batch": 1, "expires": q.config.FetchWait.Nanoseconds()})
+	for {
+		reply := q.inbox + "." + strconv.FormatUint(q.next.Add(1), 10)
+		ch := make(chan natsMsg, 1)
+		q.mu.Lock()
+		q.replies[reply] = ch
+		q.mu.Unlock()
+
+		msg, err := q.fetch(ctx, reply, ch, request)
+		q.mu.Lock()
+		delete(q.replies, reply)
+		q.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case msg == nil, msg.status == "404", msg.status == "408":
+			// No job within FetchWait
+		case msg.status != "":
+			return nil, fmt.Errorf("worker: NATS fetch failed: status %s", msg.status)
+		case msg.reply == "":
+			// JetStream API error, such as an unknown consumer
+			return nil, fmt.Errorf("worker: NATS fetch failed: %s", msg.data)
+		default:
+			return &natsDelivery{queue: q, msg: *msg}, nil
+		}
+	}
+}
+
+// fetch sends one pull request and waits for its reply, returning nil if
+// none arrives in time.
+func (q *NATSQueue) fetch(ctx context.Context, reply string, ch chan natsMsg, request []byte) (*natsMsg, error) {
+	subject := "$JS.API.CONSUMER.MSG.NEXT." + q.config.Stream + "." + q.config.Consumer
+	if err := q.publish(subject, reply, request); err != nil {
+		return nil, err
+	}
+	timer := time.NewTimer(q.config.FetchWait + 5*time.Second)
+	defer timer.Stop()
+	select {
+	case msg := <-ch:
+		return &msg, nil
+	case <-timer.C:
+		return nil, nil
+	case <-q.done:
+		return nil, q.err
+	case <-ctx.Done():
+		// A job sent after this is not acked and redelivered after AckWait.
+		return nil, ctx.Err()
+	}
+}
+
+// PublishResult implements Queue.
+func (q *NATSQueue) PublishResult(ctx context.Context, result Result) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return q.publish(q.config.ResultSubject, "", data)
+}
+
+// Close closes the connection. Jobs received but not settled are
+// redelivered after the consumer's AckWait.
+func (q *NATSQueue) Close() error {
+	q.fail(errNATSClosed)
+	return nil
+}
+
+// publish sends a message, with a reply subject if reply is not empty.
+func (q *NATSQueue) publish(subject, reply string, data []byte) error {
+	if reply != "" {
+		subject += " " + reply
+	}
+	if err := q.write("PUB %s %d\r\n%s\r\n", subject, len(data), data); err != nil {
+		return fmt.Errorf("worker: NATS publish failed: %w", err)
+	}
+	return nil
+}
+
+// write sends a protocol line.
+func (q *NATSQueue) write(format string, args ...any) error {
+	q.wmu.Lock()
+	defer q.wmu.Unlock()
+	select {
+	case <-q.done:
+		return q.err
+	default:
+	}
+	fmt.Fprintf(q.w, format, args...)
+	return q.w.Flush()
+}
+
+// fail closes the connection and records why, once.
+func (q *NATSQueue) fail(err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.err != nil {
+		return
+	}
+	q.err = err
+	close(q.done)
+	q.conn.Close()
+}
+
+// readLoop reads protocol messages until the connection fails, answering
+// pings and passing replies to their waiting Receive.
+func (q *NATSQueue) readLoop(r *bufio.Reader) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			q.fail(fmt.Errorf("worker: NATS connection lost: %w", err))
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			q.write("PONG\r\n")
+		case "-ERR":
+			q.fail(fmt.Errorf("worker: NATS error: %s", strings.Trim(strings.TrimSpace(line[4:]), "'")))
+			return
+		case "MSG", "HMSG":
+			msg, err := readNATSMsg(r, fields)
+			if err != nil {
+				q.fail(fmt.Errorf("worker: NATS connection lost: %w", err))
+				return
+			}
+			q.mu.Lock()
+			ch := q.replies[msg.subject]
+			q.mu.Unlock()
+			if ch != nil {
+				select {
+				case ch <- msg:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// readNATSMsg reads the payload of a MSG or HMSG line split into fields:
+// "MSG <subject> <sid> [reply] <bytes>" or
+// "HMSG <subject> <sid> [reply] <header bytes> <total bytes>".
+func readNATSMsg(r *bufio.Reader, fields []string) (natsMsg, error) {
+	headers := strings.EqualFold(fields[0], "HMSG")
+	sizes := 1
+	if headers {
+		sizes = 2
+	}
+	if len(fields) != 3+sizes && len(fields) != 4+sizes {
+		return natsMsg{}, fmt.Errorf("malformed %s", strings.Join(fields, " "))
+	}
+	msg := natsMsg{subject: fields[1]}
+	if len(fields) == 4+sizes {
+		msg.reply = fields[3]
+	}
+	total, err := strconv.Atoi(fields[len(fields)-1])
+	headerLen := 0
+	if headers && err == nil {
+		headerLen, err = strconv.Atoi(fields[len(fields)-2])
+	}
+	if err != nil || total < 0 || headerLen < 0 || headerLen > total {
+		return natsMsg{}, fmt.Errorf("malformed %s", strings.Join(fields, " "))
+	}
+	data := make([]byte, total+2)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return natsMsg{}, err
+	}
+	if headerLen > 0 {
+		// The first header line is "NATS/1.0" or "NATS/1.0 <status> <description>"
+		first, _, _ := strings.Cut(string(data[:headerLen]), "\r\n")
+		if status := strings.Fields(first); len(status) > 1 {
+			msg.status = status[1]
+		}
+	}
+	msg.data = data[headerLen:total]
+	return msg, nil
+}
+
+// natsDelivery is a job received from a NATSQueue.
+type natsDelivery struct {
+	queue *NATSQueue
+	msg   natsMsg
+}
+
+func (d *natsDelivery) Body() []byte {
+	return d.msg.data
+}
+
+func (d *natsDelivery) Ack(ctx context.Context) error {
+	return d.queue.publish(d.msg.reply, "", []byte("+ACK"))
+}
+
+func (d *natsDelivery) Nack(ctx context.Context) error {
+	return d.queue.publish(d.msg.reply, "", []byte("-NAK"))
+}
@@ -0,0 +1,156 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNATS is a NATS server with one JetStream consumer. Pull requests are
+// answered from jobs, with a 404 status once they run out, and every other
+// publish is recorded by subject.
+type fakeNATS struct {
+	t        *testing.T
+	listener net.Listener
+
+	mu        sync.Mutex
+	jobs      []string
+	published map[string][]string
+	pulls     int
+}
+
+func newFakeNATS(t *testing.T, jobs ...string) *fakeNATS {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeNATS{t: t, listener: listener, jobs: jobs, published: make(map[string][]string)}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeNATS) url() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+func (s *fakeNATS) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	fmt.Fprintf(conn, "INFO {\"headers\":true}\r\n")
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "PING":
+			fmt.Fprintf(conn, "PONG\r\n")
+		case "PUB":
+			n, _ := strconv.Atoi(fields[len(fields)-1])
+			data := make([]byte, n+2)
+			io.ReadFull(r, data)
+			if !strings.HasPrefix(fields[1], "$JS.API.CONSUMER.MSG.NEXT.JOBS.browsers") {
+				s.mu.Lock()
+				s.published[fields[1]] = append(s.published[fields[1]], string(data[:n]))
+				s.mu.Unlock()
+				continue
+			}
+			reply := fields[2]
+			s.mu.Lock()
+			s.pulls++
+			var job string
+			if len(s.jobs) > 0 {
+				job, s.jobs = s.jobs[0], s.jobs[1:]
+			}
+			n = s.pulls
+			s.mu.Unlock()
+			if job == "" {
+				header := "NATS/1.0 404 No Messages\r\n\r\n"
+				fmt.Fprintf(conn, "HMSG %s 1 %d %d\r\n%s\r\n", reply, len(header), len(header), header)
+			} else {
+				fmt.Fprintf(conn, "MSG %s 1 $JS.ACK.JOBS.browsers.1.%d.%d.0.0 %d\r\n%s\r\n", reply, n, n, len(job), job)
+			}
+		}
+	}
+}
+
+func (s *fakeNATS) publishedTo(subject string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.published[subject]
+}
+
+func TestNATSQueue(t *testing.T) {
+	server := newFakeNATS(t, "", `{"id":"job-1","profileId":"p1"}`)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	queue, err := DialNATS(ctx, NATSConfig{URL: server.url(), Stream: "JOBS", Consumer: "browsers", ResultSubject: "jobs.results"})
+	if err != nil {
+		t.Fatalf("DialNATS failed: %v", err)
+	}
+	defer queue.Close()
+
+	delivery, err := queue.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	var job Job
+	if err := json.Unmarshal(delivery.Body(), &job); err != nil || job.ID != "job-1" {
+		t.Fatalf("job = %+v, %v", job, err)
+	}
+	if err := queue.PublishResult(ctx, Result{JobID: "job-1", Success: true}); err != nil {
+		t.Fatalf("PublishResult failed: %v", err)
+	}
+	delivery.Nack(ctx)
+	delivery.Ack(ctx)
+
+	waitUntil(t, func() bool { return len(server.publishedTo("$JS.ACK.JOBS.browsers.1.2.2.0.0")) == 2 })
+	if acks := server.publishedTo("$JS.ACK.JOBS.browsers.1.2.2.0.0"); acks[0] != "-NAK" || acks[1] != "+ACK" {
+		t.Errorf("acks = %v", acks)
+	}
+	if results := server.publishedTo("jobs.results"); len(results) != 1 || !strings.Contains(results[0], `"jobId":"job-1"`) {
+		t.Errorf("results = %v", results)
+	}
+
+	queue.Close()
+	if _, err := queue.Receive(ctx); err == nil {
+		t.Error("Receive after Close succeeded")
+	}
+}
+
+func TestDialNATSValidation(t *testing.T) {
+	ctx := context.Background()
+	if _, err := DialNATS(ctx, NATSConfig{URL: "nats://127.0.0.1:1", Stream: "JOBS"}); err == nil {
+		t.Error("expected an error without consumer and result subject")
+	}
+	config := NATSConfig{URL: "http://127.0.0.1:1", Stream: "JOBS", Consumer: "c", ResultSubject: "r"}
+	if _, err := DialNATS(ctx, config); err == nil {
+		t.Error("expected an error for an http URL")
+	}
+}
+
+// waitUntil polls cond until it holds or a second has passed.
+func waitUntil(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// MemoryQueue is an in-process Queue backed by channels, useful for tests
+// and for feeding jobs from within the same program. Nacked jobs are put
+// back at the end of the queue.
+type MemoryQueue struct {
+	jobs    chan []byte
+	results chan Result
+}
+
+// NewMemoryQueue creates a MemoryQueue holding up to size pending jobs and
+// size unread results.
+func NewMemoryQueue(size int) *MemoryQueue {
+	return &MemoryQueue{
+		jobs:    make(chan []byte, size),
+		results: make(chan Result, size),
+	}
+}
+
+// Push enqueues a job, blocking while the queue is full.
+func (q *MemoryQueue) Push(ctx context.Context, job Job) error {
+	body, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return q.push(ctx, body)
+}
+
+// Results returns the channel results are published to.
+func (q *MemoryQueue) Results() <-chan Result {
+	return q.results
+}
+
+// Receive implements Queue.
+func (q *MemoryQueue) Receive(ctx context.Context) (Delivery, error) {
+	select {
+	case body := <-q.jobs:
+		return &memoryDelivery{queue: q, body: body}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// PublishResult implements Queue.
+func (q *MemoryQueue) PublishResult(ctx context.Context, result Result) error {
+	select {
+	case q.results <- result:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// push enqueues a raw job body.
+func (q *MemoryQueue) push(ctx context.Context, body []byte) error {
+	select {
+	case q.jobs <- body:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// memoryDelivery is a job received from a MemoryQueue.
+type memoryDelivery struct {
+	queue *MemoryQueue
+	body  []byte
+}
+
+func (d *memoryDelivery) Body() []byte {
+	return d.body
+}
+
+func (d *memoryDelivery) Ack(ctx context.Context) error {
+	return nil
+}
+
+func (d *memoryDelivery) Nack(ctx context.Context) error {
+	return d.queue.push(ctx, d.body)
+}
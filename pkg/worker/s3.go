@@ -0,0 +1,177 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// S3Config configures an S3Store.
+type S3Config struct {
+	Bucket string
+	Region string // e.g. "us-east-1"
+
+	// Endpoint is the S3 API URL. Default: https://s3.<Region>.amazonaws.com.
+	// Set it for S3-compatible services such as MinIO or R2.
+	Endpoint string
+
+	// PathStyle addresses objects as <Endpoint>/<Bucket>/<key> instead of
+	// <bucket>.<endpoint host>/<key>. Most S3-compatible services need it.
+	PathStyle bool
+
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Optional, for temporary credentials
+
+	// Prefix is prepended to every key, e.g. "jobs/".
+	Prefix string
+
+	// PublicURL, if set, is used instead of the object URL as the base of
+	// returned URLs, e.g. a CDN in front of the bucket.
+	PublicURL string
+
+	// HTTPClient sends the requests. Default: http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// S3Store is an ArtifactStore that uploads to Amazon S3 or an
+// S3-compatible service, signing requests with AWS Signature Version 4.
+type S3Store struct {
+	config S3Config
+	base   *url.URL
+	now    func() time.Time
+}
+
+// NewS3Store creates an S3Store.
+func NewS3Store(config S3Config) (*S3Store, error) {
+	if config.Bucket == "" || config.Region == "" {
+		return nil, fmt.Errorf("worker: S3 bucket and region are required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("worker: S3 credentials are required")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+
+	base, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("worker: invalid S3 endpoint %q", config.Endpoint)
+	}
+	if config.PathStyle {
+		base.Path += "/" + config.Bucket
+	} else {
+		base.Host = config.Bucket + "." + base.Host
+	}
+	return &S3Store{config: config, base: base, now: time.Now}, nil
+}
+
+// Put implements ArtifactStore.
+func (s *S3Store) Put(ctx context.Context, key, contentType string, data io.Reader) (string, error) {
+	body, err := io.ReadAll(data)
+	if err != nil {
+		return "", fmt.Errorf("worker: read artifact failed: %w", err)
+	}
+	key = s.config.Prefix + key
+
+	objectURL := *s.base
+	objectURL.Path += "/" + key
+	objectURL.RawPath = s.base.EscapedPath() + "/" + escapeKey(key)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if s.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+	}
+	signV4(req, sha256Hex(body), s.config.AccessKeyID, s.config.SecretAccessKey, s.config.Region, "s3", s.now())
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("worker: S3 upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("worker: S3 upload failed: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	if s.config.PublicURL != "" {
+		return strings.TrimRight(s.config.PublicURL, "/") + "/" + escapeKey(key), nil
+	}
+	return objectURL.String(), nil
+}
+
+// signV4 signs req with AWS Signature Version 4, covering the host and all
+// headers already set on req.
+func signV4(req *http.Request, payloadHash, accessKey, secretKey, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
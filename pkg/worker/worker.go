@@ -0,0 +1,336 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+// Default worker settings.
+const (
+	DefaultConcurrency  = 1
+	DefaultJobTimeout   = 5 * time.Minute
+	DefaultMaxAttempts  = 3
+	DefaultRetryDelay   = 5 * time.Second
+	defaultCloseTimeout = 30 * time.Second
+)
+
+// Job asks the worker to open a profile and run the handler on it.
+type Job struct {
+	ID        string          `json:"id"`
+	ProfileID string          `json:"profileId"`
+	Payload   json.RawMessage `json:"payload,omitempty"`   // Passed to the handler as is
+	TimeoutMs int64           `json:"timeoutMs,omitempty"` // Overrides the worker's job timeout
+}
+
+// Result reports the outcome of a job.
+type Result struct {
+	JobID     string          `json:"jobId"`
+	ProfileID string          `json:"profileId"`
+	Success   bool            `json:"success"`
+	Output    json.RawMessage `json:"output,omitempty"` // Handler output on success
+	Error     string          `json:"error,omitempty"`  // Last error on failure
+	Attempts  int             `json:"attempts"`
+	Started   time.Time       `json:"started"`
+	Finished  time.Time       `json:"finished"`
+
+	// Artifacts maps artifact names to URLs for the files saved with
+	// SaveArtifact during the last attempt.
+	Artifacts map[string]string `json:"artifacts,omitempty"`
+}
+
+// Delivery is a job message received from a Queue.
+type Delivery interface {
+	// Body returns the JSON-encoded Job.
+	Body() []byte
+	// Ack marks the message as processed.
+	Ack(ctx context.Context) error
+	// Nack returns the message to the queue for redelivery.
+	Nack(ctx context.Context) error
+}
+
+// Queue is a message broker that delivers jobs and accepts results.
+type Queue interface {
+	// Receive blocks until a job is available or ctx is done.
+	Receive(ctx context.Context) (Delivery, error)
+	// PublishResult sends the result of a job.
+	PublishResult(ctx context.Context, result Result) error
+}
+
+// Browsers opens and closes profiles. *bitbrowser.Client implements it.
+type Browsers interface {
+	Open(ctx context.Context, id string, opts *bitbrowser.OpenOptions) (*bitbrowser.OpenResult, error)
+	Close(ctx context.Context, id string) error
+}
+
+// Handler runs a job against an open browser and returns its output.
+// The context is canceled when the job times out.
+type Handler func(ctx context.Context, job Job, browser *bitbrowser.OpenResult) (json.RawMessage, error)
+
+// Worker consumes jobs from a Queue. Create one with New.
+type Worker struct {
+	queue    Queue
+	browsers Browsers
+	handler  Handler
+
+	concurrency int
+	jobTimeout  time.Duration
+	maxAttempts int
+	retryDelay  time.Duration
+	openOptions *bitbrowser.OpenOptions
+	headful     HeadfulPolicy
+	artifacts   ArtifactStore
+	logger      *slog.Logger
+}
+
+// Option configures a Worker.
+type Option func(*Worker)
+
+// WithConcurrency sets how many jobs run at once.
+// Default: 1.
+func WithConcurrency(n int) Option {
+	return func(w *Worker) {
+		if n > 0 {
+			w.concurrency = n
+		}
+	}
+}
+
+// WithJobTimeout bounds each attempt of a job, including opening and
+// closing the browser. Jobs may override it with TimeoutMs.
+// Default: 5 minutes.
+func WithJobTimeout(d time.Duration) Option {
+	return func(w *Worker) {
+		if d > 0 {
+			w.jobTimeout = d
+		}
+	}
+}
+
+// WithMaxAttempts sets how many times a failing job is tried before a
+// failed Result is published.
+// Default: 3.
+func WithMaxAttempts(n int) Option {
+	return func(w *Worker) {
+		if n > 0 {
+			w.maxAttempts = n
+		}
+	}
+}
+
+// WithRetryDelay sets the pause between attempts of a job.
+// Default: 5 seconds.
+func WithRetryDelay(d time.Duration) Option {
+	return func(w *Worker) {
+		if d >= 0 {
+			w.retryDelay = d
+		}
+	}
+}
+
+// WithOpenOptions sets the options used to open browsers.
+func WithOpenOptions(opts *bitbrowser.OpenOptions) Option {
+	return func(w *Worker) {
+		w.openOptions = opts
+	}
+}
+
+// WithArtifactStore sets where SaveArtifact stores job artifacts such as
+// screenshots, HARs and exported cookies.
+func WithArtifactStore(store ArtifactStore) Option {
+	return func(w *Worker) {
+		w.artifacts = store
+	}
+}
+
+// WithLogger sets a logger for job progress.
+func WithLogger(logger *slog.Logger) Option {
+	return func(w *Worker) {
+		w.logger = logger
+	}
+}
+
+// New creates a Worker that takes jobs from queue and runs handler on
+// browsers opened through browsers.
+func New(queue Queue, browsers Browsers, handler Handler, opts ...Option) *Worker {
+	w := &Worker{
+		queue:       queue,
+		browsers:    browsers,
+		handler:     handler,
+		concurrency: DefaultConcurrency,
+		jobTimeout:  DefaultJobTimeout,
+		maxAttempts: DefaultMaxAttempts,
+		retryDelay:  DefaultRetryDelay,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Run consumes jobs until ctx is canceled or Receive fails, then waits for
+// running jobs to finish. It returns nil after cancellation, or the
+// Receive error.
+//
+// Every delivery is acknowledged once its Result is published, whether the
+// job succeeded or not. If the Result cannot be published, or a job fails
+// because ctx was canceled, the delivery is nacked so the job is
+// redelivered.
+func (w *Worker) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	slots := make(chan struct{}, w.concurrency)
+	for {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+
+		delivery, err := w.queue.Receive(ctx)
+		if err != nil {
+			<-slots
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("worker: receive failed: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			w.handle(ctx, delivery)
+		}()
+	}
+}
+
+// handle runs one delivery and settles it.
+func (w *Worker) handle(ctx context.Context, delivery Delivery) {
+	// Settle even if Run's context is canceled mid-job
+	settleCtx := context.WithoutCancel(ctx)
+
+	var job Job
+	var result Result
+	if err := json.Unmarshal(delivery.Body(), &job); err != nil {
+		now := time.Now()
+		result = Result{Error: "invalid job: " + err.Error(), Started: now, Finished: now}
+	} else if job.ProfileID == "" {
+		now := time.Now()
+		result = Result{JobID: job.ID, Error: "invalid job: profileId is required", Started: now, Finished: now}
+	} else {
+		result = w.process(ctx, job)
+		if !result.Success && ctx.Err() != nil {
+			// Interrupted by Run stopping: redeliver instead of failing the job
+			w.log(slog.LevelInfo, "Job interrupted, returning it to the queue", "job", job.ID, "profile", job.ProfileID)
+			delivery.Nack(settleCtx)
+			return
+		}
+	}
+
+	if err := w.queue.PublishResult(settleCtx, result); err != nil {
+		w.log(slog.LevelError, "Publish result failed", "job", result.JobID, "error", err)
+		delivery.Nack(settleCtx)
+		return
+	}
+	delivery.Ack(settleCtx)
+}
+
+// process runs a job with retries.
+func (w *Worker) process(ctx context.Context, job Job) Result {
+	result := Result{JobID: job.ID, ProfileID: job.ProfileID, Started: time.Now()}
+
+	var err error
+	for result.Attempts < w.maxAttempts {
+		if result.Attempts > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.retryDelay):
+			}
+		}
+		if ctx.Err() != nil {
+			err = errors.Join(err, ctx.Err())
+			break
+		}
+
+		result.Attempts++
+		var output json.RawMessage
+		output, result.Artifacts, err = w.attempt(ctx, job, result.Attempts)
+		if err == nil {
+			result.Success = true
+			result.Output = output
+			break
+		}
+		w.log(slog.LevelWarn, "Job attempt failed", "job", job.ID, "profile", job.ProfileID,
+			"attempt", result.Attempts, "error", err)
+	}
+
+	if !result.Success {
+		result.Error = err.Error()
+	}
+	result.Finished = time.Now()
+	w.log(slog.LevelInfo, "Job finished", "job", job.ID, "profile", job.ProfileID,
+		"success", result.Success, "attempts", result.Attempts, "duration", result.Finished.Sub(result.Started))
+	return result
+}
+
+// attempt opens the browser, runs the handler and closes the browser.
+// It returns the handler output and the URLs of saved artifacts.
+func (w *Worker) attempt(ctx context.Context, job Job, n int) (output json.RawMessage, artifacts map[string]string, err error) {
+	timeout := w.jobTimeout
+	if job.TimeoutMs > 0 {
+		timeout = time.Duration(job.TimeoutMs) * time.Millisecond
+	}
+	openOptions := w.openOptions
+	if w.headfulAttempt(n) {
+		openOptions = w.headfulOptions()
+		if w.headful.Timeout > 0 {
+			timeout = w.headful.Timeout
+		}
+		ctx = context.WithValue(ctx, headfulKey{}, true)
+		w.log(slog.LevelInfo, "Reopening profile headful", "job", job.ID, "profile", job.ProfileID, "attempt", n)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	browser, err := w.browsers.Open(ctx, job.ProfileID, openOptions)
+	if browser != nil {
+		// Close even when the job timed out, or when the browser launched
+		// but a later step of Open failed
+		defer func() {
+			closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultCloseTimeout)
+			defer cancel()
+			if err := w.browsers.Close(closeCtx, job.ProfileID); err != nil {
+				w.log(slog.LevelWarn, "Close browser failed", "job", job.ID, "profile", job.ProfileID, "error", err)
+			}
+		}()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("worker: open profile %s failed: %w", job.ProfileID, err)
+	}
+
+	ctx, collected := withArtifacts(ctx, w.artifacts, job, n)
+	output, err = w.handler(ctx, job, browser)
+	if err == nil && ctx.Err() != nil {
+		err = fmt.Errorf("worker: job %s timed out after %s: %w", job.ID, timeout, ctx.Err())
+	}
+	if err != nil {
+		err = w.checkCrash(ctx, job, err)
+		return nil, collected.saved(), err
+	}
+	return output, collected.saved(), nil
+}
+
+// log logs through the configured logger, if any.
+func (w *Worker) log(level slog.Level, msg string, attrs ...any) {
+	if w.logger != nil {
+		w.logger.Log(context.Background(), level, msg, attrs...)
+	}
+}
@@ -0,0 +1,280 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lpg-it/go-antidetect/pkg/bitbrowser"
+)
+
+// fakeBrowsers records opens and closes.
+type fakeBrowsers struct {
+	mu      sync.Mutex
+	open    map[string]bool
+	opens   int
+	closes  int
+	openErr error
+	lateErr error // Returned with the browser, as by a failing ready hook
+}
+
+func newFakeBrowsers() *fakeBrowsers {
+	return &fakeBrowsers{open: make(map[string]bool)}
+}
+
+func (b *fakeBrowsers) Open(ctx context.Context, id string, opts *bitbrowser.OpenOptions) (*bitbrowser.OpenResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.opens++
+	if b.openErr != nil {
+		return nil, b.openErr
+	}
+	b.open[id] = true
+	return &bitbrowser.OpenResult{Ws: "ws://127.0.0.1:9222/devtools/browser/" + id}, b.lateErr
+}
+
+func (b *fakeBrowsers) Close(ctx context.Context, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closes++
+	delete(b.open, id)
+	return nil
+}
+
+// runJobs pushes jobs, runs a worker until all results arrive and returns them by job ID.
+func runJobs(t *testing.T, browsers Browsers, handler Handler, jobs []Job, opts ...Option) map[string]Result {
+	t.Helper()
+	queue := NewMemoryQueue(len(jobs))
+	for _, job := range jobs {
+		if err := queue.Push(context.Background(), job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- New(queue, browsers, handler, opts...).Run(ctx) }()
+
+	results := make(map[string]Result)
+	timeout := time.After(5 * time.Second)
+	for len(results) < len(jobs) {
+		select {
+		case r := <-queue.Results():
+			results[r.JobID] = r
+		case <-timeout:
+			t.Fatalf("timed out with %d of %d results", len(results), len(jobs))
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run returned %v", err)
+	}
+	return results
+}
+
+func TestWorker(t *testing.T) {
+	t.Run("runs jobs and closes browsers", func(t *testing.T) {
+		browsers := newFakeBrowsers()
+		handler := func(ctx context.Context, job Job, browser *bitbrowser.OpenResult) (json.RawMessage, error) {
+			return json.Marshal(map[string]string{"ws": browser.Ws, "payload": string(job.Payload)})
+		}
+		jobs := []Job{
+			{ID: "j1", ProfileID: "p1", Payload: json.RawMessage(`1`)},
+			{ID: "j2", ProfileID: "p2", Payload: json.RawMessage(`2`)},
+		}
+
+		results := runJobs(t, browsers, handler, jobs, WithConcurrency(2))
+		for _, job := range jobs {
+			r := results[job.ID]
+			if !r.Success || r.Attempts != 1 || r.ProfileID != job.ProfileID {
+				t.Errorf("unexpected result: %+v", r)
+			}
+			var out map[string]string
+			json.Unmarshal(r.Output, &out)
+			if out["payload"] != string(job.Payload) || out["ws"] == "" {
+				t.Errorf("unexpected output: %s", r.Output)
+			}
+		}
+		if browsers.opens != 2 || browsers.closes != 2 || len(browsers.open) != 0 {
+			t.Errorf("opens = %d, closes = %d, still open = %v", browsers.opens, browsers.closes, browsers.open)
+		}
+	})
+
+	t.Run("retries failed attempts", func(t *testing.T) {
+		browsers := newFakeBrowsers()
+		calls := 0
+		handler := func(ctx context.Context, job Job, browser *bitbrowser.OpenResult) (json.RawMessage, error) {
+			calls++
+			if calls < 3 {
+				return nil, errors.New("flaky")
+			}
+			return json.RawMessage(`"ok"`), nil
+		}
+
+		r := runJobs(t, browsers, handler, []Job{{ID: "j1", ProfileID: "p1"}}, WithRetryDelay(0))["j1"]
+		if !r.Success || r.Attempts != 3 {
+			t.Errorf("unexpected result: %+v", r)
+		}
+		if browsers.closes != 3 {
+			t.Errorf("closes = %d, want one per attempt", browsers.closes)
+		}
+	})
+
+	t.Run("publishes failure after max attempts", func(t *testing.T) {
+		browsers := newFakeBrowsers()
+		browsers.openErr = errors.New("profile locked")
+		handler := func(ctx context.Context, job Job, browser *bitbrowser.OpenResult) (json.RawMessage, error) {
+			t.Error("handler called although open failed")
+			return nil, nil
+		}
+
+		r := runJobs(t, browsers, handler, []Job{{ID: "j1", ProfileID: "p1"}}, WithMaxAttempts(2), WithRetryDelay(0))["j1"]
+		if r.Success || r.Attempts != 2 || r.Error == "" {
+			t.Errorf("unexpected result: %+v", r)
+		}
+		if browsers.closes != 0 {
+			t.Errorf("closes = %d, want 0", browsers.closes)
+		}
+	})
+
+	t.Run("closes browsers whose open failed after launch", func(t *testing.T) {
+		browsers := newFakeBrowsers()
+		browsers.lateErr = errors.New("ready hook failed")
+		handler := func(ctx context.Context, job Job, browser *bitbrowser.OpenResult) (json.RawMessage, error) {
+			t.Error("handler called although open failed")
+			return nil, nil
+		}
+
+		r := runJobs(t, browsers, handler, []Job{{ID: "j1", ProfileID: "p1"}}, WithMaxAttempts(2), WithRetryDelay(0))["j1"]
+		if r.Success || browsers.closes != 2 || len(browsers.open) != 0 {
+			t.Errorf("result = %+v, closes = %d, still open = %v", r, browsers.closes, browsers.open)
+		}
+	})
+
+	t.Run("job timeout", func(t *testing.T) {
+		browsers := newFakeBrowsers()
+		handler := func(ctx context.Context, job Job, browser *bitbrowser.OpenResult) (json.RawMessage, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		r := runJobs(t, browsers, handler, []Job{{ID: "j1", ProfileID: "p1", TimeoutMs: 10}}, WithMaxAttempts(1))["j1"]
+		if r.Success || r.Error == "" {
+			t.Errorf("unexpected result: %+v", r)
+		}
+		if browsers.closes != 1 {
+			t.Error("browser was not closed after timeout")
+		}
+	})
+
+	t.Run("invalid job", func(t *testing.T) {
+		browsers := newFakeBrowsers()
+		r := runJobs(t, browsers, nil, []Job{{ID: "j1"}})["j1"]
+		if r.Success || r.Attempts != 0 || browsers.opens != 0 {
+			t.Errorf("unexpected result: %+v", r)
+		}
+	})
+}
+
+// settleQueue is a MemoryQueue that counts acks and nacks.
+type settleQueue struct {
+	*MemoryQueue
+	mu          sync.Mutex
+	acks, nacks int
+}
+
+func (q *settleQueue) Receive(ctx context.Context) (Delivery, error) {
+	d, err := q.MemoryQueue.Receive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &settleDelivery{Delivery: d, queue: q}, nil
+}
+
+func (q *settleQueue) counts() (acks, nacks int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.acks, q.nacks
+}
+
+type settleDelivery struct {
+	Delivery
+	queue *settleQueue
+}
+
+func (d *settleDelivery) Ack(ctx context.Context) error {
+	d.queue.mu.Lock()
+	d.queue.acks++
+	d.queue.mu.Unlock()
+	return d.Delivery.Ack(ctx)
+}
+
+func (d *settleDelivery) Nack(ctx context.Context) error {
+	d.queue.mu.Lock()
+	d.queue.nacks++
+	d.queue.mu.Unlock()
+	return d.Delivery.Nack(ctx)
+}
+
+func TestWorkerStopRedeliversRunningJobs(t *testing.T) {
+	queue := &settleQueue{MemoryQueue: NewMemoryQueue(1)}
+	queue.Push(context.Background(), Job{ID: "j1", ProfileID: "p1"})
+	started := make(chan struct{})
+	handler := func(ctx context.Context, job Job, browser *bitbrowser.OpenResult) (json.RawMessage, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	browsers := newFakeBrowsers()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- New(queue, browsers, handler).Run(ctx) }()
+	<-started
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run returned %v", err)
+	}
+
+	if acks, nacks := queue.counts(); acks != 0 || nacks != 1 {
+		t.Errorf("acks = %d, nacks = %d, want the job nacked", acks, nacks)
+	}
+	select {
+	case r := <-queue.Results():
+		t.Errorf("published %+v for an interrupted job", r)
+	default:
+	}
+	if browsers.closes != 1 {
+		t.Errorf("closes = %d, want 1", browsers.closes)
+	}
+}
+
+func TestMemoryQueue_Nack(t *testing.T) {
+	queue := NewMemoryQueue(1)
+	queue.Push(context.Background(), Job{ID: "j1"})
+
+	d, err := queue.Receive(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Nack(context.Background())
+
+	d, err = queue.Receive(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var job Job
+	json.Unmarshal(d.Body(), &job)
+	if job.ID != "j1" {
+		t.Errorf("redelivered job = %+v", job)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := queue.Receive(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}